@@ -0,0 +1,1280 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        v4.25.3
+// source: api/screener/v1/screener.proto
+
+package screenerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListCoinsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only these symbols; empty is every coin.
+	Symbols []string `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	// Only coins with this reversal status, e.g. TRIGGER.
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Only coins whose reversal score reaches this.
+	MinScore float64 `protobuf:"fixed64,3,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"`
+	// At most this many coins; 0 is no limit.
+	Limit int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Puts the coins on this user's watchlist first.
+	UserId string `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (x *ListCoinsRequest) Reset() {
+	*x = ListCoinsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCoinsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoinsRequest) ProtoMessage() {}
+
+func (x *ListCoinsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoinsRequest.ProtoReflect.Descriptor instead.
+func (*ListCoinsRequest) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListCoinsRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *ListCoinsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListCoinsRequest) GetMinScore() float64 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *ListCoinsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCoinsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListCoinsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Coins []*Coin `protobuf:"bytes,1,rep,name=coins,proto3" json:"coins,omitempty"`
+}
+
+func (x *ListCoinsResponse) Reset() {
+	*x = ListCoinsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCoinsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoinsResponse) ProtoMessage() {}
+
+func (x *ListCoinsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoinsResponse.ProtoReflect.Descriptor instead.
+func (*ListCoinsResponse) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListCoinsResponse) GetCoins() []*Coin {
+	if x != nil {
+		return x.Coins
+	}
+	return nil
+}
+
+type GetCoinRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+}
+
+func (x *GetCoinRequest) Reset() {
+	*x = GetCoinRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCoinRequest) ProtoMessage() {}
+
+func (x *GetCoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCoinRequest.ProtoReflect.Descriptor instead.
+func (*GetCoinRequest) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCoinRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type StreamCoinsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only these symbols; empty is every coin.
+	Symbols []string `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	// Only coins whose reversal score reaches this.
+	MinScore float64 `protobuf:"fixed64,2,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"`
+	// Puts the coins on this user's watchlist first.
+	UserId string `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (x *StreamCoinsRequest) Reset() {
+	*x = StreamCoinsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamCoinsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCoinsRequest) ProtoMessage() {}
+
+func (x *StreamCoinsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCoinsRequest.ProtoReflect.Descriptor instead.
+func (*StreamCoinsRequest) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamCoinsRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *StreamCoinsRequest) GetMinScore() float64 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *StreamCoinsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// CoinsUpdate is the result of one screening cycle.
+type CoinsUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AsOf  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	Coins []*Coin                `protobuf:"bytes,2,rep,name=coins,proto3" json:"coins,omitempty"`
+}
+
+func (x *CoinsUpdate) Reset() {
+	*x = CoinsUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoinsUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoinsUpdate) ProtoMessage() {}
+
+func (x *CoinsUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoinsUpdate.ProtoReflect.Descriptor instead.
+func (*CoinsUpdate) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CoinsUpdate) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+func (x *CoinsUpdate) GetCoins() []*Coin {
+	if x != nil {
+		return x.Coins
+	}
+	return nil
+}
+
+// Coin is a screened coin. Field meanings follow the JSON CoinData of the
+// HTTP and WebSocket APIs.
+type Coin struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol             string  `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price              float64 `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	PriceChangePercent float64 `protobuf:"fixed64,3,opt,name=price_change_percent,json=priceChangePercent,proto3" json:"price_change_percent,omitempty"`
+	FundingRate        float64 `protobuf:"fixed64,4,opt,name=funding_rate,json=fundingRate,proto3" json:"funding_rate,omitempty"`
+	BasisSpread        float64 `protobuf:"fixed64,5,opt,name=basis_spread,json=basisSpread,proto3" json:"basis_spread,omitempty"`
+	// Reversal (SHORT)
+	Score           float64           `protobuf:"fixed64,6,opt,name=score,proto3" json:"score,omitempty"`
+	Status          string            `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	TriggerTf       string            `protobuf:"bytes,8,opt,name=trigger_tf,json=triggerTf,proto3" json:"trigger_tf,omitempty"`
+	ConfluenceCount int32             `protobuf:"varint,9,opt,name=confluence_count,json=confluenceCount,proto3" json:"confluence_count,omitempty"`
+	TfScores        []*TimeframeScore `protobuf:"bytes,10,rep,name=tf_scores,json=tfScores,proto3" json:"tf_scores,omitempty"`
+	RegimeFilter    string            `protobuf:"bytes,11,opt,name=regime_filter,json=regimeFilter,proto3" json:"regime_filter,omitempty"`
+	// Intraday (15m + 1h SHORT)
+	IntradayScore  float64 `protobuf:"fixed64,12,opt,name=intraday_score,json=intradayScore,proto3" json:"intraday_score,omitempty"`
+	IntradayStatus string  `protobuf:"bytes,13,opt,name=intraday_status,json=intradayStatus,proto3" json:"intraday_status,omitempty"`
+	// Pullback (LONG)
+	PullbackScore  float64 `protobuf:"fixed64,14,opt,name=pullback_score,json=pullbackScore,proto3" json:"pullback_score,omitempty"`
+	PullbackStatus string  `protobuf:"bytes,15,opt,name=pullback_status,json=pullbackStatus,proto3" json:"pullback_status,omitempty"`
+	// Breakout (LONG or SHORT)
+	BreakoutScore     float64 `protobuf:"fixed64,16,opt,name=breakout_score,json=breakoutScore,proto3" json:"breakout_score,omitempty"`
+	BreakoutStatus    string  `protobuf:"bytes,17,opt,name=breakout_status,json=breakoutStatus,proto3" json:"breakout_status,omitempty"`
+	BreakoutDirection string  `protobuf:"bytes,18,opt,name=breakout_direction,json=breakoutDirection,proto3" json:"breakout_direction,omitempty"`
+	// Follow trend (LONG or SHORT)
+	FollowTrendScore     float64 `protobuf:"fixed64,19,opt,name=follow_trend_score,json=followTrendScore,proto3" json:"follow_trend_score,omitempty"`
+	FollowTrendStatus    string  `protobuf:"bytes,20,opt,name=follow_trend_status,json=followTrendStatus,proto3" json:"follow_trend_status,omitempty"`
+	FollowTrendDirection string  `protobuf:"bytes,21,opt,name=follow_trend_direction,json=followTrendDirection,proto3" json:"follow_trend_direction,omitempty"`
+	// Primary timeframe features
+	Features *Features `protobuf:"bytes,22,opt,name=features,proto3" json:"features,omitempty"`
+}
+
+func (x *Coin) Reset() {
+	*x = Coin{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Coin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coin) ProtoMessage() {}
+
+func (x *Coin) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coin.ProtoReflect.Descriptor instead.
+func (*Coin) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Coin) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Coin) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Coin) GetPriceChangePercent() float64 {
+	if x != nil {
+		return x.PriceChangePercent
+	}
+	return 0
+}
+
+func (x *Coin) GetFundingRate() float64 {
+	if x != nil {
+		return x.FundingRate
+	}
+	return 0
+}
+
+func (x *Coin) GetBasisSpread() float64 {
+	if x != nil {
+		return x.BasisSpread
+	}
+	return 0
+}
+
+func (x *Coin) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Coin) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Coin) GetTriggerTf() string {
+	if x != nil {
+		return x.TriggerTf
+	}
+	return ""
+}
+
+func (x *Coin) GetConfluenceCount() int32 {
+	if x != nil {
+		return x.ConfluenceCount
+	}
+	return 0
+}
+
+func (x *Coin) GetTfScores() []*TimeframeScore {
+	if x != nil {
+		return x.TfScores
+	}
+	return nil
+}
+
+func (x *Coin) GetRegimeFilter() string {
+	if x != nil {
+		return x.RegimeFilter
+	}
+	return ""
+}
+
+func (x *Coin) GetIntradayScore() float64 {
+	if x != nil {
+		return x.IntradayScore
+	}
+	return 0
+}
+
+func (x *Coin) GetIntradayStatus() string {
+	if x != nil {
+		return x.IntradayStatus
+	}
+	return ""
+}
+
+func (x *Coin) GetPullbackScore() float64 {
+	if x != nil {
+		return x.PullbackScore
+	}
+	return 0
+}
+
+func (x *Coin) GetPullbackStatus() string {
+	if x != nil {
+		return x.PullbackStatus
+	}
+	return ""
+}
+
+func (x *Coin) GetBreakoutScore() float64 {
+	if x != nil {
+		return x.BreakoutScore
+	}
+	return 0
+}
+
+func (x *Coin) GetBreakoutStatus() string {
+	if x != nil {
+		return x.BreakoutStatus
+	}
+	return ""
+}
+
+func (x *Coin) GetBreakoutDirection() string {
+	if x != nil {
+		return x.BreakoutDirection
+	}
+	return ""
+}
+
+func (x *Coin) GetFollowTrendScore() float64 {
+	if x != nil {
+		return x.FollowTrendScore
+	}
+	return 0
+}
+
+func (x *Coin) GetFollowTrendStatus() string {
+	if x != nil {
+		return x.FollowTrendStatus
+	}
+	return ""
+}
+
+func (x *Coin) GetFollowTrendDirection() string {
+	if x != nil {
+		return x.FollowTrendDirection
+	}
+	return ""
+}
+
+func (x *Coin) GetFeatures() *Features {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type TimeframeScore struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tf    string  `protobuf:"bytes,1,opt,name=tf,proto3" json:"tf,omitempty"`
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Rsi   float64 `protobuf:"fixed64,3,opt,name=rsi,proto3" json:"rsi,omitempty"`
+}
+
+func (x *TimeframeScore) Reset() {
+	*x = TimeframeScore{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimeframeScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeframeScore) ProtoMessage() {}
+
+func (x *TimeframeScore) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeframeScore.ProtoReflect.Descriptor instead.
+func (*TimeframeScore) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TimeframeScore) GetTf() string {
+	if x != nil {
+		return x.Tf
+	}
+	return ""
+}
+
+func (x *TimeframeScore) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *TimeframeScore) GetRsi() float64 {
+	if x != nil {
+		return x.Rsi
+	}
+	return 0
+}
+
+// Features are the main indicators of the coin's primary timeframe.
+type Features struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rsi               float64 `protobuf:"fixed64,1,opt,name=rsi,proto3" json:"rsi,omitempty"`
+	Cci               float64 `protobuf:"fixed64,2,opt,name=cci,proto3" json:"cci,omitempty"`
+	ZScore            float64 `protobuf:"fixed64,3,opt,name=z_score,json=zScore,proto3" json:"z_score,omitempty"`
+	OverExtEma        float64 `protobuf:"fixed64,4,opt,name=over_ext_ema,json=overExtEma,proto3" json:"over_ext_ema,omitempty"`
+	OverExtVwap       float64 `protobuf:"fixed64,5,opt,name=over_ext_vwap,json=overExtVwap,proto3" json:"over_ext_vwap,omitempty"`
+	OpenInterestDelta float64 `protobuf:"fixed64,6,opt,name=open_interest_delta,json=openInterestDelta,proto3" json:"open_interest_delta,omitempty"`
+	MacdHistogram     float64 `protobuf:"fixed64,7,opt,name=macd_histogram,json=macdHistogram,proto3" json:"macd_histogram,omitempty"`
+	AtrPercentile     float64 `protobuf:"fixed64,8,opt,name=atr_percentile,json=atrPercentile,proto3" json:"atr_percentile,omitempty"`
+	VolatilityRegime  string  `protobuf:"bytes,9,opt,name=volatility_regime,json=volatilityRegime,proto3" json:"volatility_regime,omitempty"`
+	Choppiness        float64 `protobuf:"fixed64,10,opt,name=choppiness,proto3" json:"choppiness,omitempty"`
+	TakerDeltaRatio   float64 `protobuf:"fixed64,11,opt,name=taker_delta_ratio,json=takerDeltaRatio,proto3" json:"taker_delta_ratio,omitempty"`
+	IsSqueeze         bool    `protobuf:"varint,12,opt,name=is_squeeze,json=isSqueeze,proto3" json:"is_squeeze,omitempty"`
+	IsLosingMomentum  bool    `protobuf:"varint,13,opt,name=is_losing_momentum,json=isLosingMomentum,proto3" json:"is_losing_momentum,omitempty"`
+	StructureTrend    string  `protobuf:"bytes,14,opt,name=structure_trend,json=structureTrend,proto3" json:"structure_trend,omitempty"`
+}
+
+func (x *Features) Reset() {
+	*x = Features{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Features) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Features) ProtoMessage() {}
+
+func (x *Features) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Features.ProtoReflect.Descriptor instead.
+func (*Features) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Features) GetRsi() float64 {
+	if x != nil {
+		return x.Rsi
+	}
+	return 0
+}
+
+func (x *Features) GetCci() float64 {
+	if x != nil {
+		return x.Cci
+	}
+	return 0
+}
+
+func (x *Features) GetZScore() float64 {
+	if x != nil {
+		return x.ZScore
+	}
+	return 0
+}
+
+func (x *Features) GetOverExtEma() float64 {
+	if x != nil {
+		return x.OverExtEma
+	}
+	return 0
+}
+
+func (x *Features) GetOverExtVwap() float64 {
+	if x != nil {
+		return x.OverExtVwap
+	}
+	return 0
+}
+
+func (x *Features) GetOpenInterestDelta() float64 {
+	if x != nil {
+		return x.OpenInterestDelta
+	}
+	return 0
+}
+
+func (x *Features) GetMacdHistogram() float64 {
+	if x != nil {
+		return x.MacdHistogram
+	}
+	return 0
+}
+
+func (x *Features) GetAtrPercentile() float64 {
+	if x != nil {
+		return x.AtrPercentile
+	}
+	return 0
+}
+
+func (x *Features) GetVolatilityRegime() string {
+	if x != nil {
+		return x.VolatilityRegime
+	}
+	return ""
+}
+
+func (x *Features) GetChoppiness() float64 {
+	if x != nil {
+		return x.Choppiness
+	}
+	return 0
+}
+
+func (x *Features) GetTakerDeltaRatio() float64 {
+	if x != nil {
+		return x.TakerDeltaRatio
+	}
+	return 0
+}
+
+func (x *Features) GetIsSqueeze() bool {
+	if x != nil {
+		return x.IsSqueeze
+	}
+	return false
+}
+
+func (x *Features) GetIsLosingMomentum() bool {
+	if x != nil {
+		return x.IsLosingMomentum
+	}
+	return false
+}
+
+func (x *Features) GetStructureTrend() string {
+	if x != nil {
+		return x.StructureTrend
+	}
+	return ""
+}
+
+type PlaceShortRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Symbol string `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// Sizes the order: quantity = amount_usdt * leverage / entry_price.
+	EntryPrice float64 `protobuf:"fixed64,3,opt,name=entry_price,json=entryPrice,proto3" json:"entry_price,omitempty"`
+	StopLoss   float64 `protobuf:"fixed64,4,opt,name=stop_loss,json=stopLoss,proto3" json:"stop_loss,omitempty"`
+	// Margin in USDT; 0 uses the user's trading config.
+	AmountUsdt float64 `protobuf:"fixed64,5,opt,name=amount_usdt,json=amountUsdt,proto3" json:"amount_usdt,omitempty"`
+	// 1 to 20.
+	Leverage int32 `protobuf:"varint,6,opt,name=leverage,proto3" json:"leverage,omitempty"`
+}
+
+func (x *PlaceShortRequest) Reset() {
+	*x = PlaceShortRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaceShortRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceShortRequest) ProtoMessage() {}
+
+func (x *PlaceShortRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceShortRequest.ProtoReflect.Descriptor instead.
+func (*PlaceShortRequest) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PlaceShortRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PlaceShortRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PlaceShortRequest) GetEntryPrice() float64 {
+	if x != nil {
+		return x.EntryPrice
+	}
+	return 0
+}
+
+func (x *PlaceShortRequest) GetStopLoss() float64 {
+	if x != nil {
+		return x.StopLoss
+	}
+	return 0
+}
+
+func (x *PlaceShortRequest) GetAmountUsdt() float64 {
+	if x != nil {
+		return x.AmountUsdt
+	}
+	return 0
+}
+
+func (x *PlaceShortRequest) GetLeverage() int32 {
+	if x != nil {
+		return x.Leverage
+	}
+	return 0
+}
+
+type PlaceShortResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EntryOrderId    int64   `protobuf:"varint,1,opt,name=entry_order_id,json=entryOrderId,proto3" json:"entry_order_id,omitempty"`
+	StopLossOrderId int64   `protobuf:"varint,2,opt,name=stop_loss_order_id,json=stopLossOrderId,proto3" json:"stop_loss_order_id,omitempty"`
+	Quantity        float64 `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+}
+
+func (x *PlaceShortResponse) Reset() {
+	*x = PlaceShortResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_screener_v1_screener_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaceShortResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceShortResponse) ProtoMessage() {}
+
+func (x *PlaceShortResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_screener_v1_screener_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceShortResponse.ProtoReflect.Descriptor instead.
+func (*PlaceShortResponse) Descriptor() ([]byte, []int) {
+	return file_api_screener_v1_screener_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PlaceShortResponse) GetEntryOrderId() int64 {
+	if x != nil {
+		return x.EntryOrderId
+	}
+	return 0
+}
+
+func (x *PlaceShortResponse) GetStopLossOrderId() int64 {
+	if x != nil {
+		return x.StopLossOrderId
+	}
+	return 0
+}
+
+func (x *PlaceShortResponse) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+var File_api_screener_v1_screener_proto protoreflect.FileDescriptor
+
+var file_api_screener_v1_screener_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2f, 0x76,
+	0x31, 0x2f, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0b, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x90,
+	0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x53, 0x63, 0x6f,
+	0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x22, 0x3c, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x63, 0x6f, 0x69, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x52, 0x05, 0x63, 0x6f, 0x69, 0x6e, 0x73, 0x22,
+	0x28, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x22, 0x64, 0x0a, 0x12, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x43, 0x6f, 0x69, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6e,
+	0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6d, 0x69,
+	0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22,
+	0x67, 0x0a, 0x0b, 0x43, 0x6f, 0x69, 0x6e, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x2f,
+	0x0a, 0x05, 0x61, 0x73, 0x5f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x61, 0x73, 0x4f, 0x66, 0x12,
+	0x27, 0x0a, 0x05, 0x63, 0x6f, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69,
+	0x6e, 0x52, 0x05, 0x63, 0x6f, 0x69, 0x6e, 0x73, 0x22, 0xe9, 0x06, 0x0a, 0x04, 0x43, 0x6f, 0x69,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12,
+	0x30, 0x0a, 0x14, 0x70, 0x72, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f,
+	0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x70,
+	0x72, 0x69, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x61, 0x74,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x52, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x61, 0x73, 0x69, 0x73, 0x5f, 0x73, 0x70,
+	0x72, 0x65, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x69,
+	0x73, 0x53, 0x70, 0x72, 0x65, 0x61, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72,
+	0x5f, 0x74, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x72, 0x69, 0x67, 0x67,
+	0x65, 0x72, 0x54, 0x66, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e,
+	0x63, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f,
+	0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x38, 0x0a, 0x09, 0x74, 0x66, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52,
+	0x08, 0x74, 0x66, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x67,
+	0x69, 0x6d, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x25,
+	0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x72, 0x61, 0x64, 0x61, 0x79, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x72, 0x61, 0x64, 0x61, 0x79,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x74, 0x72, 0x61, 0x64, 0x61,
+	0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x69, 0x6e, 0x74, 0x72, 0x61, 0x64, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25,
+	0x0a, 0x0e, 0x70, 0x75, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x70, 0x75, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x75, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x70, 0x75, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25,
+	0x0a, 0x0e, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75, 0x74,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75,
+	0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2d,
+	0x0a, 0x12, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x62, 0x72, 0x65, 0x61,
+	0x6b, 0x6f, 0x75, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a,
+	0x12, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x74, 0x72, 0x65, 0x6e, 0x64, 0x5f, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x66, 0x6f, 0x6c, 0x6c, 0x6f,
+	0x77, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x66,
+	0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x74, 0x72, 0x65, 0x6e, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77,
+	0x54, 0x72, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x66,
+	0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x74, 0x72, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x66, 0x6f, 0x6c,
+	0x6c, 0x6f, 0x77, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x31, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x16, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x22, 0x48, 0x0a, 0x0e, 0x54, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d,
+	0x65, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x66, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x74, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x72, 0x73, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x72, 0x73, 0x69, 0x22, 0xfa,
+	0x03, 0x0a, 0x08, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x72,
+	0x73, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x72, 0x73, 0x69, 0x12, 0x10, 0x0a,
+	0x03, 0x63, 0x63, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x63, 0x63, 0x69, 0x12,
+	0x17, 0x0a, 0x07, 0x7a, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x06, 0x7a, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x6f, 0x76, 0x65, 0x72,
+	0x5f, 0x65, 0x78, 0x74, 0x5f, 0x65, 0x6d, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a,
+	0x6f, 0x76, 0x65, 0x72, 0x45, 0x78, 0x74, 0x45, 0x6d, 0x61, 0x12, 0x22, 0x0a, 0x0d, 0x6f, 0x76,
+	0x65, 0x72, 0x5f, 0x65, 0x78, 0x74, 0x5f, 0x76, 0x77, 0x61, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x6f, 0x76, 0x65, 0x72, 0x45, 0x78, 0x74, 0x56, 0x77, 0x61, 0x70, 0x12, 0x2e,
+	0x0a, 0x13, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x5f,
+	0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x6f, 0x70, 0x65,
+	0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x25,
+	0x0a, 0x0e, 0x6d, 0x61, 0x63, 0x64, 0x5f, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x6d, 0x61, 0x63, 0x64, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x67, 0x72, 0x61, 0x6d, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x74, 0x72, 0x5f, 0x70, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x61,
+	0x74, 0x72, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x12, 0x2b, 0x0a, 0x11,
+	0x76, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x6d,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x76, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x52, 0x65, 0x67, 0x69, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x6f,
+	0x70, 0x70, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x63,
+	0x68, 0x6f, 0x70, 0x70, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x74, 0x61, 0x6b,
+	0x65, 0x72, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x74, 0x61,
+	0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x73, 0x71, 0x75, 0x65,
+	0x65, 0x7a, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x53, 0x71, 0x75,
+	0x65, 0x65, 0x7a, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x69, 0x73, 0x5f, 0x6c, 0x6f, 0x73, 0x69, 0x6e,
+	0x67, 0x5f, 0x6d, 0x6f, 0x6d, 0x65, 0x6e, 0x74, 0x75, 0x6d, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x10, 0x69, 0x73, 0x4c, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x6d, 0x65, 0x6e, 0x74,
+	0x75, 0x6d, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x5f,
+	0x74, 0x72, 0x65, 0x6e, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x22, 0xbf, 0x01, 0x0a, 0x11,
+	0x50, 0x6c, 0x61, 0x63, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62,
+	0x6f, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x70, 0x72, 0x69, 0x63,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x6f, 0x70, 0x5f, 0x6c, 0x6f, 0x73, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x73, 0x74, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x75, 0x73, 0x64, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x55, 0x73, 0x64,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x65, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x65, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x22, 0x83, 0x01,
+	0x0a, 0x12, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x2b, 0x0a, 0x12, 0x73, 0x74,
+	0x6f, 0x70, 0x5f, 0x6c, 0x6f, 0x73, 0x73, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x74, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x32, 0xb3, 0x02, 0x0a, 0x0f, 0x53, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4a, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x43,
+	0x6f, 0x69, 0x6e, 0x73, 0x12, 0x1d, 0x2e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x12, 0x1b,
+	0x2e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x43, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x73, 0x63,
+	0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x12, 0x4a,
+	0x0a, 0x0b, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x69, 0x6e, 0x73, 0x12, 0x1f, 0x2e,
+	0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x43, 0x6f, 0x69, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
+	0x2e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69,
+	0x6e, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x12, 0x4d, 0x0a, 0x0a, 0x50, 0x6c,
+	0x61, 0x63, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x2e, 0x73, 0x63, 0x72, 0x65, 0x65,
+	0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x73, 0x63, 0x72, 0x65, 0x65,
+	0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2d, 0x5a, 0x2b, 0x73, 0x63, 0x72,
+	0x65, 0x65, 0x6e, 0x65, 0x72, 0x2d, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x3b, 0x73, 0x63,
+	0x72, 0x65, 0x65, 0x6e, 0x65, 0x72, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_screener_v1_screener_proto_rawDescOnce sync.Once
+	file_api_screener_v1_screener_proto_rawDescData = file_api_screener_v1_screener_proto_rawDesc
+)
+
+func file_api_screener_v1_screener_proto_rawDescGZIP() []byte {
+	file_api_screener_v1_screener_proto_rawDescOnce.Do(func() {
+		file_api_screener_v1_screener_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_screener_v1_screener_proto_rawDescData)
+	})
+	return file_api_screener_v1_screener_proto_rawDescData
+}
+
+var file_api_screener_v1_screener_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_screener_v1_screener_proto_goTypes = []interface{}{
+	(*ListCoinsRequest)(nil),      // 0: screener.v1.ListCoinsRequest
+	(*ListCoinsResponse)(nil),     // 1: screener.v1.ListCoinsResponse
+	(*GetCoinRequest)(nil),        // 2: screener.v1.GetCoinRequest
+	(*StreamCoinsRequest)(nil),    // 3: screener.v1.StreamCoinsRequest
+	(*CoinsUpdate)(nil),           // 4: screener.v1.CoinsUpdate
+	(*Coin)(nil),                  // 5: screener.v1.Coin
+	(*TimeframeScore)(nil),        // 6: screener.v1.TimeframeScore
+	(*Features)(nil),              // 7: screener.v1.Features
+	(*PlaceShortRequest)(nil),     // 8: screener.v1.PlaceShortRequest
+	(*PlaceShortResponse)(nil),    // 9: screener.v1.PlaceShortResponse
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_api_screener_v1_screener_proto_depIdxs = []int32{
+	5,  // 0: screener.v1.ListCoinsResponse.coins:type_name -> screener.v1.Coin
+	10, // 1: screener.v1.CoinsUpdate.as_of:type_name -> google.protobuf.Timestamp
+	5,  // 2: screener.v1.CoinsUpdate.coins:type_name -> screener.v1.Coin
+	6,  // 3: screener.v1.Coin.tf_scores:type_name -> screener.v1.TimeframeScore
+	7,  // 4: screener.v1.Coin.features:type_name -> screener.v1.Features
+	0,  // 5: screener.v1.ScreenerService.ListCoins:input_type -> screener.v1.ListCoinsRequest
+	2,  // 6: screener.v1.ScreenerService.GetCoin:input_type -> screener.v1.GetCoinRequest
+	3,  // 7: screener.v1.ScreenerService.StreamCoins:input_type -> screener.v1.StreamCoinsRequest
+	8,  // 8: screener.v1.ScreenerService.PlaceShort:input_type -> screener.v1.PlaceShortRequest
+	1,  // 9: screener.v1.ScreenerService.ListCoins:output_type -> screener.v1.ListCoinsResponse
+	5,  // 10: screener.v1.ScreenerService.GetCoin:output_type -> screener.v1.Coin
+	4,  // 11: screener.v1.ScreenerService.StreamCoins:output_type -> screener.v1.CoinsUpdate
+	9,  // 12: screener.v1.ScreenerService.PlaceShort:output_type -> screener.v1.PlaceShortResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_api_screener_v1_screener_proto_init() }
+func file_api_screener_v1_screener_proto_init() {
+	if File_api_screener_v1_screener_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_screener_v1_screener_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCoinsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCoinsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCoinRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamCoinsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoinsUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Coin); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimeframeScore); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Features); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaceShortRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_screener_v1_screener_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaceShortResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_screener_v1_screener_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_screener_v1_screener_proto_goTypes,
+		DependencyIndexes: file_api_screener_v1_screener_proto_depIdxs,
+		MessageInfos:      file_api_screener_v1_screener_proto_msgTypes,
+	}.Build()
+	File_api_screener_v1_screener_proto = out.File
+	file_api_screener_v1_screener_proto_rawDesc = nil
+	file_api_screener_v1_screener_proto_goTypes = nil
+	file_api_screener_v1_screener_proto_depIdxs = nil
+}
@@ -0,0 +1,62 @@
+// Command migrate applies or rolls back the versioned schema migrations.
+//
+//	migrate up          apply all pending migrations (the server also does this on start)
+//	migrate down [n]    roll back the latest n migrations (default 1)
+//	migrate status      list migrations and whether they are applied
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"screener-backend/internal/infrastructure/db"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: migrate up | down [n] | status")
+	}
+
+	dbURL := db.ResolveDatabaseURL()
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dbURL, db.DefaultPoolConfig())
+	if err != nil {
+		log.Fatalf("Failed to create DB pool: %v", err)
+	}
+	defer pool.Close()
+
+	switch os.Args[1] {
+	case "up":
+		err = db.Migrate(ctx, pool)
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			if steps, err = strconv.Atoi(os.Args[2]); err != nil || steps < 1 {
+				log.Fatalf("invalid step count %q", os.Args[2])
+			}
+		}
+		err = db.MigrateDown(ctx, pool, steps)
+	case "status":
+		var statuses []db.MigrationStatus
+		statuses, err = db.Status(ctx, pool)
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, state)
+		}
+	default:
+		log.Fatalf("unknown command %q", os.Args[1])
+	}
+	if err != nil {
+		log.Fatalf("migrate %s failed: %v", os.Args[1], err)
+	}
+}
@@ -3,77 +3,128 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
+	"screener-backend/internal/config"
+	grpcserver "screener-backend/internal/delivery/grpc"
 	httphandler "screener-backend/internal/delivery/http"
 	"screener-backend/internal/delivery/websocket"
 	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
 	"screener-backend/internal/infrastructure/db"
 	"screener-backend/internal/infrastructure/fcm"
+	"screener-backend/internal/infrastructure/leader"
+	"screener-backend/internal/infrastructure/logging"
+	"screener-backend/internal/infrastructure/marketdata"
+	"screener-backend/internal/infrastructure/metrics"
+	"screener-backend/internal/infrastructure/pubsub"
+	"screener-backend/internal/infrastructure/redis"
+	"screener-backend/internal/infrastructure/reporting"
+	"screener-backend/internal/infrastructure/scheduler"
+	"screener-backend/internal/infrastructure/tracing"
 	"screener-backend/internal/repository"
 	"screener-backend/internal/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 )
 
-func resolveDatabaseURL() string {
-	if v := strings.TrimSpace(os.Getenv("DATABASE_URL")); v != "" {
-		return v
-	}
+func main() {
+	// ctx lives until the background work has stopped; SIGTERM (a Heroku
+	// dyno restart) or Ctrl-C starts the shutdown at the end of main
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stopSignals()
 
-	// Common case: user references the add-on variable explicitly.
-	if v := strings.TrimSpace(os.Getenv("HEROKU_POSTGRESQL_YELLOW_URL")); v != "" {
-		return v
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
 	}
 
-	// Fallback: scan any Heroku Postgres add-on URL.
-	for _, kv := range os.Environ() {
-		parts := strings.SplitN(kv, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		key := parts[0]
-		val := strings.TrimSpace(parts[1])
-		if val == "" {
-			continue
-		}
-		if strings.HasPrefix(key, "HEROKU_POSTGRESQL_") && strings.HasSuffix(key, "_URL") {
-			return val
-		}
-	}
+	applyLogging(cfg)
 
-	return ""
-}
+	shutdownTracing, err := tracing.Init(tracing.Config{
+		Endpoint:    cfg.Tracing.Endpoint,
+		Headers:     cfg.Tracing.Headers,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("Invalid configuration: tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+	if cfg.Tracing.Endpoint != "" {
+		log.Printf("✓ Tracing enabled (OTLP → %s)", cfg.Tracing.Endpoint)
+	}
 
-func main() {
-	ctx := context.Background()
+	shutdownReporting, err := reporting.Init(reporting.Config{
+		DSN:         cfg.Reporting.SentryDSN,
+		Environment: cfg.Reporting.Environment,
+		Release:     cfg.Reporting.Release,
+		ServerName:  leader.InstanceID(),
+	})
+	if err != nil {
+		log.Fatalf("Invalid configuration: reporting: %v", err)
+	}
+	defer shutdownReporting(context.Background())
+	if cfg.Reporting.SentryDSN != "" {
+		log.Println("✓ Error reporting enabled (Sentry)")
+	}
 
 	// 1. Initialize Repositories
-	repo := repository.NewInMemoryScreenerRepository()
+	var repo domain.ScreenerRepository = repository.NewInMemoryScreenerRepository()
+	var priceCache domain.PriceCache = repository.NewInMemoryPriceCache()
+	var cooldownStore domain.CooldownStore
+	var redisClient *redis.Client
+	if cfg.Redis.URL != "" {
+		redisClient, err = redis.NewClient(cfg.Redis.URL)
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		defer redisClient.Close()
+		repo = repository.NewRedisScreenerRepository(redisClient)
+		priceCache = repository.NewRedisPriceCache(redisClient)
+		cooldownStore = repository.NewRedisCooldownStore(redisClient)
+		log.Println("✓ Redis connected (coin snapshots, prices and cooldowns)")
+	}
 	tokenRepo := repository.NewTokenRepository()
-	tradeRepo := repository.NewInMemoryTradeRepository()
 	
 	// Initialize Binance API Repository with encryption key
-	encryptionKey := os.Getenv("API_ENCRYPTION_KEY")
-	dbURL := resolveDatabaseURL()
-	if dbURL != "" {
-		// Production safety: do not allow weak/empty encryption key when persisting secrets.
-		if len(encryptionKey) < 32 {
-			log.Fatal("API_ENCRYPTION_KEY is required and must be at least 32 characters when Postgres is enabled")
-		}
-	} else {
+	// (config validation rejects a weak key when Postgres is enabled)
+	encryptionKey := cfg.Security.EncryptionKey
+	dbURL := cfg.Database.URL
+	if dbURL == "" && encryptionKey == "" {
 		// Dev fallback only (in-memory storage).
-		if encryptionKey == "" {
-			encryptionKey = "dev-only-default-key-change-in-production"
-		}
+		encryptionKey = "dev-only-default-key-change-in-production"
 	}
 
 	var autoScalpRepo domain.AutoScalpRepository
 	var binanceAPIRepo domain.BinanceAPIStore
+	var tradeRepo domain.TradeEntryRepository
+	var idempotencyRepo domain.IdempotencyRepository
+	var summaryRepo domain.DailySummaryRepository
+	var archiveRepo domain.MarketArchiveRepository
+	var configHistoryRepo domain.ConfigHistoryRepository
+	var signalRepo domain.SignalRepository
+	var scoringRepo domain.ScoringVersionRepository
+	var blackoutRepo domain.BlackoutRepository
+	var recordingRepo domain.CycleRecordingRepository
+	var watchlistRepo domain.WatchlistRepository
+	var userRepo domain.UserRepository
+	var screenerConfigRepo domain.ScreenerConfigRepository
+	var scoringProfileRepo domain.ScoringProfileRepository
+	var pool *pgxpool.Pool
+	var dbPing func(ctx context.Context) error
 
 	if dbURL != "" {
-		pool, err := db.NewPool(ctx, dbURL, db.DefaultPoolConfig())
+		pool, err = db.NewPool(ctx, dbURL, cfg.Database.PoolConfig())
 		if err != nil {
 			log.Fatalf("Failed to create DB pool: %v", err)
 		}
@@ -82,18 +133,64 @@ func main() {
 		if err := db.Migrate(ctx, pool); err != nil {
 			log.Fatalf("DB migrate failed: %v", err)
 		}
-		log.Println("✓ Postgres connected (pooled) and migrated")
 
-		autoScalpRepo = repository.NewPostgresAutoScalpRepository(pool)
-		binanceAPIRepo = repository.NewPostgresBinanceAPIRepository(pool, encryptionKey)
+		// Order bookkeeping gets its own small pool with a short statement
+		// timeout so heavy history queries can't starve it
+		tradingPool, err := db.NewPool(ctx, dbURL, cfg.Database.TradingPoolConfig())
+		if err != nil {
+			log.Fatalf("Failed to create trading DB pool: %v", err)
+		}
+		defer tradingPool.Close()
+		log.Println("✓ Postgres connected (main + trading pools) and migrated")
+		dbPing = func(ctx context.Context) error {
+			if err := pool.Ping(ctx); err != nil {
+				return err
+			}
+			return tradingPool.Ping(ctx)
+		}
+
+		autoScalpRepo = repository.NewPostgresAutoScalpRepository(tradingPool)
+		binanceAPIRepo = repository.NewPostgresBinanceAPIRepository(tradingPool, encryptionKey)
+		idempotencyRepo = repository.NewPostgresIdempotencyRepository(tradingPool)
+		tradeRepo = repository.NewPostgresTradeRepository(pool)
+		summaryRepo = repository.NewPostgresDailySummaryRepository(pool)
+		archiveRepo = repository.NewPostgresMarketArchiveRepository(pool)
+		configHistoryRepo = repository.NewPostgresConfigHistoryRepository(pool)
+		signalRepo = repository.NewPostgresSignalRepository(pool)
+		scoringRepo = repository.NewPostgresScoringVersionRepository(pool)
+		blackoutRepo = repository.NewPostgresBlackoutRepository(pool)
+		recordingRepo = repository.NewPostgresCycleRecordingRepository(pool)
+		watchlistRepo = repository.NewPostgresWatchlistRepository(pool)
+		userRepo = repository.NewPostgresUserRepository(pool)
+		screenerConfigRepo = repository.NewPostgresScreenerConfigRepository(pool)
+		scoringProfileRepo = repository.NewPostgresScoringProfileRepository(pool)
+		if cooldownStore == nil {
+			cooldownStore = repository.NewPostgresCooldownStore(tradingPool)
+		}
 	} else {
 		log.Println("⚠ Postgres not configured (DATABASE_URL / HEROKU_POSTGRESQL_*_URL not set); using in-memory storage")
 		autoScalpRepo = repository.NewInMemoryAutoScalpRepository()
 		binanceAPIRepo = repository.NewBinanceAPIRepository(encryptionKey)
+		tradeRepo = repository.NewInMemoryTradeRepository()
+		idempotencyRepo = repository.NewInMemoryIdempotencyRepository()
+		summaryRepo = repository.NewInMemoryDailySummaryRepository()
+		archiveRepo = repository.NewInMemoryMarketArchiveRepository()
+		configHistoryRepo = repository.NewInMemoryConfigHistoryRepository()
+		signalRepo = repository.NewInMemorySignalRepository()
+		scoringRepo = repository.NewInMemoryScoringVersionRepository()
+		blackoutRepo = repository.NewInMemoryBlackoutRepository()
+		recordingRepo = repository.NewInMemoryCycleRecordingRepository()
+		watchlistRepo = repository.NewInMemoryWatchlistRepository()
+		userRepo = repository.NewInMemoryUserRepository()
+		screenerConfigRepo = repository.NewInMemoryScreenerConfigRepository()
+		scoringProfileRepo = repository.NewInMemoryScoringProfileRepository()
+		if cooldownStore == nil {
+			cooldownStore = repository.NewInMemoryCooldownStore()
+		}
 	}
 
 	// 2. Initialize FCM Client
-	fcmClient, err := fcm.NewClient()
+	fcmClient, err := fcm.NewClient(cfg.Notifications.FirebaseCredentialsPath, cfg.Notifications.FirebaseCredentialsJSON)
 	if err != nil {
 		log.Printf("Warning: FCM initialization failed: %v", err)
 		log.Println("Server will continue without push notifications")
@@ -104,31 +201,141 @@ func main() {
 	}
 
 	// 3. Initialize Usecase
-	binanceBaseURL := os.Getenv("BINANCE_BASE_URL")
-	uc := usecase.NewScreenerUsecase(repo, tokenRepo, fcmClient, binanceBaseURL)
-	
-	// 4. Initialize Auto Scalping Service
-	autoScalpService := usecase.NewAutoScalpingService(autoScalpRepo, repo)
-	
-	// Start auto scalping monitor (every 5 seconds)
+	binanceBaseURL := cfg.Binance.BaseURL
+	binance.SetWeightBudget(cfg.Binance.WeightBudget)
+	binance.SetRetryPolicy(binance.RetryPolicy{Retries: cfg.Binance.Retries, Backoff: cfg.Binance.RetryBackoff})
+	events := newEventBus(cfg, redisClient, pool)
+	log.Printf("✓ Event bus: %s", events)
+	regimeFilter := usecase.NewRegimeFilter(cfg)
+	marketContext := usecase.NewMarketContextService(marketdata.NewClient(cfg.MarketContext.CoinGeckoURL, cfg.MarketContext.FearGreedURL, cfg.MarketContext.CoinGeckoAPIKey), cfg)
+	blackouts := usecase.NewBlackoutCalendar(blackoutRepo)
+	correlations := usecase.NewCorrelationTracker(cfg)
+	watchlists := usecase.NewWatchlistService(watchlistRepo)
+	screenerConfigs := usecase.NewScreenerConfigService(screenerConfigRepo)
+
+	// Runtime-reloadable settings: admin API or SIGHUP
+	configStore := config.NewStore(cfg)
+	scoringProfiles := usecase.NewScoringProfileService(scoringProfileRepo, configStore, screenerConfigs, regimeFilter)
+	uc := usecase.NewScreenerUsecase(repo, tokenRepo, fcmClient, cfg, archiveRepo, cooldownStore, events, signalRepo, regimeFilter, marketContext, blackouts, correlations, recordingRepo, watchlists, screenerConfigs, scoringProfiles)
+	configStore.OnChange(uc.ApplyConfig)
+	configStore.OnChange(regimeFilter.ApplyConfig)
+	configStore.OnChange(correlations.ApplyConfig)
+	configStore.OnChange(marketContext.ApplyConfig)
+	configStore.OnChange(applyLogging)
+	configHistory := usecase.NewConfigHistoryService(configStore, configHistoryRepo)
 	go func() {
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-		for range ticker.C {
-			autoScalpService.MonitorAndExecute()
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		for range hup {
+			if _, err := configHistory.Reload(ctx, "sighup"); err != nil {
+				log.Printf("Config reload failed: %v", err)
+				continue
+			}
+			log.Println("✓ Configuration reloaded")
 		}
 	}()
+	
+	// 4. Initialize Auto Scalping Service
+	fillSim := usecase.NewFillSimulator(binance.NewClient(binanceBaseURL), cfg)
+	configStore.OnChange(fillSim.ApplyConfig)
+	binanceTrading := usecase.NewBinanceTradingService(binanceAPIRepo, autoScalpRepo, binance.NewSymbolFilterCache(binance.NewClient(binanceBaseURL)))
+	autoScalpService := usecase.NewAutoScalpingService(autoScalpRepo, repo, priceCache, events, regimeFilter, blackouts, fillSim, binanceTrading)
+	tradeMonitor := usecase.NewTradeMonitorService(tradeRepo, repo, fillSim)
+	dailySummary := usecase.NewDailySummaryService(tradeRepo, summaryRepo, tokenRepo, fcmClient)
+	signalOutcomes := usecase.NewSignalOutcomeService(signalRepo, binance.NewClient(binanceBaseURL))
 
-	// 5. Start Screener Loop in background
-	go uc.Run()
+	// 5. Background jobs; intervals are re-read after every run, and a reload
+	// reschedules them right away. When scaled out, only the elected leader
+	// runs the jobs that trade or notify.
+	elector := newElector(cfg, redisClient, pool)
+	electorDone := make(chan struct{})
+	go func() {
+		elector.Run(ctx)
+		close(electorDone)
+	}()
+	jobs := scheduler.New(elector.IsLeader)
+	jobs.RegisterLeaderOnly("screener", scheduler.Every(uc.ScanInterval), scheduler.Timeout(cfg.Screener.CycleTimeout, uc.RunCycle))
+	jobs.RegisterLeaderOnly("autoscalp-monitor",
+		scheduler.Every(func() time.Duration { return configStore.Current().AutoScalp.MonitorInterval }),
+		scheduler.Timeout(monitorJobTimeout, autoScalpService.MonitorAndExecute))
+	jobs.RegisterLeaderOnly("trade-monitor",
+		scheduler.Every(func() time.Duration { return configStore.Current().AutoScalp.TradeMonitorInterval }),
+		scheduler.Timeout(monitorJobTimeout, tradeMonitor.CheckEntries))
+	jobs.RegisterLeaderOnly("daily-summary", scheduler.DailyAt(23, 55), scheduler.Timeout(maintenanceJobTimeout, func(ctx context.Context) error {
+		return dailySummary.RunForDay(ctx, time.Now().UTC())
+	}))
+	jobs.RegisterLeaderOnly("signal-outcomes",
+		scheduler.Every(func() time.Duration { return time.Minute }),
+		scheduler.Timeout(maintenanceJobTimeout, signalOutcomes.Label))
+	// Every instance serves /api/market/context, so every instance refreshes it
+	if cfg.MarketContext.Enabled {
+		jobs.Register("market-context",
+			scheduler.Every(func() time.Duration { return configStore.Current().MarketContext.Interval }),
+			scheduler.Timeout(maintenanceJobTimeout, marketContext.Refresh))
+		go func() {
+			if err := marketContext.Refresh(ctx); err != nil {
+				log.Printf("Market context: initial fetch failed: %v", err)
+			}
+		}()
+	}
+	jobs.Register("token-cleanup", scheduler.DailyAt(3, 0), scheduler.Simple(func() {
+		if n := tokenRepo.PruneStale(staleTokenAge); n > 0 {
+			log.Printf("Token cleanup: removed %d stale device tokens", n)
+		}
+	}))
+	retention := usecase.NewRetentionService(archiveRepo, autoScalpRepo, cooldownStore, signalRepo, func() config.RetentionConfig {
+		return configStore.Current().Retention
+	})
+	jobs.RegisterLeaderOnly("retention", scheduler.DailyAt(4, 0), scheduler.Timeout(maintenanceJobTimeout, retention.Prune))
+	configStore.OnChange(func(*config.Config) { jobs.Reschedule() })
+	elector.OnChange(func(isLeader bool) {
+		if isLeader {
+			// the previous leader may have notified since startup
+			restoreCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			uc.RestoreCooldowns(restoreCtx)
+			cancel()
+			jobs.Trigger("screener")
+		}
+	})
+	jobs.Start(ctx)
+	jobs.Trigger("screener") // first cycle right away (no-op on followers)
 
 	// 6. Initialize HTTP Handlers
-	wsHandler := websocket.NewHandler(repo)
+	wsHandler := websocket.NewHandler(repo, watchlists, scoringProfiles)
+	grpcService := grpcserver.NewServer(repo, watchlists, scoringProfiles, binanceTrading)
+	go events.Run(ctx, func(topic string, payload []byte) {
+		wsHandler.Dispatch(topic, payload)
+		grpcService.Dispatch(topic, payload)
+	})
 	tokenHandler := httphandler.NewTokenHandler(tokenRepo)
 	testHandler := httphandler.NewTestHandler(fcmClient, tokenRepo)
-	tradeHandler := httphandler.NewTradeHandler(tradeRepo)
+	tradeImportService := usecase.NewTradeImportService(binanceAPIRepo, tradeRepo, usecase.NewUSDConverter(binanceBaseURL))
+	tradeHandler := httphandler.NewTradeHandler(tradeRepo, tradeImportService, repo, dailySummary, autoScalpRepo)
 	autoScalpHandler := httphandler.NewAutoScalpHandler(autoScalpService)
 	binanceAPIHandler := httphandler.NewBinanceAPIHandler(binanceAPIRepo)
+	emergencyStopHandler := httphandler.NewEmergencyStopHandler(autoScalpService)
+	archiveHandler := httphandler.NewArchiveHandler(archiveRepo)
+	datasetService := usecase.NewDatasetService(archiveRepo)
+	exportHandler := httphandler.NewExportHandler(repo, datasetService)
+	coinHandler := httphandler.NewCoinHandler(repo, scoringProfiles)
+	signalHandler := httphandler.NewSignalHandler(signalOutcomes)
+	marketHandler := httphandler.NewMarketHandler(regimeFilter, marketContext)
+	watchlistHandler := httphandler.NewWatchlistHandler(watchlists)
+	screenerConfigHandler := httphandler.NewScreenerConfigHandler(screenerConfigs)
+	scoringProfileHandler := httphandler.NewScoringProfileHandler(scoringProfiles)
+	var auth *usecase.AuthService
+	if cfg.Security.JWTSecret != "" {
+		auth = usecase.NewAuthService(userRepo, cfg.Security.JWTSecret, cfg.Security.TokenTTL)
+		log.Printf("✓ User authentication enabled (required: %v)", !cfg.Security.AllowAnonymous)
+	} else {
+		log.Println("⚠ User authentication disabled (ALLOW_ANONYMOUS) - for local development only")
+	}
+	authHandler := httphandler.NewAuthHandler(auth)
+	portfolioHandler := httphandler.NewPortfolioHandler(usecase.NewPortfolioService(binanceAPIRepo, autoScalpRepo, tradeRepo, repo))
+	analyticsHandler := httphandler.NewAnalyticsHandler(usecase.NewStrategyAnalyticsService(signalRepo, autoScalpRepo, tradeRepo), correlations)
+	backtestHandler := httphandler.NewBacktestHandler(usecase.NewBacktester(uc, binance.NewClient(binanceBaseURL), configStore))
+	selfTest := usecase.NewSelfTestService(binance.NewClient(binanceBaseURL), binanceAPIRepo, dbPing, fcmClient)
+	adminHandler := httphandler.NewAdminHandler(configStore, jobs, usecase.NewCredentialBackupService(binanceAPIRepo), selfTest, configHistory, usecase.NewScoringOptimizer(datasetService, scoringRepo, configStore), blackouts, usecase.NewReplayService(uc, recordingRepo, configStore))
 
 	// Routes
 	http.HandleFunc("/ws", wsHandler.Handle)
@@ -137,6 +344,10 @@ func main() {
 		w.Write([]byte(`{"status":"ok"}`))
 	})
 	
+	// User authentication
+	http.HandleFunc("/api/auth/register", authHandler.Register)
+	http.HandleFunc("/api/auth/login", authHandler.Login)
+
 	// Token management endpoints
 	http.HandleFunc("/api/register-token", tokenHandler.HandleRegisterToken)
 	http.HandleFunc("/api/unregister-token", tokenHandler.HandleUnregisterToken)
@@ -146,9 +357,10 @@ func main() {
 	http.HandleFunc("/api/test-notification", testHandler.SendTestNotification)
 
 	// Trade management endpoints
+	createTrade := httphandler.Idempotent(idempotencyRepo, cfg.Server.RequestTimeout, tradeHandler.CreateEntry)
 	http.HandleFunc("/api/trades", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
-			tradeHandler.CreateEntry(w, r)
+			createTrade(w, r)
 		} else if r.Method == http.MethodGet {
 			if r.URL.Query().Get("status") == "active" {
 				tradeHandler.GetActiveEntries(w, r)
@@ -159,21 +371,41 @@ func main() {
 	})
 	http.HandleFunc("/api/trades/active", tradeHandler.GetActiveEntries)
 	http.HandleFunc("/api/trades/history", tradeHandler.GetHistory)
-	http.HandleFunc("/api/trades/update", tradeHandler.UpdateEntry)
-	http.HandleFunc("/api/trades/delete", tradeHandler.DeleteEntry)
+	http.HandleFunc("/api/trades/export", tradeHandler.ExportEntries)
+	http.HandleFunc("/api/trades/analytics", tradeHandler.GetAnalytics)
+	http.HandleFunc("/api/trades/equity", tradeHandler.GetEquityCurve)
+	http.HandleFunc("/api/trades/summaries", tradeHandler.GetDailySummaries)
+	http.HandleFunc("/api/trades/calendar", tradeHandler.GetCalendar)
+	http.HandleFunc("/api/trades/import", httphandler.Idempotent(idempotencyRepo, cfg.Server.RequestTimeout, tradeHandler.ImportFromBinance))
+	http.HandleFunc("/api/trades/archived", tradeHandler.GetArchivedEntries)
+	http.HandleFunc("/api/trades/{id}/restore", tradeHandler.RestoreEntry)
+	http.HandleFunc("/api/trades/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			tradeHandler.GetEntry(w, r)
+		case http.MethodPut:
+			tradeHandler.UpdateEntry(w, r)
+		case http.MethodDelete:
+			tradeHandler.DeleteEntry(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
 
 	// Auto Scalping endpoints
+	updateAutoScalpSettings := httphandler.Idempotent(idempotencyRepo, cfg.Server.RequestTimeout, autoScalpHandler.UpdateSettings)
 	http.HandleFunc("/api/autoscalp/settings", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			autoScalpHandler.GetSettings(w, r)
 		} else if r.Method == http.MethodPost {
-			autoScalpHandler.UpdateSettings(w, r)
+			updateAutoScalpSettings(w, r)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
 	http.HandleFunc("/api/autoscalp/active", autoScalpHandler.GetActivePositions)
 	http.HandleFunc("/api/autoscalp/history", autoScalpHandler.GetHistory)
+	http.HandleFunc("/api/autoscalp/equity", autoScalpHandler.GetEquityCurve)
 
 	// Binance API endpoints
 	http.HandleFunc("/api/binance/credentials", func(w http.ResponseWriter, r *http.Request) {
@@ -199,15 +431,258 @@ func main() {
 		}
 	})
 	http.HandleFunc("/api/binance/test-connection", binanceAPIHandler.TestConnection)
+	http.HandleFunc("/api/binance/sub-accounts", binanceAPIHandler.GetSubAccounts)
+	http.HandleFunc("/api/binance/emergency-stop", httphandler.RequireToken(emergencyStopHandler.Stop, auth))
+	http.HandleFunc("/api/portfolio", portfolioHandler.GetPortfolio)
+	http.HandleFunc("/api/backtest", backtestHandler.RunBacktest)
 
-	// Get port from environment variable (Heroku sets this)
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Watchlists
+	http.HandleFunc("/api/watchlist", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			watchlistHandler.GetWatchlist(w, r)
+		case http.MethodPost:
+			watchlistHandler.SaveItem(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/watchlist/{symbol}", watchlistHandler.DeleteItem)
+
+	// Screener strategies and timeframes
+	http.HandleFunc("/api/screener/config", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			screenerConfigHandler.GetConfig(w, r)
+		case http.MethodPost:
+			screenerConfigHandler.SaveConfig(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/screener/scoring-profile", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			scoringProfileHandler.GetProfile(w, r)
+		case http.MethodPost:
+			scoringProfileHandler.SaveProfile(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Market archive
+	http.HandleFunc("/api/archive/klines", archiveHandler.GetKlines)
+	http.HandleFunc("/api/archive/snapshots", archiveHandler.GetSnapshots)
+	http.HandleFunc("/api/screener/history", archiveHandler.GetScreenerHistory)
+
+	// Signal outcomes
+	http.HandleFunc("/api/signals", signalHandler.List)
+	http.HandleFunc("/api/signals/stats", signalHandler.GetStats)
+	http.HandleFunc("/api/analytics/strategies", analyticsHandler.GetStrategies)
+	http.HandleFunc("/api/analytics/correlations", analyticsHandler.GetCorrelations)
+	http.HandleFunc("/api/coins/heatmap", coinHandler.GetHeatmap)
+	http.HandleFunc("/api/screener/coins", coinHandler.ListCoins)
+	http.HandleFunc("/api/market/regime", marketHandler.GetRegime)
+	http.HandleFunc("/api/market/context", marketHandler.GetContext)
+
+	// Bulk export of the latest cycle
+	http.HandleFunc("/api/export/snapshot", exportHandler.GetSnapshot)
+	http.HandleFunc("/api/export/dataset", exportHandler.GetDataset)
+
+	// Admin
+	http.HandleFunc("/api/admin/config", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			adminHandler.GetConfig(w, r)
+		case http.MethodPatch:
+			adminHandler.UpdateConfig(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/admin/config/reload", adminHandler.ReloadConfig)
+	http.HandleFunc("/api/admin/config/history", adminHandler.GetConfigHistory)
+	http.HandleFunc("/api/admin/config/history/{id}/rollback", adminHandler.RollbackConfig)
+	http.HandleFunc("/api/admin/logging", adminHandler.Logging)
+	http.HandleFunc("/api/admin/binance-usage", adminHandler.GetBinanceUsage)
+	http.HandleFunc("/api/admin/jobs", adminHandler.GetJobs)
+	http.HandleFunc("/api/admin/jobs/{name}/run", adminHandler.RunJob)
+	http.HandleFunc("/api/admin/credentials/export", adminHandler.ExportCredentials)
+	http.HandleFunc("/api/admin/credentials/import", adminHandler.ImportCredentials)
+	http.HandleFunc("/api/admin/selftest", adminHandler.GetSelfTest)
+	http.HandleFunc("/api/admin/scoring/optimize", adminHandler.OptimizeScoring)
+	http.HandleFunc("/api/admin/scoring/versions", adminHandler.GetScoringVersions)
+	http.HandleFunc("/api/admin/scoring/versions/{id}/evaluation", adminHandler.EvaluateScoringVersion)
+	http.HandleFunc("/api/admin/blackouts", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			adminHandler.GetBlackouts(w, r)
+		case http.MethodPost:
+			adminHandler.CreateBlackout(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/admin/blackouts/{id}", adminHandler.DeleteBlackout)
+	http.HandleFunc("/api/admin/replay/recordings", adminHandler.GetCycleRecordings)
+	http.HandleFunc("/api/admin/replay", adminHandler.ReplayCycle)
+
+	// Prometheus scrape endpoint
+	http.Handle("/metrics", metrics.Handler())
+
+	// Port comes from PORT (Heroku sets this), default 8080
+	port := cfg.Server.Port
+
+	// Connectivity report; failures are logged and never stop the server
+	go selfTest.Run(ctx)
+
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort != "" {
+		lis, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+		if err != nil {
+			log.Fatalf("gRPC listen: %v", err)
+		}
+		log.Printf("✓ gRPC API on port %s", cfg.Server.GRPCPort)
+		grpcServer = grpcserver.NewGRPCServer(grpcService, auth, !cfg.Security.AllowAnonymous)
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatal(err)
+			}
+		}()
 	}
 
 	log.Printf("Server starting on port %s", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+	handler := httphandler.WithRequestTimeout(httphandler.WithRecover(httphandler.WithAuth(http.DefaultServeMux, auth, !cfg.Security.AllowAnonymous)), cfg.Server.RequestTimeout)
+	srv := &http.Server{Addr: ":" + port, Handler: handler}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	select {
+	case err := <-serveErr:
 		log.Fatal(err)
+	case <-sigCtx.Done():
+	}
+	stopSignals() // a second signal kills the process right away
+
+	log.Printf("Shutting down (waiting up to %v)...", cfg.Server.ShutdownTimeout)
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancelShutdown()
+	shutdown(shutdownCtx, srv, grpcServer, grpcService, wsHandler, jobs)
+
+	// Leadership goes last, so no other instance starts trading while this
+	// one finishes; the deferred closes then release Redis and Postgres
+	cancel()
+	select {
+	case <-electorDone:
+	case <-shutdownCtx.Done():
+	}
+	log.Println("✓ Shutdown complete")
+}
+
+// shutdown stops taking work and lets the work in flight finish: WebSocket
+// clients are sent away, gRPC streams ended, HTTP requests and unary gRPC
+// calls drained, and the background jobs (screener cycle, autoscalp and
+// trade monitors) finish their current run. Writes to Postgres happen within
+// those requests and runs, so none are pending afterwards. Whatever is still
+// going when ctx is done is cancelled.
+func shutdown(ctx context.Context, srv *http.Server, grpcServer *grpc.Server, grpcService *grpcserver.Server, wsHandler *websocket.Handler, jobs *scheduler.Scheduler) {
+	wsHandler.Close()
+	grpcService.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("HTTP shutdown: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if grpcServer == nil {
+			return
+		}
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}()
+
+	if err := jobs.Stop(ctx); err != nil {
+		log.Printf("Background jobs still running were cancelled: %v", err)
+	} else {
+		log.Println("✓ Background jobs stopped")
 	}
+	wg.Wait()
 }
+
+// newElector picks the worker lock per leader.lock; auto prefers Redis, then
+// Postgres, and falls back to a single always-leading instance.
+func newElector(cfg *config.Config, redisClient *redis.Client, pool *pgxpool.Pool) *leader.Elector {
+	mode := cfg.Leader.Lock
+	if mode == "auto" {
+		switch {
+		case redisClient != nil:
+			mode = "redis"
+		case pool != nil:
+			mode = "postgres"
+		default:
+			mode = "none"
+		}
+	}
+	switch mode {
+	case "redis":
+		return leader.NewRedis(redisClient, cfg.Leader.TTL)
+	case "postgres":
+		return leader.NewPostgres(pool, cfg.Leader.TTL)
+	default:
+		return leader.Single()
+	}
+}
+
+// newEventBus picks the WebSocket event bus per events.bus; auto prefers
+// Redis, then Postgres, and falls back to this instance only.
+func newEventBus(cfg *config.Config, redisClient *redis.Client, pool *pgxpool.Pool) pubsub.Broker {
+	mode := cfg.Events.Bus
+	if mode == "auto" {
+		switch {
+		case redisClient != nil:
+			mode = "redis"
+		case pool != nil:
+			mode = "postgres"
+		default:
+			mode = "local"
+		}
+	}
+	switch mode {
+	case "redis":
+		return pubsub.NewRedis(redisClient)
+	case "postgres":
+		return pubsub.NewPostgres(pool)
+	default:
+		return pubsub.NewLocal()
+	}
+}
+
+// applyLogging sets the log level and per-symbol selection (validated by config)
+func applyLogging(cfg *config.Config) {
+	level, _ := logging.ParseLevel(cfg.Logging.Level)
+	logging.SetLevel(level)
+	logging.SetSymbols(cfg.Logging.Symbols)
+}
+
+// staleTokenAge is how long a device token survives without re-registering
+const staleTokenAge = 60 * 24 * time.Hour
+
+// Upper bounds of one job run; a run cut short fails with the context error
+// and the next one starts on schedule
+const (
+	monitorJobTimeout     = 30 * time.Second
+	maintenanceJobTimeout = 10 * time.Minute
+)
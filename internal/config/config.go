@@ -0,0 +1,709 @@
+// Package config loads the typed application configuration. Values start
+// from the defaults declared on the struct tags, are overridden by the
+// optional YAML file named in CONFIG_FILE, then by environment variables.
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/db"
+	"screener-backend/internal/infrastructure/logging"
+	"screener-backend/internal/infrastructure/secrets"
+)
+
+// Config is the full application configuration
+type Config struct {
+	Server        ServerConfig        `yaml:"server"`
+	Security      SecurityConfig      `yaml:"security"`
+	Binance       BinanceConfig       `yaml:"binance"`
+	Screener      ScreenerConfig      `yaml:"screener"`
+	AutoScalp     AutoScalpConfig     `yaml:"autoscalp"`
+	Notifications NotificationConfig  `yaml:"notifications"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Redis         RedisConfig         `yaml:"redis"`
+	Tracing       TracingConfig       `yaml:"tracing"`
+	Reporting     ReportingConfig     `yaml:"reporting"`
+	Logging       LoggingConfig       `yaml:"logging"`
+	Leader        LeaderConfig        `yaml:"leader"`
+	Events        EventsConfig        `yaml:"events"`
+	Regime        RegimeConfig        `yaml:"regime"`
+	MarketContext MarketContextConfig `yaml:"marketContext"`
+	Correlation   CorrelationConfig   `yaml:"correlation"`
+	Replay        ReplayConfig        `yaml:"replay"`
+	FillSim       FillSimConfig       `yaml:"fillSim"`
+	OpenInterest  OpenInterestConfig  `yaml:"openInterest"`
+	Positioning   PositioningConfig   `yaml:"positioning"`
+	Retention     RetentionConfig     `yaml:"retention"`
+	Secrets       SecretsConfig       `yaml:"secrets"`
+}
+
+type ServerConfig struct {
+	Port string `yaml:"port" env:"PORT" default:"8080"`
+	// GRPCPort serves the gRPC API (screener.v1.ScreenerService); off when empty
+	GRPCPort string `yaml:"grpcPort" env:"GRPC_PORT"`
+	// RequestTimeout bounds the database and exchange work of one API request
+	RequestTimeout time.Duration `yaml:"requestTimeout" env:"HTTP_REQUEST_TIMEOUT" default:"30s"`
+	// ShutdownTimeout is how long a SIGTERM waits for requests, screening
+	// cycles and trades in flight; Heroku kills the dyno 30s after it
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT" default:"25s"`
+}
+
+type SecurityConfig struct {
+	// EncryptionKey encrypts stored Binance credentials (min 32 chars with Postgres)
+	EncryptionKey string `yaml:"encryptionKey" env:"API_ENCRYPTION_KEY" secret:"true" external:"true"`
+	// AdminToken guards /api/admin/*; the admin API is disabled when empty
+	AdminToken string `yaml:"adminToken" env:"ADMIN_TOKEN" secret:"true"`
+	// JWTSecret signs the tokens issued by /api/auth/*; required unless
+	// AllowAnonymous is set
+	JWTSecret string `yaml:"jwtSecret" env:"JWT_SECRET" secret:"true" external:"true"`
+	// TokenTTL is how long an issued token is valid
+	TokenTTL time.Duration `yaml:"tokenTtl" env:"JWT_TTL" default:"168h"`
+	// AllowAnonymous serves requests naming a userId without a token, acting
+	// as that user; without JWTSecret it turns authentication off. For local
+	// development only.
+	AllowAnonymous bool `yaml:"allowAnonymous" env:"ALLOW_ANONYMOUS"`
+}
+
+type BinanceConfig struct {
+	// BaseURL overrides the USDⓈ-M futures market data host
+	BaseURL string `yaml:"baseUrl" env:"BINANCE_BASE_URL"`
+	// KlineCacheSize is how many symbol/interval kline series the screener
+	// keeps between requests; 0 turns the cache off
+	KlineCacheSize int `yaml:"klineCacheSize" env:"KLINE_CACHE_SIZE" default:"2000"`
+	// KlineCacheLiveTTL is how long the still-forming candle is reused
+	KlineCacheLiveTTL time.Duration `yaml:"klineCacheLiveTtl" env:"KLINE_CACHE_LIVE_TTL" default:"15s"`
+	// Stream keeps the screener's klines and tickers current from the
+	// futures market streams instead of polling REST every cycle
+	Stream bool `yaml:"stream" env:"BINANCE_STREAM" default:"true"`
+	// StreamURL overrides the futures market stream host
+	StreamURL string `yaml:"streamUrl" env:"BINANCE_STREAM_URL"`
+	// Liquidations follows the all-market force order stream for the
+	// liquidation features of the short readiness score
+	Liquidations bool `yaml:"liquidations" env:"BINANCE_LIQUIDATIONS" default:"true"`
+	// WeightBudget is the share of a host's per-minute request weight limit
+	// the market data requests may use; requests past it are queued
+	WeightBudget float64 `yaml:"weightBudget" env:"BINANCE_WEIGHT_BUDGET" default:"0.8"`
+	// Retries is how many times a Binance call is repeated after a transport
+	// error or 5xx; 0 turns retries off
+	Retries int `yaml:"retries" env:"BINANCE_RETRIES" default:"2"`
+	// RetryBackoff is the wait before the first repeat, doubled for each
+	// next one and jittered
+	RetryBackoff time.Duration `yaml:"retryBackoff" env:"BINANCE_RETRY_BACKOFF" default:"250ms"`
+}
+
+type ScreenerConfig struct {
+	ScanInterval    time.Duration `yaml:"scanInterval" env:"SCAN_INTERVAL" default:"1m" reload:"true"`
+	VWAPAnchor      string        `yaml:"vwapAnchor" env:"VWAP_ANCHOR" default:"session" reload:"true"`
+	CCIExtreme      string        `yaml:"cciExtreme" env:"SCORE_CCI_EXTREME" default:"200" reload:"true"`
+	PullbackTrendMA string        `yaml:"pullbackTrendMa" env:"PULLBACK_TREND_MA" default:"ema" reload:"true"`
+	LinRegLookback  int           `yaml:"linregLookback" env:"LINREG_LOOKBACK" default:"50" reload:"true"`
+	// ScoreWeights scales the reversal score components, e.g.
+	// "overextension=1.2,exhaustion=0.8"; empty keeps every weight at 1
+	ScoreWeights string `yaml:"scoreWeights" env:"SCORE_WEIGHTS" reload:"true"`
+	// TriggerScore is the final score a 2-TF confluence needs for TRIGGER
+	TriggerScore float64 `yaml:"triggerScore" env:"SCORE_TRIGGER" default:"40" reload:"true"`
+	// CycleTimeout cancels a screening cycle's outstanding calls once exceeded
+	CycleTimeout time.Duration `yaml:"cycleTimeout" env:"SCAN_CYCLE_TIMEOUT" default:"5m"`
+	// HistoryTopN is how many coins of each cycle, by best score, the score
+	// history keeps; 0 turns it off
+	HistoryTopN int `yaml:"historyTopN" env:"SCREENER_HISTORY_TOP_N" default:"50" reload:"true"`
+	// LiquidationWindow is how far back a symbol's liquidations are totalled
+	LiquidationWindow time.Duration `yaml:"liquidationWindow" env:"LIQUIDATION_WINDOW" default:"15m" reload:"true"`
+	// LiquidationCascadeUSD is the long liquidation value of the last 5
+	// minutes that makes a cascade
+	LiquidationCascadeUSD float64 `yaml:"liquidationCascadeUsd" env:"LIQUIDATION_CASCADE_USD" default:"250000" reload:"true"`
+	// Concurrency is no longer used: requests are paced by
+	// binance.weightBudget. It is kept so existing config files still load.
+	Concurrency int `yaml:"concurrency" env:"SCAN_CONCURRENCY"`
+}
+
+type AutoScalpConfig struct {
+	MonitorInterval      time.Duration `yaml:"monitorInterval" env:"AUTOSCALP_MONITOR_INTERVAL" default:"5s" reload:"true"`
+	TradeMonitorInterval time.Duration `yaml:"tradeMonitorInterval" env:"TRADE_MONITOR_INTERVAL" default:"5s" reload:"true"`
+}
+
+type NotificationConfig struct {
+	Cooldown                time.Duration `yaml:"cooldown" env:"NOTIFICATION_COOLDOWN" default:"5m" reload:"true"`
+	FirebaseCredentialsPath string        `yaml:"firebaseCredentialsPath" env:"FIREBASE_CREDENTIALS_PATH"`
+	FirebaseCredentialsJSON string        `yaml:"firebaseCredentialsJson" env:"FIREBASE_CREDENTIALS_JSON" secret:"true" external:"true"`
+}
+
+type DatabaseConfig struct {
+	// URL falls back to the Heroku Postgres add-on variables when unset
+	URL               string        `yaml:"url" env:"DATABASE_URL" secret:"true"`
+	MaxConns          int32         `yaml:"maxConns" env:"DB_MAX_CONNS" default:"10"`
+	MinConns          int32         `yaml:"minConns" env:"DB_MIN_CONNS" default:"2"`
+	MaxConnLifetime   time.Duration `yaml:"maxConnLifetime" env:"DB_MAX_CONN_LIFETIME" default:"30m"`
+	MaxConnIdleTime   time.Duration `yaml:"maxConnIdleTime" env:"DB_MAX_CONN_IDLE_TIME" default:"5m"`
+	HealthCheckPeriod time.Duration `yaml:"healthCheckPeriod" env:"DB_HEALTH_CHECK_PERIOD" default:"30s"`
+	StatementTimeout  time.Duration `yaml:"statementTimeout" env:"DB_STATEMENT_TIMEOUT" default:"30s"`
+	// The trading pool serves order bookkeeping (autoscalp, credentials,
+	// idempotency) apart from history and analytics reads
+	TradingMaxConns         int32         `yaml:"tradingMaxConns" env:"DB_TRADING_MAX_CONNS" default:"3"`
+	TradingStatementTimeout time.Duration `yaml:"tradingStatementTimeout" env:"DB_TRADING_STATEMENT_TIMEOUT" default:"5s"`
+}
+
+type RedisConfig struct {
+	URL string `yaml:"url" env:"REDIS_URL" secret:"true"`
+}
+
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector URL; tracing is off when empty
+	Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Headers     string  `yaml:"headers" env:"OTEL_EXPORTER_OTLP_HEADERS" secret:"true"`
+	ServiceName string  `yaml:"serviceName" env:"OTEL_SERVICE_NAME" default:"screener-backend"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"OTEL_TRACES_SAMPLE_RATIO" default:"1"`
+}
+
+type ReportingConfig struct {
+	// SentryDSN enables error reporting (panics, failed orders, repeated job
+	// failures); off when empty
+	SentryDSN   string `yaml:"sentryDsn" env:"SENTRY_DSN" secret:"true"`
+	Environment string `yaml:"environment" env:"SENTRY_ENVIRONMENT" default:"production"`
+	Release     string `yaml:"release" env:"SENTRY_RELEASE"`
+}
+
+type LoggingConfig struct {
+	// Level is the minimum level written: debug, info or warn
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"info" reload:"true"`
+	// Symbols gets per-symbol screener lines: a comma-separated list, * for
+	// every symbol (very noisy) or empty for none
+	Symbols string `yaml:"symbols" env:"LOG_SYMBOLS" reload:"true"`
+}
+
+type LeaderConfig struct {
+	// Lock picks where the background-worker lock lives: auto (Redis, else
+	// Postgres, else none), redis, postgres or none (always leader)
+	Lock string        `yaml:"lock" env:"LEADER_LOCK" default:"auto"`
+	TTL  time.Duration `yaml:"ttl" env:"LEADER_LOCK_TTL" default:"15s"`
+}
+
+// EventsConfig picks how screener updates and autoscalp events reach the
+// WebSocket clients of every instance: auto (Redis, else Postgres, else
+// local), redis, postgres or local (this instance only)
+type EventsConfig struct {
+	Bus string `yaml:"bus" env:"EVENTS_BUS" default:"auto"`
+}
+
+// RegimeConfig gates signals against the BTC market regime. Each filter is
+// off, downweight (scores scaled by Downweight) or suppress (signal dropped).
+type RegimeConfig struct {
+	// ShortFilter applies to SHORT reversal signals in a strong BTC uptrend
+	ShortFilter string `yaml:"shortFilter" env:"REGIME_SHORT_FILTER" default:"off" reload:"true"`
+	// LongFilter applies to pullback LONG signals during a BTC breakdown
+	LongFilter string  `yaml:"longFilter" env:"REGIME_LONG_FILTER" default:"off" reload:"true"`
+	Downweight float64 `yaml:"downweight" env:"REGIME_DOWNWEIGHT" default:"0.8" reload:"true"`
+}
+
+// MarketContextConfig is the global market context: BTC dominance and total
+// market cap from CoinGecko, the Fear & Greed index from alternative.me
+type MarketContextConfig struct {
+	Enabled  bool          `yaml:"enabled" env:"MARKET_CONTEXT_ENABLED" default:"true"`
+	Interval time.Duration `yaml:"interval" env:"MARKET_CONTEXT_INTERVAL" default:"10m" reload:"true"`
+	// ScoreModifier scales reversal and pullback scores by up to this
+	// fraction either way with the context; 0 leaves scores alone
+	ScoreModifier float64 `yaml:"scoreModifier" env:"MARKET_CONTEXT_SCORE_MODIFIER" default:"0" reload:"true"`
+	CoinGeckoURL  string  `yaml:"coingeckoUrl" env:"COINGECKO_BASE_URL"`
+	// CoinGeckoAPIKey is an optional demo API key for higher rate limits
+	CoinGeckoAPIKey string `yaml:"coingeckoApiKey" env:"COINGECKO_API_KEY" secret:"true"`
+	FearGreedURL    string `yaml:"fearGreedUrl" env:"FEAR_GREED_BASE_URL"`
+}
+
+// CorrelationConfig is the rolling return correlation matrix of the top
+// screened symbols, recomputed after every screening cycle
+type CorrelationConfig struct {
+	// TopN is how many of the highest scoring symbols are correlated; 0 turns it off
+	TopN      int    `yaml:"topN" env:"CORRELATION_TOP_N" default:"20" reload:"true"`
+	Timeframe string `yaml:"timeframe" env:"CORRELATION_TIMEFRAME" default:"1h" reload:"true"`
+	// Window is how many candle returns each correlation covers
+	Window int `yaml:"window" env:"CORRELATION_WINDOW" default:"48" reload:"true"`
+}
+
+// ReplayConfig records the raw market data screening cycles read, so a cycle
+// can be replayed to explain its scores and statuses
+type ReplayConfig struct {
+	// Symbols are recorded every cycle: a comma-separated list, * for every
+	// symbol (megabytes per cycle) or empty for none
+	Symbols string `yaml:"symbols" env:"REPLAY_SYMBOLS" reload:"true"`
+	// Keep is how many recorded cycles are kept
+	Keep int `yaml:"keep" env:"REPLAY_KEEP" default:"200" reload:"true"`
+}
+
+// FillSimConfig is how the stops and take profits of paper autoscalp
+// positions and journal entries are filled
+type FillSimConfig struct {
+	// Enabled walks the 1m candles traded since the last check; off compares
+	// the latest screener price with the levels only
+	Enabled bool `yaml:"enabled" env:"FILL_SIM_ENABLED" default:"true" reload:"true"`
+	// OrderBook prices stop fills by walking a depth snapshot for the
+	// position's size instead of the flat SlippageBps
+	OrderBook bool `yaml:"orderBook" env:"FILL_SIM_ORDER_BOOK" reload:"true"`
+	// SlippageBps worsens every stop fill, in basis points
+	SlippageBps float64 `yaml:"slippageBps" env:"FILL_SIM_SLIPPAGE_BPS" default:"2" reload:"true"`
+}
+
+// OpenInterestConfig is how the open interest delta of the crowding score
+// components is measured
+type OpenInterestConfig struct {
+	// Enabled fetches the open interest history; off leaves the delta at 0
+	Enabled bool `yaml:"enabled" env:"OI_ENABLED" default:"true" reload:"true"`
+	// Period is the history's resolution
+	Period string `yaml:"period" env:"OI_PERIOD" default:"5m" reload:"true"`
+	// Lookback is how many periods the delta spans (12 x 5m = 1 hour)
+	Lookback int `yaml:"lookback" env:"OI_LOOKBACK" default:"12" reload:"true"`
+}
+
+// PositioningConfig is how the long/short ratios of the crowding score
+// components are read
+type PositioningConfig struct {
+	// Enabled fetches the ratios; off leaves them at 0
+	Enabled bool `yaml:"enabled" env:"POSITIONING_ENABLED" default:"true" reload:"true"`
+	// Period is the ratios' resolution
+	Period string `yaml:"period" env:"POSITIONING_PERIOD" default:"5m" reload:"true"`
+}
+
+// RetentionConfig is how many days of each history to keep; 0 keeps it forever
+type RetentionConfig struct {
+	SnapshotDays        int `yaml:"snapshotDays" env:"RETENTION_SNAPSHOT_DAYS" default:"30" reload:"true"`
+	CandleDays          int `yaml:"candleDays" env:"RETENTION_CANDLE_DAYS" default:"90" reload:"true"`
+	NotificationDays    int `yaml:"notificationDays" env:"RETENTION_NOTIFICATION_DAYS" default:"7" reload:"true"`
+	AutoScalpDays       int `yaml:"autoscalpDays" env:"RETENTION_AUTOSCALP_DAYS" default:"180" reload:"true"`
+	EmergencyStopDays   int `yaml:"emergencyStopDays" env:"RETENTION_EMERGENCY_STOP_DAYS" default:"180" reload:"true"`
+	SignalDays          int `yaml:"signalDays" env:"RETENTION_SIGNAL_DAYS" default:"365" reload:"true"`
+	ScreenerHistoryDays int `yaml:"screenerHistoryDays" env:"RETENTION_SCREENER_HISTORY_DAYS" default:"30" reload:"true"`
+}
+
+// SecretsConfig selects where the settings tagged external (encryption key,
+// Firebase credentials) are read from: env (the environment variables
+// themselves), vault, gcp (Secret Manager) or aws-kms (KMS ciphertext in the
+// environment variables)
+type SecretsConfig struct {
+	Provider   string `yaml:"provider" env:"SECRET_PROVIDER" default:"env"`
+	VaultAddr  string `yaml:"vaultAddr" env:"VAULT_ADDR"`
+	VaultToken string `yaml:"vaultToken" env:"VAULT_TOKEN" secret:"true"`
+	VaultPath  string `yaml:"vaultPath" env:"VAULT_SECRET_PATH" default:"secret/data/screener-backend"`
+	GCPProject string `yaml:"gcpProject" env:"GCP_PROJECT"`
+	AWSRegion  string `yaml:"awsRegion" env:"AWS_REGION"`
+}
+
+// ProviderConfig returns the settings of the secret provider
+func (c SecretsConfig) ProviderConfig() secrets.Config {
+	return secrets.Config{
+		Provider:   c.Provider,
+		VaultAddr:  c.VaultAddr,
+		VaultToken: c.VaultToken,
+		VaultPath:  c.VaultPath,
+		GCPProject: c.GCPProject,
+		AWSRegion:  c.AWSRegion,
+	}
+}
+
+// PoolConfig returns the settings of the main pool (journal, history,
+// analytics, archive)
+func (c DatabaseConfig) PoolConfig() db.PoolConfig {
+	return db.PoolConfig{
+		Name:              "main",
+		MaxConns:          c.MaxConns,
+		MinConns:          c.MinConns,
+		MaxConnLifetime:   c.MaxConnLifetime,
+		MaxConnIdleTime:   c.MaxConnIdleTime,
+		HealthCheckPeriod: c.HealthCheckPeriod,
+		StatementTimeout:  c.StatementTimeout,
+	}
+}
+
+// TradingPoolConfig returns the settings of the small latency-sensitive pool
+func (c DatabaseConfig) TradingPoolConfig() db.PoolConfig {
+	return db.PoolConfig{
+		Name:              "trading",
+		MaxConns:          c.TradingMaxConns,
+		MinConns:          1,
+		MaxConnLifetime:   c.MaxConnLifetime,
+		MaxConnIdleTime:   c.MaxConnIdleTime,
+		HealthCheckPeriod: c.HealthCheckPeriod,
+		StatementTimeout:  c.TradingStatementTimeout,
+	}
+}
+
+// secretsTimeout bounds fetching every external setting at startup
+const secretsTimeout = 30 * time.Second
+
+// field is one leaf setting reached through the struct tags
+type field struct {
+	path   string // yaml path, e.g. "screener.scanInterval"
+	env    string
+	def    string
+	secret bool
+	reload bool // may change at runtime (see Store)
+	// external may be read from the secret provider (see resolveSecrets)
+	external bool
+	value    reflect.Value
+}
+
+// Load builds the configuration from defaults, CONFIG_FILE and the environment, then validates it.
+func Load() (*Config, error) {
+	cfg := &Config{}
+	fields := collectFields(reflect.ValueOf(cfg).Elem(), "")
+
+	for _, f := range fields {
+		if f.def == "" {
+			continue
+		}
+		if err := setField(f, f.def); err != nil {
+			return nil, fmt.Errorf("default for %s: %w", f.path, err)
+		}
+	}
+
+	if path := strings.TrimSpace(os.Getenv("CONFIG_FILE")); path != "" {
+		values, err := readYAMLFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+		byPath := make(map[string]field, len(fields))
+		for _, f := range fields {
+			byPath[f.path] = f
+		}
+		for key, v := range values {
+			f, ok := byPath[key]
+			if !ok {
+				return nil, fmt.Errorf("config file %s: unknown key %q", path, key)
+			}
+			if err := setField(f, v); err != nil {
+				return nil, fmt.Errorf("config file %s: %s: %w", path, key, err)
+			}
+		}
+	}
+
+	for _, f := range fields {
+		v := strings.TrimSpace(os.Getenv(f.env))
+		if f.env == "" || v == "" {
+			continue
+		}
+		if err := setField(f, v); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.env, err)
+		}
+	}
+
+	if cfg.Database.URL == "" {
+		cfg.Database.URL = db.ResolveDatabaseURL()
+	}
+
+	if err := resolveSecrets(cfg, fields); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// resolveSecrets replaces the external settings with the values held by the
+// configured secret provider. Settings the provider doesn't have keep the
+// value from the file or environment.
+func resolveSecrets(cfg *Config, fields []field) error {
+	switch cfg.Secrets.Provider {
+	case "env", "vault", "gcp", "aws-kms":
+	default:
+		return fmt.Errorf("secrets.provider: expected env, vault, gcp or aws-kms, got %q", cfg.Secrets.Provider)
+	}
+	if cfg.Secrets.Provider == "env" {
+		return nil // already read from the environment
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+	provider, err := secrets.New(ctx, cfg.Secrets.ProviderConfig())
+	if err != nil {
+		return fmt.Errorf("secrets: %w", err)
+	}
+	for _, f := range fields {
+		if !f.external {
+			continue
+		}
+		v, err := provider.Get(ctx, f.env)
+		if err != nil {
+			return fmt.Errorf("secrets: %s from %s: %w", f.env, provider, err)
+		}
+		if v != "" {
+			f.value.SetString(v)
+		}
+	}
+	return nil
+}
+
+// Validate checks ranges and enumerations, reporting every problem at once
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port <= 0 || port > 65535 {
+		check(false, "server.port: invalid port %q", c.Server.Port)
+	}
+	if c.Server.GRPCPort != "" {
+		port, err := strconv.Atoi(c.Server.GRPCPort)
+		check(err == nil && port > 0 && port <= 65535 && c.Server.GRPCPort != c.Server.Port, "server.grpcPort: invalid port %q (must differ from server.port)", c.Server.GRPCPort)
+	}
+	check(c.Server.RequestTimeout >= time.Second, "server.requestTimeout: must be at least 1s")
+	check(c.Server.ShutdownTimeout >= time.Second, "server.shutdownTimeout: must be at least 1s")
+	if c.Database.URL != "" {
+		check(len(c.Security.EncryptionKey) >= 32, "security.encryptionKey: must be at least 32 characters when Postgres is enabled")
+	}
+	if c.Security.JWTSecret != "" {
+		check(len(c.Security.JWTSecret) >= 32, "security.jwtSecret: must be at least 32 characters")
+	} else {
+		check(c.Security.AllowAnonymous, "security.jwtSecret: required (set ALLOW_ANONYMOUS=true to run without authentication in local development)")
+	}
+	check(c.Security.TokenTTL >= time.Minute, "security.tokenTtl: must be at least 1m")
+
+	check(c.Binance.KlineCacheSize >= 0 && c.Binance.KlineCacheLiveTTL >= 0, "binance: kline cache size and TTL must not be negative")
+	check(c.Binance.WeightBudget > 0 && c.Binance.WeightBudget <= 1, "binance.weightBudget: must be in (0, 1]")
+	check(c.Binance.Retries >= 0 && c.Binance.Retries <= 5, "binance.retries: must be between 0 and 5")
+	check(c.Binance.RetryBackoff >= 0 && c.Binance.RetryBackoff <= 5*time.Second, "binance.retryBackoff: must be between 0 and 5s")
+
+	errs = append(errs, c.Screener.validate()...)
+	check(c.AutoScalp.MonitorInterval >= time.Second, "autoscalp.monitorInterval: must be at least 1s")
+	check(c.AutoScalp.TradeMonitorInterval >= time.Second, "autoscalp.tradeMonitorInterval: must be at least 1s")
+	check(c.Notifications.Cooldown >= 0, "notifications.cooldown: must not be negative")
+
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		check(false, "logging.level: %v", err)
+	}
+	check(c.Tracing.SampleRatio > 0 && c.Tracing.SampleRatio <= 1, "tracing.sampleRatio: must be in (0, 1]")
+
+	switch c.Leader.Lock {
+	case "auto", "none":
+	case "redis":
+		check(c.Redis.URL != "", "leader.lock: redis requires REDIS_URL")
+	case "postgres":
+		check(c.Database.URL != "", "leader.lock: postgres requires DATABASE_URL")
+	default:
+		check(false, "leader.lock: expected auto, redis, postgres or none, got %q", c.Leader.Lock)
+	}
+	check(c.Leader.TTL >= 3*time.Second, "leader.ttl: must be at least 3s")
+
+	for _, f := range []struct{ name, mode string }{
+		{"regime.shortFilter", c.Regime.ShortFilter},
+		{"regime.longFilter", c.Regime.LongFilter},
+	} {
+		switch f.mode {
+		case "off", "downweight", "suppress":
+		default:
+			check(false, "%s: expected off, downweight or suppress, got %q", f.name, f.mode)
+		}
+	}
+	check(c.Regime.Downweight > 0 && c.Regime.Downweight < 1, "regime.downweight: must be between 0 and 1")
+
+	check(c.MarketContext.Interval >= time.Minute, "marketContext.interval: must be at least 1m")
+	check(c.MarketContext.ScoreModifier >= 0 && c.MarketContext.ScoreModifier <= 0.5, "marketContext.scoreModifier: must be between 0 and 0.5")
+
+	check(c.Correlation.TopN >= 0 && c.Correlation.TopN <= 100, "correlation.topN: must be between 0 and 100")
+	switch c.Correlation.Timeframe {
+	case "5m", "15m", "30m", "1h", "4h":
+	default:
+		check(false, "correlation.timeframe: expected 5m, 15m, 30m, 1h or 4h, got %q", c.Correlation.Timeframe)
+	}
+	check(c.Correlation.Window >= 10 && c.Correlation.Window <= 96, "correlation.window: must be between 10 and 96")
+
+	check(c.Replay.Keep >= 1 && c.Replay.Keep <= 10000, "replay.keep: must be between 1 and 10000")
+	check(c.FillSim.SlippageBps >= 0 && c.FillSim.SlippageBps <= 100, "fillSim.slippageBps: must be between 0 and 100")
+	check(futuresDataPeriod(c.OpenInterest.Period), "openInterest.period: expected 5m, 15m, 30m, 1h, 2h, 4h, 6h, 12h or 1d, got %q", c.OpenInterest.Period)
+	check(c.OpenInterest.Lookback >= 1 && c.OpenInterest.Lookback <= 499, "openInterest.lookback: must be between 1 and 499")
+	check(futuresDataPeriod(c.Positioning.Period), "positioning.period: expected 5m, 15m, 30m, 1h, 2h, 4h, 6h, 12h or 1d, got %q", c.Positioning.Period)
+
+	switch c.Events.Bus {
+	case "auto", "local":
+	case "redis":
+		check(c.Redis.URL != "", "events.bus: redis requires REDIS_URL")
+	case "postgres":
+		check(c.Database.URL != "", "events.bus: postgres requires DATABASE_URL")
+	default:
+		check(false, "events.bus: expected auto, redis, postgres or local, got %q", c.Events.Bus)
+	}
+
+	r := c.Retention
+	check(r.SnapshotDays >= 0 && r.CandleDays >= 0 && r.NotificationDays >= 0 && r.AutoScalpDays >= 0 && r.EmergencyStopDays >= 0 && r.SignalDays >= 0 && r.ScreenerHistoryDays >= 0,
+		"retention: days must not be negative")
+
+	check(c.Database.MaxConns > 0, "database.maxConns: must be positive")
+	check(c.Database.MinConns >= 0 && c.Database.MinConns <= c.Database.MaxConns, "database.minConns: must be between 0 and maxConns")
+	check(c.Database.TradingMaxConns > 0, "database.tradingMaxConns: must be positive")
+	check(c.Database.StatementTimeout >= 0 && c.Database.TradingStatementTimeout >= 0, "database: statement timeouts must not be negative")
+
+	return errors.Join(errs...)
+}
+
+// futuresDataPeriod reports whether p is a period of Binance's
+// /futures/data statistics
+func futuresDataPeriod(p string) bool {
+	switch p {
+	case "5m", "15m", "30m", "1h", "2h", "4h", "6h", "12h", "1d":
+		return true
+	}
+	return false
+}
+
+func (c ScreenerConfig) validate() []error {
+	var errs []error
+	if c.ScanInterval < 10*time.Second {
+		errs = append(errs, errors.New("screener.scanInterval: must be at least 10s"))
+	}
+	switch strings.ToLower(c.VWAPAnchor) {
+	case "session", "week", "swing_low", "swing_high":
+	default:
+		errs = append(errs, fmt.Errorf("screener.vwapAnchor: unknown anchor %q", c.VWAPAnchor))
+	}
+	if v := strings.ToLower(c.CCIExtreme); v != "off" {
+		if level, err := strconv.ParseFloat(v, 64); err != nil || level < 0 {
+			errs = append(errs, fmt.Errorf("screener.cciExtreme: expected a positive level or off, got %q", c.CCIExtreme))
+		}
+	}
+	if _, err := domain.ParseScoreWeights(c.ScoreWeights); err != nil {
+		errs = append(errs, fmt.Errorf("screener.scoreWeights: %w", err))
+	}
+	if c.TriggerScore <= 0 || c.TriggerScore > 100 {
+		errs = append(errs, errors.New("screener.triggerScore: must be above 0 and at most 100"))
+	}
+	switch strings.ToLower(c.PullbackTrendMA) {
+	case "ema", "hma":
+	default:
+		errs = append(errs, fmt.Errorf("screener.pullbackTrendMa: expected ema or hma, got %q", c.PullbackTrendMA))
+	}
+	if c.LinRegLookback < 10 || c.LinRegLookback > 100 {
+		errs = append(errs, errors.New("screener.linregLookback: must be between 10 and 100"))
+	}
+	if c.CycleTimeout < 10*time.Second {
+		errs = append(errs, errors.New("screener.cycleTimeout: must be at least 10s"))
+	}
+	if c.HistoryTopN < 0 || c.HistoryTopN > 500 {
+		errs = append(errs, errors.New("screener.historyTopN: must be between 0 and 500"))
+	}
+	// The liquidation feed keeps an hour; the cascade reads the last 5 minutes
+	if c.LiquidationWindow < 5*time.Minute || c.LiquidationWindow > time.Hour {
+		errs = append(errs, errors.New("screener.liquidationWindow: must be between 5m and 1h"))
+	}
+	if c.LiquidationCascadeUSD <= 0 {
+		errs = append(errs, errors.New("screener.liquidationCascadeUsd: must be above 0"))
+	}
+	return errs
+}
+
+// Redacted returns the configuration grouped by section with secrets masked,
+// for the admin view.
+func (c *Config) Redacted() map[string]map[string]interface{} {
+	out := make(map[string]map[string]interface{})
+	for _, f := range collectFields(reflect.ValueOf(c).Elem(), "") {
+		section, key, _ := strings.Cut(f.path, ".")
+		if out[section] == nil {
+			out[section] = make(map[string]interface{})
+		}
+
+		var v interface{}
+		switch {
+		case f.secret:
+			v = ""
+			if !f.value.IsZero() {
+				v = "********"
+			}
+		case f.value.Type() == reflect.TypeOf(time.Duration(0)):
+			v = time.Duration(f.value.Int()).String()
+		default:
+			v = f.value.Interface()
+		}
+		out[section][key] = v
+	}
+	return out
+}
+
+// ReloadableValues returns every runtime-changeable setting by yaml path, in
+// the form Store.Update accepts
+func (c *Config) ReloadableValues() map[string]string {
+	out := make(map[string]string)
+	for _, f := range collectFields(reflect.ValueOf(c).Elem(), "") {
+		if !f.reload {
+			continue
+		}
+		if f.value.Type() == reflect.TypeOf(time.Duration(0)) {
+			out[f.path] = time.Duration(f.value.Int()).String()
+		} else {
+			out[f.path] = fmt.Sprint(f.value.Interface())
+		}
+	}
+	return out
+}
+
+func collectFields(v reflect.Value, prefix string) []field {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		path := sf.Tag.Get("yaml")
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+			fields = append(fields, collectFields(v.Field(i), path)...)
+			continue
+		}
+		fields = append(fields, field{
+			path:     path,
+			env:      sf.Tag.Get("env"),
+			def:      sf.Tag.Get("default"),
+			secret:   sf.Tag.Get("secret") == "true",
+			reload:   sf.Tag.Get("reload") == "true",
+			external: sf.Tag.Get("external") == "true",
+			value:    v.Field(i),
+		})
+	}
+	return fields
+}
+
+func setField(f field, raw string) error {
+	v := f.value
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
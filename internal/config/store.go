@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Store holds the live configuration. Settings tagged reload:"true" can be
+// changed at runtime; every change is validated as a whole and published as
+// a new *Config, so readers never see a partial update.
+type Store struct {
+	current   atomic.Pointer[Config]
+	mu        sync.Mutex // serializes writers and listener registration
+	listeners []func(*Config)
+}
+
+// NewStore wraps the configuration loaded at startup
+func NewStore(cfg *Config) *Store {
+	s := &Store{}
+	s.current.Store(cfg)
+	return s
+}
+
+// Current returns the live configuration; treat it as read-only
+func (s *Store) Current() *Config {
+	return s.current.Load()
+}
+
+// OnChange registers fn to run after every applied change
+func (s *Store) OnChange(fn func(*Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reloadable lists the yaml paths that may change at runtime
+func (s *Store) Reloadable() []string {
+	var paths []string
+	for _, f := range collectFields(reflect.ValueOf(s.Current()).Elem(), "") {
+		if f.reload {
+			paths = append(paths, f.path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Update applies the given path -> value changes (e.g. "screener.scanInterval": "30s").
+// Unknown or non-reloadable paths reject the whole update.
+func (s *Store) Update(values map[string]string) (*Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := *s.Current()
+	byPath := make(map[string]field)
+	for _, f := range collectFields(reflect.ValueOf(&next).Elem(), "") {
+		byPath[f.path] = f
+	}
+	for path, v := range values {
+		f, ok := byPath[path]
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q", path)
+		}
+		if !f.reload {
+			return nil, fmt.Errorf("%s cannot be changed at runtime", path)
+		}
+		if err := setField(f, v); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return s.publish(&next)
+}
+
+// Reload re-reads CONFIG_FILE and the environment and applies the reloadable
+// settings. Other settings that changed are logged and need a restart.
+func (s *Store) Reload() (*Config, error) {
+	fresh, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := *s.Current()
+	freshFields := collectFields(reflect.ValueOf(fresh).Elem(), "")
+	for i, f := range collectFields(reflect.ValueOf(&next).Elem(), "") {
+		newValue := freshFields[i].value
+		if reflect.DeepEqual(f.value.Interface(), newValue.Interface()) {
+			continue
+		}
+		if !f.reload {
+			log.Printf("Config reload: %s changed but needs a restart", f.path)
+			continue
+		}
+		f.value.Set(newValue)
+	}
+	return s.publish(&next)
+}
+
+// publish validates next, swaps it in and notifies listeners; s.mu is held
+func (s *Store) publish(next *Config) (*Config, error) {
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	s.current.Store(next)
+	for _, fn := range s.listeners {
+		fn(next)
+	}
+	return next, nil
+}
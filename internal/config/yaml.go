@@ -0,0 +1,93 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readYAMLFile reads the subset of YAML the config file needs: nested
+// mappings of scalars, indented with spaces, with # comments. Keys are
+// returned as dotted paths ("screener.scanInterval").
+func readYAMLFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseYAML(data)
+}
+
+func parseYAML(data []byte) (map[string]string, error) {
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := stripComment(scanner.Text())
+		if strings.TrimSpace(line) == "" || strings.TrimSpace(line) == "---" {
+			continue
+		}
+		if strings.Contains(line, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNo)
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected key: value", lineNo)
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		parts := make([]string, 0, len(stack)+1)
+		for _, l := range stack {
+			parts = append(parts, l.key)
+		}
+		parts = append(parts, key)
+		path := strings.Join(parts, ".")
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			stack = append(stack, level{indent: indent, key: key})
+			continue
+		}
+		values[path] = unquote(value)
+	}
+	return values, scanner.Err()
+}
+
+// stripComment drops a trailing # comment that is not inside quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' '):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
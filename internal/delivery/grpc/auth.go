@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	screenerv1 "screener-backend/api/screener/v1"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/usecase"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	errMissingToken = domain.Unauthorized("AUTH_MISSING_TOKEN", "a bearer token is required")
+	errUserMismatch = domain.Forbidden("AUTH_USER_MISMATCH", "user_id does not match the token")
+)
+
+// tokenRequired are the methods served only with a valid bearer token, for
+// calls too consequential to serve on a user_id alone
+var tokenRequired = map[string]bool{
+	screenerv1.ScreenerService_PlaceShort_FullMethodName: true,
+}
+
+type userIDKey struct{}
+
+// userScoped is a request naming the user it acts for
+type userScoped interface {
+	GetUserId() string
+}
+
+// callerID returns the user a call acts for: the token's user, or the
+// request's user_id when the call carried no token
+func callerID(ctx context.Context, requested string) string {
+	if userID, ok := ctx.Value(userIDKey{}).(string); ok {
+		return userID
+	}
+	return requested
+}
+
+// authenticator binds calls to the user of their bearer token (the
+// authorization metadata), like the HTTP API's WithAuth. A request's
+// user_id must match the token's user. An invalid token is rejected; a call
+// without one is served unless it is in tokenRequired, or required is set
+// and it names a user_id.
+type authenticator struct {
+	auth     *usecase.AuthService
+	required bool
+}
+
+func (a *authenticator) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := a.authorize(ctx, req); err != nil {
+		return nil, toStatus(err)
+	}
+	return handler(ctx, req)
+}
+
+// stream checks the token when the call starts and the user_id of each
+// request as the handler receives it
+func (a *authenticator) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return toStatus(err)
+	}
+	return handler(srv, &authStream{ServerStream: ss, ctx: ctx, auth: a})
+}
+
+// authenticate returns ctx carrying the user of the call's token, if it has
+// one
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	token := bearerToken(ctx)
+	if token == "" {
+		if tokenRequired[method] {
+			return nil, errMissingToken
+		}
+		return ctx, nil
+	}
+	userID, err := a.auth.ParseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, userIDKey{}, userID), nil
+}
+
+// authorize checks the user_id a request names against the call's token
+func (a *authenticator) authorize(ctx context.Context, req interface{}) error {
+	r, ok := req.(userScoped)
+	if !ok || r.GetUserId() == "" {
+		return nil
+	}
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	switch {
+	case !ok && a.required:
+		return errMissingToken
+	case ok && r.GetUserId() != userID:
+		return errUserMismatch
+	}
+	return nil
+}
+
+// authStream is a stream carrying the caller's user, checking each request
+// it receives
+type authStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	auth *authenticator
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if err := s.auth.authorize(s.ctx, m); err != nil {
+		return toStatus(err)
+	}
+	return nil
+}
+
+func bearerToken(ctx context.Context) string {
+	for _, header := range metadata.ValueFromIncomingContext(ctx, "authorization") {
+		if len(header) > len("Bearer ") && strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+			return strings.TrimSpace(header[len("Bearer "):])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	screenerv1 "screener-backend/api/screener/v1"
+	"screener-backend/internal/domain"
+)
+
+// toCoin converts a screened coin to its protobuf form
+func toCoin(c domain.CoinData) *screenerv1.Coin {
+	coin := &screenerv1.Coin{
+		Symbol:               c.Symbol,
+		Price:                c.Price,
+		PriceChangePercent:   c.PriceChangePercent,
+		FundingRate:          c.FundingRate,
+		BasisSpread:          c.BasisSpread,
+		Score:                c.Score,
+		Status:               c.Status,
+		TriggerTf:            c.TriggerTF,
+		ConfluenceCount:      int32(c.ConfluenceCount),
+		TfScores:             toTimeframeScores(c.TFScores),
+		RegimeFilter:         c.RegimeFilter,
+		IntradayScore:        c.IntradayScore,
+		IntradayStatus:       c.IntradayStatus,
+		PullbackScore:        c.PullbackScore,
+		PullbackStatus:       c.PullbackStatus,
+		BreakoutScore:        c.BreakoutScore,
+		BreakoutStatus:       c.BreakoutStatus,
+		BreakoutDirection:    c.BreakoutDirection,
+		FollowTrendScore:     c.FollowTrendScore,
+		FollowTrendStatus:    c.FollowTrendStatus,
+		FollowTrendDirection: c.FollowTrendDirection,
+	}
+	if f := c.Features; f != nil {
+		coin.Features = &screenerv1.Features{
+			Rsi:               f.RSI,
+			Cci:               f.CCI,
+			ZScore:            f.ZScore,
+			OverExtEma:        f.OverExtEma,
+			OverExtVwap:       f.OverExtVwap,
+			OpenInterestDelta: f.OpenInterestDelta,
+			MacdHistogram:     f.MacdHistogram,
+			AtrPercentile:     f.AtrPercentile,
+			VolatilityRegime:  f.VolatilityRegime,
+			Choppiness:        f.Choppiness,
+			TakerDeltaRatio:   f.TakerDeltaRatio,
+			IsSqueeze:         f.IsSqueeze,
+			IsLosingMomentum:  f.IsLosingMomentum,
+			StructureTrend:    f.StructureTrend,
+		}
+	}
+	return coin
+}
+
+func toTimeframeScores(scores []domain.TimeframeScore) []*screenerv1.TimeframeScore {
+	out := make([]*screenerv1.TimeframeScore, 0, len(scores))
+	for _, s := range scores {
+		out = append(out, &screenerv1.TimeframeScore{Tf: s.TF, Score: s.Score, Rsi: s.RSI})
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"screener-backend/internal/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorKinds maps domain error kinds to gRPC codes, like the HTTP API's
+// status mapping
+var errorKinds = []struct {
+	kind error
+	code codes.Code
+}{
+	{domain.ErrNotFound, codes.NotFound},
+	{domain.ErrValidation, codes.InvalidArgument},
+	{domain.ErrConflict, codes.AlreadyExists},
+	{domain.ErrUnauthorized, codes.Unauthenticated},
+	{domain.ErrForbidden, codes.PermissionDenied},
+	{domain.ErrRiskLimit, codes.FailedPrecondition},
+	{domain.ErrExchangeRejected, codes.FailedPrecondition},
+}
+
+// toStatus converts err to a gRPC status. Classified errors keep their
+// message, prefixed with their code (e.g. "REAL_TRADING_DISABLED: real
+// trading is disabled"); unclassified ones are logged and reported as
+// UNAVAILABLE, since they are mostly Binance failures.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	msg := err.Error()
+	if code := domain.ErrorCode(err); code != "" {
+		msg = code + ": " + msg
+	}
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			return status.Error(k.code, msg)
+		}
+	}
+	log.Printf("gRPC UNAVAILABLE: %v", err)
+	return status.Error(codes.Unavailable, msg)
+}
@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"screener-backend/internal/infrastructure/reporting"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recoverUnary turns a handler panic into an INTERNAL status and reports it
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = reportPanic(ctx, info.FullMethod, rec)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// recoverStream is recoverUnary for streaming calls
+func recoverStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = reportPanic(ss.Context(), info.FullMethod, rec)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func reportPanic(ctx context.Context, method string, rec interface{}) error {
+	stack := debug.Stack()
+	log.Printf("Panic serving gRPC %s: %v\n%s", method, rec, stack)
+	reporting.Capture(ctx, reporting.Event{
+		Level:   reporting.LevelFatal,
+		Message: "panic in gRPC handler",
+		Err:     fmt.Errorf("panic: %v", rec),
+		Tags:    map[string]string{"method": method},
+		Stack:   stack,
+	})
+	return status.Error(codes.Internal, "internal server error")
+}
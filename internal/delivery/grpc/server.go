@@ -0,0 +1,303 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+
+	screenerv1 "screener-backend/api/screener/v1"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/usecase"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// streamBuffer is how many cycles a stream may fall behind before it is
+// ended with RESOURCE_EXHAUSTED; the client reconnects and gets a fresh
+// snapshot
+const streamBuffer = 4
+
+// Watchlists looks up the symbols on a user's watchlist
+type Watchlists interface {
+	WatchedSymbols(ctx context.Context, userID string) map[string]bool
+}
+
+// Profiles weights coins' reversal scores by a user's scoring profile
+type Profiles interface {
+	Rescore(ctx context.Context, userID string, coins []domain.CoinData) ([]domain.CoinData, error)
+}
+
+// Server implements screener.v1.ScreenerService. Cycles reach it through the
+// event bus like the WebSocket handler's, so streams on every instance see
+// every cycle.
+type Server struct {
+	screenerv1.UnimplementedScreenerServiceServer
+
+	repo       domain.ScreenerRepository
+	watchlists Watchlists
+	profiles   Profiles
+	trading    *usecase.BinanceTradingService
+
+	mu      sync.Mutex
+	streams map[*coinStream]struct{}
+
+	closing   chan struct{} // closed by Close
+	closeOnce sync.Once
+}
+
+// coinStream is one StreamCoins call
+type coinStream struct {
+	req     *screenerv1.StreamCoinsRequest
+	updates chan *cycleCoins
+	dropped chan struct{} // closed when the stream fell too far behind
+}
+
+// cycleCoins is a cycle's coins, shared by all streams
+type cycleCoins struct {
+	asOf  *timestamppb.Timestamp
+	coins []domain.CoinData
+}
+
+// NewServer creates a new gRPC screener service
+func NewServer(repo domain.ScreenerRepository, watchlists Watchlists, profiles Profiles, trading *usecase.BinanceTradingService) *Server {
+	return &Server{
+		repo:       repo,
+		watchlists: watchlists,
+		profiles:   profiles,
+		trading:    trading,
+		streams:    make(map[*coinStream]struct{}),
+		closing:    make(chan struct{}),
+	}
+}
+
+// Close ends every StreamCoins call on shutdown, so the gRPC server's
+// graceful stop only waits for the unary calls in flight
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closing) })
+}
+
+// NewGRPCServer wraps srv in a gRPC server with panic recovery, bearer
+// token checks and server reflection, so tools like grpcurl work without the
+// proto file. required rejects calls naming a user_id without a token; auth
+// nil (no JWT secret) turns the checks off.
+func NewGRPCServer(srv *Server, auth *usecase.AuthService, required bool) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{recoverUnary}
+	stream := []grpc.StreamServerInterceptor{recoverStream}
+	if auth != nil {
+		a := &authenticator{auth: auth, required: required}
+		unary = append(unary, a.unary)
+		stream = append(stream, a.stream)
+	}
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	screenerv1.RegisterScreenerServiceServer(s, srv)
+	reflection.Register(s)
+	return s
+}
+
+// ListCoins returns the latest cycle's coins that pass the request's filters,
+// scored by the caller's scoring profile
+func (s *Server) ListCoins(ctx context.Context, req *screenerv1.ListCoinsRequest) (*screenerv1.ListCoinsResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	userID := callerID(ctx, req.GetUserId())
+	symbols := symbolSet(req.GetSymbols())
+	var coins []*screenerv1.Coin
+	for _, coin := range s.rescore(ctx, userID, s.repo.GetCoins()) {
+		if req.GetStatus() != "" && coin.Status != req.GetStatus() {
+			continue
+		}
+		if keep(coin, symbols, req.GetMinScore()) {
+			coins = append(coins, toCoin(coin))
+		}
+	}
+	coins = s.watchedFirst(ctx, userID, coins)
+	if limit := int(req.GetLimit()); limit > 0 && len(coins) > limit {
+		coins = coins[:limit]
+	}
+	return &screenerv1.ListCoinsResponse{Coins: coins}, nil
+}
+
+// GetCoin returns one coin of the latest cycle
+func (s *Server) GetCoin(_ context.Context, req *screenerv1.GetCoinRequest) (*screenerv1.Coin, error) {
+	symbol := strings.ToUpper(req.GetSymbol())
+	if symbol == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol is required")
+	}
+	for _, coin := range s.repo.GetCoins() {
+		if coin.Symbol == symbol {
+			return toCoin(coin), nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "%s is not in the latest cycle", symbol)
+}
+
+// StreamCoins sends the latest coins, then every cycle's, until the client
+// cancels or falls behind. Scores are weighted by the caller's scoring
+// profile.
+func (s *Server) StreamCoins(req *screenerv1.StreamCoinsRequest, stream screenerv1.ScreenerService_StreamCoinsServer) error {
+	ctx := stream.Context()
+	c := &coinStream{req: req, updates: make(chan *cycleCoins, streamBuffer), dropped: make(chan struct{})}
+
+	s.mu.Lock()
+	s.streams[c] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.streams, c)
+		s.mu.Unlock()
+	}()
+
+	if err := stream.Send(s.filterUpdate(ctx, req, &cycleCoins{asOf: timestamppb.Now(), coins: s.repo.GetCoins()})); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.dropped:
+			return status.Error(codes.ResourceExhausted, "stream fell behind; reconnect for a fresh snapshot")
+		case <-s.closing:
+			return status.Error(codes.Unavailable, "server shutting down; reconnect")
+		case update := <-c.updates:
+			if err := stream.Send(s.filterUpdate(ctx, req, update)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// PlaceShort opens a SHORT with a stop loss through the Binance account of
+// the token's user, with the same checks as the auto scalper's real trades.
+// The position is the caller's own and is not linked to an auto scalp entry.
+func (s *Server) PlaceShort(ctx context.Context, req *screenerv1.PlaceShortRequest) (*screenerv1.PlaceShortResponse, error) {
+	userID := callerID(ctx, req.GetUserId())
+	switch {
+	case userID == "":
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	case req.GetSymbol() == "":
+		return nil, status.Error(codes.InvalidArgument, "symbol is required")
+	case req.GetEntryPrice() <= 0:
+		return nil, status.Error(codes.InvalidArgument, "entry_price must be positive")
+	case req.GetStopLoss() <= req.GetEntryPrice():
+		return nil, status.Error(codes.InvalidArgument, "stop_loss must be above entry_price for a SHORT")
+	case req.GetAmountUsdt() < 0:
+		return nil, status.Error(codes.InvalidArgument, "amount_usdt must not be negative")
+	case req.GetLeverage() < 1 || req.GetLeverage() > 20:
+		return nil, status.Error(codes.InvalidArgument, "leverage must be between 1 and 20")
+	}
+
+	entryID, slID, qty, err := s.trading.PlaceShortWithStopLoss(ctx, userID, strings.ToUpper(req.GetSymbol()), req.GetEntryPrice(), req.GetStopLoss(), req.GetAmountUsdt(), int(req.GetLeverage()))
+	if err != nil {
+		if entryID != 0 {
+			// The position is open without its stop: report both
+			return nil, status.Errorf(codes.Internal, "entry order %d filled but the stop loss failed: %v", entryID, err)
+		}
+		return nil, toStatus(err)
+	}
+	return &screenerv1.PlaceShortResponse{EntryOrderId: entryID, StopLossOrderId: slID, Quantity: qty}, nil
+}
+
+// Dispatch hands the coins of a finished cycle to every open stream; other
+// topics are ignored
+func (s *Server) Dispatch(topic string, payload []byte) {
+	if topic != domain.TopicCoins {
+		return
+	}
+	update := &cycleCoins{asOf: timestamppb.Now()}
+	if err := json.Unmarshal(payload, &update.coins); err != nil {
+		log.Printf("gRPC: decoding coins event: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.streams {
+		select {
+		case c.updates <- update:
+		default:
+			log.Println("gRPC stream too slow; ending it")
+			close(c.dropped)
+			delete(s.streams, c)
+		}
+	}
+}
+
+// filterUpdate is a stream's update from a cycle shared by all streams:
+// scored for the caller, with the stream's filters applied
+func (s *Server) filterUpdate(ctx context.Context, req *screenerv1.StreamCoinsRequest, update *cycleCoins) *screenerv1.CoinsUpdate {
+	userID := callerID(ctx, req.GetUserId())
+	symbols := symbolSet(req.GetSymbols())
+	coins := make([]*screenerv1.Coin, 0, len(update.coins))
+	for _, coin := range s.rescore(ctx, userID, update.coins) {
+		if keep(coin, symbols, req.GetMinScore()) {
+			coins = append(coins, toCoin(coin))
+		}
+	}
+	return &screenerv1.CoinsUpdate{AsOf: update.asOf, Coins: s.watchedFirst(ctx, userID, coins)}
+}
+
+// rescore weights the coins' reversal scores by the user's scoring profile;
+// the coins as they are when that fails
+func (s *Server) rescore(ctx context.Context, userID string, coins []domain.CoinData) []domain.CoinData {
+	if userID == "" || s.profiles == nil {
+		return coins
+	}
+	rescored, err := s.profiles.Rescore(ctx, userID, coins)
+	if err != nil {
+		log.Printf("gRPC: scoring profile of %s not applied: %v", userID, err)
+		return coins
+	}
+	return rescored
+}
+
+// watchedFirst moves the coins on the user's watchlist to the front, keeping
+// the score order within both groups
+func (s *Server) watchedFirst(ctx context.Context, userID string, coins []*screenerv1.Coin) []*screenerv1.Coin {
+	if userID == "" || s.watchlists == nil {
+		return coins
+	}
+	watched := s.watchlists.WatchedSymbols(ctx, userID)
+	if len(watched) == 0 {
+		return coins
+	}
+	ordered := make([]*screenerv1.Coin, 0, len(coins))
+	for _, coin := range coins {
+		if watched[coin.Symbol] {
+			ordered = append(ordered, coin)
+		}
+	}
+	for _, coin := range coins {
+		if !watched[coin.Symbol] {
+			ordered = append(ordered, coin)
+		}
+	}
+	return ordered
+}
+
+// keep applies the symbol and score filters; a nil set is every symbol
+func keep(coin domain.CoinData, symbols map[string]bool, minScore float64) bool {
+	return (symbols == nil || symbols[coin.Symbol]) && coin.Score >= minScore
+}
+
+// symbolSet is nil for no symbols
+func symbolSet(symbols []string) map[string]bool {
+	if len(symbols) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		set[strings.ToUpper(strings.TrimSpace(s))] = true
+	}
+	return set
+}
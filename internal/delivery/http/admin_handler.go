@@ -0,0 +1,694 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+	"screener-backend/internal/infrastructure/scheduler"
+	"screener-backend/internal/usecase"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBackupBody caps an uploaded credential backup
+const maxBackupBody = 10 << 20
+
+// AdminHandler serves operator endpoints, guarded by the X-Admin-Token header
+type AdminHandler struct {
+	store     *config.Store
+	jobs      *scheduler.Scheduler
+	backups   *usecase.CredentialBackupService
+	selftest  *usecase.SelfTestService
+	history   *usecase.ConfigHistoryService
+	scoring   *usecase.ScoringOptimizer
+	blackouts *usecase.BlackoutCalendar
+	replay    *usecase.ReplayService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(store *config.Store, jobs *scheduler.Scheduler, backups *usecase.CredentialBackupService, selftest *usecase.SelfTestService, history *usecase.ConfigHistoryService, scoring *usecase.ScoringOptimizer, blackouts *usecase.BlackoutCalendar, replay *usecase.ReplayService) *AdminHandler {
+	return &AdminHandler{store: store, jobs: jobs, backups: backups, selftest: selftest, history: history, scoring: scoring, blackouts: blackouts, replay: replay}
+}
+
+// GetConfig handles GET /api/admin/config with secrets redacted
+func (h *AdminHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"config":     h.store.Current().Redacted(),
+		"reloadable": h.store.Reloadable(),
+	})
+}
+
+// UpdateConfig handles PATCH /api/admin/config with a body such as
+// {"screener.scanInterval": "30s", "screener.historyTopN": 20}.
+// Only reloadable settings are accepted; the change applies without restart.
+func (h *AdminHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	var body map[string]interface{}
+	if err := dec.Decode(&body); err != nil || len(body) == 0 {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	values := make(map[string]string, len(body))
+	for path, v := range body {
+		values[path] = fmt.Sprint(v)
+	}
+
+	cfg, err := h.history.Update(r.Context(), adminAuthor(r), values)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cfg.Redacted())
+}
+
+// ReloadConfig handles POST /api/admin/config/reload: re-reads CONFIG_FILE and
+// the environment, same as SIGHUP.
+func (h *AdminHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	cfg, err := h.history.Reload(r.Context(), adminAuthor(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cfg.Redacted())
+}
+
+// Logging handles GET and PATCH /api/admin/logging. PATCH takes
+// {"level": "debug", "symbols": "BTCUSDT,ETHUSDT"}; either field may be
+// omitted. Same as patching logging.* through /api/admin/config.
+func (h *AdminHandler) Logging(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	cfg := h.store.Current()
+	if r.Method == http.MethodPatch {
+		var body struct {
+			Level   *string `json:"level"`
+			Symbols *string `json:"symbols"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || (body.Level == nil && body.Symbols == nil) {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		values := make(map[string]string)
+		if body.Level != nil {
+			values["logging.level"] = *body.Level
+		}
+		if body.Symbols != nil {
+			values["logging.symbols"] = *body.Symbols
+		}
+		var err error
+		if cfg, err = h.history.Update(r.Context(), adminAuthor(r), values); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Admin: logging set to level=%s symbols=%q", cfg.Logging.Level, cfg.Logging.Symbols)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"level":   cfg.Logging.Level,
+		"symbols": cfg.Logging.Symbols,
+	})
+}
+
+// GetConfigHistory handles GET /api/admin/config/history?limit=50: runtime
+// settings changes, newest first, each with author and diff
+func (h *AdminHandler) GetConfigHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 500 {
+			http.Error(w, "Invalid limit (1-500)", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	changes, err := h.history.History(r.Context(), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+// RollbackConfig handles POST /api/admin/config/history/{id}/rollback: puts
+// the settings of that change back to their previous values, recorded as a
+// new change
+func (h *AdminHandler) RollbackConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid change id", http.StatusBadRequest)
+		return
+	}
+
+	cfg, change, err := h.history.Rollback(r.Context(), adminAuthor(r), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrValidation) {
+			writeError(w, err)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"change": change,
+		"config": cfg.Redacted(),
+	})
+}
+
+// GetBinanceUsage handles GET /api/admin/binance-usage: call counts, errors and
+// latency per endpoint plus the last used weight reported by each host
+func (h *AdminHandler) GetBinanceUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(binance.Usage())
+}
+
+// GetJobs handles GET /api/admin/jobs: schedule and last-run status of every
+// background job, and whether this instance is the worker leader
+func (h *AdminHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"leader": h.jobs.IsLeader(),
+		"jobs":   h.jobs.Statuses(),
+	})
+}
+
+// RunJob handles POST /api/admin/jobs/{name}/run: starts the job now and
+// returns 202 without waiting for it to finish
+func (h *AdminHandler) RunJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	name := r.PathValue("name")
+	if err := h.jobs.Trigger(name); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"status": "started", "job": name})
+}
+
+// GetSelfTest handles GET /api/admin/selftest: the startup connectivity report
+// (Binance public and signed endpoints, database, FCM)
+func (h *AdminHandler) GetSelfTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	report := h.selftest.Report()
+	if report == nil {
+		http.Error(w, "Self-test has not started yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ExportCredentials handles POST /api/admin/credentials/export with body
+// {"passphrase": "..."}: every user's Binance credentials sealed under the
+// passphrase, as a downloadable JSON file for ImportCredentials on another host
+func (h *AdminHandler) ExportCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var body struct {
+		Passphrase string `json:"passphrase"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	backup, err := h.backups.Export(r.Context(), body.Passphrase)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("credentials_%s.json", backup.ExportedAt.Format("20060102_1504"))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	json.NewEncoder(w).Encode(backup)
+}
+
+// ImportCredentials handles POST /api/admin/credentials/import with body
+// {"passphrase": "...", "backup": {...}, "overwrite": false}. Secrets are
+// re-encrypted with this host's key; existing users are skipped unless overwrite.
+func (h *AdminHandler) ImportCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var body struct {
+		Passphrase string                    `json:"passphrase"`
+		Backup     *usecase.CredentialBackup `json:"backup"`
+		Overwrite  bool                      `json:"overwrite"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBackupBody)).Decode(&body); err != nil || body.Backup == nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.backups.Import(r.Context(), body.Backup, body.Passphrase, body.Overwrite)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	log.Printf("Admin: imported %d Binance credentials (%d skipped)", result.Imported, len(result.Skipped))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// OptimizeScoring handles POST /api/admin/scoring/optimize with body
+// {"from": "...", "to": "...", "horizon": "15m", "folds": 4, "minSignals": 20,
+// "top": 10, "save": false, "note": "..."}; every field is optional and the
+// range defaults to the last 7 days. With save the best configuration is
+// stored as a shadow scoring version.
+func (h *AdminHandler) OptimizeScoring(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var body struct {
+		From       string `json:"from"`
+		To         string `json:"to"`
+		Horizon    string `json:"horizon"`
+		Folds      int    `json:"folds"`
+		MinSignals int    `json:"minSignals"`
+		Top        int    `json:"top"`
+		Save       bool   `json:"save"`
+		Note       string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	req := usecase.OptimizeRequest{
+		To:         time.Now(),
+		Horizon:    body.Horizon,
+		Folds:      body.Folds,
+		MinSignals: body.MinSignals,
+		Top:        body.Top,
+		Save:       body.Save,
+		Author:     adminAuthor(r),
+		Note:       body.Note,
+	}
+	req.From = req.To.Add(-7 * 24 * time.Hour)
+	var err error
+	if body.From != "" {
+		if req.From, err = parseFilterTime(body.From, false); err != nil {
+			http.Error(w, "Invalid from", http.StatusBadRequest)
+			return
+		}
+	}
+	if body.To != "" {
+		if req.To, err = parseFilterTime(body.To, true); err != nil {
+			http.Error(w, "Invalid to", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.To.Before(req.From) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.scoring.Optimize(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetScoringVersions handles GET /api/admin/scoring/versions?limit=50, newest first
+func (h *AdminHandler) GetScoringVersions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	versions, err := h.scoring.Versions(r.Context(), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
+// EvaluateScoringVersion handles GET /api/admin/scoring/versions/{id}/evaluation
+// ?from=&to=: the version's TRIGGER signals next to the live configuration's
+// on the snapshots archived since the version was saved
+func (h *AdminHandler) EvaluateScoringVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid version id", http.StatusBadRequest)
+		return
+	}
+	var from, to time.Time
+	q := r.URL.Query()
+	if v := q.Get("from"); v != "" {
+		if from, err = parseFilterTime(v, false); err != nil {
+			http.Error(w, "Invalid from", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if to, err = parseFilterTime(v, true); err != nil {
+			http.Error(w, "Invalid to", http.StatusBadRequest)
+			return
+		}
+	}
+
+	evaluation, err := h.scoring.EvaluateVersion(r.Context(), id, from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(evaluation)
+}
+
+// GetBlackouts handles GET /api/admin/blackouts?since=: the blackout windows
+// not over yet, or all those ending after since
+func (h *AdminHandler) GetBlackouts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		if since, err = parseFilterTime(v, false); err != nil {
+			http.Error(w, "Invalid since", http.StatusBadRequest)
+			return
+		}
+	}
+
+	windows, err := h.blackouts.List(r.Context(), since)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"active":  h.blackouts.Active(r.Context()),
+		"windows": windows,
+	})
+}
+
+// CreateBlackout handles POST /api/admin/blackouts with body
+// {"kind": "FOMC", "name": "FOMC rate decision", "start": "...", "end": "..."};
+// start and end are RFC3339 and kind defaults to OTHER
+func (h *AdminHandler) CreateBlackout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var body struct {
+		Kind  string    `json:"kind"`
+		Name  string    `json:"name"`
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	window := &domain.BlackoutWindow{Kind: body.Kind, Name: body.Name, Start: body.Start, End: body.End, CreatedBy: adminAuthor(r)}
+	if err := h.blackouts.Add(r.Context(), window); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(window)
+}
+
+// DeleteBlackout handles DELETE /api/admin/blackouts/{id}
+func (h *AdminHandler) DeleteBlackout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid blackout id", http.StatusBadRequest)
+		return
+	}
+	if err := h.blackouts.Remove(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"deleted"}`))
+}
+
+// GetCycleRecordings handles GET /api/admin/replay/recordings?symbol=&from=&to=&limit=50:
+// the recorded cycles, newest first. The range defaults to the last 24 hours.
+func (h *AdminHandler) GetCycleRecordings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	from, to, ok := archiveRange(w, r)
+	if !ok {
+		return
+	}
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	recordings, err := h.replay.Recordings(r.Context(), r.URL.Query().Get("symbol"), from, to, limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recordings)
+}
+
+// ReplayCycle handles POST /api/admin/replay with body {"id": 12} or
+// {"symbol": "XYZUSDT", "at": "2024-05-20T14:03:00Z"}, plus optional
+// "currentSettings": true. The recorded cycle is run again with a fixed clock
+// and the coins it produced are compared with the replay.
+func (h *AdminHandler) ReplayCycle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var body struct {
+		ID              int64  `json:"id"`
+		Symbol          string `json:"symbol"`
+		At              string `json:"at"`
+		CurrentSettings bool   `json:"currentSettings"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req := usecase.ReplayRequest{ID: body.ID, Symbol: body.Symbol, CurrentSettings: body.CurrentSettings}
+	if body.At != "" {
+		var err error
+		if req.At, err = parseFilterTime(body.At, true); err != nil {
+			http.Error(w, "Invalid at", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.replay.Replay(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// adminAuthor names who made an admin change: the optional X-Admin-User
+// header, else "admin" (the token is shared)
+func adminAuthor(r *http.Request) string {
+	if user := strings.TrimSpace(r.Header.Get("X-Admin-User")); user != "" {
+		return user
+	}
+	return "admin"
+}
+
+// authorize checks X-Admin-Token against security.adminToken; the admin API
+// is off when no token is configured.
+func (h *AdminHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	token := h.store.Current().Security.AdminToken
+	if token == "" {
+		http.Error(w, "Admin API disabled (set ADMIN_TOKEN)", http.StatusForbidden)
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(token)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
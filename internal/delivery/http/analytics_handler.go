@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/usecase"
+	"time"
+)
+
+// defaultStrategyWindow is the range of the strategy dashboard without from
+const defaultStrategyWindow = 30 * 24 * time.Hour
+
+// AnalyticsHandler serves cross-strategy performance reports and cross-symbol
+// correlations
+type AnalyticsHandler struct {
+	strategies   *usecase.StrategyAnalyticsService
+	correlations *usecase.CorrelationTracker
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(strategies *usecase.StrategyAnalyticsService, correlations *usecase.CorrelationTracker) *AnalyticsHandler {
+	return &AnalyticsHandler{strategies: strategies, correlations: correlations}
+}
+
+// GetStrategies handles GET /api/analytics/strategies?userId=&from=&to=&horizon=15m:
+// hit rate, average return and expectancy per strategy and timeframe, from
+// screener signals, auto scalp positions and (with userId) journal trades.
+// The range defaults to the last 30 days.
+func (h *AnalyticsHandler) GetStrategies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	from, to, ok := archiveRange(w, r)
+	if !ok {
+		return
+	}
+	if q.Get("from") == "" {
+		from = to.Add(-defaultStrategyWindow)
+	}
+	horizon := q.Get("horizon")
+	if horizon == "" {
+		horizon = "15m"
+	}
+
+	dashboard, err := h.strategies.Dashboard(r.Context(), q.Get("userId"), from, to, horizon)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dashboard)
+}
+
+// GetCorrelations handles GET /api/analytics/correlations: the rolling return
+// correlations among the top screened symbols of the last cycle
+func (h *AnalyticsHandler) GetCorrelations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	matrix := h.correlations.Current()
+	if matrix == nil {
+		http.Error(w, "Correlations not computed yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matrix)
+}
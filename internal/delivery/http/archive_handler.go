@@ -0,0 +1,170 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSnapshotLimit = 200
+	maxSnapshotLimit     = 2000
+	defaultHistoryLimit  = 1000
+	maxHistoryLimit      = 5000
+)
+
+// ArchiveHandler serves archived klines and screener snapshots
+type ArchiveHandler struct {
+	archive domain.MarketArchiveRepository
+}
+
+// NewArchiveHandler creates a new archive handler
+func NewArchiveHandler(archive domain.MarketArchiveRepository) *ArchiveHandler {
+	return &ArchiveHandler{archive: archive}
+}
+
+// GetKlines handles GET /api/archive/klines?symbol=BTCUSDT&interval=1m&from=...&to=...
+// The range defaults to the last 24 hours.
+func (h *ArchiveHandler) GetKlines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	symbol := strings.ToUpper(q.Get("symbol"))
+	interval := q.Get("interval")
+	if symbol == "" || interval == "" {
+		http.Error(w, "symbol and interval are required", http.StatusBadRequest)
+		return
+	}
+	from, to, ok := archiveRange(w, r)
+	if !ok {
+		return
+	}
+
+	candles, err := h.archive.GetCandles(r.Context(), symbol, interval, from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if candles == nil {
+		candles = []domain.Candle{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(candles)
+}
+
+// GetSnapshots handles GET /api/archive/snapshots?symbol=BTCUSDT&from=...&to=...&limit=200
+// Returns the newest snapshots first; the range defaults to the last 24 hours.
+func (h *ArchiveHandler) GetSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	symbol := strings.ToUpper(r.URL.Query().Get("symbol"))
+	if symbol == "" {
+		http.Error(w, "symbol is required", http.StatusBadRequest)
+		return
+	}
+	from, to, ok := archiveRange(w, r)
+	if !ok {
+		return
+	}
+	limit := defaultSnapshotLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxSnapshotLimit {
+			http.Error(w, "Invalid limit (1-2000)", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	snapshots, err := h.archive.GetSnapshots(r.Context(), symbol, from, to, limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if snapshots == nil {
+		snapshots = []domain.CoinSnapshot{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+// GetScreenerHistory handles GET /api/screener/history?symbol=BTCUSDT&from=...&to=...&limit=1000
+// Returns the symbol's scores at every cycle it was among the top coins,
+// oldest first; the newest limit when the range has more. The range defaults
+// to the last 24 hours.
+func (h *ArchiveHandler) GetScreenerHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	symbol := strings.ToUpper(r.URL.Query().Get("symbol"))
+	if symbol == "" {
+		http.Error(w, "symbol is required", http.StatusBadRequest)
+		return
+	}
+	from, to, ok := archiveRange(w, r)
+	if !ok {
+		return
+	}
+	limit := defaultHistoryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxHistoryLimit {
+			http.Error(w, "Invalid limit (1-5000)", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	history, err := h.archive.GetScreenerHistory(r.Context(), symbol, from, to, limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if history == nil {
+		history = []domain.ScreenerSnapshot{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// archiveRange reads from/to (RFC3339 or date), writing a 400 on bad input
+func archiveRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	q := r.URL.Query()
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	if v := q.Get("from"); v != "" {
+		t, err := parseFilterTime(v, false)
+		if err != nil {
+			http.Error(w, "Invalid from", http.StatusBadRequest)
+			return from, to, false
+		}
+		from = t
+	}
+	if v := q.Get("to"); v != "" {
+		t, err := parseFilterTime(v, true)
+		if err != nil {
+			http.Error(w, "Invalid to", http.StatusBadRequest)
+			return from, to, false
+		}
+		to = t
+	}
+	if to.Before(from) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return from, to, false
+	}
+	return from, to, true
+}
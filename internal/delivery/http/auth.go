@@ -0,0 +1,173 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/usecase"
+)
+
+var (
+	errMissingToken = domain.Unauthorized("AUTH_MISSING_TOKEN", "a bearer token is required")
+	errUserMismatch = domain.Forbidden("AUTH_USER_MISMATCH", "userId does not match the token")
+)
+
+// authExempt are the path prefixes WithAuth leaves alone: logging in, and
+// the admin API, which has its own token
+var authExempt = []string{"/api/auth/", "/api/admin/"}
+
+type userIDKey struct{}
+
+// AuthenticatedUser returns the ID of the user whose token the request
+// carried, "" when it carried none
+func AuthenticatedUser(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey{}).(string)
+	return userID
+}
+
+// WithAuth binds each request to the user of its bearer token (the
+// Authorization header, or the access_token query parameter for WebSocket
+// clients that cannot set headers). The userId query parameter and the
+// top-level userId of a JSON object body are filled in with the token's user
+// when missing and must match it otherwise, so handlers keyed by userId only
+// ever see the caller's own. Body keys are matched case-insensitively, as
+// encoding/json decodes them. An invalid token is rejected; a request without
+// one is served as before unless required is set and it names a userId.
+// auth nil (no JWT secret) turns the check off.
+func WithAuth(next http.Handler, auth *usecase.AuthService, required bool) http.Handler {
+	if auth == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range authExempt {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		token := bearerToken(r)
+		if token == "" && !required {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := readBody(r)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		query := r.URL.Query()
+		bodyUserIDs, bodyFields := jsonUserIDs(body)
+
+		if token == "" {
+			if query.Get("userId") != "" || len(bodyUserIDs) > 0 {
+				writeError(w, errMissingToken)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, err := auth.ParseToken(token)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if query.Get("userId") != "" && query.Get("userId") != userID {
+			writeError(w, errUserMismatch)
+			return
+		}
+		for _, id := range bodyUserIDs {
+			if id != userID {
+				writeError(w, errUserMismatch)
+				return
+			}
+		}
+
+		query.Set("userId", userID)
+		query.Del("access_token")
+		r.URL.RawQuery = query.Encode()
+		if bodyFields != nil {
+			// One exact userId, so the handler can't decode another
+			for key := range bodyFields {
+				if strings.EqualFold(key, "userId") {
+					delete(bodyFields, key)
+				}
+			}
+			bodyFields["userId"], _ = json.Marshal(userID)
+			body, _ = json.Marshal(bodyFields)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey{}, userID)))
+	})
+}
+
+// RequireToken serves next only to requests that carried a valid bearer
+// token, for endpoints too consequential to serve on a userId alone. With
+// auth off (no JWT secret) it serves every request, as WithAuth does.
+func RequireToken(next http.HandlerFunc, auth *usecase.AuthService) http.HandlerFunc {
+	if auth == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if AuthenticatedUser(r.Context()) == "" {
+			writeError(w, errMissingToken)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); len(header) > len("Bearer ") && strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(header[len("Bearer "):])
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+// readBody reads the body of a request that has one, leaving a copy in its
+// place for the handler
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// jsonUserIDs returns the user IDs a JSON object body names, one for every
+// key encoding/json would decode into a userId field (any case), along with
+// its fields; fields is nil when the body is not a JSON object
+func jsonUserIDs(body []byte) (userIDs []string, fields map[string]json.RawMessage) {
+	if len(bytes.TrimSpace(body)) == 0 || json.Unmarshal(body, &fields) != nil {
+		return nil, nil
+	}
+	for key, raw := range fields {
+		var userID string
+		if strings.EqualFold(key, "userId") && json.Unmarshal(raw, &userID) == nil && userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, fields
+}
+
+// jsonUserID returns the userId a handler decodes from a JSON body
+func jsonUserID(body []byte) string {
+	var v struct {
+		UserID string `json:"userId"`
+	}
+	json.Unmarshal(body, &v)
+	return v.UserID
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/usecase"
+)
+
+// AuthHandler registers and logs in users
+type AuthHandler struct {
+	auth *usecase.AuthService
+}
+
+// NewAuthHandler creates a new auth handler; auth is nil when user
+// authentication is disabled
+func NewAuthHandler(auth *usecase.AuthService) *AuthHandler {
+	return &AuthHandler{auth: auth}
+}
+
+type credentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Register handles POST /api/auth/register with body {"email": "...",
+// "password": "..."} and returns {"token", "expiresAt", "user"}
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	h.issue(w, r, h.auth.Register)
+}
+
+// Login handles POST /api/auth/login with body {"email": "...", "password":
+// "..."} and returns {"token", "expiresAt", "user"}
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	h.issue(w, r, h.auth.Login)
+}
+
+func (h *AuthHandler) issue(w http.ResponseWriter, r *http.Request, issue func(ctx context.Context, email, password string) (*usecase.AuthToken, error)) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.auth == nil {
+		http.Error(w, "Authentication disabled (set JWT_SECRET)", http.StatusForbidden)
+		return
+	}
+
+	var req credentialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := issue(r.Context(), req.Email, req.Password)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(token)
+}
@@ -18,14 +18,24 @@ func NewAutoScalpHandler(service *usecase.AutoScalpingService) *AutoScalpHandler
 	return &AutoScalpHandler{service: service}
 }
 
-// GetSettings handles GET /api/autoscalp/settings
+// GetSettings handles GET /api/autoscalp/settings?userId=xxx
 func (h *AutoScalpHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	settings := h.service.GetSettings()
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.service.GetSettings(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(settings)
 }
@@ -42,51 +52,58 @@ func (h *AutoScalpHandler) UpdateSettings(w http.ResponseWriter, r *http.Request
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if settings.UserID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.UpdateSettings(r.Context(), &settings); err != nil {
+		writeError(w, err)
+		return
+	}
 
-	h.service.UpdateSettings(&settings)
-	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
 		"message": "Settings updated successfully",
 	})
 }
 
-// GetActivePositions handles GET /api/autoscalp/active
+// GetActivePositions handles GET /api/autoscalp/active?userId=xxx
 func (h *AutoScalpHandler) GetActivePositions(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	activePositions := h.service.GetActivePositions()
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	activePositions := h.service.GetActivePositions(r.Context(), userID)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(activePositions)
 }
 
-// GetHistory handles GET /api/autoscalp/history?period=1d|7d|30d
+// GetHistory handles GET /api/autoscalp/history?userId=xxx&period=1d|7d|30d
 func (h *AutoScalpHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	period := r.URL.Query().Get("period")
-	var fromTime time.Time
-	
-	switch period {
-	case "1d":
-		fromTime = time.Now().Add(-24 * time.Hour)
-	case "7d":
-		fromTime = time.Now().Add(-7 * 24 * time.Hour)
-	case "30d":
-		fromTime = time.Now().Add(-30 * 24 * time.Hour)
-	default:
-		fromTime = time.Now().Add(-24 * time.Hour) // Default 1 day
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
 	}
 
+	fromTime := autoScalpPeriodStart(r.URL.Query().Get("period"))
+
 	// Get history and stats
-	history := h.service.GetHistory(fromTime)
-	stats := h.service.GetStatistics(fromTime)
+	history := h.service.GetHistory(r.Context(), userID, fromTime)
+	stats := h.service.GetStatistics(r.Context(), userID, fromTime)
 
 	response := map[string]interface{}{
 		"history": history,
@@ -96,3 +113,32 @@ func (h *AutoScalpHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// GetEquityCurve handles GET /api/autoscalp/equity?userId=xxx&period=1d|7d|30d
+func (h *AutoScalpHandler) GetEquityCurve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	curve := h.service.GetEquityCurve(r.Context(), userID, autoScalpPeriodStart(r.URL.Query().Get("period")))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(curve)
+}
+
+func autoScalpPeriodStart(period string) time.Time {
+	switch period {
+	case "7d":
+		return time.Now().Add(-7 * 24 * time.Hour)
+	case "30d":
+		return time.Now().Add(-30 * 24 * time.Hour)
+	default:
+		return time.Now().Add(-24 * time.Hour) // Default 1 day
+	}
+}
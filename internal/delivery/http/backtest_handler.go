@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"screener-backend/internal/usecase"
+)
+
+// BacktestHandler runs backtests of the screener's strategies
+type BacktestHandler struct {
+	backtester *usecase.Backtester
+}
+
+// NewBacktestHandler creates a new backtest handler
+func NewBacktestHandler(backtester *usecase.Backtester) *BacktestHandler {
+	return &BacktestHandler{backtester: backtester}
+}
+
+// RunBacktest handles POST /api/backtest with body {"symbol": "SOLUSDT",
+// "strategy": "reversal|pullback|breakout|autoscalp", "from": "...", "to":
+// "...", "step": "5m", "feeBps": 4, "exit": {"stopLossPercent": 0.4, ...},
+// "csv": "..."}. exit overrides the auto scalper's default exit settings
+// field by field. csv holds 1m candles to replay instead of downloading the
+// range; from and to then default to the candles' range. Without csv the
+// range defaults to the last 24 hours.
+func (h *BacktestHandler) RunBacktest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Symbol   string          `json:"symbol"`
+		Strategy string          `json:"strategy"`
+		From     string          `json:"from"`
+		To       string          `json:"to"`
+		Step     string          `json:"step"`
+		FeeBps   float64         `json:"feeBps"`
+		Exit     json.RawMessage `json:"exit"`
+		CSV      string          `json:"csv"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req := usecase.BacktestRequest{Symbol: body.Symbol, Strategy: body.Strategy, FeeBps: body.FeeBps, Exit: usecase.DefaultAutoScalpSettings()}
+	if len(body.Exit) > 0 {
+		if err := json.Unmarshal(body.Exit, req.Exit); err != nil {
+			http.Error(w, "Invalid exit", http.StatusBadRequest)
+			return
+		}
+	}
+	var err error
+	if body.Step != "" {
+		if req.Step, err = time.ParseDuration(body.Step); err != nil {
+			http.Error(w, "Invalid step", http.StatusBadRequest)
+			return
+		}
+	}
+	if body.CSV != "" {
+		if req.Candles, err = usecase.ParseCandleCSV(strings.NewReader(body.CSV)); err != nil {
+			writeError(w, err)
+			return
+		}
+	} else {
+		req.To = time.Now()
+		req.From = req.To.Add(-24 * time.Hour)
+	}
+	if body.From != "" {
+		if req.From, err = parseFilterTime(body.From, false); err != nil {
+			http.Error(w, "Invalid from", http.StatusBadRequest)
+			return
+		}
+	}
+	if body.To != "" {
+		if req.To, err = parseFilterTime(body.To, true); err != nil {
+			http.Error(w, "Invalid to", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.backtester.Run(r.Context(), req)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
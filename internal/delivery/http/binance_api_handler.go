@@ -1,7 +1,9 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"screener-backend/internal/domain"
@@ -31,6 +33,11 @@ func (h *BinanceAPIHandler) SaveCredentials(w http.ResponseWriter, r *http.Reque
 		SecretKey string   `json:"secretKey"`
 		IsTestnet bool     `json:"isTestnet"`
 		IsEnabled bool     `json:"isEnabled"`
+
+		// Optional sub-account routing
+		SubAccountEmail     string `json:"subAccountEmail"`
+		SubAccountAPIKey    string `json:"subAccountApiKey"`
+		SubAccountSecretKey string `json:"subAccountSecretKey"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -44,9 +51,31 @@ func (h *BinanceAPIHandler) SaveCredentials(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if req.SubAccountEmail != "" {
+		if req.IsTestnet {
+			http.Error(w, "Sub-accounts are not supported on testnet", http.StatusBadRequest)
+			return
+		}
+		if req.SubAccountAPIKey == "" || req.SubAccountSecretKey == "" {
+			http.Error(w, "Sub-account API key and secret are required when subAccountEmail is set", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cred := &domain.BinanceAPICredentials{
+		UserID:              req.UserID,
+		APIKey:              req.APIKey,
+		SecretKey:           req.SecretKey,
+		IsTestnet:           req.IsTestnet,
+		IsEnabled:           req.IsEnabled,
+		Permissions:         []string{"FUTURES"}, // Default for futures trading
+		SubAccountEmail:     req.SubAccountEmail,
+		SubAccountAPIKey:    req.SubAccountAPIKey,
+		SubAccountSecretKey: req.SubAccountSecretKey,
+	}
+
 	// Test connection before saving
-	client := binance.NewTradingClient(req.APIKey, req.SecretKey, req.IsTestnet)
-	if err := client.TestConnection(); err != nil {
+	if err := testCredentials(r.Context(), cred); err != nil {
 		log.Printf("Binance API test failed: %v", err)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
@@ -58,24 +87,14 @@ func (h *BinanceAPIHandler) SaveCredentials(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Get account info to check permissions
-	accountInfo, err := client.GetAccountInfo()
+	accountInfo, err := fetchAccountInfo(r.Context(), cred)
 	if err != nil {
 		http.Error(w, "Failed to get account info", http.StatusBadRequest)
 		return
 	}
 
-	// Save credentials
-	cred := &domain.BinanceAPICredentials{
-		UserID:      req.UserID,
-		APIKey:      req.APIKey,
-		SecretKey:   req.SecretKey,
-		IsTestnet:   req.IsTestnet,
-		IsEnabled:   req.IsEnabled,
-		Permissions: []string{"FUTURES"}, // Default for futures trading
-	}
-
-	if err := h.repo.SaveCredentials(cred); err != nil {
-		http.Error(w, "Failed to save credentials", http.StatusInternalServerError)
+	if err := h.repo.SaveCredentials(r.Context(), cred); err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -100,7 +119,7 @@ func (h *BinanceAPIHandler) GetCredentials(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	cred, err := h.repo.GetCredentials(userID)
+	cred, err := h.repo.GetCredentials(r.Context(), userID)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusNotFound)
@@ -120,6 +139,10 @@ func (h *BinanceAPIHandler) GetCredentials(w http.ResponseWriter, r *http.Reques
 		"lastTested": cred.LastTested,
 		"createdAt":  cred.CreatedAt,
 	}
+	if cred.UsesSubAccount() {
+		response["subAccountEmail"] = cred.SubAccountEmail
+		response["subAccountApiKey"] = cred.SubAccountAPIKey
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -138,8 +161,8 @@ func (h *BinanceAPIHandler) DeleteCredentials(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if err := h.repo.DeleteCredentials(userID); err != nil {
-		http.Error(w, "Failed to delete credentials", http.StatusInternalServerError)
+	if err := h.repo.DeleteCredentials(r.Context(), userID); err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -162,17 +185,15 @@ func (h *BinanceAPIHandler) GetAccountInfo(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	cred, err := h.repo.GetCredentials(userID)
+	cred, err := h.repo.GetCredentials(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "Credentials not found", http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
-	client := binance.NewTradingClient(cred.APIKey, cred.SecretKey, cred.IsTestnet)
-	accountInfo, err := client.GetAccountInfo()
+	accountInfo, err := fetchAccountInfo(r.Context(), cred)
 	if err != nil {
-		log.Printf("Failed to get account info: %v", err)
-		http.Error(w, "Failed to get account info", http.StatusInternalServerError)
+		writeUpstreamError(w, fmt.Errorf("failed to get account info: %w", err))
 		return
 	}
 
@@ -198,8 +219,8 @@ func (h *BinanceAPIHandler) SaveTradingConfig(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if err := h.repo.SaveTradingConfig(&config); err != nil {
-		http.Error(w, "Failed to save config", http.StatusInternalServerError)
+	if err := h.repo.SaveTradingConfig(r.Context(), &config); err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -222,9 +243,9 @@ func (h *BinanceAPIHandler) GetTradingConfig(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	config, err := h.repo.GetTradingConfig(userID)
+	config, err := h.repo.GetTradingConfig(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "Failed to get config", http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -245,14 +266,13 @@ func (h *BinanceAPIHandler) TestConnection(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	cred, err := h.repo.GetCredentials(userID)
+	cred, err := h.repo.GetCredentials(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "Credentials not found", http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
-	client := binance.NewTradingClient(cred.APIKey, cred.SecretKey, cred.IsTestnet)
-	if err := client.TestConnection(); err != nil {
+	if err := testCredentials(r.Context(), cred); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -263,10 +283,13 @@ func (h *BinanceAPIHandler) TestConnection(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Update last tested
-	h.repo.UpdateLastTested(userID)
+	h.repo.UpdateLastTested(r.Context(), userID)
 
 	// Get account info
-	accountInfo, _ := client.GetAccountInfo()
+	accountInfo, err := fetchAccountInfo(r.Context(), cred)
+	if err != nil {
+		accountInfo = &domain.BinanceAccountInfo{}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -275,3 +298,73 @@ func (h *BinanceAPIHandler) TestConnection(w http.ResponseWriter, r *http.Reques
 		"positions": accountInfo.PositionsCount,
 	})
 }
+
+// GetSubAccounts handles GET /api/binance/sub-accounts?userId=xxx
+// Lists sub-accounts under the stored master credentials so the app can offer a selection.
+func (h *BinanceAPIHandler) GetSubAccounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	cred, err := h.repo.GetCredentials(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	master := binance.NewTradingClient(cred.APIKey, cred.SecretKey, cred.IsTestnet)
+	subAccounts, err := master.GetSubAccounts(r.Context())
+	if err != nil {
+		writeUpstreamError(w, fmt.Errorf("failed to list sub-accounts: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"subAccounts": subAccounts,
+		"selected":    cred.SubAccountEmail,
+	})
+}
+
+// testCredentials verifies the master key and, when a sub-account is selected,
+// that it belongs to the master account and its own trading keys work.
+func testCredentials(ctx context.Context, cred *domain.BinanceAPICredentials) error {
+	master := binance.NewTradingClient(cred.APIKey, cred.SecretKey, cred.IsTestnet)
+	if !cred.UsesSubAccount() {
+		return master.TestConnection(ctx)
+	}
+
+	subAccounts, err := master.GetSubAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, sub := range subAccounts {
+		if sub.Email == cred.SubAccountEmail {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("sub-account %s not found under master account", cred.SubAccountEmail)
+	}
+
+	return binance.NewTradingClientForCredentials(cred).TestConnection(ctx)
+}
+
+// fetchAccountInfo returns balances/positions of the account trades are routed to:
+// the selected sub-account (queried via the master key) or the main futures account.
+func fetchAccountInfo(ctx context.Context, cred *domain.BinanceAPICredentials) (*domain.BinanceAccountInfo, error) {
+	master := binance.NewTradingClient(cred.APIKey, cred.SecretKey, cred.IsTestnet)
+	if cred.UsesSubAccount() {
+		return master.GetSubAccountFuturesAccount(ctx, cred.SubAccountEmail)
+	}
+	return master.GetAccountInfo(ctx)
+}
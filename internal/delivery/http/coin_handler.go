@@ -0,0 +1,112 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/usecase"
+	"strconv"
+)
+
+// CoinHandler serves views of the latest screening cycle
+type CoinHandler struct {
+	repo     domain.ScreenerRepository
+	profiles *usecase.ScoringProfileService
+}
+
+// NewCoinHandler creates a new coin handler
+func NewCoinHandler(repo domain.ScreenerRepository, profiles *usecase.ScoringProfileService) *CoinHandler {
+	return &CoinHandler{repo: repo, profiles: profiles}
+}
+
+// ListCoins handles GET /api/screener/coins?strategy=reversal|intraday|pullback|breakout|trend&status=TRIGGER&minScore=70&offset=0&limit=50&userId=
+// The latest cycle's coins for clients that missed the WebSocket updates,
+// filtered by one strategy's status and score and ordered by that score,
+// highest first. With userId the reversal scores, and the statuses they
+// earn, are weighted by the user's scoring profile.
+func (h *CoinHandler) ListCoins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	query := usecase.CoinQuery{Strategy: q.Get("strategy"), Status: q.Get("status")}
+	if v := q.Get("minScore"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "Invalid minScore", http.StatusBadRequest)
+			return
+		}
+		query.MinScore = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+		query.Offset = n
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > usecase.MaxCoinPageSize {
+			http.Error(w, "Invalid limit (1-500)", http.StatusBadRequest)
+			return
+		}
+		query.Limit = n
+	}
+
+	coins := h.repo.GetCoins()
+	if userID := q.Get("userId"); userID != "" {
+		var err error
+		if coins, err = h.profiles.Rescore(r.Context(), userID, coins); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	page, err := usecase.QueryCoins(coins, query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetHeatmap handles GET /api/coins/heatmap?strategy=reversal|pullback|breakout|trend&limit=
+// A symbols × timeframes matrix of scores and RSI for a market overview,
+// without the full CoinData. Symbols are ordered by the strategy's score,
+// highest first; limit keeps only the top ones.
+func (h *CoinHandler) GetHeatmap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	strategy := q.Get("strategy")
+	if strategy == "" {
+		strategy = usecase.HeatmapReversal
+	}
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	heatmap, err := usecase.BuildHeatmap(h.repo.GetCoins(), strategy, limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(heatmap)
+}
@@ -0,0 +1,24 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithRequestTimeout puts a deadline on each request's context so database
+// and exchange calls made for a slow or abandoned request are cancelled
+// instead of holding pool connections. WebSocket upgrades are left alone:
+// their context lives as long as the connection.
+func WithRequestTimeout(next http.Handler, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
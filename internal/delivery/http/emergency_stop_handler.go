@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"screener-backend/internal/usecase"
+)
+
+// EmergencyStopHandler flattens a user's Binance account on request
+type EmergencyStopHandler struct {
+	service *usecase.AutoScalpingService
+}
+
+// NewEmergencyStopHandler creates a new emergency stop handler
+func NewEmergencyStopHandler(service *usecase.AutoScalpingService) *EmergencyStopHandler {
+	return &EmergencyStopHandler{service: service}
+}
+
+// Stop handles POST /api/binance/emergency-stop with {"userId": "...", "reason": "..."}
+// Closes every position, cancels the open orders and turns auto scalping
+// off, and returns what was done.
+func (h *EmergencyStopHandler) Stop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		UserID string `json:"userId"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.UserID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "manual"
+	}
+
+	result, err := h.service.EmergencyStop(r.Context(), req.UserID, reason)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"screener-backend/internal/domain"
+)
+
+// errorResponse is the JSON body of every error written by writeError
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// errorKinds maps domain error kinds to a status and the code used when the
+// error carries none of its own
+var errorKinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{domain.ErrNotFound, http.StatusNotFound, "NOT_FOUND"},
+	{domain.ErrValidation, http.StatusBadRequest, "VALIDATION_FAILED"},
+	{domain.ErrConflict, http.StatusConflict, "CONFLICT"},
+	{domain.ErrRiskLimit, http.StatusUnprocessableEntity, "RISK_LIMIT"},
+	{domain.ErrExchangeRejected, http.StatusUnprocessableEntity, "EXCHANGE_REJECTED"},
+	{domain.ErrUnauthorized, http.StatusUnauthorized, "UNAUTHORIZED"},
+	{domain.ErrForbidden, http.StatusForbidden, "FORBIDDEN"},
+}
+
+// writeError writes err as {"error": ..., "code": ...}. Unclassified errors
+// are logged and reported as a generic 500 so internals don't leak.
+func writeError(w http.ResponseWriter, err error) {
+	writeErrorWithFallback(w, err, http.StatusInternalServerError, "INTERNAL", "Internal server error")
+}
+
+// writeUpstreamError is writeError for calls that reach Binance: unclassified
+// failures are reported as 502 with the underlying message.
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	writeErrorWithFallback(w, err, http.StatusBadGateway, "UPSTREAM_ERROR", err.Error())
+}
+
+func writeErrorWithFallback(w http.ResponseWriter, err error, status int, code, message string) {
+	classified := false
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			status, code, message = k.status, k.code, err.Error()
+			classified = true
+			break
+		}
+	}
+	if c := domain.ErrorCode(err); c != "" {
+		code = c
+	}
+	if !classified {
+		log.Printf("HTTP %d: %v", status, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: message, Code: code})
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/parquet"
+	"screener-backend/internal/usecase"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportHandler serves bulk dumps of the screener's data for offline analysis
+type ExportHandler struct {
+	repo    domain.ScreenerRepository
+	dataset *usecase.DatasetService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(repo domain.ScreenerRepository, dataset *usecase.DatasetService) *ExportHandler {
+	return &ExportHandler{repo: repo, dataset: dataset}
+}
+
+// GetSnapshot handles GET /api/export/snapshot?format=json|ndjson
+// Every coin of the latest cycle with its per-timeframe scores and features.
+// json (default) is one document; ndjson is one coin per line, gzip-compressed.
+func (h *ExportHandler) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "ndjson" {
+		http.Error(w, "Invalid format (expected json or ndjson)", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().UTC()
+	coins := h.repo.GetCoins()
+	if coins == nil {
+		coins = []domain.CoinData{}
+	}
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"exportedAt": now,
+			"count":      len(coins),
+			"coins":      coins,
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("snapshot_%s.ndjson.gz", now.Format("20060102_1504"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	gz := gzip.NewWriter(w)
+	defer gz.Close()
+	enc := json.NewEncoder(gz)
+	for i := range coins {
+		if err := enc.Encode(&coins[i]); err != nil {
+			return // client went away
+		}
+	}
+}
+
+// GetDataset handles GET /api/export/dataset?format=csv|parquet&symbol=&from=...&to=...
+// One row per archived snapshot with features, per-TF scores and forward
+// return labels (ret_5m ... ret_4h) for model training. The range defaults to
+// the last 24 hours and may span up to 31 days.
+func (h *ExportHandler) GetDataset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "parquet" {
+		http.Error(w, "Invalid format (expected csv or parquet)", http.StatusBadRequest)
+		return
+	}
+	from, to, ok := archiveRange(w, r)
+	if !ok {
+		return
+	}
+
+	ds, err := h.dataset.Build(r.Context(), strings.ToUpper(r.URL.Query().Get("symbol")), from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("dataset_%s_%s.%s", from.UTC().Format("20060102_1504"), to.UTC().Format("20060102_1504"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "parquet" {
+		pw := parquet.NewWriter(ds.Columns)
+		for _, row := range ds.Rows {
+			if err := pw.Append(row); err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/vnd.apache.parquet")
+		pw.WriteTo(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	cw := csv.NewWriter(w)
+	header := make([]string, len(ds.Columns))
+	for i, c := range ds.Columns {
+		header[i] = c.Name
+	}
+	cw.Write(header)
+	record := make([]string, len(ds.Columns))
+	for _, row := range ds.Rows {
+		for i, v := range row {
+			record[i] = csvValue(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return // client went away
+		}
+	}
+	cw.Flush()
+}
+
+// csvValue formats a dataset value; NaN is left empty
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case string:
+		return v
+	}
+	return ""
+}
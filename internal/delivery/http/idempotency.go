@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"screener-backend/internal/domain"
+	"time"
+)
+
+const (
+	idempotencyKeyTTL       = 24 * time.Hour
+	idempotencyStoreTimeout = 5 * time.Second
+)
+
+// Idempotent wraps a mutating handler so that requests carrying the same
+// Idempotency-Key header (per path and user) run at most once; retries
+// get the stored response replayed. Requests without the header pass through.
+// 5xx responses are not stored so the client can retry them, nor are
+// requests whose handler panicked. lease is how long a request may run (the
+// request timeout); a key still reserved after it is taken over by a retry.
+func Idempotent(repo domain.IdempotencyRepository, lease time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" || repo == nil {
+			next(w, r)
+			return
+		}
+		if len(key) > 255 {
+			http.Error(w, "Idempotency-Key too long", http.StatusBadRequest)
+			return
+		}
+
+		userID, err := requestUser(r)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		scope := r.Method + " " + r.URL.Path + "|" + userID
+		stored, reserved, err := repo.Reserve(r.Context(), scope, key, idempotencyKeyTTL, lease+idempotencyStoreTimeout)
+		if err != nil {
+			log.Printf("Idempotency: reserve failed, processing without dedupe: %v", err)
+			next(w, r)
+			return
+		}
+		if !reserved {
+			if stored == nil {
+				http.Error(w, "A request with this Idempotency-Key is still being processed", http.StatusConflict)
+				return
+			}
+			if stored.ContentType != "" {
+				w.Header().Set("Content-Type", stored.ContentType)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.Body)
+			return
+		}
+
+		// Record the outcome even if the client gave up or the request
+		// deadline passed while the handler ran
+		storeCtx := func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.WithoutCancel(r.Context()), idempotencyStoreTimeout)
+		}
+
+		// Unless the response is stored, the key is released for a retry,
+		// also when the handler panics
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			ctx, cancel := storeCtx()
+			defer cancel()
+			if err := repo.Release(ctx, scope, key); err != nil {
+				log.Printf("Idempotency: release failed: %v", err)
+			}
+		}()
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		if rec.status >= 500 {
+			return
+		}
+		ctx, cancel := storeCtx()
+		defer cancel()
+		if err := repo.Complete(ctx, scope, key, &domain.IdempotentResponse{
+			StatusCode:  rec.status,
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+			CreatedAt:   time.Now(),
+		}); err != nil {
+			log.Printf("Idempotency: storing response failed: %v", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// requestUser is the user a request acts for: the token's, else the userId
+// of the query string or of a JSON object body. The body is left in place.
+func requestUser(r *http.Request) (string, error) {
+	if userID := AuthenticatedUser(r.Context()); userID != "" {
+		return userID, nil
+	}
+	if userID := r.URL.Query().Get("userId"); userID != "" {
+		return userID, nil
+	}
+	body, err := readBody(r)
+	if err != nil {
+		return "", err
+	}
+	return jsonUserID(body), nil
+}
+
+// responseRecorder passes the response through while keeping a copy
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/usecase"
+)
+
+// MarketHandler serves market-wide state the screener's signals are judged against
+type MarketHandler struct {
+	regime        *usecase.RegimeFilter
+	marketContext *usecase.MarketContextService
+}
+
+// NewMarketHandler creates a new market handler
+func NewMarketHandler(regime *usecase.RegimeFilter, marketContext *usecase.MarketContextService) *MarketHandler {
+	return &MarketHandler{regime: regime, marketContext: marketContext}
+}
+
+// GetRegime handles GET /api/market/regime: the BTC trend and volatility
+// regime and which signal filters it currently switches on
+func (h *MarketHandler) GetRegime(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	regime := h.regime.Current()
+	if regime == nil {
+		http.Error(w, "Regime not detected yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"regime":      regime,
+		"shortFilter": h.regime.ShortFilter(),
+		"longFilter":  h.regime.LongFilter(),
+	})
+}
+
+// GetContext handles GET /api/market/context: BTC dominance, total market cap
+// change and the Fear & Greed index, with the score multipliers they give
+func (h *MarketHandler) GetContext(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	current := h.marketContext.Current()
+	if current == nil {
+		http.Error(w, "Market context not fetched yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"context":         current,
+		"shortMultiplier": h.marketContext.ShortMultiplier(),
+		"longMultiplier":  h.marketContext.LongMultiplier(),
+	})
+}
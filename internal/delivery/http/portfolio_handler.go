@@ -0,0 +1,42 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/usecase"
+)
+
+// PortfolioHandler serves the consolidated view of a user's open positions
+type PortfolioHandler struct {
+	portfolio *usecase.PortfolioService
+}
+
+// NewPortfolioHandler creates a new portfolio handler
+func NewPortfolioHandler(portfolio *usecase.PortfolioService) *PortfolioHandler {
+	return &PortfolioHandler{portfolio: portfolio}
+}
+
+// GetPortfolio handles GET /api/portfolio?userId=: live Binance positions,
+// active auto scalp positions and open journal trades, with total exposure,
+// unrealized P/L and the risk left to each stop
+func (h *PortfolioHandler) GetPortfolio(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	portfolio, err := h.portfolio.Portfolio(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(portfolio)
+}
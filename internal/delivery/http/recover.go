@@ -0,0 +1,39 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"screener-backend/internal/infrastructure/reporting"
+)
+
+// WithRecover turns a handler panic into a 500 and reports it with the
+// request route, instead of the server's bare log line and dropped connection
+func WithRecover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+			stack := debug.Stack()
+			log.Printf("Panic serving %s %s: %v\n%s", r.Method, r.URL.Path, rec, stack)
+			reporting.Capture(r.Context(), reporting.Event{
+				Level:   reporting.LevelFatal,
+				Message: "panic in HTTP handler",
+				Err:     fmt.Errorf("panic: %v", rec),
+				UserID:  r.URL.Query().Get("userId"),
+				Tags:    map[string]string{"method": r.Method, "path": r.URL.Path},
+				Stack:   stack,
+			})
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/usecase"
+)
+
+// ScoringProfileHandler manages the users' weightings of the reversal score
+type ScoringProfileHandler struct {
+	profiles *usecase.ScoringProfileService
+}
+
+// NewScoringProfileHandler creates a new scoring profile handler
+func NewScoringProfileHandler(profiles *usecase.ScoringProfileService) *ScoringProfileHandler {
+	return &ScoringProfileHandler{profiles: profiles}
+}
+
+// GetProfile handles GET /api/screener/scoring-profile?userId=
+func (h *ScoringProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.profiles.Get(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// SaveProfile handles POST /api/screener/scoring-profile with body
+// {"userId": "...", "weights": {"overextension": 1.2, "crowding": 1,
+// "exhaustion": 0.8, "structure": 1, "momentum": 1}}. Omitted weights keep
+// their current values.
+func (h *ScoringProfileHandler) SaveProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		UserID  string          `json:"userId"`
+		Weights json.RawMessage `json:"weights"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.UserID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.profiles.Get(r.Context(), req.UserID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(req.Weights) > 0 {
+		if err := json.Unmarshal(req.Weights, &profile.Weights); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.profiles.Save(r.Context(), profile); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
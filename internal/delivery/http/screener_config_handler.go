@@ -0,0 +1,59 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/usecase"
+)
+
+// ScreenerConfigHandler manages the strategy toggles and timeframes of the
+// screener
+type ScreenerConfigHandler struct {
+	configs *usecase.ScreenerConfigService
+}
+
+// NewScreenerConfigHandler creates a new screener config handler
+func NewScreenerConfigHandler(configs *usecase.ScreenerConfigService) *ScreenerConfigHandler {
+	return &ScreenerConfigHandler{configs: configs}
+}
+
+// GetConfig handles GET /api/screener/config
+func (h *ScreenerConfigHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.configs.Current(r.Context()))
+}
+
+// SaveConfig handles POST /api/screener/config with body {"scalp":
+// {"enabled": true, "timeframes": ["1m", "5m"]}, "intraday": {...},
+// "pullback": {"enabled": true, "setupTimeframes": ["5m", "15m"],
+// "executionTimeframes": ["1m", "3m"]}, "breakout": {...}, "followTrend":
+// {...}}. Omitted strategies keep their current settings.
+func (h *ScreenerConfigHandler) SaveConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg := h.configs.Current(r.Context())
+	if err := json.NewDecoder(r.Body).Decode(cfg); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	cfg.UpdatedBy = AuthenticatedUser(r.Context())
+	if cfg.UpdatedBy == "" {
+		cfg.UpdatedBy = r.URL.Query().Get("userId")
+	}
+
+	if err := h.configs.Save(r.Context(), cfg); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cfg)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/usecase"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSignalLimit = 200
+	maxSignalLimit     = 2000
+)
+
+// SignalHandler serves recorded screener signals and their outcomes
+type SignalHandler struct {
+	signals *usecase.SignalOutcomeService
+}
+
+// NewSignalHandler creates a new signal handler
+func NewSignalHandler(signals *usecase.SignalOutcomeService) *SignalHandler {
+	return &SignalHandler{signals: signals}
+}
+
+// List handles GET /api/signals?strategy=TRIGGER&symbol=BTCUSDT&from=...&to=...&limit=200
+// Newest first, with the outcomes measured so far; the range defaults to the
+// last 24 hours.
+func (h *SignalHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	strategy, ok := signalStrategy(w, q.Get("strategy"))
+	if !ok {
+		return
+	}
+	from, to, ok := archiveRange(w, r)
+	if !ok {
+		return
+	}
+	limit := defaultSignalLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxSignalLimit {
+			http.Error(w, "Invalid limit (1-2000)", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	signals, err := h.signals.List(r.Context(), domain.SignalFilter{
+		Strategy: strategy,
+		Symbol:   strings.ToUpper(q.Get("symbol")),
+		From:     from,
+		To:       to,
+		Limit:    limit,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(signals)
+}
+
+// GetStats handles GET /api/signals/stats?strategy=TRIGGER&from=...&to=...&bucket=10
+// Hit rate and average forward return at every horizon, per strategy and
+// per score bucket of the given width.
+func (h *SignalHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	strategy, ok := signalStrategy(w, q.Get("strategy"))
+	if !ok {
+		return
+	}
+	from, to, ok := archiveRange(w, r)
+	if !ok {
+		return
+	}
+	var bucket float64
+	if v := q.Get("bucket"); v != "" {
+		b, err := strconv.ParseFloat(v, 64)
+		if err != nil || b < 1 || b > 100 {
+			http.Error(w, "Invalid bucket (1-100)", http.StatusBadRequest)
+			return
+		}
+		bucket = b
+	}
+
+	stats, err := h.signals.Stats(r.Context(), from, to, strategy, bucket)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// signalStrategy validates the optional strategy filter, writing a 400 on bad input
+func signalStrategy(w http.ResponseWriter, v string) (string, bool) {
+	switch s := strings.ToUpper(v); s {
+	case "", domain.StrategyTrigger, domain.StrategyBreakout, domain.StrategyDip:
+		return s, true
+	default:
+		http.Error(w, "Invalid strategy (expected TRIGGER, BREAKOUT or DIP)", http.StatusBadRequest)
+		return "", false
+	}
+}
@@ -52,7 +52,7 @@ func (h *TestHandler) SendTestNotification(w http.ResponseWriter, r *http.Reques
 		"timestamp": "now",
 	}
 
-	err := h.fcmClient.SendMulticast(tokens, title, body, data)
+	err := h.fcmClient.SendMulticast(r.Context(), tokens, title, body, data)
 	if err != nil {
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"success": false,
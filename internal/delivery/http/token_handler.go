@@ -21,6 +21,7 @@ func NewTokenHandler(tokenRepo *repository.TokenRepository) *TokenHandler {
 type RegisterTokenRequest struct {
 	Token    string
 	Platform string
+	UserID   string // optional, links the device to a user for personal notifications
 }
 
 type TokenResponse struct {
@@ -50,7 +51,7 @@ func (h *TokenHandler) HandleRegisterToken(w http.ResponseWriter, r *http.Reques
 		req.Platform = "android"
 	}
 
-	h.tokenRepo.RegisterToken(req.Token, req.Platform, time.Now().Unix())
+	h.tokenRepo.RegisterToken(req.Token, req.Platform, req.UserID, time.Now().Unix())
 
 	response := TokenResponse{
 		Success: true,
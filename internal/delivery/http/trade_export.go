@@ -0,0 +1,172 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"screener-backend/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var tradeExportHeader = []string{
+	"ID", "Symbol", "Side", "Strategy", "Status",
+	"Entry Time", "Entry Price", "Quantity", "Leverage", "Notional",
+	"Stop Loss", "TP1", "TP2", "TP3", "Planned R:R",
+	"Exit Time", "Exit Price", "Fees", "Funding", "P/L", "R-Multiple", "Duration (min)",
+	"Tags", "Entry Reason", "Notes",
+}
+
+// ExportEntries handles GET /api/trades/export?userId=xxx&format=csv|xlsx
+// Exports the whole journal (open and closed entries), oldest first, narrowed
+// by the same filters as GetHistory.
+func (h *TradeHandler) ExportEntries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		http.Error(w, "Invalid format (expected csv or xlsx)", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	entries := h.repo.GetEntryHistory(r.Context(), userID, filter)
+	for _, e := range h.repo.GetActiveEntries(r.Context(), userID) {
+		if filter.Matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].EntryTime.Before(entries[j].EntryTime)
+	})
+
+	filename := fmt.Sprintf("trades_%s.%s", time.Now().Format("20060102"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		cw := csv.NewWriter(w)
+		cw.Write(tradeExportHeader)
+		for _, e := range entries {
+			cw.Write(tradeExportRow(e))
+		}
+		cw.Flush()
+		return
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := f.GetSheetName(0)
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := sw.SetRow("A1", toCells(tradeExportHeader)); err != nil {
+		writeError(w, err)
+		return
+	}
+	for i, e := range entries {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := sw.SetRow(cell, toCells(tradeExportRow(e))); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	f.Write(w)
+}
+
+func tradeExportRow(e *domain.TradeEntry) []string {
+	side := "SHORT"
+	if e.IsLong {
+		side = "LONG"
+	}
+
+	exitTime, exitPrice, pl, rMultiple, duration := "", "", "", "", ""
+	if e.ExitTime != nil {
+		exitTime = e.ExitTime.Format(time.RFC3339)
+		duration = strconv.FormatFloat(e.Duration().Minutes(), 'f', 1, 64)
+	}
+	if e.ExitPrice != nil {
+		exitPrice = formatExportFloat(*e.ExitPrice)
+	}
+	if e.ProfitLoss != nil {
+		pl = formatExportFloat(*e.ProfitLoss)
+	}
+	if rm, ok := e.RMultiple(); ok {
+		rMultiple = strconv.FormatFloat(rm, 'f', 2, 64)
+	}
+
+	return []string{
+		e.ID, e.Symbol, side, e.Strategy, e.Status,
+		e.EntryTime.Format(time.RFC3339),
+		formatExportFloat(e.EntryPrice),
+		formatExportFloat(e.Quantity),
+		strconv.Itoa(e.Leverage),
+		formatExportFloat(e.Notional),
+		formatExportFloat(e.StopLoss),
+		formatExportFloat(e.TakeProfit1),
+		formatExportFloat(e.TakeProfit2),
+		formatExportFloat(e.TakeProfit3),
+		formatExportFloat(e.RiskReward),
+		exitTime, exitPrice,
+		formatExportFloat(e.Fees),
+		formatExportFloat(e.Funding),
+		pl, rMultiple, duration,
+		strings.Join(e.Tags, ";"),
+		e.EntryReason,
+		e.Notes,
+	}
+}
+
+func formatExportFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// tradeExportTextColumns stay strings in the spreadsheet even if they look numeric
+var tradeExportTextColumns = map[string]bool{
+	"ID": true, "Symbol": true, "Side": true, "Strategy": true, "Status": true,
+	"Entry Time": true, "Exit Time": true, "Tags": true, "Entry Reason": true, "Notes": true,
+}
+
+// toCells converts a row to spreadsheet cells, keeping numeric columns numeric
+func toCells(row []string) []interface{} {
+	cells := make([]interface{}, len(row))
+	for i, v := range row {
+		numeric := !tradeExportTextColumns[tradeExportHeader[i]]
+		if f, err := strconv.ParseFloat(v, 64); err == nil && numeric {
+			cells[i] = f
+		} else {
+			cells[i] = v
+		}
+	}
+	return cells
+}
@@ -1,42 +1,81 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"screener-backend/internal/domain"
+	"screener-backend/internal/usecase"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // TradeHandler handles trade entry endpoints
 type TradeHandler struct {
-	repo domain.TradeEntryRepository
+	repo          domain.TradeEntryRepository
+	importer      *usecase.TradeImportService
+	screenerRepo  domain.ScreenerRepository
+	summaries     *usecase.DailySummaryService
+	autoScalpRepo domain.AutoScalpRepository
 }
 
 // NewTradeHandler creates a new trade handler
-func NewTradeHandler(repo domain.TradeEntryRepository) *TradeHandler {
-	return &TradeHandler{repo: repo}
+func NewTradeHandler(
+	repo domain.TradeEntryRepository,
+	importer *usecase.TradeImportService,
+	screenerRepo domain.ScreenerRepository,
+	summaries *usecase.DailySummaryService,
+	autoScalpRepo domain.AutoScalpRepository,
+) *TradeHandler {
+	return &TradeHandler{
+		repo:          repo,
+		importer:      importer,
+		screenerRepo:  screenerRepo,
+		summaries:     summaries,
+		autoScalpRepo: autoScalpRepo,
+	}
 }
 
-// CreateEntry handles POST /api/trades
+// CreateEntry handles POST /api/trades (userId in body or query)
+// Pass "signalSymbol" to snapshot the screener's current CoinData for that
+// symbol onto the entry. Only the latest screener cycle is available; a
+// symbol it did not screen is rejected (SIGNAL_NOT_IN_CYCLE) rather than
+// stored without a snapshot.
 func (h *TradeHandler) CreateEntry(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	var entry domain.TradeEntry
-	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+	var req struct {
+		domain.TradeEntry
+		SignalSymbol string `json:"signalSymbol"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		// Log the exact error for debugging
 		fmt.Printf("Error decoding request body: %v\n", err)
 		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
+	entry := req.TradeEntry
 	
 	// Log received entry for debugging
 	fmt.Printf("Received entry: Symbol=%s, IsLong=%v, EntryPrice=%f, Status=%s\n", 
 		entry.Symbol, entry.IsLong, entry.EntryPrice, entry.Status)
 
+	if entry.UserID == "" {
+		entry.UserID = r.URL.Query().Get("userId")
+	}
+	if entry.UserID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
 	// Set default values
 	if entry.ID == "" {
 		entry.ID = fmt.Sprintf("%d", time.Now().UnixNano())
@@ -47,24 +86,85 @@ func (h *TradeHandler) CreateEntry(w http.ResponseWriter, r *http.Request) {
 	if entry.Status == "" {
 		entry.Status = "active"
 	}
+	if entry.Strategy == "" {
+		entry.Strategy = "manual"
+	}
+	if !domain.IsValidTradeStrategy(entry.Strategy) {
+		http.Error(w, "Invalid strategy (expected scalp, pullback, breakout or manual)", http.StatusBadRequest)
+		return
+	}
+	entry.Tags = normalizeTags(entry.Tags)
 
-	if err := h.repo.CreateEntry(&entry); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := entry.ValidateLevels(); err != nil {
+		writeError(w, domain.Validation("INVALID_TRADE_LEVELS", err.Error()))
+		return
+	}
+	if entry.Quantity < 0 || entry.Fees < 0 {
+		http.Error(w, "quantity and fees must not be negative", http.StatusBadRequest)
+		return
+	}
+	if entry.Leverage < 0 || entry.Leverage > 125 {
+		http.Error(w, "leverage must be between 1 and 125 (omitted or 0 means 1)", http.StatusBadRequest)
 		return
 	}
+	if entry.Leverage == 0 {
+		entry.Leverage = 1
+	}
+	entry.SettleAsset = strings.ToUpper(strings.TrimSpace(entry.SettleAsset))
+	if entry.SettleAsset == "USDT" {
+		entry.SettleAsset = ""
+	}
+	entry.ProfitLossNative = nil
+	entry.Notional = entry.EntryPrice * entry.Quantity
+	if entry.IsCoinMargined() {
+		entry.Notional = entry.Quantity // COIN-M quantity is already the USD contract value
+	}
+	entry.RiskReward = math.Round(entry.CalculateRiskReward()*100) / 100
+
+	// Snapshots are only taken server-side
+	entry.Signal = nil
+	if symbol := strings.ToUpper(strings.TrimSpace(req.SignalSymbol)); symbol != "" {
+		snap := h.signalSnapshot(symbol)
+		if snap == nil {
+			writeError(w, domain.Validation("SIGNAL_NOT_IN_CYCLE", fmt.Sprintf("no screener signal for %s in the current cycle", symbol)))
+			return
+		}
+		entry.Signal = snap
+	}
+
+	if entry.AutoScalpEntryID != "" {
+		if err := h.checkAutoScalpLink(r.Context(), &entry, entry.AutoScalpEntryID); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	if err := h.repo.CreateEntry(r.Context(), &entry); err != nil {
+		writeError(w, err)
+		return
+	}
+	if entry.AutoScalpEntryID != "" {
+		h.setAutoScalpLink(r.Context(), entry.AutoScalpEntryID, entry.ID)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(entry)
 }
 
-// GetActiveEntries handles GET /api/trades/active
+// GetActiveEntries handles GET /api/trades/active?userId=xxx
 func (h *TradeHandler) GetActiveEntries(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	entries := h.repo.GetActiveEntries()
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	entries := h.repo.GetActiveEntries(r.Context(), userID)
 	if entries == nil {
 		entries = make([]*domain.TradeEntry, 0)
 	}
@@ -73,39 +173,85 @@ func (h *TradeHandler) GetActiveEntries(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(entries)
 }
 
-// GetHistory handles GET /api/trades/history
+// GetHistory handles GET /api/trades/history?userId=xxx
+// Optional filters: symbol, status, direction=long|short, tag, strategy,
+// from/to (RFC3339 or YYYY-MM-DD, matched against exit time).
 func (h *TradeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	entries := h.repo.GetEntryHistory()
-	if entries == nil {
-		entries = make([]*domain.TradeEntry, 0)
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
 	}
 
+	entries := h.repo.GetEntryHistory(r.Context(), userID, filter)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(entries)
 }
 
-// UpdateEntry handles PUT /api/trades/update?id={id}
+// GetEntry handles GET /api/trades/{id}?userId=xxx
+func (h *TradeHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Missing id parameter", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.repo.GetEntryByID(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// UpdateEntry handles PUT /api/trades/{id}?userId=xxx
 func (h *TradeHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	id := r.URL.Query().Get("id")
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	id := r.PathValue("id")
 	if id == "" {
 		http.Error(w, "Missing id parameter", http.StatusBadRequest)
 		return
 	}
 
 	// Get existing entry
-	existing, err := h.repo.GetEntryByID(id)
+	existing, err := h.repo.GetEntryByID(r.Context(), userID, id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
@@ -116,6 +262,12 @@ func (h *TradeHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
 		ExitTime    *string    `json:"exitTime"`
 		ProfitLoss  *float64   `json:"profitLoss"`
 		EntryReason *string    `json:"entryReason"`
+		Notes       *string    `json:"notes"`
+		Tags        *[]string  `json:"tags"`
+		Strategy    *string    `json:"strategy"`
+		Fees        *float64   `json:"fees"`
+		// "" unlinks; any other value links the given auto scalp entry
+		AutoScalpEntryID *string `json:"autoScalpEntryId"`
 	}
 
 	var payload updatePayload
@@ -143,50 +295,458 @@ func (h *TradeHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
 	if payload.EntryReason != nil {
 		updated.EntryReason = *payload.EntryReason
 	}
-
-	// Calculate P/L if closing and not explicitly provided
-	if updated.ExitPrice != nil && (updated.Status == "closed" || updated.Status == "stopped") {
-		if payload.ProfitLoss != nil {
-			updated.ProfitLoss = payload.ProfitLoss
-		} else {
-			// Simple P/L calculation (assuming position size = 1)
-			diff := 0.0
-			if updated.IsLong {
-				diff = *updated.ExitPrice - updated.EntryPrice
-			} else {
-				diff = updated.EntryPrice - *updated.ExitPrice
+	if payload.Fees != nil {
+		if *payload.Fees < 0 {
+			http.Error(w, "fees must not be negative", http.StatusBadRequest)
+			return
+		}
+		updated.Fees = *payload.Fees
+	}
+	if payload.Notes != nil {
+		updated.Notes = *payload.Notes
+	}
+	if payload.Tags != nil {
+		updated.Tags = normalizeTags(*payload.Tags)
+	}
+	if payload.Strategy != nil {
+		if !domain.IsValidTradeStrategy(*payload.Strategy) {
+			http.Error(w, "Invalid strategy (expected scalp, pullback, breakout or manual)", http.StatusBadRequest)
+			return
+		}
+		updated.Strategy = *payload.Strategy
+	}
+	if payload.AutoScalpEntryID != nil && *payload.AutoScalpEntryID != existing.AutoScalpEntryID {
+		if *payload.AutoScalpEntryID != "" {
+			if err := h.checkAutoScalpLink(r.Context(), &updated, *payload.AutoScalpEntryID); err != nil {
+				writeError(w, err)
+				return
 			}
-			updated.ProfitLoss = &diff
 		}
+		updated.AutoScalpEntryID = *payload.AutoScalpEntryID
+	}
+
+	// Calculate P/L if closing; an explicit profitLoss is in the settlement asset
+	if updated.ExitPrice != nil && updated.IsClosed() {
+		updated.SettleProfitLoss(*updated.ExitPrice, payload.ProfitLoss)
 	}
 
-	if err := h.repo.UpdateEntry(&updated); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.repo.UpdateEntry(r.Context(), &updated); err != nil {
+		writeError(w, err)
 		return
 	}
+	if updated.AutoScalpEntryID != existing.AutoScalpEntryID {
+		if existing.AutoScalpEntryID != "" {
+			h.setAutoScalpLink(r.Context(), existing.AutoScalpEntryID, "")
+		}
+		if updated.AutoScalpEntryID != "" {
+			h.setAutoScalpLink(r.Context(), updated.AutoScalpEntryID, updated.ID)
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(updated)
 }
 
-// DeleteEntry handles DELETE /api/trades/delete?id={id}
+// DeleteEntry handles DELETE /api/trades/{id}?userId=xxx[&purge=true]
+// By default the entry is archived and can be restored. purge=true permanently
+// removes an entry that has already been archived.
 func (h *TradeHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	id := r.URL.Query().Get("id")
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	id := r.PathValue("id")
 	if id == "" {
 		http.Error(w, "Missing id parameter", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.repo.DeleteEntry(id); err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+	if r.URL.Query().Get("purge") == "true" {
+		var linkedAuto string
+		for _, e := range h.repo.GetArchivedEntries(r.Context(), userID) {
+			if e.ID == id {
+				linkedAuto = e.AutoScalpEntryID
+			}
+		}
+		if err := h.repo.PurgeEntry(r.Context(), userID, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		if linkedAuto != "" {
+			h.setAutoScalpLink(r.Context(), linkedAuto, "")
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"purged"}`))
+		return
+	}
+
+	if err := h.repo.ArchiveEntry(r.Context(), userID, id); err != nil {
+		writeError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"deleted"}`))
+	w.Write([]byte(`{"status":"archived"}`))
+}
+
+// RestoreEntry handles POST /api/trades/{id}/restore?userId=xxx
+func (h *TradeHandler) RestoreEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Missing id parameter", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.RestoreEntry(r.Context(), userID, id); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	entry, err := h.repo.GetEntryByID(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// GetArchivedEntries handles GET /api/trades/archived?userId=xxx
+func (h *TradeHandler) GetArchivedEntries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	entries := h.repo.GetArchivedEntries(r.Context(), userID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// normalizeTags trims tags and drops empty or duplicate (case-insensitive) ones
+func normalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		key := strings.ToLower(t)
+		if t == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, t)
+	}
+	return result
+}
+
+// signalSnapshot copies the screener's latest CoinData for symbol, or nil if absent
+func (h *TradeHandler) signalSnapshot(symbol string) *domain.TradeSignalSnapshot {
+	for _, coin := range h.screenerRepo.GetCoins() {
+		if coin.Symbol == symbol {
+			return &domain.TradeSignalSnapshot{CapturedAt: time.Now(), Coin: coin}
+		}
+	}
+	return nil
+}
+
+// parseHistoryFilter reads the optional history filters from the query string
+func parseHistoryFilter(r *http.Request) (domain.TradeHistoryFilter, error) {
+	q := r.URL.Query()
+	filter := domain.TradeHistoryFilter{
+		Symbol:   strings.ToUpper(strings.TrimSpace(q.Get("symbol"))),
+		Status:   q.Get("status"),
+		Tag:      strings.TrimSpace(q.Get("tag")),
+		Strategy: q.Get("strategy"),
+	}
+
+	switch filter.Status {
+	case "", "closed", "stopped", "tp3_hit":
+	default:
+		return filter, domain.Validation("INVALID_FILTER", "invalid status (expected closed, stopped or tp3_hit)")
+	}
+
+	switch strings.ToLower(q.Get("direction")) {
+	case "":
+	case "long":
+		isLong := true
+		filter.IsLong = &isLong
+	case "short":
+		isLong := false
+		filter.IsLong = &isLong
+	default:
+		return filter, domain.Validation("INVALID_FILTER", "invalid direction (expected long or short)")
+	}
+
+	if v := q.Get("from"); v != "" {
+		t, err := parseFilterTime(v, false)
+		if err != nil {
+			return filter, domain.Validation("INVALID_FILTER", fmt.Sprintf("invalid from: %v", err))
+		}
+		filter.From = &t
+	}
+	if v := q.Get("to"); v != "" {
+		t, err := parseFilterTime(v, true)
+		if err != nil {
+			return filter, domain.Validation("INVALID_FILTER", fmt.Sprintf("invalid to: %v", err))
+		}
+		filter.To = &t
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime accepts RFC3339 or a plain date; a plain `to` date covers the whole day
+func parseFilterTime(v string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// GetAnalytics handles GET /api/trades/analytics?userId=xxx&period=7d|30d|90d|1y|all
+// Accepts the same filters as GetHistory. include=autoscalp adds the user's
+// auto scalp results, counting positions linked to a journal entry only once.
+func (h *TradeHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	from := journalPeriodStart(r.URL.Query().Get("period"), now)
+
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if filter.From == nil || filter.From.Before(from) {
+		filter.From = &from
+	}
+
+	entries := h.repo.GetEntryHistory(r.Context(), userID, filter)
+	if r.URL.Query().Get("include") == "autoscalp" {
+		entries = usecase.MergeAutoScalpEntries(entries, h.autoScalpRepo.GetHistory(r.Context(), userID, from), filter)
+	}
+	analytics := usecase.ComputeTradeAnalytics(entries, from, now)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analytics)
+}
+
+// ImportFromBinance handles POST /api/trades/import
+// Body: {"userId": "...", "days": 7} (days defaults to 7, max 90)
+func (h *TradeHandler) ImportFromBinance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		UserID string `json:"userId"`
+		Days   int    `json:"days"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.UserID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+	if req.Days <= 0 {
+		req.Days = 7
+	}
+	if req.Days > usecase.MaxTradeImportDays {
+		http.Error(w, fmt.Sprintf("days must be at most %d", usecase.MaxTradeImportDays), http.StatusBadRequest)
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -req.Days)
+	result, err := h.importer.Import(r.Context(), req.UserID, from, to)
+	if err != nil {
+		writeUpstreamError(w, fmt.Errorf("import failed: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetEquityCurve handles GET /api/trades/equity?userId=xxx&period=7d|30d|90d|1y|all
+// Returns cumulative P/L and max drawdown of closed trades (same filters as GetHistory).
+// include=autoscalp merges auto scalp results like GetAnalytics.
+func (h *TradeHandler) GetEquityCurve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	from := journalPeriodStart(r.URL.Query().Get("period"), time.Now())
+	if filter.From == nil || filter.From.Before(from) {
+		filter.From = &from
+	}
+
+	entries := h.repo.GetEntryHistory(r.Context(), userID, filter)
+	if r.URL.Query().Get("include") == "autoscalp" {
+		entries = usecase.MergeAutoScalpEntries(entries, h.autoScalpRepo.GetHistory(r.Context(), userID, *filter.From), filter)
+	}
+	curve := usecase.BuildEquityCurve(usecase.TradeEntryEquityPoints(entries))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(curve)
+}
+
+// GetCalendar handles GET /api/trades/calendar?userId=xxx&month=YYYY-MM
+// Returns realized P/L per UTC day of the month (default: current month),
+// combining the user's journal and auto scalp results; linked positions count once.
+func (h *TradeHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if v := r.URL.Query().Get("month"); v != "" {
+		t, err := time.Parse("2006-01", v)
+		if err != nil {
+			http.Error(w, "Invalid month (expected YYYY-MM)", http.StatusBadRequest)
+			return
+		}
+		monthStart = t
+	}
+
+	filter := domain.TradeHistoryFilter{From: &monthStart}
+	entries := h.repo.GetEntryHistory(r.Context(), userID, filter)
+	entries = usecase.MergeAutoScalpEntries(entries, h.autoScalpRepo.GetHistory(r.Context(), userID, monthStart), filter)
+	calendar := usecase.ComputeTradeCalendar(entries, monthStart)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(calendar)
+}
+
+// GetDailySummaries handles GET /api/trades/summaries?userId=xxx&limit=30
+func (h *TradeHandler) GetDailySummaries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	limit := 30
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 365 {
+			http.Error(w, "Invalid limit (1-365)", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.summaries.GetSummaries(r.Context(), userID, limit))
+}
+
+// checkAutoScalpLink verifies that autoID exists, belongs to the trade's user
+// (another user's entry is reported as not found) and isn't already managed
+// by another journal entry
+func (h *TradeHandler) checkAutoScalpLink(ctx context.Context, trade *domain.TradeEntry, autoID string) error {
+	auto, err := h.autoScalpRepo.GetEntryByID(ctx, autoID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && auto.UserID != trade.UserID) {
+		return domain.Validation("INVALID_AUTOSCALP_LINK", fmt.Sprintf("auto scalp entry %s not found", autoID))
+	}
+	if err != nil {
+		return err
+	}
+	if auto.TradeEntryID != "" && auto.TradeEntryID != trade.ID {
+		return domain.Conflict("AUTOSCALP_ALREADY_LINKED", fmt.Sprintf("auto scalp entry %s is already linked to trade %s", autoID, auto.TradeEntryID))
+	}
+	return nil
+}
+
+// setAutoScalpLink records the back-reference on the auto scalp entry ("" clears it).
+// It only touches the link, so the monitor's concurrent updates can't undo it.
+func (h *TradeHandler) setAutoScalpLink(ctx context.Context, autoID, tradeID string) {
+	if err := h.autoScalpRepo.SetTradeLink(ctx, autoID, tradeID); err != nil {
+		log.Printf("Trade link: failed to update auto scalp entry %s: %v", autoID, err)
+	}
+}
+
+// journalPeriodStart maps ?period= to a start time (default 30 days, "all" = zero time)
+func journalPeriodStart(period string, now time.Time) time.Time {
+	switch period {
+	case "7d":
+		return now.AddDate(0, 0, -7)
+	case "90d":
+		return now.AddDate(0, 0, -90)
+	case "1y":
+		return now.AddDate(-1, 0, 0)
+	case "all":
+		return time.Time{}
+	default:
+		return now.AddDate(0, 0, -30)
+	}
 }
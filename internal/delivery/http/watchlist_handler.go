@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/usecase"
+)
+
+// WatchlistHandler manages the users' watchlists
+type WatchlistHandler struct {
+	watchlists *usecase.WatchlistService
+}
+
+// NewWatchlistHandler creates a new watchlist handler
+func NewWatchlistHandler(watchlists *usecase.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{watchlists: watchlists}
+}
+
+// GetWatchlist handles GET /api/watchlist?userId=
+func (h *WatchlistHandler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.watchlists.List(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// SaveItem handles POST /api/watchlist with body {"userId": "...", "symbol":
+// "SOLUSDT", "alertScore": 30, "breakoutAlertScore": 50, "note": "..."}. It
+// adds the symbol or replaces its thresholds; omitted scores leave the symbol
+// to the global alerts.
+func (h *WatchlistHandler) SaveItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var item domain.WatchlistItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if item.UserID == "" {
+		item.UserID = r.URL.Query().Get("userId")
+	}
+	if item.UserID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.watchlists.Save(r.Context(), &item); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// DeleteItem handles DELETE /api/watchlist/{symbol}?userId=
+func (h *WatchlistHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+	if userID == "" {
+		http.Error(w, "Missing userId", http.StatusBadRequest)
+		return
+	}
+	if err := h.watchlists.Remove(r.Context(), userID, r.PathValue("symbol")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"deleted"}`))
+}
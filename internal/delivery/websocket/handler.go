@@ -1,11 +1,20 @@
 package websocket
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	httphandler "screener-backend/internal/delivery/http"
 	"screener-backend/internal/domain"
+	"screener-backend/internal/usecase"
 
 	"github.com/gorilla/websocket"
 )
@@ -18,47 +27,456 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+const (
+	writeWait = 10 * time.Second
+	// pingPeriod stays under Heroku's 55s idle connection limit
+	pingPeriod = 30 * time.Second
+	pongWait   = 2 * pingPeriod
+	// sendBuffer is how many messages a client may fall behind before it is
+	// dropped; it reconnects and gets a fresh snapshot
+	sendBuffer = 16
+	// maxMessageSize bounds a client message; subscriptions are the only
+	// messages clients send
+	maxMessageSize       = 16 << 10
+	maxSubscribedSymbols = 500
+)
+
+// Messages of subscribed clients
+const (
+	msgCoinsSnapshot = "coins.snapshot"
+	msgCoinsDelta    = "coins.delta"
+	msgError         = "error"
+)
+
+// Watchlists looks up the symbols on a user's watchlist
+type Watchlists interface {
+	WatchedSymbols(ctx context.Context, userID string) map[string]bool
+}
+
+// Profiles weights coins' reversal scores by a user's scoring profile
+type Profiles interface {
+	Rescore(ctx context.Context, userID string, coins []domain.CoinData) ([]domain.CoinData, error)
+}
+
+// Handler pushes every event delivered through the event bus to the
+// WebSocket clients of this instance
 type Handler struct {
-	repo domain.ScreenerRepository
+	repo       domain.ScreenerRepository
+	watchlists Watchlists
+	profiles   Profiles
+
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	latest  []byte // coins payload of the last event, for new clients
+
+	closing   chan struct{} // closed by Close
+	closeOnce sync.Once
+}
+
+type client struct {
+	conn    *websocket.Conn
+	topics  map[string]bool
+	userID  string // coin lists are scored by their profile, watchlisted coins first
+	owner   string // the user of the connection's bearer token; gets their autoscalp events
+	send    chan []byte
+	closing <-chan struct{}
+
+	// Set by a subscription, under Handler.mu. filter is nil for clients
+	// getting the full coin lists; sent is the JSON of each coin last sent
+	// to a subscribed client, by symbol.
+	filter *usecase.CoinFilter
+	sent   map[string][]byte
+}
+
+// subscription is what a client sends to get only some coins:
+// {"symbols":["BTCUSDT"],"minScore":70,"strategies":["breakout"]}. No
+// symbols matches every symbol; no strategies means reversal.
+type subscription struct {
+	Symbols    []string `json:"symbols"`
+	MinScore   float64  `json:"minScore"`
+	Strategies []string `json:"strategies"`
 }
 
-func NewHandler(repo domain.ScreenerRepository) *Handler {
+// coinsMessage is a subscribed client's coins: every matching coin in a
+// snapshot, then per cycle the coins that changed or started to match and the
+// symbols that stopped matching
+type coinsMessage struct {
+	Type    string            `json:"type"`
+	Coins   []json.RawMessage `json:"coins,omitempty"`
+	Updated []json.RawMessage `json:"updated,omitempty"`
+	Removed []string          `json:"removed,omitempty"`
+}
+
+// coinCycle is a coins payload decoded once for all subscribed clients
+type coinCycle struct {
+	raw   []json.RawMessage
+	coins []domain.CoinData
+}
+
+func NewHandler(repo domain.ScreenerRepository, watchlists Watchlists, profiles Profiles) *Handler {
 	return &Handler{
-		repo: repo,
+		repo:       repo,
+		watchlists: watchlists,
+		profiles:   profiles,
+		clients:    make(map[*client]struct{}),
+		closing:    make(chan struct{}),
 	}
 }
 
+// Close says goodbye to every client on shutdown: they get a going away close
+// frame and reconnect, to another instance if there is one
+func (h *Handler) Close() {
+	h.closeOnce.Do(func() { close(h.closing) })
+}
+
+// Handle serves /ws?topics=coins,autoscalp&userId=. Clients get the coin list
+// on connect and after every screening cycle; autoscalp events only when
+// asked for, and then only the events of the token's user, so that topic
+// needs a bearer token. topics defaults to coins. With userId the reversal
+// scores are weighted by that user's scoring profile and the coins on their
+// watchlist lead each coin list. A client that sends a
+// subscription gets only the matching coins from then on, as deltas.
 func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	topics := parseTopics(r.URL.Query().Get("topics"))
+	owner := httphandler.AuthenticatedUser(r.Context())
+	if topics[domain.TopicAutoScalp] && owner == "" {
+		http.Error(w, "The autoscalp topic requires a bearer token", http.StatusUnauthorized)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	defer conn.Close()
 
+	c := &client{conn: conn, topics: topics, userID: r.URL.Query().Get("userId"), owner: owner, send: make(chan []byte, sendBuffer), closing: h.closing}
 	log.Println("New Client Connected")
 
 	// Send initial data immediately
-	coins := h.repo.GetCoins()
-	if err := conn.WriteJSON(coins); err != nil {
-		log.Println("Write error:", err)
+	if c.topics[domain.TopicCoins] {
+		initial, err := h.snapshot()
+		if err != nil {
+			log.Println("Write error:", err)
+			conn.Close()
+			return
+		}
+		c.send <- h.coinsFor(c.userID, initial)
+	}
+
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+
+	go c.writeLoop()
+	c.readLoop(func(msg []byte) { h.subscribe(c, msg) })
+
+	h.mu.Lock()
+	delete(h.clients, c)
+	close(c.send)
+	h.mu.Unlock()
+}
+
+// Dispatch forwards an event to the clients subscribed to its topic
+func (h *Handler) Dispatch(topic string, payload []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if topic == domain.TopicCoins {
+		h.latest = payload
+	}
+	perUser := make(map[string][]byte) // coin lists as a user sees them
+	// The coins decoded for the first subscribed client of each user
+	cycles := make(map[string]*coinCycle)
+	// An autoscalp event goes to its entry's owner only
+	var owner string
+	if topic == domain.TopicAutoScalp {
+		var event domain.AutoScalpEvent
+		if err := json.Unmarshal(payload, &event); err != nil || event.Entry == nil || event.Entry.UserID == "" {
+			return
+		}
+		owner = event.Entry.UserID
+	}
+	for c := range h.clients {
+		if !c.topics[topic] || (owner != "" && c.owner != owner) {
+			continue
+		}
+		msg := payload
+		if topic == domain.TopicCoins {
+			if c.userID != "" {
+				var ok bool
+				if msg, ok = perUser[c.userID]; !ok {
+					msg = h.coinsFor(c.userID, payload)
+					perUser[c.userID] = msg
+				}
+			}
+			if c.filter != nil {
+				cycle, ok := cycles[c.userID]
+				if !ok {
+					var err error
+					if cycle, err = decodeCoinCycle(msg); err != nil {
+						log.Println("Decoding coins for subscribed clients failed:", err)
+					}
+					cycles[c.userID] = cycle
+				}
+				if cycle == nil {
+					continue
+				}
+				if msg = c.delta(cycle); msg == nil {
+					continue
+				}
+			}
+		}
+		c.enqueue(msg)
+	}
+}
+
+// subscribe applies a client's subscription and sends it a snapshot of the
+// matching coins, or an error message for an invalid subscription
+func (h *Handler) subscribe(c *client, msg []byte) {
+	var sub subscription
+	if err := json.Unmarshal(msg, &sub); err != nil {
+		h.sendError(c, "invalid subscription")
 		return
 	}
+	if len(sub.Symbols) > maxSubscribedSymbols {
+		h.sendError(c, fmt.Sprintf("at most %d symbols", maxSubscribedSymbols))
+		return
+	}
+	filter, err := usecase.NewCoinFilter(sub.Symbols, sub.Strategies, sub.MinScore)
+	if err != nil {
+		h.sendError(c, err.Error())
+		return
+	}
+
+	// The snapshot comes from the latest event under the lock, so no cycle
+	// dispatched meanwhile is missed; the repository only before any event
+	fallback, err := h.snapshot()
+	if err != nil {
+		h.sendError(c, "loading coins failed")
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	payload := h.latest
+	if payload == nil {
+		payload = fallback
+	}
+	cycle, err := decodeCoinCycle(h.coinsFor(c.userID, payload))
+	if err != nil {
+		log.Println("Decoding coins for a subscription failed:", err)
+		c.enqueue(errorMessage("loading coins failed"))
+		return
+	}
+	c.filter = filter
+	c.topics[domain.TopicCoins] = true
+	c.sent = make(map[string][]byte)
+	snapshot := coinsMessage{Type: msgCoinsSnapshot, Coins: []json.RawMessage{}}
+	for i := range cycle.coins {
+		if filter.Match(&cycle.coins[i]) {
+			snapshot.Coins = append(snapshot.Coins, cycle.raw[i])
+			c.sent[cycle.coins[i].Symbol] = cycle.raw[i]
+		}
+	}
+	out, _ := json.Marshal(snapshot)
+	c.enqueue(out)
+}
 
-	ticker := time.NewTicker(5 * time.Second) // Poll every 5 seconds to match update cycle broadly
-	defer ticker.Stop()
+func (h *Handler) sendError(c *client, message string) {
+	h.mu.Lock()
+	c.enqueue(errorMessage(message))
+	h.mu.Unlock()
+}
+
+func errorMessage(message string) []byte {
+	out, _ := json.Marshal(map[string]string{"type": msgError, "error": message})
+	return out
+}
+
+// enqueue queues msg for the client, dropping a client that fell too far
+// behind. Callers hold h.mu, so the send channel is still open.
+func (c *client) enqueue(msg []byte) {
+	select {
+	case c.send <- msg:
+	default:
+		log.Println("WebSocket client too slow; disconnecting")
+		c.conn.Close()
+	}
+}
+
+// delta is a subscribed client's message for a cycle, nil when none of its
+// coins changed
+func (c *client) delta(cycle *coinCycle) []byte {
+	current := make(map[string][]byte, len(c.sent))
+	msg := coinsMessage{Type: msgCoinsDelta}
+	for i := range cycle.coins {
+		if !c.filter.Match(&cycle.coins[i]) {
+			continue
+		}
+		symbol, raw := cycle.coins[i].Symbol, cycle.raw[i]
+		current[symbol] = raw
+		if !bytes.Equal(c.sent[symbol], raw) {
+			msg.Updated = append(msg.Updated, raw)
+		}
+	}
+	for symbol := range c.sent {
+		if _, ok := current[symbol]; !ok {
+			msg.Removed = append(msg.Removed, symbol)
+		}
+	}
+	c.sent = current
+	if len(msg.Updated) == 0 && len(msg.Removed) == 0 {
+		return nil
+	}
+	sort.Strings(msg.Removed)
+	out, _ := json.Marshal(msg)
+	return out
+}
+
+func decodeCoinCycle(payload []byte) (*coinCycle, error) {
+	cycle := &coinCycle{}
+	if err := json.Unmarshal(payload, &cycle.raw); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(payload, &cycle.coins); err != nil {
+		return nil, err
+	}
+	return cycle, nil
+}
+
+// snapshot is the latest coin list: from the last event, or the repository
+// before any arrived
+func (h *Handler) snapshot() ([]byte, error) {
+	h.mu.RLock()
+	latest := h.latest
+	h.mu.RUnlock()
+	if latest != nil {
+		return latest, nil
+	}
+	return json.Marshal(h.repo.GetCoins())
+}
+
+// coinsFor is a coins payload as the user sees it: scored by their scoring
+// profile, with the coins on their watchlist moved to the front, keeping the
+// score order within both groups
+func (h *Handler) coinsFor(userID string, payload []byte) []byte {
+	if userID == "" {
+		return payload
+	}
+	payload = h.rescore(userID, payload)
+	if h.watchlists == nil {
+		return payload
+	}
+	watched := h.watchlists.WatchedSymbols(context.Background(), userID)
+	if len(watched) == 0 {
+		return payload
+	}
+
+	var coins []json.RawMessage
+	var symbols []struct {
+		Symbol string `json:"symbol"`
+	}
+	if json.Unmarshal(payload, &coins) != nil || json.Unmarshal(payload, &symbols) != nil {
+		return payload
+	}
+	ordered := make([]json.RawMessage, 0, len(coins))
+	for i, coin := range coins {
+		if watched[symbols[i].Symbol] {
+			ordered = append(ordered, coin)
+		}
+	}
+	for i, coin := range coins {
+		if !watched[symbols[i].Symbol] {
+			ordered = append(ordered, coin)
+		}
+	}
+	out, err := json.Marshal(ordered)
+	if err != nil {
+		return payload
+	}
+	return out
+}
+
+// rescore weights a coins payload's reversal scores by the user's scoring
+// profile; the payload as it is when that fails
+func (h *Handler) rescore(userID string, payload []byte) []byte {
+	if h.profiles == nil {
+		return payload
+	}
+	var coins []domain.CoinData
+	if err := json.Unmarshal(payload, &coins); err != nil {
+		return payload
+	}
+	rescored, err := h.profiles.Rescore(context.Background(), userID, coins)
+	if err != nil {
+		log.Printf("Scoring profile of %s not applied: %v", userID, err)
+		return payload
+	}
+	out, err := json.Marshal(rescored)
+	if err != nil {
+		return payload
+	}
+	return out
+}
+
+// writeLoop sends queued messages and keeps the connection alive with pings
+func (c *client) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
 
 	for {
 		select {
-		case <-ticker.C:
-			// Fetch latest
-			currentCoins := h.repo.GetCoins()
-			// Optimizaion: Diff? Or just send all.
-			// Send all for now.
-			if err := conn.WriteJSON(currentCoins); err != nil {
+		case msg, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
 				log.Println("Write error:", err)
 				return
 			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.closing:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+			return
+		}
+	}
+}
+
+// readLoop hands client messages to onMessage and returns once the
+// connection is gone
+func (c *client) readLoop(onMessage func(msg []byte)) {
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return
 		}
+		onMessage(msg)
+	}
+}
+
+func parseTopics(raw string) map[string]bool {
+	topics := make(map[string]bool)
+	for _, t := range strings.Split(raw, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			topics[t] = true
+		}
+	}
+	if len(topics) == 0 {
+		topics[domain.TopicCoins] = true
 	}
+	return topics
 }
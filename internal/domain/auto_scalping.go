@@ -1,10 +1,14 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // AutoScalpEntry represents an auto scalping trade
 type AutoScalpEntry struct {
 	ID               string     `json:"id"`
+	UserID           string     `json:"userId,omitempty"` // Whose settings manage it (empty for entries opened before settings were per user)
 	Symbol           string     `json:"symbol"`
 	EntryPrice       float64    `json:"entryPrice"`
 	StopLoss         float64    `json:"stopLoss"`
@@ -24,12 +28,17 @@ type AutoScalpEntry struct {
 	IsRealTrade      bool    `json:"isRealTrade"`              // Paper vs Real
 	BinanceOrderID   *int64  `json:"binanceOrderId,omitempty"` // Entry order ID
 	BinanceSLOrderID *int64  `json:"binanceSlOrderId,omitempty"` // Stop Loss order ID
+	BinanceTPOrderID *int64  `json:"binanceTpOrderId,omitempty"` // Take Profit order ID
 	Quantity         float64 `json:"quantity"`                 // Position size
 	Leverage         int     `json:"leverage"`                 // Leverage used
+
+	// Manual journal entry that took over this position (empty if none)
+	TradeEntryID string `json:"tradeEntryId,omitempty"`
 }
 
 // AutoScalpSettings represents user settings for auto scalping
 type AutoScalpSettings struct {
+	UserID               string  `json:"userId"`
 	Enabled              bool    `json:"enabled"`
 	MaxConcurrentTrades  int     `json:"maxConcurrentTrades"`
 	MinEntryScore        float64 `json:"minEntryScore"`      // Min score to enter (e.g., 70)
@@ -37,18 +46,41 @@ type AutoScalpSettings struct {
 	MinProfitPercent     float64 `json:"minProfitPercent"`   // Min profit to start trailing (e.g., 0.3%)
 	TrailingStopPercent  float64 `json:"trailingStopPercent"` // Trailing from peak (e.g., 0.2%)
 	MaxPositionTime      int     `json:"maxPositionTime"`    // Max seconds in position (e.g., 1800 = 30min)
+	TrailingMode         string  `json:"trailingMode"`       // "percent" (default) or "chandelier"
+	UpdatedAt            time.Time `json:"updatedAt"`
 }
 
+// Trailing stop modes for AutoScalpSettings.TrailingMode
+const (
+	TrailingModePercent    = "percent"    // exit on TrailingStopPercent retrace from peak
+	TrailingModeChandelier = "chandelier" // ratchet the stop down to the Chandelier Exit
+)
+
 // AutoScalpRepository defines auto scalp operations
 type AutoScalpRepository interface {
-	CreateEntry(entry *AutoScalpEntry) error
-	GetActiveEntries() []*AutoScalpEntry
-	GetEntryByID(id string) (*AutoScalpEntry, error)
-	UpdateEntry(entry *AutoScalpEntry) error
-	GetHistory(fromTime time.Time) []*AutoScalpEntry
-	DeleteEntry(id string) error
+	CreateEntry(ctx context.Context, entry *AutoScalpEntry) error
+	GetActiveEntries(ctx context.Context, userID string) []*AutoScalpEntry
+	GetAllActiveEntries(ctx context.Context) []*AutoScalpEntry // across all users, for background monitoring
+	GetEntryByID(ctx context.Context, id string) (*AutoScalpEntry, error)
+	UpdateEntry(ctx context.Context, entry *AutoScalpEntry) error // keeps the stored TradeEntryID
+	SetTradeLink(ctx context.Context, id, tradeEntryID string) error // "" clears it
+	GetHistory(ctx context.Context, userID string, fromTime time.Time) []*AutoScalpEntry
+	GetAllHistory(ctx context.Context, fromTime time.Time) []*AutoScalpEntry // across all users, for aggregate analytics
+	DeleteEntry(ctx context.Context, id string) error
 
 	// Binance integration helpers (best-effort for in-memory repo)
-	UpdateOrAttachBinanceOrders(symbol string, entryOrderID int64, slOrderID int64, qty float64, leverage int, filledPrice float64) error
-	RecordEmergencyStop(userID string, at time.Time, reason string) error
+	UpdateOrAttachBinanceOrders(ctx context.Context, userID string, symbol string, entryOrderID int64, slOrderID int64, tpOrderID int64, qty float64, leverage int, filledPrice float64) error
+	RecordEmergencyStop(ctx context.Context, userID string, at time.Time, reason string) error
+
+	// Retention: PruneHistory deletes closed entries that exited before the
+	// given time, except those linked to a journal trade
+	PruneHistory(ctx context.Context, before time.Time) error
+	PruneEmergencyStops(ctx context.Context, before time.Time) error
+
+	// Settings are kept per user. GetSettings returns ErrNotFound before the
+	// user's first save; ListEnabledSettings returns the settings of every
+	// user with auto scalping enabled.
+	GetSettings(ctx context.Context, userID string) (*AutoScalpSettings, error)
+	SaveSettings(ctx context.Context, settings *AutoScalpSettings) error
+	ListEnabledSettings(ctx context.Context) ([]*AutoScalpSettings, error)
 }
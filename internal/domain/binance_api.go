@@ -13,6 +13,34 @@ type BinanceAPICredentials struct {
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 	LastTested  time.Time `json:"lastTested"`
+
+	// Sub-account routing (optional). When SubAccountEmail is set, APIKey/SecretKey
+	// belong to the master account and are only used to query the sub-account's
+	// balances and positions. Orders are placed with the sub-account's own key pair
+	// because Binance does not let a master key trade on behalf of a sub-account.
+	SubAccountEmail     string `json:"subAccountEmail,omitempty"`
+	SubAccountAPIKey    string `json:"subAccountApiKey,omitempty"`
+	SubAccountSecretKey string `json:"subAccountSecretKey,omitempty"` // Will be encrypted in storage
+}
+
+// UsesSubAccount reports whether trades should be routed into a sub-account.
+func (c *BinanceAPICredentials) UsesSubAccount() bool {
+	return c.SubAccountEmail != ""
+}
+
+// TradingKeys returns the key pair that should sign order requests.
+func (c *BinanceAPICredentials) TradingKeys() (apiKey, secretKey string) {
+	if c.UsesSubAccount() && c.SubAccountAPIKey != "" && c.SubAccountSecretKey != "" {
+		return c.SubAccountAPIKey, c.SubAccountSecretKey
+	}
+	return c.APIKey, c.SecretKey
+}
+
+// BinanceSubAccount represents a sub-account listed under a master account
+type BinanceSubAccount struct {
+	Email      string `json:"email"`
+	IsFreeze   bool   `json:"isFreeze"`
+	CreateTime int64  `json:"createTime"`
 }
 
 // BinanceAccountInfo represents account balance and info
@@ -47,6 +75,30 @@ type BinancePosition struct {
 	Leverage         int     `json:"leverage"`
 }
 
+// BinanceUserTrade represents a single futures fill from /fapi/v1/userTrades
+type BinanceUserTrade struct {
+	ID              int64     `json:"id"`
+	OrderID         int64     `json:"orderId"`
+	Symbol          string    `json:"symbol"`
+	Side            string    `json:"side"`         // BUY or SELL
+	PositionSide    string    `json:"positionSide"` // BOTH (one-way mode), LONG or SHORT
+	Price           float64   `json:"price"`
+	Qty             float64   `json:"qty"`
+	RealizedPnl     float64   `json:"realizedPnl"`
+	Commission      float64   `json:"commission"` // always positive
+	CommissionAsset string    `json:"commissionAsset"`
+	Time            time.Time `json:"time"`
+}
+
+// BinanceIncome represents an entry of the futures income history (funding, realized P/L, ...)
+type BinanceIncome struct {
+	Symbol     string    `json:"symbol"`
+	IncomeType string    `json:"incomeType"` // e.g. FUNDING_FEE, REALIZED_PNL, COMMISSION
+	Income     float64   `json:"income"`
+	Asset      string    `json:"asset"`
+	Time       time.Time `json:"time"`
+}
+
 // BinanceTradingConfig represents trading configuration
 type BinanceTradingConfig struct {
 	UserID              string  `json:"userId"`
@@ -73,6 +125,7 @@ type BinanceOrderRequest struct {
 	Price        float64 `json:"price,omitempty"`
 	StopLoss     float64 `json:"stopLoss,omitempty"`
 	TakeProfit   float64 `json:"takeProfit,omitempty"`
+	ReduceOnly   bool    `json:"reduceOnly,omitempty"` // only reduce the position (one-way mode)
 }
 
 // BinanceOrderResponse represents the response from Binance after placing an order
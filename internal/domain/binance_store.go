@@ -1,16 +1,20 @@
 package domain
 
+import "context"
+
 // BinanceAPIStore abstracts storage for Binance credentials/config.
 // Implementations: in-memory (for dev) and Postgres (for production).
 //
 // Note: SecretKey is expected to be encrypted at rest by the implementation.
 type BinanceAPIStore interface {
-	SaveCredentials(cred *BinanceAPICredentials) error
-	GetCredentials(userID string) (*BinanceAPICredentials, error)
-	DeleteCredentials(userID string) error
+	SaveCredentials(ctx context.Context, cred *BinanceAPICredentials) error
+	GetCredentials(ctx context.Context, userID string) (*BinanceAPICredentials, error)
+	DeleteCredentials(ctx context.Context, userID string) error
+	// ListCredentials returns every user's credentials, decrypted (for backups)
+	ListCredentials(ctx context.Context) ([]*BinanceAPICredentials, error)
 
-	SaveTradingConfig(config *BinanceTradingConfig) error
-	GetTradingConfig(userID string) (*BinanceTradingConfig, error)
+	SaveTradingConfig(ctx context.Context, config *BinanceTradingConfig) error
+	GetTradingConfig(ctx context.Context, userID string) (*BinanceTradingConfig, error)
 
-	UpdateLastTested(userID string) error
+	UpdateLastTested(ctx context.Context, userID string) error
 }
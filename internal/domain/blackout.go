@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Kinds of blackout windows
+const (
+	BlackoutFOMC        = "FOMC"
+	BlackoutCPI         = "CPI"
+	BlackoutMaintenance = "MAINTENANCE" // exchange maintenance
+	BlackoutOther       = "OTHER"
+)
+
+// BlackoutKinds lists the accepted blackout kinds
+var BlackoutKinds = []string{BlackoutFOMC, BlackoutCPI, BlackoutMaintenance, BlackoutOther}
+
+// BlackoutWindow is a period of high event risk: the auto scalper opens no
+// new positions and notifications are tagged while it is active
+type BlackoutWindow struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"` // e.g. "FOMC rate decision"
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy"`
+}
+
+// Contains reports whether t falls in [Start, End)
+func (w *BlackoutWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// BlackoutRepository stores the blackout calendar
+type BlackoutRepository interface {
+	// SaveBlackout stores a new window and sets its ID
+	SaveBlackout(ctx context.Context, w *BlackoutWindow) error
+	// ListBlackouts returns the windows ending after endsAfter, earliest start first
+	ListBlackouts(ctx context.Context, endsAfter time.Time) ([]*BlackoutWindow, error)
+	DeleteBlackout(ctx context.Context, id int64) error
+}
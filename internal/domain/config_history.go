@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SettingDiff is one setting changed at runtime, values in their config
+// file form (e.g. "30s")
+type SettingDiff struct {
+	Path string `json:"path"` // e.g. screener.cciExtreme
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// ConfigChange is one applied change of the runtime settings (scan interval,
+// scoring thresholds, monitor intervals...)
+type ConfigChange struct {
+	ID         int64         `json:"id"`
+	Author     string        `json:"author"`
+	Source     string        `json:"source"` // api, reload or rollback
+	CreatedAt  time.Time     `json:"createdAt"`
+	Diff       []SettingDiff `json:"diff"`
+	RollbackOf *int64        `json:"rollbackOf,omitempty"` // change reverted by this one
+}
+
+// ConfigHistoryRepository keeps every runtime settings change
+type ConfigHistoryRepository interface {
+	// AppendConfigChange stores change and sets its ID
+	AppendConfigChange(ctx context.Context, change *ConfigChange) error
+	// ListConfigChanges returns the newest changes first
+	ListConfigChanges(ctx context.Context, limit int) ([]*ConfigChange, error)
+	GetConfigChange(ctx context.Context, id int64) (*ConfigChange, error)
+}
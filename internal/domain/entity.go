@@ -8,6 +8,8 @@ type MarketFeatures struct {
 	IsAboveUpperBand   bool     `json:"isAboveUpperBand"`
 	CandleRangeRatio   float64  `json:"candleRangeRatio"`
 	RSI                float64  `json:"rsi"`
+	CCI                float64  `json:"cci"`    // Commodity Channel Index (20)
+	ZScore             float64  `json:"zScore"` // close vs 20-candle mean, in standard deviations
 	IsRsiBearishDiv    bool     `json:"isRsiBearishDiv"`
 	RejectionWickRatio float64  `json:"rejectionWickRatio"`
 	FundingRate        float64  `json:"fundingRate"`
@@ -17,13 +19,78 @@ type MarketFeatures struct {
 	IsBreakdown        bool     `json:"isBreakdown"`
 	IsRetest           bool     `json:"isRetest"`
 	IsRetestFail       bool     `json:"isRetestFail"`
+	// Long/short ratios of the latest positioning.period; 0 when unknown
+	LongShortRatio          float64 `json:"longShortRatio"`          // all traders, by accounts
+	TopTraderLongShortRatio float64 `json:"topTraderLongShortRatio"` // top 20% traders, by position size
 	// Loss of Momentum indicators
 	HasRsiDivergence    bool    `json:"hasRsiDivergence"`    // Price HH, RSI LH
-	HasVolumeDivergence bool    `json:"hasVolumeDivergence"` // Price up, volume down
+	HasRsiBullishDiv    bool    `json:"hasRsiBullishDiv"`    // Price LL, RSI HL (long setups)
+	HasVolumeDivergence bool    `json:"hasVolumeDivergence"` // Price up, volume down (simple or OBV)
+	HasObvDivergence    bool    `json:"hasObvDivergence"`    // Price HH, OBV LH
 	MomentumSlope       float64 `json:"momentumSlope"`       // Rate of RSI change (negative = slowing)
 	RsiSlope            float64 `json:"rsiSlope"`            // RSI trend direction
 	VolumeDeclineRatio  float64 `json:"volumeDeclineRatio"`  // Current vs avg volume
 	IsLosingMomentum    bool    `json:"isLosingMomentum"`    // Combined momentum loss signal
+	MacdHistogram       float64 `json:"macdHistogram"`       // MACD(12,26,9) histogram
+	MacdContracting     bool    `json:"macdContracting"`     // Positive histogram shrinking
+	MacdCross           string  `json:"macdCross,omitempty"` // "BULLISH" / "BEARISH" signal cross (last 3 candles)
+	StochK              float64 `json:"stochK"`              // Stochastic RSI (14, 14, 3, 3) %K, 0-100
+	StochD              float64 `json:"stochD"`              // Stochastic RSI %D
+	StochCrossUp        bool    `json:"stochCrossUp"`        // %K crossed above %D from below 20 (last 3 candles)
+	// Ichimoku (9, 26, 52, 26); empty without enough history
+	CloudPosition string `json:"cloudPosition,omitempty"` // "ABOVE", "BELOW", "INSIDE"
+	IchimokuTrend string `json:"ichimokuTrend,omitempty"` // "BULLISH", "BEARISH", "NEUTRAL"
+	// Volatility squeeze: BB(20, 2) inside Keltner(20, ATR 14 x1.5)
+	IsSqueeze    bool `json:"isSqueeze"`
+	SqueezeBars  int  `json:"squeezeBars"`  // consecutive squeeze candles
+	SqueezeFired bool `json:"squeezeFired"` // squeeze released on the last candle
+	// Volatility regime: latest ATR% ranked against the last 100 readings
+	AtrPercentile    float64 `json:"atrPercentile"`
+	VolatilityRegime string  `json:"volatilityRegime,omitempty"` // "LOW", "NORMAL", "HIGH"
+	Choppiness       float64 `json:"choppiness"` // Choppiness Index (14): >61.8 ranging, <38.2 trending
+	// Directional movement (ADX 14); 0 without enough history
+	ADX       float64 `json:"adx"`       // trend strength: >25 trending, <20 chop
+	PlusDI    float64 `json:"plusDI"`    // +DI
+	MinusDI   float64 `json:"minusDI"`   // -DI
+	ADXRising bool    `json:"adxRising"` // ADX above its value 3 candles ago
+	// Chandelier Exit (22 candles, 3 x ATR 14); 0 without enough history
+	ChandelierLong  float64 `json:"chandelierLong"`  // highest high - 3 ATR
+	ChandelierShort float64 `json:"chandelierShort"` // lowest low + 3 ATR
+	// Taker flow from kline taker-buy columns (quote currency)
+	TakerDelta       float64 `json:"takerDelta"`       // last candle: taker buys - taker sells
+	TakerDeltaRatio  float64 `json:"takerDeltaRatio"`  // last 5 candles: net delta / quote volume (-1..1)
+	HasCvdDivergence bool    `json:"hasCvdDivergence"` // Price HH, cumulative delta LH (last 20 candles)
+	// Forced liquidations over screener.liquidationWindow (USDT); 0 without the feed
+	LongLiquidations     float64 `json:"longLiquidations"`
+	ShortLiquidations    float64 `json:"shortLiquidations"`
+	LiquidationImbalance float64 `json:"liquidationImbalance"` // (long - short) / total, -1..1; positive = longs flushed
+	LiquidationCascade   bool    `json:"liquidationCascade"`   // long liquidations accelerating in the last 5 minutes
+	// Volume profile (last 50 candles, 24 buckets, 70% value area)
+	POC            *float64 `json:"poc,omitempty"`
+	ValueAreaHigh  *float64 `json:"valueAreaHigh,omitempty"`
+	ValueAreaLow   *float64 `json:"valueAreaLow,omitempty"`
+	DistToPocATR   *float64 `json:"distToPocATR,omitempty"` // (close - POC) / ATR; positive = above POC
+	// Fibonacci retracement of the latest pivot-to-pivot impulse leg
+	FibLegUp    bool    `json:"fibLegUp"`
+	FibReaction float64 `json:"fibReaction"` // 0.382 / 0.5 / 0.618 when price reacts at that level, else 0
+	// Market structure from pivot swings (5/2)
+	StructureTrend     string          `json:"structureTrend,omitempty"` // "BULLISH" / "BEARISH" after the last break
+	LastSwingHigh      string          `json:"lastSwingHigh,omitempty"`  // "HH" / "LH"
+	LastSwingLow       string          `json:"lastSwingLow,omitempty"`   // "HL" / "LL"
+	LastStructureBreak *StructureBreak `json:"lastStructureBreak,omitempty"`
+}
+
+// StructureBreak is a close beyond the latest confirmed swing high/low.
+type StructureBreak struct {
+	Type      string  `json:"type"`      // "BOS" (continuation) or "CHOCH" (change of character)
+	Direction string  `json:"direction"` // "BULLISH" or "BEARISH"
+	Price     float64 `json:"price"`     // swing level that was broken
+	BarsAgo   int     `json:"barsAgo"`   // 0 = broken by the last candle
+}
+
+// IsRecent reports whether the break happened within the last `bars` candles in direction.
+func (b *StructureBreak) IsRecent(direction string, bars int) bool {
+	return b != nil && b.Direction == direction && b.BarsAgo < bars
 }
 
 // TimeframeScore stores score for a single timeframe.
@@ -31,6 +98,8 @@ type TimeframeScore struct {
 	TF    string  `json:"tf"`
 	Score float64 `json:"score"`
 	RSI   float64 `json:"rsi"`
+	// Components of a reversal score, for re-weighting by scoring profiles
+	Components *ScoreComponents `json:"components,omitempty"`
 }
 
 // TimeframeFeatures stores features per timeframe for display.
@@ -51,11 +120,13 @@ type CoinData struct {
 	TriggerTF          string              `json:"triggerTf,omitempty"`      // Primary TF that triggered
 	ConfluenceCount    int                 `json:"confluenceCount"`          // How many TFs aligned (1-3)
 	TFScores           []TimeframeScore    `json:"tfScores,omitempty"`       // Scores per TF
+	ScoreMultiplier    float64             `json:"scoreMultiplier,omitempty"` // Confluence and market context multiplier of the TF scores' average
 	TFFeatures         []TimeframeFeatures `json:"tfFeatures,omitempty"`     // Features per TF
 	PriceChangePercent float64             `json:"priceChangePercent"`
 	FundingRate        float64             `json:"fundingRate"`
 	BasisSpread        float64             `json:"basisSpread"`
 	Features           *MarketFeatures     `json:"features"`                 // Primary TF features
+	RegimeFilter       string              `json:"regimeFilter,omitempty"`   // "downweight" or "suppress" when the BTC regime filter acted on a signal
 	// Intraday Setup (15m + 1h analysis) - SHORT
 	IntradayStatus     string              `json:"intradayStatus,omitempty"` // "HOT", "WARM", "COOL"
 	IntradayScore      float64             `json:"intradayScore"`            // Score based on 15m + 1h
@@ -67,11 +138,12 @@ type CoinData struct {
 	PullbackTFScores   []TimeframeScore    `json:"pullbackTfScores,omitempty"`
 	PullbackFeatures   *MarketFeatures     `json:"pullbackFeatures,omitempty"`
 	// Breakout Hunter (15m + 1h) with Volume Spike confirmation - LONG & SHORT
-	BreakoutStatus     string              `json:"breakoutStatus,omitempty"` // "BREAKOUT_LONG", "BREAKOUT_SHORT", "TESTING_LONG", "TESTING_SHORT", "WAIT"
+	BreakoutStatus     string              `json:"breakoutStatus,omitempty"` // "BREAKOUT_LONG", "BREAKOUT_SHORT", "TESTING_LONG", "TESTING_SHORT", "WAIT", "SQUEEZE"
 	BreakoutDirection  string              `json:"breakoutDirection,omitempty"` // "LONG", "SHORT", ""
 	BreakoutScore      float64             `json:"breakoutScore"`
 	BreakoutTFScores   []TimeframeScore    `json:"breakoutTfScores,omitempty"`
 	BreakoutFeatures   *MarketFeatures     `json:"breakoutFeatures,omitempty"`
+	BreakoutSqueeze    string              `json:"breakoutSqueeze,omitempty"` // "COILING" (squeeze on), "FIRED" (just released), ""
 	// Follow Trend - Ikuti trend yang sedang kuat (LONG atau SHORT)
 	FollowTrendStatus    string              `json:"followTrendStatus,omitempty"` // "HOT", "STRONG", "MODERATE"
 	FollowTrendDirection string              `json:"followTrendDirection,omitempty"` // "LONG", "SHORT", ""
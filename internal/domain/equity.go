@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// EquityPoint is the account P/L after one closed trade
+type EquityPoint struct {
+	Time       time.Time `json:"time"`
+	TradeID    string    `json:"tradeId"`
+	Symbol     string    `json:"symbol"`
+	ProfitLoss float64   `json:"profitLoss"`
+	Equity     float64   `json:"equity"`   // cumulative P/L
+	Drawdown   float64   `json:"drawdown"` // distance below the running peak (>= 0)
+}
+
+// EquityCurve is the cumulative P/L series of closed trades with drawdown stats.
+// Shared by the manual journal and auto scalping so both can be compared.
+type EquityCurve struct {
+	Points         []EquityPoint `json:"points"`
+	NetProfit      float64       `json:"netProfit"`
+	PeakEquity     float64       `json:"peakEquity"`
+	MaxDrawdown    float64       `json:"maxDrawdown"`
+	MaxDrawdownPct *float64      `json:"maxDrawdownPct"` // relative to the peak; nil while the peak is <= 0
+	MaxDrawdownAt  *time.Time    `json:"maxDrawdownAt,omitempty"`
+}
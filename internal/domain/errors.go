@@ -0,0 +1,85 @@
+package domain
+
+import "errors"
+
+// Error kinds shared by repositories and usecases. Handlers map them to HTTP
+// statuses; match them with errors.Is.
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrValidation       = errors.New("validation failed")
+	ErrConflict         = errors.New("conflict")
+	ErrExchangeRejected = errors.New("exchange rejected the request")
+	ErrRiskLimit        = errors.New("risk limit exceeded")
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrForbidden        = errors.New("forbidden")
+)
+
+// Error is a classified error with a machine-readable code (e.g. TRADE_NOT_FOUND)
+// that API clients can rely on instead of the message text.
+type Error struct {
+	Kind    error  // one of the Err* kinds above
+	Code    string // stable, upper snake case
+	Message string
+	Err     error // optional underlying cause
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is / errors.As
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// NotFound builds an ErrNotFound error
+func NotFound(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+// Validation builds an ErrValidation error
+func Validation(code, message string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message}
+}
+
+// Conflict builds an ErrConflict error
+func Conflict(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// ExchangeRejected wraps an exchange error that the exchange itself refused
+func ExchangeRejected(code, message string, cause error) *Error {
+	return &Error{Kind: ErrExchangeRejected, Code: code, Message: message, Err: cause}
+}
+
+// RiskLimit builds an ErrRiskLimit error
+func RiskLimit(code, message string) *Error {
+	return &Error{Kind: ErrRiskLimit, Code: code, Message: message}
+}
+
+// Unauthorized builds an ErrUnauthorized error: the caller could not be
+// authenticated
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden builds an ErrForbidden error: the caller is authenticated but may
+// not act on the resource
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+// ErrorCode returns the code of the first *Error in err's chain, or "" if none
+func ErrorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
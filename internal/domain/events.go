@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// Topics broadcast to every instance's WebSocket clients
+const (
+	// TopicCoins carries the JSON array of coins of a finished screening cycle
+	TopicCoins = "coins"
+	// TopicAutoScalp carries an AutoScalpEvent
+	TopicAutoScalp = "autoscalp"
+)
+
+// EventPublisher broadcasts a payload to every running instance, this one
+// included
+type EventPublisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// AutoScalpEvent is sent when an auto scalp position opens or closes
+type AutoScalpEvent struct {
+	Type  string          `json:"type"`  // always "autoscalp", to tell it apart from coin arrays
+	Event string          `json:"event"` // opened or closed
+	Entry *AutoScalpEntry `json:"entry"`
+}
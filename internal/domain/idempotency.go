@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotentResponse is a stored HTTP response replayed for a retried request
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// IdempotencyRepository remembers responses by (scope, key) so retried
+// submissions are not executed twice. Keys expire after the given ttl.
+type IdempotencyRepository interface {
+	// Reserve claims the key for a new request. If the key was already used,
+	// reserved is false and stored holds the earlier response, or is nil while
+	// that request is still in flight. A reservation older than lease that
+	// never completed (its process died mid-request) is taken over.
+	Reserve(ctx context.Context, scope, key string, ttl, lease time.Duration) (stored *IdempotentResponse, reserved bool, err error)
+	Complete(ctx context.Context, scope, key string, resp *IdempotentResponse) error
+	Release(ctx context.Context, scope, key string) error // drop a reservation so the request can be retried
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Candle is one closed kline kept in the market archive
+type Candle struct {
+	OpenTime      time.Time `json:"openTime"`
+	Open          float64   `json:"open"`
+	High          float64   `json:"high"`
+	Low           float64   `json:"low"`
+	Close         float64   `json:"close"`
+	Volume        float64   `json:"volume"`
+	QuoteVolume   float64   `json:"quoteVolume"`
+	TakerBuyQuote float64   `json:"takerBuyQuote"`
+}
+
+// CoinSnapshot is a coin's screening result at one cycle
+type CoinSnapshot struct {
+	TakenAt time.Time `json:"takenAt"`
+	Coin    CoinData  `json:"coin"`
+}
+
+// ScreenerSnapshot is a coin's scores and statuses at one cycle, kept for
+// the top coins of every cycle so a score can be followed up to a trigger.
+// Rank is the coin's place in the cycle by its best score, from 1.
+type ScreenerSnapshot struct {
+	TakenAt           time.Time `json:"takenAt"`
+	Symbol            string    `json:"symbol"`
+	Rank              int       `json:"rank"`
+	Price             float64   `json:"price"`
+	Score             float64   `json:"score"`
+	Status            string    `json:"status"`
+	IntradayScore     float64   `json:"intradayScore"`
+	IntradayStatus    string    `json:"intradayStatus"`
+	PullbackScore     float64   `json:"pullbackScore"`
+	PullbackStatus    string    `json:"pullbackStatus"`
+	BreakoutScore     float64   `json:"breakoutScore"`
+	BreakoutStatus    string    `json:"breakoutStatus"`
+	FollowTrendScore  float64   `json:"followTrendScore"`
+	FollowTrendStatus string    `json:"followTrendStatus"`
+}
+
+// MarketArchiveRepository stores candles and screener snapshots over time so
+// backtests and history views don't have to refetch from Binance.
+type MarketArchiveRepository interface {
+	// SaveCandles upserts closed candles of symbol/interval
+	SaveCandles(ctx context.Context, symbol, interval string, candles []Candle) error
+	// GetCandles returns candles with open time in [from, to], oldest first
+	GetCandles(ctx context.Context, symbol, interval string, from, to time.Time) ([]Candle, error)
+	// SaveSnapshots stores one screening cycle
+	SaveSnapshots(ctx context.Context, takenAt time.Time, coins []CoinData) error
+	// GetSnapshots returns a symbol's snapshots in [from, to], newest first
+	GetSnapshots(ctx context.Context, symbol string, from, to time.Time, limit int) ([]CoinSnapshot, error)
+	// ListSnapshots returns the snapshots in [from, to] of symbol, or of every
+	// symbol when empty, oldest first
+	ListSnapshots(ctx context.Context, symbol string, from, to time.Time, limit int) ([]CoinSnapshot, error)
+	// PruneCandles deletes candles that opened before the given time
+	PruneCandles(ctx context.Context, before time.Time) error
+	// PruneSnapshots deletes snapshots taken before the given time
+	PruneSnapshots(ctx context.Context, before time.Time) error
+	// SaveScreenerSnapshots stores the top coins of one screening cycle
+	SaveScreenerSnapshots(ctx context.Context, snapshots []ScreenerSnapshot) error
+	// GetScreenerHistory returns the newest limit screener snapshots of a
+	// symbol in [from, to], oldest first
+	GetScreenerHistory(ctx context.Context, symbol string, from, to time.Time, limit int) ([]ScreenerSnapshot, error)
+	// PruneScreenerSnapshots deletes screener snapshots taken before the
+	// given time
+	PruneScreenerSnapshots(ctx context.Context, before time.Time) error
+}
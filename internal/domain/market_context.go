@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// MarketContext is the market-wide backdrop of the screener's signals
+type MarketContext struct {
+	BTCDominance          float64   `json:"btcDominance"` // percent of total market cap
+	ETHDominance          float64   `json:"ethDominance"`
+	TotalMarketCapUSD     float64   `json:"totalMarketCapUsd"`
+	MarketCapChange24hPct float64   `json:"marketCapChange24hPct"`
+	GlobalUpdatedAt       time.Time `json:"globalUpdatedAt"`
+	FearGreed             int       `json:"fearGreed"` // 0 extreme fear - 100 extreme greed
+	FearGreedLabel        string    `json:"fearGreedLabel"`
+	FearGreedUpdatedAt    time.Time `json:"fearGreedUpdatedAt"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// BTC market regimes, from both higher timeframes agreeing to both opposing
+const (
+	RegimeStrongUp  = "STRONG_UP" // 1h and 4h trending up with expanding ranges
+	RegimeUp        = "UP"
+	RegimeNeutral   = "NEUTRAL"
+	RegimeDown      = "DOWN"
+	RegimeBreakdown = "BREAKDOWN" // 1h and 4h trending down with expanding ranges
+)
+
+// Regime filter modes
+const (
+	RegimeFilterOff        = "off"
+	RegimeFilterDownweight = "downweight"
+	RegimeFilterSuppress   = "suppress"
+)
+
+// MarketRegime is the state of the market leader (BTCUSDT) that altcoin
+// signals are judged against
+type MarketRegime struct {
+	Symbol     string            `json:"symbol"`
+	Trend      string            `json:"trend"`      // one of the Regime* constants
+	Volatility string            `json:"volatility"` // 1h ATR regime: LOW, NORMAL or HIGH
+	Timeframes []RegimeTimeframe `json:"timeframes"`
+	DetectedAt time.Time         `json:"detectedAt"`
+}
+
+// RegimeTimeframe is the trend read on one timeframe
+type RegimeTimeframe struct {
+	TF    string  `json:"tf"`
+	Trend string  `json:"trend"` // UP, DOWN or NEUTRAL
+	Close float64 `json:"close"`
+	EMA20 float64 `json:"ema20"`
+	EMA50 float64 `json:"ema50"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CycleRecording is the raw market data one screening cycle read for a set of
+// symbols (klines, tickers, funding) with the settings and results of the
+// cycle, kept so the cycle can be replayed
+type CycleRecording struct {
+	ID        int64     `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	Symbols   []string  `json:"symbols"`
+	Data      []byte    `json:"-"` // gzip-compressed JSON, read by the replay runner
+}
+
+// CycleRecordingRepository stores recorded cycles
+type CycleRecordingRepository interface {
+	// SaveCycleRecording stores a recording, sets its ID and drops all but
+	// the newest keep recordings
+	SaveCycleRecording(ctx context.Context, r *CycleRecording, keep int) error
+	// ListCycleRecordings returns recordings started in [from, to] that hold
+	// symbol (any when empty), newest first and without Data
+	ListCycleRecordings(ctx context.Context, symbol string, from, to time.Time, limit int) ([]*CycleRecording, error)
+	GetCycleRecording(ctx context.Context, id int64) (*CycleRecording, error)
+}
@@ -1,6 +1,27 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 type ScreenerRepository interface {
 	SaveCoins(coins []CoinData)
 	GetCoins() []CoinData
 }
+
+// PriceCache keeps the latest known price per symbol
+type PriceCache interface {
+	SetPrices(prices map[string]float64)
+	GetPrice(symbol string) (float64, bool)
+}
+
+// CooldownStore persists when each alert key (e.g. "BTCUSDT", "BTCUSDT_BREAKOUT")
+// was last notified, so cooldowns survive restarts and deploys.
+type CooldownStore interface {
+	// LoadCooldowns returns keys notified at or after since
+	LoadCooldowns(ctx context.Context, since time.Time) (map[string]time.Time, error)
+	SaveCooldown(ctx context.Context, key string, notifiedAt time.Time) error
+	// PruneCooldowns drops keys last notified before the given time
+	PruneCooldowns(ctx context.Context, before time.Time) error
+}
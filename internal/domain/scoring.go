@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScoreWeights scale the components of the reversal score. 1 keeps a
+// component's default maximum (overextension 30, crowding 20, exhaustion 30,
+// structure 20, momentum 15).
+type ScoreWeights struct {
+	Overextension float64 `json:"overextension"`
+	Crowding      float64 `json:"crowding"`
+	Exhaustion    float64 `json:"exhaustion"`
+	Structure     float64 `json:"structure"`
+	Momentum      float64 `json:"momentum"`
+}
+
+// DefaultScoreWeights leave the reversal score as designed
+var DefaultScoreWeights = ScoreWeights{Overextension: 1, Crowding: 1, Exhaustion: 1, Structure: 1, Momentum: 1}
+
+// maxScoreWeight bounds a single component weight
+const maxScoreWeight = 5
+
+// ParseScoreWeights reads "overextension=1.2,exhaustion=0.8"; components
+// left out keep weight 1, and empty is the default
+func ParseScoreWeights(s string) (ScoreWeights, error) {
+	w := DefaultScoreWeights
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return w, fmt.Errorf("expected component=weight, got %q", part)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || v < 0 || v > maxScoreWeight {
+			return w, fmt.Errorf("weight of %s must be between 0 and %d, got %q", name, maxScoreWeight, value)
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "overextension":
+			w.Overextension = v
+		case "crowding":
+			w.Crowding = v
+		case "exhaustion":
+			w.Exhaustion = v
+		case "structure":
+			w.Structure = v
+		case "momentum":
+			w.Momentum = v
+		default:
+			return w, fmt.Errorf("unknown score component %q", name)
+		}
+	}
+	return w, nil
+}
+
+// Valid reports whether every weight is between 0 and maxScoreWeight and at
+// least one is above 0
+func (w ScoreWeights) Valid() bool {
+	positive := false
+	for _, v := range []float64{w.Overextension, w.Crowding, w.Exhaustion, w.Structure, w.Momentum} {
+		if v < 0 || v > maxScoreWeight {
+			return false
+		}
+		positive = positive || v > 0
+	}
+	return positive
+}
+
+// String formats the weights the way ParseScoreWeights reads them
+func (w ScoreWeights) String() string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	return "overextension=" + f(w.Overextension) + ",crowding=" + f(w.Crowding) + ",exhaustion=" + f(w.Exhaustion) +
+		",structure=" + f(w.Structure) + ",momentum=" + f(w.Momentum)
+}
+
+// ScoreComponents are the unweighted parts of the reversal score
+type ScoreComponents struct {
+	Overextension float64 `json:"overextension"` // 0-30
+	Crowding      float64 `json:"crowding"`      // 0-20
+	Exhaustion    float64 `json:"exhaustion"`    // 0-30
+	Structure     float64 `json:"structure"`     // 0-20
+	Momentum      float64 `json:"momentum"`      // 0-15
+}
+
+// Weighted sums the components scaled by w
+func (c ScoreComponents) Weighted(w ScoreWeights) float64 {
+	return c.Overextension*w.Overextension + c.Crowding*w.Crowding + c.Exhaustion*w.Exhaustion +
+		c.Structure*w.Structure + c.Momentum*w.Momentum
+}
+
+// ScoringProfile is a user's own weighting of the reversal score components
+type ScoringProfile struct {
+	UserID    string       `json:"userId"`
+	Weights   ScoreWeights `json:"weights"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// ScoringProfileRepository stores the users' scoring profiles
+type ScoringProfileRepository interface {
+	// GetScoringProfile returns the user's profile; ErrNotFound before the
+	// user saves one
+	GetScoringProfile(ctx context.Context, userID string) (*ScoringProfile, error)
+	// SaveScoringProfile adds or replaces the user's profile
+	SaveScoringProfile(ctx context.Context, p *ScoringProfile) error
+}
+
+// Scoring version statuses
+const (
+	ScoringShadow  = "shadow"  // evaluated alongside the live scoring, not used for signals
+	ScoringRetired = "retired" // no longer evaluated
+)
+
+// ScoringMetrics is how a scoring configuration's TRIGGER signals fared
+type ScoringMetrics struct {
+	Signals      int     `json:"signals"`
+	HitRate      float64 `json:"hitRate"`      // share of signals followed by a drop
+	AvgReturnPct float64 `json:"avgReturnPct"` // short-side return, positive = profitable
+}
+
+// ScoringVersion is a saved scoring configuration, e.g. an optimizer result
+// kept for shadow evaluation before it is applied
+type ScoringVersion struct {
+	ID           int64          `json:"id"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	Source       string         `json:"source"` // optimizer or manual
+	Status       string         `json:"status"`
+	Weights      ScoreWeights   `json:"weights"`
+	TriggerScore float64        `json:"triggerScore"`
+	Horizon      string         `json:"horizon"`     // forward-return horizon it was selected on
+	TestMetrics  ScoringMetrics `json:"testMetrics"` // out-of-sample result when saved
+	Note         string         `json:"note,omitempty"`
+}
+
+// ScoringVersionRepository stores scoring versions
+type ScoringVersionRepository interface {
+	// SaveScoringVersion stores a new version and sets its ID
+	SaveScoringVersion(ctx context.Context, v *ScoringVersion) error
+	// ListScoringVersions returns the newest versions first
+	ListScoringVersions(ctx context.Context, limit int) ([]*ScoringVersion, error)
+	GetScoringVersion(ctx context.Context, id int64) (*ScoringVersion, error)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ScreeningIntervals lists the kline intervals a strategy can be screened on
+var ScreeningIntervals = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h"}
+
+// StrategyConfig turns a strategy on or off and picks its two timeframes,
+// fastest first. The strategy scores each timeframe and looks for agreement
+// between them.
+type StrategyConfig struct {
+	Enabled    bool     `json:"enabled"`
+	Timeframes []string `json:"timeframes"`
+}
+
+// PullbackConfig configures the pullback strategy, which confirms the trend
+// on its setup timeframes and times the entry on its execution timeframes
+type PullbackConfig struct {
+	Enabled             bool     `json:"enabled"`
+	SetupTimeframes     []string `json:"setupTimeframes"`
+	ExecutionTimeframes []string `json:"executionTimeframes"`
+}
+
+// ScreenerConfig decides which strategies a screening cycle runs and on
+// which timeframes. Cycles pick up a saved config within a minute.
+type ScreenerConfig struct {
+	// Scalp is the 1m + 5m reversal analysis. Its timeframes give every coin
+	// its price and features, so they are screened even when it is disabled;
+	// disabled, it assigns no reversal status.
+	Scalp       StrategyConfig `json:"scalp"`
+	Intraday    StrategyConfig `json:"intraday"`
+	Pullback    PullbackConfig `json:"pullback"`
+	Breakout    StrategyConfig `json:"breakout"`
+	FollowTrend StrategyConfig `json:"followTrend"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	UpdatedBy   string         `json:"updatedBy,omitempty"`
+}
+
+// DefaultScreenerConfig is every strategy on its original timeframes
+func DefaultScreenerConfig() *ScreenerConfig {
+	return &ScreenerConfig{
+		Scalp:       StrategyConfig{Enabled: true, Timeframes: []string{"1m", "5m"}},
+		Intraday:    StrategyConfig{Enabled: true, Timeframes: []string{"15m", "1h"}},
+		Pullback:    PullbackConfig{Enabled: true, SetupTimeframes: []string{"5m", "15m"}, ExecutionTimeframes: []string{"1m", "3m"}},
+		Breakout:    StrategyConfig{Enabled: true, Timeframes: []string{"15m", "1h"}},
+		FollowTrend: StrategyConfig{Enabled: true, Timeframes: []string{"15m", "1h"}},
+	}
+}
+
+// ScreenerConfigRepository stores the screener config
+type ScreenerConfigRepository interface {
+	// GetScreenerConfig returns the saved config, ErrNotFound before the
+	// first save
+	GetScreenerConfig(ctx context.Context) (*ScreenerConfig, error)
+	SaveScreenerConfig(ctx context.Context, cfg *ScreenerConfig) error
+}
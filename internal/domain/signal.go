@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Screener strategies whose signals are tracked
+const (
+	StrategyTrigger  = "TRIGGER"  // short reversal (Status TRIGGER)
+	StrategyBreakout = "BREAKOUT" // confirmed breakout, either direction
+	StrategyDip      = "DIP"      // buy-the-dip pullback
+)
+
+// SignalHorizons are how long after a signal its outcome is measured
+var SignalHorizons = []SignalHorizon{
+	{Name: "5m", After: 5 * time.Minute},
+	{Name: "15m", After: 15 * time.Minute},
+	{Name: "1h", After: time.Hour},
+	{Name: "4h", After: 4 * time.Hour},
+}
+
+// SignalHorizon is one forward-return window
+type SignalHorizon struct {
+	Name  string
+	After time.Duration
+}
+
+// Signal is a coin entering a strategy's signal state, labeled with the
+// price change that followed
+type Signal struct {
+	ID         int64                    `json:"id"`
+	Symbol     string                   `json:"symbol"`
+	Strategy   string                   `json:"strategy"`
+	Direction  string                   `json:"direction"` // LONG or SHORT
+	Timeframe  string                   `json:"timeframe"` // strongest TF of the strategy; empty for older signals
+	Score      float64                  `json:"score"`
+	Price      float64                  `json:"price"`
+	SignaledAt time.Time                `json:"signaledAt"`
+	Outcomes   map[string]SignalOutcome `json:"outcomes"` // horizon name -> outcome
+	Labeled    bool                     `json:"labeled"`  // every horizon measured
+}
+
+// SignalOutcome is the price at one horizon. ReturnPct is signed in the
+// signal's direction: positive means the call was right.
+type SignalOutcome struct {
+	Price     float64 `json:"price"`
+	ReturnPct float64 `json:"returnPct"`
+}
+
+// SignalFilter narrows ListSignals; zero fields match everything
+type SignalFilter struct {
+	Strategy string
+	Symbol   string
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+// SignalRepository stores signals and their outcomes
+type SignalRepository interface {
+	// SaveSignal stores a new signal and sets its ID
+	SaveSignal(ctx context.Context, signal *Signal) error
+	// ListUnlabeledSignals returns signals still missing an outcome, oldest first
+	ListUnlabeledSignals(ctx context.Context, limit int) ([]*Signal, error)
+	// SaveOutcomes replaces a signal's outcomes and labeled flag
+	SaveOutcomes(ctx context.Context, id int64, outcomes map[string]SignalOutcome, labeled bool) error
+	// ListSignals returns matching signals, newest first
+	ListSignals(ctx context.Context, filter SignalFilter) ([]*Signal, error)
+	// PruneSignals deletes signals raised before the given time
+	PruneSignals(ctx context.Context, before time.Time) error
+}
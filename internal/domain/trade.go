@@ -1,10 +1,16 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // TradeEntry represents a trade position
 type TradeEntry struct {
 	ID            string    `json:"id"`
+	UserID        string    `json:"userId"`
 	Symbol        string    `json:"symbol"`
 	IsLong        bool      `json:"isLong"`
 	EntryPrice    float64   `json:"entryPrice"`
@@ -18,14 +24,322 @@ type TradeEntry struct {
 	ExitTime      *time.Time `json:"exitTime,omitempty"`
 	ProfitLoss    *float64  `json:"profitLoss,omitempty"`
 	EntryReason   string    `json:"entryReason"`
+	Notes         string    `json:"notes"`
+	Tags          []string  `json:"tags"`
+	Strategy      string    `json:"strategy"` // scalp, pullback, breakout, manual
+	RiskReward    float64   `json:"riskReward"` // planned reward:risk to the furthest target, computed on creation
+	Quantity      float64   `json:"quantity"`   // position size in base asset; 0 = unknown (legacy, P/L per 1 unit)
+	Leverage      int       `json:"leverage"`
+	Notional      float64   `json:"notional"`   // entry price * quantity (USDT)
+	Fees          float64   `json:"fees"`       // trading commissions paid (USDT)
+	Funding       float64   `json:"funding"`    // net funding received (+) or paid (-) while open (USDT)
+	Signal        *TradeSignalSnapshot `json:"signal,omitempty"` // screener state the trade was taken from
+	SettleAsset   string    `json:"settleAsset,omitempty"` // "" = USDT; a coin (e.g. BTC) marks a COIN-M entry: Quantity is contract value in USD, Fees/Funding are in the coin
+	ProfitLossNative *float64 `json:"profitLossNative,omitempty"` // COIN-M only: P/L in SettleAsset (ProfitLoss is its USD value at exit)
+	AutoScalpEntryID string `json:"autoScalpEntryId,omitempty"` // auto scalp position this trade manages, counted once in combined stats
+	ArchivedAt    *time.Time `json:"archivedAt,omitempty"` // soft-deleted; hidden from lists until restored
 }
 
-// TradeEntryRepository defines the interface for trade entry operations
+// TradeSignalSnapshot is a copy of the screener's CoinData at the moment a
+// trade was opened from a signal, kept for post-mortems.
+type TradeSignalSnapshot struct {
+	CapturedAt time.Time `json:"capturedAt"`
+	Coin       CoinData  `json:"coin"`
+}
+
+// TradeStrategies lists the accepted values for TradeEntry.Strategy
+var TradeStrategies = []string{"scalp", "pullback", "breakout", "manual"}
+
+// IsValidTradeStrategy reports whether s is one of TradeStrategies
+func IsValidTradeStrategy(s string) bool {
+	for _, v := range TradeStrategies {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// TradeEntryRepository defines the interface for trade entry operations.
+// Lookups are scoped to the owning user; UpdateEntry matches on entry.UserID.
+// Archived entries are only visible through GetArchivedEntries.
 type TradeEntryRepository interface {
-	CreateEntry(entry *TradeEntry) error
-	GetActiveEntries() []*TradeEntry
-	GetEntryByID(id string) (*TradeEntry, error)
-	UpdateEntry(entry *TradeEntry) error
-	GetEntryHistory() []*TradeEntry
-	DeleteEntry(id string) error
+	CreateEntry(ctx context.Context, entry *TradeEntry) error
+	GetActiveEntries(ctx context.Context, userID string) []*TradeEntry
+	GetAllActiveEntries(ctx context.Context) []*TradeEntry // across all users, for background monitoring
+	GetUserIDs(ctx context.Context) []string               // users with at least one journal entry
+	GetEntryByID(ctx context.Context, userID, id string) (*TradeEntry, error)
+	UpdateEntry(ctx context.Context, entry *TradeEntry) error
+	GetEntryHistory(ctx context.Context, userID string, filter TradeHistoryFilter) []*TradeEntry
+
+	ArchiveEntry(ctx context.Context, userID, id string) error
+	RestoreEntry(ctx context.Context, userID, id string) error
+	GetArchivedEntries(ctx context.Context, userID string) []*TradeEntry
+	PurgeEntry(ctx context.Context, userID, id string) error // permanent; only archived entries can be purged
+}
+
+// TradeHistoryFilter narrows history queries; zero values mean "no filter".
+// From/To apply to the exit time (entry time when no exit was recorded).
+type TradeHistoryFilter struct {
+	Symbol   string
+	Status   string
+	IsLong   *bool
+	Tag      string
+	Strategy string
+	From     *time.Time
+	To       *time.Time
+}
+
+// Matches reports whether the entry satisfies the filter
+func (f TradeHistoryFilter) Matches(e *TradeEntry) bool {
+	if f.Symbol != "" && !strings.EqualFold(e.Symbol, f.Symbol) {
+		return false
+	}
+	if f.Status != "" && e.Status != f.Status {
+		return false
+	}
+	if f.IsLong != nil && e.IsLong != *f.IsLong {
+		return false
+	}
+	if f.Tag != "" && !e.HasTag(f.Tag) {
+		return false
+	}
+	if f.Strategy != "" && e.Strategy != f.Strategy {
+		return false
+	}
+	at := e.EntryTime
+	if e.ExitTime != nil {
+		at = *e.ExitTime
+	}
+	if f.From != nil && at.Before(*f.From) {
+		return false
+	}
+	if f.To != nil && at.After(*f.To) {
+		return false
+	}
+	return true
+}
+
+// IsClosed reports whether the trade has reached a terminal status
+// (closed manually, stopped out, or final target hit).
+func (e *TradeEntry) IsClosed() bool {
+	return e.Status == "closed" || e.Status == "stopped" || e.Status == "tp3_hit"
+}
+
+// CalculateProfitLoss returns the net P/L in USD for exiting at exitPrice:
+// price move times quantity, minus fees, plus funding. Entries recorded
+// without a quantity fall back to a size of 1. COIN-M entries are settled in
+// the coin and valued at the exit price.
+func (e *TradeEntry) CalculateProfitLoss(exitPrice float64) float64 {
+	if e.IsCoinMargined() {
+		return e.nativeProfitLoss(exitPrice) * exitPrice
+	}
+	move := e.EntryPrice - exitPrice
+	if e.IsLong {
+		move = -move
+	}
+	return move*e.size() - e.Fees + e.Funding
+}
+
+// SettleProfitLoss sets ProfitLoss (USD) for exiting at exitPrice. native, when
+// given, is a P/L the user reported in the settlement asset instead of the
+// computed one. COIN-M entries also keep the coin amount in ProfitLossNative.
+func (e *TradeEntry) SettleProfitLoss(exitPrice float64, native *float64) {
+	if !e.IsCoinMargined() {
+		pl := e.CalculateProfitLoss(exitPrice)
+		if native != nil {
+			pl = *native
+		}
+		e.ProfitLoss = &pl
+		return
+	}
+
+	coin := e.nativeProfitLoss(exitPrice)
+	if native != nil {
+		coin = *native
+	}
+	usd := coin * exitPrice
+	e.ProfitLossNative = &coin
+	e.ProfitLoss = &usd
+}
+
+// IsCoinMargined reports whether the entry settles in a non-stable coin (COIN-M)
+func (e *TradeEntry) IsCoinMargined() bool {
+	return e.SettleAsset != "" && !IsUSDStable(e.SettleAsset)
+}
+
+// nativeProfitLoss is the COIN-M P/L in the coin: contract value times the
+// change of 1/price, minus fees, plus funding (both in the coin).
+func (e *TradeEntry) nativeProfitLoss(exitPrice float64) float64 {
+	if e.EntryPrice <= 0 || exitPrice <= 0 {
+		return 0
+	}
+	move := 1/e.EntryPrice - 1/exitPrice
+	if !e.IsLong {
+		move = -move
+	}
+	return move*e.size() - e.Fees + e.Funding
+}
+
+// riskAmount is the loss from entry to stop loss in the settlement asset
+func (e *TradeEntry) riskAmount() float64 {
+	if e.StopLoss <= 0 || e.EntryPrice <= 0 {
+		return 0
+	}
+	var risk float64
+	if e.IsCoinMargined() {
+		risk = 1/e.EntryPrice - 1/e.StopLoss
+	} else {
+		risk = e.EntryPrice - e.StopLoss
+	}
+	if risk < 0 {
+		risk = -risk
+	}
+	return risk * e.size()
+}
+
+// IsUSDStable reports whether asset is a USD stablecoin counted 1:1 with USD
+func IsUSDStable(asset string) bool {
+	switch strings.ToUpper(asset) {
+	case "USD", "USDT", "USDC", "BUSD", "FDUSD", "TUSD":
+		return true
+	}
+	return false
+}
+
+// size returns the position quantity, or 1 for legacy entries without one
+func (e *TradeEntry) size() float64 {
+	if e.Quantity > 0 {
+		return e.Quantity
+	}
+	return 1
+}
+
+// HasTag reports whether the entry carries the given tag (case-insensitive)
+func (e *TradeEntry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// RMultiple returns the realized P/L expressed in units of initial risk
+// (distance from entry to stop loss times quantity). ok is false while the
+// trade is open or when no stop loss was set. COIN-M entries are measured in
+// the coin so price moves of the coin itself don't skew R.
+func (e *TradeEntry) RMultiple() (r float64, ok bool) {
+	pl := e.ProfitLoss
+	if e.IsCoinMargined() {
+		pl = e.ProfitLossNative
+	}
+	risk := e.riskAmount()
+	if pl == nil || risk == 0 {
+		return 0, false
+	}
+	return *pl / risk, true
+}
+
+// Duration returns how long the trade was held; zero while still open.
+func (e *TradeEntry) Duration() time.Duration {
+	if e.ExitTime == nil {
+		return 0
+	}
+	return e.ExitTime.Sub(e.EntryTime)
+}
+
+// ValidateLevels checks that the stop loss and take profits sit on the
+// correct side of the entry for the trade direction and that targets are
+// ordered (TP1 closest to entry). An unset stop loss or take profit (0) is
+// skipped.
+func (e *TradeEntry) ValidateLevels() error {
+	if e.EntryPrice <= 0 {
+		return fmt.Errorf("entry price must be positive")
+	}
+	if e.StopLoss < 0 {
+		return fmt.Errorf("stop loss must not be negative")
+	}
+
+	side := "short"
+	if e.IsLong {
+		side = "long"
+	}
+	// beyond reports whether a is further in the profit direction than b
+	beyond := func(a, b float64) bool {
+		if e.IsLong {
+			return a > b
+		}
+		return a < b
+	}
+
+	if e.StopLoss > 0 && !beyond(e.EntryPrice, e.StopLoss) {
+		if e.IsLong {
+			return fmt.Errorf("%s trade: stop loss (%g) must be below entry price (%g)", side, e.StopLoss, e.EntryPrice)
+		}
+		return fmt.Errorf("%s trade: stop loss (%g) must be above entry price (%g)", side, e.StopLoss, e.EntryPrice)
+	}
+
+	prev := e.EntryPrice
+	prevName := "entry price"
+	for i, tp := range []float64{e.TakeProfit1, e.TakeProfit2, e.TakeProfit3} {
+		if tp == 0 {
+			continue
+		}
+		if tp < 0 || !beyond(tp, prev) {
+			dir := "above"
+			if !e.IsLong {
+				dir = "below"
+			}
+			return fmt.Errorf("%s trade: take profit %d (%g) must be %s %s (%g)", side, i+1, tp, dir, prevName, prev)
+		}
+		prev = tp
+		prevName = fmt.Sprintf("take profit %d", i+1)
+	}
+	return nil
+}
+
+// CalculateRiskReward returns the planned reward:risk ratio to the furthest
+// take profit that is set, or 0 when no target or stop loss is defined.
+func (e *TradeEntry) CalculateRiskReward() float64 {
+	target := e.TakeProfit3
+	if target == 0 {
+		target = e.TakeProfit2
+	}
+	if target == 0 {
+		target = e.TakeProfit1
+	}
+	if target == 0 || e.StopLoss <= 0 {
+		return 0
+	}
+
+	risk := e.EntryPrice - e.StopLoss
+	reward := target - e.EntryPrice
+	if !e.IsLong {
+		risk, reward = -risk, -reward
+	}
+	if risk <= 0 {
+		return 0
+	}
+	return reward / risk
+}
+
+// OpenRisk returns the USD lost if the trade is stopped out from here
+// (0 without a stop loss or once the stop is in profit). COIN-M losses are
+// valued at the stop price.
+func (e *TradeEntry) OpenRisk() float64 {
+	if e.StopLoss <= 0 {
+		return 0
+	}
+	inProfit := e.StopLoss >= e.EntryPrice
+	if !e.IsLong {
+		inProfit = e.StopLoss <= e.EntryPrice
+	}
+	if inProfit {
+		return 0
+	}
+	if e.IsCoinMargined() {
+		return e.riskAmount() * e.StopLoss
+	}
+	return e.riskAmount()
 }
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// TradeAnalytics summarizes journal performance over a period
+type TradeAnalytics struct {
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	TotalTrades  int       `json:"totalTrades"`
+	Wins         int       `json:"wins"`
+	Losses       int       `json:"losses"`
+	WinRate      float64   `json:"winRate"`    // percent
+	Expectancy   float64   `json:"expectancy"` // average P/L per trade
+	AverageR     float64   `json:"averageR"`   // over trades with a stop loss
+	GrossProfit  float64   `json:"grossProfit"`
+	GrossLoss    float64   `json:"grossLoss"` // positive number
+	NetProfit    float64   `json:"netProfit"`
+	ProfitFactor *float64  `json:"profitFactor"` // nil when there are no losing trades
+
+	BestSymbols  []TradeGroupStats `json:"bestSymbols"`
+	WorstSymbols []TradeGroupStats `json:"worstSymbols"`
+	ByWeekday    []TradeGroupStats `json:"byWeekday"` // by entry time, UTC
+	ByHour       []TradeGroupStats `json:"byHour"`    // by entry hour, UTC
+	ByStrategy   []TradeGroupStats `json:"byStrategy"`
+	ByTag        []TradeGroupStats `json:"byTag"`
+}
+
+// TradeGroupStats holds performance for one bucket (symbol, weekday, tag...)
+type TradeGroupStats struct {
+	Key       string  `json:"key"`
+	Trades    int     `json:"trades"`
+	WinRate   float64 `json:"winRate"`
+	NetProfit float64 `json:"netProfit"`
+	AverageR  float64 `json:"averageR"`
+}
+
+// TradeCalendar holds realized P/L per UTC day of one month (heatmap data)
+type TradeCalendar struct {
+	Month       string             `json:"month"` // YYYY-MM
+	Days        []TradeCalendarDay `json:"days"`  // every day of the month, in order
+	NetProfit   float64            `json:"netProfit"`
+	TotalTrades int                `json:"totalTrades"`
+	TradingDays int                `json:"tradingDays"` // days with at least one closed trade
+	BestDay     *TradeCalendarDay  `json:"bestDay,omitempty"`
+	WorstDay    *TradeCalendarDay  `json:"worstDay,omitempty"`
+}
+
+// TradeCalendarDay aggregates the trades closed on one day
+type TradeCalendarDay struct {
+	Date      string  `json:"date"` // YYYY-MM-DD
+	NetProfit float64 `json:"netProfit"`
+	Trades    int     `json:"trades"`
+	Wins      int     `json:"wins"`
+	Losses    int     `json:"losses"`
+}
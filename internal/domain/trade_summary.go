@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DailyTradeSummary is the end-of-day recap of a user's journal
+type DailyTradeSummary struct {
+	UserID       string    `json:"userId"`
+	Date         string    `json:"date"` // YYYY-MM-DD (UTC)
+	TradesTaken  int       `json:"tradesTaken"`
+	TradesClosed int       `json:"tradesClosed"`
+	NetPL        float64   `json:"netPL"`
+	WinRate      float64   `json:"winRate"` // percent, over trades closed that day
+	OpenTrades   int       `json:"openTrades"`
+	OpenRisk     float64   `json:"openRisk"` // loss if every open trade hits its stop (USDT)
+	Notified     bool      `json:"notified"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// DailySummaryRepository stores daily summaries (one per user per date)
+type DailySummaryRepository interface {
+	SaveSummary(ctx context.Context, summary *DailyTradeSummary) error
+	GetSummaries(ctx context.Context, userID string, limit int) []*DailyTradeSummary
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// User is an account registered with an email and password. Its ID is the
+// userId every user-scoped endpoint is keyed by.
+type User struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// UserRepository stores registered users. Emails are stored lower case and
+// are unique.
+type UserRepository interface {
+	// CreateUser stores a new user; a taken email is an ErrConflict
+	CreateUser(ctx context.Context, user *User) error
+	// GetUserByEmail returns ErrNotFound for an unknown email
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+}
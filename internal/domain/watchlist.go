@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WatchlistItem is a symbol a user follows closely: it is screened every
+// cycle, listed first in the user's WebSocket payloads and may alert the
+// user below the global notification thresholds
+type WatchlistItem struct {
+	UserID string `json:"userId"`
+	Symbol string `json:"symbol"`
+	// AlertScore notifies the user once the reversal score reaches it, even
+	// short of TRIGGER; nil leaves the symbol to the global TRIGGER alert
+	AlertScore *float64 `json:"alertScore,omitempty"`
+	// BreakoutAlertScore does the same for the breakout score, even short of
+	// a confirmed breakout
+	BreakoutAlertScore *float64  `json:"breakoutAlertScore,omitempty"`
+	Note               string    `json:"note"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// WatchlistRepository stores the users' watchlists. An item is keyed by its
+// user and symbol.
+type WatchlistRepository interface {
+	// ListWatchlist returns the user's items by symbol
+	ListWatchlist(ctx context.Context, userID string) ([]*WatchlistItem, error)
+	// ListAllWatchlists returns the items of every user, for the screener
+	ListAllWatchlists(ctx context.Context) ([]*WatchlistItem, error)
+	// SaveWatchlistItem adds the item or replaces the user's item for its
+	// symbol, keeping the original CreatedAt
+	SaveWatchlistItem(ctx context.Context, item *WatchlistItem) error
+	DeleteWatchlistItem(ctx context.Context, userID, symbol string) error
+}
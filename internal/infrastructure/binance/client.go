@@ -1,10 +1,14 @@
 package binance
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
+
+	"screener-backend/internal/domain"
 )
 
 const (
@@ -12,9 +16,15 @@ const (
 	SpotBaseURL = "https://api.binance.com"
 )
 
+// publicTimeout caps a market data request that the caller's context leaves
+// unbounded, so a stalled connection can't hold up a screening cycle
+const publicTimeout = 10 * time.Second
+
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	klines     *KlineCache   // nil: every GetKlines goes to Binance
+	stream     *MarketStream // nil: klines and tickers are polled over REST
 }
 
 func NewClient(baseURL string) *Client {
@@ -22,11 +32,17 @@ func NewClient(baseURL string) *Client {
 		baseURL = FapiBaseURL
 	}
 	return &Client{
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Timeout: publicTimeout, Transport: newInstrumentedTransport("public")},
 		baseURL:    baseURL,
 	}
 }
 
+// WithKlineCache serves GetKlines through cache (nil turns caching off)
+func (c *Client) WithKlineCache(cache *KlineCache) *Client {
+	c.klines = cache
+	return c
+}
+
 type Ticker24h struct {
 	Symbol             string `json:"symbol"`
 	PriceChangePercent string `json:"priceChangePercent"`
@@ -49,9 +65,27 @@ type PremiumIndex struct {
 	LastFundingRate string `json:"lastFundingRate"`
 }
 
+// OrderBook is a depth snapshot, best price first on both sides
+type OrderBook struct {
+	Bids []BookLevel
+	Asks []BookLevel
+}
+
+type BookLevel struct {
+	Price float64
+	Qty   float64
+}
+
+// OpenInterestPoint is the open interest at the end of one period
+type OpenInterestPoint struct {
+	Time  time.Time
+	Qty   float64 // contracts (base asset)
+	Value float64 // USDT
+}
+
 // GetActiveTradingSymbols returns symbols with status "TRADING" from Futures API.
-func (c *Client) GetActiveTradingSymbols() ([]string, error) {
-	resp, err := c.httpClient.Get(c.baseURL + "/fapi/v1/exchangeInfo")
+func (c *Client) GetActiveTradingSymbols(ctx context.Context) ([]string, error) {
+	resp, err := c.get(ctx, c.baseURL+"/fapi/v1/exchangeInfo")
 	if err != nil {
 		return nil, err
 	}
@@ -77,8 +111,22 @@ func (c *Client) GetActiveTradingSymbols() ([]string, error) {
 }
 
 // GetFutures24hrTicker returns 24hr statistics for all markets.
-func (c *Client) GetFutures24hrTicker() ([]Ticker24h, error) {
-	resp, err := c.httpClient.Get(c.baseURL + "/fapi/v1/ticker/24hr")
+func (c *Client) GetFutures24hrTicker(ctx context.Context) ([]Ticker24h, error) {
+	if c.stream == nil {
+		return c.fetchTickers(ctx)
+	}
+	if tickers, ok := c.stream.tickers24h(); ok {
+		return tickers, nil
+	}
+	tickers, err := c.fetchTickers(ctx)
+	if err == nil {
+		c.stream.seedTickers(tickers)
+	}
+	return tickers, err
+}
+
+func (c *Client) fetchTickers(ctx context.Context) ([]Ticker24h, error) {
+	resp, err := c.get(ctx, c.baseURL+"/fapi/v1/ticker/24hr")
 	if err != nil {
 		return nil, err
 	}
@@ -99,9 +147,31 @@ func (c *Client) GetFutures24hrTicker() ([]Ticker24h, error) {
 // Returns raw interface slice to keep it simple or we can parse to float per field.
 // Binance returns: [ [open_time, open, high, low, close, volume, ...], ... ]
 // All are nums or strings representing nums.
-func (c *Client) GetKlines(symbol, interval string, limit int) ([][]interface{}, error) {
+func (c *Client) GetKlines(ctx context.Context, symbol, interval string, limit int) ([][]interface{}, error) {
+	if c.stream == nil {
+		return c.getKlines(ctx, symbol, interval, limit)
+	}
+	if rows, ok := c.stream.klines(symbol, interval, limit); ok {
+		return rows, nil
+	}
+	rows, err := c.getKlines(ctx, symbol, interval, limit)
+	if err == nil {
+		c.stream.seedKlines(symbol, interval, rows)
+	}
+	return rows, err
+}
+
+// getKlines is GetKlines over REST, through the kline cache if there is one
+func (c *Client) getKlines(ctx context.Context, symbol, interval string, limit int) ([][]interface{}, error) {
+	if c.klines != nil {
+		return c.klines.get(ctx, symbol, interval, limit, c.fetchKlines)
+	}
+	return c.fetchKlines(ctx, symbol, interval, limit)
+}
+
+func (c *Client) fetchKlines(ctx context.Context, symbol, interval string, limit int) ([][]interface{}, error) {
 	url := fmt.Sprintf("%s/fapi/v1/klines?symbol=%s&interval=%s&limit=%d", c.baseURL, symbol, interval, limit)
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -119,9 +189,9 @@ func (c *Client) GetKlines(symbol, interval string, limit int) ([][]interface{},
 }
 
 // GetFundingRate returns the last funding rate for a symbol.
-func (c *Client) GetFundingRate(symbol string) (float64, error) {
+func (c *Client) GetFundingRate(ctx context.Context, symbol string) (float64, error) {
 	url := fmt.Sprintf("%s/fapi/v1/premiumIndex?symbol=%s", c.baseURL, symbol)
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return 0, err
 	}
@@ -139,3 +209,218 @@ func (c *Client) GetFundingRate(symbol string) (float64, error) {
 	rate, _ := strconv.ParseFloat(data.LastFundingRate, 64)
 	return rate, nil
 }
+
+// GetOrderBook returns the top limit levels of the order book
+// (5, 10, 20, 50, 100, 500 or 1000).
+func (c *Client) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	url := fmt.Sprintf("%s/fapi/v1/depth?symbol=%s&limit=%d", c.baseURL, symbol, limit)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance API error: %d", resp.StatusCode)
+	}
+
+	var data struct {
+		Bids [][2]string `json:"bids"`
+		Asks [][2]string `json:"asks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	levels := func(rows [][2]string) []BookLevel {
+		out := make([]BookLevel, 0, len(rows))
+		for _, r := range rows {
+			price, _ := strconv.ParseFloat(r[0], 64)
+			qty, _ := strconv.ParseFloat(r[1], 64)
+			out = append(out, BookLevel{Price: price, Qty: qty})
+		}
+		return out
+	}
+	return &OrderBook{Bids: levels(data.Bids), Asks: levels(data.Asks)}, nil
+}
+
+// GetOpenInterestHist returns the open interest of the last limit periods
+// (5m, 15m, 30m, 1h, 2h, 4h, 6h, 12h, 1d), oldest first. Binance keeps the
+// last 30 days.
+func (c *Client) GetOpenInterestHist(ctx context.Context, symbol, period string, limit int) ([]OpenInterestPoint, error) {
+	url := fmt.Sprintf("%s/futures/data/openInterestHist?symbol=%s&period=%s&limit=%d", c.baseURL, symbol, period, limit)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance API error: %d", resp.StatusCode)
+	}
+
+	var data []struct {
+		SumOpenInterest      string `json:"sumOpenInterest"`
+		SumOpenInterestValue string `json:"sumOpenInterestValue"`
+		Timestamp            int64  `json:"timestamp"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	points := make([]OpenInterestPoint, 0, len(data))
+	for _, d := range data {
+		qty, _ := strconv.ParseFloat(d.SumOpenInterest, 64)
+		value, _ := strconv.ParseFloat(d.SumOpenInterestValue, 64)
+		points = append(points, OpenInterestPoint{Time: time.UnixMilli(d.Timestamp), Qty: qty, Value: value})
+	}
+	return points, nil
+}
+
+// maxKlinesPerRequest is the largest page /fapi/v1/klines returns
+const maxKlinesPerRequest = 1500
+
+// GetKlinesBetween returns the candles of interval opened in [from, to),
+// oldest first, paging through as many requests as the range takes. The
+// cache and the streams are bypassed.
+func (c *Client) GetKlinesBetween(ctx context.Context, symbol, interval string, from, to time.Time) ([]domain.Candle, error) {
+	var candles []domain.Candle
+	for cursor := from; cursor.Before(to); {
+		url := fmt.Sprintf("%s/fapi/v1/klines?symbol=%s&interval=%s&startTime=%d&endTime=%d&limit=%d",
+			c.baseURL, symbol, interval, cursor.UnixMilli(), to.UnixMilli()-1, maxKlinesPerRequest)
+		resp, err := c.get(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		var klines [][]interface{}
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("binance API error: %d", resp.StatusCode)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(&klines)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		page := Candles(klines)
+		if len(page) == 0 {
+			break
+		}
+		candles = append(candles, page...)
+		next := page[len(page)-1].OpenTime.Add(time.Millisecond)
+		if len(page) < maxKlinesPerRequest || !next.After(cursor) {
+			break
+		}
+		cursor = next
+	}
+	return candles, nil
+}
+
+// GetPriceAt returns the close of the 1m candle containing t.
+func (c *Client) GetPriceAt(ctx context.Context, symbol string, t time.Time) (float64, error) {
+	url := fmt.Sprintf("%s/fapi/v1/klines?symbol=%s&interval=1m&startTime=%d&limit=1",
+		c.baseURL, symbol, t.Truncate(time.Minute).UnixMilli())
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("binance API error: %d", resp.StatusCode)
+	}
+
+	var klines [][]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&klines); err != nil {
+		return 0, err
+	}
+	if len(klines) == 0 || len(klines[0]) < 5 {
+		return 0, fmt.Errorf("no kline for %s at %s", symbol, t.Format(time.RFC3339))
+	}
+	closeStr, _ := klines[0][4].(string)
+	price, err := strconv.ParseFloat(closeStr, 64)
+	if err != nil || price <= 0 {
+		return 0, fmt.Errorf("invalid close price for %s", symbol)
+	}
+	return price, nil
+}
+
+// get issues a GET bound to ctx (cancellation, deadline and trace parent),
+// waiting first for the host's weight budget to cover it, and repeats it
+// after a transient failure
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	limiter := limiterFor(req.URL.Hostname())
+	weight := requestWeight(req.URL)
+	return retry(ctx, "public", req.URL.Path, func() (*http.Response, error) {
+		if err := limiter.wait(ctx, weight); err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			limiter.observe(resp)
+		}
+		return resp, err
+	})
+}
+
+// ClosedCandles converts raw klines to candles, dropping the still-forming
+// last candle (close time in the future).
+func ClosedCandles(klines [][]interface{}, now time.Time) []domain.Candle {
+	candles := make([]domain.Candle, 0, len(klines))
+	for _, k := range klines {
+		if len(k) < 11 {
+			continue
+		}
+		closeMs, _ := k[6].(float64)
+		if time.UnixMilli(int64(closeMs)).After(now) {
+			continue
+		}
+		candles = append(candles, klineCandle(k))
+	}
+	return candles
+}
+
+// Candles converts raw klines to candles, the still-forming last candle included.
+func Candles(klines [][]interface{}) []domain.Candle {
+	candles := make([]domain.Candle, 0, len(klines))
+	for _, k := range klines {
+		if len(k) < 11 {
+			continue
+		}
+		candles = append(candles, klineCandle(k))
+	}
+	return candles
+}
+
+// KlineRow is the REST kline of candle c lasting interval, as GetKlines
+// returns it. The trade count and taker buy base volume aren't kept on
+// candles and come out 0.
+func KlineRow(c domain.Candle, interval time.Duration) []interface{} {
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	openMs := c.OpenTime.UnixMilli()
+	return []interface{}{float64(openMs), num(c.Open), num(c.High), num(c.Low), num(c.Close), num(c.Volume),
+		float64(openMs + interval.Milliseconds() - 1), num(c.QuoteVolume), float64(0), "0", num(c.TakerBuyQuote), "0"}
+}
+
+func klineCandle(k []interface{}) domain.Candle {
+	openMs, _ := k[0].(float64)
+	return domain.Candle{
+		OpenTime:      time.UnixMilli(int64(openMs)).UTC(),
+		Open:          klineFloat(k[1]),
+		High:          klineFloat(k[2]),
+		Low:           klineFloat(k[3]),
+		Close:         klineFloat(k[4]),
+		Volume:        klineFloat(k[5]),
+		QuoteVolume:   klineFloat(k[7]),
+		TakerBuyQuote: klineFloat(k[10]),
+	}
+}
+
+func klineFloat(v interface{}) float64 {
+	s, _ := v.(string)
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
@@ -0,0 +1,194 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+// symbolFiltersTTL is how long the exchange's order rules are kept; Binance
+// changes a symbol's tick or step size rarely and announces it in advance
+const symbolFiltersTTL = time.Hour
+
+var (
+	ErrOrderBelowMinQty      = domain.Validation("ORDER_BELOW_MIN_QTY", "order quantity is below the symbol's minimum")
+	ErrOrderBelowMinNotional = domain.Validation("ORDER_BELOW_MIN_NOTIONAL", "order value is below the symbol's minimum notional")
+	ErrUnknownSymbol         = domain.NotFound("SYMBOL_FILTERS_NOT_FOUND", "symbol is not listed on the exchange")
+)
+
+// SymbolFilters are the order rules of a symbol from exchangeInfo: the
+// PRICE_FILTER tick size, the LOT_SIZE step and minimum quantity (the
+// MARKET_LOT_SIZE ones for market orders) and the MIN_NOTIONAL value
+type SymbolFilters struct {
+	Symbol         string
+	TickSize       float64
+	StepSize       float64
+	MinQty         float64
+	MarketStepSize float64
+	MarketMinQty   float64
+	MinNotional    float64 // USDT
+
+	priceDecimals     int
+	quantityDecimals  int
+	marketQtyDecimals int
+}
+
+// RoundMarketQuantity rounds a market order quantity down to the market step
+// size
+func (f *SymbolFilters) RoundMarketQuantity(qty float64) float64 {
+	return floorToStep(qty, f.MarketStepSize, f.marketQtyDecimals)
+}
+
+// RoundPrice rounds a price to the nearest tick
+func (f *SymbolFilters) RoundPrice(price float64) float64 {
+	if f.TickSize <= 0 {
+		return price
+	}
+	return roundDecimals(math.Round(price/f.TickSize)*f.TickSize, f.priceDecimals)
+}
+
+// CheckMarketOrder reports whether a market order of qty at about price
+// meets the minimum quantity and notional
+func (f *SymbolFilters) CheckMarketOrder(qty, price float64) error {
+	if qty <= 0 || qty < f.MarketMinQty {
+		return fmt.Errorf("%w (%s: %g < %g)", ErrOrderBelowMinQty, f.Symbol, qty, f.MarketMinQty)
+	}
+	if notional := qty * price; notional < f.MinNotional {
+		return fmt.Errorf("%w (%s: %.2f < %.2f USDT)", ErrOrderBelowMinNotional, f.Symbol, notional, f.MinNotional)
+	}
+	return nil
+}
+
+// SymbolFilterCache keeps the order rules of every symbol, reloaded from
+// exchangeInfo once an hour. A failed reload keeps the rules loaded before.
+type SymbolFilterCache struct {
+	client *Client
+
+	mu       sync.Mutex
+	filters  map[string]*SymbolFilters
+	loadedAt time.Time
+}
+
+// NewSymbolFilterCache creates a cache loading exchangeInfo through client
+func NewSymbolFilterCache(client *Client) *SymbolFilterCache {
+	return &SymbolFilterCache{client: client}
+}
+
+// Get returns the order rules of symbol
+func (fc *SymbolFilterCache) Get(ctx context.Context, symbol string) (*SymbolFilters, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if time.Since(fc.loadedAt) > symbolFiltersTTL {
+		filters, err := fc.client.GetSymbolFilters(ctx)
+		switch {
+		case err == nil:
+			fc.filters = filters
+			fc.loadedAt = time.Now()
+		case fc.filters == nil:
+			return nil, err
+		default:
+			logging.Warnf("Reloading the exchange's symbol filters failed: %v", err)
+		}
+	}
+	f, ok := fc.filters[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w (%s)", ErrUnknownSymbol, symbol)
+	}
+	return f, nil
+}
+
+// exchangeFilter is one entry of a symbol's exchangeInfo filters; the
+// fields present depend on the filter type
+type exchangeFilter struct {
+	FilterType string `json:"filterType"`
+	TickSize   string `json:"tickSize"`
+	StepSize   string `json:"stepSize"`
+	MinQty     string `json:"minQty"`
+	Notional   string `json:"notional"`
+}
+
+// GetSymbolFilters returns the order rules of every symbol, by symbol
+func (c *Client) GetSymbolFilters(ctx context.Context) (map[string]*SymbolFilters, error) {
+	resp, err := c.get(ctx, c.baseURL+"/fapi/v1/exchangeInfo")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance API error: %d", resp.StatusCode)
+	}
+
+	var info struct {
+		Symbols []struct {
+			Symbol  string           `json:"symbol"`
+			Filters []exchangeFilter `json:"filters"`
+		} `json:"symbols"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+
+	filters := make(map[string]*SymbolFilters, len(info.Symbols))
+	for _, s := range info.Symbols {
+		f := &SymbolFilters{Symbol: s.Symbol}
+		for _, raw := range s.Filters {
+			switch raw.FilterType {
+			case "PRICE_FILTER":
+				f.TickSize, f.priceDecimals = parseStep(raw.TickSize)
+			case "LOT_SIZE":
+				f.StepSize, f.quantityDecimals = parseStep(raw.StepSize)
+				f.MinQty, _ = strconv.ParseFloat(raw.MinQty, 64)
+			case "MARKET_LOT_SIZE":
+				f.MarketStepSize, f.marketQtyDecimals = parseStep(raw.StepSize)
+				f.MarketMinQty, _ = strconv.ParseFloat(raw.MinQty, 64)
+			case "MIN_NOTIONAL":
+				f.MinNotional, _ = strconv.ParseFloat(raw.Notional, 64)
+			}
+		}
+		// Symbols without a market lot size trade market orders by LOT_SIZE
+		if f.MarketStepSize <= 0 {
+			f.MarketStepSize, f.marketQtyDecimals, f.MarketMinQty = f.StepSize, f.quantityDecimals, f.MinQty
+		}
+		filters[s.Symbol] = f
+	}
+	return filters, nil
+}
+
+// parseStep parses a step such as "0.00100" into its value and the number
+// of decimals it allows (3)
+func parseStep(s string) (float64, int) {
+	step, err := strconv.ParseFloat(s, 64)
+	if err != nil || step <= 0 {
+		return 0, 8
+	}
+	decimals := 0
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		decimals = len(strings.TrimRight(s[i+1:], "0"))
+	}
+	return step, decimals
+}
+
+// floorToStep rounds v down to a multiple of step; the epsilon keeps a value
+// already on the step (0.3 / 0.1 = 2.9999...) from dropping a step
+func floorToStep(v, step float64, decimals int) float64 {
+	if step <= 0 {
+		return v
+	}
+	return roundDecimals(math.Floor(v/step+1e-9)*step, decimals)
+}
+
+func roundDecimals(v float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	return math.Round(v*p) / p
+}
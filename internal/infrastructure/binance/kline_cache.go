@@ -0,0 +1,181 @@
+package binance
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"screener-backend/internal/infrastructure/metrics"
+)
+
+var klineCacheTotal = metrics.NewCounterVec("binance_kline_cache_total",
+	"Kline lookups by outcome: hit (served from cache), partial (only the newest candles fetched) or miss.", "result")
+
+// KlineCache keeps the latest klines per symbol and interval, least recently
+// used first out. Closed candles never change, so they are kept until
+// evicted; the still-forming last candle is trusted for liveTTL and never
+// past its close time. A stale entry is refreshed by fetching only the
+// candles opened since it was stored.
+type KlineCache struct {
+	size    int
+	liveTTL time.Duration
+
+	mu      sync.Mutex
+	order   *list.List // front = most recently used; values are *klineEntry
+	entries map[string]*list.Element
+}
+
+type klineEntry struct {
+	key       string
+	rows      [][]interface{} // oldest first, as returned by Binance
+	fetchedAt time.Time
+}
+
+// klineFetch loads klines from Binance
+type klineFetch func(ctx context.Context, symbol, interval string, limit int) ([][]interface{}, error)
+
+// NewKlineCache creates a cache of up to size symbol/interval pairs; nil
+// (caching off) when size is not positive
+func NewKlineCache(size int, liveTTL time.Duration) *KlineCache {
+	if size <= 0 {
+		return nil
+	}
+	return &KlineCache{
+		size:    size,
+		liveTTL: liveTTL,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the last limit klines, from the cache when they are fresh
+func (kc *KlineCache) get(ctx context.Context, symbol, interval string, limit int, fetch klineFetch) ([][]interface{}, error) {
+	key := symbol + "|" + interval
+	now := time.Now()
+
+	rows, fetchedAt, ok := kc.lookup(key)
+	if !ok || len(rows) < limit {
+		return kc.fill(ctx, key, symbol, interval, limit, fetch, now)
+	}
+
+	last := rows[len(rows)-1]
+	if now.Sub(fetchedAt) < kc.liveTTL && !now.After(klineTime(last, 6)) {
+		klineCacheTotal.Inc("hit")
+		return rows[len(rows)-limit:], nil
+	}
+
+	// Refetch the cached forming candle plus every candle opened since
+	step := IntervalDuration(interval)
+	lastOpen := klineTime(last, 0)
+	if step <= 0 || lastOpen.IsZero() {
+		return kc.fill(ctx, key, symbol, interval, limit, fetch, now)
+	}
+	missing := int(now.Sub(lastOpen)/step) + 1
+	if missing >= limit {
+		return kc.fill(ctx, key, symbol, interval, limit, fetch, now)
+	}
+	fresh, err := fetch(ctx, symbol, interval, missing+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(fresh) == 0 || klineTime(fresh[0], 0).After(lastOpen) {
+		// Not contiguous with the cached candles
+		return kc.fill(ctx, key, symbol, interval, limit, fetch, now)
+	}
+
+	firstNew := klineTime(fresh[0], 0)
+	merged := make([][]interface{}, 0, len(rows)+len(fresh))
+	for _, r := range rows {
+		if klineTime(r, 0).Before(firstNew) {
+			merged = append(merged, r)
+		}
+	}
+	merged = append(merged, fresh...)
+	if len(merged) < limit {
+		return kc.fill(ctx, key, symbol, interval, limit, fetch, now)
+	}
+	if len(merged) > len(rows) {
+		merged = merged[len(merged)-len(rows):]
+	}
+	kc.store(key, merged, now)
+	klineCacheTotal.Inc("partial")
+	return merged[len(merged)-limit:], nil
+}
+
+// fill fetches limit klines and caches them
+func (kc *KlineCache) fill(ctx context.Context, key, symbol, interval string, limit int, fetch klineFetch, now time.Time) ([][]interface{}, error) {
+	klineCacheTotal.Inc("miss")
+	rows, err := fetch(ctx, symbol, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+	if IntervalDuration(interval) > 0 && len(rows) > 0 {
+		kc.store(key, rows, now)
+	}
+	return rows, nil
+}
+
+func (kc *KlineCache) lookup(key string) ([][]interface{}, time.Time, bool) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	el, ok := kc.entries[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	kc.order.MoveToFront(el)
+	e := el.Value.(*klineEntry)
+	return e.rows, e.fetchedAt, true
+}
+
+func (kc *KlineCache) store(key string, rows [][]interface{}, fetchedAt time.Time) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	if el, ok := kc.entries[key]; ok {
+		e := el.Value.(*klineEntry)
+		e.rows, e.fetchedAt = rows, fetchedAt
+		kc.order.MoveToFront(el)
+		return
+	}
+	kc.entries[key] = kc.order.PushFront(&klineEntry{key: key, rows: rows, fetchedAt: fetchedAt})
+	for kc.order.Len() > kc.size {
+		oldest := kc.order.Back()
+		kc.order.Remove(oldest)
+		delete(kc.entries, oldest.Value.(*klineEntry).key)
+	}
+}
+
+// klineTime reads a millisecond timestamp column (0 = open, 6 = close)
+func klineTime(row []interface{}, col int) time.Time {
+	if len(row) <= col {
+		return time.Time{}
+	}
+	ms, ok := row[col].(float64)
+	if !ok {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(ms))
+}
+
+// IntervalDuration is the candle length of a kline interval; 0 for monthly
+// candles, which vary in length and aren't cached
+func IntervalDuration(interval string) time.Duration {
+	var n int
+	var unit byte
+	if _, err := fmt.Sscanf(interval, "%d%c", &n, &unit); err != nil || n <= 0 {
+		return 0
+	}
+	switch unit {
+	case 'm':
+		return time.Duration(n) * time.Minute
+	case 'h':
+		return time.Duration(n) * time.Hour
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
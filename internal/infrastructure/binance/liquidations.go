@@ -0,0 +1,193 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	liquidationStream = "!forceOrder@arr"
+	// LiquidationRetention is the longest window the feed can total; older
+	// buckets are dropped
+	LiquidationRetention = time.Hour
+	liquidationBucket    = time.Minute
+)
+
+// LiquidationTotals is the USDT value of a symbol's forced orders over a
+// window. Long liquidations are forced sells of long positions, short
+// liquidations forced buys of shorts.
+type LiquidationTotals struct {
+	Long  float64 `json:"long"`
+	Short float64 `json:"short"`
+}
+
+// Imbalance is (long - short) / (long + short): +1 when only longs are being
+// liquidated, -1 when only shorts are, 0 without liquidations
+func (t LiquidationTotals) Imbalance() float64 {
+	if sum := t.Long + t.Short; sum > 0 {
+		return (t.Long - t.Short) / sum
+	}
+	return 0
+}
+
+// LiquidationFeed totals every symbol's liquidations from the all-market
+// force order stream, in one-minute buckets kept for LiquidationRetention.
+// Binance sends at most the latest liquidation of a symbol per second, so
+// the totals undercount a cascade but keep its shape. While disconnected the
+// totals only age; Totals is never more than a connection drop behind.
+type LiquidationFeed struct {
+	baseURL string
+	once    sync.Once
+
+	mu      sync.Mutex
+	buckets map[string][]liquidationBucketTotals // by symbol, oldest first
+}
+
+type liquidationBucketTotals struct {
+	start time.Time
+	LiquidationTotals
+}
+
+// NewLiquidationFeed creates a feed on baseURL (FstreamBaseURL when empty).
+// It connects on Start.
+func NewLiquidationFeed(baseURL string) *LiquidationFeed {
+	if baseURL == "" {
+		baseURL = FstreamBaseURL
+	}
+	return &LiquidationFeed{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		buckets: make(map[string][]liquidationBucketTotals),
+	}
+}
+
+// Start connects the feed and keeps it connected for the life of the process
+func (f *LiquidationFeed) Start() {
+	f.once.Do(func() { go f.run() })
+}
+
+// Totals is the symbol's liquidations in the last window (at most
+// LiquidationRetention), to the minute
+func (f *LiquidationFeed) Totals(symbol string, window time.Duration) LiquidationTotals {
+	since := time.Now().Add(-window).Truncate(liquidationBucket)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var totals LiquidationTotals
+	for _, b := range f.buckets[symbol] {
+		if !b.start.Before(since) {
+			totals.Long += b.Long
+			totals.Short += b.Short
+		}
+	}
+	return totals
+}
+
+// run keeps the connection open, with a growing pause between failed attempts
+func (f *LiquidationFeed) run() {
+	backoff := time.Second
+	sweep := time.NewTicker(liquidationBucket)
+	defer sweep.Stop()
+	go func() {
+		for range sweep.C {
+			f.sweep()
+		}
+	}()
+	for {
+		connected := time.Now()
+		if err := f.serve(); err != nil {
+			log.Printf("Binance liquidation stream: %v", err)
+		}
+		if time.Since(connected) > time.Minute {
+			backoff = time.Second
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+// serve dials the force order stream and reads until the connection fails
+func (f *LiquidationFeed) serve() error {
+	ws, _, err := websocket.DefaultDialer.Dial(f.baseURL+"/ws/"+liquidationStream, nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer ws.Close()
+
+	ws.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	ws.SetPingHandler(func(data string) error {
+		ws.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		return ws.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(10*time.Second))
+	})
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+		ws.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		f.handle(msg)
+	}
+}
+
+type forceOrderEvent struct {
+	Order struct {
+		Symbol      string `json:"s"`
+		Side        string `json:"S"` // SELL liquidates a long, BUY a short
+		AvgPrice    string `json:"ap"`
+		FilledQty   string `json:"z"`
+		TradeTimeMs int64  `json:"T"`
+	} `json:"o"`
+}
+
+// handle adds one force order event to its symbol's bucket
+func (f *LiquidationFeed) handle(msg []byte) {
+	var e forceOrderEvent
+	if err := json.Unmarshal(msg, &e); err != nil || e.Order.Symbol == "" {
+		return
+	}
+	price, _ := strconv.ParseFloat(e.Order.AvgPrice, 64)
+	qty, _ := strconv.ParseFloat(e.Order.FilledQty, 64)
+	value := price * qty
+	if value <= 0 {
+		return
+	}
+	start := time.UnixMilli(e.Order.TradeTimeMs).Truncate(liquidationBucket)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	buckets := f.buckets[e.Order.Symbol]
+	if n := len(buckets); n == 0 || buckets[n-1].start.Before(start) {
+		buckets = append(buckets, liquidationBucketTotals{start: start})
+	}
+	// Events arrive in trade order; a late one counts in the latest bucket
+	last := &buckets[len(buckets)-1]
+	if e.Order.Side == "SELL" {
+		last.Long += value
+	} else {
+		last.Short += value
+	}
+	f.buckets[e.Order.Symbol] = buckets
+}
+
+// sweep drops the buckets older than LiquidationRetention
+func (f *LiquidationFeed) sweep() {
+	cutoff := time.Now().Add(-LiquidationRetention - liquidationBucket)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for symbol, buckets := range f.buckets {
+		i := 0
+		for i < len(buckets) && buckets[i].start.Before(cutoff) {
+			i++
+		}
+		if i == len(buckets) {
+			delete(f.buckets, symbol)
+		} else if i > 0 {
+			f.buckets[symbol] = append(buckets[:0:0], buckets[i:]...)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// LongShortRatio is one period of a long/short ratio: the shares long and
+// short (0-1) and their ratio. For all traders the shares are of accounts;
+// for top traders they are of position size.
+type LongShortRatio struct {
+	Time  time.Time
+	Ratio float64
+	Long  float64
+	Short float64
+}
+
+// GetGlobalLongShortRatio returns the long/short account ratio of all
+// traders over the last limit periods, oldest first
+func (c *Client) GetGlobalLongShortRatio(ctx context.Context, symbol, period string, limit int) ([]LongShortRatio, error) {
+	return c.getLongShortRatio(ctx, "/futures/data/globalLongShortAccountRatio", symbol, period, limit)
+}
+
+// GetTopLongShortPositionRatio returns the long/short position ratio of the
+// top 20% of traders by margin over the last limit periods, oldest first
+func (c *Client) GetTopLongShortPositionRatio(ctx context.Context, symbol, period string, limit int) ([]LongShortRatio, error) {
+	return c.getLongShortRatio(ctx, "/futures/data/topLongShortPositionRatio", symbol, period, limit)
+}
+
+func (c *Client) getLongShortRatio(ctx context.Context, path, symbol, period string, limit int) ([]LongShortRatio, error) {
+	url := fmt.Sprintf("%s%s?symbol=%s&period=%s&limit=%d", c.baseURL, path, symbol, period, limit)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance API error: %d", resp.StatusCode)
+	}
+
+	var data []struct {
+		LongShortRatio string `json:"longShortRatio"`
+		LongAccount    string `json:"longAccount"`
+		ShortAccount   string `json:"shortAccount"`
+		Timestamp      int64  `json:"timestamp"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	ratios := make([]LongShortRatio, 0, len(data))
+	for _, d := range data {
+		ratio, _ := strconv.ParseFloat(d.LongShortRatio, 64)
+		long, _ := strconv.ParseFloat(d.LongAccount, 64)
+		short, _ := strconv.ParseFloat(d.ShortAccount, 64)
+		ratios = append(ratios, LongShortRatio{Time: time.UnixMilli(d.Timestamp), Ratio: ratio, Long: long, Short: short})
+	}
+	return ratios, nil
+}
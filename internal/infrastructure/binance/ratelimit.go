@@ -0,0 +1,210 @@
+package binance
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultWeightBudget is the share of a host's per-minute request weight
+// limit the market data requests may use, leaving the rest for orders and
+// other instances on the same IP
+const DefaultWeightBudget = 0.8
+
+// banPause is how long requests stop after a 418 without Retry-After
+const banPause = 2 * time.Minute
+
+var weightLimiters = struct {
+	mu     sync.Mutex
+	budget float64
+	hosts  map[string]*weightLimiter
+}{
+	budget: DefaultWeightBudget,
+	hosts:  make(map[string]*weightLimiter),
+}
+
+// SetWeightBudget sets the share (0-1] of each host's weight limit the
+// market data requests may use. It applies to hosts not yet called, so set
+// it before the first request.
+func SetWeightBudget(budget float64) {
+	weightLimiters.mu.Lock()
+	defer weightLimiters.mu.Unlock()
+	weightLimiters.budget = budget
+}
+
+// limiterFor returns the host's limiter; the weight limit is per IP, so
+// every Client calling the host shares it
+func limiterFor(host string) *weightLimiter {
+	weightLimiters.mu.Lock()
+	defer weightLimiters.mu.Unlock()
+	l, ok := weightLimiters.hosts[host]
+	if !ok {
+		capacity := float64(weightLimits[host]) * weightLimiters.budget
+		l = &weightLimiter{host: host, capacity: capacity, tokens: capacity, updated: time.Now()}
+		weightLimiters.hosts[host] = l
+	}
+	return l
+}
+
+// weightLimiter is a token bucket of request weight for one host: capacity
+// per minute, refilled continuously. Requests reserve their weight in
+// arrival order and wait until the bucket covers it, so a burst is spread
+// over the minute instead of tripping the limit. The used weight Binance
+// reports drains the bucket when other clients on the IP spend it, and a
+// 429 or 418 stops every request to the host until Retry-After. Hosts
+// without a known limit are only paused.
+type weightLimiter struct {
+	host     string
+	capacity float64 // weight per minute; 0 when the limit is unknown
+
+	mu          sync.Mutex
+	tokens      float64 // negative while requests wait for their weight
+	updated     time.Time
+	pausedUntil time.Time
+}
+
+// wait blocks until the host can take a request of weight cost, or until
+// ctx ends
+func (l *weightLimiter) wait(ctx context.Context, cost int) error {
+	ready := l.reserve(float64(cost))
+	for {
+		if d := time.Until(ready); d > 0 {
+			timer := time.NewTimer(d)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				l.refund(float64(cost))
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		// A 429 seen while waiting holds the reservation back too
+		l.mu.Lock()
+		ready = l.pausedUntil
+		l.mu.Unlock()
+		if !time.Now().Before(ready) {
+			return nil
+		}
+	}
+}
+
+// reserve takes cost from the bucket and returns when the request may go
+func (l *weightLimiter) reserve(cost float64) time.Time {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ready := now
+	if l.capacity > 0 {
+		l.refill(now)
+		l.tokens -= cost
+		if l.tokens < 0 {
+			ready = now.Add(time.Duration(-l.tokens / l.capacity * float64(time.Minute)))
+		}
+	}
+	if ready.Before(l.pausedUntil) {
+		ready = l.pausedUntil
+	}
+	return ready
+}
+
+func (l *weightLimiter) refund(cost float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.capacity > 0 {
+		l.tokens += cost
+	}
+}
+
+// refill adds the weight accrued since the last update; callers hold mu
+func (l *weightLimiter) refill(now time.Time) {
+	l.tokens = math.Min(l.capacity, l.tokens+now.Sub(l.updated).Minutes()*l.capacity)
+	l.updated = now
+}
+
+// observe reads the used weight and rate-limit responses off resp
+func (l *weightLimiter) observe(resp *http.Response) {
+	now := time.Now()
+	nextMinute := now.Truncate(time.Minute).Add(time.Minute)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusTeapot:
+		until := nextMinute
+		if resp.StatusCode == http.StatusTeapot {
+			until = now.Add(banPause)
+		}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			until = now.Add(time.Duration(secs) * time.Second)
+		}
+		if until.After(l.pausedUntil) {
+			l.pausedUntil = until
+			log.Printf("Binance %s answered %d; pausing requests until %s", l.host, resp.StatusCode, until.Format(time.TimeOnly))
+		}
+		return
+	}
+
+	used, err := strconv.Atoi(resp.Header.Get("X-MBX-USED-WEIGHT-1M"))
+	if err != nil || l.capacity == 0 {
+		return
+	}
+	// Binance counts weight per calendar minute, so a spent budget is back
+	// at the next one
+	l.refill(now)
+	if float64(used) >= l.capacity {
+		l.tokens = math.Min(l.tokens, 0)
+		if nextMinute.After(l.pausedUntil) {
+			l.pausedUntil = nextMinute
+		}
+	} else {
+		l.tokens = math.Min(l.tokens, l.capacity-float64(used))
+	}
+}
+
+// requestWeight is the request weight Binance charges for u
+func requestWeight(u *url.URL) int {
+	q := u.Query()
+	limit, err := strconv.Atoi(q.Get("limit"))
+	switch u.Path {
+	case "/fapi/v1/klines":
+		switch {
+		case err != nil:
+			return 5 // default limit 500
+		case limit < 100:
+			return 1
+		case limit < 500:
+			return 2
+		case limit <= 1000:
+			return 5
+		default:
+			return 10
+		}
+	case "/fapi/v1/depth":
+		switch {
+		case err != nil:
+			return 10 // default limit 500
+		case limit <= 50:
+			return 2
+		case limit <= 100:
+			return 5
+		case limit <= 500:
+			return 10
+		default:
+			return 20
+		}
+	case "/fapi/v1/ticker/24hr":
+		if q.Get("symbol") == "" {
+			return 40
+		}
+	case "/fapi/v1/premiumIndex":
+		if q.Get("symbol") == "" {
+			return 10
+		}
+	}
+	return 1
+}
@@ -0,0 +1,161 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"screener-backend/internal/infrastructure/metrics"
+)
+
+// RetryPolicy is how a Binance call is repeated after a transient failure: a
+// transport error or a 5xx. Rejections (4xx) are never repeated.
+type RetryPolicy struct {
+	Retries int           // repeats after the first try; 0 turns retries off
+	Backoff time.Duration // wait before the first repeat, doubled for each next one
+}
+
+// DefaultRetryPolicy repeats a call twice, after about 250ms and 500ms
+var DefaultRetryPolicy = RetryPolicy{Retries: 2, Backoff: 250 * time.Millisecond}
+
+// maxRetryBackoff caps the wait before a single repeat
+const maxRetryBackoff = 5 * time.Second
+
+const orderEndpoint = "/fapi/v1/order"
+
+// errOrderNotFound is Binance's -2013 "Order does not exist"
+const errOrderNotFound = -2013
+
+var retriesTotal = metrics.NewCounterVec("binance_retries_total",
+	"Binance REST calls repeated after a transient failure, by API kind and endpoint.", "api", "endpoint")
+
+var retryPolicy = struct {
+	mu     sync.Mutex
+	policy RetryPolicy
+}{policy: DefaultRetryPolicy}
+
+// SetRetryPolicy sets how every Client and TradingClient repeats failed calls
+func SetRetryPolicy(p RetryPolicy) {
+	retryPolicy.mu.Lock()
+	defer retryPolicy.mu.Unlock()
+	retryPolicy.policy = p
+}
+
+func currentRetryPolicy() RetryPolicy {
+	retryPolicy.mu.Lock()
+	defer retryPolicy.mu.Unlock()
+	return retryPolicy.policy
+}
+
+// pause waits out the backoff before repeat attempt+1, jittered between half
+// and all of it so callers that failed together don't repeat together. False
+// when ctx ends first or its deadline leaves no time for the wait.
+func (p RetryPolicy) pause(ctx context.Context, attempt int) bool {
+	d := min(p.Backoff<<attempt, maxRetryBackoff)
+	if d > 0 {
+		d = d/2 + rand.N(d/2+1)
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// transient reports whether a call that ended with resp and err may succeed
+// if repeated
+func transient(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return err != nil || resp.StatusCode >= 500
+}
+
+// retry runs call, an idempotent request, repeating it while it fails
+// transiently and the policy allows. The last response is returned with its
+// body open.
+func retry(ctx context.Context, api, endpoint string, call func() (*http.Response, error)) (*http.Response, error) {
+	p := currentRetryPolicy()
+	for attempt := 0; ; attempt++ {
+		resp, err := call()
+		if attempt >= p.Retries || !transient(ctx, resp, err) || !p.pause(ctx, attempt) {
+			return resp, err
+		}
+		discard(resp)
+		retriesTotal.Inc(api, endpoint)
+	}
+}
+
+// placeOrder sends a new order. Placing is not idempotent: a timeout or 5xx
+// can come after Binance took the order, and placing it again would double
+// the position. So each order carries a newClientOrderId, and after a
+// transient failure the order is looked up by it. When Binance has it, the
+// lookup is returned in place of the lost answer; its fields are those of a
+// placement's. Only when Binance reports no such order is it placed again.
+func (c *TradingClient) placeOrder(ctx context.Context, baseURL string, params url.Values) (*http.Response, error) {
+	if params.Get("newClientOrderId") == "" {
+		params.Set("newClientOrderId", newClientOrderID())
+	}
+	lookup := url.Values{}
+	lookup.Set("symbol", params.Get("symbol"))
+	lookup.Set("origClientOrderId", params.Get("newClientOrderId"))
+
+	p := currentRetryPolicy()
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, baseURL, http.MethodPost, orderEndpoint, params)
+		if attempt >= p.Retries || !transient(ctx, resp, err) || !p.pause(ctx, attempt) {
+			return resp, err
+		}
+
+		placed, lookupErr := c.send(ctx, baseURL, http.MethodGet, orderEndpoint, lookup)
+		if lookupErr == nil && placed.StatusCode == http.StatusOK {
+			discard(resp)
+			return placed, nil
+		}
+		if lookupErr != nil || !orderNotFound(placed) {
+			// Whether the order exists is unknown; report the failure
+			// rather than risk a second one
+			discard(placed)
+			return resp, err
+		}
+		discard(resp)
+		retriesTotal.Inc("signed", orderEndpoint)
+	}
+}
+
+// orderNotFound reports whether resp is Binance's answer for an unknown
+// order; it consumes the body
+func orderNotFound(resp *http.Response) bool {
+	defer resp.Body.Close()
+	var e struct {
+		Code int `json:"code"`
+	}
+	body, err := io.ReadAll(resp.Body)
+	return err == nil && json.Unmarshal(body, &e) == nil && e.Code == errOrderNotFound
+}
+
+// newClientOrderID is a unique newClientOrderId (Binance allows 36
+// characters)
+func newClientOrderID() string {
+	return fmt.Sprintf("scr-%016x%08x", rand.Uint64(), rand.Uint32())
+}
+
+// discard drains and closes the body of a response that is not returned
+func discard(resp *http.Response) {
+	if resp != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,474 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"screener-backend/internal/infrastructure/metrics"
+
+	"github.com/gorilla/websocket"
+)
+
+// FstreamBaseURL is the USDⓈ-M futures market stream host
+const FstreamBaseURL = "wss://fstream.binance.com"
+
+const (
+	// streamsPerConn is Binance's limit of streams on one connection
+	streamsPerConn = 200
+	// streamFlushEvery batches new subscriptions into one SUBSCRIBE per
+	// connection, well under the 10 messages per second Binance accepts
+	streamFlushEvery = 500 * time.Millisecond
+	// streamIdleTTL is how long a series nobody asks for stays subscribed
+	streamIdleTTL = time.Hour
+	// streamReadTimeout ends a silent connection; Binance pings every 3 minutes
+	streamReadTimeout = 10 * time.Minute
+	// streamCandleGrace is how long past its close a forming candle may go
+	// without an update before the series is treated as stale
+	streamCandleGrace = 5 * time.Second
+	// tickerStreamMaxAge is how old the last ticker event may be for the
+	// streamed tickers to be served
+	tickerStreamMaxAge = 30 * time.Second
+	tickerStream       = "!ticker@arr"
+)
+
+var (
+	streamLookups = metrics.NewCounterVec("binance_stream_lookups_total",
+		"Market data lookups by kind (klines, tickers) and outcome: hit (served from the stream) or miss (fetched over REST).", "kind", "result")
+	streamConnections = metrics.NewGaugeVec("binance_stream_connections",
+		"Open market stream connections.", "host")
+)
+
+// MarketStream keeps klines and 24h tickers current from the futures market
+// streams, so the screener reads them from memory instead of polling REST.
+// A series is seeded over REST the first time it is asked for (see
+// Client.GetKlines) and subscribed; from the first streamed update on it is
+// served from memory. A series that misses updates (a dropped connection, a
+// gap between candles, a silent symbol) falls back to REST and is seeded
+// again. Connections are opened on the first subscription, so instances
+// that never screen never connect.
+type MarketStream struct {
+	baseURL string
+	once    sync.Once
+
+	mu        sync.Mutex
+	series    map[string]*streamSeries // by stream name, e.g. btcusdt@kline_5m
+	pending   []string                 // stream names waiting for a SUBSCRIBE
+	conns     []*streamConn
+	tickers   map[string]Ticker24h
+	tickersAt time.Time // last ticker event; zero until one follows a seed
+	tickersOn bool      // ticker stream subscribed
+	tickerUse time.Time
+	connected int // open connections, for the gauge
+}
+
+// streamSeries is the candles of one symbol and interval, oldest first, in
+// the REST kline layout
+type streamSeries struct {
+	step   time.Duration
+	rows   [][]interface{}
+	live   bool // updated by the stream since it was seeded
+	conn   *streamConn
+	usedAt time.Time
+}
+
+// streamConn is one WebSocket connection and the streams assigned to it
+type streamConn struct {
+	stream  *MarketStream
+	streams map[string]bool
+
+	writeMu sync.Mutex
+	ws      *websocket.Conn // nil while disconnected
+	nextID  int
+}
+
+// NewMarketStream creates a market stream on baseURL (FstreamBaseURL when empty)
+func NewMarketStream(baseURL string) *MarketStream {
+	if baseURL == "" {
+		baseURL = FstreamBaseURL
+	}
+	return &MarketStream{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		series:  make(map[string]*streamSeries),
+		tickers: make(map[string]Ticker24h),
+	}
+}
+
+// WithStream serves GetKlines and GetFutures24hrTicker from stream when it
+// is current (nil turns streaming off)
+func (c *Client) WithStream(stream *MarketStream) *Client {
+	c.stream = stream
+	return c
+}
+
+func klineStreamName(symbol, interval string) string {
+	return strings.ToLower(symbol) + "@kline_" + interval
+}
+
+// klines returns the last limit candles when the series is streamed, long
+// enough and current
+func (s *MarketStream) klines(symbol, interval string, limit int) ([][]interface{}, bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ser, ok := s.series[klineStreamName(symbol, interval)]
+	if !ok {
+		streamLookups.Inc("klines", "miss")
+		return nil, false
+	}
+	ser.usedAt = now
+	if !ser.live || len(ser.rows) < limit || now.After(klineTime(ser.rows[len(ser.rows)-1], 6).Add(streamCandleGrace)) {
+		streamLookups.Inc("klines", "miss")
+		return nil, false
+	}
+	streamLookups.Inc("klines", "hit")
+	out := make([][]interface{}, limit)
+	copy(out, ser.rows[len(ser.rows)-limit:])
+	return out, true
+}
+
+// seedKlines replaces a series with candles fetched over REST and subscribes
+// it if it isn't yet. The series is served once the stream updates it.
+func (s *MarketStream) seedKlines(symbol, interval string, rows [][]interface{}) {
+	step := IntervalDuration(interval)
+	if step <= 0 || len(rows) == 0 {
+		return
+	}
+	s.start()
+	name := klineStreamName(symbol, interval)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ser, ok := s.series[name]
+	if !ok {
+		ser = &streamSeries{step: step}
+		s.series[name] = ser
+		s.pending = append(s.pending, name)
+	}
+	ser.rows = append([][]interface{}(nil), rows...)
+	ser.live = false
+	ser.usedAt = time.Now()
+}
+
+// tickers24h returns every symbol's 24h ticker when the ticker stream is current
+func (s *MarketStream) tickers24h() ([]Ticker24h, bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickerUse = now
+	if s.tickersAt.IsZero() || now.Sub(s.tickersAt) > tickerStreamMaxAge {
+		streamLookups.Inc("tickers", "miss")
+		return nil, false
+	}
+	streamLookups.Inc("tickers", "hit")
+	out := make([]Ticker24h, 0, len(s.tickers))
+	for _, t := range s.tickers {
+		out = append(out, t)
+	}
+	return out, true
+}
+
+// seedTickers replaces the tickers with a REST snapshot and subscribes the
+// ticker stream if it isn't yet. The stream only sends the symbols that
+// traded in the last second, so the snapshot provides the rest.
+func (s *MarketStream) seedTickers(tickers []Ticker24h) {
+	s.start()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickers = make(map[string]Ticker24h, len(tickers))
+	for _, t := range tickers {
+		s.tickers[t.Symbol] = t
+	}
+	s.tickersAt = time.Time{}
+	s.tickerUse = time.Now()
+	if !s.tickersOn {
+		s.tickersOn = true
+		s.pending = append(s.pending, tickerStream)
+	}
+}
+
+// start runs the subscription loop on first use
+func (s *MarketStream) start() {
+	s.once.Do(func() { go s.run() })
+}
+
+// run subscribes pending streams and drops idle ones for the life of the process
+func (s *MarketStream) run() {
+	flush := time.NewTicker(streamFlushEvery)
+	defer flush.Stop()
+	lastSweep := time.Now()
+	for range flush.C {
+		s.flush()
+		if time.Since(lastSweep) >= streamIdleTTL/12 {
+			s.sweep()
+			lastSweep = time.Now()
+		}
+	}
+}
+
+// flush assigns pending streams to connections with room, opening new ones
+// as needed, and subscribes them
+func (s *MarketStream) flush() {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	added := make(map[*streamConn][]string)
+	for _, name := range s.pending {
+		ser, ok := s.series[name]
+		if !ok && name != tickerStream {
+			continue // swept before it was subscribed
+		}
+		conn := s.connWithRoom()
+		conn.streams[name] = true
+		if ok {
+			ser.conn = conn
+		}
+		added[conn] = append(added[conn], name)
+	}
+	s.pending = nil
+	s.mu.Unlock()
+
+	for conn, names := range added {
+		conn.send("SUBSCRIBE", names)
+	}
+}
+
+// connWithRoom is a connection below streamsPerConn, a new one if all are
+// full. Callers hold s.mu.
+func (s *MarketStream) connWithRoom() *streamConn {
+	for _, c := range s.conns {
+		if len(c.streams) < streamsPerConn {
+			return c
+		}
+	}
+	c := &streamConn{stream: s, streams: make(map[string]bool)}
+	s.conns = append(s.conns, c)
+	go c.run()
+	return c
+}
+
+// sweep unsubscribes the series and tickers nobody asked for in streamIdleTTL
+func (s *MarketStream) sweep() {
+	cutoff := time.Now().Add(-streamIdleTTL)
+	s.mu.Lock()
+	removed := make(map[*streamConn][]string)
+	for name, ser := range s.series {
+		if ser.usedAt.Before(cutoff) {
+			delete(s.series, name)
+			if ser.conn != nil {
+				delete(ser.conn.streams, name)
+				removed[ser.conn] = append(removed[ser.conn], name)
+			}
+		}
+	}
+	if s.tickersOn && s.tickerUse.Before(cutoff) {
+		s.tickersOn = false
+		s.tickersAt = time.Time{}
+		for _, c := range s.conns {
+			if c.streams[tickerStream] {
+				delete(c.streams, tickerStream)
+				removed[c] = append(removed[c], tickerStream)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for conn, names := range removed {
+		conn.send("UNSUBSCRIBE", names)
+	}
+}
+
+// run keeps the connection open, resubscribing its streams after every
+// reconnect, with a growing pause between failed attempts
+func (c *streamConn) run() {
+	backoff := time.Second
+	for {
+		connected := time.Now()
+		if err := c.serve(); err != nil {
+			log.Printf("Binance market stream: %v", err)
+		}
+		c.stream.markDown(c)
+		if time.Since(connected) > time.Minute {
+			backoff = time.Second
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+// serve dials, subscribes the connection's streams and reads until the
+// connection fails
+func (c *streamConn) serve() error {
+	ws, _, err := websocket.DefaultDialer.Dial(c.stream.baseURL+"/stream", nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer ws.Close()
+	c.stream.countConn(1)
+	defer c.stream.countConn(-1)
+
+	ws.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	ws.SetPingHandler(func(data string) error {
+		ws.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		return ws.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(10*time.Second))
+	})
+
+	c.writeMu.Lock()
+	c.ws = ws
+	c.writeMu.Unlock()
+	defer func() {
+		c.writeMu.Lock()
+		c.ws = nil
+		c.writeMu.Unlock()
+	}()
+
+	c.stream.mu.Lock()
+	names := make([]string, 0, len(c.streams))
+	for name := range c.streams {
+		names = append(names, name)
+	}
+	c.stream.mu.Unlock()
+	if len(names) > 0 {
+		c.send("SUBSCRIBE", names)
+	}
+
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+		ws.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		c.stream.handle(msg)
+	}
+}
+
+func (s *MarketStream) countConn(delta int) {
+	s.mu.Lock()
+	s.connected += delta
+	n := s.connected
+	s.mu.Unlock()
+	host := strings.TrimPrefix(strings.TrimPrefix(s.baseURL, "wss://"), "ws://")
+	streamConnections.Set(float64(n), host)
+}
+
+// send writes a SUBSCRIBE or UNSUBSCRIBE request. While disconnected it is
+// a no-op: the next connection subscribes everything assigned to it.
+func (c *streamConn) send(method string, names []string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.ws == nil {
+		return
+	}
+	c.nextID++
+	req := struct {
+		Method string   `json:"method"`
+		Params []string `json:"params"`
+		ID     int      `json:"id"`
+	}{method, names, c.nextID}
+	c.ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := c.ws.WriteJSON(req); err != nil {
+		log.Printf("Binance market stream: %s: %v", strings.ToLower(method), err)
+		c.ws.Close()
+	}
+}
+
+// markDown stops serving the series of a lost connection until they are
+// seeded again; updates were missed while it was down
+func (s *MarketStream) markDown(c *streamConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name := range c.streams {
+		if ser, ok := s.series[name]; ok {
+			ser.live = false
+		}
+		if name == tickerStream {
+			s.tickersAt = time.Time{}
+		}
+	}
+}
+
+// streamEnvelope is a combined stream message
+type streamEnvelope struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type klineEvent struct {
+	Kline struct {
+		OpenTime         int64  `json:"t"`
+		CloseTime        int64  `json:"T"`
+		Open             string `json:"o"`
+		Close            string `json:"c"`
+		High             string `json:"h"`
+		Low              string `json:"l"`
+		Volume           string `json:"v"`
+		Trades           int64  `json:"n"`
+		QuoteVolume      string `json:"q"`
+		TakerBuyVolume   string `json:"V"`
+		TakerBuyQuoteVol string `json:"Q"`
+	} `json:"k"`
+}
+
+type tickerEvent struct {
+	Symbol             string `json:"s"`
+	PriceChangePercent string `json:"P"`
+	LastPrice          string `json:"c"`
+	QuoteVolume        string `json:"q"`
+}
+
+// handle applies one stream message; subscription replies are ignored
+func (s *MarketStream) handle(msg []byte) {
+	var env streamEnvelope
+	if err := json.Unmarshal(msg, &env); err != nil || env.Stream == "" {
+		return
+	}
+	if env.Stream == tickerStream {
+		var events []tickerEvent
+		if err := json.Unmarshal(env.Data, &events); err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.tickersOn {
+			for _, e := range events {
+				s.tickers[e.Symbol] = Ticker24h{Symbol: e.Symbol, PriceChangePercent: e.PriceChangePercent, LastPrice: e.LastPrice, QuoteVolume: e.QuoteVolume}
+			}
+			s.tickersAt = time.Now()
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	var e klineEvent
+	if err := json.Unmarshal(env.Data, &e); err != nil {
+		return
+	}
+	k := e.Kline
+	// The REST layout: open time, OHLCV, close time, quote volume, trades,
+	// taker buy base and quote volume, ignore
+	row := []interface{}{float64(k.OpenTime), k.Open, k.High, k.Low, k.Close, k.Volume, float64(k.CloseTime), k.QuoteVolume, float64(k.Trades), k.TakerBuyVolume, k.TakerBuyQuoteVol, "0"}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ser, ok := s.series[env.Stream]
+	if !ok || len(ser.rows) == 0 {
+		return
+	}
+	lastOpen := klineTime(ser.rows[len(ser.rows)-1], 0)
+	open := time.UnixMilli(k.OpenTime)
+	switch {
+	case open.Equal(lastOpen):
+		ser.rows[len(ser.rows)-1] = row
+	case open.Equal(lastOpen.Add(ser.step)):
+		ser.rows = append(ser.rows[1:], row)
+	case open.Before(lastOpen):
+		return // an update older than the seed
+	default:
+		ser.live = false // candles missing in between; seed again
+		return
+	}
+	ser.live = true
+}
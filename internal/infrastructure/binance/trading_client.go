@@ -1,12 +1,15 @@
 package binance
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"screener-backend/internal/domain"
@@ -16,10 +19,11 @@ import (
 
 // TradingClient handles authenticated Binance API requests
 type TradingClient struct {
-	apiKey     string
-	secretKey  string
-	baseURL    string
-	httpClient *http.Client
+	apiKey      string
+	secretKey   string
+	baseURL     string
+	sapiBaseURL string // Spot/SAPI host, used for sub-account endpoints
+	httpClient  *http.Client
 }
 
 // BinanceAPIError captures structured error info returned by Binance.
@@ -40,6 +44,12 @@ func (e *BinanceAPIError) Error() string {
 	return fmt.Sprintf("binance API error %d: %s", e.StatusCode, e.Body)
 }
 
+// Is classifies 4xx responses as domain.ErrExchangeRejected; 5xx are
+// exchange outages, not rejections.
+func (e *BinanceAPIError) Is(target error) bool {
+	return target == domain.ErrExchangeRejected && e.StatusCode < 500
+}
+
 func parseBinanceAPIError(statusCode int, body []byte) error {
 	var parsed struct {
 		Code int    `json:"code"`
@@ -54,30 +64,40 @@ func parseBinanceAPIError(statusCode int, body []byte) error {
 // NewTradingClient creates a new authenticated Binance client
 func NewTradingClient(apiKey, secretKey string, isTestnet bool) *TradingClient {
 	baseURL := FapiBaseURL
+	sapiBaseURL := SpotBaseURL
 	if isTestnet {
 		baseURL = "https://testnet.binancefuture.com"
+		sapiBaseURL = "" // Sub-account endpoints are not available on testnet
 	}
 
 	return &TradingClient{
-		apiKey:     apiKey,
-		secretKey:  secretKey,
-		baseURL:    baseURL,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		apiKey:      apiKey,
+		secretKey:   secretKey,
+		baseURL:     baseURL,
+		sapiBaseURL: sapiBaseURL,
+		httpClient:  &http.Client{Timeout: 10 * time.Second, Transport: newInstrumentedTransport("signed")},
 	}
 }
 
+// NewTradingClientForCredentials creates a client signed with the key pair that
+// orders should be placed with (the sub-account's keys when one is selected).
+func NewTradingClientForCredentials(cred *domain.BinanceAPICredentials) *TradingClient {
+	apiKey, secretKey := cred.TradingKeys()
+	return NewTradingClient(apiKey, secretKey, cred.IsTestnet)
+}
+
 // TestConnection tests if API credentials are valid
-func (c *TradingClient) TestConnection() error {
+func (c *TradingClient) TestConnection(ctx context.Context) error {
 	// Test with simple account endpoint
-	_, err := c.GetAccountInfo()
+	_, err := c.GetAccountInfo(ctx)
 	return err
 }
 
 // GetAccountInfo retrieves account balance and positions
-func (c *TradingClient) GetAccountInfo() (*domain.BinanceAccountInfo, error) {
+func (c *TradingClient) GetAccountInfo(ctx context.Context) (*domain.BinanceAccountInfo, error) {
 	endpoint := "/fapi/v2/account"
 	
-	resp, err := c.signedRequest("GET", endpoint, nil)
+	resp, err := c.signedRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -175,7 +195,7 @@ func (c *TradingClient) GetAccountInfo() (*domain.BinanceAccountInfo, error) {
 	info.PositionsCount = openPositions
 
 	// Get open orders count
-	orders, err := c.GetOpenOrders("")
+	orders, err := c.GetOpenOrders(ctx, "")
 	if err == nil {
 		info.OpenOrdersCount = len(orders)
 	}
@@ -184,14 +204,14 @@ func (c *TradingClient) GetAccountInfo() (*domain.BinanceAccountInfo, error) {
 }
 
 // GetOpenOrders retrieves all open orders (or for specific symbol)
-func (c *TradingClient) GetOpenOrders(symbol string) ([]map[string]interface{}, error) {
+func (c *TradingClient) GetOpenOrders(ctx context.Context, symbol string) ([]map[string]interface{}, error) {
 	endpoint := "/fapi/v1/openOrders"
 	params := url.Values{}
 	if symbol != "" {
 		params.Set("symbol", symbol)
 	}
 
-	resp, err := c.signedRequest("GET", endpoint, params)
+	resp, err := c.signedRequest(ctx, "GET", endpoint, params)
 	if err != nil {
 		return nil, err
 	}
@@ -215,14 +235,14 @@ func (c *TradingClient) GetOpenOrders(symbol string) ([]map[string]interface{},
 }
 
 // SetLeverage sets the leverage for a symbol (USDT-margined futures).
-func (c *TradingClient) SetLeverage(symbol string, leverage int) error {
+func (c *TradingClient) SetLeverage(ctx context.Context, symbol string, leverage int) error {
 	endpoint := "/fapi/v1/leverage"
 
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("leverage", strconv.Itoa(leverage))
 
-	resp, err := c.signedRequest("POST", endpoint, params)
+	resp, err := c.signedRequest(ctx, "POST", endpoint, params)
 	if err != nil {
 		return err
 	}
@@ -242,7 +262,7 @@ func (c *TradingClient) SetLeverage(symbol string, leverage int) error {
 
 // PlaceStopLossOrder places a STOP_MARKET order with closePosition=true so the stop lives on Binance.
 // positionSide should be "SHORT", "LONG", or "BOTH" depending on the account mode.
-func (c *TradingClient) PlaceStopLossOrder(symbol string, _ float64, stopPrice float64, positionSide string) (int64, error) {
+func (c *TradingClient) PlaceStopLossOrder(ctx context.Context, symbol string, _ float64, stopPrice float64, positionSide string) (int64, error) {
 	endpoint := "/fapi/v1/order"
 
 	side := "BUY"
@@ -263,7 +283,55 @@ func (c *TradingClient) PlaceStopLossOrder(symbol string, _ float64, stopPrice f
 		params.Set("positionSide", positionSide)
 	}
 
-	resp, err := c.signedRequest("POST", endpoint, params)
+	resp, err := c.signedRequest(ctx, "POST", endpoint, params)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, parseBinanceAPIError(resp.StatusCode, body)
+	}
+
+	var binanceResp struct {
+		OrderID int64 `json:"orderId"`
+	}
+	if err := json.Unmarshal(body, &binanceResp); err != nil {
+		return 0, err
+	}
+
+	return binanceResp.OrderID, nil
+}
+
+// PlaceTakeProfitOrder places a TAKE_PROFIT_MARKET order with closePosition=true so the take profit lives on Binance.
+// positionSide should be "SHORT", "LONG", or "BOTH" depending on the account mode.
+func (c *TradingClient) PlaceTakeProfitOrder(ctx context.Context, symbol string, _ float64, stopPrice float64, positionSide string) (int64, error) {
+	endpoint := "/fapi/v1/order"
+
+	side := "BUY"
+	if positionSide == "LONG" {
+		side = "SELL"
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", side)
+	params.Set("type", "TAKE_PROFIT_MARKET")
+	params.Set("stopPrice", fmt.Sprintf("%.8f", stopPrice))
+	params.Set("closePosition", "true")
+	params.Set("workingType", "MARK_PRICE")
+	params.Set("priceProtect", "true")
+
+	if positionSide != "" {
+		params.Set("positionSide", positionSide)
+	}
+
+	resp, err := c.signedRequest(ctx, "POST", endpoint, params)
 	if err != nil {
 		return 0, err
 	}
@@ -289,7 +357,7 @@ func (c *TradingClient) PlaceStopLossOrder(symbol string, _ float64, stopPrice f
 }
 
 // PlaceOrder places a new order
-func (c *TradingClient) PlaceOrder(req *domain.BinanceOrderRequest) (*domain.BinanceOrderResponse, error) {
+func (c *TradingClient) PlaceOrder(ctx context.Context, req *domain.BinanceOrderRequest) (*domain.BinanceOrderResponse, error) {
 	endpoint := "/fapi/v1/order"
 	
 	params := url.Values{}
@@ -301,13 +369,16 @@ func (c *TradingClient) PlaceOrder(req *domain.BinanceOrderRequest) (*domain.Bin
 	if req.PositionSide != "" {
 		params.Set("positionSide", req.PositionSide)
 	}
+	if req.ReduceOnly {
+		params.Set("reduceOnly", "true")
+	}
 	
 	if req.OrderType == "LIMIT" && req.Price > 0 {
 		params.Set("price", fmt.Sprintf("%.8f", req.Price))
 		params.Set("timeInForce", "GTC")
 	}
 
-	resp, err := c.signedRequest("POST", endpoint, params)
+	resp, err := c.signedRequest(ctx, "POST", endpoint, params)
 	if err != nil {
 		return nil, err
 	}
@@ -347,14 +418,14 @@ func (c *TradingClient) PlaceOrder(req *domain.BinanceOrderRequest) (*domain.Bin
 }
 
 // CancelOrder cancels an existing order
-func (c *TradingClient) CancelOrder(symbol string, orderID int64) error {
+func (c *TradingClient) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
 	endpoint := "/fapi/v1/order"
 	
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("orderId", strconv.FormatInt(orderID, 10))
 
-	resp, err := c.signedRequest("DELETE", endpoint, params)
+	resp, err := c.signedRequest(ctx, "DELETE", endpoint, params)
 	if err != nil {
 		return err
 	}
@@ -368,11 +439,361 @@ func (c *TradingClient) CancelOrder(symbol string, orderID int64) error {
 	return nil
 }
 
+// CancelAllOpenOrders cancels every open order of a symbol, stop and take
+// profit orders included
+func (c *TradingClient) CancelAllOpenOrders(ctx context.Context, symbol string) error {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+
+	resp, err := c.signedRequest(ctx, "DELETE", "/fapi/v1/allOpenOrders", params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return parseBinanceAPIError(resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// GetUserTrades retrieves the account's fills for a symbol between start and end.
+// Binance caps the window at 7 days and 1000 fills per call; callers page by time.
+func (c *TradingClient) GetUserTrades(ctx context.Context, symbol string, start, end time.Time) ([]domain.BinanceUserTrade, error) {
+	endpoint := "/fapi/v1/userTrades"
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("startTime", strconv.FormatInt(start.UnixMilli(), 10))
+	params.Set("endTime", strconv.FormatInt(end.UnixMilli(), 10))
+	params.Set("limit", "1000")
+
+	resp, err := c.signedRequest(ctx, "GET", endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceAPIError(resp.StatusCode, body)
+	}
+
+	var raw []struct {
+		ID              int64  `json:"id"`
+		OrderID         int64  `json:"orderId"`
+		Symbol          string `json:"symbol"`
+		Side            string `json:"side"`
+		PositionSide    string `json:"positionSide"`
+		Price           string `json:"price"`
+		Qty             string `json:"qty"`
+		RealizedPnl     string `json:"realizedPnl"`
+		Commission      string `json:"commission"`
+		CommissionAsset string `json:"commissionAsset"`
+		Time            int64  `json:"time"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	trades := make([]domain.BinanceUserTrade, 0, len(raw))
+	for _, t := range raw {
+		price, _ := strconv.ParseFloat(t.Price, 64)
+		qty, _ := strconv.ParseFloat(t.Qty, 64)
+		pnl, _ := strconv.ParseFloat(t.RealizedPnl, 64)
+		commission, _ := strconv.ParseFloat(t.Commission, 64)
+
+		trades = append(trades, domain.BinanceUserTrade{
+			ID:              t.ID,
+			OrderID:         t.OrderID,
+			Symbol:          t.Symbol,
+			Side:            t.Side,
+			PositionSide:    t.PositionSide,
+			Price:           price,
+			Qty:             qty,
+			RealizedPnl:     pnl,
+			Commission:      math.Abs(commission),
+			CommissionAsset: t.CommissionAsset,
+			Time:            time.UnixMilli(t.Time),
+		})
+	}
+
+	return trades, nil
+}
+
+// GetIncomeHistory retrieves futures income records of the given type (empty = all types)
+// between start and end, up to 1000 records per call.
+func (c *TradingClient) GetIncomeHistory(ctx context.Context, incomeType string, start, end time.Time) ([]domain.BinanceIncome, error) {
+	endpoint := "/fapi/v1/income"
+
+	params := url.Values{}
+	if incomeType != "" {
+		params.Set("incomeType", incomeType)
+	}
+	params.Set("startTime", strconv.FormatInt(start.UnixMilli(), 10))
+	params.Set("endTime", strconv.FormatInt(end.UnixMilli(), 10))
+	params.Set("limit", "1000")
+
+	resp, err := c.signedRequest(ctx, "GET", endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceAPIError(resp.StatusCode, body)
+	}
+
+	var raw []struct {
+		Symbol     string `json:"symbol"`
+		IncomeType string `json:"incomeType"`
+		Income     string `json:"income"`
+		Asset      string `json:"asset"`
+		Time       int64  `json:"time"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	incomes := make([]domain.BinanceIncome, 0, len(raw))
+	for _, in := range raw {
+		amount, _ := strconv.ParseFloat(in.Income, 64)
+		incomes = append(incomes, domain.BinanceIncome{
+			Symbol:     in.Symbol,
+			IncomeType: in.IncomeType,
+			Income:     amount,
+			Asset:      in.Asset,
+			Time:       time.UnixMilli(in.Time),
+		})
+	}
+
+	return incomes, nil
+}
+
+// ErrSubAccountUnsupported is returned when sub-account endpoints are used on testnet.
+var ErrSubAccountUnsupported = errors.New("sub-account endpoints are not available on testnet")
+
+// GetSubAccounts lists the sub-accounts under the master account (master key required)
+func (c *TradingClient) GetSubAccounts(ctx context.Context) ([]domain.BinanceSubAccount, error) {
+	if c.sapiBaseURL == "" {
+		return nil, ErrSubAccountUnsupported
+	}
+
+	params := url.Values{}
+	params.Set("limit", "200")
+
+	resp, err := c.signedRequestTo(ctx, c.sapiBaseURL, "GET", "/sapi/v1/sub-account/list", params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceAPIError(resp.StatusCode, body)
+	}
+
+	var binanceResp struct {
+		SubAccounts []domain.BinanceSubAccount `json:"subAccounts"`
+	}
+	if err := json.Unmarshal(body, &binanceResp); err != nil {
+		return nil, err
+	}
+
+	return binanceResp.SubAccounts, nil
+}
+
+// GetSubAccountFuturesAccount retrieves USDT-margined futures balances and positions
+// of a sub-account, queried through the master account.
+func (c *TradingClient) GetSubAccountFuturesAccount(ctx context.Context, email string) (*domain.BinanceAccountInfo, error) {
+	if c.sapiBaseURL == "" {
+		return nil, ErrSubAccountUnsupported
+	}
+
+	params := url.Values{}
+	params.Set("email", email)
+	params.Set("futuresType", "1") // 1 = USDT-margined
+
+	resp, err := c.signedRequestTo(ctx, c.sapiBaseURL, "GET", "/sapi/v2/sub-account/futures/account", params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceAPIError(resp.StatusCode, body)
+	}
+
+	var binanceResp struct {
+		FutureAccountResp struct {
+			TotalWalletBalance    string `json:"totalWalletBalance"`
+			MaxWithdrawAmount     string `json:"maxWithdrawAmount"`
+			TotalUnrealizedProfit string `json:"totalUnrealizedProfit"`
+			Assets                []struct {
+				Asset             string `json:"asset"`
+				WalletBalance     string `json:"walletBalance"`
+				MaxWithdrawAmount string `json:"maxWithdrawAmount"`
+			} `json:"assets"`
+		} `json:"futureAccountResp"`
+	}
+	if err := json.Unmarshal(body, &binanceResp); err != nil {
+		return nil, err
+	}
+
+	acct := binanceResp.FutureAccountResp
+	totalBalance, _ := strconv.ParseFloat(acct.TotalWalletBalance, 64)
+	availableBalance, _ := strconv.ParseFloat(acct.MaxWithdrawAmount, 64)
+	totalUnrealizedPL, _ := strconv.ParseFloat(acct.TotalUnrealizedProfit, 64)
+
+	info := &domain.BinanceAccountInfo{
+		TotalBalance:      totalBalance,
+		AvailableBalance:  availableBalance,
+		TotalUnrealizedPL: totalUnrealizedPL,
+		Assets:            []domain.BinanceAsset{},
+		Positions:         []domain.BinancePosition{},
+	}
+
+	for _, asset := range acct.Assets {
+		balance, _ := strconv.ParseFloat(asset.WalletBalance, 64)
+		available, _ := strconv.ParseFloat(asset.MaxWithdrawAmount, 64)
+
+		if asset.Asset == "USDT" {
+			info.UsdtBalance = balance
+		}
+
+		info.Assets = append(info.Assets, domain.BinanceAsset{
+			Asset:            asset.Asset,
+			Balance:          balance,
+			AvailableBalance: available,
+			UsdValue:         balance, // Simplified
+		})
+	}
+
+	positions, err := c.GetSubAccountPositions(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	info.Positions = positions
+	info.PositionsCount = len(positions)
+
+	return info, nil
+}
+
+// GetSubAccountPositions retrieves open USDT-margined futures positions of a sub-account
+func (c *TradingClient) GetSubAccountPositions(ctx context.Context, email string) ([]domain.BinancePosition, error) {
+	if c.sapiBaseURL == "" {
+		return nil, ErrSubAccountUnsupported
+	}
+
+	params := url.Values{}
+	params.Set("email", email)
+	params.Set("futuresType", "1")
+
+	resp, err := c.signedRequestTo(ctx, c.sapiBaseURL, "GET", "/sapi/v2/sub-account/futures/positionRisk", params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseBinanceAPIError(resp.StatusCode, body)
+	}
+
+	var binanceResp struct {
+		FuturePositionRiskVos []struct {
+			Symbol           string `json:"symbol"`
+			PositionAmount   string `json:"positionAmount"`
+			EntryPrice       string `json:"entryPrice"`
+			MarkPrice        string `json:"markPrice"`
+			UnrealizedProfit string `json:"unrealizedProfit"`
+			Leverage         string `json:"leverage"`
+		} `json:"futurePositionRiskVos"`
+	}
+	if err := json.Unmarshal(body, &binanceResp); err != nil {
+		return nil, err
+	}
+
+	positions := []domain.BinancePosition{}
+	for _, pos := range binanceResp.FuturePositionRiskVos {
+		posAmt, _ := strconv.ParseFloat(pos.PositionAmount, 64)
+		if posAmt == 0 {
+			continue // Skip empty positions
+		}
+
+		entryPrice, _ := strconv.ParseFloat(pos.EntryPrice, 64)
+		markPrice, _ := strconv.ParseFloat(pos.MarkPrice, 64)
+		unrealizedProfit, _ := strconv.ParseFloat(pos.UnrealizedProfit, 64)
+		leverage, _ := strconv.Atoi(pos.Leverage)
+
+		// The sub-account endpoint does not report hedge-mode side; derive it from the sign.
+		positionSide := "LONG"
+		if posAmt < 0 {
+			positionSide = "SHORT"
+		}
+
+		positions = append(positions, domain.BinancePosition{
+			Symbol:           pos.Symbol,
+			PositionSide:     positionSide,
+			PositionAmount:   posAmt,
+			EntryPrice:       entryPrice,
+			MarkPrice:        markPrice,
+			UnrealizedProfit: unrealizedProfit,
+			Leverage:         leverage,
+		})
+	}
+
+	return positions, nil
+}
+
 // signedRequest makes a signed API request
-func (c *TradingClient) signedRequest(method, endpoint string, params url.Values) (*http.Response, error) {
+func (c *TradingClient) signedRequest(ctx context.Context, method, endpoint string, params url.Values) (*http.Response, error) {
+	return c.signedRequestTo(ctx, c.baseURL, method, endpoint, params)
+}
+
+// signedRequestTo makes a signed API request against a specific host,
+// repeating it after a transient failure. Every request but placing an order
+// is idempotent (setting leverage and cancelling included); orders go
+// through placeOrder.
+func (c *TradingClient) signedRequestTo(ctx context.Context, baseURL, method, endpoint string, params url.Values) (*http.Response, error) {
 	if params == nil {
 		params = url.Values{}
 	}
+	if method == http.MethodPost && endpoint == orderEndpoint {
+		return c.placeOrder(ctx, baseURL, params)
+	}
+	return retry(ctx, "signed", endpoint, func() (*http.Response, error) {
+		return c.send(ctx, baseURL, method, endpoint, params)
+	})
+}
+
+// send signs params with a fresh timestamp and makes one request
+func (c *TradingClient) send(ctx context.Context, baseURL, method, endpoint string, params url.Values) (*http.Response, error) {
+	params.Del("signature")
 
 	// Add timestamp
 	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
@@ -384,10 +805,10 @@ func (c *TradingClient) signedRequest(method, endpoint string, params url.Values
 	params.Set("signature", signature)
 
 	// Build URL
-	fullURL := c.baseURL + endpoint + "?" + params.Encode()
+	fullURL := baseURL + endpoint + "?" + params.Encode()
 
 	// Create request
-	req, err := http.NewRequest(method, fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
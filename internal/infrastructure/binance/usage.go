@@ -0,0 +1,187 @@
+package binance
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"screener-backend/internal/infrastructure/metrics"
+	"screener-backend/internal/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("screener-backend/binance")
+
+// Request weight limits per minute (exchangeInfo rateLimits)
+var weightLimits = map[string]int{
+	"fapi.binance.com":          2400,
+	"testnet.binancefuture.com": 2400,
+	"api.binance.com":           6000,
+}
+
+var (
+	requestsTotal = metrics.NewCounterVec("binance_requests_total",
+		"Binance REST calls by API kind, endpoint and HTTP status.", "api", "endpoint", "status")
+	requestDuration = metrics.NewHistogramVec("binance_request_duration_seconds",
+		"Binance REST call latency.", metrics.DefaultBuckets, "api", "endpoint")
+	usedWeight = metrics.NewGaugeVec("binance_used_weight_1m",
+		"Request weight used in the current minute, from X-MBX-USED-WEIGHT-1M.", "host")
+	orderCount = metrics.NewGaugeVec("binance_order_count_1m",
+		"Orders placed in the current minute, from X-MBX-ORDER-COUNT-1M.", "host")
+)
+
+// HostUsage is the latest rate-limit state reported by one Binance host
+type HostUsage struct {
+	Host          string    `json:"host"`
+	UsedWeight1m  int       `json:"usedWeight1m"`
+	WeightLimit1m int       `json:"weightLimit1m,omitempty"`
+	HeadroomPct   *float64  `json:"headroomPct,omitempty"`
+	OrderCount1m  int       `json:"orderCount1m"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// EndpointUsage aggregates calls to one endpoint since startup
+type EndpointUsage struct {
+	API          string  `json:"api"` // public or signed
+	Endpoint     string  `json:"endpoint"`
+	Calls        int64   `json:"calls"`
+	Errors       int64   `json:"errors"`      // transport errors and non-2xx
+	RateLimited  int64   `json:"rateLimited"` // 429/418
+	AvgLatencyMs float64 `json:"avgLatencyMs"`
+	MaxLatencyMs float64 `json:"maxLatencyMs"`
+}
+
+// UsageSummary is the operator view of Binance API consumption
+type UsageSummary struct {
+	Since     time.Time       `json:"since"`
+	Hosts     []HostUsage     `json:"hosts"`
+	Endpoints []EndpointUsage `json:"endpoints"`
+}
+
+type endpointTotals struct {
+	EndpointUsage
+	totalLatency time.Duration
+}
+
+var usage = struct {
+	mu        sync.Mutex
+	since     time.Time
+	hosts     map[string]*HostUsage
+	endpoints map[string]*endpointTotals
+}{
+	since:     time.Now(),
+	hosts:     make(map[string]*HostUsage),
+	endpoints: make(map[string]*endpointTotals),
+}
+
+// Usage returns call counts per endpoint and the last reported weight per host
+func Usage() UsageSummary {
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+
+	summary := UsageSummary{Since: usage.since, Hosts: []HostUsage{}, Endpoints: []EndpointUsage{}}
+	for _, h := range usage.hosts {
+		summary.Hosts = append(summary.Hosts, *h)
+	}
+	for _, e := range usage.endpoints {
+		u := e.EndpointUsage
+		if u.Calls > 0 {
+			u.AvgLatencyMs = float64(e.totalLatency.Microseconds()) / 1000 / float64(u.Calls)
+		}
+		summary.Endpoints = append(summary.Endpoints, u)
+	}
+	sort.Slice(summary.Hosts, func(i, j int) bool { return summary.Hosts[i].Host < summary.Hosts[j].Host })
+	sort.Slice(summary.Endpoints, func(i, j int) bool { return summary.Endpoints[i].Calls > summary.Endpoints[j].Calls })
+	return summary
+}
+
+// instrumentedTransport records every Binance call for /metrics and Usage
+type instrumentedTransport struct {
+	api  string
+	next http.RoundTripper
+}
+
+func newInstrumentedTransport(api string) http.RoundTripper {
+	return &instrumentedTransport{api: api, next: http.DefaultTransport}
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Path
+	_, span := tracer.Start(req.Context(), "binance "+endpoint, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("binance.api", t.api),
+			attribute.String("http.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+		))
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	tracing.EndSpan(span, err)
+	requestsTotal.Inc(t.api, endpoint, status)
+	requestDuration.Observe(elapsed.Seconds(), t.api, endpoint)
+
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+
+	key := t.api + " " + endpoint
+	e, ok := usage.endpoints[key]
+	if !ok {
+		e = &endpointTotals{EndpointUsage: EndpointUsage{API: t.api, Endpoint: endpoint}}
+		usage.endpoints[key] = e
+	}
+	e.Calls++
+	e.totalLatency += elapsed
+	if ms := float64(elapsed.Microseconds()) / 1000; ms > e.MaxLatencyMs {
+		e.MaxLatencyMs = ms
+	}
+	if err != nil || resp.StatusCode >= 300 {
+		e.Errors++
+	}
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 418 {
+		e.RateLimited++
+	}
+
+	host := req.URL.Hostname()
+	weight, werr := strconv.Atoi(resp.Header.Get("X-MBX-USED-WEIGHT-1M"))
+	orders, oerr := strconv.Atoi(resp.Header.Get("X-MBX-ORDER-COUNT-1M"))
+	if werr != nil && oerr != nil {
+		return resp, nil
+	}
+	h, ok := usage.hosts[host]
+	if !ok {
+		h = &HostUsage{Host: host, WeightLimit1m: weightLimits[host]}
+		usage.hosts[host] = h
+	}
+	h.UpdatedAt = time.Now()
+	if werr == nil {
+		h.UsedWeight1m = weight
+		usedWeight.Set(float64(weight), host)
+		if h.WeightLimit1m > 0 {
+			headroom := float64(h.WeightLimit1m-weight) / float64(h.WeightLimit1m) * 100
+			h.HeadroomPct = &headroom
+		}
+	}
+	if oerr == nil {
+		h.OrderCount1m = orders
+		orderCount.Set(float64(orders), host)
+	}
+	return resp, nil
+}
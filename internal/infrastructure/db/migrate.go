@@ -2,78 +2,244 @@ package db
 
 import (
 	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// Migrate creates the minimal tables needed by this app.
-// This keeps setup simple (no external migration tool), but still gives persistence.
+// Migrations live in migrations/ as NNNN_name.up.sql / NNNN_name.down.sql.
+// Add new schema changes as a new file pair; never edit an applied one.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID serializes migrations across instances (pg advisory lock key)
+const migrationLockID = 7240315
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Version int64  `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+// LoadMigrations parses the embedded migration files, ordered by version.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		name := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		versionStr, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected NNNN_name", name)
+		}
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", name, err)
+		}
+
+		body, err := migrationFiles.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("migration version %d used by %s and %s", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrate applies every pending migration in order, each in its own
+// transaction, and records it in schema_migrations.
 func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
-	stmts := []string{
-		`create table if not exists binance_credentials (
-			user_id text primary key,
-			api_key text not null,
-			secret_key_enc text not null,
-			is_testnet boolean not null default false,
-			is_enabled boolean not null default true,
-			permissions jsonb not null default '[]'::jsonb,
-			created_at timestamptz not null default now(),
-			updated_at timestamptz not null default now(),
-			last_tested timestamptz not null default '1970-01-01'::timestamptz
-		);`,
-		`create table if not exists binance_trading_config (
-			user_id text primary key,
-			trade_amount_usdt double precision not null default 10,
-			leverage int not null default 1,
-			order_type text not null default 'MARKET',
-			max_slippage_percent double precision not null default 0.5,
-			max_daily_loss_usdt double precision not null default 100,
-			max_daily_trades int not null default 10,
-			enable_real_trading boolean not null default false,
-			use_stop_loss boolean not null default true,
-			use_take_profit boolean not null default true,
-			default_stop_loss_pct double precision not null default 0.8,
-			default_take_profit_pct double precision not null default 1.5,
-			updated_at timestamptz not null default now()
-		);`,
-		`create table if not exists autoscalp_entries (
-			id text primary key,
-			symbol text not null,
-			entry_price double precision not null,
-			stop_loss double precision not null,
-			entry_time timestamptz not null,
-			exit_price double precision null,
-			exit_time timestamptz null,
-			exit_reason text not null default '',
-			profit_loss double precision null,
-			profit_loss_pct double precision null,
-			duration_seconds int not null default 0,
-			status text not null,
-			entry_score double precision not null default 0,
-			highest_price double precision not null default 0,
-			trailing_stop_pct double precision not null default 0,
-
-			is_real_trade boolean not null default false,
-			binance_order_id bigint null,
-			binance_sl_order_id bigint null,
-			quantity double precision not null default 0,
-			leverage int not null default 0
-		);`,
-		`create index if not exists autoscalp_entries_status_idx on autoscalp_entries(status);`,
-		`create index if not exists autoscalp_entries_exit_time_idx on autoscalp_entries(exit_time);`,
-		`create index if not exists autoscalp_entries_symbol_entry_time_idx on autoscalp_entries(symbol, entry_time desc);`,
-		`create table if not exists emergency_stop_events (
-			id bigserial primary key,
-			user_id text not null,
-			occurred_at timestamptz not null,
-			reason text not null
-		);`,
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+
+	return withMigrationLock(ctx, pool, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if applied[m.Version] {
+				continue
+			}
+			if err := runMigration(ctx, conn, m.Up, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, `insert into schema_migrations (version, name) values ($1, $2)`, m.Version, m.Name)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", m.Version, m.Name, err)
+			}
+			log.Printf("DB migration applied: %04d_%s", m.Version, m.Name)
+		}
+		return nil
+	})
+}
+
+// MigrateDown rolls back the latest `steps` applied migrations.
+func MigrateDown(ctx context.Context, pool *pgxpool.Pool, steps int) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+
+	return withMigrationLock(ctx, pool, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			m := migrations[i]
+			if !applied[m.Version] {
+				continue
+			}
+			if m.Down == "" {
+				return fmt.Errorf("migration %04d_%s has no down file", m.Version, m.Name)
+			}
+			if err := runMigration(ctx, conn, m.Down, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, `delete from schema_migrations where version = $1`, m.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %04d_%s down: %w", m.Version, m.Name, err)
+			}
+			log.Printf("DB migration rolled back: %04d_%s", m.Version, m.Name)
+			steps--
+		}
+		return nil
+	})
+}
+
+// Status lists every known migration and whether it is applied.
+func Status(ctx context.Context, pool *pgxpool.Pool) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
 	}
 
-	for _, stmt := range stmts {
-		if _, err := pool.Exec(ctx, stmt); err != nil {
+	var statuses []MigrationStatus
+	err = withMigrationLock(ctx, pool, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
 			return err
 		}
+		for _, m := range migrations {
+			statuses = append(statuses, MigrationStatus{Version: m.Version, Name: m.Name, Applied: applied[m.Version]})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withMigrationLock runs fn on one connection holding the migration advisory
+// lock, after making sure schema_migrations exists.
+func withMigrationLock(ctx context.Context, pool *pgxpool.Pool, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `select pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), `select pg_advisory_unlock($1)`, migrationLockID)
+
+	if _, err := conn.Exec(ctx, `create table if not exists schema_migrations (
+		version bigint primary key,
+		name text not null,
+		applied_at timestamptz not null default now()
+	)`); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]bool, error) {
+	rows, err := conn.Query(ctx, `select version from schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// runMigration executes a migration script and its bookkeeping atomically.
+// Scripts run over the simple protocol, so a file may hold several statements.
+func runMigration(ctx context.Context, conn *pgxpool.Conn, script string, record func(tx pgx.Tx) error) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Schema changes may outlive the pool's statement timeout
+	if _, err := tx.Exec(ctx, `set local statement_timeout = 0`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, script, pgx.QueryExecModeSimpleProtocol); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
 	}
-	return nil
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,5 @@
+drop table if exists trade_entries;
+drop table if exists emergency_stop_events;
+drop table if exists autoscalp_entries;
+drop table if exists binance_trading_config;
+drop table if exists binance_credentials;
@@ -0,0 +1,14 @@
+drop index if exists trade_entries_user_id_idx;
+alter table trade_entries
+	drop column if exists user_id,
+	drop column if exists notes,
+	drop column if exists tags,
+	drop column if exists strategy,
+	drop column if exists risk_reward,
+	drop column if exists fees,
+	drop column if exists funding,
+	drop column if exists signal_snapshot,
+	drop column if exists quantity,
+	drop column if exists leverage,
+	drop column if exists notional,
+	drop column if exists archived_at;
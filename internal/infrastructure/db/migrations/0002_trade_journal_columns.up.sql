@@ -0,0 +1,13 @@
+alter table trade_entries add column if not exists user_id text not null default '';
+create index if not exists trade_entries_user_id_idx on trade_entries(user_id);
+alter table trade_entries add column if not exists notes text not null default '';
+alter table trade_entries add column if not exists tags text[] not null default '{}';
+alter table trade_entries add column if not exists strategy text not null default 'manual';
+alter table trade_entries add column if not exists risk_reward double precision not null default 0;
+alter table trade_entries add column if not exists fees double precision not null default 0;
+alter table trade_entries add column if not exists funding double precision not null default 0;
+alter table trade_entries add column if not exists signal_snapshot jsonb;
+alter table trade_entries add column if not exists quantity double precision not null default 0;
+alter table trade_entries add column if not exists leverage integer not null default 0;
+alter table trade_entries add column if not exists notional double precision not null default 0;
+alter table trade_entries add column if not exists archived_at timestamptz;
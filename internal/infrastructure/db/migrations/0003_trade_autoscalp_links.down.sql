@@ -0,0 +1,2 @@
+alter table trade_entries drop column if exists autoscalp_entry_id;
+alter table autoscalp_entries drop column if exists trade_entry_id;
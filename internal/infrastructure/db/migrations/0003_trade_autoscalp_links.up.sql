@@ -0,0 +1,2 @@
+alter table trade_entries add column if not exists autoscalp_entry_id text not null default '';
+alter table autoscalp_entries add column if not exists trade_entry_id text not null default '';
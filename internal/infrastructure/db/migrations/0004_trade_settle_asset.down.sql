@@ -0,0 +1,3 @@
+alter table trade_entries
+	drop column if exists settle_asset,
+	drop column if exists profit_loss_native;
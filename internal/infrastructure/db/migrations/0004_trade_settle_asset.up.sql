@@ -0,0 +1,2 @@
+alter table trade_entries add column if not exists settle_asset text not null default '';
+alter table trade_entries add column if not exists profit_loss_native double precision;
@@ -0,0 +1,9 @@
+create table if not exists idempotency_keys (
+	scope text not null,
+	key text not null,
+	status_code integer not null default 0,
+	content_type text not null default '',
+	body bytea not null default '',
+	created_at timestamptz not null default now(),
+	primary key (scope, key)
+);
@@ -0,0 +1 @@
+drop table if exists trade_daily_summaries;
@@ -0,0 +1,13 @@
+create table if not exists trade_daily_summaries (
+	user_id text not null,
+	date text not null,
+	trades_taken integer not null default 0,
+	trades_closed integer not null default 0,
+	net_pl double precision not null default 0,
+	win_rate double precision not null default 0,
+	open_trades integer not null default 0,
+	open_risk double precision not null default 0,
+	notified boolean not null default false,
+	created_at timestamptz not null default now(),
+	primary key (user_id, date)
+);
@@ -0,0 +1,4 @@
+alter table binance_credentials
+	drop column if exists sub_account_email,
+	drop column if exists sub_account_api_key,
+	drop column if exists sub_account_secret_enc;
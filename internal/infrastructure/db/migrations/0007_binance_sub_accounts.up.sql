@@ -0,0 +1,3 @@
+alter table binance_credentials add column if not exists sub_account_email text not null default '';
+alter table binance_credentials add column if not exists sub_account_api_key text not null default '';
+alter table binance_credentials add column if not exists sub_account_secret_enc text not null default '';
@@ -0,0 +1,2 @@
+drop table if exists coin_snapshots;
+drop table if exists kline_archive;
@@ -0,0 +1,23 @@
+-- Monthly partitions are created on demand by the archive repository
+create table if not exists kline_archive (
+	symbol text not null,
+	interval text not null,
+	open_time timestamptz not null,
+	open double precision not null,
+	high double precision not null,
+	low double precision not null,
+	close double precision not null,
+	volume double precision not null default 0,
+	quote_volume double precision not null default 0,
+	taker_buy_quote double precision not null default 0,
+	primary key (symbol, interval, open_time)
+) partition by range (open_time);
+create table if not exists coin_snapshots (
+	symbol text not null,
+	taken_at timestamptz not null,
+	score double precision not null default 0,
+	status text not null default '',
+	price double precision not null default 0,
+	data jsonb not null,
+	primary key (symbol, taken_at)
+) partition by range (taken_at);
@@ -0,0 +1,4 @@
+create table if not exists notification_cooldowns (
+	key text primary key,
+	notified_at timestamptz not null
+);
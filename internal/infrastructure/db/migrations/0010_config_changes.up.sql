@@ -0,0 +1,8 @@
+create table if not exists config_changes (
+	id bigserial primary key,
+	author text not null,
+	source text not null,
+	created_at timestamptz not null,
+	diff jsonb not null,
+	rollback_of bigint references config_changes(id)
+);
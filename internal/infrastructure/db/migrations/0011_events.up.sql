@@ -0,0 +1,8 @@
+create table if not exists events (
+	id bigserial primary key,
+	topic text not null,
+	payload bytea not null,
+	created_at timestamptz not null default now()
+);
+
+create index if not exists events_created_at_idx on events (created_at);
@@ -0,0 +1,14 @@
+create table if not exists signals (
+	id bigserial primary key,
+	symbol text not null,
+	strategy text not null,
+	direction text not null,
+	score double precision not null,
+	price double precision not null,
+	signaled_at timestamptz not null,
+	outcomes jsonb not null default '{}',
+	labeled boolean not null default false
+);
+
+create index if not exists signals_strategy_signaled_at_idx on signals (strategy, signaled_at);
+create index if not exists signals_unlabeled_idx on signals (signaled_at) where not labeled;
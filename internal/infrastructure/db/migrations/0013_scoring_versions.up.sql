@@ -0,0 +1,11 @@
+create table if not exists scoring_versions (
+	id bigserial primary key,
+	created_at timestamptz not null,
+	source text not null,
+	status text not null,
+	weights jsonb not null,
+	trigger_score double precision not null,
+	horizon text not null,
+	test_metrics jsonb not null,
+	note text not null default ''
+);
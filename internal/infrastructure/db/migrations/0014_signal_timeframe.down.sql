@@ -0,0 +1 @@
+alter table signals drop column if exists timeframe;
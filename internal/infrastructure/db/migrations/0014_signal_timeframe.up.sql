@@ -0,0 +1 @@
+alter table signals add column if not exists timeframe text not null default '';
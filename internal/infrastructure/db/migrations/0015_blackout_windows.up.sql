@@ -0,0 +1,11 @@
+create table if not exists blackout_windows (
+	id bigserial primary key,
+	kind text not null,
+	name text not null,
+	starts_at timestamptz not null,
+	ends_at timestamptz not null,
+	created_at timestamptz not null,
+	created_by text not null default ''
+);
+
+create index if not exists blackout_windows_ends_at_idx on blackout_windows(ends_at);
@@ -0,0 +1,8 @@
+create table if not exists cycle_recordings (
+	id bigserial primary key,
+	started_at timestamptz not null,
+	symbols text[] not null,
+	data bytea not null
+);
+
+create index if not exists cycle_recordings_started_at_idx on cycle_recordings(started_at);
@@ -0,0 +1,10 @@
+create table if not exists watchlist_items (
+	user_id text not null,
+	symbol text not null,
+	alert_score double precision,
+	breakout_alert_score double precision,
+	note text not null default '',
+	created_at timestamptz not null,
+	updated_at timestamptz not null,
+	primary key (user_id, symbol)
+);
@@ -0,0 +1,6 @@
+create table if not exists users (
+	id text primary key,
+	email text not null unique,
+	password_hash text not null,
+	created_at timestamptz not null
+);
@@ -0,0 +1,5 @@
+create table if not exists screener_config (
+	id integer primary key check (id = 1),
+	config jsonb not null,
+	updated_at timestamptz not null
+);
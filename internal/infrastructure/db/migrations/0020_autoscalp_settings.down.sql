@@ -0,0 +1,3 @@
+drop index if exists autoscalp_entries_user_status_idx;
+alter table autoscalp_entries drop column if exists user_id;
+drop table if exists autoscalp_settings;
@@ -0,0 +1,14 @@
+create table if not exists autoscalp_settings (
+	user_id text primary key,
+	enabled boolean not null default false,
+	max_concurrent_trades int not null,
+	min_entry_score double precision not null,
+	stop_loss_percent double precision not null,
+	min_profit_percent double precision not null,
+	trailing_stop_percent double precision not null,
+	max_position_time int not null,
+	trailing_mode text not null default 'percent',
+	updated_at timestamptz not null
+);
+alter table autoscalp_entries add column if not exists user_id text not null default '';
+create index if not exists autoscalp_entries_user_status_idx on autoscalp_entries(user_id, status);
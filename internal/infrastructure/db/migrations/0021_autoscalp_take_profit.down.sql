@@ -0,0 +1 @@
+alter table autoscalp_entries drop column if exists binance_tp_order_id;
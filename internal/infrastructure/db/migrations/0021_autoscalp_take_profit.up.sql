@@ -0,0 +1 @@
+alter table autoscalp_entries add column if not exists binance_tp_order_id bigint null;
@@ -0,0 +1,19 @@
+-- The top coins of every screening cycle, for score history. Monthly
+-- partitions are created on demand by the archive repository.
+create table if not exists screener_snapshots (
+	symbol text not null,
+	taken_at timestamptz not null,
+	rank integer not null,
+	price double precision not null default 0,
+	score double precision not null default 0,
+	status text not null default '',
+	intraday_score double precision not null default 0,
+	intraday_status text not null default '',
+	pullback_score double precision not null default 0,
+	pullback_status text not null default '',
+	breakout_score double precision not null default 0,
+	breakout_status text not null default '',
+	follow_trend_score double precision not null default 0,
+	follow_trend_status text not null default '',
+	primary key (symbol, taken_at)
+) partition by range (taken_at);
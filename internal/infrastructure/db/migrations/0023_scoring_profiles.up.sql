@@ -0,0 +1,6 @@
+-- Each user's weighting of the reversal score components
+create table if not exists scoring_profiles (
+	user_id text primary key,
+	weights jsonb not null,
+	updated_at timestamptz not null
+);
@@ -0,0 +1,2 @@
+-- The previous owner of a backfilled entry is not recorded; nothing to undo.
+select 1;
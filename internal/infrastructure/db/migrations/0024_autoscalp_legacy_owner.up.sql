@@ -0,0 +1,7 @@
+-- Entries opened before settings were per user have no owner. On a
+-- single-account install the only credentials holder opened them, so they
+-- are handed to that user and monitored with their settings again.
+update autoscalp_entries
+set user_id = (select user_id from binance_credentials)
+where user_id = ''
+	and (select count(*) from binance_credentials) = 1;
@@ -5,23 +5,30 @@ import (
 	"errors"
 	"net"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PoolConfig struct {
+	// Name labels the pool's query spans (db.pool)
+	Name              string
 	MaxConns          int32
 	MinConns          int32
 	MaxConnLifetime   time.Duration
 	MaxConnIdleTime   time.Duration
 	HealthCheckPeriod time.Duration
+	// StatementTimeout is set on every connection; 0 keeps the server default
+	StatementTimeout time.Duration
 }
 
 func DefaultPoolConfig() PoolConfig {
 	return PoolConfig{
+		Name:              "main",
 		MaxConns:          10,
 		MinConns:          2,
 		MaxConnLifetime:   30 * time.Minute,
@@ -122,6 +129,17 @@ func NewPool(ctx context.Context, databaseURL string, cfg PoolConfig) (*pgxpool.
 		return nil, errors.New("no IPv4 addresses resolved for database host")
 	}
 
+	poolCfg.ConnConfig.Tracer = queryTracer{pool: cfg.Name}
+	if cfg.StatementTimeout > 0 {
+		// Set after connecting rather than as a startup parameter, which
+		// PgBouncer-style poolers reject
+		timeout := strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+		poolCfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+			_, err := conn.Exec(ctx, "set statement_timeout = "+timeout)
+			return err
+		}
+	}
+
 	poolCfg.MaxConns = cfg.MaxConns
 	poolCfg.MinConns = cfg.MinConns
 	poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
@@ -130,3 +148,34 @@ func NewPool(ctx context.Context, databaseURL string, cfg PoolConfig) (*pgxpool.
 
 	return pgxpool.NewWithConfig(ctx, poolCfg)
 }
+
+// ResolveDatabaseURL returns DATABASE_URL, falling back to Heroku Postgres
+// add-on variables. Empty means no database is configured.
+func ResolveDatabaseURL() string {
+	if v := strings.TrimSpace(os.Getenv("DATABASE_URL")); v != "" {
+		return v
+	}
+
+	// Common case: user references the add-on variable explicitly.
+	if v := strings.TrimSpace(os.Getenv("HEROKU_POSTGRESQL_YELLOW_URL")); v != "" {
+		return v
+	}
+
+	// Fallback: scan any Heroku Postgres add-on URL.
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := parts[0]
+		val := strings.TrimSpace(parts[1])
+		if val == "" {
+			continue
+		}
+		if strings.HasPrefix(key, "HEROKU_POSTGRESQL_") && strings.HasSuffix(key, "_URL") {
+			return val
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"screener-backend/internal/infrastructure/tracing"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxTracedStatement = 500
+
+var tracer = tracing.Tracer("screener-backend/db")
+
+// queryTracer wraps every pgx query in a client span carrying the SQL text
+type queryTracer struct {
+	pool string
+}
+
+func (t queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	stmt := strings.Join(strings.Fields(data.SQL), " ")
+	if len(stmt) > maxTracedStatement {
+		stmt = stmt[:maxTracedStatement] + "..."
+	}
+	op, _, _ := strings.Cut(stmt, " ")
+	ctx, _ = tracer.Start(ctx, "db "+strings.ToLower(op), trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", stmt),
+			attribute.String("db.pool", t.pool),
+		))
+	return ctx
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	tracing.EndSpan(span, data.Err)
+}
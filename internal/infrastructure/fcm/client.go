@@ -7,6 +7,8 @@ import (
 	"log"
 	"os"
 
+	"screener-backend/internal/infrastructure/logging"
+
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/messaging"
 	"google.golang.org/api/option"
@@ -16,15 +18,14 @@ type Client struct {
 	client *messaging.Client
 }
 
-// NewClient initializes Firebase Cloud Messaging client
-func NewClient() (*Client, error) {
+// NewClient initializes Firebase Cloud Messaging client from a credentials
+// file path or, when empty, the credentials JSON itself
+func NewClient(credPath, credJSON string) (*Client, error) {
 	ctx := context.Background()
 
 	// Check for Firebase credentials
-	credPath := os.Getenv("FIREBASE_CREDENTIALS_PATH")
 	if credPath == "" {
-		// Try to read from environment variable JSON string
-		credJSON := os.Getenv("FIREBASE_CREDENTIALS_JSON")
+		// Fall back to the JSON string
 		if credJSON == "" {
 			log.Println("Warning: No Firebase credentials found. FCM disabled.")
 			return &Client{client: nil}, nil
@@ -60,7 +61,7 @@ func NewClient() (*Client, error) {
 }
 
 // SendNotification sends a push notification to a specific device token
-func (c *Client) SendNotification(token, title, body string, data map[string]string) error {
+func (c *Client) SendNotification(ctx context.Context, token, title, body string, data map[string]string) error {
 	if c.client == nil {
 		return fmt.Errorf("FCM client not initialized")
 	}
@@ -81,18 +82,17 @@ func (c *Client) SendNotification(token, title, body string, data map[string]str
 		},
 	}
 
-	ctx := context.Background()
 	response, err := c.client.Send(ctx, message)
 	if err != nil {
 		return fmt.Errorf("error sending message: %w", err)
 	}
 
-	log.Printf("Successfully sent message: %s", response)
+	logging.Debugf("Successfully sent message: %s", response)
 	return nil
 }
 
 // SendMulticast sends notification to multiple tokens
-func (c *Client) SendMulticast(tokens []string, title, body string, data map[string]string) error {
+func (c *Client) SendMulticast(ctx context.Context, tokens []string, title, body string, data map[string]string) error {
 	if c.client == nil {
 		return fmt.Errorf("FCM client not initialized")
 	}
@@ -117,13 +117,24 @@ func (c *Client) SendMulticast(tokens []string, title, body string, data map[str
 		},
 	}
 
-	ctx := context.Background()
 	response, err := c.client.SendEachForMulticast(ctx, message)
 	if err != nil {
 		return fmt.Errorf("error sending multicast: %w", err)
 	}
 
-	log.Printf("Successfully sent %d messages (%d failures)", response.SuccessCount, response.FailureCount)
+	logging.Debugf("Successfully sent %d messages (%d failures)", response.SuccessCount, response.FailureCount)
+	return nil
+}
+
+// Validate sends a dry-run message to a topic, which checks the credentials
+// and project without delivering anything
+func (c *Client) Validate(ctx context.Context) error {
+	if c.client == nil {
+		return fmt.Errorf("FCM client not initialized")
+	}
+	if _, err := c.client.SendDryRun(ctx, &messaging.Message{Topic: "selftest"}); err != nil {
+		return fmt.Errorf("dry-run send failed: %w", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,88 @@
+package indicators
+
+import "math"
+
+// ADX thresholds by convention
+const (
+	ADXTrending = 25 // above: strong directional trend
+	ADXWeak     = 20 // below: no trend, price chops sideways
+)
+
+// ADX holds Wilder's Average Directional Index and its +DI/-DI lines, 0-100.
+// Values before enough data is available are 0.
+type ADX struct {
+	ADX     []float64
+	PlusDI  []float64
+	MinusDI []float64
+}
+
+// CalculateADX computes Wilder's directional movement system: +DI and -DI
+// are the smoothed up and down moves as a share of the smoothed true range,
+// and ADX the smoothed spread between them. ADX measures trend strength,
+// not direction; the DI lines give the direction. +DI/-DI start at index
+// period and ADX at 2*period-1. Standard period is 14.
+func CalculateADX(highs, lows, closes []float64, period int) ADX {
+	length := len(closes)
+	result := ADX{ADX: make([]float64, length), PlusDI: make([]float64, length), MinusDI: make([]float64, length)}
+	if period < 1 || length < 2*period || len(highs) < length || len(lows) < length {
+		return result
+	}
+
+	tr := make([]float64, length)
+	plusDM := make([]float64, length)
+	minusDM := make([]float64, length)
+	for i := 1; i < length; i++ {
+		tr[i] = math.Max(highs[i]-lows[i], math.Max(math.Abs(highs[i]-closes[i-1]), math.Abs(lows[i]-closes[i-1])))
+		up := highs[i] - highs[i-1]
+		down := lows[i-1] - lows[i]
+		if up > down && up > 0 {
+			plusDM[i] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i] = down
+		}
+	}
+
+	// Wilder smoothing: seeded with the sum of the first period moves
+	var smoothTR, smoothPlus, smoothMinus float64
+	for i := 1; i <= period; i++ {
+		smoothTR += tr[i]
+		smoothPlus += plusDM[i]
+		smoothMinus += minusDM[i]
+	}
+
+	dx := make([]float64, length)
+	p := float64(period)
+	for i := period; i < length; i++ {
+		if i > period {
+			smoothTR = smoothTR - smoothTR/p + tr[i]
+			smoothPlus = smoothPlus - smoothPlus/p + plusDM[i]
+			smoothMinus = smoothMinus - smoothMinus/p + minusDM[i]
+		}
+		if smoothTR > 0 {
+			result.PlusDI[i] = 100 * smoothPlus / smoothTR
+			result.MinusDI[i] = 100 * smoothMinus / smoothTR
+		}
+		if sum := result.PlusDI[i] + result.MinusDI[i]; sum > 0 {
+			dx[i] = 100 * math.Abs(result.PlusDI[i]-result.MinusDI[i]) / sum
+		}
+	}
+
+	first := 2*period - 1
+	result.ADX[first] = average(dx[period : first+1])
+	for i := first + 1; i < length; i++ {
+		result.ADX[i] = (result.ADX[i-1]*(p-1) + dx[i]) / p
+	}
+
+	return result
+}
+
+// ADXRising reports whether ADX at idx is above its value lookback candles
+// earlier: a trend gaining strength
+func ADXRising(adx ADX, idx, lookback int) bool {
+	prev := idx - lookback
+	if idx >= len(adx.ADX) || prev < 0 || adx.ADX[prev] == 0 {
+		return false
+	}
+	return adx.ADX[idx] > adx.ADX[prev]
+}
@@ -0,0 +1,105 @@
+package indicators
+
+import (
+	"strings"
+	"time"
+)
+
+// VWAPAnchor selects where an anchored VWAP starts accumulating.
+type VWAPAnchor string
+
+const (
+	AnchorSession   VWAPAnchor = "session"    // UTC day open
+	AnchorWeek      VWAPAnchor = "week"       // Monday 00:00 UTC
+	AnchorSwingLow  VWAPAnchor = "swing_low"  // latest confirmed pivot low
+	AnchorSwingHigh VWAPAnchor = "swing_high" // latest confirmed pivot high
+)
+
+// ParseVWAPAnchor maps a config string to an anchor (default: session)
+func ParseVWAPAnchor(s string) VWAPAnchor {
+	switch VWAPAnchor(strings.ToLower(strings.TrimSpace(s))) {
+	case AnchorWeek:
+		return AnchorWeek
+	case AnchorSwingLow:
+		return AnchorSwingLow
+	case AnchorSwingHigh:
+		return AnchorSwingHigh
+	default:
+		return AnchorSession
+	}
+}
+
+// ParseKlineOpenTimes extracts candle open times from raw Binance klines.
+func ParseKlineOpenTimes(klines [][]interface{}) []time.Time {
+	times := make([]time.Time, len(klines))
+	for i, k := range klines {
+		if len(k) > 0 {
+			times[i] = time.UnixMilli(int64(parseToFloat(k[0]))).UTC()
+		}
+	}
+	return times
+}
+
+// CalculateAnchoredVWAP computes VWAP accumulated from the anchor. Session and
+// week anchors reset at every new UTC day/week in the series (the first period
+// starts at the first candle when the series doesn't reach back to its open);
+// swing anchors start at the latest pivot (5 left, 2 right bars) and are 0
+// before it, or everywhere when no pivot is found.
+func CalculateAnchoredVWAP(times []time.Time, highs, lows, closes, volumes []float64, anchor VWAPAnchor) []float64 {
+	n := len(closes)
+	vwap := make([]float64, n)
+	if len(highs) < n || len(lows) < n || len(volumes) < n {
+		return vwap
+	}
+
+	switch anchor {
+	case AnchorSwingLow, AnchorSwingHigh:
+		var pivots []Pivot
+		if anchor == AnchorSwingLow {
+			pivots = FindPivotLows(lows, 5, 2)
+		} else {
+			pivots = FindPivotHighs(highs, 5, 2)
+		}
+		if len(pivots) == 0 {
+			return vwap
+		}
+		accumulateVWAP(vwap, highs, lows, closes, volumes, pivots[len(pivots)-1].Index, n)
+		return vwap
+	}
+
+	if len(times) < n {
+		return vwap
+	}
+	periodStart := func(t time.Time) time.Time {
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		if anchor == AnchorWeek {
+			offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+			return day.AddDate(0, 0, -offset)
+		}
+		return day
+	}
+
+	start := 0
+	for start < n {
+		end := start + 1
+		for end < n && periodStart(times[end]).Equal(periodStart(times[start])) {
+			end++
+		}
+		accumulateVWAP(vwap, highs, lows, closes, volumes, start, end)
+		start = end
+	}
+	return vwap
+}
+
+// accumulateVWAP fills vwap[start:end] with typical-price VWAP from start
+func accumulateVWAP(vwap, highs, lows, closes, volumes []float64, start, end int) {
+	cumulativeTPV, cumulativeVol := 0.0, 0.0
+	for i := start; i < end; i++ {
+		typicalPrice := (highs[i] + lows[i] + closes[i]) / 3.0
+		cumulativeTPV += typicalPrice * volumes[i]
+		cumulativeVol += volumes[i]
+		if cumulativeVol > 0 {
+			vwap[i] = cumulativeTPV / cumulativeVol
+		}
+	}
+}
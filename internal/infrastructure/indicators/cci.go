@@ -0,0 +1,38 @@
+package indicators
+
+import "math"
+
+// CalculateCCI computes the Commodity Channel Index over the typical price
+// (high+low+close)/3. Readings beyond ±100 are stretched, beyond ±200 extreme.
+func CalculateCCI(highs, lows, closes []float64, period int) []float64 {
+	length := len(closes)
+	cci := make([]float64, length)
+	if period <= 0 || length < period || len(highs) < length || len(lows) < length {
+		return cci
+	}
+
+	typical := make([]float64, length)
+	for i := 0; i < length; i++ {
+		typical[i] = (highs[i] + lows[i] + closes[i]) / 3
+	}
+
+	for i := period - 1; i < length; i++ {
+		sum := 0.0
+		for j := 0; j < period; j++ {
+			sum += typical[i-j]
+		}
+		sma := sum / float64(period)
+
+		meanDev := 0.0
+		for j := 0; j < period; j++ {
+			meanDev += math.Abs(typical[i-j] - sma)
+		}
+		meanDev /= float64(period)
+
+		if meanDev > 0 {
+			cci[i] = (typical[i] - sma) / (0.015 * meanDev)
+		}
+	}
+
+	return cci
+}
@@ -0,0 +1,38 @@
+package indicators
+
+// ChandelierExit holds ATR-based trailing stop references.
+type ChandelierExit struct {
+	Long  []float64 // highest high - k*ATR (stop for longs)
+	Short []float64 // lowest low + k*ATR (stop for shorts)
+}
+
+// CalculateChandelierExit computes the Chandelier Exit over `period` candles
+// using the supplied ATR series (usually ATR 22 or 14) and multiplier k (usually 3).
+// Values stay 0 until both the lookback and the ATR are available.
+func CalculateChandelierExit(highs, lows, atr []float64, period int, multiplier float64) ChandelierExit {
+	length := len(highs)
+	long := make([]float64, length)
+	short := make([]float64, length)
+	if period <= 0 || length < period || len(lows) < length || len(atr) < length {
+		return ChandelierExit{Long: long, Short: short}
+	}
+
+	for i := period - 1; i < length; i++ {
+		if atr[i] == 0 {
+			continue
+		}
+		highest, lowest := highs[i], lows[i]
+		for j := i - period + 1; j < i; j++ {
+			if highs[j] > highest {
+				highest = highs[j]
+			}
+			if lows[j] < lowest {
+				lowest = lows[j]
+			}
+		}
+		long[i] = highest - multiplier*atr[i]
+		short[i] = lowest + multiplier*atr[i]
+	}
+
+	return ChandelierExit{Long: long, Short: short}
+}
@@ -0,0 +1,41 @@
+package indicators
+
+import "math"
+
+// Choppiness thresholds (Fibonacci levels by convention)
+const (
+	ChopRanging  = 61.8 // above: sideways, breakouts tend to fail
+	ChopTrending = 38.2 // below: directional market
+)
+
+// CalculateChoppiness computes the Choppiness Index:
+// 100 * log10(sum(TR, n) / (highest high - lowest low)) / log10(n).
+// Ranges 0-100; higher means choppier.
+func CalculateChoppiness(highs, lows, closes []float64, period int) []float64 {
+	length := len(closes)
+	chop := make([]float64, length)
+	if period < 2 || length < period+1 || len(highs) < length || len(lows) < length {
+		return chop
+	}
+
+	tr := make([]float64, length)
+	for i := 1; i < length; i++ {
+		tr[i] = math.Max(highs[i]-lows[i], math.Max(math.Abs(highs[i]-closes[i-1]), math.Abs(lows[i]-closes[i-1])))
+	}
+
+	logPeriod := math.Log10(float64(period))
+	for i := period; i < length; i++ {
+		sumTR := 0.0
+		highest, lowest := highs[i], lows[i]
+		for j := i - period + 1; j <= i; j++ {
+			sumTR += tr[j]
+			highest = math.Max(highest, highs[j])
+			lowest = math.Min(lowest, lows[j])
+		}
+		if highest > lowest && sumTR > 0 {
+			chop[i] = 100 * math.Log10(sumTR/(highest-lowest)) / logPeriod
+		}
+	}
+
+	return chop
+}
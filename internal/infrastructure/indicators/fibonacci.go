@@ -0,0 +1,71 @@
+package indicators
+
+import "math"
+
+// FibRatios are the retracement ratios tracked for pullback entries
+var FibRatios = []float64{0.382, 0.5, 0.618}
+
+// FibRetracement holds the retracement levels of the latest impulse leg.
+type FibRetracement struct {
+	SwingHigh float64
+	SwingLow  float64
+	IsUpLeg   bool                // true = leg ran low -> high, levels are supports
+	Levels    map[float64]float64 // ratio -> price
+}
+
+// CalculateFibRetracement derives the active impulse leg from the most recent
+// pivot high and pivot low. The later of the two marks the end of the leg.
+// Returns nil when either pivot is missing or the leg has no range.
+func CalculateFibRetracement(pivotHighs, pivotLows []Pivot) *FibRetracement {
+	if len(pivotHighs) == 0 || len(pivotLows) == 0 {
+		return nil
+	}
+	high := pivotHighs[len(pivotHighs)-1]
+	low := pivotLows[len(pivotLows)-1]
+	legRange := high.Price - low.Price
+	if legRange <= 0 {
+		return nil
+	}
+
+	fib := &FibRetracement{
+		SwingHigh: high.Price,
+		SwingLow:  low.Price,
+		IsUpLeg:   high.Index > low.Index,
+		Levels:    make(map[float64]float64, len(FibRatios)),
+	}
+	for _, ratio := range FibRatios {
+		if fib.IsUpLeg {
+			fib.Levels[ratio] = high.Price - legRange*ratio
+		} else {
+			fib.Levels[ratio] = low.Price + legRange*ratio
+		}
+	}
+	return fib
+}
+
+// FibReaction returns the ratio of the level the current candle is reacting
+// at, or 0 if none. On an up leg the candle must have traded down into the
+// level (low within tolerance) and closed back above it; on a down leg the
+// mirror image applies. tolerance is in price units (typically a fraction of ATR).
+func FibReaction(fib *FibRetracement, high, low, close, tolerance float64) float64 {
+	if fib == nil {
+		return 0
+	}
+	best, bestDist := 0.0, math.MaxFloat64
+	for _, ratio := range FibRatios {
+		level := fib.Levels[ratio]
+		var touched bool
+		var dist float64
+		if fib.IsUpLeg {
+			touched = low <= level+tolerance && close >= level
+			dist = math.Abs(low - level)
+		} else {
+			touched = high >= level-tolerance && close <= level
+			dist = math.Abs(high - level)
+		}
+		if touched && dist < bestDist {
+			best, bestDist = ratio, dist
+		}
+	}
+	return best
+}
@@ -0,0 +1,71 @@
+package indicators
+
+import (
+	"math"
+	"strings"
+)
+
+// MAType selects the moving average used as a trend baseline.
+type MAType string
+
+const (
+	MATypeEMA MAType = "ema"
+	MATypeHMA MAType = "hma" // Hull MA: lower lag, same smoothness
+)
+
+// ParseMAType parses a config value, defaulting to EMA.
+func ParseMAType(s string) MAType {
+	if MAType(strings.ToLower(strings.TrimSpace(s))) == MATypeHMA {
+		return MATypeHMA
+	}
+	return MATypeEMA
+}
+
+// CalculateMA computes the moving average of the given type.
+func CalculateMA(data []float64, period int, maType MAType) []float64 {
+	if maType == MATypeHMA {
+		return CalculateHMA(data, period)
+	}
+	return CalculateEMA(data, period)
+}
+
+// CalculateWMA computes the linearly Weighted Moving Average.
+func CalculateWMA(data []float64, period int) []float64 {
+	return weightedMA(data, period, 0)
+}
+
+// CalculateHMA computes the Hull Moving Average:
+// WMA(2*WMA(n/2) - WMA(n), sqrt(n)).
+func CalculateHMA(data []float64, period int) []float64 {
+	hma := make([]float64, len(data))
+	if period < 2 || len(data) < period {
+		return hma
+	}
+
+	half := weightedMA(data, period/2, 0)
+	full := weightedMA(data, period, 0)
+	diff := make([]float64, len(data))
+	for i := period - 1; i < len(data); i++ {
+		diff[i] = 2*half[i] - full[i]
+	}
+
+	return weightedMA(diff, int(math.Sqrt(float64(period))), period-1)
+}
+
+// weightedMA computes a WMA over data[start:], leaving earlier values at 0.
+func weightedMA(data []float64, period, start int) []float64 {
+	wma := make([]float64, len(data))
+	if period <= 0 || len(data)-start < period {
+		return wma
+	}
+
+	denom := float64(period*(period+1)) / 2
+	for i := start + period - 1; i < len(data); i++ {
+		sum := 0.0
+		for j := 0; j < period; j++ {
+			sum += data[i-j] * float64(period-j)
+		}
+		wma[i] = sum / denom
+	}
+	return wma
+}
@@ -0,0 +1,91 @@
+package indicators
+
+// Ichimoku holds the Ichimoku Kinko Hyo lines. SenkouA/SenkouB are already
+// displaced: SenkouA[i] is the cloud edge drawn at candle i (computed from
+// candle i-displacement). Values without enough history are 0.
+type Ichimoku struct {
+	Tenkan  []float64
+	Kijun   []float64
+	SenkouA []float64
+	SenkouB []float64
+}
+
+// CalculateIchimoku computes Ichimoku with the given periods (standard 9, 26, 52, 26).
+func CalculateIchimoku(highs, lows []float64, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) Ichimoku {
+	n := len(highs)
+	ich := Ichimoku{
+		Tenkan:  midpoints(highs, lows, tenkanPeriod),
+		Kijun:   midpoints(highs, lows, kijunPeriod),
+		SenkouA: make([]float64, n),
+		SenkouB: make([]float64, n),
+	}
+	spanB := midpoints(highs, lows, senkouBPeriod)
+
+	for i := displacement; i < n; i++ {
+		src := i - displacement
+		if ich.Tenkan[src] != 0 && ich.Kijun[src] != 0 {
+			ich.SenkouA[i] = (ich.Tenkan[src] + ich.Kijun[src]) / 2
+		}
+		ich.SenkouB[i] = spanB[src]
+	}
+	return ich
+}
+
+// midpoints returns (highest high + lowest low) / 2 over each period window
+func midpoints(highs, lows []float64, period int) []float64 {
+	n := len(highs)
+	out := make([]float64, n)
+	if period <= 0 || len(lows) < n {
+		return out
+	}
+	for i := period - 1; i < n; i++ {
+		hi, lo := highs[i], lows[i]
+		for j := i - period + 1; j < i; j++ {
+			if highs[j] > hi {
+				hi = highs[j]
+			}
+			if lows[j] < lo {
+				lo = lows[j]
+			}
+		}
+		out[i] = (hi + lo) / 2
+	}
+	return out
+}
+
+// CloudPosition returns "ABOVE", "BELOW" or "INSIDE" for price against the
+// cloud at candle i, or "" when the cloud isn't defined yet.
+func CloudPosition(ich Ichimoku, i int, price float64) string {
+	if i < 0 || i >= len(ich.SenkouA) || ich.SenkouA[i] == 0 || ich.SenkouB[i] == 0 {
+		return ""
+	}
+	top, bottom := ich.SenkouA[i], ich.SenkouB[i]
+	if bottom > top {
+		top, bottom = bottom, top
+	}
+	switch {
+	case price > top:
+		return "ABOVE"
+	case price < bottom:
+		return "BELOW"
+	default:
+		return "INSIDE"
+	}
+}
+
+// IchimokuTrend classifies candle i: "BULLISH" (above the cloud with Tenkan
+// over Kijun), "BEARISH" (below with Tenkan under Kijun), otherwise "NEUTRAL".
+// Returns "" when there isn't enough history.
+func IchimokuTrend(ich Ichimoku, i int, price float64) string {
+	pos := CloudPosition(ich, i, price)
+	if pos == "" || ich.Tenkan[i] == 0 || ich.Kijun[i] == 0 {
+		return ""
+	}
+	if pos == "ABOVE" && ich.Tenkan[i] > ich.Kijun[i] {
+		return "BULLISH"
+	}
+	if pos == "BELOW" && ich.Tenkan[i] < ich.Kijun[i] {
+		return "BEARISH"
+	}
+	return "NEUTRAL"
+}
@@ -0,0 +1,68 @@
+package indicators
+
+// KeltnerChannels holds an EMA midline with ATR-based bands.
+type KeltnerChannels struct {
+	Upper  []float64
+	Middle []float64
+	Lower  []float64
+}
+
+// CalculateKeltnerChannels computes EMA(emaPeriod) ± multiplier * ATR(atrPeriod).
+// Values without enough history are 0.
+func CalculateKeltnerChannels(highs, lows, closes []float64, emaPeriod, atrPeriod int, multiplier float64) KeltnerChannels {
+	n := len(closes)
+	kc := KeltnerChannels{
+		Upper:  make([]float64, n),
+		Middle: CalculateEMA(closes, emaPeriod),
+		Lower:  make([]float64, n),
+	}
+	atr := CalculateATR(highs, lows, closes, atrPeriod)
+	if len(atr) < n {
+		return kc
+	}
+
+	for i := 0; i < n; i++ {
+		if kc.Middle[i] == 0 || atr[i] == 0 {
+			continue
+		}
+		kc.Upper[i] = kc.Middle[i] + multiplier*atr[i]
+		kc.Lower[i] = kc.Middle[i] - multiplier*atr[i]
+	}
+	return kc
+}
+
+// Squeeze describes the Bollinger-inside-Keltner volatility squeeze at the last candle.
+type Squeeze struct {
+	On    bool // Bollinger Bands inside Keltner Channels now
+	Bars  int  // consecutive squeeze candles (up to the previous candle when Fired)
+	Fired bool // squeeze ended on the last candle: expansion starting
+}
+
+// DetectSqueeze checks whether the Bollinger Bands sit inside the Keltner
+// Channels (volatility coiling) and whether a squeeze was just released.
+func DetectSqueeze(bb BollingerBands, kc KeltnerChannels) Squeeze {
+	n := len(bb.Upper)
+	if n == 0 || len(kc.Upper) < n {
+		return Squeeze{}
+	}
+
+	inside := func(i int) bool {
+		return bb.Upper[i] != 0 && kc.Upper[i] != 0 &&
+			bb.Upper[i] < kc.Upper[i] && bb.Lower[i] > kc.Lower[i]
+	}
+
+	var s Squeeze
+	s.On = inside(n - 1)
+	end := n - 1
+	if !s.On {
+		if n < 2 || !inside(n-2) {
+			return s
+		}
+		s.Fired = true
+		end = n - 2
+	}
+	for i := end; i >= 0 && inside(i); i-- {
+		s.Bars++
+	}
+	return s
+}
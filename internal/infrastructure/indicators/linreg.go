@@ -0,0 +1,60 @@
+package indicators
+
+import "math"
+
+// LinRegChannel is a least-squares line over the lookback window with
+// ±k standard deviation bounds, evaluated at the latest candle.
+type LinRegChannel struct {
+	Slope    float64 // price units per candle
+	SlopePct float64 // slope as % of the midline per candle (comparable across symbols)
+	Midline  float64
+	Upper    float64
+	Lower    float64
+	StdDev   float64 // of the residuals around the line
+}
+
+// CalculateLinRegChannel fits a regression line to the last `lookback`
+// values. Returns a zero channel when there is not enough data.
+func CalculateLinRegChannel(data []float64, lookback int, k float64) LinRegChannel {
+	n := len(data)
+	if lookback < 2 || n < lookback {
+		return LinRegChannel{}
+	}
+	window := data[n-lookback:]
+
+	sumX, sumY, sumXY, sumX2 := 0.0, 0.0, 0.0, 0.0
+	for i, y := range window {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumX2 += x * x
+	}
+	count := float64(lookback)
+	denom := count*sumX2 - sumX*sumX
+	if denom == 0 {
+		return LinRegChannel{}
+	}
+	slope := (count*sumXY - sumX*sumY) / denom
+	intercept := (sumY - slope*sumX) / count
+
+	sumSq := 0.0
+	for i, y := range window {
+		residual := y - (intercept + slope*float64(i))
+		sumSq += residual * residual
+	}
+	stdDev := math.Sqrt(sumSq / count)
+
+	mid := intercept + slope*float64(lookback-1)
+	channel := LinRegChannel{
+		Slope:   slope,
+		Midline: mid,
+		Upper:   mid + k*stdDev,
+		Lower:   mid - k*stdDev,
+		StdDev:  stdDev,
+	}
+	if mid != 0 {
+		channel.SlopePct = slope / mid * 100
+	}
+	return channel
+}
@@ -0,0 +1,83 @@
+package indicators
+
+import "math"
+
+// MACD holds the MACD line, its signal line and the histogram (line - signal).
+// Values before enough data is available are 0.
+type MACD struct {
+	Line      []float64
+	Signal    []float64
+	Histogram []float64
+}
+
+// CalculateMACD computes MACD (fast EMA - slow EMA) with a signal EMA of the line.
+// Standard parameters are 12, 26, 9.
+func CalculateMACD(closes []float64, fastPeriod, slowPeriod, signalPeriod int) MACD {
+	n := len(closes)
+	result := MACD{
+		Line:      make([]float64, n),
+		Signal:    make([]float64, n),
+		Histogram: make([]float64, n),
+	}
+	if fastPeriod <= 0 || slowPeriod <= fastPeriod || signalPeriod <= 0 || n < slowPeriod+signalPeriod-1 {
+		return result
+	}
+
+	fast := CalculateEMA(closes, fastPeriod)
+	slow := CalculateEMA(closes, slowPeriod)
+	start := slowPeriod - 1
+	for i := start; i < n; i++ {
+		result.Line[i] = fast[i] - slow[i]
+	}
+
+	// Signal line is an EMA over the valid part of the MACD line
+	signal := CalculateEMA(result.Line[start:], signalPeriod)
+	for i := start + signalPeriod - 1; i < n; i++ {
+		result.Signal[i] = signal[i-start]
+		result.Histogram[i] = result.Line[i] - result.Signal[i]
+	}
+	return result
+}
+
+// IsHistogramContracting reports whether the histogram kept its sign but shrank
+// in magnitude for each of the last `bars` candles (momentum fading).
+func IsHistogramContracting(histogram []float64, bars int) bool {
+	n := len(histogram)
+	if bars < 1 || n < bars+1 {
+		return false
+	}
+	last := histogram[n-1]
+	if last == 0 {
+		return false
+	}
+	for i := n - bars; i < n; i++ {
+		prev, cur := histogram[i-1], histogram[i]
+		if (cur > 0) != (last > 0) || (prev > 0) != (last > 0) {
+			return false
+		}
+		if math.Abs(cur) >= math.Abs(prev) {
+			return false
+		}
+	}
+	return true
+}
+
+// MACDCross reports a signal-line cross within the last `lookback` candles:
+// "BULLISH" (line crossed above signal), "BEARISH" (below) or "" if none.
+// The most recent cross wins.
+func MACDCross(m MACD, lookback int) string {
+	n := len(m.Histogram)
+	for i := n - 1; i >= 1 && i >= n-lookback; i-- {
+		prev, cur := m.Histogram[i-1], m.Histogram[i]
+		if prev == 0 && m.Signal[i-1] == 0 {
+			break // before the signal line is defined
+		}
+		if prev <= 0 && cur > 0 {
+			return "BULLISH"
+		}
+		if prev >= 0 && cur < 0 {
+			return "BEARISH"
+		}
+	}
+	return ""
+}
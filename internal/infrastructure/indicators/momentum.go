@@ -3,10 +3,14 @@ package indicators
 // MomentumSignals contains all momentum loss detection signals
 type MomentumSignals struct {
 	HasRsiDivergence    bool
-	HasVolumeDivergence bool
+	HasVolumeDivergence bool // simple volume fade or OBV divergence
+	HasObvDivergence    bool // price higher high, OBV lower high
 	MomentumSlope       float64
 	RsiSlope            float64
 	VolumeDeclineRatio  float64
+	MacdHistogram       float64 // latest MACD(12,26,9) histogram
+	MacdContracting     bool    // histogram shrinking for 3 candles
+	MacdCross           string  // "BULLISH", "BEARISH" or "" (last 3 candles)
 	IsLosingMomentum    bool
 }
 
@@ -27,13 +31,26 @@ func DetectMomentumLoss(closes, highs, volumes []float64, rsiValues []float64) M
 	// 2. Volume Divergence (Price going up but volume declining)
 	signals.HasVolumeDivergence, signals.VolumeDeclineRatio = detectVolumeDivergence(closes, volumes, 10)
 
+	// 2b. OBV divergence (cumulative volume flow not confirming the new high)
+	obv := CalculateOBV(closes, volumes)
+	signals.HasObvDivergence = DetectOBVBearishDivergence(highs, obv, 20)
+	if signals.HasObvDivergence {
+		signals.HasVolumeDivergence = true
+	}
+
 	// 3. RSI Slope (Rate of change of RSI over last 5 candles)
 	signals.RsiSlope = calculateSlope(rsiValues[n-5:])
 
 	// 4. Momentum Slope (Price ROC trend)
 	signals.MomentumSlope = calculateMomentumSlope(closes, 10)
 
-	// 5. Combined signal: Is Losing Momentum
+	// 5. MACD histogram contraction / bearish signal cross
+	macd := CalculateMACD(closes, 12, 26, 9)
+	signals.MacdHistogram = macd.Histogram[n-1]
+	signals.MacdContracting = signals.MacdHistogram > 0 && IsHistogramContracting(macd.Histogram, 3)
+	signals.MacdCross = MACDCross(macd, 3)
+
+	// 6. Combined signal: Is Losing Momentum
 	momentumLossCount := 0
 	if signals.HasRsiDivergence {
 		momentumLossCount++
@@ -47,6 +64,9 @@ func DetectMomentumLoss(closes, highs, volumes []float64, rsiValues []float64) M
 	if signals.MomentumSlope < 0 { // Price momentum slowing
 		momentumLossCount++
 	}
+	if signals.MacdContracting || signals.MacdCross == "BEARISH" { // Bullish MACD fading
+		momentumLossCount++
+	}
 
 	signals.IsLosingMomentum = momentumLossCount >= 2
 
@@ -77,6 +97,39 @@ func detectRsiDivergence(highs, rsiValues []float64, lookback int) bool {
 	return priceHH && rsiLH
 }
 
+// DetectRsiBullishDivergence checks for bullish RSI divergence on lows:
+// price makes a lower low but RSI makes a higher low (selling pressure fading)
+func DetectRsiBullishDivergence(lows, rsiValues []float64, lookback int) bool {
+	n := len(lows)
+	if n < lookback || len(rsiValues) < n {
+		return false
+	}
+
+	troughs := findLocalTroughs(lows, n-lookback, n)
+	if len(troughs) < 2 {
+		return false
+	}
+
+	lastTrough := troughs[len(troughs)-1]
+	prevTrough := troughs[len(troughs)-2]
+
+	priceLL := lows[lastTrough] < lows[prevTrough]
+	rsiHL := rsiValues[lastTrough] > rsiValues[prevTrough]
+
+	return priceLL && rsiHL
+}
+
+// findLocalTroughs finds indices of local minima in a range
+func findLocalTroughs(data []float64, start, end int) []int {
+	troughs := []int{}
+	for i := start + 1; i < end-1; i++ {
+		if data[i] < data[i-1] && data[i] < data[i+1] {
+			troughs = append(troughs, i)
+		}
+	}
+	return troughs
+}
+
 // findLocalPeaks finds indices of local maxima in a range
 func findLocalPeaks(data []float64, start, end int) []int {
 	peaks := []int{}
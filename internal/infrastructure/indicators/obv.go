@@ -0,0 +1,41 @@
+package indicators
+
+// CalculateOBV computes On-Balance Volume: volume is added on up closes and
+// subtracted on down closes. The series starts at 0.
+func CalculateOBV(closes, volumes []float64) []float64 {
+	n := len(closes)
+	obv := make([]float64, n)
+	if len(volumes) < n {
+		return obv
+	}
+	for i := 1; i < n; i++ {
+		switch {
+		case closes[i] > closes[i-1]:
+			obv[i] = obv[i-1] + volumes[i]
+		case closes[i] < closes[i-1]:
+			obv[i] = obv[i-1] - volumes[i]
+		default:
+			obv[i] = obv[i-1]
+		}
+	}
+	return obv
+}
+
+// DetectOBVBearishDivergence reports whether, within the last `lookback`
+// candles, price made a higher high while OBV made a lower high (buying
+// volume not confirming the move).
+func DetectOBVBearishDivergence(highs, obv []float64, lookback int) bool {
+	n := len(highs)
+	if n < lookback || len(obv) < n {
+		return false
+	}
+
+	peaks := findLocalPeaks(highs, n-lookback, n)
+	if len(peaks) < 2 {
+		return false
+	}
+	last := peaks[len(peaks)-1]
+	prev := peaks[len(peaks)-2]
+
+	return highs[last] > highs[prev] && obv[last] < obv[prev]
+}
@@ -0,0 +1,63 @@
+package indicators
+
+// StochRSI holds the %K and %D lines of the Stochastic RSI, 0-100. Values
+// before enough data is available are 0.
+type StochRSI struct {
+	K []float64
+	D []float64
+
+	start int // first index with both lines defined; len(K) when none is
+}
+
+// CalculateStochRSI computes the Stochastic RSI: where the RSI sits within
+// its range over stochPeriod candles, smoothed into %K by a kPeriod average
+// and into %D by a dPeriod average of %K. A flat RSI range reads 50.
+// Standard parameters are 14, 14, 3, 3.
+func CalculateStochRSI(closes []float64, rsiPeriod, stochPeriod, kPeriod, dPeriod int) StochRSI {
+	n := len(closes)
+	result := StochRSI{K: make([]float64, n), D: make([]float64, n), start: n}
+	if rsiPeriod <= 0 || stochPeriod <= 0 || kPeriod <= 0 || dPeriod <= 0 {
+		return result
+	}
+	// The RSI starts at index rsiPeriod
+	first := rsiPeriod + stochPeriod - 1
+	if n < first+kPeriod+dPeriod-1 {
+		return result
+	}
+
+	rsi := CalculateRSI(closes, rsiPeriod)
+	raw := make([]float64, n)
+	for i := first; i < n; i++ {
+		lowest, highest := rsi[i], rsi[i]
+		for j := i - stochPeriod + 1; j < i; j++ {
+			lowest = min(lowest, rsi[j])
+			highest = max(highest, rsi[j])
+		}
+		if highest > lowest {
+			raw[i] = (rsi[i] - lowest) / (highest - lowest) * 100
+		} else {
+			raw[i] = 50
+		}
+	}
+
+	for i := first + kPeriod - 1; i < n; i++ {
+		result.K[i] = average(raw[i-kPeriod+1 : i+1])
+	}
+	result.start = first + kPeriod + dPeriod - 2
+	for i := result.start; i < n; i++ {
+		result.D[i] = average(result.K[i-dPeriod+1 : i+1])
+	}
+	return result
+}
+
+// StochRSICrossUp reports %K crossing above %D within the last lookback
+// candles from below level (oversold, usually 20)
+func StochRSICrossUp(s StochRSI, level float64, lookback int) bool {
+	n := len(s.K)
+	for i := n - 1; i > s.start && i >= n-lookback; i-- {
+		if s.K[i-1] <= s.D[i-1] && s.K[i] > s.D[i] && min(s.K[i-1], s.D[i-1]) < level {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package indicators
+
+import "math"
+
+// Streaming indicators update in O(1) per closed candle from their saved
+// state and produce the same values as the batch Calculate* functions over
+// the full series. State fields are exported so it can be persisted (JSON)
+// and restored between runs. Values stay 0 until the indicator is warmed up.
+
+// StreamingEMA is the incremental form of CalculateEMA.
+type StreamingEMA struct {
+	Period int     `json:"period"`
+	Count  int     `json:"count"`
+	Sum    float64 `json:"sum"` // seed SMA accumulator
+	Value  float64 `json:"value"`
+}
+
+// NewStreamingEMA creates an EMA state for period.
+func NewStreamingEMA(period int) *StreamingEMA {
+	return &StreamingEMA{Period: period}
+}
+
+// Update adds one value and returns the current EMA.
+func (s *StreamingEMA) Update(v float64) float64 {
+	s.Count++
+	if s.Count < s.Period {
+		s.Sum += v
+		return 0
+	}
+	if s.Count == s.Period {
+		s.Sum += v
+		s.Value = s.Sum / float64(s.Period)
+		return s.Value
+	}
+	k := 2.0 / (float64(s.Period) + 1.0)
+	s.Value = v*k + s.Value*(1-k)
+	return s.Value
+}
+
+// StreamingRSI is the incremental form of CalculateRSI (Wilder smoothing).
+type StreamingRSI struct {
+	Period    int     `json:"period"`
+	Count     int     `json:"count"` // closes seen
+	PrevClose float64 `json:"prevClose"`
+	AvgGain   float64 `json:"avgGain"` // running sum until warmed up
+	AvgLoss   float64 `json:"avgLoss"`
+	Value     float64 `json:"value"`
+}
+
+// NewStreamingRSI creates an RSI state for period.
+func NewStreamingRSI(period int) *StreamingRSI {
+	return &StreamingRSI{Period: period}
+}
+
+// Update adds one close and returns the current RSI.
+func (s *StreamingRSI) Update(close float64) float64 {
+	s.Count++
+	if s.Count == 1 {
+		s.PrevClose = close
+		return 0
+	}
+
+	change := close - s.PrevClose
+	s.PrevClose = close
+	gain, loss := 0.0, 0.0
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	changes := s.Count - 1
+	p := float64(s.Period)
+	switch {
+	case changes < s.Period:
+		s.AvgGain += gain
+		s.AvgLoss += loss
+		return 0
+	case changes == s.Period:
+		s.AvgGain = (s.AvgGain + gain) / p
+		s.AvgLoss = (s.AvgLoss + loss) / p
+	default:
+		s.AvgGain = (s.AvgGain*(p-1) + gain) / p
+		s.AvgLoss = (s.AvgLoss*(p-1) + loss) / p
+	}
+
+	if s.AvgLoss == 0 {
+		s.Value = 100
+	} else {
+		s.Value = 100 - 100/(1+s.AvgGain/s.AvgLoss)
+	}
+	return s.Value
+}
+
+// StreamingATR is the incremental form of CalculateATR.
+type StreamingATR struct {
+	Period    int     `json:"period"`
+	Count     int     `json:"count"`
+	PrevClose float64 `json:"prevClose"`
+	Sum       float64 `json:"sum"` // seed TR accumulator
+	Value     float64 `json:"value"`
+}
+
+// NewStreamingATR creates an ATR state for period.
+func NewStreamingATR(period int) *StreamingATR {
+	return &StreamingATR{Period: period}
+}
+
+// Update adds one candle and returns the current ATR.
+func (s *StreamingATR) Update(high, low, close float64) float64 {
+	tr := high - low
+	if s.Count > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(high-s.PrevClose), math.Abs(low-s.PrevClose)))
+	}
+	s.PrevClose = close
+	s.Count++
+
+	switch {
+	case s.Count < s.Period:
+		s.Sum += tr
+		return 0
+	case s.Count == s.Period:
+		s.Value = (s.Sum + tr) / float64(s.Period)
+	default:
+		s.Value = (s.Value*float64(s.Period-1) + tr) / float64(s.Period)
+	}
+	return s.Value
+}
+
+// StreamingBollinger is the incremental form of CalculateBollingerBands,
+// keeping the last Period closes in a ring buffer with running sums.
+type StreamingBollinger struct {
+	Period     int       `json:"period"`
+	Multiplier float64   `json:"multiplier"`
+	Window     []float64 `json:"window"`
+	Pos        int       `json:"pos"`
+	Count      int       `json:"count"`
+	Sum        float64   `json:"sum"`
+	SumSq      float64   `json:"sumSq"`
+}
+
+// NewStreamingBollinger creates a Bollinger state for period and multiplier.
+func NewStreamingBollinger(period int, multiplier float64) *StreamingBollinger {
+	return &StreamingBollinger{Period: period, Multiplier: multiplier, Window: make([]float64, period)}
+}
+
+// Update adds one close and returns the current upper, middle and lower band.
+func (s *StreamingBollinger) Update(close float64) (upper, middle, lower float64) {
+	old := s.Window[s.Pos]
+	s.Window[s.Pos] = close
+	s.Pos = (s.Pos + 1) % s.Period
+	s.Sum += close
+	s.SumSq += close * close
+	s.Count++
+	if s.Count > s.Period {
+		s.Sum -= old
+		s.SumSq -= old * old
+	}
+	if s.Count < s.Period {
+		return 0, 0, 0
+	}
+
+	p := float64(s.Period)
+	middle = s.Sum / p
+	variance := s.SumSq/p - middle*middle
+	if variance < 0 {
+		variance = 0 // float rounding
+	}
+	stdDev := math.Sqrt(variance)
+	return middle + s.Multiplier*stdDev, middle, middle - s.Multiplier*stdDev
+}
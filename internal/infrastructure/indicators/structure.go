@@ -0,0 +1,155 @@
+package indicators
+
+import "sort"
+
+// Swing labels relative to the previous swing of the same kind
+const (
+	SwingHH = "HH" // higher high
+	SwingLH = "LH" // lower high
+	SwingHL = "HL" // higher low
+	SwingLL = "LL" // lower low
+)
+
+// Structure break types
+const (
+	BreakBOS   = "BOS"   // break of structure: continuation in the current trend
+	BreakCHoCH = "CHOCH" // change of character: first break against the trend
+)
+
+// Swing is a confirmed pivot labeled against the previous swing of its kind.
+type Swing struct {
+	Index  int
+	Price  float64
+	IsHigh bool
+	Label  string // HH/LH for highs, HL/LL for lows; "" for the first of each kind
+}
+
+// StructureBreak is a close beyond the latest confirmed swing.
+type StructureBreak struct {
+	Type       string  // BreakBOS or BreakCHoCH
+	Direction  string  // "BULLISH" (close above a swing high) or "BEARISH"
+	Index      int     // candle that closed beyond the level
+	Price      float64 // the swing level that was broken
+	SwingIndex int     // candle of the broken swing
+}
+
+// MarketStructure describes the swing sequence of a series.
+type MarketStructure struct {
+	Swings []Swing
+	Breaks []StructureBreak
+	Trend  string // "BULLISH", "BEARISH" or "" before the first break
+}
+
+// AnalyzeMarketStructure labels pivot swings (FindPivotHighs/Lows with the
+// given bars) and walks the closes to detect BOS / CHoCH. A swing can only be
+// broken once it is confirmed (rightBars after the pivot) and only once.
+func AnalyzeMarketStructure(highs, lows, closes []float64, leftBars, rightBars int) MarketStructure {
+	ms := MarketStructure{}
+	n := len(closes)
+	if n == 0 || len(highs) < n || len(lows) < n {
+		return ms
+	}
+
+	for _, p := range FindPivotHighs(highs[:n], leftBars, rightBars) {
+		ms.Swings = append(ms.Swings, Swing{Index: p.Index, Price: p.Price, IsHigh: true})
+	}
+	for _, p := range FindPivotLows(lows[:n], leftBars, rightBars) {
+		ms.Swings = append(ms.Swings, Swing{Index: p.Index, Price: p.Price})
+	}
+	sort.SliceStable(ms.Swings, func(i, j int) bool { return ms.Swings[i].Index < ms.Swings[j].Index })
+
+	var prevHigh, prevLow *Swing
+	for i := range ms.Swings {
+		s := &ms.Swings[i]
+		if s.IsHigh {
+			if prevHigh != nil {
+				s.Label = SwingLH
+				if s.Price > prevHigh.Price {
+					s.Label = SwingHH
+				}
+			}
+			prevHigh = s
+		} else {
+			if prevLow != nil {
+				s.Label = SwingLL
+				if s.Price > prevLow.Price {
+					s.Label = SwingHL
+				}
+			}
+			prevLow = s
+		}
+	}
+
+	// Walk candles, tracking the latest confirmed unbroken swing of each kind
+	var activeHigh, activeLow *Swing
+	next := 0
+	for i := 0; i < n; i++ {
+		for next < len(ms.Swings) && ms.Swings[next].Index+rightBars <= i {
+			if ms.Swings[next].IsHigh {
+				activeHigh = &ms.Swings[next]
+			} else {
+				activeLow = &ms.Swings[next]
+			}
+			next++
+		}
+
+		if activeHigh != nil && closes[i] > activeHigh.Price {
+			ms.addBreak("BULLISH", i, activeHigh)
+			activeHigh = nil
+		}
+		if activeLow != nil && closes[i] < activeLow.Price {
+			ms.addBreak("BEARISH", i, activeLow)
+			activeLow = nil
+		}
+	}
+
+	return ms
+}
+
+func (ms *MarketStructure) addBreak(direction string, index int, swing *Swing) {
+	breakType := BreakBOS
+	if ms.Trend != "" && ms.Trend != direction {
+		breakType = BreakCHoCH
+	}
+	ms.Breaks = append(ms.Breaks, StructureBreak{
+		Type:       breakType,
+		Direction:  direction,
+		Index:      index,
+		Price:      swing.Price,
+		SwingIndex: swing.Index,
+	})
+	ms.Trend = direction
+}
+
+// LastBreak returns the most recent structure break, or nil.
+func (ms MarketStructure) LastBreak() *StructureBreak {
+	if len(ms.Breaks) == 0 {
+		return nil
+	}
+	return &ms.Breaks[len(ms.Breaks)-1]
+}
+
+// RecentBreak returns the latest break in direction ("BULLISH"/"BEARISH")
+// that happened within the last `bars` candles of a series of length n.
+func (ms MarketStructure) RecentBreak(direction string, n, bars int) *StructureBreak {
+	for i := len(ms.Breaks) - 1; i >= 0; i-- {
+		b := ms.Breaks[i]
+		if b.Index < n-bars {
+			return nil
+		}
+		if b.Direction == direction {
+			return &b
+		}
+	}
+	return nil
+}
+
+// LastSwingLabel returns the label of the latest swing high (isHigh) or low.
+func (ms MarketStructure) LastSwingLabel(isHigh bool) string {
+	for i := len(ms.Swings) - 1; i >= 0; i-- {
+		if ms.Swings[i].IsHigh == isHigh {
+			return ms.Swings[i].Label
+		}
+	}
+	return ""
+}
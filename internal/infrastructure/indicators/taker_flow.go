@@ -0,0 +1,85 @@
+package indicators
+
+// Binance kline columns used for taker flow
+const (
+	klineQuoteVolumeIdx   = 7
+	klineTakerBuyQuoteIdx = 10
+)
+
+// ParseTakerFlow extracts quote volume and taker-buy quote volume from raw
+// Binance klines. Klines without those columns yield zeros.
+func ParseTakerFlow(klines [][]interface{}) (quoteVolumes, takerBuyQuote []float64) {
+	quoteVolumes = make([]float64, len(klines))
+	takerBuyQuote = make([]float64, len(klines))
+	for i, k := range klines {
+		if len(k) <= klineTakerBuyQuoteIdx {
+			continue
+		}
+		quoteVolumes[i] = parseToFloat(k[klineQuoteVolumeIdx])
+		takerBuyQuote[i] = parseToFloat(k[klineTakerBuyQuoteIdx])
+	}
+	return quoteVolumes, takerBuyQuote
+}
+
+// CalculateTakerDelta returns per-candle net aggression in quote currency:
+// taker buys minus taker sells (sells = quote volume - taker buys).
+func CalculateTakerDelta(quoteVolumes, takerBuyQuote []float64) []float64 {
+	n := len(quoteVolumes)
+	delta := make([]float64, n)
+	if len(takerBuyQuote) < n {
+		return delta
+	}
+	for i := 0; i < n; i++ {
+		delta[i] = 2*takerBuyQuote[i] - quoteVolumes[i]
+	}
+	return delta
+}
+
+// TakerDeltaRatio is the net delta of the last `window` candles divided by
+// their quote volume: -1 (all taker sells) to +1 (all taker buys).
+func TakerDeltaRatio(delta, quoteVolumes []float64, window int) float64 {
+	n := len(delta)
+	if window <= 0 || n < window || len(quoteVolumes) < n {
+		return 0
+	}
+	sumDelta, sumVol := 0.0, 0.0
+	for i := n - window; i < n; i++ {
+		sumDelta += delta[i]
+		sumVol += quoteVolumes[i]
+	}
+	if sumVol == 0 {
+		return 0
+	}
+	return sumDelta / sumVol
+}
+
+// CalculateCumulativeDelta sums the taker delta candle by candle (CVD). The
+// level depends on where the series starts; only its shape is meaningful.
+func CalculateCumulativeDelta(delta []float64) []float64 {
+	cvd := make([]float64, len(delta))
+	sum := 0.0
+	for i, d := range delta {
+		sum += d
+		cvd[i] = sum
+	}
+	return cvd
+}
+
+// DetectCVDBearishDivergence reports whether, within the last `lookback`
+// candles, price made a higher high while cumulative delta made a lower
+// high: the new high was reached without net taker buying behind it.
+func DetectCVDBearishDivergence(highs, cvd []float64, lookback int) bool {
+	n := len(highs)
+	if n < lookback || len(cvd) < n {
+		return false
+	}
+
+	peaks := findLocalPeaks(highs, n-lookback, n)
+	if len(peaks) < 2 {
+		return false
+	}
+	last := peaks[len(peaks)-1]
+	prev := peaks[len(peaks)-2]
+
+	return highs[last] > highs[prev] && cvd[last] < cvd[prev]
+}
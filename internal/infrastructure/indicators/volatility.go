@@ -0,0 +1,59 @@
+package indicators
+
+// Volatility regimes by ATR percentile
+const (
+	VolRegimeLow    = "LOW"    // bottom quartile: dead market
+	VolRegimeNormal = "NORMAL" // middle half
+	VolRegimeHigh   = "HIGH"   // top quartile: expanded ranges
+)
+
+// minRegimeSamples is the least number of ATR readings needed to rank the current one
+const minRegimeSamples = 20
+
+// VolatilityRegime ranks the latest ATR (as % of close, so it is comparable
+// across price levels) against the previous `lookback` readings and classifies
+// it. Returns the percentile (0-100) and regime, or (0, "") without enough data.
+func VolatilityRegime(atr, closes []float64, lookback int) (float64, string) {
+	n := len(atr)
+	if n == 0 || len(closes) < n || atr[n-1] == 0 || closes[n-1] == 0 {
+		return 0, ""
+	}
+
+	current := atr[n-1] / closes[n-1]
+	below, samples := 0, 0
+	for i := n - 2; i >= 0 && samples < lookback; i-- {
+		if atr[i] == 0 || closes[i] == 0 {
+			break // ATR warm-up reached
+		}
+		if atr[i]/closes[i] < current {
+			below++
+		}
+		samples++
+	}
+	if samples < minRegimeSamples {
+		return 0, ""
+	}
+
+	percentile := float64(below) / float64(samples) * 100
+	switch {
+	case percentile < 25:
+		return percentile, VolRegimeLow
+	case percentile > 75:
+		return percentile, VolRegimeHigh
+	default:
+		return percentile, VolRegimeNormal
+	}
+}
+
+// VolatilityStopMultiplier scales a stop distance for the regime: tighter in
+// quiet markets, wider when ranges expand so normal noise does not stop out.
+func VolatilityStopMultiplier(regime string) float64 {
+	switch regime {
+	case VolRegimeLow:
+		return 0.75
+	case VolRegimeHigh:
+		return 1.5
+	default:
+		return 1.0
+	}
+}
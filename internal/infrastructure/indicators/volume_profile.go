@@ -0,0 +1,105 @@
+package indicators
+
+import "math"
+
+// VolumeBucket is one price bucket of a volume profile.
+type VolumeBucket struct {
+	Low    float64
+	High   float64
+	Volume float64
+}
+
+// VolumeProfile distributes traded volume over price buckets.
+type VolumeProfile struct {
+	Buckets       []VolumeBucket
+	POC           float64 // point of control: mid price of the highest-volume bucket
+	ValueAreaHigh float64
+	ValueAreaLow  float64
+}
+
+// CalculateVolumeProfile builds a profile over the last `lookback` candles with
+// `buckets` equal price buckets. Each candle's volume is spread over the
+// buckets its high-low range overlaps. The value area grows outwards from the
+// POC until it holds valueAreaPct (e.g. 0.7) of the volume. Returns a zero
+// profile when there is not enough data.
+func CalculateVolumeProfile(highs, lows, volumes []float64, lookback, buckets int, valueAreaPct float64) VolumeProfile {
+	n := len(highs)
+	if lookback <= 0 || buckets <= 0 || n < lookback || len(lows) < n || len(volumes) < n {
+		return VolumeProfile{}
+	}
+	start := n - lookback
+
+	lo, hi := lows[start], highs[start]
+	for i := start; i < n; i++ {
+		lo = math.Min(lo, lows[i])
+		hi = math.Max(hi, highs[i])
+	}
+	if hi <= lo {
+		return VolumeProfile{}
+	}
+
+	width := (hi - lo) / float64(buckets)
+	profile := VolumeProfile{Buckets: make([]VolumeBucket, buckets)}
+	for b := range profile.Buckets {
+		profile.Buckets[b].Low = lo + float64(b)*width
+		profile.Buckets[b].High = lo + float64(b+1)*width
+	}
+
+	total := 0.0
+	for i := start; i < n; i++ {
+		candleRange := highs[i] - lows[i]
+		if candleRange <= 0 {
+			// Doji-like candle: all volume in one bucket
+			b := int((highs[i] - lo) / width)
+			if b >= buckets {
+				b = buckets - 1
+			}
+			profile.Buckets[b].Volume += volumes[i]
+			total += volumes[i]
+			continue
+		}
+		first := int((lows[i] - lo) / width)
+		last := int((highs[i] - lo) / width)
+		if last >= buckets {
+			last = buckets - 1
+		}
+		for b := first; b <= last; b++ {
+			overlap := math.Min(highs[i], profile.Buckets[b].High) - math.Max(lows[i], profile.Buckets[b].Low)
+			if overlap > 0 {
+				profile.Buckets[b].Volume += volumes[i] * overlap / candleRange
+			}
+		}
+		total += volumes[i]
+	}
+
+	poc := 0
+	for b := range profile.Buckets {
+		if profile.Buckets[b].Volume > profile.Buckets[poc].Volume {
+			poc = b
+		}
+	}
+	profile.POC = (profile.Buckets[poc].Low + profile.Buckets[poc].High) / 2
+
+	// Value area: add the heavier neighbour until the target share is covered
+	low, high := poc, poc
+	covered := profile.Buckets[poc].Volume
+	for covered < total*valueAreaPct && (low > 0 || high < buckets-1) {
+		below, above := -1.0, -1.0
+		if low > 0 {
+			below = profile.Buckets[low-1].Volume
+		}
+		if high < buckets-1 {
+			above = profile.Buckets[high+1].Volume
+		}
+		if above >= below {
+			high++
+			covered += above
+		} else {
+			low--
+			covered += below
+		}
+	}
+	profile.ValueAreaLow = profile.Buckets[low].Low
+	profile.ValueAreaHigh = profile.Buckets[high].High
+	return profile
+}
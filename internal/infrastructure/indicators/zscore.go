@@ -0,0 +1,32 @@
+package indicators
+
+import "math"
+
+// CalculateZScore returns how many standard deviations each value sits from
+// its rolling `period` mean. A volatility-normalized overextension measure.
+func CalculateZScore(data []float64, period int) []float64 {
+	z := make([]float64, len(data))
+	if period < 2 || len(data) < period {
+		return z
+	}
+
+	for i := period - 1; i < len(data); i++ {
+		sum := 0.0
+		for j := 0; j < period; j++ {
+			sum += data[i-j]
+		}
+		mean := sum / float64(period)
+
+		sumSq := 0.0
+		for j := 0; j < period; j++ {
+			diff := data[i-j] - mean
+			sumSq += diff * diff
+		}
+		stdDev := math.Sqrt(sumSq / float64(period))
+		if stdDev > 0 {
+			z[i] = (data[i] - mean) / stdDev
+		}
+	}
+
+	return z
+}
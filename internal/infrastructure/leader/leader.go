@@ -0,0 +1,130 @@
+// Package leader elects one instance to run the background workers (screener,
+// autoscalper, monitors) when the app is scaled to several dynos. Every
+// instance keeps serving HTTP and WebSocket.
+package leader
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// lock is one backend able to hold a cluster-wide exclusive lock
+type lock interface {
+	// tryAcquire takes the lock, or renews it if already held; false means
+	// another instance holds it
+	tryAcquire(ctx context.Context) (bool, error)
+	release(ctx context.Context)
+	String() string
+}
+
+// Elector keeps trying to hold the lock and reports whether this instance
+// currently does.
+type Elector struct {
+	lock     lock
+	interval time.Duration
+	instance string
+
+	leader atomic.Bool
+	mu     sync.Mutex
+	notify []func(leader bool)
+}
+
+func newElector(l lock, ttl time.Duration) *Elector {
+	return &Elector{lock: l, interval: ttl / 3, instance: InstanceID()}
+}
+
+// Single is for deployments without a shared store: this instance always leads
+func Single() *Elector {
+	e := newElector(staticLock{}, 3*time.Second)
+	e.leader.Store(true)
+	return e
+}
+
+// IsLeader reports whether this instance should run the background workers
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Instance identifies this process in logs and status output
+func (e *Elector) Instance() string {
+	return e.instance
+}
+
+// Backend names the lock in use (redis, postgres, none)
+func (e *Elector) Backend() string {
+	return e.lock.String()
+}
+
+// OnChange registers fn to run whenever leadership is gained or lost
+func (e *Elector) OnChange(fn func(leader bool)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.notify = append(e.notify, fn)
+}
+
+// Run campaigns until ctx is cancelled, then releases the lock so another
+// instance can take over without waiting for it to expire.
+func (e *Elector) Run(ctx context.Context) {
+	if _, ok := e.lock.(staticLock); ok {
+		return
+	}
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := e.lock.tryAcquire(ctx)
+		if err != nil {
+			log.Printf("Leader lock (%s): %v", e.lock, err)
+			ok = false // can't prove we still hold it
+		}
+		e.set(ok)
+
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			e.lock.release(releaseCtx)
+			cancel()
+			e.set(false)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Elector) set(leader bool) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+	if leader {
+		log.Printf("✓ Instance %s is now the worker leader (%s lock)", e.instance, e.lock)
+	} else {
+		log.Printf("⚠ Instance %s lost the worker lead; background jobs paused", e.instance)
+	}
+
+	e.mu.Lock()
+	notify := append([]func(bool){}, e.notify...)
+	e.mu.Unlock()
+	for _, fn := range notify {
+		fn(leader)
+	}
+}
+
+// InstanceID is the Heroku dyno name when available, else host and pid
+func InstanceID() string {
+	if dyno := os.Getenv("DYNO"); dyno != "" {
+		return dyno
+	}
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+type staticLock struct{}
+
+func (staticLock) tryAcquire(context.Context) (bool, error) { return true, nil }
+func (staticLock) release(context.Context)                  {}
+func (staticLock) String() string                           { return "none" }
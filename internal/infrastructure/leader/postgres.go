@@ -0,0 +1,76 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// workerLockID is the pg advisory lock key for the worker lead (see
+// migrationLockID in the db package for the other one in use)
+const workerLockID = 7240316
+
+// postgresLock holds a session advisory lock on a connection taken out of
+// the pool, so the lock lives exactly as long as that connection.
+type postgresLock struct {
+	pool *pgxpool.Pool
+	conn *pgx.Conn
+	held bool
+}
+
+// NewPostgres elects through a Postgres advisory lock
+func NewPostgres(pool *pgxpool.Pool, ttl time.Duration) *Elector {
+	return newElector(&postgresLock{pool: pool}, ttl)
+}
+
+func (l *postgresLock) tryAcquire(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if l.conn == nil {
+		pc, err := l.pool.Acquire(ctx)
+		if err != nil {
+			return false, err
+		}
+		l.conn = pc.Hijack()
+		l.held = false
+	}
+
+	if l.held {
+		// The lock is ours while the session is alive
+		if _, err := l.conn.Exec(ctx, `select 1`); err != nil {
+			l.drop()
+			return false, err
+		}
+		return true, nil
+	}
+
+	var ok bool
+	if err := l.conn.QueryRow(ctx, `select pg_try_advisory_lock($1)`, workerLockID).Scan(&ok); err != nil {
+		l.drop()
+		return false, err
+	}
+	l.held = ok
+	return ok, nil
+}
+
+func (l *postgresLock) release(ctx context.Context) {
+	if l.conn == nil {
+		return
+	}
+	if l.held {
+		l.conn.Exec(ctx, `select pg_advisory_unlock($1)`, workerLockID)
+	}
+	l.drop()
+}
+
+// drop closes the session, which also frees the lock server-side
+func (l *postgresLock) drop() {
+	l.conn.Close(context.Background())
+	l.conn = nil
+	l.held = false
+}
+
+func (l *postgresLock) String() string { return "postgres" }
@@ -0,0 +1,57 @@
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"screener-backend/internal/infrastructure/redis"
+)
+
+const redisLeaderKey = "screener:leader"
+
+// Compare-and-set scripts so an instance only renews or frees its own lock
+const (
+	renewScript   = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+	releaseScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+)
+
+// redisLock is a single-key lease that the holder renews every ttl/3
+type redisLock struct {
+	client *redis.Client
+	token  string
+	ttl    time.Duration
+}
+
+// NewRedis elects through a lease key in Redis
+func NewRedis(client *redis.Client, ttl time.Duration) *Elector {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	l := &redisLock{client: client, ttl: ttl, token: InstanceID() + ":" + hex.EncodeToString(buf)}
+	return newElector(l, ttl)
+}
+
+func (l *redisLock) tryAcquire(context.Context) (bool, error) {
+	ttl := strconv.FormatInt(l.ttl.Milliseconds(), 10)
+	reply, err := l.client.Do("SET", redisLeaderKey, l.token, "NX", "PX", ttl)
+	if err != nil {
+		return false, err
+	}
+	if reply == "OK" {
+		return true, nil
+	}
+	renewed, err := l.client.Do("EVAL", renewScript, "1", redisLeaderKey, l.token, ttl)
+	if err != nil {
+		return false, err
+	}
+	n, _ := renewed.(int64)
+	return n == 1, nil
+}
+
+func (l *redisLock) release(context.Context) {
+	l.client.Do("EVAL", releaseScript, "1", redisLeaderKey, l.token)
+}
+
+func (l *redisLock) String() string { return "redis" }
@@ -0,0 +1,128 @@
+// Package logging puts levels and a per-symbol switch on top of the standard
+// logger, both changeable at runtime. At info, a cycle logs a handful of
+// lines; per-symbol detail is only written for the symbols selected with
+// SetSymbols, so debugging one coin doesn't flood the log quota.
+package logging
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// Level orders log lines by importance
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	default:
+		return "warn"
+	}
+}
+
+// ParseLevel reads debug, info or warn
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q (expected debug, info or warn)", s)
+	}
+}
+
+var (
+	level   atomic.Int32 // Level; starts at info (see init)
+	symbols atomic.Pointer[symbolFilter]
+)
+
+func init() {
+	level.Store(int32(LevelInfo))
+}
+
+// symbolFilter selects the symbols whose per-symbol lines are written
+type symbolFilter struct {
+	all bool
+	set map[string]bool
+}
+
+// SetLevel changes the minimum level written
+func SetLevel(l Level) {
+	level.Store(int32(l))
+}
+
+// CurrentLevel returns the minimum level written
+func CurrentLevel() Level {
+	return Level(level.Load())
+}
+
+// SetSymbols selects the symbols logged by Symbolf: a comma-separated list
+// such as "BTCUSDT,ETHUSDT", "*" for every symbol, or empty for none
+func SetSymbols(list string) {
+	f := &symbolFilter{set: make(map[string]bool)}
+	for _, s := range strings.Split(list, ",") {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		switch s {
+		case "":
+		case "*":
+			f.all = true
+		default:
+			f.set[s] = true
+		}
+	}
+	if !f.all && len(f.set) == 0 {
+		f = nil
+	}
+	symbols.Store(f)
+}
+
+// SymbolEnabled reports whether per-symbol lines are written for symbol
+func SymbolEnabled(symbol string) bool {
+	f := symbols.Load()
+	return f != nil && (f.all || f.set[symbol])
+}
+
+// Debugf logs detail useful while investigating
+func Debugf(format string, args ...interface{}) {
+	output(LevelDebug, format, args...)
+}
+
+// Infof logs routine progress (cycle started, notification sent)
+func Infof(format string, args ...interface{}) {
+	output(LevelInfo, format, args...)
+}
+
+// Warnf logs failures that were handled but need attention
+func Warnf(format string, args ...interface{}) {
+	output(LevelWarn, format, args...)
+}
+
+// Symbolf logs per-symbol screener detail when symbol is selected, whatever
+// the level
+func Symbolf(symbol, format string, args ...interface{}) {
+	if !SymbolEnabled(symbol) {
+		return
+	}
+	log.Output(2, "["+symbol+"] "+fmt.Sprintf(format, args...))
+}
+
+func output(l Level, format string, args ...interface{}) {
+	if l < CurrentLevel() {
+		return
+	}
+	log.Output(3, fmt.Sprintf(format, args...))
+}
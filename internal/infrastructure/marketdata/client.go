@@ -0,0 +1,134 @@
+// Package marketdata fetches market-wide context from public APIs:
+// CoinGecko's global market data (BTC dominance, total market cap) and
+// alternative.me's Fear & Greed index.
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	CoinGeckoBaseURL = "https://api.coingecko.com"
+	FearGreedBaseURL = "https://api.alternative.me"
+)
+
+// requestTimeout caps one request; both APIs answer in well under a second
+const requestTimeout = 10 * time.Second
+
+// Client calls CoinGecko and alternative.me
+type Client struct {
+	httpClient   *http.Client
+	coinGeckoURL string
+	fearGreedURL string
+	coinGeckoKey string // optional demo API key for higher rate limits
+}
+
+// NewClient creates a client; empty URLs use the public APIs
+func NewClient(coinGeckoURL, fearGreedURL, coinGeckoKey string) *Client {
+	if coinGeckoURL == "" {
+		coinGeckoURL = CoinGeckoBaseURL
+	}
+	if fearGreedURL == "" {
+		fearGreedURL = FearGreedBaseURL
+	}
+	return &Client{
+		httpClient:   &http.Client{Timeout: requestTimeout},
+		coinGeckoURL: coinGeckoURL,
+		fearGreedURL: fearGreedURL,
+		coinGeckoKey: coinGeckoKey,
+	}
+}
+
+// Global is CoinGecko's market-wide data
+type Global struct {
+	BTCDominance          float64 // percent of total market cap
+	ETHDominance          float64
+	TotalMarketCapUSD     float64
+	MarketCapChange24hPct float64
+	UpdatedAt             time.Time
+}
+
+// FearGreed is the latest Fear & Greed index reading
+type FearGreed struct {
+	Value          int    // 0 (extreme fear) to 100 (extreme greed)
+	Classification string // e.g. "Extreme Fear", "Greed"
+	Timestamp      time.Time
+}
+
+// GetGlobal returns GET /api/v3/global
+func (c *Client) GetGlobal(ctx context.Context) (*Global, error) {
+	var body struct {
+		Data struct {
+			TotalMarketCap      map[string]float64 `json:"total_market_cap"`
+			MarketCapPercentage map[string]float64 `json:"market_cap_percentage"`
+			MarketCapChange24h  float64            `json:"market_cap_change_percentage_24h_usd"`
+			UpdatedAt           int64              `json:"updated_at"`
+		} `json:"data"`
+	}
+	headers := map[string]string{}
+	if c.coinGeckoKey != "" {
+		headers["x-cg-demo-api-key"] = c.coinGeckoKey
+	}
+	if err := c.getJSON(ctx, c.coinGeckoURL+"/api/v3/global", headers, &body); err != nil {
+		return nil, fmt.Errorf("coingecko: %w", err)
+	}
+	if body.Data.MarketCapPercentage["btc"] == 0 {
+		return nil, fmt.Errorf("coingecko: response without BTC dominance")
+	}
+	return &Global{
+		BTCDominance:          body.Data.MarketCapPercentage["btc"],
+		ETHDominance:          body.Data.MarketCapPercentage["eth"],
+		TotalMarketCapUSD:     body.Data.TotalMarketCap["usd"],
+		MarketCapChange24hPct: body.Data.MarketCapChange24h,
+		UpdatedAt:             time.Unix(body.Data.UpdatedAt, 0).UTC(),
+	}, nil
+}
+
+// GetFearGreed returns the latest reading of GET /fng/?limit=1
+func (c *Client) GetFearGreed(ctx context.Context) (*FearGreed, error) {
+	var body struct {
+		Data []struct {
+			Value          string `json:"value"`
+			Classification string `json:"value_classification"`
+			Timestamp      string `json:"timestamp"`
+		} `json:"data"`
+	}
+	if err := c.getJSON(ctx, c.fearGreedURL+"/fng/?limit=1", nil, &body); err != nil {
+		return nil, fmt.Errorf("fear & greed: %w", err)
+	}
+	if len(body.Data) == 0 {
+		return nil, fmt.Errorf("fear & greed: empty response")
+	}
+	latest := body.Data[0]
+	value, err := strconv.Atoi(latest.Value)
+	if err != nil {
+		return nil, fmt.Errorf("fear & greed: invalid value %q", latest.Value)
+	}
+	ts, _ := strconv.ParseInt(latest.Timestamp, 10, 64)
+	return &FearGreed{Value: value, Classification: latest.Classification, Timestamp: time.Unix(ts, 0).UTC()}, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, url string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,259 @@
+// Package metrics is a small in-process metrics registry (counters, gauges,
+// histograms with labels) served in the Prometheus text format on /metrics.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are latency buckets in seconds
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Default is the process-wide registry served by Handler
+var Default = NewRegistry()
+
+type metric interface {
+	write(w io.Writer)
+}
+
+// Registry holds named metrics in registration order
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// Write writes every metric in the Prometheus text exposition format
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// Handler serves the default registry
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		Default.Write(w)
+	})
+}
+
+// vec tracks one series per label-value combination
+type vec[T any] struct {
+	name, help, kind string
+	labels           []string
+	mu               sync.Mutex
+	series           map[string]*T
+	newSeries        func() *T
+}
+
+func (v *vec[T]) with(values ...string) *T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d labels, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s, ok := v.series[key]
+	if !ok {
+		s = v.newSeries()
+		v.series[key] = s
+	}
+	return s
+}
+
+// each visits series sorted by label values
+func (v *vec[T]) each(fn func(labels string, s *T)) {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	series := make([]*T, len(keys))
+	for i, k := range keys {
+		series[i] = v.series[k]
+	}
+	v.mu.Unlock()
+
+	for i, k := range keys {
+		fn(formatLabels(v.labels, strings.Split(k, "\xff")), series[i])
+	}
+}
+
+func (v *vec[T]) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, v.kind)
+}
+
+// CounterVec is a monotonically increasing value per label set
+type CounterVec struct {
+	vec[floatValue]
+}
+
+// floatValue is a float guarded by a mutex; fine for the update rates involved
+type floatValue struct {
+	mu sync.Mutex
+	v  float64
+}
+
+func (f *floatValue) add(d float64) {
+	f.mu.Lock()
+	f.v += d
+	f.mu.Unlock()
+}
+
+func (f *floatValue) set(v float64) {
+	f.mu.Lock()
+	f.v = v
+	f.mu.Unlock()
+}
+
+func (f *floatValue) get() float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.v
+}
+
+// NewCounterVec registers a counter on the default registry
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec[floatValue]{name: name, help: help, kind: "counter", labels: labels,
+		series: make(map[string]*floatValue), newSeries: func() *floatValue { return &floatValue{} }}}
+	Default.register(c)
+	return c
+}
+
+// Inc adds one to the series with the given label values
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.with(labelValues...).add(1)
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.header(w)
+	c.each(func(labels string, s *floatValue) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, labels, formatFloat(s.get()))
+	})
+}
+
+// GaugeVec is a value that can go up and down per label set
+type GaugeVec struct {
+	vec[floatValue]
+}
+
+// NewGaugeVec registers a gauge on the default registry
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{vec[floatValue]{name: name, help: help, kind: "gauge", labels: labels,
+		series: make(map[string]*floatValue), newSeries: func() *floatValue { return &floatValue{} }}}
+	Default.register(g)
+	return g
+}
+
+// Set stores v for the series with the given label values
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	g.with(labelValues...).set(v)
+}
+
+func (g *GaugeVec) write(w io.Writer) {
+	g.header(w)
+	g.each(func(labels string, s *floatValue) {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, labels, formatFloat(s.get()))
+	})
+}
+
+// HistogramVec counts observations into cumulative buckets per label set
+type HistogramVec struct {
+	vec[histogram]
+	buckets []float64
+}
+
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64 // per bucket, non-cumulative; last is +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewHistogramVec registers a histogram on the default registry
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{buckets: buckets}
+	h.vec = vec[histogram]{name: name, help: help, kind: "histogram", labels: labels,
+		series: make(map[string]*histogram), newSeries: func() *histogram {
+			return &histogram{counts: make([]uint64, len(buckets)+1)}
+		}}
+	Default.register(h)
+	return h
+}
+
+// Observe records v for the series with the given label values
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	s := h.with(labelValues...)
+	i := sort.SearchFloat64s(h.buckets, v)
+	s.mu.Lock()
+	s.counts[i]++
+	s.sum += v
+	s.count++
+	s.mu.Unlock()
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.header(w)
+	h.each(func(labels string, s *histogram) {
+		s.mu.Lock()
+		counts := append([]uint64(nil), s.counts...)
+		sum, count := s.sum, s.count
+		s.mu.Unlock()
+
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, withLabel(labels, "le", formatFloat(le)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, withLabel(labels, "le", "+Inf"), count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, labels, formatFloat(sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, labels, count)
+	})
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	parts := make([]string, len(names))
+	for i, n := range names {
+		parts[i] = n + "=" + strconv.Quote(values[i])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func withLabel(labels, name, value string) string {
+	pair := name + "=" + strconv.Quote(value)
+	if labels == "" {
+		return "{" + pair + "}"
+	}
+	return labels[:len(labels)-1] + "," + pair + "}"
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
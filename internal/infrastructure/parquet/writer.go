@@ -0,0 +1,292 @@
+// Package parquet writes flat tables as Apache Parquet files: one row group,
+// one uncompressed PLAIN-encoded page per column, all columns required. That
+// is enough for dataset exports read by pandas, Polars, Spark or DuckDB.
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Type is the type of a column's values
+type Type int
+
+const (
+	Double    Type = iota // float64; NaN marks a missing value
+	Timestamp             // time.Time, stored as milliseconds since the epoch
+	String                // string, UTF-8
+)
+
+// Column describes one column of the table
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Parquet physical and converted types, encodings and page types used here
+const (
+	physicalInt64     = 2
+	physicalDouble    = 5
+	physicalByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	repetitionRequired = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecUncompressed  = 0
+	pageData           = 0
+)
+
+var magic = []byte("PAR1")
+
+// Writer buffers rows column by column and writes the file in one go
+type Writer struct {
+	columns []Column
+	data    [][]byte // PLAIN-encoded values per column
+	rows    int
+}
+
+// NewWriter creates a writer for a table with the given columns
+func NewWriter(columns []Column) *Writer {
+	return &Writer{columns: columns, data: make([][]byte, len(columns))}
+}
+
+// Append adds a row; values follow the column order and types
+func (w *Writer) Append(row []any) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, want %d", len(row), len(w.columns))
+	}
+	for i, col := range w.columns {
+		switch col.Type {
+		case Double:
+			v, ok := row[i].(float64)
+			if !ok {
+				return fmt.Errorf("parquet: column %s: want float64, got %T", col.Name, row[i])
+			}
+			w.data[i] = binary.LittleEndian.AppendUint64(w.data[i], math.Float64bits(v))
+		case Timestamp:
+			v, ok := row[i].(time.Time)
+			if !ok {
+				return fmt.Errorf("parquet: column %s: want time.Time, got %T", col.Name, row[i])
+			}
+			w.data[i] = binary.LittleEndian.AppendUint64(w.data[i], uint64(v.UnixMilli()))
+		case String:
+			v, ok := row[i].(string)
+			if !ok {
+				return fmt.Errorf("parquet: column %s: want string, got %T", col.Name, row[i])
+			}
+			w.data[i] = binary.LittleEndian.AppendUint32(w.data[i], uint32(len(v)))
+			w.data[i] = append(w.data[i], v...)
+		}
+	}
+	w.rows++
+	return nil
+}
+
+// WriteTo writes the whole file to out
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	cw := &countingWriter{w: out}
+	cw.write(magic)
+
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunk, len(w.columns))
+	for i := range w.columns {
+		header := &compact{}
+		header.i32(1, pageData)
+		header.i32(2, int32(len(w.data[i])))
+		header.i32(3, int32(len(w.data[i])))
+		header.structBegin(5)
+		header.i32(1, int32(w.rows))
+		header.i32(2, encodingPlain)
+		header.i32(3, encodingRLE)
+		header.i32(4, encodingRLE)
+		header.structEnd()
+		header.stop()
+
+		chunks[i].offset = cw.n
+		cw.write(header.b)
+		cw.write(w.data[i])
+		chunks[i].size = cw.n - chunks[i].offset
+	}
+
+	meta := &compact{}
+	meta.i32(1, 1) // version
+	meta.listBegin(2, typeStruct, len(w.columns)+1)
+	meta.elemBegin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(w.columns)))
+	meta.elemEnd()
+	for _, col := range w.columns {
+		meta.elemBegin()
+		meta.i32(1, physicalType(col.Type))
+		meta.i32(3, repetitionRequired)
+		meta.binary(4, col.Name)
+		switch col.Type {
+		case String:
+			meta.i32(6, convertedUTF8)
+		case Timestamp:
+			meta.i32(6, convertedTimestampMillis)
+		}
+		meta.elemEnd()
+	}
+	meta.i64(3, int64(w.rows))
+
+	var total int64
+	for _, c := range chunks {
+		total += c.size
+	}
+	meta.listBegin(4, typeStruct, 1)
+	meta.elemBegin()
+	meta.listBegin(1, typeStruct, len(w.columns))
+	for i, col := range w.columns {
+		meta.elemBegin()
+		meta.i64(2, chunks[i].offset)
+		meta.structBegin(3)
+		meta.i32(1, physicalType(col.Type))
+		meta.listBegin(2, typeI32, 1)
+		meta.varint(zigzag(encodingPlain))
+		meta.listBegin(3, typeBinary, 1)
+		meta.str(col.Name)
+		meta.i32(4, codecUncompressed)
+		meta.i64(5, int64(w.rows))
+		meta.i64(6, chunks[i].size)
+		meta.i64(7, chunks[i].size)
+		meta.i64(9, chunks[i].offset)
+		meta.structEnd()
+		meta.elemEnd()
+	}
+	meta.i64(2, total)
+	meta.i64(3, int64(w.rows))
+	meta.elemEnd()
+	meta.binary(6, "screener-backend")
+	meta.stop()
+
+	cw.write(meta.b)
+	cw.write(binary.LittleEndian.AppendUint32(nil, uint32(len(meta.b))))
+	cw.write(magic)
+	return cw.n, cw.err
+}
+
+func physicalType(t Type) int32 {
+	switch t {
+	case Timestamp:
+		return physicalInt64
+	case String:
+		return physicalByteArray
+	default:
+		return physicalDouble
+	}
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) write(p []byte) {
+	if c.err != nil {
+		return
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+}
+
+// Thrift compact protocol types
+const (
+	typeI32    = 5
+	typeI64    = 6
+	typeBinary = 8
+	typeList   = 9
+	typeStruct = 12
+)
+
+// compact encodes the Thrift compact protocol, enough for Parquet metadata
+type compact struct {
+	b    []byte
+	last []int16 // field id last written, per open struct
+}
+
+func (c *compact) field(id int16, typ byte) {
+	if len(c.last) == 0 {
+		c.last = []int16{0}
+	}
+	top := len(c.last) - 1
+	if delta := id - c.last[top]; delta > 0 && delta <= 15 {
+		c.b = append(c.b, byte(delta)<<4|typ)
+	} else {
+		c.b = append(c.b, typ)
+		c.varint(zigzag(int64(id)))
+	}
+	c.last[top] = id
+}
+
+func (c *compact) varint(v uint64) {
+	c.b = binary.AppendUvarint(c.b, v)
+}
+
+func (c *compact) str(v string) {
+	c.varint(uint64(len(v)))
+	c.b = append(c.b, v...)
+}
+
+func (c *compact) i32(id int16, v int32) {
+	c.field(id, typeI32)
+	c.varint(zigzag(int64(v)))
+}
+
+func (c *compact) i64(id int16, v int64) {
+	c.field(id, typeI64)
+	c.varint(zigzag(v))
+}
+
+func (c *compact) binary(id int16, v string) {
+	c.field(id, typeBinary)
+	c.str(v)
+}
+
+func (c *compact) listBegin(id int16, elem byte, size int) {
+	c.field(id, typeList)
+	if size < 15 {
+		c.b = append(c.b, byte(size)<<4|elem)
+		return
+	}
+	c.b = append(c.b, 0xf0|elem)
+	c.varint(uint64(size))
+}
+
+// structBegin opens a struct-typed field
+func (c *compact) structBegin(id int16) {
+	c.field(id, typeStruct)
+	c.elemBegin()
+}
+
+func (c *compact) structEnd() { c.elemEnd() }
+
+// elemBegin opens a struct that is a list element (no field header)
+func (c *compact) elemBegin() {
+	if len(c.last) == 0 {
+		c.last = []int16{0}
+	}
+	c.last = append(c.last, 0)
+}
+
+func (c *compact) elemEnd() {
+	c.stop()
+	c.last = c.last[:len(c.last)-1]
+}
+
+func (c *compact) stop() { c.b = append(c.b, 0) }
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
@@ -0,0 +1,83 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresChannel is the LISTEN/NOTIFY channel. NOTIFY payloads are capped
+// at 8000 bytes, far less than a cycle's coins, so the event goes into the
+// events table and only its id is sent.
+const postgresChannel = "screener_events"
+
+// eventRetention is how long published events are kept for listeners to read
+const eventRetention = 10 * time.Minute
+
+type postgresBroker struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgres broadcasts through Postgres LISTEN/NOTIFY
+func NewPostgres(pool *pgxpool.Pool) Broker {
+	return &postgresBroker{pool: pool}
+}
+
+func (b *postgresBroker) Publish(ctx context.Context, topic string, payload []byte) error {
+	_, err := b.pool.Exec(ctx, `
+		with e as (
+			insert into events(topic, payload) values ($1, $2) returning id
+		)
+		select pg_notify('`+postgresChannel+`', id::text) from e
+	`, topic, payload)
+	if err != nil {
+		return err
+	}
+	if _, err := b.pool.Exec(ctx, `delete from events where created_at < now() - $1::interval`, eventRetention.String()); err != nil {
+		log.Printf("Event bus (postgres): pruning events failed: %v", err)
+	}
+	return nil
+}
+
+func (b *postgresBroker) Run(ctx context.Context, fn Handler) {
+	runWithBackoff(ctx, b.String(), func(ctx context.Context) error {
+		return b.listen(ctx, fn)
+	})
+}
+
+// listen holds a connection out of the pool for LISTEN and reads each
+// notified event through the pool
+func (b *postgresBroker) listen(ctx context.Context, fn Handler) error {
+	pc, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	conn := pc.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, `listen `+postgresChannel); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseInt(n.Payload, 10, 64)
+		if err != nil {
+			continue
+		}
+		var topic string
+		var payload []byte
+		if err := b.pool.QueryRow(ctx, `select topic, payload from events where id = $1`, id).Scan(&topic, &payload); err != nil {
+			log.Printf("Event bus (postgres): reading event %d failed: %v", id, err)
+			continue
+		}
+		fn(topic, payload)
+	}
+}
+
+func (b *postgresBroker) String() string { return "postgres" }
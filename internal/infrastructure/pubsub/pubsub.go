@@ -0,0 +1,81 @@
+// Package pubsub broadcasts events to every instance of the app, so each
+// one can push them to its own WebSocket clients no matter which instance
+// produced them.
+package pubsub
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Handler receives every event, including the ones this instance published
+type Handler func(topic string, payload []byte)
+
+// Broker publishes events and delivers them to Run's handler
+type Broker interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Run delivers events to fn until ctx is cancelled, reconnecting after
+	// failures. Events published while disconnected are lost.
+	Run(ctx context.Context, fn Handler)
+	String() string
+}
+
+// Reconnect backoff of the shared brokers
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// runWithBackoff calls subscribe until ctx is cancelled, waiting longer
+// after each failure in a row
+func runWithBackoff(ctx context.Context, name string, subscribe func(ctx context.Context) error) {
+	backoff := minBackoff
+	for {
+		start := time.Now()
+		err := subscribe(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > maxBackoff {
+			backoff = minBackoff // it was connected for a while
+		}
+		log.Printf("Event bus (%s): subscription lost: %v; reconnecting in %v", name, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// local delivers events within this process only
+type local struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewLocal is for a single instance without a shared store
+func NewLocal() Broker {
+	return &local{}
+}
+
+func (b *local) Publish(_ context.Context, topic string, payload []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, fn := range b.handlers {
+		fn(topic, payload)
+	}
+	return nil
+}
+
+func (b *local) Run(ctx context.Context, fn Handler) {
+	b.mu.Lock()
+	b.handlers = append(b.handlers, fn)
+	b.mu.Unlock()
+	<-ctx.Done()
+}
+
+func (b *local) String() string { return "local" }
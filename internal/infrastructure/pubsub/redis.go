@@ -0,0 +1,34 @@
+package pubsub
+
+import (
+	"context"
+	"strings"
+
+	"screener-backend/internal/infrastructure/redis"
+)
+
+// redisChannelPrefix namespaces the event channels; the topic follows it
+const redisChannelPrefix = "screener:events:"
+
+type redisBroker struct {
+	client *redis.Client
+}
+
+// NewRedis broadcasts through Redis pub/sub
+func NewRedis(client *redis.Client) Broker {
+	return &redisBroker{client: client}
+}
+
+func (b *redisBroker) Publish(_ context.Context, topic string, payload []byte) error {
+	return b.client.Publish(redisChannelPrefix+topic, string(payload))
+}
+
+func (b *redisBroker) Run(ctx context.Context, fn Handler) {
+	runWithBackoff(ctx, b.String(), func(ctx context.Context) error {
+		return b.client.PSubscribe(ctx, redisChannelPrefix+"*", func(channel, message string) {
+			fn(strings.TrimPrefix(channel, redisChannelPrefix), []byte(message))
+		})
+	})
+}
+
+func (b *redisBroker) String() string { return "redis" }
@@ -0,0 +1,359 @@
+// Package redis is a minimal RESP2 client covering the handful of commands
+// the app needs (strings, hashes and pub/sub), with a small connection pool.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNil is returned when a key does not exist
+var ErrNil = errors.New("redis: nil")
+
+const (
+	dialTimeout = 5 * time.Second
+	ioTimeout   = 5 * time.Second
+	maxIdle     = 8
+)
+
+// Client talks to one Redis server
+type Client struct {
+	addr     string
+	password string
+	username string
+	db       int
+	useTLS   bool
+
+	mu   sync.Mutex
+	idle []*conn
+}
+
+type conn struct {
+	nc net.Conn
+	rd *bufio.Reader
+}
+
+// NewClient parses a redis:// or rediss:// URL (as set in REDIS_URL) and
+// checks the server is reachable.
+func NewClient(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid REDIS_URL scheme %q", u.Scheme)
+	}
+
+	c := &Client{addr: u.Host, useTLS: u.Scheme == "rediss"}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+	if path := strings.TrimPrefix(u.Path, "/"); path != "" {
+		if c.db, err = strconv.Atoi(path); err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL database %q", path)
+		}
+	}
+
+	if _, err := c.Do("PING"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Do runs one command and returns its reply: string, int64, []interface{} or nil.
+// Server error replies are returned as errors.
+func (c *Client) Do(args ...string) (interface{}, error) {
+	cn, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(args...)
+	var serverErr redisError
+	if err != nil && !errors.As(err, &serverErr) {
+		cn.nc.Close() // connection state unknown
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// Get returns a string value, or ErrNil if the key is missing
+func (c *Client) Get(key string) (string, error) {
+	reply, err := c.Do("GET", key)
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNil
+	}
+	return reply.(string), nil
+}
+
+// Set stores a string value; ttl 0 keeps it forever
+func (c *Client) Set(key, value string, ttl time.Duration) error {
+	args := []string{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := c.Do(args...)
+	return err
+}
+
+// HSet sets several hash fields at once
+func (c *Client) HSet(key string, fields map[string]string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	args := make([]string, 0, 2+2*len(fields))
+	args = append(args, "HSET", key)
+	for f, v := range fields {
+		args = append(args, f, v)
+	}
+	_, err := c.Do(args...)
+	return err
+}
+
+// HGet returns one hash field, or ErrNil if missing
+func (c *Client) HGet(key, field string) (string, error) {
+	reply, err := c.Do("HGET", key, field)
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNil
+	}
+	return reply.(string), nil
+}
+
+// HGetAll returns all fields of a hash (empty map if the key is missing)
+func (c *Client) HGetAll(key string) (map[string]string, error) {
+	reply, err := c.Do("HGETALL", key)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := reply.([]interface{})
+	out := make(map[string]string, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		f, _ := items[i].(string)
+		v, _ := items[i+1].(string)
+		out[f] = v
+	}
+	return out, nil
+}
+
+// Publish sends message to every subscriber of channel
+func (c *Client) Publish(channel, message string) error {
+	_, err := c.Do("PUBLISH", channel, message)
+	return err
+}
+
+// subscribePing keeps an idle subscription alive and detects a dead server
+const subscribePing = 30 * time.Second
+
+// PSubscribe calls fn for every message published to a channel matching
+// pattern, on a dedicated connection, until ctx is cancelled or the
+// connection fails. Callers reconnect by calling it again.
+func (c *Client) PSubscribe(ctx context.Context, pattern string, fn func(channel, message string)) error {
+	cn, err := c.dial()
+	if err != nil {
+		return err
+	}
+	defer cn.nc.Close()
+	if _, err := cn.do("PSUBSCRIBE", pattern); err != nil {
+		return err
+	}
+
+	stop := context.AfterFunc(ctx, func() { cn.nc.Close() })
+	defer stop()
+	go func() {
+		ticker := time.NewTicker(subscribePing)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := cn.write("PING"); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		cn.nc.SetDeadline(time.Now().Add(3 * subscribePing))
+		reply, err := cn.readReply()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		items, _ := reply.([]interface{})
+		if len(items) == 4 && items[0] == "pmessage" {
+			channel, _ := items[2].(string)
+			message, _ := items[3].(string)
+			fn(channel, message)
+		}
+	}
+}
+
+// Close drops all idle connections
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cn := range c.idle {
+		cn.nc.Close()
+	}
+	c.idle = nil
+}
+
+func (c *Client) get() (*conn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+	return c.dial()
+}
+
+func (c *Client) put(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.idle) >= maxIdle {
+		cn.nc.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+func (c *Client) dial() (*conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var nc net.Conn
+	var err error
+	if c.useTLS {
+		// Heroku Redis serves self-signed certificates on rediss://
+		nc, err = tls.DialWithDialer(dialer, "tcp", c.addr, &tls.Config{InsecureSkipVerify: true})
+	} else {
+		nc, err = dialer.Dial("tcp", c.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &conn{nc: nc, rd: bufio.NewReader(nc)}
+	if c.password != "" {
+		args := []string{"AUTH", c.password}
+		if c.username != "" && c.username != "default" {
+			args = []string{"AUTH", c.username, c.password}
+		}
+		if _, err := cn.do(args...); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.do("SELECT", strconv.Itoa(c.db)); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+func (cn *conn) do(args ...string) (interface{}, error) {
+	cn.nc.SetDeadline(time.Now().Add(ioTimeout))
+	if err := cn.write(args...); err != nil {
+		return nil, err
+	}
+	return cn.readReply()
+}
+
+func (cn *conn) write(args ...string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	_, err := io.WriteString(cn.nc, b.String())
+	return err
+}
+
+func (cn *conn) readReply() (interface{}, error) {
+	line, err := cn.rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(cn.rd, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			item, err := cn.readReply()
+			var serverErr redisError
+			if errors.As(err, &serverErr) {
+				items[i] = serverErr // keep reading so the stream stays in sync
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
@@ -0,0 +1,95 @@
+// Package reporting sends failures that need a human (panics, rejected
+// orders, a position left without its stop loss, a job failing over and over)
+// to an error tracker. Call sites use Capture; Init installs the Sentry
+// reporter when a DSN is configured, otherwise events are dropped and the
+// call sites' own log lines remain the only record.
+package reporting
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Level is the severity shown in the tracker
+type Level string
+
+const (
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal" // money at risk, e.g. a position without stop loss
+)
+
+// Event is one reported failure
+type Event struct {
+	Level   Level
+	Message string // stable summary, also used to group events, e.g. "stop loss placement failed"
+	Err     error
+	UserID  string
+	Symbol  string
+	Tags    map[string]string
+	Extra   map[string]interface{}
+	Stack   []byte // goroutine stack, for panics
+}
+
+// Reporter delivers events to an error tracker
+type Reporter interface {
+	Report(ctx context.Context, ev Event)
+	// Shutdown flushes queued events
+	Shutdown(ctx context.Context) error
+}
+
+// Config selects the reporter
+type Config struct {
+	DSN         string // Sentry DSN; reporting is off when empty
+	Environment string
+	Release     string
+	ServerName  string
+}
+
+var current atomic.Pointer[Reporter]
+
+// Init installs the Sentry reporter and returns its shutdown, which flushes
+// pending events. It is a no-op when cfg.DSN is empty.
+func Init(cfg Config) (func(context.Context) error, error) {
+	if cfg.DSN == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	r, err := newSentry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	Use(r)
+	return r.Shutdown, nil
+}
+
+// Use installs another reporter in place of Sentry; nil turns reporting off
+func Use(r Reporter) {
+	if r == nil {
+		current.Store(nil)
+		return
+	}
+	current.Store(&r)
+}
+
+// Capture reports ev. The trace ID of the span in ctx, if any, is attached
+// so the event can be matched with its trace.
+func Capture(ctx context.Context, ev Event) {
+	r := current.Load()
+	if r == nil {
+		return
+	}
+	if ev.Level == "" {
+		ev.Level = LevelError
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		tags := make(map[string]string, len(ev.Tags)+1)
+		for k, v := range ev.Tags {
+			tags[k] = v
+		}
+		tags["trace_id"] = sc.TraceID().String()
+		ev.Tags = tags
+	}
+	(*r).Report(ctx, ev)
+}
@@ -0,0 +1,205 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const sentryQueueSize = 256
+
+// sentryReporter posts events to Sentry's envelope endpoint from a background
+// goroutine. Events are dropped when the queue is full, so a storm of errors
+// can't block trading.
+type sentryReporter struct {
+	endpoint   string
+	auth       string
+	dsn        string
+	cfg        Config
+	httpClient *http.Client
+
+	mu     sync.RWMutex // guards closing queue against concurrent Report
+	closed bool
+	queue  chan sentryEvent
+	done   chan struct{}
+}
+
+// newSentry parses a DSN of the form https://<key>@<host>/<project>
+func newSentry(cfg Config) (*sentryReporter, error) {
+	u, err := url.Parse(cfg.DSN)
+	if err != nil || u.User == nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN")
+	}
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	prefix, project := "", path
+	if i >= 0 {
+		prefix, project = "/"+path[:i], path[i+1:]
+	}
+	if project == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing project id")
+	}
+
+	r := &sentryReporter{
+		endpoint:   fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, prefix, project),
+		auth:       fmt.Sprintf("Sentry sentry_version=7, sentry_client=screener-backend/1.0, sentry_key=%s", u.User.Username()),
+		dsn:        cfg.DSN,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		queue:      make(chan sentryEvent, sentryQueueSize),
+		done:       make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+// sentryEvent is the subset of the Sentry event payload we fill in
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Platform    string                 `json:"platform"`
+	Level       Level                  `json:"level"`
+	Message     string                 `json:"message"`
+	Fingerprint []string               `json:"fingerprint"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	User        *sentryUser            `json:"user,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Exception   *sentryExceptions      `json:"exception,omitempty"`
+}
+
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (r *sentryReporter) Report(_ context.Context, ev Event) {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	e := sentryEvent{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       ev.Level,
+		Message:     ev.Message,
+		Fingerprint: []string{ev.Message},
+		Environment: r.cfg.Environment,
+		Release:     r.cfg.Release,
+		ServerName:  r.cfg.ServerName,
+		Tags:        make(map[string]string, len(ev.Tags)+1),
+		Extra:       make(map[string]interface{}, len(ev.Extra)+1),
+	}
+	for k, v := range ev.Tags {
+		e.Tags[k] = v
+	}
+	for k, v := range ev.Extra {
+		e.Extra[k] = v
+	}
+	if ev.Symbol != "" {
+		e.Tags["symbol"] = ev.Symbol
+	}
+	if ev.UserID != "" {
+		e.User = &sentryUser{ID: ev.UserID}
+	}
+	if ev.Err != nil {
+		e.Exception = &sentryExceptions{Values: []sentryException{{Type: ev.Message, Value: ev.Err.Error()}}}
+	}
+	if len(ev.Stack) > 0 {
+		e.Extra["stack"] = string(ev.Stack)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- e:
+	default:
+		log.Printf("Reporting: queue full, dropped event %q", ev.Message)
+	}
+}
+
+func (r *sentryReporter) run() {
+	defer close(r.done)
+	for e := range r.queue {
+		if err := r.send(e); err != nil {
+			log.Printf("Reporting: sending %q to Sentry failed: %v", e.Message, err)
+		}
+	}
+}
+
+func (r *sentryReporter) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// send posts one event as an envelope: envelope header, item header, payload
+func (r *sentryReporter) send(e sentryEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	header, _ := json.Marshal(map[string]string{
+		"event_id": e.EventID,
+		"dsn":      r.dsn,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	item, _ := json.Marshal(map[string]interface{}{"type": "event", "length": len(payload)})
+
+	var body bytes.Buffer
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(item)
+	body.WriteByte('\n')
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type every struct {
+	interval func() time.Duration
+}
+
+// Every runs a job at a fixed interval after each start. The interval is read
+// again before every wait, so reloaded settings apply from the next run.
+// The first run happens one interval after Start.
+func Every(interval func() time.Duration) Schedule {
+	return every{interval: interval}
+}
+
+func (e every) Next(last, now time.Time) time.Time {
+	if last.IsZero() {
+		last = now
+	}
+	return last.Add(e.interval())
+}
+
+func (e every) String() string {
+	return "every " + e.interval().String()
+}
+
+type dailyAt struct {
+	hour, minute int
+}
+
+// DailyAt runs a job once a day at hour:minute UTC
+func DailyAt(hour, minute int) Schedule {
+	return dailyAt{hour: hour, minute: minute}
+}
+
+func (d dailyAt) Next(last, now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), d.hour, d.minute, 0, 0, time.UTC)
+	if !next.After(now) || !next.After(last) {
+		next = next.Add(24 * time.Hour)
+	}
+	return next
+}
+
+func (d dailyAt) String() string {
+	return fmt.Sprintf("daily at %02d:%02d UTC", d.hour, d.minute)
+}
+
+// Simple adapts a job without an error result
+func Simple(fn func()) Func {
+	return func(context.Context) error {
+		fn()
+		return nil
+	}
+}
+
+// Timeout bounds every run of fn to d; database and exchange calls made with
+// the run's context are cancelled when it expires
+func Timeout(d time.Duration, fn Func) Func {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return fn(ctx)
+	}
+}
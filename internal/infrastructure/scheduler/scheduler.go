@@ -0,0 +1,380 @@
+// Package scheduler runs the app's background jobs (screener cycle, monitors,
+// daily summaries...) on interval or daily schedules, records the outcome of
+// each run and lets operators trigger a job by hand.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/reporting"
+	"sort"
+	"sync"
+	"time"
+)
+
+// failureReportThreshold is how many failed runs in a row get a job reported
+// to the error tracker (once, until it succeeds again)
+const failureReportThreshold = 3
+
+var (
+	// ErrUnknownJob is returned by Trigger for a name that was never registered
+	ErrUnknownJob = domain.NotFound("JOB_NOT_FOUND", "unknown job")
+	// ErrJobRunning is returned by Trigger while the job is still running
+	ErrJobRunning = domain.Conflict("JOB_RUNNING", "job is already running")
+	// ErrNotLeader is returned by Trigger for a leader-only job on a follower
+	ErrNotLeader = domain.Conflict("NOT_LEADER", "job runs on the leader instance only")
+	// ErrStopped is returned by Trigger once the scheduler is stopping
+	ErrStopped = domain.Conflict("SCHEDULER_STOPPED", "the server is shutting down")
+)
+
+// Schedule decides when a job runs next
+type Schedule interface {
+	// Next returns the next run time after a run that started at last
+	// (zero if the job has never run).
+	Next(last, now time.Time) time.Time
+	String() string
+}
+
+// Func is the work done by one run of a job
+type Func func(ctx context.Context) error
+
+// Status is the last-run report of a job
+type Status struct {
+	Name         string     `json:"name"`
+	Schedule     string     `json:"schedule"`
+	Running      bool       `json:"running"`
+	LeaderOnly   bool       `json:"leaderOnly"`
+	Runs         int        `json:"runs"`
+	Failures     int        `json:"failures"`
+	Skipped      int        `json:"skipped"` // due while this instance was not the leader
+	LastStart    *time.Time `json:"lastStart,omitempty"`
+	LastDuration string     `json:"lastDuration,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	NextRun      time.Time  `json:"nextRun"`
+}
+
+type job struct {
+	name       string
+	schedule   Schedule
+	fn         Func
+	leaderOnly bool
+
+	mu        sync.Mutex
+	running   bool
+	runs      int
+	failures  int
+	streak    int // consecutive failures
+	skipped   int
+	lastStart time.Time
+	lastSkip  time.Time
+	lastDur   time.Duration
+	lastErr   error
+	next      time.Time
+	wake      chan struct{} // recompute next (trigger or reschedule)
+}
+
+// Scheduler owns the registered jobs. Register everything before Start.
+type Scheduler struct {
+	mu     sync.RWMutex
+	jobs   map[string]*job
+	ctx    context.Context // of the runs
+	leader func() bool
+
+	stopLoops  context.CancelFunc
+	cancelRuns context.CancelFunc
+	stopping   bool
+	running    sync.WaitGroup // runs in progress
+}
+
+// New creates an empty scheduler. leader reports whether this instance may
+// run leader-only jobs; nil means it always may.
+func New(leader func() bool) *Scheduler {
+	if leader == nil {
+		leader = func() bool { return true }
+	}
+	return &Scheduler{jobs: make(map[string]*job), ctx: context.Background(), leader: leader}
+}
+
+// Register adds a job that runs on every instance. Runs of the same job never
+// overlap: a run that is due while the previous one is still going is skipped.
+func (s *Scheduler) Register(name string, schedule Schedule, fn Func) {
+	s.add(&job{name: name, schedule: schedule, fn: fn})
+}
+
+// RegisterLeaderOnly adds a job that only the elected leader runs, for work
+// that must not be duplicated across instances (orders, notifications)
+func (s *Scheduler) RegisterLeaderOnly(name string, schedule Schedule, fn Func) {
+	s.add(&job{name: name, schedule: schedule, fn: fn, leaderOnly: true})
+}
+
+func (s *Scheduler) add(j *job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[j.name]; exists {
+		panic(fmt.Sprintf("scheduler: job %q registered twice", j.name))
+	}
+	j.wake = make(chan struct{}, 1)
+	s.jobs[j.name] = j
+}
+
+// IsLeader reports whether this instance currently runs leader-only jobs
+func (s *Scheduler) IsLeader() bool {
+	return s.leader()
+}
+
+// Start launches one loop per job; they stop when ctx is cancelled or on Stop
+func (s *Scheduler) Start(ctx context.Context) {
+	loopCtx, stopLoops := context.WithCancel(ctx)
+	runCtx, cancelRuns := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.ctx = runCtx
+	s.stopLoops = stopLoops
+	s.cancelRuns = cancelRuns
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+
+	for _, j := range jobs {
+		go s.loop(loopCtx, runCtx, j)
+	}
+}
+
+// Stop starts no more runs and waits for the running ones to finish, so a
+// shutdown doesn't cut off a cycle or a trade halfway. Runs still going when
+// ctx is done are cancelled and Stop returns ctx's error without waiting
+// for them.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopping = true
+	stopLoops, cancelRuns := s.stopLoops, s.cancelRuns
+	s.mu.Unlock()
+	if stopLoops == nil { // never started
+		return nil
+	}
+	stopLoops()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		cancelRuns()
+		return nil
+	case <-ctx.Done():
+		cancelRuns()
+		return ctx.Err()
+	}
+}
+
+// track counts a run about to start; false once the scheduler is stopping
+func (s *Scheduler) track() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopping {
+		return false
+	}
+	s.running.Add(1)
+	return true
+}
+
+// Trigger runs a job now, outside its schedule, without waiting for it
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.RLock()
+	j, ok := s.jobs[name]
+	ctx := s.ctx
+	s.mu.RUnlock()
+	if !ok {
+		return ErrUnknownJob
+	}
+	if j.leaderOnly && !s.leader() {
+		return ErrNotLeader
+	}
+	if !s.track() {
+		return ErrStopped
+	}
+	if !j.begin() {
+		s.running.Done()
+		return ErrJobRunning
+	}
+	go func() {
+		defer s.running.Done()
+		s.execute(ctx, j)
+		j.poke() // the next scheduled run counts from this one
+	}()
+	return nil
+}
+
+// Reschedule makes every job recompute its next run, e.g. after an interval
+// setting changed
+func (s *Scheduler) Reschedule() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, j := range s.jobs {
+		j.poke()
+	}
+}
+
+// Statuses reports every job, ordered by name
+func (s *Scheduler) Statuses() []Status {
+	s.mu.RLock()
+	out := make([]Status, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, j.status())
+	}
+	s.mu.RUnlock()
+	sort.Slice(out, func(i, k int) bool { return out[i].Name < out[k].Name })
+	return out
+}
+
+// loop runs j on its schedule until ctx is cancelled; the runs get runCtx
+func (s *Scheduler) loop(ctx, runCtx context.Context, j *job) {
+	for {
+		now := time.Now()
+		j.mu.Lock()
+		last := j.lastStart
+		if j.lastSkip.After(last) {
+			last = j.lastSkip
+		}
+		next := j.schedule.Next(last, now)
+		if next.Before(now) {
+			next = now
+		}
+		j.next = next
+		j.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-j.wake:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+
+		if j.leaderOnly && !s.leader() {
+			j.mu.Lock()
+			j.skipped++
+			j.lastSkip = time.Now()
+			j.mu.Unlock()
+			continue
+		}
+		if !s.track() {
+			return
+		}
+		if !j.begin() {
+			s.running.Done()
+			// A manual run is in progress; it pokes us when done
+			select {
+			case <-ctx.Done():
+				return
+			case <-j.wake:
+			}
+			continue
+		}
+		s.execute(runCtx, j)
+		s.running.Done()
+	}
+}
+
+// begin marks the job running; false if it already is
+func (j *job) begin() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.running {
+		return false
+	}
+	j.running = true
+	j.lastStart = time.Now()
+	return true
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job) {
+	start := time.Now()
+	err := safeRun(ctx, j.name, j.fn)
+	dur := time.Since(start)
+	if err != nil {
+		log.Printf("Job %s failed after %v: %v", j.name, dur.Round(time.Millisecond), err)
+	}
+
+	j.mu.Lock()
+	j.running = false
+	j.runs++
+	j.lastDur = dur
+	j.lastErr = err
+	if err != nil {
+		j.failures++
+		j.streak++
+	} else {
+		j.streak = 0
+	}
+	streak := j.streak
+	j.mu.Unlock()
+
+	if streak == failureReportThreshold {
+		reporting.Capture(ctx, reporting.Event{
+			Message: "job failing repeatedly",
+			Err:     err,
+			Tags:    map[string]string{"job": j.name},
+			Extra:   map[string]interface{}{"consecutiveFailures": streak},
+		})
+	}
+}
+
+// safeRun turns a panic into an error so one bad run doesn't kill the loop
+func safeRun(ctx context.Context, name string, fn Func) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			reporting.Capture(ctx, reporting.Event{
+				Level:   reporting.LevelFatal,
+				Message: "panic in job " + name,
+				Err:     err,
+				Tags:    map[string]string{"job": name},
+				Stack:   debug.Stack(),
+			})
+		}
+	}()
+	return fn(ctx)
+}
+
+func (j *job) poke() {
+	select {
+	case j.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (j *job) status() Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	st := Status{
+		Name:       j.name,
+		Schedule:   j.schedule.String(),
+		Running:    j.running,
+		LeaderOnly: j.leaderOnly,
+		Runs:       j.runs,
+		Failures:   j.failures,
+		Skipped:    j.skipped,
+		NextRun:    j.next,
+	}
+	if !j.lastStart.IsZero() {
+		last := j.lastStart
+		st.LastStart = &last
+	}
+	if j.runs > 0 {
+		st.LastDuration = j.lastDur.Round(time.Millisecond).String()
+	}
+	if j.lastErr != nil {
+		st.LastError = j.lastErr.Error()
+	}
+	return st
+}
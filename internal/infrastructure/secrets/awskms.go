@@ -0,0 +1,145 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// awsKMS decrypts secrets kept in the environment as base64 KMS ciphertext
+// (the CiphertextBlob of `aws kms encrypt`), so only encrypted values ever
+// sit in the dyno config. Credentials come from the standard AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN variables.
+type awsKMS struct {
+	region       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+func newAWSKMS(cfg Config) (*awsKMS, error) {
+	k := &awsKMS{
+		region:       cfg.AWSRegion,
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if k.region == "" {
+		return nil, errors.New("aws-kms provider requires AWS_REGION")
+	}
+	if k.accessKey == "" || k.secretKey == "" {
+		return nil, errors.New("aws-kms provider requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return k, nil
+}
+
+func (k *awsKMS) Get(ctx context.Context, name string) (string, error) {
+	ciphertext := strings.TrimSpace(os.Getenv(name))
+	if ciphertext == "" {
+		return "", nil
+	}
+	if _, err := base64.StdEncoding.DecodeString(ciphertext); err != nil {
+		return "", fmt.Errorf("aws kms: %s is not base64 ciphertext: %w", name, err)
+	}
+
+	payload, _ := json.Marshal(map[string]string{"CiphertextBlob": ciphertext})
+	host := "kms." + k.region + ".amazonaws.com"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+host+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Decrypt")
+	k.sign(req, host, payload, time.Now().UTC())
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("aws kms: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("aws kms: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("aws kms: decrypt %s: %s: %s", name, resp.Status, body)
+	}
+
+	var out struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("aws kms: decode %s: %w", name, err)
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(out.Plaintext)
+	if err != nil {
+		return "", fmt.Errorf("aws kms: decode %s: %w", name, err)
+	}
+	return string(plaintext), nil
+}
+
+// sign adds a Signature Version 4 Authorization header
+func (k *awsKMS) sign(req *http.Request, host string, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if k.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", k.sessionToken)
+	}
+
+	// Headers to sign, already in canonical (sorted) order
+	names := []string{"content-type", "host", "x-amz-date", "x-amz-security-token", "x-amz-target"}
+	var canonicalHeaders strings.Builder
+	var signedNames []string
+	for _, h := range names {
+		value := req.Header.Get(h)
+		if h == "host" {
+			value = host
+		}
+		if value == "" {
+			continue
+		}
+		canonicalHeaders.WriteString(h + ":" + value + "\n")
+		signedNames = append(signedNames, h)
+	}
+	signed := strings.Join(signedNames, ";")
+
+	canonicalRequest := strings.Join([]string{"POST", "/", "", canonicalHeaders.String(), signed, payloadHash}, "\n")
+	scope := day + "/" + k.region + "/kms/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+k.secretKey), day)
+	key = hmacSHA256(key, k.region)
+	key = hmacSHA256(key, "kms")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		k.accessKey, scope, signed, signature))
+}
+
+func (k *awsKMS) String() string { return "aws-kms" }
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// gcp reads the latest version of a Google Secret Manager secret whose id is
+// the environment variable name. Authentication uses Application Default
+// Credentials.
+type gcp struct {
+	project string
+	svc     *secretmanager.Service
+}
+
+func newGCP(ctx context.Context, cfg Config) (*gcp, error) {
+	if cfg.GCPProject == "" {
+		return nil, errors.New("gcp provider requires GCP_PROJECT")
+	}
+	svc, err := secretmanager.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gcp secret manager: %w", err)
+	}
+	return &gcp{project: cfg.GCPProject, svc: svc}, nil
+}
+
+func (g *gcp) Get(ctx context.Context, name string) (string, error) {
+	resource := fmt.Sprintf("projects/%s/secrets/%s/versions/latest", g.project, name)
+	resp, err := g.svc.Projects.Secrets.Versions.Access(resource).Context(ctx).Do()
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("gcp secret manager: %s: %w", name, err)
+	}
+	if resp.Payload == nil {
+		return "", nil
+	}
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("gcp secret manager: %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+func (g *gcp) String() string { return "gcp" }
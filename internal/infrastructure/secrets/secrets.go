@@ -0,0 +1,61 @@
+// Package secrets resolves sensitive settings (the credential encryption
+// key, Firebase credentials) from an external secret manager instead of raw
+// environment variables. Secrets are named after the environment variable
+// they replace, e.g. API_ENCRYPTION_KEY.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Provider looks up one secret by name. An empty value with a nil error
+// means the secret is not set.
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+	String() string
+}
+
+// Config picks and configures the provider
+type Config struct {
+	// Provider is env, vault, gcp or aws-kms
+	Provider   string
+	VaultAddr  string
+	VaultToken string
+	VaultPath  string // KV v2 data path, e.g. secret/data/screener-backend
+	GCPProject string
+	AWSRegion  string
+}
+
+// requestTimeout bounds one call to a remote secret manager
+const requestTimeout = 10 * time.Second
+
+// New creates the provider named in cfg
+func New(ctx context.Context, cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case "", "env":
+		return Env{}, nil
+	case "vault":
+		return newVault(cfg)
+	case "gcp":
+		return newGCP(ctx, cfg)
+	case "aws-kms":
+		return newAWSKMS(cfg)
+	default:
+		return nil, fmt.Errorf("unknown secret provider %q", cfg.Provider)
+	}
+}
+
+// Env reads secrets straight from the environment
+type Env struct{}
+
+func (Env) Get(_ context.Context, name string) (string, error) {
+	return os.Getenv(name), nil
+}
+
+func (Env) String() string { return "env" }
+
+var httpClient = &http.Client{Timeout: requestTimeout}
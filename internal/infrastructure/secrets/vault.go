@@ -0,0 +1,70 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// vault reads the fields of one HashiCorp Vault KV v2 secret; each field is
+// named after the environment variable it replaces
+type vault struct {
+	addr  string
+	token string
+	path  string
+}
+
+func newVault(cfg Config) (*vault, error) {
+	if cfg.VaultAddr == "" || cfg.VaultToken == "" {
+		return nil, errors.New("vault provider requires VAULT_ADDR and VAULT_TOKEN")
+	}
+	return &vault{
+		addr:  strings.TrimRight(cfg.VaultAddr, "/"),
+		token: cfg.VaultToken,
+		path:  strings.Trim(cfg.VaultPath, "/"),
+	}, nil
+}
+
+func (v *vault) Get(ctx context.Context, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.addr+"/v1/"+v.path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault: %s returned %s", v.path, resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("vault: decode %s: %w", v.path, err)
+	}
+	switch value := body.Data.Data[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	default:
+		// Structured values (e.g. Firebase credentials pasted as JSON)
+		raw, err := json.Marshal(value)
+		return string(raw), err
+	}
+}
+
+func (v *vault) String() string { return "vault" }
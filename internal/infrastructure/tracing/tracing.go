@@ -0,0 +1,126 @@
+// Package tracing wires OpenTelemetry tracing. Instrumented code uses the
+// otel API (Tracer below); when an OTLP endpoint is configured, Init installs
+// the otel SDK's tracer provider, batching finished spans to the OTLP/HTTP
+// exporter. Without an endpoint the otel no-op provider stays in place.
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config selects the exporter
+type Config struct {
+	Endpoint    string  // OTLP/HTTP base URL, e.g. http://localhost:4318
+	Headers     string  // "key=value,key2=value2", sent with every export
+	ServiceName string  // service.name resource attribute
+	SampleRatio float64 // fraction of root spans kept, 0..1
+}
+
+// Init installs the exporting tracer provider and returns its shutdown,
+// which flushes pending spans. It is a no-op when cfg.Endpoint is empty.
+// Root spans are sampled at cfg.SampleRatio; child spans follow their parent.
+func Init(cfg Config) (func(context.Context) error, error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(tracesURL(cfg.Endpoint)),
+		otlptracehttp.WithHeaders(parseHeaders(cfg.Headers)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns a named tracer from the global provider
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// EndSpan records err (if any) on span and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Stages times consecutive phases of one unit of work as sibling child spans
+// of ctx: starting a stage ends the previous one.
+type Stages struct {
+	tracer trace.Tracer
+	parent context.Context
+	ctx    context.Context
+	span   trace.Span
+}
+
+func NewStages(ctx context.Context, tracer trace.Tracer) *Stages {
+	return &Stages{tracer: tracer, parent: ctx, ctx: ctx}
+}
+
+// Start ends the running stage and begins the named one
+func (s *Stages) Start(name string, attrs ...attribute.KeyValue) {
+	s.End()
+	s.ctx, s.span = s.tracer.Start(s.parent, name, trace.WithAttributes(attrs...))
+}
+
+// Context is the running stage's context (the parent's between stages)
+func (s *Stages) Context() context.Context {
+	return s.ctx
+}
+
+// End ends the running stage, if any
+func (s *Stages) End() {
+	if s.span != nil {
+		s.span.End()
+		s.span = nil
+		s.ctx = s.parent
+	}
+}
+
+// tracesURL is the OTLP/HTTP traces URL of a collector's base URL
+func tracesURL(endpoint string) string {
+	url := strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/traces") {
+		url += "/v1/traces"
+	}
+	return url
+}
+
+func parseHeaders(raw string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(k) != "" {
+			headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return headers
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"screener-backend/internal/domain"
 	"sync"
@@ -13,6 +14,8 @@ type InMemoryAutoScalpRepository struct {
 	entries map[string]*domain.AutoScalpEntry // Active entries
 	history []*domain.AutoScalpEntry          // Closed entries
 
+	settings map[string]*domain.AutoScalpSettings // key: userID
+
 	lastEmergencyStopUser   string
 	lastEmergencyStopAt     time.Time
 	lastEmergencyStopReason string
@@ -23,22 +26,39 @@ func NewInMemoryAutoScalpRepository() *InMemoryAutoScalpRepository {
 	return &InMemoryAutoScalpRepository{
 		entries: make(map[string]*domain.AutoScalpEntry),
 		history: make([]*domain.AutoScalpEntry, 0),
+
+		settings: make(map[string]*domain.AutoScalpSettings),
 	}
 }
 
-func (r *InMemoryAutoScalpRepository) CreateEntry(entry *domain.AutoScalpEntry) error {
+func (r *InMemoryAutoScalpRepository) CreateEntry(_ context.Context, entry *domain.AutoScalpEntry) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.entries[entry.ID]; exists {
-		return fmt.Errorf("entry with ID %s already exists", entry.ID)
+		return domain.Conflict("AUTOSCALP_ENTRY_EXISTS", fmt.Sprintf("entry with ID %s already exists", entry.ID))
 	}
 
 	r.entries[entry.ID] = entry
 	return nil
 }
 
-func (r *InMemoryAutoScalpRepository) GetActiveEntries() []*domain.AutoScalpEntry {
+// GetActiveEntries returns the user's active entries
+func (r *InMemoryAutoScalpRepository) GetActiveEntries(_ context.Context, userID string) []*domain.AutoScalpEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]*domain.AutoScalpEntry, 0)
+	for _, entry := range r.entries {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// GetAllActiveEntries returns the active entries of every user
+func (r *InMemoryAutoScalpRepository) GetAllActiveEntries(_ context.Context) []*domain.AutoScalpEntry {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -49,24 +69,38 @@ func (r *InMemoryAutoScalpRepository) GetActiveEntries() []*domain.AutoScalpEntr
 	return entries
 }
 
-func (r *InMemoryAutoScalpRepository) GetEntryByID(id string) (*domain.AutoScalpEntry, error) {
+func (r *InMemoryAutoScalpRepository) GetEntryByID(_ context.Context, id string) (*domain.AutoScalpEntry, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	entry, exists := r.entries[id]
-	if !exists {
-		return nil, fmt.Errorf("entry with ID %s not found", id)
+	if entry, exists := r.entries[id]; exists {
+		return entry, nil
+	}
+	for _, entry := range r.history {
+		if entry.ID == id {
+			return entry, nil
+		}
 	}
-	return entry, nil
+	return nil, autoScalpNotFound(id)
 }
 
-func (r *InMemoryAutoScalpRepository) UpdateEntry(entry *domain.AutoScalpEntry) error {
+func (r *InMemoryAutoScalpRepository) UpdateEntry(_ context.Context, entry *domain.AutoScalpEntry) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.entries[entry.ID]; !exists {
-		return fmt.Errorf("entry with ID %s not found", entry.ID)
+	stored, exists := r.entries[entry.ID]
+	if !exists {
+		for i, closed := range r.history {
+			if closed.ID == entry.ID {
+				entry.TradeEntryID = closed.TradeEntryID
+				r.history[i] = entry
+				return nil
+			}
+		}
+		return autoScalpNotFound(entry.ID)
 	}
+	// The journal link only changes through SetTradeLink
+	entry.TradeEntryID = stored.TradeEntryID
 
 	// If closing, move to history
 	if entry.Status == "CLOSED" {
@@ -79,7 +113,44 @@ func (r *InMemoryAutoScalpRepository) UpdateEntry(entry *domain.AutoScalpEntry)
 	return nil
 }
 
-func (r *InMemoryAutoScalpRepository) GetHistory(fromTime time.Time) []*domain.AutoScalpEntry {
+// SetTradeLink sets the journal entry managing the entry, active or closed
+func (r *InMemoryAutoScalpRepository) SetTradeLink(_ context.Context, id, tradeEntryID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, exists := r.entries[id]; exists {
+		updated := *stored
+		updated.TradeEntryID = tradeEntryID
+		r.entries[id] = &updated
+		return nil
+	}
+	for i, closed := range r.history {
+		if closed.ID == id {
+			updated := *closed
+			updated.TradeEntryID = tradeEntryID
+			r.history[i] = &updated
+			return nil
+		}
+	}
+	return autoScalpNotFound(id)
+}
+
+// GetHistory returns the user's entries closed since fromTime
+func (r *InMemoryAutoScalpRepository) GetHistory(_ context.Context, userID string, fromTime time.Time) []*domain.AutoScalpEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filtered := make([]*domain.AutoScalpEntry, 0)
+	for _, entry := range r.history {
+		if entry.UserID == userID && entry.ExitTime != nil && entry.ExitTime.After(fromTime) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// GetAllHistory returns every user's entries closed since fromTime
+func (r *InMemoryAutoScalpRepository) GetAllHistory(_ context.Context, fromTime time.Time) []*domain.AutoScalpEntry {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -92,26 +163,26 @@ func (r *InMemoryAutoScalpRepository) GetHistory(fromTime time.Time) []*domain.A
 	return filtered
 }
 
-func (r *InMemoryAutoScalpRepository) DeleteEntry(id string) error {
+func (r *InMemoryAutoScalpRepository) DeleteEntry(_ context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.entries[id]; !exists {
-		return fmt.Errorf("entry with ID %s not found", id)
+		return autoScalpNotFound(id)
 	}
 
 	delete(r.entries, id)
 	return nil
 }
 
-// UpdateOrAttachBinanceOrders best-effort attaches Binance order metadata to the most recent active entry for a symbol.
-func (r *InMemoryAutoScalpRepository) UpdateOrAttachBinanceOrders(symbol string, entryOrderID int64, slOrderID int64, qty float64, leverage int, filledPrice float64) error {
+// UpdateOrAttachBinanceOrders best-effort attaches Binance order metadata to the user's most recent active entry for a symbol.
+func (r *InMemoryAutoScalpRepository) UpdateOrAttachBinanceOrders(_ context.Context, userID string, symbol string, entryOrderID int64, slOrderID int64, tpOrderID int64, qty float64, leverage int, filledPrice float64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	var selected *domain.AutoScalpEntry
 	for _, entry := range r.entries {
-		if entry.Symbol != symbol {
+		if entry.Symbol != symbol || entry.UserID != userID {
 			continue
 		}
 		if entry.Status != "ACTIVE" {
@@ -134,12 +205,15 @@ func (r *InMemoryAutoScalpRepository) UpdateOrAttachBinanceOrders(symbol string,
 	}
 	selected.BinanceOrderID = &entryOrderID
 	selected.BinanceSLOrderID = &slOrderID
+	if tpOrderID != 0 {
+		selected.BinanceTPOrderID = &tpOrderID
+	}
 
 	// Map holds pointers; entry updated in-place.
 	return nil
 }
 
-func (r *InMemoryAutoScalpRepository) RecordEmergencyStop(userID string, at time.Time, reason string) error {
+func (r *InMemoryAutoScalpRepository) RecordEmergencyStop(_ context.Context, userID string, at time.Time, reason string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -148,3 +222,65 @@ func (r *InMemoryAutoScalpRepository) RecordEmergencyStop(userID string, at time
 	r.lastEmergencyStopReason = reason
 	return nil
 }
+
+func (r *InMemoryAutoScalpRepository) PruneHistory(_ context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.history[:0]
+	for _, entry := range r.history {
+		if entry.TradeEntryID == "" && entry.ExitTime != nil && entry.ExitTime.Before(before) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	r.history = kept
+	return nil
+}
+
+func (r *InMemoryAutoScalpRepository) PruneEmergencyStops(_ context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.lastEmergencyStopAt.IsZero() && r.lastEmergencyStopAt.Before(before) {
+		r.lastEmergencyStopUser = ""
+		r.lastEmergencyStopAt = time.Time{}
+		r.lastEmergencyStopReason = ""
+	}
+	return nil
+}
+
+func (r *InMemoryAutoScalpRepository) GetSettings(_ context.Context, userID string) (*domain.AutoScalpSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settings, exists := r.settings[userID]
+	if !exists {
+		return nil, errNoAutoScalpSettings
+	}
+	copied := *settings
+	return &copied, nil
+}
+
+func (r *InMemoryAutoScalpRepository) SaveSettings(_ context.Context, settings *domain.AutoScalpSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *settings
+	r.settings[settings.UserID] = &copied
+	return nil
+}
+
+func (r *InMemoryAutoScalpRepository) ListEnabledSettings(_ context.Context) ([]*domain.AutoScalpSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	enabled := make([]*domain.AutoScalpSettings, 0)
+	for _, settings := range r.settings {
+		if settings.Enabled {
+			copied := *settings
+			enabled = append(enabled, &copied)
+		}
+	}
+	return enabled, nil
+}
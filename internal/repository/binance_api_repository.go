@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -8,6 +9,7 @@ import (
 	"errors"
 	"io"
 	"screener-backend/internal/domain"
+	"sort"
 	"sync"
 	"time"
 )
@@ -41,7 +43,7 @@ func NewBinanceAPIRepository(encryptionKey string) *BinanceAPIRepository {
 }
 
 // SaveCredentials saves or updates API credentials
-func (r *BinanceAPIRepository) SaveCredentials(cred *domain.BinanceAPICredentials) error {
+func (r *BinanceAPIRepository) SaveCredentials(_ context.Context, cred *domain.BinanceAPICredentials) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -54,6 +56,14 @@ func (r *BinanceAPIRepository) SaveCredentials(cred *domain.BinanceAPICredential
 	// Create a copy to store
 	stored := *cred
 	stored.SecretKey = encryptedSecret
+
+	if cred.SubAccountSecretKey != "" {
+		encryptedSubSecret, err := r.encrypt(cred.SubAccountSecretKey)
+		if err != nil {
+			return err
+		}
+		stored.SubAccountSecretKey = encryptedSubSecret
+	}
 	stored.UpdatedAt = time.Now()
 
 	if stored.CreatedAt.IsZero() {
@@ -65,13 +75,13 @@ func (r *BinanceAPIRepository) SaveCredentials(cred *domain.BinanceAPICredential
 }
 
 // GetCredentials retrieves credentials with decrypted secret
-func (r *BinanceAPIRepository) GetCredentials(userID string) (*domain.BinanceAPICredentials, error) {
+func (r *BinanceAPIRepository) GetCredentials(_ context.Context, userID string) (*domain.BinanceAPICredentials, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	cred, exists := r.credentials[userID]
 	if !exists {
-		return nil, errors.New("credentials not found")
+		return nil, errCredentialsNotFound
 	}
 
 	// Decrypt the secret key
@@ -83,11 +93,19 @@ func (r *BinanceAPIRepository) GetCredentials(userID string) (*domain.BinanceAPI
 	// Return a copy with decrypted secret
 	result := *cred
 	result.SecretKey = decryptedSecret
+
+	if cred.SubAccountSecretKey != "" {
+		decryptedSubSecret, err := r.decrypt(cred.SubAccountSecretKey)
+		if err != nil {
+			return nil, err
+		}
+		result.SubAccountSecretKey = decryptedSubSecret
+	}
 	return &result, nil
 }
 
 // DeleteCredentials removes credentials
-func (r *BinanceAPIRepository) DeleteCredentials(userID string) error {
+func (r *BinanceAPIRepository) DeleteCredentials(_ context.Context, userID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -95,8 +113,32 @@ func (r *BinanceAPIRepository) DeleteCredentials(userID string) error {
 	return nil
 }
 
+// ListCredentials returns all credentials with decrypted secrets
+func (r *BinanceAPIRepository) ListCredentials(ctx context.Context) ([]*domain.BinanceAPICredentials, error) {
+	r.mu.RLock()
+	userIDs := make([]string, 0, len(r.credentials))
+	for userID := range r.credentials {
+		userIDs = append(userIDs, userID)
+	}
+	r.mu.RUnlock()
+	sort.Strings(userIDs)
+
+	creds := make([]*domain.BinanceAPICredentials, 0, len(userIDs))
+	for _, userID := range userIDs {
+		cred, err := r.GetCredentials(ctx, userID)
+		if errors.Is(err, errCredentialsNotFound) {
+			continue // deleted meanwhile
+		}
+		if err != nil {
+			return nil, err
+		}
+		creds = append(creds, cred)
+	}
+	return creds, nil
+}
+
 // SaveTradingConfig saves trading configuration
-func (r *BinanceAPIRepository) SaveTradingConfig(config *domain.BinanceTradingConfig) error {
+func (r *BinanceAPIRepository) SaveTradingConfig(_ context.Context, config *domain.BinanceTradingConfig) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -105,7 +147,7 @@ func (r *BinanceAPIRepository) SaveTradingConfig(config *domain.BinanceTradingCo
 }
 
 // GetTradingConfig retrieves trading configuration
-func (r *BinanceAPIRepository) GetTradingConfig(userID string) (*domain.BinanceTradingConfig, error) {
+func (r *BinanceAPIRepository) GetTradingConfig(_ context.Context, userID string) (*domain.BinanceTradingConfig, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -132,13 +174,13 @@ func (r *BinanceAPIRepository) GetTradingConfig(userID string) (*domain.BinanceT
 }
 
 // UpdateLastTested updates the last tested timestamp
-func (r *BinanceAPIRepository) UpdateLastTested(userID string) error {
+func (r *BinanceAPIRepository) UpdateLastTested(_ context.Context, userID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	cred, exists := r.credentials[userID]
 	if !exists {
-		return errors.New("credentials not found")
+		return errCredentialsNotFound
 	}
 
 	cred.LastTested = time.Now()
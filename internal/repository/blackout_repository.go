@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryBlackoutRepository keeps blackout windows in memory
+type InMemoryBlackoutRepository struct {
+	mu      sync.RWMutex
+	nextID  int64
+	windows map[int64]*domain.BlackoutWindow
+}
+
+func NewInMemoryBlackoutRepository() *InMemoryBlackoutRepository {
+	return &InMemoryBlackoutRepository{windows: make(map[int64]*domain.BlackoutWindow)}
+}
+
+func (r *InMemoryBlackoutRepository) SaveBlackout(_ context.Context, w *domain.BlackoutWindow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	w.ID = r.nextID
+	stored := *w
+	r.windows[w.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryBlackoutRepository) ListBlackouts(_ context.Context, endsAfter time.Time) ([]*domain.BlackoutWindow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.BlackoutWindow, 0)
+	for _, w := range r.windows {
+		if w.End.After(endsAfter) {
+			copied := *w
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Start.Equal(result[j].Start) {
+			return result[i].Start.Before(result[j].Start)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *InMemoryBlackoutRepository) DeleteBlackout(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.windows[id]; !ok {
+		return errBlackoutNotFound
+	}
+	delete(r.windows, id)
+	return nil
+}
+
+// compile-time check
+var _ domain.BlackoutRepository = (*InMemoryBlackoutRepository)(nil)
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sync"
+)
+
+// InMemoryConfigHistoryRepository keeps settings changes in memory
+type InMemoryConfigHistoryRepository struct {
+	mu      sync.RWMutex
+	changes []*domain.ConfigChange // oldest first; IDs are index+1
+}
+
+func NewInMemoryConfigHistoryRepository() *InMemoryConfigHistoryRepository {
+	return &InMemoryConfigHistoryRepository{}
+}
+
+func (r *InMemoryConfigHistoryRepository) AppendConfigChange(_ context.Context, change *domain.ConfigChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	change.ID = int64(len(r.changes) + 1)
+	stored := *change
+	r.changes = append(r.changes, &stored)
+	return nil
+}
+
+func (r *InMemoryConfigHistoryRepository) ListConfigChanges(_ context.Context, limit int) ([]*domain.ConfigChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.ConfigChange, 0, len(r.changes))
+	for i := len(r.changes) - 1; i >= 0; i-- {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		c := *r.changes[i]
+		result = append(result, &c)
+	}
+	return result, nil
+}
+
+func (r *InMemoryConfigHistoryRepository) GetConfigChange(_ context.Context, id int64) (*domain.ConfigChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if id < 1 || id > int64(len(r.changes)) {
+		return nil, errConfigChangeNotFound
+	}
+	c := *r.changes[id-1]
+	return &c, nil
+}
+
+// compile-time check
+var _ domain.ConfigHistoryRepository = (*InMemoryConfigHistoryRepository)(nil)
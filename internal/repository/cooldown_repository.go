@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sync"
+	"time"
+)
+
+// InMemoryCooldownStore keeps notification cooldowns in process memory
+type InMemoryCooldownStore struct {
+	mu        sync.RWMutex
+	cooldowns map[string]time.Time
+}
+
+// NewInMemoryCooldownStore creates an empty in-memory cooldown store
+func NewInMemoryCooldownStore() *InMemoryCooldownStore {
+	return &InMemoryCooldownStore{cooldowns: make(map[string]time.Time)}
+}
+
+func (s *InMemoryCooldownStore) LoadCooldowns(_ context.Context, since time.Time) (map[string]time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]time.Time)
+	for key, at := range s.cooldowns {
+		if !at.Before(since) {
+			result[key] = at
+		}
+	}
+	return result, nil
+}
+
+func (s *InMemoryCooldownStore) SaveCooldown(_ context.Context, key string, notifiedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cooldowns[key] = notifiedAt
+	return nil
+}
+
+func (s *InMemoryCooldownStore) PruneCooldowns(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, at := range s.cooldowns {
+		if at.Before(before) {
+			delete(s.cooldowns, key)
+		}
+	}
+	return nil
+}
+
+// compile-time check
+var _ domain.CooldownStore = (*InMemoryCooldownStore)(nil)
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"slices"
+	"sync"
+	"time"
+)
+
+// InMemoryCycleRecordingRepository keeps recorded cycles in memory
+type InMemoryCycleRecordingRepository struct {
+	mu         sync.RWMutex
+	nextID     int64
+	recordings []*domain.CycleRecording // oldest first
+}
+
+func NewInMemoryCycleRecordingRepository() *InMemoryCycleRecordingRepository {
+	return &InMemoryCycleRecordingRepository{}
+}
+
+func (r *InMemoryCycleRecordingRepository) SaveCycleRecording(_ context.Context, rec *domain.CycleRecording, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	rec.ID = r.nextID
+	stored := *rec
+	r.recordings = append(r.recordings, &stored)
+	if keep > 0 && len(r.recordings) > keep {
+		r.recordings = append([]*domain.CycleRecording(nil), r.recordings[len(r.recordings)-keep:]...)
+	}
+	return nil
+}
+
+func (r *InMemoryCycleRecordingRepository) ListCycleRecordings(_ context.Context, symbol string, from, to time.Time, limit int) ([]*domain.CycleRecording, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.CycleRecording, 0)
+	for i := len(r.recordings) - 1; i >= 0; i-- {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		rec := r.recordings[i]
+		if rec.StartedAt.Before(from) || rec.StartedAt.After(to) {
+			continue
+		}
+		if symbol != "" && !slices.Contains(rec.Symbols, symbol) {
+			continue
+		}
+		copied := *rec
+		copied.Data = nil
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (r *InMemoryCycleRecordingRepository) GetCycleRecording(_ context.Context, id int64) (*domain.CycleRecording, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, rec := range r.recordings {
+		if rec.ID == id {
+			copied := *rec
+			return &copied, nil
+		}
+	}
+	return nil, errRecordingNotFound
+}
+
+// compile-time check
+var _ domain.CycleRecordingRepository = (*InMemoryCycleRecordingRepository)(nil)
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sort"
+	"sync"
+)
+
+// InMemoryDailySummaryRepository keeps daily journal summaries in memory
+type InMemoryDailySummaryRepository struct {
+	mu        sync.RWMutex
+	summaries map[string]map[string]*domain.DailyTradeSummary // userID -> date -> summary
+}
+
+func NewInMemoryDailySummaryRepository() *InMemoryDailySummaryRepository {
+	return &InMemoryDailySummaryRepository{summaries: make(map[string]map[string]*domain.DailyTradeSummary)}
+}
+
+func (r *InMemoryDailySummaryRepository) SaveSummary(_ context.Context, summary *domain.DailyTradeSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byDate, ok := r.summaries[summary.UserID]
+	if !ok {
+		byDate = make(map[string]*domain.DailyTradeSummary)
+		r.summaries[summary.UserID] = byDate
+	}
+	byDate[summary.Date] = summary
+	return nil
+}
+
+func (r *InMemoryDailySummaryRepository) GetSummaries(_ context.Context, userID string, limit int) []*domain.DailyTradeSummary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.DailyTradeSummary, 0)
+	for _, s := range r.summaries[userID] {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date > result[j].Date })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// compile-time check
+var _ domain.DailySummaryRepository = (*InMemoryDailySummaryRepository)(nil)
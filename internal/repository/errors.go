@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"fmt"
+	"screener-backend/internal/domain"
+)
+
+// Errors shared by the in-memory and Postgres implementations so both report
+// the same codes.
+var (
+	errTradeNotFound         = domain.NotFound("TRADE_NOT_FOUND", "entry not found")
+	errArchivedTradeNotFound = domain.NotFound("ARCHIVED_TRADE_NOT_FOUND", "archived entry not found")
+	errCredentialsNotFound   = domain.NotFound("CREDENTIALS_NOT_FOUND", "credentials not found")
+	errConfigChangeNotFound  = domain.NotFound("CONFIG_CHANGE_NOT_FOUND", "config change not found")
+	errSignalNotFound        = domain.NotFound("SIGNAL_NOT_FOUND", "signal not found")
+	errScoringNotFound       = domain.NotFound("SCORING_VERSION_NOT_FOUND", "scoring version not found")
+	errBlackoutNotFound      = domain.NotFound("BLACKOUT_NOT_FOUND", "blackout window not found")
+	errRecordingNotFound     = domain.NotFound("CYCLE_RECORDING_NOT_FOUND", "cycle recording not found")
+	errWatchlistItemNotFound = domain.NotFound("WATCHLIST_ITEM_NOT_FOUND", "symbol not on the watchlist")
+	errNoScreenerConfig      = domain.NotFound("SCREENER_CONFIG_NOT_FOUND", "no screener config saved")
+	errNoAutoScalpSettings   = domain.NotFound("AUTOSCALP_SETTINGS_NOT_FOUND", "no auto scalp settings saved")
+	errNoScoringProfile      = domain.NotFound("SCORING_PROFILE_NOT_FOUND", "no scoring profile saved")
+	errUserNotFound          = domain.NotFound("USER_NOT_FOUND", "user not found")
+	errEmailTaken            = domain.Conflict("EMAIL_TAKEN", "email is already registered")
+)
+
+func autoScalpNotFound(id string) error {
+	return domain.NotFound("AUTOSCALP_ENTRY_NOT_FOUND", fmt.Sprintf("entry with ID %s not found", id))
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sync"
+	"time"
+)
+
+type idempotencyRecord struct {
+	resp       *domain.IdempotentResponse // nil while in flight
+	reservedAt time.Time
+}
+
+// InMemoryIdempotencyRepository keeps idempotency keys in memory
+type InMemoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]*idempotencyRecord
+}
+
+func NewInMemoryIdempotencyRepository() *InMemoryIdempotencyRepository {
+	return &InMemoryIdempotencyRepository{records: make(map[string]*idempotencyRecord)}
+}
+
+func (r *InMemoryIdempotencyRepository) Reserve(_ context.Context, scope, key string, ttl, lease time.Duration) (*domain.IdempotentResponse, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for k, rec := range r.records {
+		if now.Sub(rec.reservedAt) > ttl {
+			delete(r.records, k)
+		}
+	}
+
+	id := scope + "|" + key
+	if rec, ok := r.records[id]; ok && (rec.resp != nil || now.Sub(rec.reservedAt) <= lease) {
+		return rec.resp, false, nil
+	}
+	r.records[id] = &idempotencyRecord{reservedAt: now}
+	return nil, true, nil
+}
+
+func (r *InMemoryIdempotencyRepository) Complete(_ context.Context, scope, key string, resp *domain.IdempotentResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rec, ok := r.records[scope+"|"+key]; ok {
+		rec.resp = resp
+	}
+	return nil
+}
+
+func (r *InMemoryIdempotencyRepository) Release(_ context.Context, scope, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, scope+"|"+key)
+	return nil
+}
+
+// compile-time check
+var _ domain.IdempotencyRepository = (*InMemoryIdempotencyRepository)(nil)
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	maxArchivedCandles   = 1500  // per symbol/interval
+	maxArchivedSnapshots = 20000 // across all symbols
+	maxScreenerSnapshots = 50000 // across all symbols
+)
+
+// InMemoryMarketArchiveRepository keeps a bounded recent archive in memory
+type InMemoryMarketArchiveRepository struct {
+	mu        sync.RWMutex
+	candles   map[string][]domain.Candle // symbol|interval -> candles, oldest first
+	snapshots []domain.CoinSnapshot      // oldest first
+	screener  []domain.ScreenerSnapshot  // oldest first
+}
+
+func NewInMemoryMarketArchiveRepository() *InMemoryMarketArchiveRepository {
+	return &InMemoryMarketArchiveRepository{candles: make(map[string][]domain.Candle)}
+}
+
+func (r *InMemoryMarketArchiveRepository) SaveCandles(_ context.Context, symbol, interval string, candles []domain.Candle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := symbol + "|" + interval
+	byTime := make(map[int64]domain.Candle, len(r.candles[key])+len(candles))
+	for _, c := range r.candles[key] {
+		byTime[c.OpenTime.UnixMilli()] = c
+	}
+	for _, c := range candles {
+		byTime[c.OpenTime.UnixMilli()] = c
+	}
+
+	merged := make([]domain.Candle, 0, len(byTime))
+	for _, c := range byTime {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].OpenTime.Before(merged[j].OpenTime) })
+	if len(merged) > maxArchivedCandles {
+		merged = merged[len(merged)-maxArchivedCandles:]
+	}
+	r.candles[key] = merged
+	return nil
+}
+
+func (r *InMemoryMarketArchiveRepository) GetCandles(_ context.Context, symbol, interval string, from, to time.Time) ([]domain.Candle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.Candle, 0)
+	for _, c := range r.candles[symbol+"|"+interval] {
+		if !c.OpenTime.Before(from) && !c.OpenTime.After(to) {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryMarketArchiveRepository) SaveSnapshots(_ context.Context, takenAt time.Time, coins []domain.CoinData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range coins {
+		r.snapshots = append(r.snapshots, domain.CoinSnapshot{TakenAt: takenAt, Coin: c})
+	}
+	if over := len(r.snapshots) - maxArchivedSnapshots; over > 0 {
+		r.snapshots = append([]domain.CoinSnapshot(nil), r.snapshots[over:]...)
+	}
+	return nil
+}
+
+func (r *InMemoryMarketArchiveRepository) GetSnapshots(_ context.Context, symbol string, from, to time.Time, limit int) ([]domain.CoinSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.CoinSnapshot, 0)
+	for i := len(r.snapshots) - 1; i >= 0 && (limit <= 0 || len(result) < limit); i-- {
+		s := r.snapshots[i]
+		if s.Coin.Symbol == symbol && !s.TakenAt.Before(from) && !s.TakenAt.After(to) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryMarketArchiveRepository) ListSnapshots(_ context.Context, symbol string, from, to time.Time, limit int) ([]domain.CoinSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.CoinSnapshot, 0)
+	for _, s := range r.snapshots {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		if (symbol == "" || s.Coin.Symbol == symbol) && !s.TakenAt.Before(from) && !s.TakenAt.After(to) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryMarketArchiveRepository) PruneCandles(_ context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, candles := range r.candles {
+		i := sort.Search(len(candles), func(i int) bool { return !candles[i].OpenTime.Before(before) })
+		if i == len(candles) {
+			delete(r.candles, key)
+		} else if i > 0 {
+			r.candles[key] = append([]domain.Candle(nil), candles[i:]...)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryMarketArchiveRepository) PruneSnapshots(_ context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := sort.Search(len(r.snapshots), func(i int) bool { return !r.snapshots[i].TakenAt.Before(before) })
+	if i > 0 {
+		r.snapshots = append([]domain.CoinSnapshot(nil), r.snapshots[i:]...)
+	}
+	return nil
+}
+
+func (r *InMemoryMarketArchiveRepository) SaveScreenerSnapshots(_ context.Context, snapshots []domain.ScreenerSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.screener = append(r.screener, snapshots...)
+	if over := len(r.screener) - maxScreenerSnapshots; over > 0 {
+		r.screener = append([]domain.ScreenerSnapshot(nil), r.screener[over:]...)
+	}
+	return nil
+}
+
+func (r *InMemoryMarketArchiveRepository) GetScreenerHistory(_ context.Context, symbol string, from, to time.Time, limit int) ([]domain.ScreenerSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.ScreenerSnapshot, 0)
+	for i := len(r.screener) - 1; i >= 0 && (limit <= 0 || len(result) < limit); i-- {
+		s := r.screener[i]
+		if s.Symbol == symbol && !s.TakenAt.Before(from) && !s.TakenAt.After(to) {
+			result = append(result, s)
+		}
+	}
+	slices.Reverse(result)
+	return result, nil
+}
+
+func (r *InMemoryMarketArchiveRepository) PruneScreenerSnapshots(_ context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := sort.Search(len(r.screener), func(i int) bool { return !r.screener[i].TakenAt.Before(before) })
+	if i > 0 {
+		r.screener = append([]domain.ScreenerSnapshot(nil), r.screener[i:]...)
+	}
+	return nil
+}
+
+// compile-time check
+var _ domain.MarketArchiveRepository = (*InMemoryMarketArchiveRepository)(nil)
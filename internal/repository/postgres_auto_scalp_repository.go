@@ -3,10 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
 	"screener-backend/internal/domain"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -21,19 +21,20 @@ func NewPostgresAutoScalpRepository(pool *pgxpool.Pool) *PostgresAutoScalpReposi
 	return &PostgresAutoScalpRepository{pool: pool}
 }
 
-func (r *PostgresAutoScalpRepository) CreateEntry(entry *domain.AutoScalpEntry) error {
+func (r *PostgresAutoScalpRepository) CreateEntry(ctx context.Context, entry *domain.AutoScalpEntry) error {
 	if entry == nil {
 		return errors.New("nil entry")
 	}
 
-	_, err := r.pool.Exec(context.Background(), `
+	_, err := r.pool.Exec(ctx, `
 		insert into autoscalp_entries(
 			id, symbol, entry_price, stop_loss, entry_time,
 			exit_price, exit_time, exit_reason,
 			profit_loss, profit_loss_pct, duration_seconds,
 			status, entry_score, highest_price, trailing_stop_pct,
-			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage
-		) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20)
+			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage,
+			trade_entry_id, user_id, binance_tp_order_id
+		) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23)
 	`,
 		entry.ID,
 		entry.Symbol,
@@ -55,61 +56,69 @@ func (r *PostgresAutoScalpRepository) CreateEntry(entry *domain.AutoScalpEntry)
 		nullableInt64(entry.BinanceSLOrderID),
 		entry.Quantity,
 		entry.Leverage,
+		entry.TradeEntryID,
+		entry.UserID,
+		nullableInt64(entry.BinanceTPOrderID),
 	)
 	return err
 }
 
-func (r *PostgresAutoScalpRepository) GetActiveEntries() []*domain.AutoScalpEntry {
-	rows, err := r.pool.Query(context.Background(), `
+func (r *PostgresAutoScalpRepository) GetActiveEntries(ctx context.Context, userID string) []*domain.AutoScalpEntry {
+	return r.queryEntries(ctx, `
 		select id, symbol, entry_price, stop_loss, entry_time,
 			exit_price, exit_time, exit_reason,
 			profit_loss, profit_loss_pct, duration_seconds,
 			status, entry_score, highest_price, trailing_stop_pct,
-			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage
+			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage,
+			trade_entry_id, user_id, binance_tp_order_id
+		from autoscalp_entries
+		where user_id = $1 and status = 'ACTIVE'
+		order by entry_time desc
+	`, userID)
+}
+
+func (r *PostgresAutoScalpRepository) GetAllActiveEntries(ctx context.Context) []*domain.AutoScalpEntry {
+	return r.queryEntries(ctx, `
+		select id, symbol, entry_price, stop_loss, entry_time,
+			exit_price, exit_time, exit_reason,
+			profit_loss, profit_loss_pct, duration_seconds,
+			status, entry_score, highest_price, trailing_stop_pct,
+			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage,
+			trade_entry_id, user_id, binance_tp_order_id
 		from autoscalp_entries
 		where status = 'ACTIVE'
 		order by entry_time desc
 	`)
-	if err != nil {
-		return []*domain.AutoScalpEntry{}
-	}
-	defer rows.Close()
-
-	entries := make([]*domain.AutoScalpEntry, 0)
-	for rows.Next() {
-		entry, scanErr := scanAutoScalpEntry(rows)
-		if scanErr != nil {
-			continue
-		}
-		entries = append(entries, entry)
-	}
-	return entries
 }
 
-func (r *PostgresAutoScalpRepository) GetEntryByID(id string) (*domain.AutoScalpEntry, error) {
-	row := r.pool.QueryRow(context.Background(), `
+func (r *PostgresAutoScalpRepository) GetEntryByID(ctx context.Context, id string) (*domain.AutoScalpEntry, error) {
+	row := r.pool.QueryRow(ctx, `
 		select id, symbol, entry_price, stop_loss, entry_time,
 			exit_price, exit_time, exit_reason,
 			profit_loss, profit_loss_pct, duration_seconds,
 			status, entry_score, highest_price, trailing_stop_pct,
-			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage
+			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage,
+			trade_entry_id, user_id, binance_tp_order_id
 		from autoscalp_entries
 		where id = $1
 	`, id)
 
 	e, err := scanAutoScalpEntry(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, autoScalpNotFound(id)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("entry with ID %s not found", id)
+		return nil, err
 	}
 	return e, nil
 }
 
-func (r *PostgresAutoScalpRepository) UpdateEntry(entry *domain.AutoScalpEntry) error {
+func (r *PostgresAutoScalpRepository) UpdateEntry(ctx context.Context, entry *domain.AutoScalpEntry) error {
 	if entry == nil {
 		return errors.New("nil entry")
 	}
 
-	_, err := r.pool.Exec(context.Background(), `
+	_, err := r.pool.Exec(ctx, `
 		update autoscalp_entries set
 			symbol=$2,
 			entry_price=$3,
@@ -129,7 +138,9 @@ func (r *PostgresAutoScalpRepository) UpdateEntry(entry *domain.AutoScalpEntry)
 			binance_order_id=$17,
 			binance_sl_order_id=$18,
 			quantity=$19,
-			leverage=$20
+			leverage=$20,
+			user_id=$21,
+			binance_tp_order_id=$22
 		where id=$1
 	`,
 		entry.ID,
@@ -152,21 +163,53 @@ func (r *PostgresAutoScalpRepository) UpdateEntry(entry *domain.AutoScalpEntry)
 		nullableInt64(entry.BinanceSLOrderID),
 		entry.Quantity,
 		entry.Leverage,
+		entry.UserID,
+		nullableInt64(entry.BinanceTPOrderID),
 	)
 	return err
 }
 
-func (r *PostgresAutoScalpRepository) GetHistory(fromTime time.Time) []*domain.AutoScalpEntry {
-	rows, err := r.pool.Query(context.Background(), `
+func (r *PostgresAutoScalpRepository) SetTradeLink(ctx context.Context, id, tradeEntryID string) error {
+	tag, err := r.pool.Exec(ctx, `update autoscalp_entries set trade_entry_id=$2 where id=$1`, id, tradeEntryID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return autoScalpNotFound(id)
+	}
+	return nil
+}
+
+func (r *PostgresAutoScalpRepository) GetHistory(ctx context.Context, userID string, fromTime time.Time) []*domain.AutoScalpEntry {
+	return r.queryEntries(ctx, `
 		select id, symbol, entry_price, stop_loss, entry_time,
 			exit_price, exit_time, exit_reason,
 			profit_loss, profit_loss_pct, duration_seconds,
 			status, entry_score, highest_price, trailing_stop_pct,
-			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage
+			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage,
+			trade_entry_id, user_id, binance_tp_order_id
+		from autoscalp_entries
+		where user_id = $1 and status = 'CLOSED' and exit_time is not null and exit_time >= $2
+		order by exit_time desc
+	`, userID, fromTime)
+}
+
+func (r *PostgresAutoScalpRepository) GetAllHistory(ctx context.Context, fromTime time.Time) []*domain.AutoScalpEntry {
+	return r.queryEntries(ctx, `
+		select id, symbol, entry_price, stop_loss, entry_time,
+			exit_price, exit_time, exit_reason,
+			profit_loss, profit_loss_pct, duration_seconds,
+			status, entry_score, highest_price, trailing_stop_pct,
+			is_real_trade, binance_order_id, binance_sl_order_id, quantity, leverage,
+			trade_entry_id, user_id, binance_tp_order_id
 		from autoscalp_entries
 		where status = 'CLOSED' and exit_time is not null and exit_time >= $1
 		order by exit_time desc
 	`, fromTime)
+}
+
+func (r *PostgresAutoScalpRepository) queryEntries(ctx context.Context, sql string, args ...any) []*domain.AutoScalpEntry {
+	rows, err := r.pool.Query(ctx, sql, args...)
 	if err != nil {
 		return []*domain.AutoScalpEntry{}
 	}
@@ -183,39 +226,132 @@ func (r *PostgresAutoScalpRepository) GetHistory(fromTime time.Time) []*domain.A
 	return entries
 }
 
-func (r *PostgresAutoScalpRepository) DeleteEntry(id string) error {
-	_, err := r.pool.Exec(context.Background(), `delete from autoscalp_entries where id=$1`, id)
+func (r *PostgresAutoScalpRepository) DeleteEntry(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `delete from autoscalp_entries where id=$1`, id)
 	return err
 }
 
-func (r *PostgresAutoScalpRepository) UpdateOrAttachBinanceOrders(symbol string, entryOrderID int64, slOrderID int64, qty float64, leverage int, filledPrice float64) error {
-	// Attach to most recent ACTIVE entry for symbol.
-	_, err := r.pool.Exec(context.Background(), `
+func (r *PostgresAutoScalpRepository) UpdateOrAttachBinanceOrders(ctx context.Context, userID string, symbol string, entryOrderID int64, slOrderID int64, tpOrderID int64, qty float64, leverage int, filledPrice float64) error {
+	// Attach to the user's most recent ACTIVE entry for symbol.
+	_, err := r.pool.Exec(ctx, `
 		update autoscalp_entries set
 			is_real_trade = true,
 			quantity = $2,
 			leverage = $3,
 			entry_price = case when $4 > 0 then $4 else entry_price end,
 			binance_order_id = $5,
-			binance_sl_order_id = $6
+			binance_sl_order_id = $6,
+			binance_tp_order_id = nullif($8::bigint, 0)
 		where id = (
 			select id from autoscalp_entries
-			where status='ACTIVE' and symbol=$1
+			where status='ACTIVE' and symbol=$1 and user_id=$7
 			order by entry_time desc
 			limit 1
 		)
-	`, symbol, qty, leverage, filledPrice, entryOrderID, slOrderID)
+	`, symbol, qty, leverage, filledPrice, entryOrderID, slOrderID, userID, tpOrderID)
 	return err
 }
 
-func (r *PostgresAutoScalpRepository) RecordEmergencyStop(userID string, at time.Time, reason string) error {
-	_, err := r.pool.Exec(context.Background(), `
+func (r *PostgresAutoScalpRepository) RecordEmergencyStop(ctx context.Context, userID string, at time.Time, reason string) error {
+	_, err := r.pool.Exec(ctx, `
 		insert into emergency_stop_events(user_id, occurred_at, reason)
 		values ($1,$2,$3)
 	`, userID, at, reason)
 	return err
 }
 
+func (r *PostgresAutoScalpRepository) PruneHistory(ctx context.Context, before time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		delete from autoscalp_entries
+		where status = 'CLOSED' and exit_time < $1 and trade_entry_id = ''
+	`, before)
+	return err
+}
+
+func (r *PostgresAutoScalpRepository) PruneEmergencyStops(ctx context.Context, before time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		delete from emergency_stop_events where occurred_at < $1
+	`, before)
+	return err
+}
+
+func (r *PostgresAutoScalpRepository) GetSettings(ctx context.Context, userID string) (*domain.AutoScalpSettings, error) {
+	row := r.pool.QueryRow(ctx, `
+		select user_id, enabled, max_concurrent_trades, min_entry_score, stop_loss_percent,
+			min_profit_percent, trailing_stop_percent, max_position_time, trailing_mode, updated_at
+		from autoscalp_settings
+		where user_id = $1
+	`, userID)
+
+	settings, err := scanAutoScalpSettings(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errNoAutoScalpSettings
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *PostgresAutoScalpRepository) SaveSettings(ctx context.Context, settings *domain.AutoScalpSettings) error {
+	if settings == nil {
+		return errors.New("nil settings")
+	}
+
+	_, err := r.pool.Exec(ctx, `
+		insert into autoscalp_settings(
+			user_id, enabled, max_concurrent_trades, min_entry_score, stop_loss_percent,
+			min_profit_percent, trailing_stop_percent, max_position_time, trailing_mode, updated_at
+		) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+		on conflict (user_id) do update set
+			enabled = excluded.enabled,
+			max_concurrent_trades = excluded.max_concurrent_trades,
+			min_entry_score = excluded.min_entry_score,
+			stop_loss_percent = excluded.stop_loss_percent,
+			min_profit_percent = excluded.min_profit_percent,
+			trailing_stop_percent = excluded.trailing_stop_percent,
+			max_position_time = excluded.max_position_time,
+			trailing_mode = excluded.trailing_mode,
+			updated_at = excluded.updated_at
+	`,
+		settings.UserID,
+		settings.Enabled,
+		settings.MaxConcurrentTrades,
+		settings.MinEntryScore,
+		settings.StopLossPercent,
+		settings.MinProfitPercent,
+		settings.TrailingStopPercent,
+		settings.MaxPositionTime,
+		settings.TrailingMode,
+		settings.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresAutoScalpRepository) ListEnabledSettings(ctx context.Context) ([]*domain.AutoScalpSettings, error) {
+	rows, err := r.pool.Query(ctx, `
+		select user_id, enabled, max_concurrent_trades, min_entry_score, stop_loss_percent,
+			min_profit_percent, trailing_stop_percent, max_position_time, trailing_mode, updated_at
+		from autoscalp_settings
+		where enabled
+		order by user_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	enabled := make([]*domain.AutoScalpSettings, 0)
+	for rows.Next() {
+		settings, err := scanAutoScalpSettings(rows)
+		if err != nil {
+			return nil, err
+		}
+		enabled = append(enabled, settings)
+	}
+	return enabled, rows.Err()
+}
+
 // Helpers
 
 type scanner interface {
@@ -230,6 +366,7 @@ func scanAutoScalpEntry(s scanner) (*domain.AutoScalpEntry, error) {
 	var profitLossPct pgtype.Float8
 	var orderID pgtype.Int8
 	var slOrderID pgtype.Int8
+	var tpOrderID pgtype.Int8
 
 	if err := s.Scan(
 		&e.ID,
@@ -252,6 +389,9 @@ func scanAutoScalpEntry(s scanner) (*domain.AutoScalpEntry, error) {
 		&slOrderID,
 		&e.Quantity,
 		&e.Leverage,
+		&e.TradeEntryID,
+		&e.UserID,
+		&tpOrderID,
 	); err != nil {
 		return nil, err
 	}
@@ -280,10 +420,33 @@ func scanAutoScalpEntry(s scanner) (*domain.AutoScalpEntry, error) {
 		v := slOrderID.Int64
 		e.BinanceSLOrderID = &v
 	}
+	if tpOrderID.Valid {
+		v := tpOrderID.Int64
+		e.BinanceTPOrderID = &v
+	}
 
 	return &e, nil
 }
 
+func scanAutoScalpSettings(s scanner) (*domain.AutoScalpSettings, error) {
+	var settings domain.AutoScalpSettings
+	if err := s.Scan(
+		&settings.UserID,
+		&settings.Enabled,
+		&settings.MaxConcurrentTrades,
+		&settings.MinEntryScore,
+		&settings.StopLossPercent,
+		&settings.MinProfitPercent,
+		&settings.TrailingStopPercent,
+		&settings.MaxPositionTime,
+		&settings.TrailingMode,
+		&settings.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
 func nullableFloat(v *float64) any {
 	if v == nil {
 		return pgtype.Float8{Valid: false}
@@ -12,6 +12,7 @@ import (
 	"screener-backend/internal/domain"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -35,7 +36,7 @@ func NewPostgresBinanceAPIRepository(pool *pgxpool.Pool, encryptionKey string) *
 	return &PostgresBinanceAPIRepository{pool: pool, encryptKey: key}
 }
 
-func (r *PostgresBinanceAPIRepository) SaveCredentials(cred *domain.BinanceAPICredentials) error {
+func (r *PostgresBinanceAPIRepository) SaveCredentials(ctx context.Context, cred *domain.BinanceAPICredentials) error {
 	if cred == nil {
 		return errors.New("nil credentials")
 	}
@@ -61,18 +62,30 @@ func (r *PostgresBinanceAPIRepository) SaveCredentials(cred *domain.BinanceAPICr
 		lastTested = time.Unix(0, 0).UTC()
 	}
 
-	_, err = r.pool.Exec(context.Background(), `
+	subSecretEnc := ""
+	if cred.SubAccountSecretKey != "" {
+		subSecretEnc, err = r.encrypt(cred.SubAccountSecretKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = r.pool.Exec(ctx, `
 		insert into binance_credentials(
 			user_id, api_key, secret_key_enc, is_testnet, is_enabled, permissions,
-			created_at, updated_at, last_tested
-		) values ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+			created_at, updated_at, last_tested,
+			sub_account_email, sub_account_api_key, sub_account_secret_enc
+		) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
 		on conflict (user_id) do update set
 			api_key = excluded.api_key,
 			secret_key_enc = excluded.secret_key_enc,
 			is_testnet = excluded.is_testnet,
 			is_enabled = excluded.is_enabled,
 			permissions = excluded.permissions,
-			updated_at = excluded.updated_at
+			updated_at = excluded.updated_at,
+			sub_account_email = excluded.sub_account_email,
+			sub_account_api_key = excluded.sub_account_api_key,
+			sub_account_secret_enc = excluded.sub_account_secret_enc
 	`,
 		cred.UserID,
 		cred.APIKey,
@@ -83,24 +96,62 @@ func (r *PostgresBinanceAPIRepository) SaveCredentials(cred *domain.BinanceAPICr
 		createdAt,
 		now,
 		lastTested,
+		cred.SubAccountEmail,
+		cred.SubAccountAPIKey,
+		subSecretEnc,
 	)
 	return err
 }
 
-func (r *PostgresBinanceAPIRepository) GetCredentials(userID string) (*domain.BinanceAPICredentials, error) {
-	row := r.pool.QueryRow(context.Background(), `
-		select user_id, api_key, secret_key_enc, is_testnet, is_enabled, permissions,
-			created_at, updated_at, last_tested
+const credentialColumns = `user_id, api_key, secret_key_enc, is_testnet, is_enabled, permissions,
+			created_at, updated_at, last_tested,
+			sub_account_email, sub_account_api_key, sub_account_secret_enc`
+
+func (r *PostgresBinanceAPIRepository) GetCredentials(ctx context.Context, userID string) (*domain.BinanceAPICredentials, error) {
+	row := r.pool.QueryRow(ctx, `
+		select `+credentialColumns+`
 		from binance_credentials
 		where user_id = $1
 	`, userID)
 
+	cred, err := r.scanCredentials(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errCredentialsNotFound
+	}
+	return cred, err
+}
+
+func (r *PostgresBinanceAPIRepository) ListCredentials(ctx context.Context) ([]*domain.BinanceAPICredentials, error) {
+	rows, err := r.pool.Query(ctx, `
+		select `+credentialColumns+`
+		from binance_credentials
+		order by user_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var creds []*domain.BinanceAPICredentials
+	for rows.Next() {
+		cred, err := r.scanCredentials(rows)
+		if err != nil {
+			return nil, err
+		}
+		creds = append(creds, cred)
+	}
+	return creds, rows.Err()
+}
+
+// scanCredentials reads one credentialColumns row and decrypts the secrets
+func (r *PostgresBinanceAPIRepository) scanCredentials(s scanner) (*domain.BinanceAPICredentials, error) {
 	var cred domain.BinanceAPICredentials
 	var secretEnc string
+	var subSecretEnc string
 	var permissionsRaw []byte
 	var lastTested time.Time
 
-	if err := row.Scan(
+	if err := s.Scan(
 		&cred.UserID,
 		&cred.APIKey,
 		&secretEnc,
@@ -110,8 +161,11 @@ func (r *PostgresBinanceAPIRepository) GetCredentials(userID string) (*domain.Bi
 		&cred.CreatedAt,
 		&cred.UpdatedAt,
 		&lastTested,
+		&cred.SubAccountEmail,
+		&cred.SubAccountAPIKey,
+		&subSecretEnc,
 	); err != nil {
-		return nil, errors.New("credentials not found")
+		return nil, err
 	}
 
 	secret, err := r.decrypt(secretEnc)
@@ -119,23 +173,31 @@ func (r *PostgresBinanceAPIRepository) GetCredentials(userID string) (*domain.Bi
 		return nil, err
 	}
 	cred.SecretKey = secret
+
+	if subSecretEnc != "" {
+		subSecret, err := r.decrypt(subSecretEnc)
+		if err != nil {
+			return nil, err
+		}
+		cred.SubAccountSecretKey = subSecret
+	}
 	cred.LastTested = lastTested
 
 	_ = json.Unmarshal(permissionsRaw, &cred.Permissions)
 	return &cred, nil
 }
 
-func (r *PostgresBinanceAPIRepository) DeleteCredentials(userID string) error {
-	_, err := r.pool.Exec(context.Background(), `delete from binance_credentials where user_id = $1`, userID)
+func (r *PostgresBinanceAPIRepository) DeleteCredentials(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, `delete from binance_credentials where user_id = $1`, userID)
 	return err
 }
 
-func (r *PostgresBinanceAPIRepository) SaveTradingConfig(config *domain.BinanceTradingConfig) error {
+func (r *PostgresBinanceAPIRepository) SaveTradingConfig(ctx context.Context, config *domain.BinanceTradingConfig) error {
 	if config == nil {
 		return errors.New("nil config")
 	}
 
-	_, err := r.pool.Exec(context.Background(), `
+	_, err := r.pool.Exec(ctx, `
 		insert into binance_trading_config(
 			user_id, trade_amount_usdt, leverage, order_type, max_slippage_percent,
 			max_daily_loss_usdt, max_daily_trades, enable_real_trading,
@@ -171,8 +233,8 @@ func (r *PostgresBinanceAPIRepository) SaveTradingConfig(config *domain.BinanceT
 	return err
 }
 
-func (r *PostgresBinanceAPIRepository) GetTradingConfig(userID string) (*domain.BinanceTradingConfig, error) {
-	row := r.pool.QueryRow(context.Background(), `
+func (r *PostgresBinanceAPIRepository) GetTradingConfig(ctx context.Context, userID string) (*domain.BinanceTradingConfig, error) {
+	row := r.pool.QueryRow(ctx, `
 		select user_id, trade_amount_usdt, leverage, order_type, max_slippage_percent,
 			max_daily_loss_usdt, max_daily_trades, enable_real_trading,
 			use_stop_loss, use_take_profit, default_stop_loss_pct, default_take_profit_pct
@@ -196,14 +258,14 @@ func (r *PostgresBinanceAPIRepository) GetTradingConfig(userID string) (*domain.
 		&cfg.DefaultTakeProfitPct,
 	); err != nil {
 		// fall back to the same defaults as the in-memory repo
-		return (&BinanceAPIRepository{}).GetTradingConfig(userID)
+		return (&BinanceAPIRepository{}).GetTradingConfig(ctx, userID)
 	}
 
 	return cfg, nil
 }
 
-func (r *PostgresBinanceAPIRepository) UpdateLastTested(userID string) error {
-	_, err := r.pool.Exec(context.Background(), `update binance_credentials set last_tested = now() where user_id = $1`, userID)
+func (r *PostgresBinanceAPIRepository) UpdateLastTested(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, `update binance_credentials set last_tested = now() where user_id = $1`, userID)
 	return err
 }
 
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresBlackoutRepository keeps blackout windows in blackout_windows
+type PostgresBlackoutRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresBlackoutRepository(pool *pgxpool.Pool) *PostgresBlackoutRepository {
+	return &PostgresBlackoutRepository{pool: pool}
+}
+
+func (r *PostgresBlackoutRepository) SaveBlackout(ctx context.Context, w *domain.BlackoutWindow) error {
+	return r.pool.QueryRow(ctx, `
+		insert into blackout_windows(kind, name, starts_at, ends_at, created_at, created_by)
+		values ($1,$2,$3,$4,$5,$6)
+		returning id
+	`, w.Kind, w.Name, w.Start, w.End, w.CreatedAt, w.CreatedBy).Scan(&w.ID)
+}
+
+func (r *PostgresBlackoutRepository) ListBlackouts(ctx context.Context, endsAfter time.Time) ([]*domain.BlackoutWindow, error) {
+	rows, err := r.pool.Query(ctx, `
+		select id, kind, name, starts_at, ends_at, created_at, created_by
+		from blackout_windows
+		where ends_at > $1
+		order by starts_at, id
+	`, endsAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*domain.BlackoutWindow, 0)
+	for rows.Next() {
+		w, err := scanBlackout(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresBlackoutRepository) DeleteBlackout(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `delete from blackout_windows where id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errBlackoutNotFound
+	}
+	return nil
+}
+
+func scanBlackout(s scanner) (*domain.BlackoutWindow, error) {
+	var w domain.BlackoutWindow
+	if err := s.Scan(&w.ID, &w.Kind, &w.Name, &w.Start, &w.End, &w.CreatedAt, &w.CreatedBy); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// compile-time check
+var _ domain.BlackoutRepository = (*PostgresBlackoutRepository)(nil)
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"screener-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresConfigHistoryRepository keeps settings changes in config_changes
+type PostgresConfigHistoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresConfigHistoryRepository(pool *pgxpool.Pool) *PostgresConfigHistoryRepository {
+	return &PostgresConfigHistoryRepository{pool: pool}
+}
+
+const configChangeColumns = `id, author, source, created_at, diff, rollback_of`
+
+func (r *PostgresConfigHistoryRepository) AppendConfigChange(ctx context.Context, change *domain.ConfigChange) error {
+	return r.pool.QueryRow(ctx, `
+		insert into config_changes(author, source, created_at, diff, rollback_of)
+		values ($1,$2,$3,$4,$5)
+		returning id
+	`, change.Author, change.Source, change.CreatedAt, change.Diff, change.RollbackOf).Scan(&change.ID)
+}
+
+func (r *PostgresConfigHistoryRepository) ListConfigChanges(ctx context.Context, limit int) ([]*domain.ConfigChange, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.pool.Query(ctx, `
+		select `+configChangeColumns+`
+		from config_changes
+		order by id desc
+		limit $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*domain.ConfigChange, 0)
+	for rows.Next() {
+		c, err := scanConfigChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresConfigHistoryRepository) GetConfigChange(ctx context.Context, id int64) (*domain.ConfigChange, error) {
+	c, err := scanConfigChange(r.pool.QueryRow(ctx, `
+		select `+configChangeColumns+` from config_changes where id = $1
+	`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errConfigChangeNotFound
+	}
+	return c, err
+}
+
+func scanConfigChange(s scanner) (*domain.ConfigChange, error) {
+	var c domain.ConfigChange
+	if err := s.Scan(&c.ID, &c.Author, &c.Source, &c.CreatedAt, &c.Diff, &c.RollbackOf); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// compile-time check
+var _ domain.ConfigHistoryRepository = (*PostgresConfigHistoryRepository)(nil)
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresCooldownStore keeps notification cooldowns in notification_cooldowns
+type PostgresCooldownStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresCooldownStore(pool *pgxpool.Pool) *PostgresCooldownStore {
+	return &PostgresCooldownStore{pool: pool}
+}
+
+func (s *PostgresCooldownStore) LoadCooldowns(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	rows, err := s.pool.Query(ctx, `
+		select key, notified_at from notification_cooldowns where notified_at >= $1
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]time.Time)
+	for rows.Next() {
+		var key string
+		var at time.Time
+		if err := rows.Scan(&key, &at); err != nil {
+			return nil, err
+		}
+		result[key] = at
+	}
+	return result, rows.Err()
+}
+
+func (s *PostgresCooldownStore) SaveCooldown(ctx context.Context, key string, notifiedAt time.Time) error {
+	_, err := s.pool.Exec(ctx, `
+		insert into notification_cooldowns(key, notified_at) values ($1, $2)
+		on conflict (key) do update set notified_at = excluded.notified_at
+	`, key, notifiedAt)
+	return err
+}
+
+func (s *PostgresCooldownStore) PruneCooldowns(ctx context.Context, before time.Time) error {
+	_, err := s.pool.Exec(ctx, `delete from notification_cooldowns where notified_at < $1`, before)
+	return err
+}
+
+// compile-time check
+var _ domain.CooldownStore = (*PostgresCooldownStore)(nil)
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"screener-backend/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresCycleRecordingRepository keeps recorded cycles in cycle_recordings
+type PostgresCycleRecordingRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresCycleRecordingRepository(pool *pgxpool.Pool) *PostgresCycleRecordingRepository {
+	return &PostgresCycleRecordingRepository{pool: pool}
+}
+
+func (r *PostgresCycleRecordingRepository) SaveCycleRecording(ctx context.Context, rec *domain.CycleRecording, keep int) error {
+	if err := r.pool.QueryRow(ctx, `
+		insert into cycle_recordings(started_at, symbols, data)
+		values ($1,$2,$3)
+		returning id
+	`, rec.StartedAt, rec.Symbols, rec.Data).Scan(&rec.ID); err != nil {
+		return err
+	}
+	if keep <= 0 {
+		return nil
+	}
+	_, err := r.pool.Exec(ctx, `
+		delete from cycle_recordings
+		where id <= (select id from cycle_recordings order by id desc offset $1 limit 1)
+	`, keep)
+	return err
+}
+
+func (r *PostgresCycleRecordingRepository) ListCycleRecordings(ctx context.Context, symbol string, from, to time.Time, limit int) ([]*domain.CycleRecording, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.pool.Query(ctx, `
+		select id, started_at, symbols
+		from cycle_recordings
+		where started_at between $1 and $2 and ($3 = '' or $3 = any(symbols))
+		order by id desc
+		limit $4
+	`, from, to, symbol, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*domain.CycleRecording, 0)
+	for rows.Next() {
+		var rec domain.CycleRecording
+		if err := rows.Scan(&rec.ID, &rec.StartedAt, &rec.Symbols); err != nil {
+			return nil, err
+		}
+		result = append(result, &rec)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresCycleRecordingRepository) GetCycleRecording(ctx context.Context, id int64) (*domain.CycleRecording, error) {
+	var rec domain.CycleRecording
+	err := r.pool.QueryRow(ctx, `
+		select id, started_at, symbols, data from cycle_recordings where id = $1
+	`, id).Scan(&rec.ID, &rec.StartedAt, &rec.Symbols, &rec.Data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errRecordingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// compile-time check
+var _ domain.CycleRecordingRepository = (*PostgresCycleRecordingRepository)(nil)
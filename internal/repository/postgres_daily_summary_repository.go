@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresDailySummaryRepository stores daily journal summaries in Postgres
+type PostgresDailySummaryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDailySummaryRepository(pool *pgxpool.Pool) *PostgresDailySummaryRepository {
+	return &PostgresDailySummaryRepository{pool: pool}
+}
+
+func (r *PostgresDailySummaryRepository) SaveSummary(ctx context.Context, s *domain.DailyTradeSummary) error {
+	_, err := r.pool.Exec(ctx, `
+		insert into trade_daily_summaries(
+			user_id, date, trades_taken, trades_closed, net_pl, win_rate,
+			open_trades, open_risk, notified, created_at
+		) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+		on conflict (user_id, date) do update set
+			trades_taken = excluded.trades_taken,
+			trades_closed = excluded.trades_closed,
+			net_pl = excluded.net_pl,
+			win_rate = excluded.win_rate,
+			open_trades = excluded.open_trades,
+			open_risk = excluded.open_risk,
+			notified = excluded.notified,
+			created_at = excluded.created_at
+	`,
+		s.UserID,
+		s.Date,
+		s.TradesTaken,
+		s.TradesClosed,
+		s.NetPL,
+		s.WinRate,
+		s.OpenTrades,
+		s.OpenRisk,
+		s.Notified,
+		s.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresDailySummaryRepository) GetSummaries(ctx context.Context, userID string, limit int) []*domain.DailyTradeSummary {
+	if limit <= 0 {
+		limit = 30
+	}
+	rows, err := r.pool.Query(ctx, `
+		select user_id, date, trades_taken, trades_closed, net_pl, win_rate,
+			open_trades, open_risk, notified, created_at
+		from trade_daily_summaries
+		where user_id = $1
+		order by date desc
+		limit $2
+	`, userID, limit)
+	if err != nil {
+		return []*domain.DailyTradeSummary{}
+	}
+	defer rows.Close()
+
+	result := make([]*domain.DailyTradeSummary, 0)
+	for rows.Next() {
+		var s domain.DailyTradeSummary
+		if err := rows.Scan(
+			&s.UserID,
+			&s.Date,
+			&s.TradesTaken,
+			&s.TradesClosed,
+			&s.NetPL,
+			&s.WinRate,
+			&s.OpenTrades,
+			&s.OpenRisk,
+			&s.Notified,
+			&s.CreatedAt,
+		); err != nil {
+			continue
+		}
+		result = append(result, &s)
+	}
+	return result
+}
+
+// compile-time check
+var _ domain.DailySummaryRepository = (*PostgresDailySummaryRepository)(nil)
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresIdempotencyRepository stores idempotency keys in Postgres so
+// retries are deduplicated across restarts and instances.
+// status_code = 0 marks a request that is still in flight.
+type PostgresIdempotencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresIdempotencyRepository(pool *pgxpool.Pool) *PostgresIdempotencyRepository {
+	return &PostgresIdempotencyRepository{pool: pool}
+}
+
+func (r *PostgresIdempotencyRepository) Reserve(ctx context.Context, scope, key string, ttl, lease time.Duration) (*domain.IdempotentResponse, bool, error) {
+
+	// Expired keys can be reused, and abandoned reservations taken over
+	now := time.Now()
+	if _, err := r.pool.Exec(ctx, `
+		delete from idempotency_keys
+		where scope=$1 and key=$2 and (created_at < $3 or (status_code = 0 and created_at < $4))
+	`, scope, key, now.Add(-ttl), now.Add(-lease)); err != nil {
+		return nil, false, err
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		insert into idempotency_keys(scope, key, status_code, content_type, body, created_at)
+		values ($1, $2, 0, '', '', now())
+		on conflict (scope, key) do nothing
+	`, scope, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if tag.RowsAffected() == 1 {
+		return nil, true, nil
+	}
+
+	var resp domain.IdempotentResponse
+	if err := r.pool.QueryRow(ctx, `
+		select status_code, content_type, body, created_at
+		from idempotency_keys where scope=$1 and key=$2
+	`, scope, key).Scan(&resp.StatusCode, &resp.ContentType, &resp.Body, &resp.CreatedAt); err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode == 0 {
+		return nil, false, nil
+	}
+	return &resp, false, nil
+}
+
+func (r *PostgresIdempotencyRepository) Complete(ctx context.Context, scope, key string, resp *domain.IdempotentResponse) error {
+	_, err := r.pool.Exec(ctx, `
+		update idempotency_keys set status_code=$3, content_type=$4, body=$5
+		where scope=$1 and key=$2
+	`, scope, key, resp.StatusCode, resp.ContentType, resp.Body)
+	return err
+}
+
+func (r *PostgresIdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	_, err := r.pool.Exec(ctx, `
+		delete from idempotency_keys where scope=$1 and key=$2
+	`, scope, key)
+	return err
+}
+
+// compile-time check
+var _ domain.IdempotencyRepository = (*PostgresIdempotencyRepository)(nil)
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"screener-backend/internal/domain"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresMarketArchiveRepository stores candles and snapshots in tables
+// range-partitioned by month; partitions are created on first write.
+type PostgresMarketArchiveRepository struct {
+	pool *pgxpool.Pool
+
+	mu         sync.Mutex
+	partitions map[string]bool // "<table>_YYYY_MM" already ensured
+}
+
+func NewPostgresMarketArchiveRepository(pool *pgxpool.Pool) *PostgresMarketArchiveRepository {
+	return &PostgresMarketArchiveRepository{pool: pool, partitions: make(map[string]bool)}
+}
+
+// ensurePartition creates the monthly partition of table covering t
+func (r *PostgresMarketArchiveRepository) ensurePartition(ctx context.Context, table string, t time.Time) error {
+	t = t.UTC()
+	monthStart := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	name := fmt.Sprintf("%s_%s", table, monthStart.Format("2006_01"))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.partitions[name] {
+		return nil
+	}
+
+	_, err := r.pool.Exec(ctx, fmt.Sprintf(
+		`create table if not exists %s partition of %s for values from ('%s') to ('%s')`,
+		name, table, monthStart.Format(time.RFC3339), monthStart.AddDate(0, 1, 0).Format(time.RFC3339),
+	))
+	if err != nil {
+		return fmt.Errorf("create partition %s: %w", name, err)
+	}
+	r.partitions[name] = true
+	return nil
+}
+
+func (r *PostgresMarketArchiveRepository) SaveCandles(ctx context.Context, symbol, interval string, candles []domain.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, c := range candles {
+		if err := r.ensurePartition(ctx, "kline_archive", c.OpenTime); err != nil {
+			return err
+		}
+		batch.Queue(`
+			insert into kline_archive(
+				symbol, interval, open_time, open, high, low, close,
+				volume, quote_volume, taker_buy_quote
+			) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+			on conflict (symbol, interval, open_time) do nothing
+		`, symbol, interval, c.OpenTime, c.Open, c.High, c.Low, c.Close, c.Volume, c.QuoteVolume, c.TakerBuyQuote)
+	}
+	return r.pool.SendBatch(ctx, batch).Close()
+}
+
+func (r *PostgresMarketArchiveRepository) GetCandles(ctx context.Context, symbol, interval string, from, to time.Time) ([]domain.Candle, error) {
+	rows, err := r.pool.Query(ctx, `
+		select open_time, open, high, low, close, volume, quote_volume, taker_buy_quote
+		from kline_archive
+		where symbol = $1 and interval = $2 and open_time between $3 and $4
+		order by open_time
+	`, symbol, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]domain.Candle, 0)
+	for rows.Next() {
+		var c domain.Candle
+		if err := rows.Scan(&c.OpenTime, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume, &c.QuoteVolume, &c.TakerBuyQuote); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresMarketArchiveRepository) SaveSnapshots(ctx context.Context, takenAt time.Time, coins []domain.CoinData) error {
+	if len(coins) == 0 {
+		return nil
+	}
+	if err := r.ensurePartition(ctx, "coin_snapshots", takenAt); err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, c := range coins {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		batch.Queue(`
+			insert into coin_snapshots(symbol, taken_at, score, status, price, data)
+			values ($1,$2,$3,$4,$5,$6)
+			on conflict (symbol, taken_at) do nothing
+		`, c.Symbol, takenAt, c.Score, c.Status, c.Price, data)
+	}
+	return r.pool.SendBatch(ctx, batch).Close()
+}
+
+func (r *PostgresMarketArchiveRepository) GetSnapshots(ctx context.Context, symbol string, from, to time.Time, limit int) ([]domain.CoinSnapshot, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	rows, err := r.pool.Query(ctx, `
+		select taken_at, data
+		from coin_snapshots
+		where symbol = $1 and taken_at between $2 and $3
+		order by taken_at desc
+		limit $4
+	`, symbol, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSnapshots(rows)
+}
+
+func (r *PostgresMarketArchiveRepository) ListSnapshots(ctx context.Context, symbol string, from, to time.Time, limit int) ([]domain.CoinSnapshot, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	rows, err := r.pool.Query(ctx, `
+		select taken_at, data
+		from coin_snapshots
+		where ($1 = '' or symbol = $1) and taken_at between $2 and $3
+		order by taken_at, symbol
+		limit $4
+	`, symbol, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSnapshots(rows)
+}
+
+func scanSnapshots(rows pgx.Rows) ([]domain.CoinSnapshot, error) {
+	defer rows.Close()
+
+	result := make([]domain.CoinSnapshot, 0)
+	for rows.Next() {
+		var s domain.CoinSnapshot
+		var data []byte
+		if err := rows.Scan(&s.TakenAt, &data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &s.Coin); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresMarketArchiveRepository) SaveScreenerSnapshots(ctx context.Context, snapshots []domain.ScreenerSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, s := range snapshots {
+		if err := r.ensurePartition(ctx, "screener_snapshots", s.TakenAt); err != nil {
+			return err
+		}
+		batch.Queue(`
+			insert into screener_snapshots(
+				symbol, taken_at, rank, price, score, status,
+				intraday_score, intraday_status, pullback_score, pullback_status,
+				breakout_score, breakout_status, follow_trend_score, follow_trend_status
+			) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)
+			on conflict (symbol, taken_at) do nothing
+		`, s.Symbol, s.TakenAt, s.Rank, s.Price, s.Score, s.Status,
+			s.IntradayScore, s.IntradayStatus, s.PullbackScore, s.PullbackStatus,
+			s.BreakoutScore, s.BreakoutStatus, s.FollowTrendScore, s.FollowTrendStatus)
+	}
+	return r.pool.SendBatch(ctx, batch).Close()
+}
+
+func (r *PostgresMarketArchiveRepository) GetScreenerHistory(ctx context.Context, symbol string, from, to time.Time, limit int) ([]domain.ScreenerSnapshot, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	rows, err := r.pool.Query(ctx, `
+		select * from (
+			select symbol, taken_at, rank, price, score, status,
+				intraday_score, intraday_status, pullback_score, pullback_status,
+				breakout_score, breakout_status, follow_trend_score, follow_trend_status
+			from screener_snapshots
+			where symbol = $1 and taken_at between $2 and $3
+			order by taken_at desc
+			limit $4
+		) newest
+		order by taken_at
+	`, symbol, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]domain.ScreenerSnapshot, 0)
+	for rows.Next() {
+		var s domain.ScreenerSnapshot
+		if err := rows.Scan(&s.Symbol, &s.TakenAt, &s.Rank, &s.Price, &s.Score, &s.Status,
+			&s.IntradayScore, &s.IntradayStatus, &s.PullbackScore, &s.PullbackStatus,
+			&s.BreakoutScore, &s.BreakoutStatus, &s.FollowTrendScore, &s.FollowTrendStatus); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresMarketArchiveRepository) PruneCandles(ctx context.Context, before time.Time) error {
+	return r.prune(ctx, "kline_archive", "open_time", before)
+}
+
+func (r *PostgresMarketArchiveRepository) PruneSnapshots(ctx context.Context, before time.Time) error {
+	return r.prune(ctx, "coin_snapshots", "taken_at", before)
+}
+
+func (r *PostgresMarketArchiveRepository) PruneScreenerSnapshots(ctx context.Context, before time.Time) error {
+	return r.prune(ctx, "screener_snapshots", "taken_at", before)
+}
+
+// prune drops whole monthly partitions that end before the cutoff (which
+// frees disk right away) and deletes the older rows of the partition it falls in.
+func (r *PostgresMarketArchiveRepository) prune(ctx context.Context, table, column string, before time.Time) error {
+	rows, err := r.pool.Query(ctx, `
+		select c.relname
+		from pg_inherits i
+		join pg_class c on c.oid = i.inhrelid
+		join pg_class p on p.oid = i.inhparent
+		where p.relname = $1
+	`, table)
+	if err != nil {
+		return err
+	}
+	var partitions []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		partitions = append(partitions, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, name := range partitions {
+		monthStart, err := time.Parse("2006_01", strings.TrimPrefix(name, table+"_"))
+		if err != nil || monthStart.AddDate(0, 1, 0).After(before) {
+			continue
+		}
+		if _, err := r.pool.Exec(ctx, fmt.Sprintf(`drop table if exists %s`, pgx.Identifier{name}.Sanitize())); err != nil {
+			return fmt.Errorf("drop partition %s: %w", name, err)
+		}
+		r.mu.Lock()
+		delete(r.partitions, name)
+		r.mu.Unlock()
+	}
+
+	_, err = r.pool.Exec(ctx, fmt.Sprintf(`delete from %s where %s < $1`, table, column), before)
+	return err
+}
+
+// compile-time check
+var _ domain.MarketArchiveRepository = (*PostgresMarketArchiveRepository)(nil)
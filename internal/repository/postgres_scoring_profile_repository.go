@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"screener-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresScoringProfileRepository keeps scoring profiles in scoring_profiles
+type PostgresScoringProfileRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresScoringProfileRepository(pool *pgxpool.Pool) *PostgresScoringProfileRepository {
+	return &PostgresScoringProfileRepository{pool: pool}
+}
+
+func (r *PostgresScoringProfileRepository) GetScoringProfile(ctx context.Context, userID string) (*domain.ScoringProfile, error) {
+	p := domain.ScoringProfile{UserID: userID}
+	err := r.pool.QueryRow(ctx, `select weights, updated_at from scoring_profiles where user_id = $1`, userID).Scan(&p.Weights, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errNoScoringProfile
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PostgresScoringProfileRepository) SaveScoringProfile(ctx context.Context, p *domain.ScoringProfile) error {
+	_, err := r.pool.Exec(ctx, `
+		insert into scoring_profiles(user_id, weights, updated_at)
+		values ($1, $2, $3)
+		on conflict (user_id) do update set weights = excluded.weights, updated_at = excluded.updated_at
+	`, p.UserID, p.Weights, p.UpdatedAt)
+	return err
+}
+
+// compile-time check
+var _ domain.ScoringProfileRepository = (*PostgresScoringProfileRepository)(nil)
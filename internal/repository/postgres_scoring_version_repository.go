@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"screener-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresScoringVersionRepository keeps scoring versions in scoring_versions
+type PostgresScoringVersionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresScoringVersionRepository(pool *pgxpool.Pool) *PostgresScoringVersionRepository {
+	return &PostgresScoringVersionRepository{pool: pool}
+}
+
+const scoringVersionColumns = `id, created_at, source, status, weights, trigger_score, horizon, test_metrics, note`
+
+func (r *PostgresScoringVersionRepository) SaveScoringVersion(ctx context.Context, v *domain.ScoringVersion) error {
+	return r.pool.QueryRow(ctx, `
+		insert into scoring_versions(created_at, source, status, weights, trigger_score, horizon, test_metrics, note)
+		values ($1,$2,$3,$4,$5,$6,$7,$8)
+		returning id
+	`, v.CreatedAt, v.Source, v.Status, v.Weights, v.TriggerScore, v.Horizon, v.TestMetrics, v.Note).Scan(&v.ID)
+}
+
+func (r *PostgresScoringVersionRepository) ListScoringVersions(ctx context.Context, limit int) ([]*domain.ScoringVersion, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.pool.Query(ctx, `
+		select `+scoringVersionColumns+`
+		from scoring_versions
+		order by id desc
+		limit $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*domain.ScoringVersion, 0)
+	for rows.Next() {
+		v, err := scanScoringVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, rows.Err()
+}
+
+func (r *PostgresScoringVersionRepository) GetScoringVersion(ctx context.Context, id int64) (*domain.ScoringVersion, error) {
+	v, err := scanScoringVersion(r.pool.QueryRow(ctx, `
+		select `+scoringVersionColumns+` from scoring_versions where id = $1
+	`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errScoringNotFound
+	}
+	return v, err
+}
+
+func scanScoringVersion(s scanner) (*domain.ScoringVersion, error) {
+	var v domain.ScoringVersion
+	if err := s.Scan(&v.ID, &v.CreatedAt, &v.Source, &v.Status, &v.Weights, &v.TriggerScore, &v.Horizon, &v.TestMetrics, &v.Note); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// compile-time check
+var _ domain.ScoringVersionRepository = (*PostgresScoringVersionRepository)(nil)
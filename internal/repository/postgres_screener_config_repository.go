@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"screener-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresScreenerConfigRepository keeps the screener config in the single
+// row of screener_config
+type PostgresScreenerConfigRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresScreenerConfigRepository(pool *pgxpool.Pool) *PostgresScreenerConfigRepository {
+	return &PostgresScreenerConfigRepository{pool: pool}
+}
+
+func (r *PostgresScreenerConfigRepository) GetScreenerConfig(ctx context.Context) (*domain.ScreenerConfig, error) {
+	var cfg domain.ScreenerConfig
+	err := r.pool.QueryRow(ctx, `select config from screener_config where id = 1`).Scan(&cfg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errNoScreenerConfig
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (r *PostgresScreenerConfigRepository) SaveScreenerConfig(ctx context.Context, cfg *domain.ScreenerConfig) error {
+	_, err := r.pool.Exec(ctx, `
+		insert into screener_config(id, config, updated_at)
+		values (1, $1, $2)
+		on conflict (id) do update set config = excluded.config, updated_at = excluded.updated_at
+	`, cfg, cfg.UpdatedAt)
+	return err
+}
+
+// compile-time check
+var _ domain.ScreenerConfigRepository = (*PostgresScreenerConfigRepository)(nil)
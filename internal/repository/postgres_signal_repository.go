@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"screener-backend/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresSignalRepository keeps signals and their outcomes in signals
+type PostgresSignalRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSignalRepository(pool *pgxpool.Pool) *PostgresSignalRepository {
+	return &PostgresSignalRepository{pool: pool}
+}
+
+const signalColumns = `id, symbol, strategy, direction, timeframe, score, price, signaled_at, outcomes, labeled`
+
+func (r *PostgresSignalRepository) SaveSignal(ctx context.Context, signal *domain.Signal) error {
+	outcomes := signal.Outcomes
+	if outcomes == nil {
+		outcomes = map[string]domain.SignalOutcome{}
+	}
+	return r.pool.QueryRow(ctx, `
+		insert into signals(symbol, strategy, direction, timeframe, score, price, signaled_at, outcomes, labeled)
+		values ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+		returning id
+	`, signal.Symbol, signal.Strategy, signal.Direction, signal.Timeframe, signal.Score, signal.Price, signal.SignaledAt, outcomes, signal.Labeled).Scan(&signal.ID)
+}
+
+func (r *PostgresSignalRepository) ListUnlabeledSignals(ctx context.Context, limit int) ([]*domain.Signal, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	return r.query(ctx, `
+		select `+signalColumns+`
+		from signals
+		where not labeled
+		order by signaled_at
+		limit $1
+	`, limit)
+}
+
+func (r *PostgresSignalRepository) SaveOutcomes(ctx context.Context, id int64, outcomes map[string]domain.SignalOutcome, labeled bool) error {
+	tag, err := r.pool.Exec(ctx, `update signals set outcomes = $2, labeled = $3 where id = $1`, id, outcomes, labeled)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errSignalNotFound
+	}
+	return nil
+}
+
+func (r *PostgresSignalRepository) ListSignals(ctx context.Context, filter domain.SignalFilter) ([]*domain.Signal, error) {
+	where := []string{"true"}
+	var args []any
+	add := func(clause string, v any) {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.Strategy != "" {
+		add("strategy = $%d", filter.Strategy)
+	}
+	if filter.Symbol != "" {
+		add("symbol = $%d", filter.Symbol)
+	}
+	if !filter.From.IsZero() {
+		add("signaled_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("signaled_at <= $%d", filter.To)
+	}
+	limit := ""
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		limit = fmt.Sprintf("limit $%d", len(args))
+	}
+	return r.query(ctx, `
+		select `+signalColumns+`
+		from signals
+		where `+strings.Join(where, " and ")+`
+		order by signaled_at desc
+		`+limit, args...)
+}
+
+func (r *PostgresSignalRepository) PruneSignals(ctx context.Context, before time.Time) error {
+	_, err := r.pool.Exec(ctx, `delete from signals where signaled_at < $1`, before)
+	return err
+}
+
+func (r *PostgresSignalRepository) query(ctx context.Context, sql string, args ...any) ([]*domain.Signal, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*domain.Signal, 0)
+	for rows.Next() {
+		var s domain.Signal
+		if err := rows.Scan(&s.ID, &s.Symbol, &s.Strategy, &s.Direction, &s.Timeframe, &s.Score, &s.Price, &s.SignaledAt, &s.Outcomes, &s.Labeled); err != nil {
+			return nil, err
+		}
+		result = append(result, &s)
+	}
+	return result, rows.Err()
+}
+
+// compile-time check
+var _ domain.SignalRepository = (*PostgresSignalRepository)(nil)
@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"screener-backend/internal/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const tradeEntryColumns = `id, user_id, symbol, is_long, entry_price, stop_loss,
+			take_profit1, take_profit2, take_profit3, entry_time,
+			status, exit_price, exit_time, profit_loss, entry_reason,
+			notes, tags, strategy, risk_reward, fees, funding,
+			signal_snapshot, quantity, leverage, notional, autoscalp_entry_id,
+			settle_asset, profit_loss_native, archived_at`
+
+// PostgresTradeRepository stores manual trade journal entries in Postgres.
+// Active entries: status in ('active','tp1_hit','tp2_hit'). History: status in ('closed','stopped','tp3_hit').
+type PostgresTradeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTradeRepository(pool *pgxpool.Pool) *PostgresTradeRepository {
+	return &PostgresTradeRepository{pool: pool}
+}
+
+func (r *PostgresTradeRepository) CreateEntry(ctx context.Context, entry *domain.TradeEntry) error {
+	if entry == nil {
+		return errors.New("nil entry")
+	}
+
+	signalJSON, err := marshalSignal(entry.Signal)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		insert into trade_entries(
+			id, user_id, symbol, is_long, entry_price, stop_loss,
+			take_profit1, take_profit2, take_profit3, entry_time,
+			status, exit_price, exit_time, profit_loss, entry_reason,
+			notes, tags, strategy, risk_reward, fees, funding,
+			signal_snapshot, quantity, leverage, notional, autoscalp_entry_id,
+			settle_asset, profit_loss_native
+		) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28)
+		on conflict (id) do nothing
+	`,
+		entry.ID,
+		entry.UserID,
+		entry.Symbol,
+		entry.IsLong,
+		entry.EntryPrice,
+		entry.StopLoss,
+		entry.TakeProfit1,
+		entry.TakeProfit2,
+		entry.TakeProfit3,
+		entry.EntryTime,
+		entry.Status,
+		nullableFloat(entry.ExitPrice),
+		nullableTime(entry.ExitTime),
+		nullableFloat(entry.ProfitLoss),
+		entry.EntryReason,
+		entry.Notes,
+		tradeTags(entry.Tags),
+		entry.Strategy,
+		entry.RiskReward,
+		entry.Fees,
+		entry.Funding,
+		signalJSON,
+		entry.Quantity,
+		entry.Leverage,
+		entry.Notional,
+		entry.AutoScalpEntryID,
+		entry.SettleAsset,
+		nullableFloat(entry.ProfitLossNative),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.Conflict("TRADE_EXISTS", fmt.Sprintf("entry with ID %s already exists", entry.ID))
+	}
+	return nil
+}
+
+func (r *PostgresTradeRepository) GetActiveEntries(ctx context.Context, userID string) []*domain.TradeEntry {
+	return r.queryEntries(ctx, `
+		select `+tradeEntryColumns+`
+		from trade_entries
+		where user_id = $1 and archived_at is null and status in ('active', 'tp1_hit', 'tp2_hit')
+		order by entry_time desc
+	`, userID)
+}
+
+func (r *PostgresTradeRepository) GetAllActiveEntries(ctx context.Context) []*domain.TradeEntry {
+	return r.queryEntries(ctx, `
+		select `+tradeEntryColumns+`
+		from trade_entries
+		where archived_at is null and status in ('active', 'tp1_hit', 'tp2_hit')
+		order by entry_time desc
+	`)
+}
+
+func (r *PostgresTradeRepository) GetUserIDs(ctx context.Context) []string {
+	rows, err := r.pool.Query(ctx, `
+		select distinct user_id from trade_entries where user_id <> '' and archived_at is null
+	`)
+	if err != nil {
+		return []string{}
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (r *PostgresTradeRepository) GetEntryByID(ctx context.Context, userID, id string) (*domain.TradeEntry, error) {
+	row := r.pool.QueryRow(ctx, `
+		select `+tradeEntryColumns+`
+		from trade_entries
+		where id = $1 and user_id = $2 and archived_at is null
+	`, id, userID)
+
+	e, err := scanTradeEntry(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errTradeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *PostgresTradeRepository) UpdateEntry(ctx context.Context, entry *domain.TradeEntry) error {
+	if entry == nil {
+		return errors.New("nil entry")
+	}
+
+	signalJSON, err := marshalSignal(entry.Signal)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		update trade_entries set
+			symbol=$2,
+			is_long=$3,
+			entry_price=$4,
+			stop_loss=$5,
+			take_profit1=$6,
+			take_profit2=$7,
+			take_profit3=$8,
+			entry_time=$9,
+			status=$10,
+			exit_price=$11,
+			exit_time=$12,
+			profit_loss=$13,
+			entry_reason=$14,
+			notes=$16,
+			tags=$17,
+			strategy=$18,
+			risk_reward=$19,
+			fees=$20,
+			funding=$21,
+			signal_snapshot=$22,
+			quantity=$23,
+			leverage=$24,
+			notional=$25,
+			autoscalp_entry_id=$26,
+			settle_asset=$27,
+			profit_loss_native=$28
+		where id=$1 and user_id=$15 and archived_at is null
+	`,
+		entry.ID,
+		entry.Symbol,
+		entry.IsLong,
+		entry.EntryPrice,
+		entry.StopLoss,
+		entry.TakeProfit1,
+		entry.TakeProfit2,
+		entry.TakeProfit3,
+		entry.EntryTime,
+		entry.Status,
+		nullableFloat(entry.ExitPrice),
+		nullableTime(entry.ExitTime),
+		nullableFloat(entry.ProfitLoss),
+		entry.EntryReason,
+		entry.UserID,
+		entry.Notes,
+		tradeTags(entry.Tags),
+		entry.Strategy,
+		entry.RiskReward,
+		entry.Fees,
+		entry.Funding,
+		signalJSON,
+		entry.Quantity,
+		entry.Leverage,
+		entry.Notional,
+		entry.AutoScalpEntryID,
+		entry.SettleAsset,
+		nullableFloat(entry.ProfitLossNative),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errTradeNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTradeRepository) GetEntryHistory(ctx context.Context, userID string, filter domain.TradeHistoryFilter) []*domain.TradeEntry {
+	where := []string{"user_id = $1", "archived_at is null", "status in ('closed', 'stopped', 'tp3_hit')"}
+	args := []any{userID}
+	add := func(clause string, v any) {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Symbol != "" {
+		add("upper(symbol) = upper($%d)", filter.Symbol)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.IsLong != nil {
+		add("is_long = $%d", *filter.IsLong)
+	}
+	if filter.Tag != "" {
+		add("exists (select 1 from unnest(tags) t where lower(t) = lower($%d))", filter.Tag)
+	}
+	if filter.Strategy != "" {
+		add("strategy = $%d", filter.Strategy)
+	}
+	if filter.From != nil {
+		add("coalesce(exit_time, entry_time) >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("coalesce(exit_time, entry_time) <= $%d", *filter.To)
+	}
+
+	return r.queryEntries(ctx, `
+		select `+tradeEntryColumns+`
+		from trade_entries
+		where `+strings.Join(where, " and ")+`
+		order by exit_time desc nulls last, entry_time desc
+	`, args...)
+}
+
+func (r *PostgresTradeRepository) queryEntries(ctx context.Context, sql string, args ...any) []*domain.TradeEntry {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return []*domain.TradeEntry{}
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.TradeEntry, 0)
+	for rows.Next() {
+		entry, scanErr := scanTradeEntry(rows)
+		if scanErr != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (r *PostgresTradeRepository) ArchiveEntry(ctx context.Context, userID, id string) error {
+	tag, err := r.pool.Exec(ctx, `
+		update trade_entries set archived_at = now()
+		where id=$1 and user_id=$2 and archived_at is null
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errTradeNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTradeRepository) RestoreEntry(ctx context.Context, userID, id string) error {
+	tag, err := r.pool.Exec(ctx, `
+		update trade_entries set archived_at = null
+		where id=$1 and user_id=$2 and archived_at is not null
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errArchivedTradeNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTradeRepository) GetArchivedEntries(ctx context.Context, userID string) []*domain.TradeEntry {
+	return r.queryEntries(ctx, `
+		select `+tradeEntryColumns+`
+		from trade_entries
+		where user_id = $1 and archived_at is not null
+		order by archived_at desc
+	`, userID)
+}
+
+func (r *PostgresTradeRepository) PurgeEntry(ctx context.Context, userID, id string) error {
+	tag, err := r.pool.Exec(ctx, `
+		delete from trade_entries where id=$1 and user_id=$2 and archived_at is not null
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errArchivedTradeNotFound
+	}
+	return nil
+}
+
+func scanTradeEntry(s scanner) (*domain.TradeEntry, error) {
+	var e domain.TradeEntry
+	var exitPrice pgtype.Float8
+	var exitTime pgtype.Timestamptz
+	var profitLoss pgtype.Float8
+	var signalRaw []byte
+	var profitLossNative pgtype.Float8
+	var archivedAt pgtype.Timestamptz
+
+	if err := s.Scan(
+		&e.ID,
+		&e.UserID,
+		&e.Symbol,
+		&e.IsLong,
+		&e.EntryPrice,
+		&e.StopLoss,
+		&e.TakeProfit1,
+		&e.TakeProfit2,
+		&e.TakeProfit3,
+		&e.EntryTime,
+		&e.Status,
+		&exitPrice,
+		&exitTime,
+		&profitLoss,
+		&e.EntryReason,
+		&e.Notes,
+		&e.Tags,
+		&e.Strategy,
+		&e.RiskReward,
+		&e.Fees,
+		&e.Funding,
+		&signalRaw,
+		&e.Quantity,
+		&e.Leverage,
+		&e.Notional,
+		&e.AutoScalpEntryID,
+		&e.SettleAsset,
+		&profitLossNative,
+		&archivedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if exitPrice.Valid {
+		v := exitPrice.Float64
+		e.ExitPrice = &v
+	}
+	if exitTime.Valid {
+		v := exitTime.Time
+		e.ExitTime = &v
+	}
+	if profitLoss.Valid {
+		v := profitLoss.Float64
+		e.ProfitLoss = &v
+	}
+	if profitLossNative.Valid {
+		v := profitLossNative.Float64
+		e.ProfitLossNative = &v
+	}
+	if archivedAt.Valid {
+		v := archivedAt.Time
+		e.ArchivedAt = &v
+	}
+	if len(signalRaw) > 0 {
+		var snap domain.TradeSignalSnapshot
+		if err := json.Unmarshal(signalRaw, &snap); err == nil {
+			e.Signal = &snap
+		}
+	}
+
+	return &e, nil
+}
+
+// marshalSignal encodes the optional signal snapshot; nil stays SQL NULL
+func marshalSignal(snap *domain.TradeSignalSnapshot) ([]byte, error) {
+	if snap == nil {
+		return nil, nil
+	}
+	return json.Marshal(snap)
+}
+
+// tradeTags avoids writing NULL into the non-null tags column
+func tradeTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// compile-time check
+var _ domain.TradeEntryRepository = (*PostgresTradeRepository)(nil)
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"screener-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresUserRepository keeps users in users
+type PostgresUserRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresUserRepository(pool *pgxpool.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{pool: pool}
+}
+
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	tag, err := r.pool.Exec(ctx, `
+		insert into users(id, email, password_hash, created_at)
+		values ($1,$2,$3,$4)
+		on conflict (email) do nothing
+	`, user.ID, user.Email, user.PasswordHash, user.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errEmailTaken
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var user domain.User
+	err := r.pool.QueryRow(ctx, `select id, email, password_hash, created_at from users where email = $1`, email).
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// compile-time check
+var _ domain.UserRepository = (*PostgresUserRepository)(nil)
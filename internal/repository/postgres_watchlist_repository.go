@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresWatchlistRepository keeps watchlists in watchlist_items
+type PostgresWatchlistRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWatchlistRepository(pool *pgxpool.Pool) *PostgresWatchlistRepository {
+	return &PostgresWatchlistRepository{pool: pool}
+}
+
+const watchlistColumns = `user_id, symbol, alert_score, breakout_alert_score, note, created_at, updated_at`
+
+func (r *PostgresWatchlistRepository) ListWatchlist(ctx context.Context, userID string) ([]*domain.WatchlistItem, error) {
+	return r.query(ctx, `select `+watchlistColumns+` from watchlist_items where user_id = $1 order by symbol`, userID)
+}
+
+func (r *PostgresWatchlistRepository) ListAllWatchlists(ctx context.Context) ([]*domain.WatchlistItem, error) {
+	return r.query(ctx, `select `+watchlistColumns+` from watchlist_items order by user_id, symbol`)
+}
+
+func (r *PostgresWatchlistRepository) SaveWatchlistItem(ctx context.Context, item *domain.WatchlistItem) error {
+	return r.pool.QueryRow(ctx, `
+		insert into watchlist_items(`+watchlistColumns+`)
+		values ($1,$2,$3,$4,$5,$6,$7)
+		on conflict (user_id, symbol) do update set
+			alert_score = excluded.alert_score,
+			breakout_alert_score = excluded.breakout_alert_score,
+			note = excluded.note,
+			updated_at = excluded.updated_at
+		returning created_at
+	`, item.UserID, item.Symbol, item.AlertScore, item.BreakoutAlertScore, item.Note, item.CreatedAt, item.UpdatedAt).Scan(&item.CreatedAt)
+}
+
+func (r *PostgresWatchlistRepository) DeleteWatchlistItem(ctx context.Context, userID, symbol string) error {
+	tag, err := r.pool.Exec(ctx, `delete from watchlist_items where user_id = $1 and symbol = $2`, userID, symbol)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errWatchlistItemNotFound
+	}
+	return nil
+}
+
+func (r *PostgresWatchlistRepository) query(ctx context.Context, sql string, args ...any) ([]*domain.WatchlistItem, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*domain.WatchlistItem, 0)
+	for rows.Next() {
+		item, err := scanWatchlistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
+
+func scanWatchlistItem(s scanner) (*domain.WatchlistItem, error) {
+	var item domain.WatchlistItem
+	if err := s.Scan(&item.UserID, &item.Symbol, &item.AlertScore, &item.BreakoutAlertScore, &item.Note, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// compile-time check
+var _ domain.WatchlistRepository = (*PostgresWatchlistRepository)(nil)
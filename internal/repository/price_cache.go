@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"screener-backend/internal/domain"
+	"sync"
+)
+
+// InMemoryPriceCache keeps latest prices in process memory
+type InMemoryPriceCache struct {
+	mu     sync.RWMutex
+	prices map[string]float64
+}
+
+// NewInMemoryPriceCache creates an empty in-memory price cache
+func NewInMemoryPriceCache() *InMemoryPriceCache {
+	return &InMemoryPriceCache{prices: make(map[string]float64)}
+}
+
+// SetPrices merges the given prices into the cache
+func (c *InMemoryPriceCache) SetPrices(prices map[string]float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for symbol, price := range prices {
+		c.prices[symbol] = price
+	}
+}
+
+// GetPrice returns the cached price of symbol
+func (c *InMemoryPriceCache) GetPrice(symbol string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	price, ok := c.prices[symbol]
+	return price, ok
+}
+
+// compile-time check
+var _ domain.PriceCache = (*InMemoryPriceCache)(nil)
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/redis"
+)
+
+const (
+	redisCoinsKey    = "screener:coins"
+	redisPricesKey   = "screener:prices"
+	redisCooldownKey = "screener:cooldowns"
+)
+
+// RedisScreenerRepository stores the latest screening snapshot in Redis so it
+// survives restarts and is shared by every instance. If Redis is unreachable
+// it serves the last snapshot this instance saw.
+type RedisScreenerRepository struct {
+	client   *redis.Client
+	fallback *InMemoryScreenerRepository
+}
+
+// NewRedisScreenerRepository creates a Redis-backed screener repository
+func NewRedisScreenerRepository(client *redis.Client) *RedisScreenerRepository {
+	return &RedisScreenerRepository{client: client, fallback: NewInMemoryScreenerRepository()}
+}
+
+func (r *RedisScreenerRepository) SaveCoins(coins []domain.CoinData) {
+	r.fallback.SaveCoins(coins)
+
+	data, err := json.Marshal(coins)
+	if err != nil {
+		log.Printf("Redis screener: encode coins: %v", err)
+		return
+	}
+	if err := r.client.Set(redisCoinsKey, string(data), 0); err != nil {
+		log.Printf("Redis screener: save coins: %v", err)
+	}
+}
+
+func (r *RedisScreenerRepository) GetCoins() []domain.CoinData {
+	data, err := r.client.Get(redisCoinsKey)
+	if err != nil {
+		if !errors.Is(err, redis.ErrNil) {
+			log.Printf("Redis screener: load coins: %v", err)
+		}
+		return r.fallback.GetCoins()
+	}
+
+	var coins []domain.CoinData
+	if err := json.Unmarshal([]byte(data), &coins); err != nil {
+		log.Printf("Redis screener: decode coins: %v", err)
+		return r.fallback.GetCoins()
+	}
+	sort.Slice(coins, func(i, j int) bool {
+		return coins[i].Score > coins[j].Score
+	})
+	return coins
+}
+
+// RedisPriceCache keeps latest prices in a Redis hash (symbol -> price)
+type RedisPriceCache struct {
+	client   *redis.Client
+	fallback *InMemoryPriceCache
+}
+
+// NewRedisPriceCache creates a Redis-backed price cache
+func NewRedisPriceCache(client *redis.Client) *RedisPriceCache {
+	return &RedisPriceCache{client: client, fallback: NewInMemoryPriceCache()}
+}
+
+func (c *RedisPriceCache) SetPrices(prices map[string]float64) {
+	c.fallback.SetPrices(prices)
+
+	fields := make(map[string]string, len(prices))
+	for symbol, price := range prices {
+		fields[symbol] = strconv.FormatFloat(price, 'f', -1, 64)
+	}
+	if err := c.client.HSet(redisPricesKey, fields); err != nil {
+		log.Printf("Redis price cache: save: %v", err)
+	}
+}
+
+func (c *RedisPriceCache) GetPrice(symbol string) (float64, bool) {
+	v, err := c.client.HGet(redisPricesKey, symbol)
+	if err != nil {
+		if !errors.Is(err, redis.ErrNil) {
+			log.Printf("Redis price cache: load %s: %v", symbol, err)
+			return c.fallback.GetPrice(symbol)
+		}
+		return 0, false
+	}
+	price, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false
+	}
+	return price, true
+}
+
+// RedisCooldownStore keeps notification cooldowns in a Redis hash
+// (key -> unix millis of the last notification)
+type RedisCooldownStore struct {
+	client *redis.Client
+}
+
+// NewRedisCooldownStore creates a Redis-backed cooldown store
+func NewRedisCooldownStore(client *redis.Client) *RedisCooldownStore {
+	return &RedisCooldownStore{client: client}
+}
+
+func (s *RedisCooldownStore) LoadCooldowns(_ context.Context, since time.Time) (map[string]time.Time, error) {
+	fields, err := s.client.HGetAll(redisCooldownKey)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]time.Time, len(fields))
+	for key, v := range fields {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		if at := time.UnixMilli(ms); !at.Before(since) {
+			result[key] = at
+		}
+	}
+	return result, nil
+}
+
+func (s *RedisCooldownStore) SaveCooldown(_ context.Context, key string, notifiedAt time.Time) error {
+	return s.client.HSet(redisCooldownKey, map[string]string{
+		key: strconv.FormatInt(notifiedAt.UnixMilli(), 10),
+	})
+}
+
+func (s *RedisCooldownStore) PruneCooldowns(_ context.Context, before time.Time) error {
+	fields, err := s.client.HGetAll(redisCooldownKey)
+	if err != nil {
+		return err
+	}
+	args := []string{"HDEL", redisCooldownKey}
+	for key, v := range fields {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || time.UnixMilli(ms).Before(before) {
+			args = append(args, key)
+		}
+	}
+	if len(args) == 2 {
+		return nil
+	}
+	_, err = s.client.Do(args...)
+	return err
+}
+
+// compile-time check
+var _ domain.ScreenerRepository = (*RedisScreenerRepository)(nil)
+var _ domain.PriceCache = (*RedisPriceCache)(nil)
+var _ domain.CooldownStore = (*RedisCooldownStore)(nil)
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sync"
+)
+
+// InMemoryScoringProfileRepository keeps scoring profiles in memory
+type InMemoryScoringProfileRepository struct {
+	mu       sync.RWMutex
+	profiles map[string]*domain.ScoringProfile // by user
+}
+
+func NewInMemoryScoringProfileRepository() *InMemoryScoringProfileRepository {
+	return &InMemoryScoringProfileRepository{profiles: make(map[string]*domain.ScoringProfile)}
+}
+
+func (r *InMemoryScoringProfileRepository) GetScoringProfile(_ context.Context, userID string) (*domain.ScoringProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.profiles[userID]
+	if !ok {
+		return nil, errNoScoringProfile
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (r *InMemoryScoringProfileRepository) SaveScoringProfile(_ context.Context, p *domain.ScoringProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *p
+	r.profiles[p.UserID] = &stored
+	return nil
+}
+
+// compile-time check
+var _ domain.ScoringProfileRepository = (*InMemoryScoringProfileRepository)(nil)
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sync"
+)
+
+// InMemoryScoringVersionRepository keeps scoring versions in memory
+type InMemoryScoringVersionRepository struct {
+	mu       sync.RWMutex
+	versions []*domain.ScoringVersion // oldest first; IDs are index+1
+}
+
+func NewInMemoryScoringVersionRepository() *InMemoryScoringVersionRepository {
+	return &InMemoryScoringVersionRepository{}
+}
+
+func (r *InMemoryScoringVersionRepository) SaveScoringVersion(_ context.Context, v *domain.ScoringVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v.ID = int64(len(r.versions) + 1)
+	stored := *v
+	r.versions = append(r.versions, &stored)
+	return nil
+}
+
+func (r *InMemoryScoringVersionRepository) ListScoringVersions(_ context.Context, limit int) ([]*domain.ScoringVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.ScoringVersion, 0, len(r.versions))
+	for i := len(r.versions) - 1; i >= 0; i-- {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		v := *r.versions[i]
+		result = append(result, &v)
+	}
+	return result, nil
+}
+
+func (r *InMemoryScoringVersionRepository) GetScoringVersion(_ context.Context, id int64) (*domain.ScoringVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if id < 1 || id > int64(len(r.versions)) {
+		return nil, errScoringNotFound
+	}
+	v := *r.versions[id-1]
+	return &v, nil
+}
+
+// compile-time check
+var _ domain.ScoringVersionRepository = (*InMemoryScoringVersionRepository)(nil)
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sync"
+)
+
+// InMemoryScreenerConfigRepository keeps the screener config in memory
+type InMemoryScreenerConfigRepository struct {
+	mu  sync.RWMutex
+	cfg *domain.ScreenerConfig
+}
+
+func NewInMemoryScreenerConfigRepository() *InMemoryScreenerConfigRepository {
+	return &InMemoryScreenerConfigRepository{}
+}
+
+func (r *InMemoryScreenerConfigRepository) GetScreenerConfig(_ context.Context) (*domain.ScreenerConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cfg == nil {
+		return nil, errNoScreenerConfig
+	}
+	copied := *r.cfg
+	return &copied, nil
+}
+
+func (r *InMemoryScreenerConfigRepository) SaveScreenerConfig(_ context.Context, cfg *domain.ScreenerConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *cfg
+	r.cfg = &stored
+	return nil
+}
+
+// compile-time check
+var _ domain.ScreenerConfigRepository = (*InMemoryScreenerConfigRepository)(nil)
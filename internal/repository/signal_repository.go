@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sync"
+	"time"
+)
+
+// maxSignals bounds the in-memory signal history
+const maxSignals = 20000
+
+// InMemorySignalRepository keeps recent signals in memory
+type InMemorySignalRepository struct {
+	mu      sync.RWMutex
+	signals []*domain.Signal // oldest first
+	nextID  int64
+}
+
+func NewInMemorySignalRepository() *InMemorySignalRepository {
+	return &InMemorySignalRepository{}
+}
+
+func (r *InMemorySignalRepository) SaveSignal(_ context.Context, signal *domain.Signal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	signal.ID = r.nextID
+	r.signals = append(r.signals, copySignal(signal))
+	if len(r.signals) > maxSignals {
+		r.signals = r.signals[len(r.signals)-maxSignals:]
+	}
+	return nil
+}
+
+func (r *InMemorySignalRepository) ListUnlabeledSignals(_ context.Context, limit int) ([]*domain.Signal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.Signal, 0)
+	for _, s := range r.signals {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		if !s.Labeled {
+			result = append(result, copySignal(s))
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemorySignalRepository) SaveOutcomes(_ context.Context, id int64, outcomes map[string]domain.SignalOutcome, labeled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.signals {
+		if s.ID == id {
+			s.Outcomes = make(map[string]domain.SignalOutcome, len(outcomes))
+			for h, o := range outcomes {
+				s.Outcomes[h] = o
+			}
+			s.Labeled = labeled
+			return nil
+		}
+	}
+	return errSignalNotFound
+}
+
+func (r *InMemorySignalRepository) ListSignals(_ context.Context, filter domain.SignalFilter) ([]*domain.Signal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.Signal, 0)
+	for i := len(r.signals) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+		s := r.signals[i]
+		if (filter.Strategy != "" && s.Strategy != filter.Strategy) ||
+			(filter.Symbol != "" && s.Symbol != filter.Symbol) ||
+			(!filter.From.IsZero() && s.SignaledAt.Before(filter.From)) ||
+			(!filter.To.IsZero() && s.SignaledAt.After(filter.To)) {
+			continue
+		}
+		result = append(result, copySignal(s))
+	}
+	return result, nil
+}
+
+func (r *InMemorySignalRepository) PruneSignals(_ context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.signals[:0]
+	for _, s := range r.signals {
+		if !s.SignaledAt.Before(before) {
+			kept = append(kept, s)
+		}
+	}
+	r.signals = kept
+	return nil
+}
+
+func copySignal(s *domain.Signal) *domain.Signal {
+	c := *s
+	c.Outcomes = make(map[string]domain.SignalOutcome, len(s.Outcomes))
+	for h, o := range s.Outcomes {
+		c.Outcomes[h] = o
+	}
+	return &c
+}
+
+// compile-time check
+var _ domain.SignalRepository = (*InMemorySignalRepository)(nil)
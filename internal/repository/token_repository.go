@@ -2,12 +2,14 @@ package repository
 
 import (
 	"sync"
+	"time"
 )
 
 // DeviceToken represents a registered device token
 type DeviceToken struct {
 	Token     string
 	Platform  string // "android" or "ios"
+	UserID    string // optional; enables per-user notifications
 	CreatedAt int64
 }
 
@@ -24,13 +26,14 @@ func NewTokenRepository() *TokenRepository {
 }
 
 // RegisterToken adds or updates a device token
-func (r *TokenRepository) RegisterToken(token, platform string, timestamp int64) {
+func (r *TokenRepository) RegisterToken(token, platform, userID string, timestamp int64) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	r.tokens[token] = &DeviceToken{
 		Token:     token,
 		Platform:  platform,
+		UserID:    userID,
 		CreatedAt: timestamp,
 	}
 }
@@ -62,3 +65,35 @@ func (r *TokenRepository) GetTokenCount() int {
 
 	return len(r.tokens)
 }
+
+// GetTokensForUser returns the tokens registered with the given user ID
+func (r *TokenRepository) GetTokensForUser(userID string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens := make([]string, 0)
+	for token, dt := range r.tokens {
+		if dt.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// PruneStale removes tokens that have not been re-registered within maxAge
+// and returns how many were dropped. Apps re-register on every launch, so a
+// token this old most likely belongs to an uninstalled app.
+func (r *TokenRepository) PruneStale(maxAge time.Duration) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().Add(-maxAge).Unix()
+	removed := 0
+	for token, dt := range r.tokens {
+		if dt.CreatedAt < cutoff {
+			delete(r.tokens, token)
+			removed++
+		}
+	}
+	return removed
+}
@@ -1,104 +1,202 @@
 package repository
 
 import (
-"fmt"
-"screener-backend/internal/domain"
-"sync"
+	"context"
+	"fmt"
+	"screener-backend/internal/domain"
+	"sort"
+	"sync"
+	"time"
 )
 
 // InMemoryTradeRepository stores trade entries in memory
 type InMemoryTradeRepository struct {
 	mu      sync.RWMutex
 	entries map[string]*domain.TradeEntry
-	history []*domain.TradeEntry
 }
 
 // NewInMemoryTradeRepository creates a new in-memory trade repository
 func NewInMemoryTradeRepository() domain.TradeEntryRepository {
 	return &InMemoryTradeRepository{
 		entries: make(map[string]*domain.TradeEntry),
-		history: make([]*domain.TradeEntry, 0),
 	}
 }
 
 // CreateEntry creates a new trade entry
-func (r *InMemoryTradeRepository) CreateEntry(entry *domain.TradeEntry) error {
+func (r *InMemoryTradeRepository) CreateEntry(_ context.Context, entry *domain.TradeEntry) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.entries[entry.ID]; exists {
-		return fmt.Errorf("entry with ID %s already exists", entry.ID)
+		return domain.Conflict("TRADE_EXISTS", fmt.Sprintf("entry with ID %s already exists", entry.ID))
 	}
 
 	r.entries[entry.ID] = entry
 	return nil
 }
 
-// GetActiveEntries returns all active trade entries
-func (r *InMemoryTradeRepository) GetActiveEntries() []*domain.TradeEntry {
+// GetActiveEntries returns the user's active trade entries
+func (r *InMemoryTradeRepository) GetActiveEntries(_ context.Context, userID string) []*domain.TradeEntry {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	active := make([]*domain.TradeEntry, 0)
 	for _, entry := range r.entries {
-		if entry.Status == "active" || entry.Status == "tp1_hit" || entry.Status == "tp2_hit" {
+		if entry.UserID == userID && entry.ArchivedAt == nil && isActiveStatus(entry.Status) {
 			active = append(active, entry)
 		}
 	}
+	sortByEntryTimeDesc(active)
 	return active
 }
 
-// GetEntryByID retrieves an entry by ID
-func (r *InMemoryTradeRepository) GetEntryByID(id string) (*domain.TradeEntry, error) {
+// GetAllActiveEntries returns active trade entries of every user
+func (r *InMemoryTradeRepository) GetAllActiveEntries(_ context.Context) []*domain.TradeEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	active := make([]*domain.TradeEntry, 0)
+	for _, entry := range r.entries {
+		if entry.ArchivedAt == nil && isActiveStatus(entry.Status) {
+			active = append(active, entry)
+		}
+	}
+	return active
+}
+
+// GetUserIDs returns every user that owns a journal entry
+func (r *InMemoryTradeRepository) GetUserIDs(_ context.Context) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, entry := range r.entries {
+		if entry.UserID != "" && !seen[entry.UserID] {
+			seen[entry.UserID] = true
+			ids = append(ids, entry.UserID)
+		}
+	}
+	return ids
+}
+
+func isActiveStatus(status string) bool {
+	return status == "active" || status == "tp1_hit" || status == "tp2_hit"
+}
+
+// GetEntryByID retrieves one of the user's (non-archived) entries by ID
+func (r *InMemoryTradeRepository) GetEntryByID(_ context.Context, userID, id string) (*domain.TradeEntry, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	entry, exists := r.entries[id]
-	if !exists {
-		return nil, fmt.Errorf("entry not found")
+	if !exists || entry.UserID != userID || entry.ArchivedAt != nil {
+		return nil, errTradeNotFound
 	}
 	return entry, nil
 }
 
-// UpdateEntry updates an existing entry
-func (r *InMemoryTradeRepository) UpdateEntry(entry *domain.TradeEntry) error {
+// UpdateEntry updates an existing (non-archived) entry
+func (r *InMemoryTradeRepository) UpdateEntry(_ context.Context, entry *domain.TradeEntry) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.entries[entry.ID]; !exists {
-		return fmt.Errorf("entry not found")
+	existing, exists := r.entries[entry.ID]
+	if !exists || existing.UserID != entry.UserID || existing.ArchivedAt != nil {
+		return errTradeNotFound
 	}
 
-	// If status changed to closed or stopped, move to history
-	if (entry.Status == "closed" || entry.Status == "stopped") && r.entries[entry.ID].Status != entry.Status {
-		r.history = append(r.history, entry)
-		delete(r.entries, entry.ID)
-		return nil
+	r.entries[entry.ID] = entry
+	return nil
+}
+
+// GetEntryHistory returns the user's closed/stopped/tp3_hit entries matching filter
+func (r *InMemoryTradeRepository) GetEntryHistory(_ context.Context, userID string, filter domain.TradeHistoryFilter) []*domain.TradeEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.TradeEntry, 0)
+	for _, entry := range r.entries {
+		if entry.UserID == userID && entry.ArchivedAt == nil && entry.IsClosed() && filter.Matches(entry) {
+			result = append(result, entry)
+		}
 	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].ExitTime, result[j].ExitTime
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.After(*b)
+	})
+	return result
+}
 
-	r.entries[entry.ID] = entry
+// ArchiveEntry soft-deletes one of the user's entries
+func (r *InMemoryTradeRepository) ArchiveEntry(_ context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, exists := r.entries[id]
+	if !exists || entry.UserID != userID || entry.ArchivedAt != nil {
+		return errTradeNotFound
+	}
+
+	archived := *entry
+	now := time.Now()
+	archived.ArchivedAt = &now
+	r.entries[id] = &archived
 	return nil
 }
 
-// GetEntryHistory returns all closed/stopped entries
-func (r *InMemoryTradeRepository) GetEntryHistory() []*domain.TradeEntry {
+// RestoreEntry brings back an archived entry
+func (r *InMemoryTradeRepository) RestoreEntry(_ context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, exists := r.entries[id]
+	if !exists || entry.UserID != userID || entry.ArchivedAt == nil {
+		return errArchivedTradeNotFound
+	}
+
+	restored := *entry
+	restored.ArchivedAt = nil
+	r.entries[id] = &restored
+	return nil
+}
+
+// GetArchivedEntries returns the user's archived entries, most recently archived first
+func (r *InMemoryTradeRepository) GetArchivedEntries(_ context.Context, userID string) []*domain.TradeEntry {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	result := make([]*domain.TradeEntry, len(r.history))
-	copy(result, r.history)
+	result := make([]*domain.TradeEntry, 0)
+	for _, entry := range r.entries {
+		if entry.UserID == userID && entry.ArchivedAt != nil {
+			result = append(result, entry)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ArchivedAt.After(*result[j].ArchivedAt)
+	})
 	return result
 }
 
-// DeleteEntry removes an entry
-func (r *InMemoryTradeRepository) DeleteEntry(id string) error {
+// PurgeEntry permanently removes an archived entry
+func (r *InMemoryTradeRepository) PurgeEntry(_ context.Context, userID, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.entries[id]; !exists {
-		return fmt.Errorf("entry not found")
+	entry, exists := r.entries[id]
+	if !exists || entry.UserID != userID || entry.ArchivedAt == nil {
+		return errArchivedTradeNotFound
 	}
 
 	delete(r.entries, id)
 	return nil
 }
+
+func sortByEntryTimeDesc(entries []*domain.TradeEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].EntryTime.After(entries[j].EntryTime)
+	})
+}
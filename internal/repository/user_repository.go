@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sync"
+)
+
+// InMemoryUserRepository keeps users in memory
+type InMemoryUserRepository struct {
+	mu      sync.RWMutex
+	byEmail map[string]*domain.User
+}
+
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{byEmail: make(map[string]*domain.User)}
+}
+
+func (r *InMemoryUserRepository) CreateUser(_ context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byEmail[user.Email]; ok {
+		return errEmailTaken
+	}
+	stored := *user
+	r.byEmail[user.Email] = &stored
+	return nil
+}
+
+func (r *InMemoryUserRepository) GetUserByEmail(_ context.Context, email string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, ok := r.byEmail[email]
+	if !ok {
+		return nil, errUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// compile-time check
+var _ domain.UserRepository = (*InMemoryUserRepository)(nil)
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"screener-backend/internal/domain"
+	"sort"
+	"sync"
+)
+
+// InMemoryWatchlistRepository keeps watchlists in memory
+type InMemoryWatchlistRepository struct {
+	mu    sync.RWMutex
+	items map[string]map[string]*domain.WatchlistItem // user -> symbol -> item
+}
+
+func NewInMemoryWatchlistRepository() *InMemoryWatchlistRepository {
+	return &InMemoryWatchlistRepository{items: make(map[string]map[string]*domain.WatchlistItem)}
+}
+
+func (r *InMemoryWatchlistRepository) ListWatchlist(_ context.Context, userID string) ([]*domain.WatchlistItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.WatchlistItem, 0, len(r.items[userID]))
+	for _, item := range r.items[userID] {
+		copied := *item
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Symbol < result[j].Symbol })
+	return result, nil
+}
+
+func (r *InMemoryWatchlistRepository) ListAllWatchlists(_ context.Context) ([]*domain.WatchlistItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.WatchlistItem, 0)
+	for _, items := range r.items {
+		for _, item := range items {
+			copied := *item
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UserID != result[j].UserID {
+			return result[i].UserID < result[j].UserID
+		}
+		return result[i].Symbol < result[j].Symbol
+	})
+	return result, nil
+}
+
+func (r *InMemoryWatchlistRepository) SaveWatchlistItem(_ context.Context, item *domain.WatchlistItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items, ok := r.items[item.UserID]
+	if !ok {
+		items = make(map[string]*domain.WatchlistItem)
+		r.items[item.UserID] = items
+	}
+	if prev, ok := items[item.Symbol]; ok {
+		item.CreatedAt = prev.CreatedAt
+	}
+	stored := *item
+	items[item.Symbol] = &stored
+	return nil
+}
+
+func (r *InMemoryWatchlistRepository) DeleteWatchlistItem(_ context.Context, userID, symbol string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[userID][symbol]; !ok {
+		return errWatchlistItemNotFound
+	}
+	delete(r.items[userID], symbol)
+	if len(r.items[userID]) == 0 {
+		delete(r.items, userID)
+	}
+	return nil
+}
+
+// compile-time check
+var _ domain.WatchlistRepository = (*InMemoryWatchlistRepository)(nil)
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+const (
+	tokenIssuer       = "screener-backend"
+	minPasswordLength = 8
+	maxPasswordLength = 72 // bcrypt ignores the bytes past 72
+)
+
+var (
+	ErrAuthEmail        = domain.Validation("AUTH_INVALID_EMAIL", "email is not a valid address")
+	ErrAuthPassword     = domain.Validation("AUTH_INVALID_PASSWORD", "password must be 8 to 72 characters")
+	ErrAuthLogin        = domain.Unauthorized("AUTH_INVALID_LOGIN", "wrong email or password")
+	ErrAuthInvalidToken = domain.Unauthorized("AUTH_INVALID_TOKEN", "token is invalid or expired")
+)
+
+// AuthToken is a signed token identifying a user, as returned by Register
+// and Login
+type AuthToken struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expiresAt"`
+	User      *domain.User `json:"user"`
+}
+
+// AuthService registers users and issues and verifies the HS256 JWTs that
+// identify them to the API. A token's subject is the user's ID.
+type AuthService struct {
+	users  domain.UserRepository
+	secret []byte
+	ttl    time.Duration
+
+	// dummyHash is compared against on unknown emails so a login takes as
+	// long whether or not the email is registered
+	dummyHash []byte
+}
+
+// NewAuthService creates an auth service signing with secret
+func NewAuthService(users domain.UserRepository, secret string, ttl time.Duration) *AuthService {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	return &AuthService{users: users, secret: []byte(secret), ttl: ttl, dummyHash: dummyHash}
+}
+
+// Register creates a user and logs them in
+func (s *AuthService) Register(ctx context.Context, email, password string) (*AuthToken, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return nil, ErrAuthPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{ID: uuid.NewString(), Email: email, PasswordHash: string(hash), CreatedAt: time.Now().UTC()}
+	if err := s.users.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	logging.Infof("Registered user %s", user.ID)
+	return s.issue(user)
+}
+
+// Login checks the email and password and issues a token
+func (s *AuthService) Login(ctx context.Context, email, password string) (*AuthToken, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, ErrAuthLogin
+	}
+	user, err := s.users.GetUserByEmail(ctx, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+		return nil, ErrAuthLogin
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrAuthLogin
+	}
+	return s.issue(user)
+}
+
+// ParseToken verifies a token and returns the ID of the user it identifies
+func (s *AuthService) ParseToken(token string) (string, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || claims.Subject == "" || !claims.VerifyIssuer(tokenIssuer, true) {
+		return "", ErrAuthInvalidToken
+	}
+	return claims.Subject, nil
+}
+
+func (s *AuthService) issue(user *domain.User) (*AuthToken, error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.ttl)
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    tokenIssuer,
+		Subject:   user.ID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}).SignedString(s.secret)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthToken{Token: signed, ExpiresAt: expiresAt, User: user}, nil
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrAuthEmail
+	}
+	return email, nil
+}
@@ -1,10 +1,15 @@
 package usecase
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"math"
 	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/indicators"
+	"screener-backend/internal/infrastructure/logging"
+	"sync"
 	"time"
 )
 
@@ -12,79 +17,265 @@ import (
 type AutoScalpingService struct {
 	repo          domain.AutoScalpRepository
 	screeningRepo domain.ScreenerRepository
-	settings      *domain.AutoScalpSettings
-	priceCache    map[string]float64 // symbol -> current price
+	priceCache    domain.PriceCache  // symbol -> current price
+	chandelier    map[string]float64 // symbol -> Chandelier Exit (short) of the primary TF
+	events        domain.EventPublisher
+	regime        *RegimeFilter
+	blackouts     *BlackoutCalendar
+	fills         *FillSimulator
+	trading       *BinanceTradingService // real orders for users with real trading enabled
+	walkedUntil   map[string]time.Time   // entry ID -> end of the last closed candle walked
+	locksMu       sync.Mutex
+	userLocks     map[string]*sync.Mutex // user ID -> held while their entries are monitored or emergency stopped
 }
 
+// autoScalpNotional is the USDT size paper positions are assumed to have
+const autoScalpNotional = 100
+
+var ErrTrailingMode = domain.Validation("AUTOSCALP_INVALID_TRAILING_MODE", "trailingMode must be percent or chandelier")
+
 // NewAutoScalpingService creates a new auto scalping service
 func NewAutoScalpingService(
 	repo domain.AutoScalpRepository,
 	screeningRepo domain.ScreenerRepository,
+	priceCache domain.PriceCache,
+	events domain.EventPublisher,
+	regime *RegimeFilter,
+	blackouts *BlackoutCalendar,
+	fills *FillSimulator,
+	trading *BinanceTradingService,
 ) *AutoScalpingService {
 	return &AutoScalpingService{
 		repo:          repo,
 		screeningRepo: screeningRepo,
-		priceCache:    make(map[string]float64),
-		settings: &domain.AutoScalpSettings{
-			Enabled:              false, // Start disabled
-			MaxConcurrentTrades:  3,
-			MinEntryScore:        75,    // Only TRIGGER level
-			StopLossPercent:      0.4,   // 0.4% SL - tight but reasonable
-			MinProfitPercent:     0.3,   // Start trailing at 0.3% profit
-			TrailingStopPercent:  0.15,  // Trail by 0.15% from peak
-			MaxPositionTime:      1800,  // 30 minutes max
-		},
+		priceCache:    priceCache,
+		chandelier:    make(map[string]float64),
+		events:        events,
+		regime:        regime,
+		blackouts:     blackouts,
+		fills:         fills,
+		trading:       trading,
+		walkedUntil:   make(map[string]time.Time),
+		userLocks:     make(map[string]*sync.Mutex),
+	}
+}
+
+// lockUser serializes the monitor's run for a user with their EmergencyStop;
+// it returns the unlock function
+func (s *AutoScalpingService) lockUser(userID string) func() {
+	s.locksMu.Lock()
+	l, ok := s.userLocks[userID]
+	if !ok {
+		l = &sync.Mutex{}
+		s.userLocks[userID] = l
 	}
+	s.locksMu.Unlock()
+	l.Lock()
+	return l.Unlock
 }
 
-// GetSettings returns current settings
-func (s *AutoScalpingService) GetSettings() *domain.AutoScalpSettings {
-	return s.settings
+// DefaultAutoScalpSettings are the settings the auto scalper starts with
+func DefaultAutoScalpSettings() *domain.AutoScalpSettings {
+	return &domain.AutoScalpSettings{
+		Enabled:              false, // Start disabled
+		MaxConcurrentTrades:  3,
+		MinEntryScore:        75,    // Only TRIGGER level
+		StopLossPercent:      0.4,   // 0.4% SL - tight but reasonable
+		MinProfitPercent:     0.3,   // Start trailing at 0.3% profit
+		TrailingStopPercent:  0.15,  // Trail by 0.15% from peak
+		MaxPositionTime:      1800,  // 30 minutes max
+		TrailingMode:         domain.TrailingModePercent,
+	}
+}
+
+// GetSettings returns the user's settings, the defaults before their first save
+func (s *AutoScalpingService) GetSettings(ctx context.Context, userID string) (*domain.AutoScalpSettings, error) {
+	settings, err := s.repo.GetSettings(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		settings = DefaultAutoScalpSettings()
+		settings.UserID = userID
+		return settings, nil
+	}
+	return settings, err
 }
 
-// GetActivePositions returns all active positions
-func (s *AutoScalpingService) GetActivePositions() []*domain.AutoScalpEntry {
-	return s.repo.GetActiveEntries()
+// GetActivePositions returns the user's active positions
+func (s *AutoScalpingService) GetActivePositions(ctx context.Context, userID string) []*domain.AutoScalpEntry {
+	return s.repo.GetActiveEntries(ctx, userID)
 }
 
-// GetHistory returns history for a period
-func (s *AutoScalpingService) GetHistory(fromTime time.Time) []*domain.AutoScalpEntry {
-	return s.repo.GetHistory(fromTime)
+// GetHistory returns the user's history for a period
+func (s *AutoScalpingService) GetHistory(ctx context.Context, userID string, fromTime time.Time) []*domain.AutoScalpEntry {
+	return s.repo.GetHistory(ctx, userID, fromTime)
 }
 
-// UpdateSettings updates auto scalping settings
-func (s *AutoScalpingService) UpdateSettings(settings *domain.AutoScalpSettings) {
-	s.settings = settings
+// UpdateSettings validates and stores a user's settings; the next monitor
+// run trades with them
+func (s *AutoScalpingService) UpdateSettings(ctx context.Context, settings *domain.AutoScalpSettings) error {
+	switch settings.TrailingMode {
+	case "":
+		settings.TrailingMode = domain.TrailingModePercent
+	case domain.TrailingModePercent, domain.TrailingModeChandelier:
+	default:
+		return ErrTrailingMode
+	}
+	settings.UpdatedAt = time.Now().UTC()
+	if err := s.repo.SaveSettings(ctx, settings); err != nil {
+		return err
+	}
+	logging.Infof("Auto scalp settings updated: user=%s enabled=%t maxTrades=%d minScore=%.0f",
+		settings.UserID, settings.Enabled, settings.MaxConcurrentTrades, settings.MinEntryScore)
+	return nil
+}
+
+// EmergencyStop turns the user's auto scalping off, flattens their Binance
+// account (see BinanceTradingService.EmergencyStopAll) and closes their
+// active entries with EMERGENCY_STOP. It holds the user's lock, so the
+// monitor neither opens nor closes their entries meanwhile, and turns auto
+// scalping off first; it stays off when the exchange part fails.
+func (s *AutoScalpingService) EmergencyStop(ctx context.Context, userID, reason string) (*EmergencyStopResult, error) {
+	if s.trading == nil {
+		return nil, ErrMissingCredentials
+	}
+	unlock := s.lockUser(userID)
+	defer unlock()
+
+	settings, err := s.repo.GetSettings(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	disabled := false
+	if settings != nil && settings.Enabled {
+		settings.Enabled = false
+		if err := s.UpdateSettings(ctx, settings); err != nil {
+			return nil, err
+		}
+		disabled = true
+	}
+
+	result, err := s.trading.EmergencyStopAll(ctx, userID, reason)
+	if err != nil {
+		return nil, err
+	}
+	result.AutoScalpDisabled = disabled
+
+	// Real entries close at their position's fill, paper ones at the last
+	// screened price. A real entry whose position failed to close stays
+	// active for the next stop.
+	filled := make(map[string]float64)
+	stillOpen := make(map[string]bool)
+	for _, pos := range result.Positions {
+		switch {
+		case pos.Error != "":
+			stillOpen[pos.Symbol] = true
+		case pos.FilledPrice > 0:
+			filled[pos.Symbol] = pos.FilledPrice
+		}
+	}
+	prices := make(map[string]float64)
+	for _, coin := range s.screeningRepo.GetCoins() {
+		prices[coin.Symbol] = coin.Price
+	}
+	for _, entry := range s.repo.GetActiveEntries(ctx, userID) {
+		if entry.IsRealTrade && stillOpen[entry.Symbol] {
+			continue
+		}
+		exitPrice, ok := filled[entry.Symbol]
+		if !ok || !entry.IsRealTrade {
+			if exitPrice, ok = prices[entry.Symbol]; !ok {
+				exitPrice = entry.EntryPrice
+			}
+		}
+		s.recordClose(context.WithoutCancel(ctx), entry, exitPrice, "EMERGENCY_STOP")
+		result.ClosedEntries++
+	}
+	return result, nil
 }
 
 // MonitorAndExecute checks for entry/exit opportunities (called periodically)
-func (s *AutoScalpingService) MonitorAndExecute() {
-	if !s.settings.Enabled {
-		return
+// for every user with auto scalping enabled, each with their own settings.
+// Entries without an owner, opened before settings were per user, are only
+// exited, under the default settings the service used to run globally.
+// Each user is handled under their lock with their settings and entries as
+// of then, so one stopped while the run was under way is skipped.
+// It returns ctx's error when the run was cut short by its deadline.
+func (s *AutoScalpingService) MonitorAndExecute(ctx context.Context) error {
+	enabled, err := s.repo.ListEnabledSettings(ctx)
+	if err != nil {
+		return err
+	}
+	activeEntries := s.repo.GetAllActiveEntries(ctx)
+	var legacy []*domain.AutoScalpEntry
+	for _, entry := range activeEntries {
+		if entry.UserID == "" {
+			legacy = append(legacy, entry)
+		}
+	}
+	if len(enabled) == 0 && len(legacy) == 0 {
+		return nil
 	}
 
 	// Update price cache
 	s.updatePriceCache()
+	s.pruneWalked(activeEntries)
+
+	if len(legacy) > 0 {
+		s.checkExits(ctx, DefaultAutoScalpSettings(), legacy)
+	}
+
+	for _, settings := range enabled {
+		s.monitorUser(ctx, settings.UserID)
+	}
+	return ctx.Err()
+}
+
+// monitorUser checks a user's exits and entries under their lock, if they
+// still have auto scalping enabled
+func (s *AutoScalpingService) monitorUser(ctx context.Context, userID string) {
+	unlock := s.lockUser(userID)
+	defer unlock()
+
+	settings, err := s.repo.GetSettings(ctx, userID)
+	if err != nil || !settings.Enabled {
+		return
+	}
 
 	// Check for exits on active trades
-	s.checkExits()
+	s.checkExits(ctx, settings, s.repo.GetActiveEntries(ctx, userID))
 
 	// Check for new entries
-	s.checkEntries()
+	s.checkEntries(ctx, settings)
+}
+
+// stillEnabled re-reads whether the user has auto scalping enabled, for
+// settings changed (or stopped from another instance) since the run read them
+func (s *AutoScalpingService) stillEnabled(ctx context.Context, userID string) bool {
+	settings, err := s.repo.GetSettings(ctx, userID)
+	return err == nil && settings.Enabled
 }
 
 func (s *AutoScalpingService) updatePriceCache() {
 	coins := s.screeningRepo.GetCoins()
+	prices := make(map[string]float64, len(coins))
 	for _, coin := range coins {
-		s.priceCache[coin.Symbol] = coin.Price
+		prices[coin.Symbol] = coin.Price
+		if coin.Features != nil && coin.Features.ChandelierShort > 0 {
+			s.chandelier[coin.Symbol] = coin.Features.ChandelierShort
+		}
 	}
+	s.priceCache.SetPrices(prices)
 }
 
-func (s *AutoScalpingService) checkExits() {
-	activeEntries := s.repo.GetActiveEntries()
-	
+// checkExits manages a user's active entries with their settings
+func (s *AutoScalpingService) checkExits(ctx context.Context, settings *domain.AutoScalpSettings, activeEntries []*domain.AutoScalpEntry) {
 	for _, entry := range activeEntries {
-		currentPrice, exists := s.priceCache[entry.Symbol]
+		// Paper positions are filled from the candles traded since the last
+		// check; the sampled price below is the fallback
+		if !entry.IsRealTrade && s.fills.Enabled() && s.checkPaperExit(ctx, entry, settings) {
+			continue
+		}
+
+		currentPrice, exists := s.priceCache.GetPrice(entry.Symbol)
 		if !exists {
 			continue
 		}
@@ -95,25 +286,32 @@ func (s *AutoScalpingService) checkExits() {
 			entry.HighestPrice = currentPrice
 		}
 
-		shouldExit, reason := s.shouldExit(entry, currentPrice)
+		if settings.TrailingMode == domain.TrailingModeChandelier {
+			s.ratchetChandelierStop(entry, currentPrice, settings)
+		}
+
+		shouldExit, reason := s.shouldExit(entry, currentPrice, settings)
 		if shouldExit {
-			s.closePosition(entry, currentPrice, reason)
+			s.closePosition(ctx, entry, currentPrice, reason)
 		} else {
 			// Update entry with new highest price
-			s.repo.UpdateEntry(entry)
+			s.repo.UpdateEntry(ctx, entry)
 		}
 	}
 }
 
-func (s *AutoScalpingService) shouldExit(entry *domain.AutoScalpEntry, currentPrice float64) (bool, string) {
-	// 1. Check Stop Loss
+func (s *AutoScalpingService) shouldExit(entry *domain.AutoScalpEntry, currentPrice float64, settings *domain.AutoScalpSettings) (bool, string) {
+	// 1. Check Stop Loss (a stop trailed below entry exits as a trailing stop)
 	if currentPrice >= entry.StopLoss {
+		if entry.StopLoss < entry.EntryPrice {
+			return true, "TRAILING_STOP"
+		}
 		return true, "SL_HIT"
 	}
 
 	// 2. Check max position time
 	duration := time.Since(entry.EntryTime).Seconds()
-	if int(duration) >= s.settings.MaxPositionTime {
+	if int(duration) >= settings.MaxPositionTime {
 		return true, "MAX_TIME"
 	}
 
@@ -122,28 +320,155 @@ func (s *AutoScalpingService) shouldExit(entry *domain.AutoScalpEntry, currentPr
 
 	// 4. Dynamic trailing stop logic
 	// Once we hit minimum profit, activate trailing stop
-	if profitPct >= s.settings.MinProfitPercent {
+	// (chandelier mode trails through the stop loss instead, see ratchetChandelierStop)
+	if settings.TrailingMode != domain.TrailingModeChandelier && profitPct >= settings.MinProfitPercent {
 		// Calculate peak profit
 		peakProfitPct := ((entry.EntryPrice - entry.HighestPrice) / entry.EntryPrice) * 100
 		
 		// If price retraces from peak by trailing stop %, exit
 		retraceFromPeak := peakProfitPct - profitPct
-		if retraceFromPeak >= s.settings.TrailingStopPercent {
+		if retraceFromPeak >= settings.TrailingStopPercent {
 			return true, "TRAILING_STOP"
 		}
 	}
 
-	// 5. Emergency exit if profit turns negative (price went up beyond entry)
-	if profitPct < -s.settings.StopLossPercent {
+	// 5. Emergency exit if profit turns negative (price went up beyond entry);
+	// never tighter than the (volatility-scaled) stop the entry was opened with
+	emergencyPct := settings.StopLossPercent
+	if stopPct := ((entry.StopLoss - entry.EntryPrice) / entry.EntryPrice) * 100; stopPct > emergencyPct {
+		emergencyPct = stopPct
+	}
+	if profitPct < -emergencyPct {
 		return true, "EMERGENCY_EXIT"
 	}
 
 	return false, ""
 }
 
-func (s *AutoScalpingService) closePosition(entry *domain.AutoScalpEntry, exitPrice float64, reason string) {
+// checkPaperExit walks the 1m candles closed since the position was last
+// checked, plus the forming one, against its resting stop. It returns false
+// when no candle after the entry's own minute could be loaded, leaving the
+// position to the sampled-price check.
+func (s *AutoScalpingService) checkPaperExit(ctx context.Context, entry *domain.AutoScalpEntry, settings *domain.AutoScalpSettings) bool {
+	since, ok := s.walkedUntil[entry.ID]
+	if !ok {
+		// The entry's own candle traded partly before the entry
+		since = entry.EntryTime.Truncate(time.Minute).Add(time.Minute)
+	}
+	candles, err := s.fills.Candles(ctx, entry.Symbol, since)
+	if err != nil {
+		logging.Debugf("Fill simulation: %s candles: %v", entry.Symbol, err)
+		return false
+	}
+	if len(candles) == 0 {
+		return false
+	}
+
+	for _, c := range candles {
+		// The stop is checked against the levels of the previous candles: a
+		// candle's own low may have come after its high
+		stop, reason := s.paperStop(entry, settings)
+		if price, hit := stopFill(c, stop, true); hit {
+			s.closePosition(ctx, entry, s.fills.Slip(ctx, entry.Symbol, price, autoScalpNotional, true), reason)
+			return true
+		}
+		if !s.fills.candleClosed(c) {
+			break
+		}
+		if c.Low < entry.HighestPrice {
+			entry.HighestPrice = c.Low
+		}
+		if settings.TrailingMode == domain.TrailingModeChandelier {
+			s.ratchetChandelierStop(entry, c.Close, settings)
+		}
+		s.walkedUntil[entry.ID] = c.OpenTime.Add(time.Minute)
+	}
+
+	if int(time.Since(entry.EntryTime).Seconds()) >= settings.MaxPositionTime {
+		s.closePosition(ctx, entry, candles[len(candles)-1].Close, "MAX_TIME")
+		return true
+	}
+	s.repo.UpdateEntry(ctx, entry)
+	return true
+}
+
+// paperStop is the resting stop of a paper SHORT and the reason it exits
+// with: the stop loss, in percent mode tightened to TrailingStopPercent above
+// the low once the position has reached MinProfitPercent
+func (s *AutoScalpingService) paperStop(entry *domain.AutoScalpEntry, settings *domain.AutoScalpSettings) (float64, string) {
+	stop := entry.StopLoss
+	if settings.TrailingMode != domain.TrailingModeChandelier {
+		peakProfitPct := ((entry.EntryPrice - entry.HighestPrice) / entry.EntryPrice) * 100
+		if peakProfitPct >= settings.MinProfitPercent {
+			if trail := entry.HighestPrice + entry.EntryPrice*settings.TrailingStopPercent/100; trail < stop {
+				stop = trail
+			}
+		}
+	}
+	if stop < entry.EntryPrice {
+		return stop, "TRAILING_STOP"
+	}
+	return stop, "SL_HIT"
+}
+
+// pruneWalked forgets the candle progress of positions no longer active
+func (s *AutoScalpingService) pruneWalked(active []*domain.AutoScalpEntry) {
+	ids := make(map[string]bool, len(active))
+	for _, e := range active {
+		ids[e.ID] = true
+	}
+	for id := range s.walkedUntil {
+		if !ids[id] {
+			delete(s.walkedUntil, id)
+		}
+	}
+}
+
+// ratchetChandelierStop moves the stop loss down to the Chandelier Exit once
+// the position reached MinProfitPercent. The stop only ever tightens.
+func (s *AutoScalpingService) ratchetChandelierStop(entry *domain.AutoScalpEntry, currentPrice float64, settings *domain.AutoScalpSettings) {
+	stop, ok := s.chandelier[entry.Symbol]
+	if !ok || stop <= currentPrice {
+		return
+	}
+	peakProfitPct := ((entry.EntryPrice - entry.HighestPrice) / entry.EntryPrice) * 100
+	if peakProfitPct < settings.MinProfitPercent {
+		return
+	}
+	if stop < entry.StopLoss {
+		entry.StopLoss = stop
+	}
+}
+
+func (s *AutoScalpingService) closePosition(ctx context.Context, entry *domain.AutoScalpEntry, exitPrice float64, reason string) {
+	// A real position is closed on Binance first; if that fails it stays
+	// active and the next run tries again
+	if entry.IsRealTrade && s.trading != nil {
+		var exitOrderIDs []int64
+		for _, id := range []*int64{entry.BinanceSLOrderID, entry.BinanceTPOrderID} {
+			if id != nil {
+				exitOrderIDs = append(exitOrderIDs, *id)
+			}
+		}
+		filledPrice, err := s.trading.CloseShort(ctx, entry.UserID, entry.Symbol, entry.Quantity, exitOrderIDs...)
+		if err != nil {
+			logging.Warnf("Error closing real position %s (%s): %v", entry.ID, entry.Symbol, err)
+			return
+		}
+		if filledPrice > 0 {
+			exitPrice = filledPrice
+		}
+	}
+	s.recordClose(ctx, entry, exitPrice, reason)
+}
+
+// recordClose marks an entry closed at exitPrice, its position already gone
+func (s *AutoScalpingService) recordClose(ctx context.Context, entry *domain.AutoScalpEntry, exitPrice float64, reason string) {
 	now := time.Now()
 	pl := (entry.EntryPrice - exitPrice) * 100 // Assuming position size 100 USDT
+	if entry.IsRealTrade && entry.Quantity > 0 {
+		pl = (entry.EntryPrice - exitPrice) * entry.Quantity
+	}
 	plPct := ((entry.EntryPrice - exitPrice) / entry.EntryPrice) * 100
 	duration := int(now.Sub(entry.EntryTime).Seconds())
 
@@ -155,49 +480,68 @@ func (s *AutoScalpingService) closePosition(entry *domain.AutoScalpEntry, exitPr
 	entry.DurationSeconds = duration
 	entry.Status = "CLOSED"
 
-	if err := s.repo.UpdateEntry(entry); err != nil {
-		log.Printf("Error closing position %s: %v", entry.ID, err)
+	if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+		logging.Warnf("Error closing position %s: %v", entry.ID, err)
 	} else {
-		log.Printf("✓ Auto scalp closed: %s | %s | P/L: %.2f%% | Duration: %ds | Reason: %s",
+		logging.Infof("✓ Auto scalp closed: %s | %s | P/L: %.2f%% | Duration: %ds | Reason: %s",
 			entry.Symbol, entry.ID, plPct, duration, reason)
+		s.publish(ctx, "closed", entry)
+	}
+}
+
+// publish sends a position change to the WebSocket clients of every instance
+func (s *AutoScalpingService) publish(ctx context.Context, event string, entry *domain.AutoScalpEntry) {
+	payload, err := json.Marshal(domain.AutoScalpEvent{Type: domain.TopicAutoScalp, Event: event, Entry: entry})
+	if err != nil {
+		logging.Warnf("Encoding autoscalp event failed: %v", err)
+		return
+	}
+	if err := s.events.Publish(ctx, domain.TopicAutoScalp, payload); err != nil {
+		logging.Warnf("Publishing autoscalp event failed: %v", err)
 	}
 }
 
-func (s *AutoScalpingService) checkEntries() {
+// checkEntries opens positions for a user up to their MaxConcurrentTrades
+func (s *AutoScalpingService) checkEntries(ctx context.Context, settings *domain.AutoScalpSettings) {
+	// No new positions during an event blackout; open ones are still managed
+	if w := s.blackouts.Active(ctx); w != nil {
+		logging.Debugf("Auto scalp entries paused: %s blackout %q until %s", w.Kind, w.Name, w.End.Format(time.RFC3339))
+		return
+	}
+
 	// Check if we can add more positions
-	activeCount := len(s.repo.GetActiveEntries())
-	if activeCount >= s.settings.MaxConcurrentTrades {
+	held := make(map[string]bool)
+	for _, entry := range s.repo.GetActiveEntries(ctx, settings.UserID) {
+		held[entry.Symbol] = true
+	}
+	activeCount := len(held)
+	if activeCount >= settings.MaxConcurrentTrades {
 		return
 	}
 
 	// Get high-score coins
 	coins := s.screeningRepo.GetCoins()
 	for _, coin := range coins {
-		if activeCount >= s.settings.MaxConcurrentTrades {
+		if activeCount >= settings.MaxConcurrentTrades {
 			break
 		}
 
 		// Check if already have position in this symbol
-		if s.hasActivePosition(coin.Symbol) {
+		if held[coin.Symbol] {
 			continue
 		}
 
 		// Check entry criteria
 		if s.shouldEnter(&coin) {
-			s.openPosition(&coin)
-			activeCount++
-		}
-	}
-}
-
-func (s *AutoScalpingService) hasActivePosition(symbol string) bool {
-	activeEntries := s.repo.GetActiveEntries()
-	for _, entry := range activeEntries {
-		if entry.Symbol == symbol {
-			return true
+			if !s.stillEnabled(ctx, settings.UserID) {
+				return
+			}
+			if s.openPosition(ctx, &coin, settings) {
+				held[coin.Symbol] = true
+				activeCount++
+			}
 		}
 	}
-	return false
 }
 
 func (s *AutoScalpingService) shouldEnter(coin *domain.CoinData) bool {
@@ -231,8 +575,10 @@ func (s *AutoScalpingService) shouldEnter(coin *domain.CoinData) bool {
 		reversalSigns++
 	}
 
-	// 4. Breakdown signal (price rejecting higher level)
-	if features.IsBreakdown {
+	// 4. Breakdown signal (price rejecting higher level) or a fresh bearish
+	// change of character (uptrend structure just broke)
+	if features.IsBreakdown ||
+		(features.LastStructureBreak.IsRecent("BEARISH", 3) && features.LastStructureBreak.Type == "CHOCH") {
 		reversalSigns++
 	}
 
@@ -246,9 +592,17 @@ func (s *AutoScalpingService) shouldEnter(coin *domain.CoinData) bool {
 		reversalSigns++
 	}
 
-	// Need at least 2 reversal confirmation signs
-	if reversalSigns >= 2 {
-		log.Printf("🎯 Auto scalp entry candidate: %s | RSI: %.1f | Reversal signs: %d | Price: %.6f",
+	// Need at least 2 reversal confirmation signs; against a strong BTC
+	// uptrend the regime filter asks for one more or blocks the entry
+	minSigns := 2
+	switch s.regime.ShortFilter() {
+	case domain.RegimeFilterSuppress:
+		return false
+	case domain.RegimeFilterDownweight:
+		minSigns = 3
+	}
+	if reversalSigns >= minSigns {
+		logging.Debugf("🎯 Auto scalp entry candidate: %s | RSI: %.1f | Reversal signs: %d | Price: %.6f",
 			coin.Symbol, features.RSI, reversalSigns, coin.Price)
 		return true
 	}
@@ -256,12 +610,19 @@ func (s *AutoScalpingService) shouldEnter(coin *domain.CoinData) bool {
 	return false
 }
 
-func (s *AutoScalpingService) openPosition(coin *domain.CoinData) {
+// openPosition opens a position for the user of settings; it reports whether
+// the entry was stored
+func (s *AutoScalpingService) openPosition(ctx context.Context, coin *domain.CoinData, settings *domain.AutoScalpSettings) bool {
 	entryPrice := coin.Price
-	stopLoss := entryPrice * (1 + s.settings.StopLossPercent/100)
+	stopPct := settings.StopLossPercent
+	if coin.Features != nil {
+		stopPct *= indicators.VolatilityStopMultiplier(coin.Features.VolatilityRegime)
+	}
+	stopLoss := entryPrice * (1 + stopPct/100)
 	
 	entry := &domain.AutoScalpEntry{
 		ID:              fmt.Sprintf("%d", time.Now().UnixNano()),
+		UserID:          settings.UserID,
 		Symbol:          coin.Symbol,
 		EntryPrice:      entryPrice,
 		StopLoss:        stopLoss,
@@ -269,21 +630,67 @@ func (s *AutoScalpingService) openPosition(coin *domain.CoinData) {
 		Status:          "ACTIVE",
 		EntryScore:      coin.Score,
 		HighestPrice:    entryPrice, // Initialize with entry price
-		TrailingStopPct: s.settings.TrailingStopPercent,
+		TrailingStopPct: settings.TrailingStopPercent,
 	}
 
-	if err := s.repo.CreateEntry(entry); err != nil {
-		log.Printf("Error creating auto scalp entry: %v", err)
-		return
+	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+		logging.Warnf("Error creating auto scalp entry: %v", err)
+		return false
+	}
+	if s.trading != nil && !s.openRealPosition(ctx, entry) {
+		return false
 	}
 
-	log.Printf("🎯 Auto scalp opened: %s | Score: %.0f | Entry: $%.4f | SL: $%.4f",
-		coin.Symbol, coin.Score, entryPrice, stopLoss)
+	logging.Infof("🎯 Auto scalp opened: %s | %s | Score: %.0f | Entry: $%.4f | SL: $%.4f",
+		coin.Symbol, settings.UserID, coin.Score, entryPrice, stopLoss)
+	s.publish(ctx, "opened", entry)
+	return true
+}
+
+// openRealPosition places the entry's SHORT with its stop loss and take profit
+// on Binance when the user's trading config has real trading enabled, and
+// reports whether the entry stays open. An entry that could not be placed with
+// both exits removes the entry; a SHORT left open without them is kept,
+// guarded by the entry's own stop.
+func (s *AutoScalpingService) openRealPosition(ctx context.Context, entry *domain.AutoScalpEntry) bool {
+	// Trade amount, leverage and take profit come from the trading config
+	b, err := s.trading.PlaceShortBracket(ctx, entry.UserID, entry.Symbol, entry.EntryPrice, entry.StopLoss, 0, 0, 0)
+	switch {
+	case errors.Is(err, ErrRealTradingDisabled):
+		return true
+	case err != nil && b == nil:
+		logging.Warnf("Auto scalp entry order failed: %s | %s: %v", entry.Symbol, entry.UserID, err)
+		if err := s.repo.DeleteEntry(context.WithoutCancel(ctx), entry.ID); err != nil {
+			logging.Warnf("Error removing auto scalp entry %s: %v", entry.ID, err)
+		}
+		return false
+	case err != nil:
+		logging.Warnf("Auto scalp %s | %s is open on Binance without its exits: %v", entry.Symbol, entry.UserID, err)
+		entry.IsRealTrade = true
+		entry.BinanceOrderID = &b.EntryOrderID
+		entry.Quantity = b.Quantity
+		entry.Leverage = b.Leverage
+		if err := s.repo.UpdateEntry(context.WithoutCancel(ctx), entry); err != nil {
+			logging.Warnf("Error updating auto scalp entry %s: %v", entry.ID, err)
+		}
+		return true
+	}
+
+	// The orders were attached to the stored entry
+	if attached, err := s.repo.GetEntryByID(ctx, entry.ID); err == nil {
+		*entry = *attached
+	}
+	return true
+}
+
+// GetEquityCurve returns cumulative P/L and drawdown of the user's trades closed since fromTime
+func (s *AutoScalpingService) GetEquityCurve(ctx context.Context, userID string, fromTime time.Time) *domain.EquityCurve {
+	return BuildEquityCurve(AutoScalpEquityPoints(s.repo.GetHistory(ctx, userID, fromTime)))
 }
 
-// GetStatistics calculates performance stats for a time period
-func (s *AutoScalpingService) GetStatistics(fromTime time.Time) map[string]interface{} {
-	history := s.repo.GetHistory(fromTime)
+// GetStatistics calculates the user's performance stats for a time period
+func (s *AutoScalpingService) GetStatistics(ctx context.Context, userID string, fromTime time.Time) map[string]interface{} {
+	history := s.repo.GetHistory(ctx, userID, fromTime)
 	
 	if len(history) == 0 {
 		return map[string]interface{}{
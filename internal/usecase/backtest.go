@@ -0,0 +1,670 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+	"screener-backend/internal/infrastructure/indicators"
+)
+
+const (
+	// backtestWarmupCandles is the history loaded before the first step: the
+	// candles the strategies read of their slowest timeframe
+	backtestWarmupCandles = 100
+	maxBacktestRange      = 7 * 24 * time.Hour
+	defaultBacktestStep   = 5 * time.Minute
+	// maxBacktestSteps keeps a run within an API request's time: each step
+	// runs every strategy on every interval
+	maxBacktestSteps = 3000
+	// backtestNotional is the USDT size of every simulated trade
+	backtestNotional = 100
+)
+
+// Strategies the backtester trades
+const (
+	BacktestReversal  = "reversal"  // SHORT on a reversal TRIGGER
+	BacktestPullback  = "pullback"  // LONG on a pullback DIP
+	BacktestBreakout  = "breakout"  // LONG or SHORT on a confirmed breakout
+	BacktestAutoScalp = "autoscalp" // SHORT on the auto scalper's entry rules
+)
+
+var (
+	ErrBacktestSymbol    = domain.Validation("BACKTEST_SYMBOL_REQUIRED", "symbol is required")
+	ErrBacktestStrategy  = domain.Validation("BACKTEST_INVALID_STRATEGY", "strategy must be reversal, pullback, breakout or autoscalp")
+	ErrBacktestRange     = domain.Validation("BACKTEST_INVALID_RANGE", "from must be before to and at most 7 days apart")
+	ErrBacktestStep      = domain.Validation("BACKTEST_INVALID_STEP", "step must be a whole number of minutes")
+	ErrBacktestSteps     = domain.Validation("BACKTEST_TOO_MANY_STEPS", fmt.Sprintf("the range takes more than %d steps; shorten it or lengthen the step", maxBacktestSteps))
+	ErrBacktestExit      = domain.Validation("BACKTEST_INVALID_EXIT", "stopLossPercent, minProfitPercent, trailingStopPercent and maxPositionTime must be positive")
+	ErrBacktestFee       = domain.Validation("BACKTEST_INVALID_FEE", "feeBps must be between 0 and 100")
+	ErrBacktestNoCandles = domain.Validation("BACKTEST_NO_CANDLES", "no 1m candles in the range")
+)
+
+// BacktestRequest parameterizes a backtest; zero values take the defaults
+type BacktestRequest struct {
+	Symbol   string
+	Strategy string
+	// From and To bound the steps; with Candles they default to the range of
+	// the candles less the warmup
+	From time.Time
+	To   time.Time
+	Step time.Duration // how often the strategies are run (default 5m)
+	// Candles are 1m candles to replay instead of downloading them, e.g. from
+	// ParseCandleCSV
+	Candles []domain.Candle
+	Exit    *domain.AutoScalpSettings // stop and trailing rules; nil takes the auto scalper's defaults
+	FeeBps  float64                   // charged on the entry and the exit notional
+}
+
+// BacktestTrade is one simulated position of backtestNotional USDT
+type BacktestTrade struct {
+	Side            string    `json:"side"` // LONG or SHORT
+	EntryTime       time.Time `json:"entryTime"`
+	EntryPrice      float64   `json:"entryPrice"`
+	EntryScore      float64   `json:"entryScore"`
+	StopLoss        float64   `json:"stopLoss"` // initial stop
+	ExitTime        time.Time `json:"exitTime"`
+	ExitPrice       float64   `json:"exitPrice"`
+	ExitReason      string    `json:"exitReason"` // SL_HIT, TRAILING_STOP, MAX_TIME or END_OF_DATA
+	ProfitLoss      float64   `json:"profitLoss"` // USDT, after fees
+	ProfitLossPct   float64   `json:"profitLossPct"`
+	DurationSeconds int       `json:"durationSeconds"`
+}
+
+// BacktestStats sum up the trades of a backtest
+type BacktestStats struct {
+	Trades             int      `json:"trades"`
+	Wins               int      `json:"wins"`
+	Losses             int      `json:"losses"`
+	WinRate            float64  `json:"winRate"`   // percent
+	NetProfit          float64  `json:"netProfit"` // USDT
+	AvgProfitLossPct   float64  `json:"avgProfitLossPct"`
+	ProfitFactor       *float64 `json:"profitFactor,omitempty"` // gross profit / gross loss; nil without losses
+	MaxDrawdown        float64  `json:"maxDrawdown"`            // USDT, peak to trough of the equity
+	MaxDrawdownPct     *float64 `json:"maxDrawdownPct,omitempty"`
+	AvgDurationSeconds int      `json:"avgDurationSeconds"`
+}
+
+// BacktestResult is the outcome of a backtest
+type BacktestResult struct {
+	Symbol   string                    `json:"symbol"`
+	Strategy string                    `json:"strategy"`
+	From     time.Time                 `json:"from"`
+	To       time.Time                 `json:"to"`
+	Step     string                    `json:"step"`
+	Steps    int                       `json:"steps"`   // strategy runs that produced a coin
+	Candles  int                       `json:"candles"` // 1m candles replayed, warmup included
+	Notional float64                   `json:"notional"`
+	FeeBps   float64                   `json:"feeBps"`
+	Exit     *domain.AutoScalpSettings `json:"exit"`
+	Stats    BacktestStats             `json:"stats"`
+	Trades   []BacktestTrade           `json:"trades"`
+	Equity   *domain.EquityCurve       `json:"equity"`
+}
+
+// Backtester replays historical 1m candles through the screener's strategies
+// and trades their signals with the auto scalper's exit rules.
+//
+// Every step the strategies run on the candles as they stood at that time,
+// with the interval containing it still forming, as in a live cycle. Funding
+// and open interest have no history here and score as neutral, and the BTC
+// regime and market context modifiers are off. The strategies run on the
+// timeframes of the screener config, enabled or not. A position is entered at
+// the step's price and walked candle by candle like a simulated paper fill:
+// the stop fills where the candle crossed it (at the open when it gapped
+// through), worsened by fillSim.slippageBps. One position is held at a time.
+type Backtester struct {
+	screener *ScreenerUsecase
+	client   *binance.Client
+	store    *config.Store
+}
+
+// NewBacktester creates a new backtester
+func NewBacktester(screener *ScreenerUsecase, client *binance.Client, store *config.Store) *Backtester {
+	return &Backtester{screener: screener, client: client, store: store}
+}
+
+// Run replays req.Candles, or the candles of the range downloaded from
+// Binance, and trades the strategy's signals
+func (b *Backtester) Run(ctx context.Context, req BacktestRequest) (*BacktestResult, error) {
+	req.Symbol = strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if req.Symbol == "" {
+		return nil, ErrBacktestSymbol
+	}
+	switch req.Strategy {
+	case BacktestReversal, BacktestPullback, BacktestBreakout, BacktestAutoScalp:
+	default:
+		return nil, ErrBacktestStrategy
+	}
+	if req.Step == 0 {
+		req.Step = defaultBacktestStep
+	}
+	if req.Step < time.Minute || req.Step%time.Minute != 0 {
+		return nil, ErrBacktestStep
+	}
+	if req.Exit == nil {
+		req.Exit = DefaultAutoScalpSettings()
+	}
+	if req.Exit.StopLossPercent <= 0 || req.Exit.MinProfitPercent <= 0 || req.Exit.TrailingStopPercent <= 0 || req.Exit.MaxPositionTime <= 0 {
+		return nil, ErrBacktestExit
+	}
+	if req.FeeBps < 0 || req.FeeBps > 100 {
+		return nil, ErrBacktestFee
+	}
+
+	strategies := b.screener.strategies.Current(ctx)
+	strategies.Scalp.Enabled = true
+	strategies.Intraday.Enabled = true
+	strategies.Pullback.Enabled = true
+	strategies.Breakout.Enabled = true
+	strategies.FollowTrend.Enabled = true
+	warmup := backtestWarmupCandles * slowestScreeningInterval(strategies)
+
+	candles := req.Candles
+	if len(candles) > 0 {
+		if req.From.IsZero() {
+			req.From = candles[0].OpenTime.Add(warmup)
+		}
+		if req.To.IsZero() {
+			req.To = candles[len(candles)-1].OpenTime.Add(time.Minute)
+		}
+	}
+	if now := time.Now().Truncate(time.Minute); req.To.After(now) {
+		req.To = now
+	}
+	if !req.From.Before(req.To) || req.To.Sub(req.From) > maxBacktestRange {
+		return nil, ErrBacktestRange
+	}
+	if req.To.Sub(req.From)/req.Step > maxBacktestSteps {
+		return nil, ErrBacktestSteps
+	}
+	if len(candles) == 0 {
+		var err error
+		candles, err = b.client.GetKlinesBetween(ctx, req.Symbol, "1m", req.From.Add(-warmup), req.To)
+		if err != nil {
+			return nil, fmt.Errorf("%s klines: %w", req.Symbol, err)
+		}
+	}
+	tape := newCandleTape(candles)
+	if len(tape.base) == 0 {
+		return nil, ErrBacktestNoCandles
+	}
+
+	cfg := b.store.Current()
+	regime := NewRegimeFilter(cfg) // never detects, so the regime filters stay off
+	env := &cycleEnv{
+		settings:        newScreenerSettings(cfg),
+		strategies:      strategies,
+		tickers:         make(map[string]binance.Ticker24h),
+		funding:         func(context.Context, string) (float64, error) { return 0, nil },
+		openInterest:    func(context.Context, string) (float64, error) { return 0, nil },
+		positioning:     func(context.Context, string) (positioning, error) { return positioning{}, nil },
+		liquidations:    func(string) liquidationFlow { return liquidationFlow{} },
+		regime:          regime,
+		shortMultiplier: 1,
+		longMultiplier:  1,
+	}
+	scalper := &AutoScalpingService{regime: regime}
+	run := &backtestRun{exit: req.Exit, feeBps: req.FeeBps, slippage: cfg.FillSim.SlippageBps / 10000}
+
+	result := &BacktestResult{
+		Symbol:   req.Symbol,
+		Strategy: req.Strategy,
+		From:     req.From,
+		To:       req.To,
+		Step:     req.Step.String(),
+		Candles:  len(tape.base),
+		Notional: backtestNotional,
+		FeeBps:   req.FeeBps,
+		Exit:     req.Exit,
+		Trades:   []BacktestTrade{},
+	}
+	first := req.From.Truncate(req.Step)
+	if first.Before(req.From) {
+		first = first.Add(req.Step)
+	}
+	for t := first; t.Before(req.To); t = t.Add(req.Step) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if run.pos != nil {
+			run.walk(tape.between(run.pos.walked, t))
+		}
+
+		tape.now = t
+		env.tickers[req.Symbol] = tape.ticker(req.Symbol)
+		env.klines = newCycleKlines(tape.klines).klines
+		coin, ok := b.screener.screenSymbol(ctx, env, req.Symbol)
+		if !ok {
+			continue
+		}
+		result.Steps++
+		side, score, features := backtestSignal(req.Strategy, &coin, scalper)
+		if run.pos != nil {
+			run.ratchetChandelier(features, tape.price())
+			continue
+		}
+		if side != "" {
+			run.open(side, t, tape.price(), score, features)
+		}
+	}
+	if run.pos != nil {
+		run.walk(tape.between(run.pos.walked, req.To))
+	}
+	if run.pos != nil {
+		tape.now = run.pos.walked
+		run.close(run.pos.walked, tape.price(), "END_OF_DATA")
+	}
+
+	result.Trades = run.trades
+	result.Equity = BuildEquityCurve(backtestEquityPoints(run.trades))
+	result.Stats = backtestStats(run.trades, result.Equity)
+	return result, nil
+}
+
+// backtestSignal is the side the strategy enters on coin ("" for none), with
+// the entry score and the features its stop is sized from
+func backtestSignal(strategy string, coin *domain.CoinData, scalper *AutoScalpingService) (string, float64, *domain.MarketFeatures) {
+	switch strategy {
+	case BacktestReversal:
+		if coin.Status == "TRIGGER" {
+			return "SHORT", coin.Score, coin.Features
+		}
+		return "", 0, coin.Features
+	case BacktestPullback:
+		if coin.PullbackStatus == "DIP" {
+			return "LONG", coin.PullbackScore, coin.PullbackFeatures
+		}
+		return "", 0, coin.PullbackFeatures
+	case BacktestBreakout:
+		switch coin.BreakoutStatus {
+		case "BREAKOUT_LONG":
+			return "LONG", coin.BreakoutScore, coin.BreakoutFeatures
+		case "BREAKOUT_SHORT":
+			return "SHORT", coin.BreakoutScore, coin.BreakoutFeatures
+		}
+		return "", 0, coin.BreakoutFeatures
+	default:
+		if scalper.shouldEnter(coin) {
+			return "SHORT", coin.Score, coin.Features
+		}
+		return "", 0, coin.Features
+	}
+}
+
+// backtestPosition is the open position of a backtest
+type backtestPosition struct {
+	isLong     bool
+	entryTime  time.Time
+	entryPrice float64
+	entryScore float64
+	initStop   float64
+	stop       float64
+	peak       float64   // best price since entry: the high of a LONG, the low of a SHORT
+	walked     time.Time // end of the last candle walked
+}
+
+// backtestRun holds the position and the closed trades of a backtest
+type backtestRun struct {
+	exit     *domain.AutoScalpSettings
+	feeBps   float64
+	slippage float64 // fraction a stop fill is worsened by
+
+	pos    *backtestPosition
+	trades []BacktestTrade
+}
+
+// open enters at price with the stop loss widened or tightened by the
+// volatility regime, as the auto scalper sizes it
+func (r *backtestRun) open(side string, at time.Time, price, score float64, features *domain.MarketFeatures) {
+	stopPct := r.exit.StopLossPercent
+	if features != nil {
+		stopPct *= indicators.VolatilityStopMultiplier(features.VolatilityRegime)
+	}
+	p := &backtestPosition{isLong: side == "LONG", entryTime: at, entryPrice: price, entryScore: score, peak: price, walked: at}
+	if p.isLong {
+		p.stop = price * (1 - stopPct/100)
+	} else {
+		p.stop = price * (1 + stopPct/100)
+	}
+	p.initStop = p.stop
+	r.pos = p
+}
+
+// walk moves the open position through candles, closing it on its stop or
+// once it has been held MaxPositionTime
+func (r *backtestRun) walk(candles []domain.Candle) {
+	maxHold := time.Duration(r.exit.MaxPositionTime) * time.Second
+	for _, c := range candles {
+		p := r.pos
+		if p == nil {
+			return
+		}
+		// The stop is checked against the levels of the previous candles: a
+		// candle's own extreme may have come after its adverse move
+		stop, reason := r.restingStop()
+		if price, hit := stopFill(c, stop, !p.isLong); hit {
+			if p.isLong {
+				price *= 1 - r.slippage
+			} else {
+				price *= 1 + r.slippage
+			}
+			r.close(c.OpenTime.Add(time.Minute), price, reason)
+			return
+		}
+		if p.isLong && c.High > p.peak {
+			p.peak = c.High
+		}
+		if !p.isLong && c.Low < p.peak {
+			p.peak = c.Low
+		}
+		p.walked = c.OpenTime.Add(time.Minute)
+		if p.walked.Sub(p.entryTime) >= maxHold {
+			r.close(p.walked, c.Close, "MAX_TIME")
+			return
+		}
+	}
+}
+
+// restingStop is the position's stop and the reason it exits with: the stop
+// loss, in percent mode tightened to TrailingStopPercent from the peak once
+// the position has reached MinProfitPercent
+func (r *backtestRun) restingStop() (float64, string) {
+	p := r.pos
+	stop := p.stop
+	if r.exit.TrailingMode != domain.TrailingModeChandelier && p.peakProfitPct() >= r.exit.MinProfitPercent {
+		trail := p.entryPrice * r.exit.TrailingStopPercent / 100
+		if p.isLong && p.peak-trail > stop {
+			stop = p.peak - trail
+		}
+		if !p.isLong && p.peak+trail < stop {
+			stop = p.peak + trail
+		}
+	}
+	if (p.isLong && stop > p.entryPrice) || (!p.isLong && stop < p.entryPrice) {
+		return stop, "TRAILING_STOP"
+	}
+	return stop, "SL_HIT"
+}
+
+// ratchetChandelier moves the stop to the Chandelier Exit of the step's
+// features once the position reached MinProfitPercent; the stop only tightens
+func (r *backtestRun) ratchetChandelier(features *domain.MarketFeatures, price float64) {
+	p := r.pos
+	if r.exit.TrailingMode != domain.TrailingModeChandelier || features == nil || p.peakProfitPct() < r.exit.MinProfitPercent {
+		return
+	}
+	if p.isLong && features.ChandelierLong > p.stop && features.ChandelierLong < price {
+		p.stop = features.ChandelierLong
+	}
+	if !p.isLong && features.ChandelierShort > 0 && features.ChandelierShort < p.stop && features.ChandelierShort > price {
+		p.stop = features.ChandelierShort
+	}
+}
+
+func (p *backtestPosition) peakProfitPct() float64 {
+	if p.isLong {
+		return (p.peak - p.entryPrice) / p.entryPrice * 100
+	}
+	return (p.entryPrice - p.peak) / p.entryPrice * 100
+}
+
+// close books the position at price, fees on both legs deducted
+func (r *backtestRun) close(at time.Time, price float64, reason string) {
+	p := r.pos
+	side, dir := "SHORT", -1.0
+	if p.isLong {
+		side, dir = "LONG", 1
+	}
+	ret := dir * (price - p.entryPrice) / p.entryPrice
+	fees := r.feeBps / 10000 * backtestNotional * (1 + price/p.entryPrice)
+	pl := ret*backtestNotional - fees
+	r.trades = append(r.trades, BacktestTrade{
+		Side:            side,
+		EntryTime:       p.entryTime,
+		EntryPrice:      p.entryPrice,
+		EntryScore:      round2(p.entryScore),
+		StopLoss:        p.initStop,
+		ExitTime:        at,
+		ExitPrice:       price,
+		ExitReason:      reason,
+		ProfitLoss:      round2(pl),
+		ProfitLossPct:   round2(pl / backtestNotional * 100),
+		DurationSeconds: int(at.Sub(p.entryTime).Seconds()),
+	})
+	r.pos = nil
+}
+
+func backtestEquityPoints(trades []BacktestTrade) []domain.EquityPoint {
+	points := make([]domain.EquityPoint, 0, len(trades))
+	for i, t := range trades {
+		points = append(points, domain.EquityPoint{Time: t.ExitTime, TradeID: strconv.Itoa(i + 1), ProfitLoss: t.ProfitLoss})
+	}
+	return points
+}
+
+func backtestStats(trades []BacktestTrade, equity *domain.EquityCurve) BacktestStats {
+	stats := BacktestStats{Trades: len(trades), MaxDrawdown: round2(equity.MaxDrawdown), MaxDrawdownPct: equity.MaxDrawdownPct}
+	if len(trades) == 0 {
+		return stats
+	}
+	var grossProfit, grossLoss, sumPct float64
+	var duration int
+	for _, t := range trades {
+		if t.ProfitLoss > 0 {
+			stats.Wins++
+			grossProfit += t.ProfitLoss
+		} else {
+			stats.Losses++
+			grossLoss -= t.ProfitLoss
+		}
+		sumPct += t.ProfitLossPct
+		duration += t.DurationSeconds
+	}
+	stats.WinRate = round2(float64(stats.Wins) / float64(len(trades)) * 100)
+	stats.NetProfit = round2(grossProfit - grossLoss)
+	stats.AvgProfitLossPct = round2(sumPct / float64(len(trades)))
+	stats.AvgDurationSeconds = duration / len(trades)
+	if grossLoss > 0 {
+		pf := round2(grossProfit / grossLoss)
+		stats.ProfitFactor = &pf
+	}
+	return stats
+}
+
+// candleTape serves 1m candle history to the strategies as it stood at now.
+// Every interval is aggregated from the 1m candles; the one containing now
+// is still forming, built from the minutes before now.
+type candleTape struct {
+	base      []domain.Candle // 1m, oldest first
+	quoteSums []float64       // quoteSums[i] is the quote volume of base[:i]
+	closed    map[time.Duration][]domain.Candle
+	now       time.Time
+}
+
+func newCandleTape(candles []domain.Candle) *candleTape {
+	t := &candleTape{closed: make(map[time.Duration][]domain.Candle)}
+	for _, c := range candles {
+		if n := len(t.base); n == 0 || c.OpenTime.After(t.base[n-1].OpenTime) {
+			t.base = append(t.base, c)
+		}
+	}
+	t.quoteSums = make([]float64, len(t.base)+1)
+	for i, c := range t.base {
+		t.quoteSums[i+1] = t.quoteSums[i] + c.QuoteVolume
+	}
+	return t
+}
+
+// index is the position of the first 1m candle opened at or after at
+func (t *candleTape) index(at time.Time) int {
+	return sort.Search(len(t.base), func(i int) bool { return !t.base[i].OpenTime.Before(at) })
+}
+
+// between returns the 1m candles opened in [from, to)
+func (t *candleTape) between(from, to time.Time) []domain.Candle {
+	i, j := t.index(from), t.index(to)
+	if i >= j {
+		return nil
+	}
+	return t.base[i:j]
+}
+
+// price is the close of the last minute before now
+func (t *candleTape) price() float64 {
+	if i := t.index(t.now); i > 0 {
+		return t.base[i-1].Close
+	}
+	return 0
+}
+
+// ticker is the 24h ticker at now
+func (t *candleTape) ticker(symbol string) binance.Ticker24h {
+	i, j := t.index(t.now.Add(-24*time.Hour)), t.index(t.now)
+	ticker := binance.Ticker24h{Symbol: symbol, PriceChangePercent: "0", LastPrice: "0", QuoteVolume: "0"}
+	if i >= j {
+		return ticker
+	}
+	open, last := t.base[i].Open, t.base[j-1].Close
+	if open > 0 {
+		ticker.PriceChangePercent = strconv.FormatFloat((last-open)/open*100, 'f', 3, 64)
+	}
+	ticker.LastPrice = strconv.FormatFloat(last, 'f', -1, 64)
+	ticker.QuoteVolume = strconv.FormatFloat(t.quoteSums[j]-t.quoteSums[i], 'f', 2, 64)
+	return ticker
+}
+
+// klines is the klineFetcher of the tape: the last limit candles of interval
+// at now, the forming one included
+func (t *candleTape) klines(_ context.Context, symbol, interval string, limit int) ([][]interface{}, error) {
+	d := binance.IntervalDuration(interval)
+	if d < time.Minute || d > 24*time.Hour || d%time.Minute != 0 {
+		return nil, fmt.Errorf("%s %s: interval not backtestable", symbol, interval)
+	}
+	closed, ok := t.closed[d]
+	if !ok {
+		closed = aggregateCandles(t.base, d)
+		t.closed[d] = closed
+	}
+	start := t.now.Truncate(d)
+	n := sort.Search(len(closed), func(i int) bool { return !closed[i].OpenTime.Before(start) })
+	candles := closed[max(0, n-limit):n]
+	if forming := aggregateCandles(t.between(start, t.now), d); len(forming) > 0 {
+		candles = append(candles[:len(candles):len(candles)], forming[0])
+	}
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+
+	rows := make([][]interface{}, len(candles))
+	for i, c := range candles {
+		rows[i] = binance.KlineRow(c, d)
+	}
+	return rows, nil
+}
+
+// aggregateCandles merges 1m candles into candles of interval d, aligned to
+// UTC as Binance aligns them
+func aggregateCandles(base []domain.Candle, d time.Duration) []domain.Candle {
+	var out []domain.Candle
+	for _, c := range base {
+		open := c.OpenTime.Truncate(d)
+		if n := len(out); n > 0 && out[n-1].OpenTime.Equal(open) {
+			agg := &out[n-1]
+			agg.High = math.Max(agg.High, c.High)
+			agg.Low = math.Min(agg.Low, c.Low)
+			agg.Close = c.Close
+			agg.Volume += c.Volume
+			agg.QuoteVolume += c.QuoteVolume
+			agg.TakerBuyQuote += c.TakerBuyQuote
+			continue
+		}
+		c.OpenTime = open
+		out = append(out, c)
+	}
+	return out
+}
+
+var errCandleCSV = errors.New("invalid candle CSV")
+
+// ParseCandleCSV reads 1m candles from CSV in the Binance kline layout (open
+// time, open, high, low, close, volume, then optionally close time, quote
+// volume, trades, taker buy base and taker buy quote volume), as in the
+// data.binance.vision dumps. A header row is skipped. Open times are Unix
+// milliseconds (microseconds are recognized) or RFC 3339.
+func ParseCandleCSV(r io.Reader) ([]domain.Candle, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	var candles []domain.Candle
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, domain.Validation("BACKTEST_INVALID_CSV", err.Error())
+		}
+		c, err := parseCandleRecord(record)
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, domain.Validation("BACKTEST_INVALID_CSV", fmt.Sprintf("line %d: %v", line, err))
+		}
+		candles = append(candles, c)
+	}
+	sort.SliceStable(candles, func(i, j int) bool { return candles[i].OpenTime.Before(candles[j].OpenTime) })
+	for i := 1; i < len(candles); i++ {
+		if candles[i].OpenTime.Sub(candles[i-1].OpenTime)%time.Minute != 0 {
+			return nil, domain.Validation("BACKTEST_INVALID_CSV", "candles must be 1m candles")
+		}
+	}
+	return candles, nil
+}
+
+func parseCandleRecord(record []string) (domain.Candle, error) {
+	if len(record) < 6 {
+		return domain.Candle{}, fmt.Errorf("%w: expected at least 6 columns", errCandleCSV)
+	}
+	var c domain.Candle
+	if ms, err := strconv.ParseInt(record[0], 10, 64); err == nil {
+		if ms > 1e14 {
+			ms /= 1000 // microseconds
+		}
+		c.OpenTime = time.UnixMilli(ms).UTC()
+	} else if at, err := time.Parse(time.RFC3339, record[0]); err == nil {
+		c.OpenTime = at.UTC()
+	} else {
+		return c, fmt.Errorf("%w: open time %q", errCandleCSV, record[0])
+	}
+
+	fields := []*float64{&c.Open, &c.High, &c.Low, &c.Close, &c.Volume}
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(record[i+1], 64)
+		if err != nil {
+			return c, fmt.Errorf("%w: column %d: %q", errCandleCSV, i+2, record[i+1])
+		}
+		*f = v
+	}
+	if len(record) >= 11 {
+		c.QuoteVolume, _ = strconv.ParseFloat(record[7], 64)
+		c.TakerBuyQuote, _ = strconv.ParseFloat(record[10], 64)
+	} else {
+		c.QuoteVolume = c.Volume * c.Close
+	}
+	if c.Low > c.High || c.Close <= 0 {
+		return c, fmt.Errorf("%w: bad prices", errCandleCSV)
+	}
+	return c, nil
+}
@@ -1,37 +1,55 @@
 package usecase
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"log"
 	"math"
 	"screener-backend/internal/domain"
 	"screener-backend/internal/infrastructure/binance"
+	"screener-backend/internal/infrastructure/reporting"
 	"time"
 )
 
 var (
-	ErrRealTradingDisabled = errors.New("real trading is disabled")
-	ErrMissingCredentials  = errors.New("binance credentials not configured")
+	ErrRealTradingDisabled = domain.RiskLimit("REAL_TRADING_DISABLED", "real trading is disabled")
+	ErrMissingCredentials  = domain.Validation("CREDENTIALS_MISSING", "binance credentials not configured")
+	ErrInsufficientBalance = domain.RiskLimit("INSUFFICIENT_BALANCE", "insufficient balance")
 )
 
 type BinanceTradingService struct {
 	apiRepo  domain.BinanceAPIStore
 	autoRepo domain.AutoScalpRepository
+	filters  *binance.SymbolFilterCache // step, tick and min notional per symbol
 }
 
 func NewBinanceTradingService(
 	apiRepo domain.BinanceAPIStore,
 	autoRepo domain.AutoScalpRepository,
+	filters *binance.SymbolFilterCache,
 ) *BinanceTradingService {
 	return &BinanceTradingService{
 		apiRepo:  apiRepo,
 		autoRepo: autoRepo,
+		filters:  filters,
 	}
 }
 
+// ShortBracket is a SHORT opened on Binance with its exchange-side exits
+type ShortBracket struct {
+	EntryOrderID      int64
+	StopLossOrderID   int64
+	TakeProfitOrderID int64 // 0 when placed without a take profit
+	Quantity          float64
+	Leverage          int
+	FilledPrice       float64
+}
+
 // PlaceShortWithStopLoss places a SHORT market order and immediately places a STOP_MARKET reduce-only stop loss.
-// This is the safest baseline because the SL lives on Binance.
+// This is the safest baseline because the SL lives on Binance. See PlaceShortBracket for a failed stop loss.
+// The orders are not attached to an auto scalp entry; the position is managed by the caller.
 func (s *BinanceTradingService) PlaceShortWithStopLoss(
+	ctx context.Context,
 	userID string,
 	symbol string,
 	entryPrice float64,
@@ -39,30 +57,66 @@ func (s *BinanceTradingService) PlaceShortWithStopLoss(
 	tradeAmountUSDT float64,
 	leverage int,
 ) (entryOrderID int64, slOrderID int64, qty float64, err error) {
-	cfg, cfgErr := s.apiRepo.GetTradingConfig(userID)
-	if cfgErr != nil {
-		// If no config exists, default is returned by repo.
-		cfg = &domain.BinanceTradingConfig{UserID: userID, EnableRealTrading: false}
+	b, err := s.placeShort(ctx, userID, symbol, entryPrice, stopLossPrice, 0, false, tradeAmountUSDT, leverage)
+	if b == nil {
+		return 0, 0, 0, err
 	}
+	return b.EntryOrderID, b.StopLossOrderID, b.Quantity, err
+}
 
-	if !cfg.EnableRealTrading {
-		return 0, 0, 0, ErrRealTradingDisabled
+// PlaceShortBracket places a SHORT market order with a STOP_MARKET stop loss
+// and a TAKE_PROFIT_MARKET take profit. takeProfitPrice 0 sets the take
+// profit DefaultTakeProfitPct below the fill, per the user's trading config.
+// The three go on together or not at all: when an exit order fails, the
+// placed one is cancelled and the SHORT is closed at market, and the error is
+// returned with a nil bracket. Only when that close fails too is the bracket
+// returned with the error, for a position left open without its exits.
+// The placed orders are attached to the user's active auto scalp entry for
+// the symbol.
+func (s *BinanceTradingService) PlaceShortBracket(
+	ctx context.Context,
+	userID string,
+	symbol string,
+	entryPrice float64,
+	stopLossPrice float64,
+	takeProfitPrice float64,
+	tradeAmountUSDT float64,
+	leverage int,
+) (*ShortBracket, error) {
+	b, err := s.placeShort(ctx, userID, symbol, entryPrice, stopLossPrice, takeProfitPrice, true, tradeAmountUSDT, leverage)
+	if err != nil {
+		return b, err
 	}
+	// The orders are live on the exchange by now, so record them even if
+	// the caller gave up waiting
+	_ = s.autoRepo.UpdateOrAttachBinanceOrders(context.WithoutCancel(ctx), userID, symbol, b.EntryOrderID, b.StopLossOrderID, b.TakeProfitOrderID, b.Quantity, b.Leverage, b.FilledPrice)
+	return b, nil
+}
 
-	cred, err := s.apiRepo.GetCredentials(userID)
-	if err != nil {
-		return 0, 0, 0, ErrMissingCredentials
+func (s *BinanceTradingService) placeShort(
+	ctx context.Context,
+	userID string,
+	symbol string,
+	entryPrice float64,
+	stopLossPrice float64,
+	takeProfitPrice float64,
+	withTakeProfit bool,
+	tradeAmountUSDT float64,
+	leverage int,
+) (*ShortBracket, error) {
+	cfg, cfgErr := s.apiRepo.GetTradingConfig(ctx, userID)
+	if cfgErr != nil {
+		// If no config exists, default is returned by repo.
+		cfg = &domain.BinanceTradingConfig{UserID: userID, EnableRealTrading: false}
 	}
 
-	client := binance.NewTradingClient(cred.APIKey, cred.SecretKey, cred.IsTestnet)
-	if err := client.SetLeverage(symbol, leverage); err != nil {
-		return 0, 0, 0, err
+	if !cfg.EnableRealTrading {
+		return nil, ErrRealTradingDisabled
 	}
 
-	// Risk checks: basic
-	acct, err := client.GetAccountInfo()
+	cred, err := s.apiRepo.GetCredentials(ctx, userID)
 	if err != nil {
-		return 0, 0, 0, err
+		return nil, ErrMissingCredentials
 	}
 
 	if tradeAmountUSDT <= 0 {
@@ -78,22 +132,39 @@ func (s *BinanceTradingService) PlaceShortWithStopLoss(
 		leverage = 20
 	}
 
-	// Daily loss/trade checks are handled elsewhere (emergency stop).
-	if acct.AvailableBalance <= 0 {
-		return 0, 0, 0, errors.New("insufficient balance")
+	// Size and price the orders by the symbol's exchange rules before
+	// touching the account
+	filters, err := s.filters.Get(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	// qty = (tradeAmountUSDT * leverage) / entryPrice, rounded down to the
+	// symbol's market step size
+	qty := filters.RoundMarketQuantity((tradeAmountUSDT * float64(leverage)) / entryPrice)
+	if err := filters.CheckMarketOrder(qty, entryPrice); err != nil {
+		return nil, err
+	}
+	stopLossPrice = filters.RoundPrice(stopLossPrice)
+
+	client := binance.NewTradingClientForCredentials(cred)
+	if err := client.SetLeverage(ctx, symbol, leverage); err != nil {
+		return nil, err
 	}
 
-	// Quantity approximation for USDT-margined futures: qty = (tradeAmountUSDT * leverage) / entryPrice
-	// Round down to a reasonable precision.
-	rawQty := (tradeAmountUSDT * float64(leverage)) / entryPrice
-	qty = floorTo(rawQty, 3) // 0.001 steps baseline; real step size differs per symbol.
-	if qty <= 0 {
-		return 0, 0, 0, errors.New("calculated quantity too small")
+	// Risk checks: basic
+	acct, err := client.GetAccountInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Daily loss/trade checks are handled elsewhere (emergency stop).
+	if acct.AvailableBalance <= 0 {
+		return nil, ErrInsufficientBalance
 	}
 
 	// 1) Place entry order: SELL MARKET (SHORT)
 	positionSide := "SHORT"
-	entryResp, err := client.PlaceOrder(&domain.BinanceOrderRequest{
+	entryResp, err := client.PlaceOrder(ctx, &domain.BinanceOrderRequest{
 		Symbol:       symbol,
 		Side:         "SELL",
 		PositionSide: positionSide,
@@ -104,7 +175,7 @@ func (s *BinanceTradingService) PlaceShortWithStopLoss(
 		// Fallback for One-way mode where positionSide must be BOTH.
 		if apiErr, ok := err.(*binance.BinanceAPIError); ok && apiErr.Code == -4061 {
 			positionSide = "BOTH"
-			entryResp, err = client.PlaceOrder(&domain.BinanceOrderRequest{
+			entryResp, err = client.PlaceOrder(ctx, &domain.BinanceOrderRequest{
 				Symbol:       symbol,
 				Side:         "SELL",
 				PositionSide: positionSide,
@@ -114,83 +185,258 @@ func (s *BinanceTradingService) PlaceShortWithStopLoss(
 		}
 	}
 	if err != nil {
-		return 0, 0, 0, err
+		reporting.Capture(ctx, reporting.Event{
+			Message: "entry order failed",
+			Err:     err,
+			UserID:  userID,
+			Symbol:  symbol,
+			Extra:   map[string]interface{}{"quantity": qty, "leverage": leverage},
+		})
+		return nil, err
 	}
 
-	entryOrderID = entryResp.OrderID
+	b := &ShortBracket{EntryOrderID: entryResp.OrderID, Quantity: qty, Leverage: leverage}
 	// If avg price returned is 0, fall back to provided entryPrice.
-	filledPrice := entryResp.ExecutedPrice
-	if filledPrice <= 0 {
-		filledPrice = entryPrice
+	b.FilledPrice = entryResp.ExecutedPrice
+	if b.FilledPrice <= 0 {
+		b.FilledPrice = entryPrice
 	}
+	if withTakeProfit && takeProfitPrice <= 0 && cfg.DefaultTakeProfitPct > 0 {
+		takeProfitPrice = b.FilledPrice * (1 - cfg.DefaultTakeProfitPct/100)
+	}
+	takeProfitPrice = filters.RoundPrice(takeProfitPrice)
 
-	// 2) Place STOP_MARKET closePosition stop loss
-	slID, err := client.PlaceStopLossOrder(symbol, qty, stopLossPrice, positionSide)
+	// 2) Place STOP_MARKET closePosition stop loss, then 3) the
+	// TAKE_PROFIT_MARKET take profit
+	exit := "stop loss"
+	b.StopLossOrderID, err = client.PlaceStopLossOrder(ctx, symbol, qty, stopLossPrice, positionSide)
+	if err == nil && takeProfitPrice > 0 {
+		exit = "take profit"
+		b.TakeProfitOrderID, err = client.PlaceTakeProfitOrder(ctx, symbol, qty, takeProfitPrice, positionSide)
+	}
 	if err != nil {
-		// Best effort: if SL placement fails, we should alert loudly.
-		log.Printf("CRITICAL: SL order placement failed for %s entryOrder=%d: %v", symbol, entryOrderID, err)
-		return entryOrderID, 0, qty, err
+		// Never leave the position without its exits: undo the entry.
+		// The orders are live, so finish even if the caller gave up waiting.
+		unwindCtx := context.WithoutCancel(ctx)
+		if b.StopLossOrderID != 0 {
+			if cancelErr := client.CancelOrder(unwindCtx, symbol, b.StopLossOrderID); cancelErr != nil {
+				log.Printf("Cancelling SL order %d of %s failed: %v", b.StopLossOrderID, symbol, cancelErr)
+			}
+		}
+		_, closeErr := closeShortAtMarket(unwindCtx, client, symbol, qty, positionSide)
+		if closeErr == nil {
+			log.Printf("%s placement failed for %s entryOrder=%d, entry closed: %v", exit, symbol, b.EntryOrderID, err)
+			reporting.Capture(ctx, reporting.Event{
+				Message: exit + " placement failed, entry closed",
+				Err:     err,
+				UserID:  userID,
+				Symbol:  symbol,
+				Extra:   map[string]interface{}{"entryOrderId": b.EntryOrderID, "quantity": qty, "stopPrice": stopLossPrice, "takeProfitPrice": takeProfitPrice},
+			})
+			return nil, fmt.Errorf("%s placement failed, entry closed: %w", exit, err)
+		}
+
+		// Best effort failed: the position is open without its exits, alert loudly.
+		log.Printf("CRITICAL: %s order placement failed for %s entryOrder=%d and closing it failed: %v / %v", exit, symbol, b.EntryOrderID, err, closeErr)
+		reporting.Capture(ctx, reporting.Event{
+			Level:   reporting.LevelFatal,
+			Message: exit + " placement failed",
+			Err:     err,
+			UserID:  userID,
+			Symbol:  symbol,
+			Extra:   map[string]interface{}{"entryOrderId": b.EntryOrderID, "quantity": qty, "stopPrice": stopLossPrice, "takeProfitPrice": takeProfitPrice, "closeError": closeErr.Error()},
+		})
+		b.StopLossOrderID, b.TakeProfitOrderID = 0, 0
+		return b, err
 	}
 
-	slOrderID = slID
+	return b, nil
+}
+
+// CloseShort closes a SHORT opened by PlaceShortBracket with a MARKET BUY and
+// cancels its exit orders (stop loss, take profit; 0 IDs are skipped). The
+// close is reduce-only in one-way mode; in hedge mode a BUY on the SHORT side
+// can only reduce it anyway. A position Binance has already closed (an exit
+// order filled) is not an error. It returns the average fill price, 0 when
+// there was no fill.
+// Real trading need not be enabled: positions opened before it was turned
+// off are still closed.
+func (s *BinanceTradingService) CloseShort(ctx context.Context, userID string, symbol string, qty float64, exitOrderIDs ...int64) (float64, error) {
+	cred, err := s.apiRepo.GetCredentials(ctx, userID)
+	if err != nil {
+		return 0, ErrMissingCredentials
+	}
+	client := binance.NewTradingClientForCredentials(cred)
 
-	// Persist in auto scalping repo if exists
-	_ = s.autoRepo.UpdateOrAttachBinanceOrders(symbol, entryOrderID, slOrderID, qty, leverage, filledPrice)
+	resp, err := closeShortAtMarket(ctx, client, symbol, qty, "")
+	filledPrice := 0.0
+	if apiErr, ok := err.(*binance.BinanceAPIError); ok && apiErr.Code == -2022 {
+		// ReduceOnly rejected: there is no position left to reduce
+		log.Printf("%s SHORT of user=%s already closed on Binance", symbol, userID)
+	} else if err != nil {
+		reporting.Capture(ctx, reporting.Event{
+			Level:   reporting.LevelFatal,
+			Message: "close order failed",
+			Err:     err,
+			UserID:  userID,
+			Symbol:  symbol,
+			Extra:   map[string]interface{}{"quantity": qty},
+		})
+		return 0, err
+	} else {
+		filledPrice = resp.ExecutedPrice
+	}
 
-	return entryOrderID, slOrderID, qty, nil
+	// The exits would otherwise stay on the book and close a later position
+	for _, id := range exitOrderIDs {
+		if id == 0 {
+			continue
+		}
+		if err := client.CancelOrder(context.WithoutCancel(ctx), symbol, id); err != nil {
+			log.Printf("Cancelling exit order %d of %s failed: %v", id, symbol, err)
+		}
+	}
+	return filledPrice, nil
 }
 
-func floorTo(v float64, decimals int) float64 {
-	p := math.Pow10(decimals)
-	return math.Floor(v*p) / p
+// closeShortAtMarket sends the MARKET BUY closing a SHORT of qty. An empty
+// positionSide tries the hedge mode SHORT side first, then one-way mode.
+func closeShortAtMarket(ctx context.Context, client *binance.TradingClient, symbol string, qty float64, positionSide string) (*domain.BinanceOrderResponse, error) {
+	req := &domain.BinanceOrderRequest{
+		Symbol:       symbol,
+		Side:         "BUY",
+		PositionSide: positionSide,
+		OrderType:    "MARKET",
+		Quantity:     qty,
+		ReduceOnly:   positionSide == "BOTH",
+	}
+	if positionSide != "" {
+		return client.PlaceOrder(ctx, req)
+	}
+
+	req.PositionSide = "SHORT"
+	resp, err := client.PlaceOrder(ctx, req)
+	if apiErr, ok := err.(*binance.BinanceAPIError); ok && apiErr.Code == -4061 {
+		// One-way mode: positionSide must be BOTH
+		req.PositionSide, req.ReduceOnly = "BOTH", true
+		resp, err = client.PlaceOrder(ctx, req)
+	}
+	return resp, err
 }
 
-// EmergencyStopAll closes all active positions if needed.
-func (s *BinanceTradingService) EmergencyStopAll(userID string, reason string) error {
-	cred, err := s.apiRepo.GetCredentials(userID)
+// EmergencyStopResult is what an emergency stop did on the exchange and to
+// the user's auto scalping
+type EmergencyStopResult struct {
+	StoppedAt         time.Time               `json:"stoppedAt"`
+	Reason            string                  `json:"reason"`
+	Positions         []EmergencyStopPosition `json:"positions"`
+	CancelledOrders   int                     `json:"cancelledOrders"`
+	FailedOrders      int                     `json:"failedOrders"`          // open orders that could not be cancelled
+	OrdersError       string                  `json:"ordersError,omitempty"` // listing the open orders failed
+	AutoScalpDisabled bool                    `json:"autoScalpDisabled"`     // auto scalping was on and is now off
+	ClosedEntries     int                     `json:"closedEntries"`         // active autoscalp entries closed
+}
+
+// EmergencyStopPosition is a position an emergency stop closed, or failed to
+// close when Error is set
+type EmergencyStopPosition struct {
+	Symbol       string  `json:"symbol"`
+	PositionSide string  `json:"positionSide"`
+	Quantity     float64 `json:"quantity"`
+	FilledPrice  float64 `json:"filledPrice,omitempty"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// EmergencyStopAll closes every open position of the user at market, then
+// cancels the open orders of every symbol, and records the stop. The orders of a symbol whose
+// position failed to close are kept, so its stop loss still protects it.
+// Real trading need not be enabled: a stop must flatten positions opened
+// before it was turned off.
+func (s *BinanceTradingService) EmergencyStopAll(ctx context.Context, userID string, reason string) (*EmergencyStopResult, error) {
+	cred, err := s.apiRepo.GetCredentials(ctx, userID)
 	if err != nil {
-		return ErrMissingCredentials
-	}
-	cfg, _ := s.apiRepo.GetTradingConfig(userID)
-	if cfg != nil && !cfg.EnableRealTrading {
-		return ErrRealTradingDisabled
+		return nil, ErrMissingCredentials
 	}
 
-	client := binance.NewTradingClient(cred.APIKey, cred.SecretKey, cred.IsTestnet)
-	acct, err := client.GetAccountInfo()
+	client := binance.NewTradingClientForCredentials(cred)
+	acct, err := client.GetAccountInfo(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	log.Printf("EMERGENCY STOP user=%s positions=%d reason=%s", userID, acct.PositionsCount, reason)
 
-	// Close each open position at market
+	// Orders must go through even if the caller gives up on the request
+	ctx = context.WithoutCancel(ctx)
+	result := &EmergencyStopResult{StoppedAt: time.Now(), Reason: reason, Positions: []EmergencyStopPosition{}}
+	stillOpen := make(map[string]bool)
 	for _, pos := range acct.Positions {
 		if pos.PositionAmount == 0 {
 			continue
 		}
 
+		// Hedge mode positions close from their side; one-way (BOTH)
+		// positions by their sign, reduce-only
 		side := "BUY"
-		positionSide := pos.PositionSide
-		// If SHORT positionAmt is negative sometimes. Use abs.
-		qty := math.Abs(pos.PositionAmount)
-		if positionSide == "LONG" {
+		if pos.PositionSide == "LONG" || (pos.PositionSide == "BOTH" && pos.PositionAmount > 0) {
 			side = "SELL"
 		}
-
-		_, err := client.PlaceOrder(&domain.BinanceOrderRequest{
+		closed := EmergencyStopPosition{Symbol: pos.Symbol, PositionSide: pos.PositionSide, Quantity: math.Abs(pos.PositionAmount)}
+		resp, err := client.PlaceOrder(ctx, &domain.BinanceOrderRequest{
 			Symbol:       pos.Symbol,
 			Side:         side,
-			PositionSide: positionSide,
+			PositionSide: pos.PositionSide,
 			OrderType:    "MARKET",
-			Quantity:     qty,
+			Quantity:     closed.Quantity,
+			ReduceOnly:   pos.PositionSide == "BOTH",
 		})
 		if err != nil {
 			log.Printf("Failed to close position %s: %v", pos.Symbol, err)
+			reporting.Capture(ctx, reporting.Event{
+				Level:   reporting.LevelFatal,
+				Message: "emergency stop close failed",
+				Err:     err,
+				UserID:  userID,
+				Symbol:  pos.Symbol,
+				Extra:   map[string]interface{}{"quantity": closed.Quantity, "reason": reason},
+			})
+			closed.Error = err.Error()
+			stillOpen[pos.Symbol] = true
+		} else {
+			closed.FilledPrice = resp.ExecutedPrice
 		}
+		result.Positions = append(result.Positions, closed)
 	}
 
-	// Record emergency stop timestamp
-	_ = s.autoRepo.RecordEmergencyStop(userID, time.Now(), reason)
-	return nil
+	// Orders are cancelled a symbol at a time. Without the list, the symbols
+	// of the closed positions are still cleared of their stop orders.
+	bySymbol := make(map[string]int)
+	orders, err := client.GetOpenOrders(ctx, "")
+	if err != nil {
+		log.Printf("Listing open orders for the emergency stop of user=%s failed: %v", userID, err)
+		result.OrdersError = err.Error()
+		for _, pos := range result.Positions {
+			bySymbol[pos.Symbol] = 0
+		}
+	}
+	for _, order := range orders {
+		symbol, _ := order["symbol"].(string)
+		bySymbol[symbol]++
+	}
+	for symbol, count := range bySymbol {
+		if stillOpen[symbol] {
+			continue
+		}
+		if err := client.CancelAllOpenOrders(ctx, symbol); err != nil {
+			log.Printf("Cancelling the open orders of %s failed: %v", symbol, err)
+			result.FailedOrders += count
+			continue
+		}
+		result.CancelledOrders += count
+	}
+
+	if err := s.autoRepo.RecordEmergencyStop(ctx, userID, result.StoppedAt, reason); err != nil {
+		log.Printf("Recording the emergency stop of user=%s failed: %v", userID, err)
+	}
+	return result, nil
 }
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+const (
+	// blackoutCacheTTL is how long the calendar is served from memory, so
+	// windows added on another instance show up within a minute
+	blackoutCacheTTL = time.Minute
+	maxBlackoutSpan  = 7 * 24 * time.Hour
+)
+
+var (
+	ErrBlackoutName  = domain.Validation("BLACKOUT_NAME_REQUIRED", "name is required")
+	ErrBlackoutKind  = domain.Validation("BLACKOUT_INVALID_KIND", "kind must be one of "+strings.Join(domain.BlackoutKinds, ", "))
+	ErrBlackoutRange = domain.Validation("BLACKOUT_INVALID_RANGE", "end must be after start")
+	ErrBlackoutSpan  = domain.Validation("BLACKOUT_TOO_LONG", "a blackout window must not exceed 7 days")
+)
+
+// BlackoutCalendar holds the event blackout windows (FOMC, CPI releases,
+// exchange maintenance). While one is active the auto scalper pauses new
+// entries and notifications carry a high event risk tag.
+type BlackoutCalendar struct {
+	repo domain.BlackoutRepository
+
+	mu       sync.Mutex
+	upcoming []*domain.BlackoutWindow // windows not yet over at loadedAt
+	loadedAt time.Time
+}
+
+// NewBlackoutCalendar creates a new blackout calendar
+func NewBlackoutCalendar(repo domain.BlackoutRepository) *BlackoutCalendar {
+	return &BlackoutCalendar{repo: repo}
+}
+
+// List returns the windows not over yet, or every window since since when set
+func (c *BlackoutCalendar) List(ctx context.Context, since time.Time) ([]*domain.BlackoutWindow, error) {
+	if since.IsZero() {
+		since = time.Now()
+	}
+	return c.repo.ListBlackouts(ctx, since)
+}
+
+// Add validates and stores a window
+func (c *BlackoutCalendar) Add(ctx context.Context, w *domain.BlackoutWindow) error {
+	w.Name = strings.TrimSpace(w.Name)
+	w.Kind = strings.ToUpper(strings.TrimSpace(w.Kind))
+	if w.Kind == "" {
+		w.Kind = domain.BlackoutOther
+	}
+	switch {
+	case w.Name == "":
+		return ErrBlackoutName
+	case !slices.Contains(domain.BlackoutKinds, w.Kind):
+		return ErrBlackoutKind
+	case !w.End.After(w.Start):
+		return ErrBlackoutRange
+	case w.End.Sub(w.Start) > maxBlackoutSpan:
+		return ErrBlackoutSpan
+	}
+	w.CreatedAt = time.Now().UTC()
+	if err := c.repo.SaveBlackout(ctx, w); err != nil {
+		return err
+	}
+	c.invalidate()
+	logging.Infof("Blackout added by %s: %s %q %s - %s", w.CreatedBy, w.Kind, w.Name, w.Start.Format(time.RFC3339), w.End.Format(time.RFC3339))
+	return nil
+}
+
+// Remove deletes a window
+func (c *BlackoutCalendar) Remove(ctx context.Context, id int64) error {
+	if err := c.repo.DeleteBlackout(ctx, id); err != nil {
+		return err
+	}
+	c.invalidate()
+	logging.Infof("Blackout %d removed", id)
+	return nil
+}
+
+// Active is the window covering now, nil outside any. When windows overlap
+// the one ending last is returned. A failed load keeps the cached calendar.
+func (c *BlackoutCalendar) Active(ctx context.Context) *domain.BlackoutWindow {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Sub(c.loadedAt) > blackoutCacheTTL {
+		windows, err := c.repo.ListBlackouts(ctx, now)
+		if err != nil {
+			logging.Warnf("Loading blackout windows failed: %v", err)
+		} else {
+			c.upcoming = windows
+			c.loadedAt = now
+		}
+	}
+
+	var active *domain.BlackoutWindow
+	for _, w := range c.upcoming {
+		if w.Contains(now) && (active == nil || w.End.After(active.End)) {
+			active = w
+		}
+	}
+	if active == nil {
+		return nil
+	}
+	copied := *active
+	return &copied
+}
+
+func (c *BlackoutCalendar) invalidate() {
+	c.mu.Lock()
+	c.loadedAt = time.Time{}
+	c.mu.Unlock()
+}
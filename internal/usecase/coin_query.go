@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+
+	"screener-backend/internal/domain"
+)
+
+// Coin strategies: which of a coin's scores and statuses a query filters and
+// orders by
+const (
+	CoinStrategyReversal = "reversal" // Score and Status (TRIGGER, SETUP, ...)
+	CoinStrategyIntraday = "intraday"
+	CoinStrategyPullback = "pullback"
+	CoinStrategyBreakout = "breakout"
+	CoinStrategyTrend    = "trend"
+)
+
+const (
+	DefaultCoinPageSize = 50
+	MaxCoinPageSize     = 500
+)
+
+var ErrCoinStrategy = domain.Validation("INVALID_COIN_STRATEGY", "strategy must be reversal, intraday, pullback, breakout or trend")
+
+// CoinQuery selects coins of the latest cycle by one strategy's status and
+// score. Empty Strategy is reversal; empty Status matches any.
+type CoinQuery struct {
+	Strategy string
+	Status   string
+	MinScore float64
+	Offset   int
+	Limit    int // 0 is DefaultCoinPageSize
+}
+
+// CoinPage is one page of the coins matching a query, highest score first
+type CoinPage struct {
+	Strategy string            `json:"strategy"`
+	Total    int               `json:"total"` // matches across all pages
+	Offset   int               `json:"offset"`
+	Limit    int               `json:"limit"`
+	Coins    []domain.CoinData `json:"coins"`
+}
+
+// QueryCoins filters coins by q and returns the requested page
+func QueryCoins(coins []domain.CoinData, q CoinQuery) (*CoinPage, error) {
+	if q.Strategy == "" {
+		q.Strategy = CoinStrategyReversal
+	}
+	signal := coinSignal(q.Strategy)
+	if signal == nil {
+		return nil, ErrCoinStrategy
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultCoinPageSize
+	}
+
+	matched := make([]domain.CoinData, 0)
+	for i := range coins {
+		score, status := signal(&coins[i])
+		if score >= q.MinScore && (q.Status == "" || strings.EqualFold(status, q.Status)) {
+			matched = append(matched, coins[i])
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		si, _ := signal(&matched[i])
+		sj, _ := signal(&matched[j])
+		return si > sj
+	})
+
+	page := &CoinPage{Strategy: q.Strategy, Total: len(matched), Offset: q.Offset, Limit: q.Limit, Coins: []domain.CoinData{}}
+	if q.Offset < len(matched) {
+		page.Coins = matched[q.Offset:min(q.Offset+q.Limit, len(matched))]
+	}
+	return page, nil
+}
+
+// CoinFilter matches coins by symbol and by the score of any of several
+// strategies, for streaming clients that subscribe to part of each cycle
+type CoinFilter struct {
+	symbols  map[string]bool // nil matches any symbol
+	signals  []func(c *domain.CoinData) (float64, string)
+	minScore float64
+}
+
+// NewCoinFilter builds a filter; no symbols matches every symbol, and no
+// strategies means reversal
+func NewCoinFilter(symbols, strategies []string, minScore float64) (*CoinFilter, error) {
+	f := &CoinFilter{minScore: minScore}
+	if len(symbols) > 0 {
+		f.symbols = make(map[string]bool, len(symbols))
+		for _, s := range symbols {
+			f.symbols[strings.ToUpper(strings.TrimSpace(s))] = true
+		}
+	}
+	if len(strategies) == 0 {
+		strategies = []string{CoinStrategyReversal}
+	}
+	for _, strategy := range strategies {
+		signal := coinSignal(strategy)
+		if signal == nil {
+			return nil, ErrCoinStrategy
+		}
+		f.signals = append(f.signals, signal)
+	}
+	return f, nil
+}
+
+// Match reports whether c is one of the symbols and scores at least the
+// minimum on one of the strategies
+func (f *CoinFilter) Match(c *domain.CoinData) bool {
+	if f.symbols != nil && !f.symbols[c.Symbol] {
+		return false
+	}
+	for _, signal := range f.signals {
+		if score, _ := signal(c); score >= f.minScore {
+			return true
+		}
+	}
+	return false
+}
+
+// coinSignal picks a coin's score and status for strategy; nil for an
+// unknown strategy
+func coinSignal(strategy string) func(c *domain.CoinData) (float64, string) {
+	switch strategy {
+	case CoinStrategyReversal:
+		return func(c *domain.CoinData) (float64, string) { return c.Score, c.Status }
+	case CoinStrategyIntraday:
+		return func(c *domain.CoinData) (float64, string) { return c.IntradayScore, c.IntradayStatus }
+	case CoinStrategyPullback:
+		return func(c *domain.CoinData) (float64, string) { return c.PullbackScore, c.PullbackStatus }
+	case CoinStrategyBreakout:
+		return func(c *domain.CoinData) (float64, string) { return c.BreakoutScore, c.BreakoutStatus }
+	case CoinStrategyTrend:
+		return func(c *domain.CoinData) (float64, string) { return c.FollowTrendScore, c.FollowTrendStatus }
+	}
+	return nil
+}
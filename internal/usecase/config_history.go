@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+)
+
+// Sources of a recorded settings change
+const (
+	ConfigSourceAPI      = "api"
+	ConfigSourceReload   = "reload"
+	ConfigSourceRollback = "rollback"
+)
+
+// ErrNothingToRollback is returned for a change whose diff is empty
+var ErrNothingToRollback = domain.Validation("NOTHING_TO_ROLLBACK", "change has no settings to revert")
+
+// ConfigHistoryService applies runtime settings changes through the config
+// store and records each one with its author and diff, so a scoring
+// experiment can be looked up and reverted
+type ConfigHistoryService struct {
+	store *config.Store
+	repo  domain.ConfigHistoryRepository
+}
+
+// NewConfigHistoryService creates a new settings history service
+func NewConfigHistoryService(store *config.Store, repo domain.ConfigHistoryRepository) *ConfigHistoryService {
+	return &ConfigHistoryService{store: store, repo: repo}
+}
+
+// Update applies path -> value changes (see config.Store.Update) and records them
+func (s *ConfigHistoryService) Update(ctx context.Context, author string, values map[string]string) (*config.Config, error) {
+	before := s.store.Current()
+	cfg, err := s.store.Update(values)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, author, ConfigSourceAPI, nil, before, cfg)
+	return cfg, nil
+}
+
+// Reload re-reads CONFIG_FILE and the environment (see config.Store.Reload)
+// and records what changed
+func (s *ConfigHistoryService) Reload(ctx context.Context, author string) (*config.Config, error) {
+	before := s.store.Current()
+	cfg, err := s.store.Reload()
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, author, ConfigSourceReload, nil, before, cfg)
+	return cfg, nil
+}
+
+// Rollback sets every setting touched by change id back to its value before
+// that change. Later changes to the same settings are overwritten too.
+func (s *ConfigHistoryService) Rollback(ctx context.Context, author string, id int64) (*config.Config, *domain.ConfigChange, error) {
+	change, err := s.repo.GetConfigChange(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(change.Diff) == 0 {
+		return nil, nil, ErrNothingToRollback
+	}
+	values := make(map[string]string, len(change.Diff))
+	for _, d := range change.Diff {
+		values[d.Path] = d.Old
+	}
+
+	before := s.store.Current()
+	cfg, err := s.store.Update(values)
+	if err != nil {
+		return nil, nil, err
+	}
+	recorded := s.record(ctx, author, ConfigSourceRollback, &id, before, cfg)
+	return cfg, recorded, nil
+}
+
+// History returns the newest changes first
+func (s *ConfigHistoryService) History(ctx context.Context, limit int) ([]*domain.ConfigChange, error) {
+	return s.repo.ListConfigChanges(ctx, limit)
+}
+
+// record stores the difference between before and after, if any. The
+// change is already live, so a storage failure is only logged.
+func (s *ConfigHistoryService) record(ctx context.Context, author, source string, rollbackOf *int64, before, after *config.Config) *domain.ConfigChange {
+	diff := settingsDiff(before.ReloadableValues(), after.ReloadableValues())
+	if len(diff) == 0 {
+		return nil
+	}
+	change := &domain.ConfigChange{
+		Author:     author,
+		Source:     source,
+		CreatedAt:  time.Now().UTC(),
+		Diff:       diff,
+		RollbackOf: rollbackOf,
+	}
+	if err := s.repo.AppendConfigChange(ctx, change); err != nil {
+		log.Printf("Config history: recording %s change by %s failed: %v", source, author, err)
+		return change
+	}
+	log.Printf("Config change #%d by %s (%s): %d settings", change.ID, author, source, len(diff))
+	return change
+}
+
+func settingsDiff(before, after map[string]string) []domain.SettingDiff {
+	var diff []domain.SettingDiff
+	for path, v := range after {
+		if old := before[path]; old != v {
+			diff = append(diff, domain.SettingDiff{Path: path, Old: old, New: v})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Path < diff[j].Path })
+	return diff
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+)
+
+// CorrelationMatrix holds the pairwise Pearson correlations of candle returns
+// among the top screened symbols
+type CorrelationMatrix struct {
+	ComputedAt time.Time `json:"computedAt"`
+	Timeframe  string    `json:"timeframe"`
+	Window     int       `json:"window"` // returns per correlation
+	Symbols    []string  `json:"symbols"`
+	// Values[i][j] correlates Symbols[i] with Symbols[j]; null when the two
+	// share too few candles (e.g. a fresh listing)
+	Values [][]*float64 `json:"values"`
+}
+
+// correlationSettings are the correlation.* settings, swapped as one unit on reload
+type correlationSettings struct {
+	topN      int
+	timeframe string
+	window    int
+}
+
+// CorrelationTracker recomputes the correlation matrix of the highest scoring
+// symbols after every screening cycle
+type CorrelationTracker struct {
+	settings atomic.Pointer[correlationSettings]
+	current  atomic.Pointer[CorrelationMatrix]
+}
+
+// NewCorrelationTracker creates a tracker with the correlation.* settings of cfg
+func NewCorrelationTracker(cfg *config.Config) *CorrelationTracker {
+	t := &CorrelationTracker{}
+	t.ApplyConfig(cfg)
+	return t
+}
+
+// ApplyConfig swaps in the reloadable correlation settings
+func (t *CorrelationTracker) ApplyConfig(cfg *config.Config) {
+	t.settings.Store(&correlationSettings{
+		topN:      cfg.Correlation.TopN,
+		timeframe: cfg.Correlation.Timeframe,
+		window:    cfg.Correlation.Window,
+	})
+}
+
+// Current is the last computed matrix, nil before the first one
+func (t *CorrelationTracker) Current() *CorrelationMatrix {
+	return t.current.Load()
+}
+
+// Update correlates the returns of the top coins by score (coins are sorted
+// highest first). Symbols whose klines fail are left out.
+func (t *CorrelationTracker) Update(ctx context.Context, coins []domain.CoinData, klines klineFetcher) error {
+	settings := t.settings.Load()
+	if settings.topN == 0 {
+		return nil
+	}
+	if len(coins) > settings.topN {
+		coins = coins[:settings.topN]
+	}
+
+	now := time.Now()
+	matrix := &CorrelationMatrix{ComputedAt: now.UTC(), Timeframe: settings.timeframe, Window: settings.window}
+	var series []map[int64]float64
+	for _, coin := range coins {
+		// window+1 closes give window returns; one more for the forming candle
+		raw, err := klines(ctx, coin.Symbol, settings.timeframe, settings.window+2)
+		if err != nil {
+			continue
+		}
+		candles := binance.ClosedCandles(raw, now)
+		if len(candles) > settings.window+1 {
+			candles = candles[len(candles)-settings.window-1:]
+		}
+		returns := make(map[int64]float64, len(candles))
+		for i := 1; i < len(candles); i++ {
+			if prev := candles[i-1].Close; prev > 0 {
+				returns[candles[i].OpenTime.Unix()] = (candles[i].Close - prev) / prev
+			}
+		}
+		matrix.Symbols = append(matrix.Symbols, coin.Symbol)
+		series = append(series, returns)
+	}
+	if len(matrix.Symbols) < 2 {
+		return fmt.Errorf("correlations: only %d of %d symbols have klines", len(matrix.Symbols), len(coins))
+	}
+
+	// Pairs need three quarters of the window in common
+	minOverlap := settings.window * 3 / 4
+	matrix.Values = make([][]*float64, len(series))
+	for i := range series {
+		matrix.Values[i] = make([]*float64, len(series))
+		one := 1.0
+		matrix.Values[i][i] = &one
+		for j := 0; j < i; j++ {
+			if r, ok := returnCorrelation(series[i], series[j], minOverlap); ok {
+				matrix.Values[i][j], matrix.Values[j][i] = &r, &r
+			}
+		}
+	}
+	t.current.Store(matrix)
+	return nil
+}
+
+// returnCorrelation is the Pearson correlation of two return series over the
+// candles they share
+func returnCorrelation(a, b map[int64]float64, minOverlap int) (float64, bool) {
+	var xs, ys []float64
+	for at, x := range a {
+		if y, ok := b[at]; ok {
+			xs = append(xs, x)
+			ys = append(ys, y)
+		}
+	}
+	if len(xs) < minOverlap || len(xs) < 3 {
+		return 0, false
+	}
+
+	n := float64(len(xs))
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return round2(cov / math.Sqrt(varX*varY)), true
+}
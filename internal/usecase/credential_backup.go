@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"screener-backend/internal/domain"
+)
+
+const (
+	credentialBackupVersion = 1
+	minBackupPassphrase     = 12
+	backupSaltSize          = 16
+	// backupKDFIterations follows the OWASP PBKDF2-SHA256 recommendation;
+	// imports accept up to maxBackupIterations
+	backupKDFIterations = 600000
+	maxBackupIterations = 10000000
+)
+
+var (
+	ErrWeakPassphrase = domain.Validation("PASSPHRASE_TOO_SHORT", fmt.Sprintf("passphrase must be at least %d characters", minBackupPassphrase))
+	ErrInvalidBackup  = domain.Validation("INVALID_BACKUP", "unsupported or malformed credential backup")
+	ErrBackupDecrypt  = domain.Validation("BACKUP_DECRYPT_FAILED", "wrong passphrase or corrupted backup")
+)
+
+// CredentialBackup is a portable dump of the stored Binance credentials.
+// The credentials are sealed with AES-256-GCM under a key derived from the
+// operator's passphrase (PBKDF2-SHA256), so the file can move between hosts
+// with different API_ENCRYPTION_KEYs without exposing any secret.
+type CredentialBackup struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Count      int       `json:"count"`
+	KDF        string    `json:"kdf"`
+	Iterations int       `json:"iterations"`
+	Salt       []byte    `json:"salt"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// CredentialImportResult summarizes a backup restore
+type CredentialImportResult struct {
+	Imported int      `json:"imported"`
+	Skipped  []string `json:"skipped"` // users that already had credentials
+}
+
+// CredentialBackupService exports and restores the credentials table
+type CredentialBackupService struct {
+	store domain.BinanceAPIStore
+}
+
+// NewCredentialBackupService creates a new backup service
+func NewCredentialBackupService(store domain.BinanceAPIStore) *CredentialBackupService {
+	return &CredentialBackupService{store: store}
+}
+
+// Export seals every user's credentials under passphrase
+func (s *CredentialBackupService) Export(ctx context.Context, passphrase string) (*CredentialBackup, error) {
+	if len(passphrase) < minBackupPassphrase {
+		return nil, ErrWeakPassphrase
+	}
+	creds, err := s.store.ListCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &CredentialBackup{
+		Version:    credentialBackupVersion,
+		ExportedAt: time.Now().UTC(),
+		Count:      len(creds),
+		KDF:        "pbkdf2-sha256",
+		Iterations: backupKDFIterations,
+		Salt:       make([]byte, backupSaltSize),
+	}
+	if _, err := rand.Read(backup.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := backupCipher(passphrase, backup)
+	if err != nil {
+		return nil, err
+	}
+	backup.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(backup.Nonce); err != nil {
+		return nil, err
+	}
+	backup.Ciphertext = gcm.Seal(nil, backup.Nonce, plaintext, backupAAD(backup))
+	return backup, nil
+}
+
+// Import opens backup with passphrase and stores its credentials, encrypted
+// with this host's key. Users that already have credentials are skipped
+// unless overwrite is set. Nothing is written if the backup can't be opened.
+func (s *CredentialBackupService) Import(ctx context.Context, backup *CredentialBackup, passphrase string, overwrite bool) (*CredentialImportResult, error) {
+	if backup == nil || backup.Version != credentialBackupVersion || backup.KDF != "pbkdf2-sha256" ||
+		backup.Iterations <= 0 || backup.Iterations > maxBackupIterations {
+		return nil, ErrInvalidBackup
+	}
+	gcm, err := backupCipher(passphrase, backup)
+	if err != nil {
+		return nil, err
+	}
+	if len(backup.Nonce) != gcm.NonceSize() {
+		return nil, ErrInvalidBackup
+	}
+	plaintext, err := gcm.Open(nil, backup.Nonce, backup.Ciphertext, backupAAD(backup))
+	if err != nil {
+		return nil, ErrBackupDecrypt
+	}
+	var creds []*domain.BinanceAPICredentials
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, ErrInvalidBackup
+	}
+
+	result := &CredentialImportResult{Skipped: []string{}}
+	for _, cred := range creds {
+		if cred == nil || cred.UserID == "" {
+			continue
+		}
+		if !overwrite {
+			_, err := s.store.GetCredentials(ctx, cred.UserID)
+			if err == nil {
+				result.Skipped = append(result.Skipped, cred.UserID)
+				continue
+			}
+			if !errors.Is(err, domain.ErrNotFound) {
+				return result, err
+			}
+		}
+		if err := s.store.SaveCredentials(ctx, cred); err != nil {
+			return result, fmt.Errorf("store credentials of %s: %w", cred.UserID, err)
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// backupCipher derives the AES-256-GCM cipher from passphrase and the
+// backup's salt and iteration count
+func backupCipher(passphrase string, backup *CredentialBackup) (cipher.AEAD, error) {
+	if len(backup.Salt) == 0 {
+		return nil, ErrInvalidBackup
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, backup.Salt, backup.Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// backupAAD binds the unencrypted header to the ciphertext
+func backupAAD(backup *CredentialBackup) []byte {
+	return fmt.Appendf(nil, "v%d|%s|%d", backup.Version, backup.ExportedAt.Format(time.RFC3339Nano), backup.Count)
+}
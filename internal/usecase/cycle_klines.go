@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+)
+
+// cycleKlines shares the klines of one screening cycle between its readers:
+// the strategies of a symbol read the same intervals several times (5m and
+// 15m up to three times), and the regime filter and the correlations read
+// series the strategies already loaded. Each symbol/interval series is
+// fetched once per cycle, with the largest limit asked so far; failed fetches
+// are not kept, so a later reader retries.
+type cycleKlines struct {
+	fetch klineFetcher
+
+	mu      sync.Mutex
+	series  map[string]cycleSeries // by symbol|interval
+	fetched int
+	reused  int
+}
+
+// cycleSeries is a fetched series: the klines, oldest first, and the limit
+// they were fetched with. A young symbol has fewer klines than the limit.
+type cycleSeries struct {
+	rows  [][]interface{}
+	limit int
+}
+
+func newCycleKlines(fetch klineFetcher) *cycleKlines {
+	return &cycleKlines{fetch: fetch, series: make(map[string]cycleSeries)}
+}
+
+// klines is the klineFetcher of the cycle. A symbol's strategies run on one
+// goroutine, so a series is not fetched twice concurrently.
+func (c *cycleKlines) klines(ctx context.Context, symbol, interval string, limit int) ([][]interface{}, error) {
+	key := symbol + "|" + interval
+	c.mu.Lock()
+	cached, ok := c.series[key]
+	if ok && cached.limit >= limit {
+		c.reused++
+		c.mu.Unlock()
+		return cached.rows[max(0, len(cached.rows)-limit):], nil
+	}
+	c.mu.Unlock()
+
+	rows, err := c.fetch(ctx, symbol, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.series[key] = cycleSeries{rows: rows, limit: limit}
+	c.fetched++
+	c.mu.Unlock()
+	return rows, nil
+}
+
+// stats is how many series were fetched and how many reads were served from
+// the cycle's klines
+func (c *cycleKlines) stats() (fetched, reused int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fetched, c.reused
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/fcm"
+	"screener-backend/internal/repository"
+)
+
+// DailySummaryService builds each user's end-of-day journal recap, pushes it
+// to the user's registered devices and stores it for later retrieval.
+type DailySummaryService struct {
+	tradeRepo   domain.TradeEntryRepository
+	summaryRepo domain.DailySummaryRepository
+	tokenRepo   *repository.TokenRepository
+	fcmClient   *fcm.Client
+}
+
+// NewDailySummaryService creates a new daily summary service
+func NewDailySummaryService(
+	tradeRepo domain.TradeEntryRepository,
+	summaryRepo domain.DailySummaryRepository,
+	tokenRepo *repository.TokenRepository,
+	fcmClient *fcm.Client,
+) *DailySummaryService {
+	return &DailySummaryService{
+		tradeRepo:   tradeRepo,
+		summaryRepo: summaryRepo,
+		tokenRepo:   tokenRepo,
+		fcmClient:   fcmClient,
+	}
+}
+
+// RunForDay summarizes the UTC day containing `day` for every journal user.
+// Users not reached before ctx is done are skipped and ctx's error returned.
+func (s *DailySummaryService) RunForDay(ctx context.Context, day time.Time) error {
+	for _, userID := range s.tradeRepo.GetUserIDs(ctx) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		summary := s.BuildSummary(ctx, userID, day)
+		summary.Notified = s.notify(ctx, summary)
+		if err := s.summaryRepo.SaveSummary(ctx, summary); err != nil {
+			log.Printf("Daily summary: failed to store %s for %s: %v", summary.Date, userID, err)
+		}
+	}
+	return ctx.Err()
+}
+
+// BuildSummary computes the user's summary for the UTC day containing `day`
+func (s *DailySummaryService) BuildSummary(ctx context.Context, userID string, day time.Time) *domain.DailyTradeSummary {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	dayEnd := dayStart.Add(24 * time.Hour)
+	inDay := func(t time.Time) bool { return !t.Before(dayStart) && t.Before(dayEnd) }
+
+	summary := &domain.DailyTradeSummary{
+		UserID:    userID,
+		Date:      dayStart.Format("2006-01-02"),
+		CreatedAt: time.Now(),
+	}
+
+	active := s.tradeRepo.GetActiveEntries(ctx, userID)
+	for _, e := range active {
+		if inDay(e.EntryTime) {
+			summary.TradesTaken++
+		}
+		summary.OpenTrades++
+		summary.OpenRisk += e.OpenRisk()
+	}
+
+	wins := 0
+	closed := s.tradeRepo.GetEntryHistory(ctx, userID, domain.TradeHistoryFilter{From: &dayStart})
+	for _, e := range closed {
+		if inDay(e.EntryTime) {
+			summary.TradesTaken++
+		}
+		if e.ExitTime == nil || !inDay(*e.ExitTime) || e.ProfitLoss == nil {
+			continue
+		}
+		summary.TradesClosed++
+		summary.NetPL += *e.ProfitLoss
+		if *e.ProfitLoss > 0 {
+			wins++
+		}
+	}
+	if summary.TradesClosed > 0 {
+		summary.WinRate = round2(float64(wins) / float64(summary.TradesClosed) * 100)
+	}
+	summary.NetPL = round2(summary.NetPL)
+	summary.OpenRisk = round2(summary.OpenRisk)
+	return summary
+}
+
+// notify pushes the summary to the user's devices; reports whether it was sent
+func (s *DailySummaryService) notify(ctx context.Context, summary *domain.DailyTradeSummary) bool {
+	if s.fcmClient == nil || !s.fcmClient.IsEnabled() {
+		return false
+	}
+	tokens := s.tokenRepo.GetTokensForUser(summary.UserID)
+	if len(tokens) == 0 {
+		return false
+	}
+
+	title := fmt.Sprintf("📒 Journal %s", summary.Date)
+	body := fmt.Sprintf("%d taken | P/L %+.2f USDT | Win %.0f%% | %d open, risk %.2f USDT",
+		summary.TradesTaken, summary.NetPL, summary.WinRate, summary.OpenTrades, summary.OpenRisk)
+	data := map[string]string{
+		"type":        "DAILY_SUMMARY",
+		"date":        summary.Date,
+		"tradesTaken": fmt.Sprintf("%d", summary.TradesTaken),
+		"netPL":       fmt.Sprintf("%.2f", summary.NetPL),
+		"winRate":     fmt.Sprintf("%.2f", summary.WinRate),
+		"openRisk":    fmt.Sprintf("%.2f", summary.OpenRisk),
+	}
+
+	if err := s.fcmClient.SendMulticast(ctx, tokens, title, body, data); err != nil {
+		log.Printf("Daily summary: failed to notify %s: %v", summary.UserID, err)
+		return false
+	}
+	return true
+}
+
+// GetSummaries returns the user's stored summaries, newest first
+func (s *DailySummaryService) GetSummaries(ctx context.Context, userID string, limit int) []*domain.DailyTradeSummary {
+	return s.summaryRepo.GetSummaries(ctx, userID, limit)
+}
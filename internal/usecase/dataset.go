@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/parquet"
+)
+
+const (
+	// maxDatasetRows bounds one export; narrow the range for more
+	maxDatasetRows = 200000
+	maxDatasetSpan = 31 * 24 * time.Hour
+	// labelInterval is the archived candle interval forward returns are read from
+	labelInterval = "1m"
+)
+
+var (
+	ErrDatasetSpan     = domain.Validation("DATASET_RANGE_TOO_LONG", "dataset range must not exceed 31 days")
+	ErrDatasetTooLarge = domain.Validation("DATASET_TOO_LARGE", fmt.Sprintf("more than %d snapshots in range; narrow from/to or pick a symbol", maxDatasetRows))
+)
+
+// Dataset is a flat table of archived snapshots: the coin's scores, its
+// primary-TF MarketFeatures, per-TF scores and forward-return labels
+type Dataset struct {
+	Columns []parquet.Column
+	Rows    [][]any // values follow Columns: float64 (NaN = missing), time.Time or string
+}
+
+// DatasetService builds feature datasets from the market archive for
+// training models on the screener's features
+type DatasetService struct {
+	archive domain.MarketArchiveRepository
+}
+
+// NewDatasetService creates a new dataset service
+func NewDatasetService(archive domain.MarketArchiveRepository) *DatasetService {
+	return &DatasetService{archive: archive}
+}
+
+// datasetColumn is one column and how to read it from a snapshot
+type datasetColumn struct {
+	parquet.Column
+	value func(s *domain.CoinSnapshot) any
+}
+
+// Build returns one row per archived snapshot in [from, to], of symbol or
+// of every symbol when empty, oldest first. The ret_<horizon> labels are the
+// percent price change after each signal horizon, read from the archived 1m
+// candles; NaN when the archive doesn't cover it yet.
+func (s *DatasetService) Build(ctx context.Context, symbol string, from, to time.Time) (*Dataset, error) {
+	snapshots, labels, err := s.labeledSnapshots(ctx, symbol, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := datasetColumns(snapshots)
+	ds := &Dataset{Rows: make([][]any, len(snapshots))}
+	for _, c := range columns {
+		ds.Columns = append(ds.Columns, c.Column)
+	}
+	for _, h := range domain.SignalHorizons {
+		ds.Columns = append(ds.Columns, parquet.Column{Name: "ret_" + h.Name, Type: parquet.Double})
+	}
+	for i := range snapshots {
+		row := make([]any, 0, len(ds.Columns))
+		for _, c := range columns {
+			row = append(row, c.value(&snapshots[i]))
+		}
+		for _, ret := range labels[i] {
+			row = append(row, ret)
+		}
+		ds.Rows[i] = row
+	}
+	return ds, nil
+}
+
+// labeledSnapshots loads the archived snapshots in [from, to], oldest first,
+// with their forward returns per domain.SignalHorizons
+func (s *DatasetService) labeledSnapshots(ctx context.Context, symbol string, from, to time.Time) ([]domain.CoinSnapshot, [][]float64, error) {
+	if to.Sub(from) > maxDatasetSpan {
+		return nil, nil, ErrDatasetSpan
+	}
+	snapshots, err := s.archive.ListSnapshots(ctx, symbol, from, to, maxDatasetRows+1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(snapshots) > maxDatasetRows {
+		return nil, nil, ErrDatasetTooLarge
+	}
+	labels, err := s.forwardReturns(ctx, snapshots)
+	if err != nil {
+		return nil, nil, err
+	}
+	return snapshots, labels, nil
+}
+
+// forwardReturns labels each snapshot with its return at every horizon
+func (s *DatasetService) forwardReturns(ctx context.Context, snapshots []domain.CoinSnapshot) ([][]float64, error) {
+	labels := make([][]float64, len(snapshots))
+	bySymbol := make(map[string][]int)
+	for i, snap := range snapshots {
+		bySymbol[snap.Coin.Symbol] = append(bySymbol[snap.Coin.Symbol], i)
+	}
+
+	longest := domain.SignalHorizons[len(domain.SignalHorizons)-1].After
+	for symbol, idx := range bySymbol {
+		first, last := snapshots[idx[0]].TakenAt, snapshots[idx[len(idx)-1]].TakenAt
+		candles, err := s.archive.GetCandles(ctx, symbol, labelInterval, first.Add(-time.Minute), last.Add(longest+time.Minute))
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range idx {
+			snap := &snapshots[i]
+			labels[i] = make([]float64, len(domain.SignalHorizons))
+			for j, h := range domain.SignalHorizons {
+				labels[i][j] = math.NaN()
+				if price, ok := closeAt(candles, snap.TakenAt.Add(h.After)); ok && snap.Coin.Price > 0 {
+					labels[i][j] = (price - snap.Coin.Price) / snap.Coin.Price * 100
+				}
+			}
+		}
+	}
+	return labels, nil
+}
+
+// closeAt is the close of the 1m candle containing t
+func closeAt(candles []domain.Candle, t time.Time) (float64, bool) {
+	i := sort.Search(len(candles), func(i int) bool { return candles[i].OpenTime.After(t) }) - 1
+	if i < 0 || t.Sub(candles[i].OpenTime) >= time.Minute {
+		return 0, false
+	}
+	return candles[i].Close, true
+}
+
+// datasetColumns lists the coin-level columns, every scalar MarketFeatures
+// field (as feat_<json name>) and the per-TF scores of the timeframes seen
+func datasetColumns(snapshots []domain.CoinSnapshot) []datasetColumn {
+	num := func(name string, get func(c *domain.CoinData) float64) datasetColumn {
+		return datasetColumn{parquet.Column{Name: name, Type: parquet.Double}, func(s *domain.CoinSnapshot) any { return get(&s.Coin) }}
+	}
+	text := func(name string, get func(c *domain.CoinData) string) datasetColumn {
+		return datasetColumn{parquet.Column{Name: name, Type: parquet.String}, func(s *domain.CoinSnapshot) any { return get(&s.Coin) }}
+	}
+
+	columns := []datasetColumn{
+		{parquet.Column{Name: "taken_at", Type: parquet.Timestamp}, func(s *domain.CoinSnapshot) any { return s.TakenAt.UTC() }},
+		text("symbol", func(c *domain.CoinData) string { return c.Symbol }),
+		num("price", func(c *domain.CoinData) float64 { return c.Price }),
+		num("score", func(c *domain.CoinData) float64 { return c.Score }),
+		text("status", func(c *domain.CoinData) string { return c.Status }),
+		text("trigger_tf", func(c *domain.CoinData) string { return c.TriggerTF }),
+		num("confluence_count", func(c *domain.CoinData) float64 { return float64(c.ConfluenceCount) }),
+		num("price_change_pct", func(c *domain.CoinData) float64 { return c.PriceChangePercent }),
+		num("funding_rate", func(c *domain.CoinData) float64 { return c.FundingRate }),
+		num("basis_spread", func(c *domain.CoinData) float64 { return c.BasisSpread }),
+		text("intraday_status", func(c *domain.CoinData) string { return c.IntradayStatus }),
+		num("intraday_score", func(c *domain.CoinData) float64 { return c.IntradayScore }),
+		text("pullback_status", func(c *domain.CoinData) string { return c.PullbackStatus }),
+		num("pullback_score", func(c *domain.CoinData) float64 { return c.PullbackScore }),
+		text("breakout_status", func(c *domain.CoinData) string { return c.BreakoutStatus }),
+		text("breakout_direction", func(c *domain.CoinData) string { return c.BreakoutDirection }),
+		num("breakout_score", func(c *domain.CoinData) float64 { return c.BreakoutScore }),
+		text("breakout_squeeze", func(c *domain.CoinData) string { return c.BreakoutSqueeze }),
+		text("follow_trend_status", func(c *domain.CoinData) string { return c.FollowTrendStatus }),
+		text("follow_trend_direction", func(c *domain.CoinData) string { return c.FollowTrendDirection }),
+		num("follow_trend_score", func(c *domain.CoinData) float64 { return c.FollowTrendScore }),
+	}
+	columns = append(columns, featureColumns()...)
+
+	tfs := make(map[string]bool)
+	for _, s := range snapshots {
+		for _, tf := range s.Coin.TFScores {
+			tfs[tf.TF] = true
+		}
+	}
+	names := make([]string, 0, len(tfs))
+	for tf := range tfs {
+		names = append(names, tf)
+	}
+	sort.Slice(names, func(i, j int) bool { return timeframeLess(names[i], names[j]) })
+	for _, tf := range names {
+		columns = append(columns,
+			num("score_"+tf, func(c *domain.CoinData) float64 {
+				for _, s := range c.TFScores {
+					if s.TF == tf {
+						return s.Score
+					}
+				}
+				return math.NaN()
+			}),
+			num("rsi_"+tf, func(c *domain.CoinData) float64 {
+				for _, s := range c.TFScores {
+					if s.TF == tf {
+						return s.RSI
+					}
+				}
+				return math.NaN()
+			}),
+			num("overext_ema_"+tf, func(c *domain.CoinData) float64 {
+				for _, f := range c.TFFeatures {
+					if f.TF == tf {
+						return f.OverExtEma
+					}
+				}
+				return math.NaN()
+			}),
+		)
+	}
+	return columns
+}
+
+// featureColumns reads the scalar MarketFeatures fields by reflection, so new
+// features show up in the export without changes here. Bools are 0/1; nil
+// pointers and coins without features are NaN (or empty for strings).
+func featureColumns() []datasetColumn {
+	var columns []datasetColumn
+	t := reflect.TypeOf(domain.MarketFeatures{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		index := i
+		typ := parquet.Double
+		switch f.Type.Kind() {
+		case reflect.String:
+			typ = parquet.String
+		case reflect.Float64, reflect.Int, reflect.Bool:
+		case reflect.Pointer:
+			if f.Type.Elem().Kind() != reflect.Float64 {
+				continue
+			}
+		default:
+			continue
+		}
+		columns = append(columns, datasetColumn{parquet.Column{Name: "feat_" + name, Type: typ}, func(s *domain.CoinSnapshot) any {
+			if s.Coin.Features == nil {
+				if typ == parquet.String {
+					return ""
+				}
+				return math.NaN()
+			}
+			return featureValue(reflect.ValueOf(s.Coin.Features).Elem().Field(index))
+		}})
+	}
+	return columns
+}
+
+func featureValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Float64:
+		return v.Float()
+	case reflect.Int:
+		return float64(v.Int())
+	case reflect.Bool:
+		if v.Bool() {
+			return 1.0
+		}
+		return 0.0
+	case reflect.Pointer:
+		if v.IsNil() {
+			return math.NaN()
+		}
+		return v.Elem().Float()
+	}
+	return math.NaN()
+}
+
+// timeframeLess orders timeframes by length (1m < 5m < 1h)
+func timeframeLess(a, b string) bool {
+	return timeframeMinutes(a) < timeframeMinutes(b)
+}
+
+func timeframeMinutes(tf string) int {
+	var n int
+	var unit byte
+	if _, err := fmt.Sscanf(tf, "%d%c", &n, &unit); err != nil {
+		return math.MaxInt
+	}
+	switch unit {
+	case 'h':
+		return n * 60
+	case 'd':
+		return n * 24 * 60
+	case 'w':
+		return n * 7 * 24 * 60
+	default:
+		return n
+	}
+}
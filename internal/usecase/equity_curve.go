@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"screener-backend/internal/domain"
+	"sort"
+)
+
+// BuildEquityCurve sorts closed-trade results by time and accumulates them.
+// Only Time, TradeID, Symbol and ProfitLoss of the input points are read.
+func BuildEquityCurve(points []domain.EquityPoint) *domain.EquityCurve {
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+
+	curve := &domain.EquityCurve{Points: make([]domain.EquityPoint, 0, len(points))}
+	equity, peak := 0.0, 0.0
+	var maxDDPeak float64
+
+	for _, p := range points {
+		equity += p.ProfitLoss
+		if equity > peak {
+			peak = equity
+		}
+		p.Equity = equity
+		p.Drawdown = peak - equity
+		if p.Drawdown > curve.MaxDrawdown {
+			curve.MaxDrawdown = p.Drawdown
+			maxDDPeak = peak
+			at := p.Time
+			curve.MaxDrawdownAt = &at
+		}
+		curve.Points = append(curve.Points, p)
+	}
+
+	curve.NetProfit = equity
+	curve.PeakEquity = peak
+	if curve.MaxDrawdown > 0 && maxDDPeak > 0 {
+		pct := round2(curve.MaxDrawdown / maxDDPeak * 100)
+		curve.MaxDrawdownPct = &pct
+	}
+	return curve
+}
+
+// TradeEntryEquityPoints converts closed journal entries into curve inputs
+func TradeEntryEquityPoints(entries []*domain.TradeEntry) []domain.EquityPoint {
+	points := make([]domain.EquityPoint, 0, len(entries))
+	for _, e := range entries {
+		if e.ProfitLoss == nil {
+			continue
+		}
+		at := e.EntryTime
+		if e.ExitTime != nil {
+			at = *e.ExitTime
+		}
+		points = append(points, domain.EquityPoint{Time: at, TradeID: e.ID, Symbol: e.Symbol, ProfitLoss: *e.ProfitLoss})
+	}
+	return points
+}
+
+// AutoScalpEquityPoints converts closed auto scalp entries into curve inputs
+func AutoScalpEquityPoints(entries []*domain.AutoScalpEntry) []domain.EquityPoint {
+	points := make([]domain.EquityPoint, 0, len(entries))
+	for _, e := range entries {
+		if e.ProfitLoss == nil || e.ExitTime == nil {
+			continue
+		}
+		points = append(points, domain.EquityPoint{Time: *e.ExitTime, TradeID: e.ID, Symbol: e.Symbol, ProfitLoss: *e.ProfitLoss})
+	}
+	return points
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+const (
+	fillInterval = "1m"
+	// maxFillCandles is one kline request; a position left unchecked longer
+	// is only walked over its last maxFillCandles minutes
+	maxFillCandles = 1000
+	// fillBookDepth is how many levels of the order book a stop fill may walk
+	fillBookDepth = 100
+)
+
+// fillSettings are the fillSim.* settings, swapped as one unit on reload
+type fillSettings struct {
+	enabled     bool
+	orderBook   bool
+	slippageBps float64
+}
+
+// FillSimulator models how the stops and take profits of paper trades would
+// actually have filled. Instead of comparing one sampled price with a level,
+// the monitors walk the 1m candles traded since their last check: a level is
+// reached when the candle's range crosses it, and an order the market gapped
+// through fills at the candle's open. Stop fills are worsened by a flat
+// slippage, or with fillSim.orderBook by walking a depth snapshot for the
+// position's size.
+type FillSimulator struct {
+	client   *binance.Client
+	settings atomic.Pointer[fillSettings]
+	now      func() time.Time
+}
+
+// NewFillSimulator creates a fill simulator with the fillSim.* settings of cfg
+func NewFillSimulator(client *binance.Client, cfg *config.Config) *FillSimulator {
+	f := &FillSimulator{client: client, now: time.Now}
+	f.ApplyConfig(cfg)
+	return f
+}
+
+// ApplyConfig swaps in the reloadable fill settings
+func (f *FillSimulator) ApplyConfig(cfg *config.Config) {
+	f.settings.Store(&fillSettings{
+		enabled:     cfg.FillSim.Enabled,
+		orderBook:   cfg.FillSim.OrderBook,
+		slippageBps: cfg.FillSim.SlippageBps,
+	})
+}
+
+// Enabled reports whether the monitors should walk candles; nil simulators
+// are off
+func (f *FillSimulator) Enabled() bool {
+	return f != nil && f.settings.Load().enabled
+}
+
+// Candles returns the 1m candles opened at or after since, oldest first. The
+// last one may still be forming (see candleClosed).
+func (f *FillSimulator) Candles(ctx context.Context, symbol string, since time.Time) ([]domain.Candle, error) {
+	since = since.Truncate(time.Minute)
+	limit := int(f.now().Sub(since)/time.Minute) + 1
+	if limit < 1 {
+		return nil, nil
+	}
+	if limit > maxFillCandles {
+		limit = maxFillCandles
+	}
+	raw, err := f.client.GetKlines(ctx, symbol, fillInterval, limit)
+	if err != nil {
+		return nil, err
+	}
+	candles := binance.Candles(raw)
+	for i, c := range candles {
+		if !c.OpenTime.Before(since) {
+			return candles[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+// candleClosed reports whether the 1m candle is complete
+func (f *FillSimulator) candleClosed(c domain.Candle) bool {
+	return !c.OpenTime.Add(time.Minute).After(f.now())
+}
+
+// Slip worsens a stop's fill price for an order of notional USDT; buy is the
+// order's side. The book is used when fillSim.orderBook is on and a snapshot
+// loads, else the flat fillSim.slippageBps.
+func (f *FillSimulator) Slip(ctx context.Context, symbol string, price, notional float64, buy bool) float64 {
+	settings := f.settings.Load()
+	impact := settings.slippageBps / 10000
+	if settings.orderBook && notional > 0 {
+		book, err := f.client.GetOrderBook(ctx, symbol, fillBookDepth)
+		if err != nil {
+			logging.Debugf("Fill simulation: %s order book: %v", symbol, err)
+		} else if bookImpact, ok := orderBookImpact(book, notional, buy); ok {
+			impact = bookImpact
+		}
+	}
+	if buy {
+		return price * (1 + impact)
+	}
+	return price * (1 - impact)
+}
+
+// orderBookImpact is how much worse than the best price a market order of
+// notional USDT fills on average, as a fraction. An order larger than the
+// snapshot is priced at the levels it does cover.
+func orderBookImpact(book *binance.OrderBook, notional float64, buy bool) (float64, bool) {
+	levels := book.Bids
+	if buy {
+		levels = book.Asks
+	}
+	if len(levels) == 0 || levels[0].Price <= 0 {
+		return 0, false
+	}
+	var cost, qty float64
+	for _, l := range levels {
+		take := math.Min(l.Qty, (notional-cost)/l.Price)
+		cost += take * l.Price
+		qty += take
+		if cost >= notional {
+			break
+		}
+	}
+	if qty <= 0 {
+		return 0, false
+	}
+	best := levels[0].Price
+	avg := cost / qty
+	if buy {
+		return avg/best - 1, true
+	}
+	return 1 - avg/best, true
+}
+
+// stopFill is where a stop at level fills within candle c: at the level when
+// the candle trades through it, at the open when the candle opened beyond it
+// (gapped through the stop). buy is a buy stop, a SHORT's stop loss.
+func stopFill(c domain.Candle, level float64, buy bool) (float64, bool) {
+	if level <= 0 {
+		return 0, false
+	}
+	if buy {
+		switch {
+		case c.Open >= level:
+			return c.Open, true
+		case c.High >= level:
+			return level, true
+		}
+		return 0, false
+	}
+	switch {
+	case c.Open <= level:
+		return c.Open, true
+	case c.Low <= level:
+		return level, true
+	}
+	return 0, false
+}
+
+// limitFill is where a take profit at level fills within candle c: at the
+// level, or at the better open when the candle opened beyond it. buy is a
+// buy limit, a SHORT's take profit.
+func limitFill(c domain.Candle, level float64, buy bool) (float64, bool) {
+	if level <= 0 {
+		return 0, false
+	}
+	if buy {
+		switch {
+		case c.Open <= level:
+			return c.Open, true
+		case c.Low <= level:
+			return level, true
+		}
+		return 0, false
+	}
+	switch {
+	case c.Open >= level:
+		return c.Open, true
+	case c.High >= level:
+		return level, true
+	}
+	return 0, false
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+
+	"screener-backend/internal/domain"
+)
+
+// Heatmap strategies: which of a coin's per-timeframe scores fill the cells
+const (
+	HeatmapReversal = "reversal" // scalp (1m, 5m) and intraday (15m, 1h) reversal scores
+	HeatmapPullback = "pullback"
+	HeatmapBreakout = "breakout"
+	HeatmapTrend    = "trend"
+)
+
+var ErrHeatmapStrategy = domain.Validation("INVALID_HEATMAP_STRATEGY", "strategy must be reversal, pullback, breakout or trend")
+
+// Heatmap is a compact symbols × timeframes view of one cycle: Scores[i][j]
+// and RSI[i][j] belong to Symbols[i] on Timeframes[j], null where the symbol
+// has no reading on that timeframe
+type Heatmap struct {
+	Strategy   string       `json:"strategy"`
+	Timeframes []string     `json:"timeframes"`
+	Symbols    []string     `json:"symbols"`
+	Scores     [][]*float64 `json:"scores"`
+	RSI        [][]*float64 `json:"rsi"`
+}
+
+// BuildHeatmap lays out the strategy's per-timeframe scores of coins, highest
+// strategy score first, for the top limit coins (all when limit is 0)
+func BuildHeatmap(coins []domain.CoinData, strategy string, limit int) (*Heatmap, error) {
+	tfScores, score := heatmapScores(strategy)
+	if tfScores == nil {
+		return nil, ErrHeatmapStrategy
+	}
+	coins = append([]domain.CoinData(nil), coins...)
+	sort.SliceStable(coins, func(i, j int) bool { return score(&coins[i]) > score(&coins[j]) })
+	if limit > 0 && len(coins) > limit {
+		coins = coins[:limit]
+	}
+
+	seen := make(map[string]bool)
+	for i := range coins {
+		for _, s := range tfScores(&coins[i]) {
+			seen[s.TF] = true
+		}
+	}
+	hm := &Heatmap{Strategy: strategy, Timeframes: make([]string, 0, len(seen)), Symbols: make([]string, len(coins)), Scores: make([][]*float64, len(coins)), RSI: make([][]*float64, len(coins))}
+	for tf := range seen {
+		hm.Timeframes = append(hm.Timeframes, tf)
+	}
+	sort.Slice(hm.Timeframes, func(i, j int) bool { return timeframeLess(hm.Timeframes[i], hm.Timeframes[j]) })
+	column := make(map[string]int, len(hm.Timeframes))
+	for j, tf := range hm.Timeframes {
+		column[tf] = j
+	}
+
+	for i := range coins {
+		hm.Symbols[i] = coins[i].Symbol
+		hm.Scores[i] = make([]*float64, len(hm.Timeframes))
+		hm.RSI[i] = make([]*float64, len(hm.Timeframes))
+		for _, s := range tfScores(&coins[i]) {
+			j := column[s.TF]
+			score, rsi := round1(s.Score), round1(s.RSI)
+			hm.Scores[i][j], hm.RSI[i][j] = &score, &rsi
+		}
+	}
+	return hm, nil
+}
+
+// heatmapScores picks a coin's per-timeframe scores and overall score for
+// strategy; nil for an unknown strategy
+func heatmapScores(strategy string) (func(c *domain.CoinData) []domain.TimeframeScore, func(c *domain.CoinData) float64) {
+	switch strategy {
+	case HeatmapReversal:
+		return func(c *domain.CoinData) []domain.TimeframeScore {
+			return append(append([]domain.TimeframeScore{}, c.TFScores...), c.IntradayTFScores...)
+		}, func(c *domain.CoinData) float64 { return c.Score }
+	case HeatmapPullback:
+		return func(c *domain.CoinData) []domain.TimeframeScore { return c.PullbackTFScores },
+			func(c *domain.CoinData) float64 { return c.PullbackScore }
+	case HeatmapBreakout:
+		return func(c *domain.CoinData) []domain.TimeframeScore { return c.BreakoutTFScores },
+			func(c *domain.CoinData) float64 { return c.BreakoutScore }
+	case HeatmapTrend:
+		return func(c *domain.CoinData) []domain.TimeframeScore { return c.FollowTrendTFScores },
+			func(c *domain.CoinData) float64 { return c.FollowTrendScore }
+	}
+	return nil, nil
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+)
+
+// liquidationBurst is the recent span a liquidation cascade is measured over
+const liquidationBurst = 5 * time.Minute
+
+// liquidationFlow is a symbol's forced liquidations over
+// screener.liquidationWindow and over the last liquidationBurst
+type liquidationFlow struct {
+	Window binance.LiquidationTotals `json:"window"`
+	Recent binance.LiquidationTotals `json:"recent"`
+}
+
+// liquidationSource reads the symbols' liquidations from feed; none when
+// the feed is off
+func liquidationSource(feed *binance.LiquidationFeed, window time.Duration) func(symbol string) liquidationFlow {
+	return func(symbol string) liquidationFlow {
+		if feed == nil {
+			return liquidationFlow{}
+		}
+		return liquidationFlow{Window: feed.Totals(symbol, window), Recent: feed.Totals(symbol, liquidationBurst)}
+	}
+}
+
+// applyLiquidations fills the liquidation features. A cascade is at least
+// cascadeUSD of long liquidations in the last liquidationBurst, mostly
+// longs, coming faster than over the window as a whole.
+func applyLiquidations(features *domain.MarketFeatures, flow liquidationFlow, window time.Duration, cascadeUSD float64) {
+	features.LongLiquidations = flow.Window.Long
+	features.ShortLiquidations = flow.Window.Short
+	features.LiquidationImbalance = flow.Window.Imbalance()
+
+	accelerating := flow.Window.Long > 0 && flow.Recent.Long/flow.Window.Long >= float64(liquidationBurst)/float64(window)
+	features.LiquidationCascade = flow.Recent.Long >= cascadeUSD && flow.Recent.Imbalance() >= 0.5 && accelerating
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/marketdata"
+)
+
+const (
+	// marketContextMaxAge is how old a reading may be and still move scores
+	marketContextMaxAge = 2 * time.Hour
+	// marketCapSwing is the 24h market cap change that counts fully towards
+	// the pullback modifier
+	marketCapSwing = 5.0
+)
+
+// MarketContextService keeps the latest global market context: BTC
+// dominance and total market cap from CoinGecko, and the Fear & Greed index.
+// With marketContext.scoreModifier set it also scales reversal and pullback
+// scores by the context.
+type MarketContextService struct {
+	client   *marketdata.Client
+	modifier atomic.Pointer[float64]
+
+	mu      sync.RWMutex
+	current *domain.MarketContext
+}
+
+// NewMarketContextService creates a new market context service
+func NewMarketContextService(client *marketdata.Client, cfg *config.Config) *MarketContextService {
+	s := &MarketContextService{client: client}
+	s.ApplyConfig(cfg)
+	return s
+}
+
+// ApplyConfig swaps in the reloadable score modifier
+func (s *MarketContextService) ApplyConfig(cfg *config.Config) {
+	m := cfg.MarketContext.ScoreModifier
+	s.modifier.Store(&m)
+}
+
+// Refresh fetches both sources. A source that fails keeps its previous
+// values; the errors are returned together.
+func (s *MarketContextService) Refresh(ctx context.Context) error {
+	global, globalErr := s.client.GetGlobal(ctx)
+	fng, fngErr := s.client.GetFearGreed(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := domain.MarketContext{}
+	if s.current != nil {
+		next = *s.current
+	}
+	if globalErr == nil {
+		next.BTCDominance = global.BTCDominance
+		next.ETHDominance = global.ETHDominance
+		next.TotalMarketCapUSD = global.TotalMarketCapUSD
+		next.MarketCapChange24hPct = global.MarketCapChange24hPct
+		next.GlobalUpdatedAt = global.UpdatedAt
+	}
+	if fngErr == nil {
+		next.FearGreed = fng.Value
+		next.FearGreedLabel = fng.Classification
+		next.FearGreedUpdatedAt = fng.Timestamp
+	}
+	if globalErr == nil || fngErr == nil {
+		s.current = &next
+	}
+	return errors.Join(globalErr, fngErr)
+}
+
+// Current is the latest context, nil before the first successful fetch
+func (s *MarketContextService) Current() *domain.MarketContext {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.current == nil {
+		return nil
+	}
+	c := *s.current
+	return &c
+}
+
+// ShortMultiplier scales SHORT reversal scores: up to 1+modifier in extreme
+// greed (a crowded long side to fade), down to 1-modifier in extreme fear.
+// 1 when the modifier is off or the index is stale.
+func (s *MarketContextService) ShortMultiplier() float64 {
+	m := *s.modifier.Load()
+	c := s.Current()
+	if m == 0 || c == nil || c.FearGreedUpdatedAt.IsZero() || time.Since(c.FearGreedUpdatedAt) > 24*time.Hour+marketContextMaxAge {
+		return 1
+	}
+	greed := float64(c.FearGreed-50) / 50
+	return 1 + m*greed
+}
+
+// LongMultiplier scales pullback LONG scores with the total market cap's
+// 24h change: a market falling 5% or more cuts them by the full modifier
+// (falling knives), one rising as much raises them by it. 1 when the
+// modifier is off or the data is stale.
+func (s *MarketContextService) LongMultiplier() float64 {
+	m := *s.modifier.Load()
+	c := s.Current()
+	if m == 0 || c == nil || c.GlobalUpdatedAt.IsZero() || time.Since(c.GlobalUpdatedAt) > marketContextMaxAge {
+		return 1
+	}
+	trend := math.Max(-1, math.Min(1, c.MarketCapChange24hPct/marketCapSwing))
+	return 1 + m*trend
+}
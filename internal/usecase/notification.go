@@ -1,15 +1,94 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/logging"
+	"screener-backend/internal/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// sendMulticast sends one alert to every device inside a notification span.
+// During an event blackout the alert is tagged as high event risk.
+func (uc *ScreenerUsecase) sendMulticast(ctx context.Context, tokens []string, title, body string, data map[string]string) error {
+	if w := uc.blackouts.Active(ctx); w != nil {
+		body = fmt.Sprintf("⚠️ High event risk (%s) | %s", w.Name, body)
+		data["eventRisk"] = "HIGH"
+		data["eventKind"] = w.Kind
+		data["eventName"] = w.Name
+		data["eventEnds"] = w.End.UTC().Format(time.RFC3339)
+	}
+	ctx, span := tracer.Start(ctx, "notification.send", trace.WithAttributes(
+		attribute.String("symbol", data["symbol"]),
+		attribute.String("notification.type", data["type"]),
+		attribute.Int("notification.devices", len(tokens)),
+	))
+	err := uc.fcmClient.SendMulticast(ctx, tokens, title, body, data)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// RestoreCooldowns loads notification timestamps persisted by a previous run
+// or by another instance
+func (uc *ScreenerUsecase) RestoreCooldowns(ctx context.Context) {
+	if uc.cooldowns == nil {
+		return
+	}
+	saved, err := uc.cooldowns.LoadCooldowns(ctx, time.Now().Add(-uc.settings.Load().notifyCooldown))
+	if err != nil {
+		logging.Warnf("Failed to load notification cooldowns: %v", err)
+		return
+	}
+	uc.mu.Lock()
+	for key, at := range saved {
+		uc.notifiedCoins[key] = at
+	}
+	uc.mu.Unlock()
+	if len(saved) > 0 {
+		logging.Infof("Restored %d notification cooldowns", len(saved))
+	}
+}
+
+// markNotified records an alert in memory and in the cooldown store
+func (uc *ScreenerUsecase) markNotified(ctx context.Context, key string, at time.Time) {
+	uc.mu.Lock()
+	uc.notifiedCoins[key] = at
+	uc.mu.Unlock()
+
+	if uc.cooldowns != nil {
+		if err := uc.cooldowns.SaveCooldown(ctx, key, at); err != nil {
+			logging.Warnf("Failed to persist cooldown for %s: %v", key, err)
+		}
+	}
+}
+
+// pruneCooldowns drops entries well past the cooldown period
+func (uc *ScreenerUsecase) pruneCooldowns(ctx context.Context, now time.Time) {
+	cooldown := uc.settings.Load().notifyCooldown
+	uc.mu.Lock()
+	for key, timestamp := range uc.notifiedCoins {
+		if now.Sub(timestamp) > cooldown*2 {
+			delete(uc.notifiedCoins, key)
+		}
+	}
+	uc.mu.Unlock()
+
+	if uc.cooldowns != nil {
+		if err := uc.cooldowns.PruneCooldowns(ctx, now.Add(-cooldown * 2)); err != nil {
+			logging.Warnf("Failed to prune notification cooldowns: %v", err)
+		}
+	}
+}
+
 // sendNotificationsForTriggers sends FCM notifications for coins with TRIGGER status only
-func (uc *ScreenerUsecase) sendNotificationsForTriggers(coins []domain.CoinData) {
+func (uc *ScreenerUsecase) sendNotificationsForTriggers(ctx context.Context, coins []domain.CoinData) {
 	if uc.fcmClient == nil || !uc.fcmClient.IsEnabled() {
 		return // FCM not configured
 	}
@@ -20,7 +99,7 @@ func (uc *ScreenerUsecase) sendNotificationsForTriggers(coins []domain.CoinData)
 	}
 
 	now := time.Now()
-	cooldownDuration := 5 * time.Minute
+	cooldown := uc.settings.Load().notifyCooldown
 
 	for _, coin := range coins {
 		// Notify only for TRIGGER (entry ready!)
@@ -33,7 +112,7 @@ func (uc *ScreenerUsecase) sendNotificationsForTriggers(coins []domain.CoinData)
 		lastNotified, exists := uc.notifiedCoins[coin.Symbol]
 		uc.mu.RUnlock()
 
-		if exists && now.Sub(lastNotified) < cooldownDuration {
+		if exists && now.Sub(lastNotified) < cooldown {
 			continue // Skip, still in cooldown
 		}
 
@@ -55,31 +134,23 @@ func (uc *ScreenerUsecase) sendNotificationsForTriggers(coins []domain.CoinData)
 		}
 
 		// Send to all registered tokens
-		err := uc.fcmClient.SendMulticast(tokens, title, body, data)
+		err := uc.sendMulticast(ctx, tokens, title, body, data)
 		if err != nil {
-			log.Printf("Error sending notification for %s: %v", coin.Symbol, err)
+			logging.Warnf("Error sending notification for %s: %v", coin.Symbol, err)
 		} else {
-			log.Printf("Sent notification for %s to %d devices", coin.Symbol, len(tokens))
+			logging.Infof("Sent notification for %s to %d devices", coin.Symbol, len(tokens))
 			
 			// Update notified timestamp
-			uc.mu.Lock()
-			uc.notifiedCoins[coin.Symbol] = now
-			uc.mu.Unlock()
+			uc.markNotified(ctx, coin.Symbol, now)
 		}
 	}
 
 	// Cleanup old entries (older than cooldown period)
-	uc.mu.Lock()
-	for symbol, timestamp := range uc.notifiedCoins {
-		if now.Sub(timestamp) > cooldownDuration*2 {
-			delete(uc.notifiedCoins, symbol)
-		}
-	}
-	uc.mu.Unlock()
+	uc.pruneCooldowns(ctx, now)
 }
 
 // sendNotificationsForBreakouts sends FCM notifications for coins with BREAKOUT status
-func (uc *ScreenerUsecase) sendNotificationsForBreakouts(coins []domain.CoinData) {
+func (uc *ScreenerUsecase) sendNotificationsForBreakouts(ctx context.Context, coins []domain.CoinData) {
 	if uc.fcmClient == nil || !uc.fcmClient.IsEnabled() {
 		return // FCM not configured
 	}
@@ -90,11 +161,17 @@ func (uc *ScreenerUsecase) sendNotificationsForBreakouts(coins []domain.CoinData
 	}
 
 	now := time.Now()
-	cooldownDuration := 5 * time.Minute
+	cooldown := uc.settings.Load().notifyCooldown
 
-	for _, coin := range coins {
-		// Notify only for BREAKOUT_LONG or BREAKOUT_SHORT (confirmed breakout!)
-		if coin.BreakoutStatus != "BREAKOUT_LONG" && coin.BreakoutStatus != "BREAKOUT_SHORT" {
+	// Breakouts out of a fresh squeeze release go first
+	sorted := make([]domain.CoinData, len(coins))
+	copy(sorted, coins)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].BreakoutSqueeze == "FIRED" && sorted[j].BreakoutSqueeze != "FIRED"
+	})
+
+	for _, coin := range sorted {
+		if !isBreakoutAlert(coin) {
 			continue
 		}
 
@@ -103,7 +180,7 @@ func (uc *ScreenerUsecase) sendNotificationsForBreakouts(coins []domain.CoinData
 		lastNotified, exists := uc.notifiedCoins[coin.Symbol+"_BREAKOUT"]
 		uc.mu.RUnlock()
 
-		if exists && now.Sub(lastNotified) < cooldownDuration {
+		if exists && now.Sub(lastNotified) < cooldown {
 			continue // Skip, still in cooldown
 		}
 
@@ -120,6 +197,10 @@ func (uc *ScreenerUsecase) sendNotificationsForBreakouts(coins []domain.CoinData
 			title = fmt.Sprintf("%s %s BREAKDOWN - Sell Signal!", emoji, displaySymbol)
 		}
 		
+		if coin.BreakoutSqueeze == "FIRED" {
+			title += " 💥 Squeeze release"
+		}
+		
 		body := fmt.Sprintf("Score: %.0f | Direction: %s | $%.4f | +%.1f%%", 
 			coin.BreakoutScore, coin.BreakoutDirection, coin.Price, coin.PriceChangePercent)
 
@@ -129,30 +210,139 @@ func (uc *ScreenerUsecase) sendNotificationsForBreakouts(coins []domain.CoinData
 			"price":     fmt.Sprintf("%.5f", coin.Price),
 			"status":    coin.BreakoutStatus,
 			"direction": coin.BreakoutDirection,
+			"squeeze":   coin.BreakoutSqueeze,
 			"type":      "BREAKOUT",
 		}
 
 		// Send to all registered tokens
-		err := uc.fcmClient.SendMulticast(tokens, title, body, data)
+		err := uc.sendMulticast(ctx, tokens, title, body, data)
 		if err != nil {
-			log.Printf("Error sending breakout notification for %s: %v", coin.Symbol, err)
+			logging.Warnf("Error sending breakout notification for %s: %v", coin.Symbol, err)
 		} else {
-			log.Printf("Sent breakout notification for %s (%s) to %d devices", 
+			logging.Infof("Sent breakout notification for %s (%s) to %d devices", 
 				coin.Symbol, coin.BreakoutDirection, len(tokens))
 			
 			// Update notified timestamp
-			uc.mu.Lock()
-			uc.notifiedCoins[coin.Symbol+"_BREAKOUT"] = now
-			uc.mu.Unlock()
+			uc.markNotified(ctx, coin.Symbol+"_BREAKOUT", now)
 		}
 	}
 
 	// Cleanup old entries (older than cooldown period)
-	uc.mu.Lock()
-	for symbol, timestamp := range uc.notifiedCoins {
-		if now.Sub(timestamp) > cooldownDuration*2 {
-			delete(uc.notifiedCoins, symbol)
+	uc.pruneCooldowns(ctx, now)
+}
+
+// isBreakoutAlert: BREAKOUT_LONG or BREAKOUT_SHORT (confirmed breakout!), and
+// TESTING_* right as a squeeze releases (expansion is starting)
+func isBreakoutAlert(coin domain.CoinData) bool {
+	confirmed := coin.BreakoutStatus == "BREAKOUT_LONG" || coin.BreakoutStatus == "BREAKOUT_SHORT"
+	squeezeRelease := coin.BreakoutSqueeze == "FIRED" &&
+		(coin.BreakoutStatus == "TESTING_LONG" || coin.BreakoutStatus == "TESTING_SHORT")
+	return confirmed || squeezeRelease
+}
+
+// sendNotificationsForWatchlists alerts each user on their watchlisted
+// symbols at the item's own, lower thresholds, on reversal scores weighted by
+// the user's scoring profile. Coins that already raised the global TRIGGER or
+// breakout alert are left to it.
+func (uc *ScreenerUsecase) sendNotificationsForWatchlists(ctx context.Context, coins []domain.CoinData) {
+	if uc.fcmClient == nil || !uc.fcmClient.IsEnabled() {
+		return // FCM not configured
+	}
+
+	items := uc.watchlists.All(ctx)
+	if len(items) == 0 {
+		return
+	}
+	bySymbol := make(map[string]domain.CoinData, len(coins))
+	for _, coin := range coins {
+		bySymbol[coin.Symbol] = coin
+	}
+	byUser := make(map[string]map[string]domain.CoinData) // user -> rescored coins by symbol
+
+	now := time.Now()
+	cooldown := uc.settings.Load().notifyCooldown
+	tokens := make(map[string][]string) // user -> devices
+
+	type watchAlert struct {
+		item  *domain.WatchlistItem
+		coin  domain.CoinData
+		kind  string
+		key   string
+		score float64
+	}
+	var alerts []watchAlert
+	for _, item := range items {
+		global, ok := bySymbol[item.Symbol]
+		if !ok {
+			continue
+		}
+		userCoins, ok := byUser[item.UserID]
+		if !ok {
+			userCoins = uc.rescoredBySymbol(ctx, item.UserID, coins, bySymbol)
+			byUser[item.UserID] = userCoins
+		}
+		coin := userCoins[item.Symbol]
+		if item.AlertScore != nil && global.Status != "TRIGGER" && coin.Score >= *item.AlertScore {
+			alerts = append(alerts, watchAlert{item, coin, "REVERSAL", coin.Symbol + "_WATCH_" + item.UserID, coin.Score})
+		}
+		if item.BreakoutAlertScore != nil && !isBreakoutAlert(coin) && coin.BreakoutScore >= *item.BreakoutAlertScore {
+			alerts = append(alerts, watchAlert{item, coin, "BREAKOUT", coin.Symbol + "_WATCH_BREAKOUT_" + item.UserID, coin.BreakoutScore})
 		}
 	}
-	uc.mu.Unlock()
+
+	for _, a := range alerts {
+		item, coin, kind, key, score := a.item, a.coin, a.kind, a.key, a.score
+		uc.mu.RLock()
+		lastNotified, exists := uc.notifiedCoins[key]
+		uc.mu.RUnlock()
+		if exists && now.Sub(lastNotified) < cooldown {
+			continue
+		}
+
+		userTokens, ok := tokens[item.UserID]
+		if !ok {
+			userTokens = uc.tokenRepo.GetTokensForUser(item.UserID)
+			tokens[item.UserID] = userTokens
+		}
+		if len(userTokens) == 0 {
+			continue
+		}
+
+		displaySymbol := coin.Symbol[:len(coin.Symbol)-4] // Remove "USDT"
+		title := fmt.Sprintf("👀 %s watchlist - %s score %.0f", displaySymbol, strings.ToLower(kind), score)
+		body := fmt.Sprintf("Reversal: %.0f %s | Breakout: %.0f %s | $%.4f | +%.1f%%",
+			coin.Score, coin.Status, coin.BreakoutScore, coin.BreakoutStatus, coin.Price, coin.PriceChangePercent)
+		data := map[string]string{
+			"symbol": coin.Symbol,
+			"score":  fmt.Sprintf("%.2f", score),
+			"price":  fmt.Sprintf("%.5f", coin.Price),
+			"status": coin.Status,
+			"type":   "WATCHLIST_" + kind,
+		}
+
+		if err := uc.sendMulticast(ctx, userTokens, title, body, data); err != nil {
+			logging.Warnf("Error sending watchlist notification for %s to %s: %v", coin.Symbol, item.UserID, err)
+			continue
+		}
+		logging.Infof("Sent watchlist %s notification for %s to %s (%d devices)", strings.ToLower(kind), coin.Symbol, item.UserID, len(userTokens))
+		uc.markNotified(ctx, key, now)
+	}
+}
+
+// rescoredBySymbol is coins by symbol with the user's scoring profile
+// applied; bySymbol, the cycle's, when that fails
+func (uc *ScreenerUsecase) rescoredBySymbol(ctx context.Context, userID string, coins []domain.CoinData, bySymbol map[string]domain.CoinData) map[string]domain.CoinData {
+	if uc.profiles == nil {
+		return bySymbol
+	}
+	rescored, err := uc.profiles.Rescore(ctx, userID, coins)
+	if err != nil {
+		logging.Warnf("Scoring profile of %s not applied to watchlist alerts: %v", userID, err)
+		return bySymbol
+	}
+	out := make(map[string]domain.CoinData, len(rescored))
+	for _, coin := range rescored {
+		out[coin.Symbol] = coin
+	}
+	return out
 }
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/infrastructure/binance"
+)
+
+const (
+	// oiPublishLag is how long after a period ends Binance is given to
+	// publish its open interest
+	oiPublishLag = 30 * time.Second
+	// oiMinRefetch bounds the refetching of a symbol whose latest period is
+	// late
+	oiMinRefetch = time.Minute
+)
+
+// oiSettings are the openInterest.* settings, swapped as one unit on reload
+type oiSettings struct {
+	enabled  bool
+	period   string
+	lookback int
+}
+
+// oiSnapshots is a symbol's open interest history as last fetched
+type oiSnapshots struct {
+	settings   *oiSettings // what it was fetched with
+	points     []binance.OpenInterestPoint
+	validUntil time.Time // when the next period is expected
+}
+
+// OpenInterestTracker measures how fast open interest is building: the
+// percent change over the last openInterest.lookback periods. Each symbol's
+// history is fetched once per period and cached until the next one is due.
+type OpenInterestTracker struct {
+	client   *binance.Client
+	settings atomic.Pointer[oiSettings]
+
+	mu      sync.Mutex
+	history map[string]*oiSnapshots
+}
+
+// NewOpenInterestTracker creates a tracker with the openInterest.* settings of cfg
+func NewOpenInterestTracker(client *binance.Client, cfg *config.Config) *OpenInterestTracker {
+	t := &OpenInterestTracker{client: client, history: make(map[string]*oiSnapshots)}
+	t.ApplyConfig(cfg)
+	return t
+}
+
+// ApplyConfig swaps in the reloadable settings; cached histories fetched
+// with other settings are refetched on next use
+func (t *OpenInterestTracker) ApplyConfig(cfg *config.Config) {
+	t.settings.Store(&oiSettings{
+		enabled:  cfg.OpenInterest.Enabled,
+		period:   cfg.OpenInterest.Period,
+		lookback: cfg.OpenInterest.Lookback,
+	})
+}
+
+// Delta is the symbol's open interest change over the lookback, in percent
+// of the open interest at its start. 0 when tracking is off.
+func (t *OpenInterestTracker) Delta(ctx context.Context, symbol string) (float64, error) {
+	settings := t.settings.Load()
+	if !settings.enabled {
+		return 0, nil
+	}
+	points, err := t.snapshots(ctx, symbol, settings)
+	if err != nil {
+		return 0, err
+	}
+	if len(points) < settings.lookback+1 {
+		return 0, fmt.Errorf("%s open interest: only %d periods", symbol, len(points))
+	}
+	first := points[len(points)-1-settings.lookback].Qty
+	last := points[len(points)-1].Qty
+	if first <= 0 {
+		return 0, fmt.Errorf("%s open interest: no open interest %d periods ago", symbol, settings.lookback)
+	}
+	return (last - first) / first * 100, nil
+}
+
+// snapshots returns the cached history, fetching it when the next period
+// is due
+func (t *OpenInterestTracker) snapshots(ctx context.Context, symbol string, settings *oiSettings) ([]binance.OpenInterestPoint, error) {
+	now := time.Now()
+	t.mu.Lock()
+	cached, ok := t.history[symbol]
+	t.mu.Unlock()
+	if ok && cached.settings == settings && now.Before(cached.validUntil) {
+		return cached.points, nil
+	}
+
+	points, err := t.client.GetOpenInterestHist(ctx, symbol, settings.period, settings.lookback+1)
+	if err != nil {
+		return nil, fmt.Errorf("%s open interest: %w", symbol, err)
+	}
+	validUntil := now.Add(oiMinRefetch)
+	if n := len(points); n >= 2 {
+		period := points[n-1].Time.Sub(points[n-2].Time)
+		if next := points[n-1].Time.Add(period + oiPublishLag); next.After(validUntil) {
+			validUntil = next
+		}
+	}
+
+	t.mu.Lock()
+	t.history[symbol] = &oiSnapshots{settings: settings, points: points, validUntil: validUntil}
+	t.mu.Unlock()
+	return points, nil
+}
@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+)
+
+// Sources of the positions in a portfolio
+const (
+	PortfolioSourceBinance   = "binance"   // live positions of the user's Binance account
+	PortfolioSourceAutoScalp = "autoscalp" // active auto scalp positions
+	PortfolioSourceJournal   = "journal"   // open trade journal entries
+)
+
+// Portfolio is a user's open positions across Binance, the auto scalper and
+// the trade journal, with the exposure and risk they add up to. The sources
+// can overlap (a journal entry may record a Binance position), so Sources
+// breaks the totals down per source.
+type Portfolio struct {
+	UserID    string                     `json:"userId"`
+	AsOf      time.Time                  `json:"asOf"`
+	Positions []PortfolioPosition        `json:"positions"`
+	Totals    PortfolioTotals            `json:"totals"`
+	Sources   map[string]PortfolioTotals `json:"sources"`
+	// BinanceError is why the live positions are missing, when they are
+	BinanceError string `json:"binanceError,omitempty"`
+}
+
+// PortfolioTotals sum a group of positions, in USDT
+type PortfolioTotals struct {
+	Positions     int     `json:"positions"`
+	GrossExposure float64 `json:"grossExposure"` // long plus short notional
+	NetExposure   float64 `json:"netExposure"`   // long minus short notional
+	UnrealizedPL  float64 `json:"unrealizedPL"`
+	RiskToStops   float64 `json:"riskToStops"` // lost from the mark price if every stop filled
+	Unprotected   int     `json:"unprotected"` // positions without a stop
+}
+
+// PortfolioPosition is one open position. Notional and P/L are in USDT at
+// the mark price; Quantity 0 means the size is unknown (journal entries
+// recorded without one), which counts as no exposure.
+type PortfolioPosition struct {
+	Source       string  `json:"source"`
+	ID           string  `json:"id,omitempty"` // auto scalp or journal entry ID
+	Symbol       string  `json:"symbol"`
+	IsLong       bool    `json:"isLong"`
+	Paper        bool    `json:"paper"`
+	Quantity     float64 `json:"quantity"`
+	EntryPrice   float64 `json:"entryPrice"`
+	MarkPrice    float64 `json:"markPrice"`
+	Notional     float64 `json:"notional"`
+	Leverage     int     `json:"leverage,omitempty"`
+	UnrealizedPL float64 `json:"unrealizedPL"`
+	// UnrealizedPct is the price move since entry in the position's direction
+	UnrealizedPct float64  `json:"unrealizedPct"`
+	StopLoss      *float64 `json:"stopLoss,omitempty"`
+	// StopDistancePct is how far the mark price may move against the
+	// position before the stop, in percent of the mark; negative when the
+	// mark is already beyond it
+	StopDistancePct *float64 `json:"stopDistancePct,omitempty"`
+	RiskToStop      float64  `json:"riskToStop"`
+}
+
+// PortfolioService consolidates a user's open positions into one risk view
+type PortfolioService struct {
+	credentials domain.BinanceAPIStore
+	autoScalp   domain.AutoScalpRepository
+	trades      domain.TradeEntryRepository
+	screening   domain.ScreenerRepository
+}
+
+// NewPortfolioService creates a new portfolio service
+func NewPortfolioService(credentials domain.BinanceAPIStore, autoScalp domain.AutoScalpRepository, trades domain.TradeEntryRepository, screening domain.ScreenerRepository) *PortfolioService {
+	return &PortfolioService{credentials: credentials, autoScalp: autoScalp, trades: trades, screening: screening}
+}
+
+// Portfolio lists the user's live Binance positions (when credentials are
+// saved), active auto scalp positions and open journal entries. Auto scalp
+// and journal positions are marked at the screener's latest price; a journal
+// entry managing an auto scalp position is listed once, as the auto scalp
+// position. A Binance failure leaves the live positions out and is reported
+// in BinanceError.
+func (s *PortfolioService) Portfolio(ctx context.Context, userID string) (*Portfolio, error) {
+	p := &Portfolio{UserID: userID, AsOf: time.Now().UTC(), Positions: []PortfolioPosition{}, Sources: map[string]PortfolioTotals{}}
+
+	live, err := s.binancePositions(ctx, userID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		p.BinanceError = err.Error()
+	}
+	p.Positions = append(p.Positions, live...)
+
+	prices := make(map[string]float64)
+	for _, coin := range s.screening.GetCoins() {
+		prices[coin.Symbol] = coin.Price
+	}
+
+	for _, e := range s.autoScalp.GetActiveEntries(ctx, userID) {
+		mark := prices[e.Symbol]
+		if mark <= 0 {
+			mark = e.EntryPrice
+		}
+		qty := e.Quantity
+		if qty <= 0 {
+			qty = 100 // the size closed paper positions are booked with
+		}
+		p.Positions = append(p.Positions, portfolioPosition(PortfolioSourceAutoScalp, e.ID, e.Symbol, false, !e.IsRealTrade, qty, e.EntryPrice, mark, e.StopLoss, e.Leverage))
+	}
+
+	for _, e := range s.trades.GetActiveEntries(ctx, userID) {
+		if e.AutoScalpEntryID != "" {
+			continue
+		}
+		mark := prices[e.Symbol]
+		if mark <= 0 {
+			mark = e.EntryPrice
+		}
+		p.Positions = append(p.Positions, journalPosition(e, mark))
+	}
+
+	for _, pos := range p.Positions {
+		p.Totals.add(pos)
+		t := p.Sources[pos.Source]
+		t.add(pos)
+		p.Sources[pos.Source] = t
+	}
+	p.Totals.round()
+	for source, t := range p.Sources {
+		t.round()
+		p.Sources[source] = t
+	}
+	return p, nil
+}
+
+// binancePositions loads the open positions of the account the user's trades
+// are routed to, with the stop of each from its open stop orders. No saved
+// credentials is no positions.
+func (s *PortfolioService) binancePositions(ctx context.Context, userID string) ([]PortfolioPosition, error) {
+	cred, err := s.credentials.GetCredentials(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	master := binance.NewTradingClient(cred.APIKey, cred.SecretKey, cred.IsTestnet)
+	var positions []domain.BinancePosition
+	if cred.UsesSubAccount() {
+		positions, err = master.GetSubAccountPositions(ctx, cred.SubAccountEmail)
+	} else {
+		var info *domain.BinanceAccountInfo
+		if info, err = master.GetAccountInfo(ctx); err == nil {
+			positions = info.Positions
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(positions) == 0 {
+		return nil, nil
+	}
+
+	// Stops are best effort: positions without a readable stop count as unprotected
+	orders, _ := binance.NewTradingClientForCredentials(cred).GetOpenOrders(ctx, "")
+	out := make([]PortfolioPosition, 0, len(positions))
+	for _, pos := range positions {
+		isLong := pos.PositionAmount > 0
+		if pos.PositionSide == "LONG" || pos.PositionSide == "SHORT" {
+			isLong = pos.PositionSide == "LONG"
+		}
+		stop := nearestStopOrder(orders, pos.Symbol, isLong, pos.MarkPrice)
+		position := portfolioPosition(PortfolioSourceBinance, "", pos.Symbol, isLong, false, math.Abs(pos.PositionAmount), pos.EntryPrice, pos.MarkPrice, stop, pos.Leverage)
+		position.UnrealizedPL = round2(pos.UnrealizedProfit)
+		out = append(out, position)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out, nil
+}
+
+// nearestStopOrder is the stop price of the open stop order closing the
+// position that is nearest the mark price, 0 without one. A LONG is closed
+// by a SELL stop, a SHORT by a BUY stop.
+func nearestStopOrder(orders []map[string]interface{}, symbol string, isLong bool, mark float64) float64 {
+	side := "BUY"
+	if isLong {
+		side = "SELL"
+	}
+	var best float64
+	for _, o := range orders {
+		if o["symbol"] != symbol || o["side"] != side {
+			continue
+		}
+		if t, _ := o["type"].(string); t != "STOP_MARKET" && t != "STOP" {
+			continue
+		}
+		if ps, _ := o["positionSide"].(string); ps != "" && ps != "BOTH" && (ps == "LONG") != isLong {
+			continue
+		}
+		raw, _ := o["stopPrice"].(string)
+		stop, err := strconv.ParseFloat(raw, 64)
+		if err != nil || stop <= 0 {
+			continue
+		}
+		if best == 0 || math.Abs(mark-stop) < math.Abs(mark-best) {
+			best = stop
+		}
+	}
+	return best
+}
+
+// portfolioPosition builds a linear (USDT-margined) position; stop 0 is none
+func portfolioPosition(source, id, symbol string, isLong, paper bool, qty, entry, mark, stop float64, leverage int) PortfolioPosition {
+	dir := 1.0
+	if !isLong {
+		dir = -1
+	}
+	pos := PortfolioPosition{
+		Source:       source,
+		ID:           id,
+		Symbol:       symbol,
+		IsLong:       isLong,
+		Paper:        paper,
+		Quantity:     qty,
+		EntryPrice:   entry,
+		MarkPrice:    mark,
+		Notional:     round2(qty * mark),
+		Leverage:     leverage,
+		UnrealizedPL: round2(dir * (mark - entry) * qty),
+	}
+	if entry > 0 {
+		pos.UnrealizedPct = round2(dir * (mark - entry) / entry * 100)
+	}
+	if stop > 0 && mark > 0 {
+		pos.setStop(stop, dir*(mark-stop)/mark*100, math.Max(0, dir*(mark-stop)*qty))
+	}
+	return pos
+}
+
+// journalPosition marks a journal entry at mark, with its fees, funding and
+// COIN-M settlement as the journal books them
+func journalPosition(e *domain.TradeEntry, mark float64) PortfolioPosition {
+	pos := portfolioPosition(PortfolioSourceJournal, e.ID, e.Symbol, e.IsLong, false, e.Quantity, e.EntryPrice, mark, 0, e.Leverage)
+	if e.IsCoinMargined() {
+		pos.Notional = round2(e.Quantity) // contract value in USD
+	}
+	if e.Quantity > 0 {
+		pos.UnrealizedPL = round2(e.CalculateProfitLoss(mark))
+	} else {
+		pos.UnrealizedPL = 0
+	}
+	if e.StopLoss > 0 && mark > 0 {
+		dir := 1.0
+		if !e.IsLong {
+			dir = -1
+		}
+		risk := 0.0
+		if e.Quantity > 0 {
+			risk = math.Max(0, e.CalculateProfitLoss(mark)-e.CalculateProfitLoss(e.StopLoss))
+		}
+		pos.setStop(e.StopLoss, dir*(mark-e.StopLoss)/mark*100, risk)
+	}
+	return pos
+}
+
+func (p *PortfolioPosition) setStop(stop, distancePct, risk float64) {
+	distancePct = round2(distancePct)
+	p.StopLoss = &stop
+	p.StopDistancePct = &distancePct
+	p.RiskToStop = round2(risk)
+}
+
+func (t *PortfolioTotals) add(p PortfolioPosition) {
+	t.Positions++
+	t.GrossExposure += p.Notional
+	if p.IsLong {
+		t.NetExposure += p.Notional
+	} else {
+		t.NetExposure -= p.Notional
+	}
+	t.UnrealizedPL += p.UnrealizedPL
+	t.RiskToStops += p.RiskToStop
+	if p.StopLoss == nil {
+		t.Unprotected++
+	}
+}
+
+func (t *PortfolioTotals) round() {
+	t.GrossExposure = round2(t.GrossExposure)
+	t.NetExposure = round2(t.NetExposure)
+	t.UnrealizedPL = round2(t.UnrealizedPL)
+	t.RiskToStops = round2(t.RiskToStops)
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+)
+
+// positioning is a symbol's latest long/short ratios; 0 when unknown
+type positioning struct {
+	AccountRatio     float64 `json:"accountRatio"`     // all traders, by accounts
+	TopPositionRatio float64 `json:"topPositionRatio"` // top traders, by position size
+}
+
+// positioningSettings are the positioning.* settings, swapped as one unit on
+// reload
+type positioningSettings struct {
+	enabled bool
+	period  string
+}
+
+// positioningSnapshot is a symbol's positioning as last fetched
+type positioningSnapshot struct {
+	settings   *positioningSettings // what it was fetched with
+	value      positioning
+	validUntil time.Time // when the next period is expected
+}
+
+// PositioningTracker reads how traders are positioned: the long/short
+// account ratio of all traders and the long/short position ratio of the top
+// traders. A cycle reads each symbol once; the ratios are cached until
+// Binance publishes the next period, so most cycles make no calls.
+type PositioningTracker struct {
+	client   *binance.Client
+	settings atomic.Pointer[positioningSettings]
+
+	mu     sync.Mutex
+	cached map[string]*positioningSnapshot
+}
+
+// NewPositioningTracker creates a tracker with the positioning.* settings of cfg
+func NewPositioningTracker(client *binance.Client, cfg *config.Config) *PositioningTracker {
+	t := &PositioningTracker{client: client, cached: make(map[string]*positioningSnapshot)}
+	t.ApplyConfig(cfg)
+	return t
+}
+
+// ApplyConfig swaps in the reloadable settings; ratios fetched with other
+// settings are refetched on next use
+func (t *PositioningTracker) ApplyConfig(cfg *config.Config) {
+	t.settings.Store(&positioningSettings{enabled: cfg.Positioning.Enabled, period: cfg.Positioning.Period})
+}
+
+// Get returns the symbol's latest ratios; zero when tracking is off
+func (t *PositioningTracker) Get(ctx context.Context, symbol string) (positioning, error) {
+	settings := t.settings.Load()
+	if !settings.enabled {
+		return positioning{}, nil
+	}
+	now := time.Now()
+	t.mu.Lock()
+	cached, ok := t.cached[symbol]
+	t.mu.Unlock()
+	if ok && cached.settings == settings && now.Before(cached.validUntil) {
+		return cached.value, nil
+	}
+
+	accounts, err := t.client.GetGlobalLongShortRatio(ctx, symbol, settings.period, 1)
+	if err != nil {
+		return positioning{}, fmt.Errorf("%s long/short ratio: %w", symbol, err)
+	}
+	positions, err := t.client.GetTopLongShortPositionRatio(ctx, symbol, settings.period, 1)
+	if err != nil {
+		return positioning{}, fmt.Errorf("%s top trader ratio: %w", symbol, err)
+	}
+	if len(accounts) == 0 || len(positions) == 0 {
+		return positioning{}, fmt.Errorf("%s long/short ratio: no periods", symbol)
+	}
+
+	value := positioning{AccountRatio: accounts[0].Ratio, TopPositionRatio: positions[0].Ratio}
+	validUntil := now.Add(oiMinRefetch)
+	if next := accounts[0].Time.Add(binance.IntervalDuration(settings.period) + oiPublishLag); next.After(validUntil) {
+		validUntil = next
+	}
+
+	t.mu.Lock()
+	t.cached[symbol] = &positioningSnapshot{settings: settings, value: value, validUntil: validUntil}
+	t.mu.Unlock()
+	return value, nil
+}
+
+// applyPositioning fills the positioning features
+func applyPositioning(features *domain.MarketFeatures, p positioning) {
+	features.LongShortRatio = p.AccountRatio
+	features.TopTraderLongShortRatio = p.TopPositionRatio
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/indicators"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+const (
+	regimeSymbol = "BTCUSDT"
+	regimeKlines = 150
+	// regimeMaxAge is how long a detected regime is trusted when later
+	// detections fail; after that the filters stand down
+	regimeMaxAge = 30 * time.Minute
+	// regimeSlopeBars is how far back the EMA20 must be lower (higher) for
+	// an up (down) trend
+	regimeSlopeBars = 5
+)
+
+var regimeTimeframes = []string{"1h", "4h"}
+
+// klineFetcher loads raw klines, e.g. ScreenerUsecase.getKlines
+type klineFetcher func(ctx context.Context, symbol, interval string, limit int) ([][]interface{}, error)
+
+// regimeSettings are the regime.* settings, swapped as one unit on reload
+type regimeSettings struct {
+	shortFilter string
+	longFilter  string
+	downweight  float64
+}
+
+// statusStep is a status and the score it needs
+type statusStep struct {
+	status   string
+	minScore float64
+}
+
+// RegimeFilter tracks the BTC market regime and gates signals against it:
+// SHORT reversals during a strong BTC uptrend and pullback LONGs during a
+// BTC breakdown are down-weighted or suppressed, as configured.
+type RegimeFilter struct {
+	settings atomic.Pointer[regimeSettings]
+	current  atomic.Pointer[domain.MarketRegime]
+	now      func() time.Time // fixed to the cycle's start when replaying
+}
+
+// NewRegimeFilter creates a regime filter with the regime.* settings of cfg
+func NewRegimeFilter(cfg *config.Config) *RegimeFilter {
+	f := &RegimeFilter{now: time.Now}
+	f.ApplyConfig(cfg)
+	return f
+}
+
+// ApplyConfig swaps in the reloadable regime settings
+func (f *RegimeFilter) ApplyConfig(cfg *config.Config) {
+	f.settings.Store(&regimeSettings{
+		shortFilter: cfg.Regime.ShortFilter,
+		longFilter:  cfg.Regime.LongFilter,
+		downweight:  cfg.Regime.Downweight,
+	})
+}
+
+// Current is the last detected regime, nil before the first detection
+func (f *RegimeFilter) Current() *domain.MarketRegime {
+	return f.current.Load()
+}
+
+// Detect reads the regime from the BTCUSDT 1h and 4h candles. On failure
+// the previous regime is kept until it is regimeMaxAge old.
+func (f *RegimeFilter) Detect(ctx context.Context, klines klineFetcher) error {
+	regime := &domain.MarketRegime{Symbol: regimeSymbol, DetectedAt: f.now().UTC()}
+	trends := make(map[string]string, len(regimeTimeframes))
+	for _, tf := range regimeTimeframes {
+		raw, err := klines(ctx, regimeSymbol, tf, regimeKlines)
+		if err != nil {
+			return fmt.Errorf("%s %s klines: %w", regimeSymbol, tf, err)
+		}
+		if len(raw) < 60 {
+			return fmt.Errorf("%s %s: only %d klines", regimeSymbol, tf, len(raw))
+		}
+		highs := make([]float64, len(raw))
+		lows := make([]float64, len(raw))
+		closes := make([]float64, len(raw))
+		for i, k := range raw {
+			highs[i], _ = parseValue(k[2])
+			lows[i], _ = parseValue(k[3])
+			closes[i], _ = parseValue(k[4])
+		}
+
+		reading := timeframeTrend(tf, closes)
+		trends[tf] = reading.Trend
+		regime.Timeframes = append(regime.Timeframes, reading)
+		if tf == "1h" {
+			atr := indicators.CalculateATR(highs, lows, closes, 14)
+			_, regime.Volatility = indicators.VolatilityRegime(atr, closes, 100)
+		}
+	}
+	regime.Trend = combineTrends(trends["1h"], trends["4h"], regime.Volatility)
+
+	if prev := f.current.Swap(regime); prev == nil || prev.Trend != regime.Trend {
+		logging.Infof("BTC regime: %s (1h %s, 4h %s, volatility %s)", regime.Trend, trends["1h"], trends["4h"], regime.Volatility)
+	}
+	return nil
+}
+
+// timeframeTrend is UP when close > EMA20 > EMA50 with a rising EMA20, DOWN
+// for the mirror image, else NEUTRAL
+func timeframeTrend(tf string, closes []float64) domain.RegimeTimeframe {
+	n := len(closes)
+	ema20 := indicators.CalculateEMA(closes, 20)
+	ema50 := indicators.CalculateEMA(closes, 50)
+	reading := domain.RegimeTimeframe{TF: tf, Trend: domain.RegimeNeutral, Close: closes[n-1], EMA20: ema20[n-1], EMA50: ema50[n-1]}
+	rising := ema20[n-1] > ema20[n-1-regimeSlopeBars]
+	switch {
+	case reading.Close > reading.EMA20 && reading.EMA20 > reading.EMA50 && rising:
+		reading.Trend = domain.RegimeUp
+	case reading.Close < reading.EMA20 && reading.EMA20 < reading.EMA50 && !rising:
+		reading.Trend = domain.RegimeDown
+	}
+	return reading
+}
+
+// combineTrends: both timeframes agreeing is a strong trend unless ranges are
+// contracting (a quiet drift is no squeeze risk); one trending while the
+// other is neutral is a plain trend
+func combineTrends(h1, h4, volatility string) string {
+	switch {
+	case h1 == domain.RegimeUp && h4 == domain.RegimeUp:
+		if volatility == indicators.VolRegimeLow {
+			return domain.RegimeUp
+		}
+		return domain.RegimeStrongUp
+	case h1 == domain.RegimeDown && h4 == domain.RegimeDown:
+		if volatility == indicators.VolRegimeLow {
+			return domain.RegimeDown
+		}
+		return domain.RegimeBreakdown
+	case (h1 == domain.RegimeUp || h4 == domain.RegimeUp) && h1 != domain.RegimeDown && h4 != domain.RegimeDown:
+		return domain.RegimeUp
+	case (h1 == domain.RegimeDown || h4 == domain.RegimeDown) && h1 != domain.RegimeUp && h4 != domain.RegimeUp:
+		return domain.RegimeDown
+	}
+	return domain.RegimeNeutral
+}
+
+// activeTrend is the regime trend while it is fresh enough to act on
+func (f *RegimeFilter) activeTrend() string {
+	regime := f.current.Load()
+	if regime == nil || f.now().Sub(regime.DetectedAt) > regimeMaxAge {
+		return ""
+	}
+	return regime.Trend
+}
+
+// ShortFilter is the filter mode for SHORT reversal entries right now:
+// regime.shortFilter during a strong BTC uptrend, else off
+func (f *RegimeFilter) ShortFilter() string {
+	if f.activeTrend() != domain.RegimeStrongUp {
+		return domain.RegimeFilterOff
+	}
+	return f.settings.Load().shortFilter
+}
+
+// LongFilter is the filter mode for pullback LONG entries right now:
+// regime.longFilter during a BTC breakdown, else off
+func (f *RegimeFilter) LongFilter() string {
+	if f.activeTrend() != domain.RegimeBreakdown {
+		return domain.RegimeFilterOff
+	}
+	return f.settings.Load().longFilter
+}
+
+// Apply gates a screened coin's reversal and pullback signals. Suppressed
+// signals drop to the watching status; down-weighted scores lose any status
+// they no longer reach. coin.RegimeFilter records what was done.
+func (f *RegimeFilter) Apply(coin *domain.CoinData, triggerScore float64) {
+	settings := f.settings.Load()
+
+	f.applyShort(coin, triggerScore)
+
+	if coin.PullbackStatus == "DIP" || coin.PullbackStatus == "BOUNCE" {
+		switch f.LongFilter() {
+		case domain.RegimeFilterSuppress:
+			coin.PullbackStatus = "WAIT"
+			coin.RegimeFilter = domain.RegimeFilterSuppress
+		case domain.RegimeFilterDownweight:
+			coin.PullbackScore *= settings.downweight
+			coin.PullbackStatus = demoteStatus(coin.PullbackStatus, coin.PullbackScore, []statusStep{{"DIP", 45}, {"BOUNCE", 35}, {"WAIT", 30}})
+			coin.RegimeFilter = domain.RegimeFilterDownweight
+		}
+	}
+}
+
+// applyShort is Apply's gate on the reversal (SHORT) signal
+func (f *RegimeFilter) applyShort(coin *domain.CoinData, triggerScore float64) {
+	if coin.Status != "TRIGGER" && coin.Status != "SETUP" {
+		return
+	}
+	switch f.ShortFilter() {
+	case domain.RegimeFilterSuppress:
+		coin.Status = "WATCH"
+		coin.RegimeFilter = domain.RegimeFilterSuppress
+	case domain.RegimeFilterDownweight:
+		coin.Score *= f.settings.Load().downweight
+		coin.Status = demoteStatus(coin.Status, coin.Score, []statusStep{{"TRIGGER", triggerScore}, {"SETUP", 35}, {"WATCH", 30}})
+		coin.RegimeFilter = domain.RegimeFilterDownweight
+	}
+}
+
+// Reapply gates a coin's reversal signal again after its score was computed
+// anew since the cycle, before any regime filtering (a user's scoring profile
+// rescored it): the status is set from the score by the screener's rules,
+// then gated like Apply's.
+func (f *RegimeFilter) Reapply(coin *domain.CoinData, triggerScore float64) {
+	// The filter never acts on shorts and longs at once, so unless the
+	// longs' is on, what the coin records is the shorts'
+	if f.LongFilter() == domain.RegimeFilterOff {
+		coin.RegimeFilter = ""
+	}
+	deadMarket := coin.Features != nil && coin.Features.VolatilityRegime == indicators.VolRegimeLow
+	coin.Status = reversalStatus(coin.ConfluenceCount, coin.Score, triggerScore, deadMarket)
+	f.applyShort(coin, triggerScore)
+}
+
+// demoteStatus walks down the ladder from status until score reaches the
+// step's minimum; "" below the last step
+func demoteStatus(status string, score float64, ladder []statusStep) string {
+	for i, step := range ladder {
+		if step.status != status {
+			continue
+		}
+		for _, lower := range ladder[i:] {
+			if score >= lower.minScore {
+				return lower.status
+			}
+		}
+		return ""
+	}
+	return status
+}
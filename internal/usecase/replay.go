@@ -0,0 +1,441 @@
+package usecase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+)
+
+// replaySearchWindow is how far before the asked time a recording is looked for
+const replaySearchWindow = 24 * time.Hour
+
+var (
+	ErrReplayTarget      = domain.Validation("REPLAY_TARGET_REQUIRED", "pass a recording id, or a symbol and a time")
+	ErrReplayNoRecording = domain.NotFound("CYCLE_RECORDING_NOT_FOUND", "no recorded cycle of that symbol in the 24 hours before that time")
+	ErrReplaySymbol      = domain.Validation("REPLAY_SYMBOL_NOT_RECORDED", "the symbol was not recorded in that cycle")
+	errNotRecorded       = errors.New("not recorded")
+)
+
+// replaySettings are the config sections that decide a cycle's scores and
+// statuses
+type replaySettings struct {
+	Screener config.ScreenerConfig `json:"screener"`
+	Regime   config.RegimeConfig   `json:"regime"`
+	// Strategies are the strategy toggles and timeframes; missing from
+	// recordings made before they were configurable, which ran the defaults
+	Strategies *domain.ScreenerConfig `json:"strategies,omitempty"`
+}
+
+// cycleTape is the content of a domain.CycleRecording: everything the
+// strategies read for the recorded symbols, and what they produced
+type cycleTape struct {
+	Settings        replaySettings               `json:"settings"`
+	MarketRegime    *domain.MarketRegime         `json:"marketRegime,omitempty"` // BTC regime the filters acted on
+	ShortMultiplier float64                      `json:"shortMultiplier"`
+	LongMultiplier  float64                      `json:"longMultiplier"`
+	Tickers         map[string]binance.Ticker24h `json:"tickers"`
+	Funding         map[string]float64           `json:"funding"`      // missing: the call failed
+	OpenInterest    map[string]float64           `json:"openInterest"` // OI delta; missing: the call failed
+	Positioning     map[string]positioning       `json:"positioning"`  // long/short ratios; missing: the call failed
+	Liquidations    map[string]liquidationFlow   `json:"liquidations"` // missing: none, or recorded before they were
+	// Klines holds the responses per symbol|interval|limit in call order;
+	// null for a failed call
+	Klines map[string][][][]interface{} `json:"klines"`
+	Coins  map[string]domain.CoinData   `json:"coins"` // missing: the symbol was dropped
+}
+
+func tapeKey(symbol, interval string, limit int) string {
+	return fmt.Sprintf("%s|%s|%d", symbol, interval, limit)
+}
+
+// cycleRecorder captures what a cycle reads for the symbols of replay.symbols
+type cycleRecorder struct {
+	all     bool
+	symbols map[string]bool
+
+	mu   sync.Mutex
+	tape cycleTape
+}
+
+// newCycleRecorder parses a replay.symbols list; nil when it records nothing
+func newCycleRecorder(list string) *cycleRecorder {
+	r := &cycleRecorder{symbols: make(map[string]bool)}
+	for _, s := range strings.Split(list, ",") {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		switch s {
+		case "":
+		case "*":
+			r.all = true
+		default:
+			r.symbols[s] = true
+		}
+	}
+	if !r.all && len(r.symbols) == 0 {
+		return nil
+	}
+	r.tape = cycleTape{
+		Tickers:      make(map[string]binance.Ticker24h),
+		Funding:      make(map[string]float64),
+		OpenInterest: make(map[string]float64),
+		Positioning:  make(map[string]positioning),
+		Liquidations: make(map[string]liquidationFlow),
+		Klines:       make(map[string][][][]interface{}),
+		Coins:        make(map[string]domain.CoinData),
+	}
+	return r
+}
+
+func (r *cycleRecorder) records(symbol string) bool {
+	return r.all || r.symbols[symbol]
+}
+
+// attach records the cycle's settings and modifiers and routes env's market
+// data through the recorder
+func (r *cycleRecorder) attach(env *cycleEnv) {
+	r.tape.Settings = env.settings.recorded
+	r.tape.Settings.Strategies = env.strategies
+	r.tape.MarketRegime = env.regime.Current()
+	r.tape.ShortMultiplier = env.shortMultiplier
+	r.tape.LongMultiplier = env.longMultiplier
+	for symbol, t := range env.tickers {
+		if r.records(symbol) {
+			r.tape.Tickers[symbol] = t
+		}
+	}
+
+	klines, funding, openInterest, liquidations := env.klines, env.funding, env.openInterest, env.liquidations
+	ratios := env.positioning
+	env.klines = func(ctx context.Context, symbol, interval string, limit int) ([][]interface{}, error) {
+		rows, err := klines(ctx, symbol, interval, limit)
+		if r.records(symbol) {
+			var recorded [][]interface{}
+			if err == nil {
+				recorded = append([][]interface{}{}, rows...)
+			}
+			key := tapeKey(symbol, interval, limit)
+			r.mu.Lock()
+			r.tape.Klines[key] = append(r.tape.Klines[key], recorded)
+			r.mu.Unlock()
+		}
+		return rows, err
+	}
+	env.funding = func(ctx context.Context, symbol string) (float64, error) {
+		rate, err := funding(ctx, symbol)
+		if err == nil && r.records(symbol) {
+			r.mu.Lock()
+			r.tape.Funding[symbol] = rate
+			r.mu.Unlock()
+		}
+		return rate, err
+	}
+	env.openInterest = func(ctx context.Context, symbol string) (float64, error) {
+		delta, err := openInterest(ctx, symbol)
+		if err == nil && r.records(symbol) {
+			r.mu.Lock()
+			r.tape.OpenInterest[symbol] = delta
+			r.mu.Unlock()
+		}
+		return delta, err
+	}
+	env.positioning = func(ctx context.Context, symbol string) (positioning, error) {
+		p, err := ratios(ctx, symbol)
+		if err == nil && r.records(symbol) {
+			r.mu.Lock()
+			r.tape.Positioning[symbol] = p
+			r.mu.Unlock()
+		}
+		return p, err
+	}
+	env.liquidations = func(symbol string) liquidationFlow {
+		flow := liquidations(symbol)
+		if flow != (liquidationFlow{}) && r.records(symbol) {
+			r.mu.Lock()
+			r.tape.Liquidations[symbol] = flow
+			r.mu.Unlock()
+		}
+		return flow
+	}
+}
+
+// save stores the tape with the coins the cycle produced
+func (r *cycleRecorder) save(ctx context.Context, repo domain.CycleRecordingRepository, startedAt time.Time, coins []domain.CoinData, keep int) error {
+	for _, coin := range coins {
+		if r.records(coin.Symbol) {
+			r.tape.Coins[coin.Symbol] = coin
+		}
+	}
+	symbols := make([]string, 0, len(r.tape.Tickers))
+	for symbol := range r.tape.Tickers {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(&r.tape); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return repo.SaveCycleRecording(ctx, &domain.CycleRecording{StartedAt: startedAt.UTC(), Symbols: symbols, Data: buf.Bytes()}, keep)
+}
+
+// tapePlayer serves a recorded cycle's market data in the order it was read
+type tapePlayer struct {
+	tape *cycleTape
+
+	mu   sync.Mutex
+	next map[string]int
+}
+
+func (p *tapePlayer) klines(_ context.Context, symbol, interval string, limit int) ([][]interface{}, error) {
+	key := tapeKey(symbol, interval, limit)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	responses := p.tape.Klines[key]
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("%s klines: %w", key, errNotRecorded)
+	}
+	i := min(p.next[key], len(responses)-1)
+	p.next[key]++
+	if responses[i] == nil {
+		return nil, fmt.Errorf("%s klines: the recorded call failed", key)
+	}
+	return responses[i], nil
+}
+
+func (p *tapePlayer) funding(_ context.Context, symbol string) (float64, error) {
+	rate, ok := p.tape.Funding[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%s funding: %w", symbol, errNotRecorded)
+	}
+	return rate, nil
+}
+
+func (p *tapePlayer) openInterest(_ context.Context, symbol string) (float64, error) {
+	delta, ok := p.tape.OpenInterest[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%s open interest: %w", symbol, errNotRecorded)
+	}
+	return delta, nil
+}
+
+func (p *tapePlayer) positioning(_ context.Context, symbol string) (positioning, error) {
+	ratios, ok := p.tape.Positioning[symbol]
+	if !ok {
+		return positioning{}, fmt.Errorf("%s long/short ratios: %w", symbol, errNotRecorded)
+	}
+	return ratios, nil
+}
+
+func (p *tapePlayer) liquidations(symbol string) liquidationFlow {
+	return p.tape.Liquidations[symbol]
+}
+
+// ReplayService replays recorded cycles through the screener's strategies to
+// explain a coin's scores and statuses
+type ReplayService struct {
+	screener   *ScreenerUsecase
+	recordings domain.CycleRecordingRepository
+	store      *config.Store
+}
+
+// NewReplayService creates a new replay service
+func NewReplayService(screener *ScreenerUsecase, recordings domain.CycleRecordingRepository, store *config.Store) *ReplayService {
+	return &ReplayService{screener: screener, recordings: recordings, store: store}
+}
+
+// ReplayRequest picks the cycle to replay: the recording ID, or the last
+// recording of Symbol at or before At
+type ReplayRequest struct {
+	ID     int64
+	Symbol string // only this symbol is replayed; every recorded symbol when empty
+	At     time.Time
+	// CurrentSettings replays with today's screener.* and regime.* settings
+	// and screener config instead of the recorded ones
+	CurrentSettings bool
+}
+
+// ReplayResult compares what a recorded cycle produced with its replay
+type ReplayResult struct {
+	RecordingID     int64                `json:"recordingId"`
+	StartedAt       time.Time            `json:"startedAt"`
+	Settings        string               `json:"settings"` // recorded or current
+	MarketRegime    *domain.MarketRegime `json:"marketRegime,omitempty"`
+	ShortMultiplier float64              `json:"shortMultiplier"`
+	LongMultiplier  float64              `json:"longMultiplier"`
+	Coins           []ReplayedCoin       `json:"coins"`
+}
+
+// ReplayedCoin is one symbol of a replay. Recorded or Replayed is nil when
+// that run dropped the symbol for lack of data.
+type ReplayedCoin struct {
+	Symbol   string           `json:"symbol"`
+	Recorded *domain.CoinData `json:"recorded"`
+	Replayed *domain.CoinData `json:"replayed"`
+	Match    bool             `json:"match"`
+	Diffs    []string         `json:"diffs,omitempty"` // scores and statuses that differ
+}
+
+// Recordings lists recorded cycles, newest first
+func (s *ReplayService) Recordings(ctx context.Context, symbol string, from, to time.Time, limit int) ([]*domain.CycleRecording, error) {
+	return s.recordings.ListCycleRecordings(ctx, strings.ToUpper(symbol), from, to, limit)
+}
+
+// Replay runs the strategies again on a recorded cycle's data. The clock is
+// fixed at the cycle's start and the BTC regime and market context modifiers
+// are the recorded ones, so with the recorded settings the scores and
+// statuses come out exactly as they did.
+func (s *ReplayService) Replay(ctx context.Context, req ReplayRequest) (*ReplayResult, error) {
+	req.Symbol = strings.ToUpper(req.Symbol)
+	id := req.ID
+	if id == 0 {
+		if req.Symbol == "" || req.At.IsZero() {
+			return nil, ErrReplayTarget
+		}
+		found, err := s.recordings.ListCycleRecordings(ctx, req.Symbol, req.At.Add(-replaySearchWindow), req.At, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, ErrReplayNoRecording
+		}
+		id = found[0].ID
+	}
+	rec, err := s.recordings.GetCycleRecording(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tape, err := readTape(rec.Data)
+	if err != nil {
+		return nil, fmt.Errorf("recording %d: %w", rec.ID, err)
+	}
+
+	symbols := rec.Symbols
+	if req.Symbol != "" {
+		if _, ok := tape.Tickers[req.Symbol]; !ok {
+			return nil, ErrReplaySymbol
+		}
+		symbols = []string{req.Symbol}
+	}
+
+	cfg := *s.store.Current()
+	strategies := s.screener.strategies.Current(ctx)
+	settingsName := "current"
+	if !req.CurrentSettings {
+		cfg.Screener = tape.Settings.Screener
+		cfg.Regime = tape.Settings.Regime
+		strategies = tape.Settings.Strategies
+		if strategies == nil {
+			strategies = domain.DefaultScreenerConfig()
+		}
+		settingsName = "recorded"
+	}
+	startedAt := rec.StartedAt
+	regime := NewRegimeFilter(&cfg)
+	regime.now = func() time.Time { return startedAt }
+	if tape.MarketRegime != nil {
+		regime.current.Store(tape.MarketRegime)
+	}
+	player := &tapePlayer{tape: tape, next: make(map[string]int)}
+	env := &cycleEnv{
+		settings:        newScreenerSettings(&cfg),
+		strategies:      strategies,
+		tickers:         tape.Tickers,
+		klines:          player.klines,
+		funding:         player.funding,
+		openInterest:    player.openInterest,
+		positioning:     player.positioning,
+		liquidations:    player.liquidations,
+		regime:          regime,
+		shortMultiplier: tape.ShortMultiplier,
+		longMultiplier:  tape.LongMultiplier,
+	}
+
+	result := &ReplayResult{
+		RecordingID:     rec.ID,
+		StartedAt:       rec.StartedAt,
+		Settings:        settingsName,
+		MarketRegime:    tape.MarketRegime,
+		ShortMultiplier: tape.ShortMultiplier,
+		LongMultiplier:  tape.LongMultiplier,
+		Coins:           make([]ReplayedCoin, 0, len(symbols)),
+	}
+	for _, symbol := range symbols {
+		rc := ReplayedCoin{Symbol: symbol}
+		if coin, ok := tape.Coins[symbol]; ok {
+			rc.Recorded = &coin
+		}
+		if coin, ok := s.screener.screenSymbol(ctx, env, symbol); ok {
+			rc.Replayed = &coin
+		}
+		rc.Match, rc.Diffs = compareReplay(rc.Recorded, rc.Replayed)
+		result.Coins = append(result.Coins, rc)
+	}
+	return result, nil
+}
+
+func readTape(data []byte) (*cycleTape, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	var tape cycleTape
+	if err := json.NewDecoder(gz).Decode(&tape); err != nil {
+		return nil, err
+	}
+	return &tape, nil
+}
+
+// compareReplay reports whether both runs produced the same coin, and how
+// their scores and statuses differ
+func compareReplay(recorded, replayed *domain.CoinData) (bool, []string) {
+	switch {
+	case recorded == nil && replayed == nil:
+		return true, nil
+	case recorded == nil:
+		return false, []string{"dropped in the recorded cycle, scored in the replay"}
+	case replayed == nil:
+		return false, []string{"scored in the recorded cycle, dropped in the replay"}
+	}
+
+	var diffs []string
+	for _, f := range []struct {
+		name string
+		a, b any
+	}{
+		{"score", recorded.Score, replayed.Score},
+		{"status", recorded.Status, replayed.Status},
+		{"triggerTf", recorded.TriggerTF, replayed.TriggerTF},
+		{"intradayScore", recorded.IntradayScore, replayed.IntradayScore},
+		{"intradayStatus", recorded.IntradayStatus, replayed.IntradayStatus},
+		{"pullbackScore", recorded.PullbackScore, replayed.PullbackScore},
+		{"pullbackStatus", recorded.PullbackStatus, replayed.PullbackStatus},
+		{"breakoutScore", recorded.BreakoutScore, replayed.BreakoutScore},
+		{"breakoutStatus", recorded.BreakoutStatus, replayed.BreakoutStatus},
+		{"followTrendScore", recorded.FollowTrendScore, replayed.FollowTrendScore},
+		{"followTrendStatus", recorded.FollowTrendStatus, replayed.FollowTrendStatus},
+		{"regimeFilter", recorded.RegimeFilter, replayed.RegimeFilter},
+	} {
+		if f.a != f.b {
+			diffs = append(diffs, fmt.Sprintf("%s: %v -> %v", f.name, f.a, f.b))
+		}
+	}
+	// The recorded coin went through JSON, so compare both that way
+	a, errA := json.Marshal(recorded)
+	b, errB := json.Marshal(replayed)
+	return len(diffs) == 0 && errA == nil && errB == nil && bytes.Equal(a, b), diffs
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+)
+
+// RetentionService deletes history past the configured retention windows so
+// a small Postgres plan doesn't fill up
+type RetentionService struct {
+	archive   domain.MarketArchiveRepository
+	autoRepo  domain.AutoScalpRepository
+	cooldowns domain.CooldownStore
+	signals   domain.SignalRepository
+	settings  func() config.RetentionConfig
+}
+
+// NewRetentionService creates a retention service; settings is read on every run
+func NewRetentionService(archive domain.MarketArchiveRepository, autoRepo domain.AutoScalpRepository, cooldowns domain.CooldownStore, signals domain.SignalRepository, settings func() config.RetentionConfig) *RetentionService {
+	return &RetentionService{archive: archive, autoRepo: autoRepo, cooldowns: cooldowns, signals: signals, settings: settings}
+}
+
+// Prune applies every retention window once. A failing step doesn't stop the
+// others; all failures are returned together.
+func (s *RetentionService) Prune(ctx context.Context) error {
+	cfg := s.settings()
+	now := time.Now()
+
+	steps := []struct {
+		name  string
+		days  int
+		prune func(ctx context.Context, before time.Time) error
+	}{
+		{"coin snapshots", cfg.SnapshotDays, s.archive.PruneSnapshots},
+		{"screener score history", cfg.ScreenerHistoryDays, s.archive.PruneScreenerSnapshots},
+		{"archived candles", cfg.CandleDays, s.archive.PruneCandles},
+		{"notification history", cfg.NotificationDays, s.cooldowns.PruneCooldowns},
+		{"closed autoscalp entries", cfg.AutoScalpDays, s.autoRepo.PruneHistory},
+		{"emergency stop events", cfg.EmergencyStopDays, s.autoRepo.PruneEmergencyStops},
+		{"signal outcomes", cfg.SignalDays, s.signals.PruneSignals},
+	}
+
+	var errs []error
+	for _, step := range steps {
+		if step.days <= 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		before := now.AddDate(0, 0, -step.days)
+		if err := step.prune(ctx, before); err != nil {
+			errs = append(errs, fmt.Errorf("prune %s: %w", step.name, err))
+			continue
+		}
+		log.Printf("Retention: pruned %s older than %s", step.name, before.Format("2006-01-02"))
+	}
+	return errors.Join(errs...)
+}
@@ -1,14 +1,87 @@
 package usecase
 
 import (
+	"math"
 	"screener-backend/internal/domain"
 	"screener-backend/internal/infrastructure/binance"
 	"screener-backend/internal/infrastructure/indicators"
 	"strconv"
+	"strings"
 )
 
-// CalculateScore computes the score based on market features.
-func CalculateScore(features *domain.MarketFeatures) float64 {
+// ScoreOptions toggles optional components of the reversal score.
+type ScoreOptions struct {
+	// CCIExtreme is the CCI reading counted as exhaustion (e.g. 200 for ±200).
+	// 0 disables the CCI component.
+	CCIExtreme float64
+	// Weights scale the score components (all 1 by default)
+	Weights domain.ScoreWeights
+	// TriggerScore is the final score a 2-TF confluence needs for TRIGGER
+	TriggerScore float64
+}
+
+// DefaultScoreOptions are the options ParseScoreOptions starts from
+var DefaultScoreOptions = ScoreOptions{CCIExtreme: 200, Weights: domain.DefaultScoreWeights, TriggerScore: 40}
+
+// ParseScoreOptions reads options from their env values; empty keeps the default.
+// SCORE_CCI_EXTREME: CCI level (e.g. "200"), "0" or "off" disables.
+// SCORE_WEIGHTS: see domain.ParseScoreWeights. SCORE_TRIGGER: 0 keeps 40.
+func ParseScoreOptions(cciExtreme, weights string, triggerScore float64) ScoreOptions {
+	opts := DefaultScoreOptions
+	if w, err := domain.ParseScoreWeights(weights); err == nil {
+		opts.Weights = w
+	}
+	if triggerScore > 0 {
+		opts.TriggerScore = triggerScore
+	}
+	switch v := strings.ToLower(strings.TrimSpace(cciExtreme)); v {
+	case "":
+	case "off":
+		opts.CCIExtreme = 0
+	default:
+		if level, err := strconv.ParseFloat(v, 64); err == nil && level >= 0 {
+			opts.CCIExtreme = level
+		}
+	}
+	return opts
+}
+
+// CalculateScore computes the score based on market features, with optional
+// components configured, weighted by weights (e.g. a user's scoring profile)
+// instead of opts.Weights.
+func CalculateScore(features *domain.MarketFeatures, opts ScoreOptions, weights domain.ScoreWeights) float64 {
+	return ScoreBreakdown(features, opts).Weighted(weights)
+}
+
+// CalculateScoreWithOptions is CalculateScore with the configured weights.
+func CalculateScoreWithOptions(features *domain.MarketFeatures, opts ScoreOptions) float64 {
+	return CalculateScore(features, opts, opts.Weights)
+}
+
+// ReversalScore is a coin's reversal score from its timeframe scores, each
+// set anew from its components weighted by weights: their average times
+// multiplier (confluence and market context), at most 100. A cycle scores
+// with the configured weights, a user's view with their scoring profile's.
+func ReversalScore(tfScores []domain.TimeframeScore, weights domain.ScoreWeights, multiplier float64) float64 {
+	if len(tfScores) == 0 {
+		return 0
+	}
+	var total float64
+	for i := range tfScores {
+		if c := tfScores[i].Components; c != nil {
+			tfScores[i].Score = c.Weighted(weights)
+		}
+		total += tfScores[i].Score
+	}
+	return math.Min(total/float64(len(tfScores))*multiplier, 100)
+}
+
+// ScoreComponents are the unweighted parts of the reversal score
+type ScoreComponents = domain.ScoreComponents
+
+// ScoreBreakdown computes the reversal score components; opts.Weights is
+// not applied here.
+func ScoreBreakdown(features *domain.MarketFeatures, opts ScoreOptions) ScoreComponents {
 	// Weights (Stricter for reversal accuracy)
 	// Overextension: 0-30
 	// Crowding: 0-20
@@ -64,6 +137,8 @@ func CalculateScore(features *domain.MarketFeatures) float64 {
 		sCrowd += 3
 	}
 
+	sCrowd += positioningCrowdScore(features)
+
 	if sCrowd > 20 {
 		sCrowd = 20
 	}
@@ -89,6 +164,19 @@ func CalculateScore(features *domain.MarketFeatures) float64 {
 		sExhaust += 5 // Strong rejection
 	}
 
+	// CCI extreme - price stretched far beyond its typical range
+	if opts.CCIExtreme > 0 {
+		if features.CCI >= opts.CCIExtreme {
+			sExhaust += 5
+		} else if features.CCI <= -opts.CCIExtreme {
+			sExhaust -= 5 // Already flushed, no room left for a reversal short
+		}
+	}
+
+	if sExhaust < 0 {
+		sExhaust = 0
+	}
+
 	if sExhaust > 30 {
 		sExhaust = 30
 	}
@@ -122,11 +210,26 @@ func CalculateScore(features *domain.MarketFeatures) float64 {
 		sMomentum += 2
 	}
 
+	// Distribution: the new high was not bought by takers, or takers are
+	// net selling into an extended move
+	if features.HasCvdDivergence {
+		sMomentum += 4
+	}
+	if features.TakerDeltaRatio < -0.1 && features.OverExtEma >= 0.03 {
+		sMomentum += 3
+	}
+
 	if sMomentum > 15 {
 		sMomentum = 15
 	}
 
-	return sOver + sCrowd + sExhaust + sStruct + sMomentum
+	return ScoreComponents{
+		Overextension: sOver,
+		Crowding:      sCrowd,
+		Exhaustion:    sExhaust,
+		Structure:     sStruct,
+		Momentum:      sMomentum,
+	}
 }
 
 // ExtractFeatures computes indicators and extracts features for a coin.
@@ -220,12 +323,48 @@ func ExtractFeatures(
 		distToSupportATR = &val
 	}
 
+	// Volume profile: POC / value area act as volume-based support/resistance
+	var poc, vaHigh, vaLow, distToPocATR *float64
+	if profile := indicators.CalculateVolumeProfile(highs, lows, volumes, 50, 24, 0.7); profile.POC > 0 {
+		poc, vaHigh, vaLow = &profile.POC, &profile.ValueAreaHigh, &profile.ValueAreaLow
+		if currentAtr > 0 {
+			val := (currentClose - profile.POC) / currentAtr
+			distToPocATR = &val
+		}
+	}
+
+	// Fibonacci retracement of the latest impulse leg (pivot low <-> pivot high)
+	fib := indicators.CalculateFibRetracement(indicators.FindPivotHighs(highs, 5, 2), pivots)
+	fibLegUp := fib != nil && fib.IsUpLeg
+	fibReaction := 0.0
+	if currentAtr > 0 {
+		fibReaction = indicators.FibReaction(fib, currentHigh, currentLow, currentClose, currentAtr*0.25)
+	}
+
 	// Ticker pct change
 	pctChange, _ := strconvToFloat(ticker.PriceChangePercent)
 
 	// Momentum Loss Detection
 	momentumSignals := indicators.DetectMomentumLoss(prices, highs, volumes, rsi)
 
+	cci := indicators.CalculateCCI(highs, lows, prices, 20)
+	structure := indicators.AnalyzeMarketStructure(highs, lows, prices, 5, 2)
+	atrPercentile, volRegime := indicators.VolatilityRegime(atr, prices, 100)
+	chandelier := indicators.CalculateChandelierExit(highs, lows, atr, 22, 3)
+
+	// Ichimoku cloud (used as a higher-timeframe trend filter)
+	ichimoku := indicators.CalculateIchimoku(highs, lows, 9, 26, 52, 26)
+
+	// Bollinger inside Keltner = volatility squeeze (coiling before expansion)
+	keltner := indicators.CalculateKeltnerChannels(highs, lows, prices, 20, 14, 1.5)
+	squeeze := indicators.DetectSqueeze(bb, keltner)
+
+	// Stochastic RSI times dip entries: %K turning up through %D from oversold
+	stoch := indicators.CalculateStochRSI(prices, 14, 14, 3, 3)
+
+	// ADX separates trending pullbacks and breakouts from sideways chop
+	adx := indicators.CalculateADX(highs, lows, prices, 14)
+
 	return &domain.MarketFeatures{
 		PctChange24h:        pctChange,
 		OverExtEma:          overExtEma,
@@ -233,6 +372,8 @@ func ExtractFeatures(
 		IsAboveUpperBand:    isAboveUpperBand,
 		CandleRangeRatio:    0, // Placeholder
 		RSI:                 currentRsi,
+		CCI:                 cci[lastIdx],
+		ZScore:              indicators.CalculateZScore(prices, 20)[lastIdx],
 		IsRsiBearishDiv:     momentumSignals.HasRsiDivergence,
 		RejectionWickRatio:  rejectionWickRatio,
 		FundingRate:         fundingRate,
@@ -243,12 +384,98 @@ func ExtractFeatures(
 		IsRetest:            isRetestZone,
 		IsRetestFail:        false,
 		HasRsiDivergence:    momentumSignals.HasRsiDivergence,
+		HasRsiBullishDiv:    indicators.DetectRsiBullishDivergence(lows, rsi, 10),
 		HasVolumeDivergence: momentumSignals.HasVolumeDivergence,
+		HasObvDivergence:    momentumSignals.HasObvDivergence,
 		MomentumSlope:       momentumSignals.MomentumSlope,
 		RsiSlope:            momentumSignals.RsiSlope,
 		VolumeDeclineRatio:  momentumSignals.VolumeDeclineRatio,
 		IsLosingMomentum:    momentumSignals.IsLosingMomentum,
+		MacdHistogram:       momentumSignals.MacdHistogram,
+		MacdContracting:     momentumSignals.MacdContracting,
+		MacdCross:           momentumSignals.MacdCross,
+		StochK:              stoch.K[lastIdx],
+		StochD:              stoch.D[lastIdx],
+		StochCrossUp:        indicators.StochRSICrossUp(stoch, 20, 3),
+		CloudPosition:       indicators.CloudPosition(ichimoku, lastIdx, currentClose),
+		IchimokuTrend:       indicators.IchimokuTrend(ichimoku, lastIdx, currentClose),
+		POC:                 poc,
+		ValueAreaHigh:       vaHigh,
+		ValueAreaLow:        vaLow,
+		DistToPocATR:        distToPocATR,
+		FibLegUp:            fibLegUp,
+		FibReaction:         fibReaction,
+		StructureTrend:      structure.Trend,
+		LastSwingHigh:       structure.LastSwingLabel(true),
+		LastSwingLow:        structure.LastSwingLabel(false),
+		LastStructureBreak:  structureBreakFeature(structure.LastBreak(), len(prices)),
+		AtrPercentile:       atrPercentile,
+		VolatilityRegime:    volRegime,
+		Choppiness:          indicators.CalculateChoppiness(highs, lows, prices, 14)[lastIdx],
+		ADX:                 adx.ADX[lastIdx],
+		PlusDI:              adx.PlusDI[lastIdx],
+		MinusDI:             adx.MinusDI[lastIdx],
+		ADXRising:           indicators.ADXRising(adx, lastIdx, 3),
+		ChandelierLong:      chandelier.Long[lastIdx],
+		ChandelierShort:     chandelier.Short[lastIdx],
+		IsSqueeze:           squeeze.On,
+		SqueezeBars:         squeeze.Bars,
+		SqueezeFired:        squeeze.Fired,
+	}
+}
+
+// positioningCrowdScore scores crowded longs from the long/short ratios
+// (0-8): most accounts long, and the top traders not long with them
+func positioningCrowdScore(features *domain.MarketFeatures) float64 {
+	score := 0.0
+	if features.LongShortRatio > 3 {
+		score += 5 // Three long accounts for every short
+	} else if features.LongShortRatio > 2 {
+		score += 3
 	}
+	if features.LongShortRatio > 1.5 && features.TopTraderLongShortRatio > 0 && features.TopTraderLongShortRatio < 1 {
+		score += 3 // The crowd is long, the top traders lean short
+	}
+	return score
+}
+
+// adxChop reports an ADX below the trend threshold and not rising; a real
+// breakout lifts ADX out of a range, so a rising ADX is not chop
+func adxChop(features *domain.MarketFeatures) bool {
+	return features != nil && features.ADX > 0 && features.ADX < indicators.ADXWeak && !features.ADXRising
+}
+
+// hasRecentBearishBreak reports a bearish structure break within the last 5
+// candles with the structure still bearish (price has not reclaimed it)
+func hasRecentBearishBreak(highs, lows, closes []float64) bool {
+	structure := indicators.AnalyzeMarketStructure(highs, lows, closes, 5, 2)
+	return structure.Trend == "BEARISH" && structure.RecentBreak("BEARISH", len(closes), 5) != nil
+}
+
+// structureBreakFeature converts an indicator structure break for a series of length n
+func structureBreakFeature(b *indicators.StructureBreak, n int) *domain.StructureBreak {
+	if b == nil {
+		return nil
+	}
+	return &domain.StructureBreak{
+		Type:      b.Type,
+		Direction: b.Direction,
+		Price:     b.Price,
+		BarsAgo:   n - 1 - b.Index,
+	}
+}
+
+// applyTakerFlow fills the taker-flow features from the raw klines and highs
+// the other features were computed from.
+func applyTakerFlow(features *domain.MarketFeatures, rawKlines [][]interface{}, highs []float64) {
+	if features == nil || len(rawKlines) == 0 {
+		return
+	}
+	quoteVolumes, takerBuys := indicators.ParseTakerFlow(rawKlines)
+	delta := indicators.CalculateTakerDelta(quoteVolumes, takerBuys)
+	features.TakerDelta = delta[len(delta)-1]
+	features.TakerDeltaRatio = indicators.TakerDeltaRatio(delta, quoteVolumes, 5)
+	features.HasCvdDivergence = indicators.DetectCVDBearishDivergence(highs, indicators.CalculateCumulativeDelta(delta), 20)
 }
 
 // Helper
@@ -330,6 +557,16 @@ func CalculatePullbackScore(prices, ema20, ema50, rsi []float64, features *domai
 		pullbackScore += 5 // Slight dip below EMA20
 	}
 
+	// Bouncing off a fib retracement of the last up leg
+	if features != nil && features.FibLegUp {
+		switch features.FibReaction {
+		case 0.5, 0.618:
+			pullbackScore += 10 // Golden pocket / half retrace = classic dip entry
+		case 0.382:
+			pullbackScore += 5 // Shallow retrace, strong trend
+		}
+	}
+
 	if pullbackScore > 30 {
 		pullbackScore = 30
 	}
@@ -364,6 +601,22 @@ func CalculatePullbackScore(prices, ema20, ema50, rsi []float64, features *domai
 		}
 	}
 
+	// Stochastic RSI turning up from oversold: catches short, sharp dips
+	// the 14-period RSI barely registers
+	if features != nil && features.StochCrossUp {
+		bounceScore += 10
+	}
+
+	// Choppy range: dips mean-revert instead of breaking down
+	if features != nil && features.Choppiness > indicators.ChopRanging {
+		bounceScore += 5
+	}
+
+	// Bullish divergence: lower low in price, higher low in RSI
+	if features != nil && features.HasRsiBullishDiv {
+		bounceScore += 10
+	}
+
 	if bounceScore > 25 {
 		bounceScore = 25
 	}
@@ -372,8 +625,12 @@ func CalculatePullbackScore(prices, ema20, ema50, rsi []float64, features *domai
 	// === RISK SCORE (0-15) ===
 	riskScore := 0.0
 
-	// Near support (good risk/reward)
-	if features.DistToSupportATR != nil && *features.DistToSupportATR < 2.0 {
+	// Near support (good risk/reward): pivot support or holding just above the POC
+	nearPivotSupport := features.DistToSupportATR != nil && *features.DistToSupportATR < 2.0
+	nearPoc := features.DistToPocATR != nil && *features.DistToPocATR >= 0 && *features.DistToPocATR < 1.0
+	if nearPivotSupport && nearPoc {
+		riskScore += 15 // Pivot and volume support line up
+	} else if nearPivotSupport || nearPoc {
 		riskScore += 10 // Close to support = tight stop loss
 	}
 
@@ -520,8 +777,25 @@ func CalculateBreakoutScore(prices []float64, extremes []float64, volumes []floa
 		}
 	}
 
+	// Taker aggression in the breakout direction confirms it; against it fades it
+	if direction == "LONG" {
+		if features.TakerDeltaRatio > 0.1 {
+			volumeScore += 5
+		} else if features.TakerDeltaRatio < -0.1 {
+			volumeScore -= 5
+		}
+	} else if direction == "SHORT" {
+		if features.TakerDeltaRatio < -0.1 {
+			volumeScore += 5
+		} else if features.TakerDeltaRatio > 0.1 {
+			volumeScore -= 5
+		}
+	}
+
 	if volumeScore > 30 {
 		volumeScore = 30
+	} else if volumeScore < 0 {
+		volumeScore = 0
 	}
 	score += volumeScore
 
@@ -552,6 +826,15 @@ func CalculateBreakoutScore(prices []float64, extremes []float64, volumes []floa
 		} else if features.PctChange24h > 0 {
 			momentumScore += 3 // Positive move
 		}
+
+		// MACD confirmation: fresh bullish cross or expanding positive histogram
+		if features.MacdCross == "BULLISH" {
+			momentumScore += 5
+		} else if features.MacdHistogram > 0 && !features.MacdContracting {
+			momentumScore += 3
+		} else if features.MacdCross == "BEARISH" {
+			momentumScore -= 5 // Breakout against MACD
+		}
 	} else if direction == "SHORT" {
 		// RSI in bearish zone (25-50 = ideal, not oversold yet)
 		if currentRSI < 35 && currentRSI > 25 {
@@ -574,10 +857,21 @@ func CalculateBreakoutScore(prices []float64, extremes []float64, volumes []floa
 		} else if features.PctChange24h < 0 {
 			momentumScore += 3 // Negative move
 		}
+
+		// MACD confirmation: fresh bearish cross or negative histogram
+		if features.MacdCross == "BEARISH" {
+			momentumScore += 5
+		} else if features.MacdHistogram < 0 {
+			momentumScore += 3
+		} else if features.MacdCross == "BULLISH" {
+			momentumScore -= 5 // Breakdown against MACD
+		}
 	}
 
 	if momentumScore > 25 {
 		momentumScore = 25
+	} else if momentumScore < 0 {
+		momentumScore = 0
 	}
 	score += momentumScore
 
@@ -602,11 +896,30 @@ func CalculateBreakoutScore(prices []float64, extremes []float64, volumes []floa
 		structureScore += 3
 	}
 
+	// Fresh break of structure in the breakout direction
+	structureDirection := "BULLISH"
+	if direction == "SHORT" {
+		structureDirection = "BEARISH"
+	}
+	if features.LastStructureBreak.IsRecent(structureDirection, 3) {
+		structureScore += 3
+	}
+
 	if structureScore > 15 {
 		structureScore = 15
 	}
 	score += structureScore
 
+	// Ranging market: most range "breakouts" are fakeouts
+	if features.Choppiness > indicators.ChopRanging {
+		score *= 0.8
+	}
+
+	// No trend strength and none building: the move is more chop
+	if adxChop(features) {
+		score *= 0.85
+	}
+
 	return score
 }
 
@@ -873,16 +1186,14 @@ func CalculateShortReadinessScore(
 	// Parabolic + too far from EMA/VWAP = exhaustion
 	overextScore := 0.0
 
-	// Distance from EMA50 (z-score approach)
-	if len(ema50) > lastIdx && ema50[lastIdx] > 0 {
-		distFromEma := (currentPrice - ema50[lastIdx]) / ema50[lastIdx]
-		if distFromEma > 0.15 { // >15% above EMA50
-			overextScore += 10
-		} else if distFromEma > 0.10 {
-			overextScore += 7
-		} else if distFromEma > 0.05 {
-			overextScore += 4
-		}
+	// Statistical overextension: close vs 50-candle mean in standard deviations,
+	// so a 5% move means more on a quiet coin than on a volatile one
+	if zScore := indicators.CalculateZScore(prices, 50)[lastIdx]; zScore > 3 {
+		overextScore += 10
+	} else if zScore > 2.5 {
+		overextScore += 7
+	} else if zScore > 2 {
+		overextScore += 4
 	}
 
 	// 24h pump % (parabolic move)
@@ -933,6 +1244,11 @@ func CalculateShortReadinessScore(
 		}
 	}
 
+	// Net taker selling while price sits at the highs = buyers being absorbed
+	if features.TakerDeltaRatio < -0.1 && lastIdx >= 1 && currentHigh >= highs[lastIdx-1] {
+		volumeScore += 6
+	}
+
 	if volumeScore > 20 {
 		volumeScore = 20
 	}
@@ -994,6 +1310,15 @@ func CalculateShortReadinessScore(
 		crowdScore += 2
 	}
 
+	crowdScore += positioningCrowdScore(features)
+
+	// Long liquidations cascading: the crowded longs are being flushed out
+	if features.LiquidationCascade {
+		crowdScore += 10
+	} else if features.LiquidationImbalance > 0.5 {
+		crowdScore += 3 // Mostly longs liquidated, no cascade (yet)
+	}
+
 	if crowdScore > 20 {
 		crowdScore = 20
 	}
@@ -1013,21 +1338,9 @@ func CalculateShortReadinessScore(
 		structureScore += 4
 	}
 
-	// Check for BOS: if price broke below recent higher low
-	// Simple check: compare current low with lows from last 10-20 candles
-	if lastIdx >= 20 {
-		// Find recent swing low
-		recentLow := lows[lastIdx-1]
-		for i := lastIdx - 10; i < lastIdx; i++ {
-			if lows[i] < recentLow {
-				recentLow = lows[i]
-			}
-		}
-
-		// BOS if current price closed below recent swing low
-		if currentPrice < recentLow {
-			structureScore += 8 // BOS confirmed
-		}
+	// Bearish BOS/CHoCH: a close below the latest confirmed swing low
+	if hasRecentBearishBreak(highs, lows, prices) {
+		structureScore += 8 // BOS confirmed
 	}
 
 	// RSI overbought (not a trigger, but adds to readiness)
@@ -0,0 +1,479 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/indicators"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+const (
+	defaultOptimizeFolds      = 4
+	maxOptimizeFolds          = 10
+	defaultOptimizeMinSignals = 20
+	defaultOptimizeTop        = 10
+	defaultOptimizeHorizon    = "15m"
+	// minSnapshotsPerWindow keeps tiny archives from producing folds of noise
+	minSnapshotsPerWindow = 20
+)
+
+// The grid swept by the optimizer: every combination of component weights
+// and TRIGGER thresholds (3^5 x 5 = 1215 configurations)
+var (
+	optimizerWeightGrid  = []float64{0.5, 1, 1.5}
+	optimizerTriggerGrid = []float64{35, 40, 45, 50, 55}
+)
+
+var (
+	ErrUnknownHorizon      = domain.Validation("UNKNOWN_HORIZON", "horizon must be one of 5m, 15m, 1h, 4h")
+	ErrNotEnoughSnapshots  = domain.Validation("NOT_ENOUGH_SNAPSHOTS", "not enough archived snapshots in range for walk-forward validation")
+	ErrNoScoringCandidate  = domain.Validation("NO_SCORING_CANDIDATE", "no configuration produced enough signals; widen the range or lower minSignals")
+	ErrInvalidOptimizeArgs = domain.Validation("INVALID_OPTIMIZE_REQUEST", fmt.Sprintf("folds must be between 1 and %d and minSignals, top not negative", maxOptimizeFolds))
+)
+
+// OptimizeRequest parameterizes a walk-forward run; zero values take the defaults
+type OptimizeRequest struct {
+	From       time.Time
+	To         time.Time
+	Horizon    string // forward-return horizon the configurations are judged on (default 15m)
+	Folds      int    // test windows (default 4); the range is cut into Folds+1 windows
+	MinSignals int    // signals a configuration needs on a training range to be picked (default 20)
+	Top        int    // configurations listed in Best (default 10)
+	Save       bool   // store Best[0] as a shadow scoring version
+	Author     string
+	Note       string
+}
+
+// ScoringConfig is one candidate: component weights and the TRIGGER threshold
+type ScoringConfig struct {
+	Weights      domain.ScoreWeights `json:"weights"`
+	TriggerScore float64             `json:"triggerScore"`
+}
+
+// WalkForwardFold is one step: the configuration picked on every window
+// before the test window, and how it did on the test window
+type WalkForwardFold struct {
+	TrainFrom time.Time             `json:"trainFrom"`
+	TestFrom  time.Time             `json:"testFrom"`
+	TestTo    time.Time             `json:"testTo"`
+	Selected  *ScoringConfig        `json:"selected"` // nil when no configuration reached minSignals
+	Train     domain.ScoringMetrics `json:"train"`
+	Test      domain.ScoringMetrics `json:"test"`
+	Baseline  domain.ScoringMetrics `json:"baseline"` // live configuration on the test window
+}
+
+// RankedScoringConfig is a configuration with its out-of-sample result
+type RankedScoringConfig struct {
+	ScoringConfig
+	Test          domain.ScoringMetrics `json:"test"`          // pooled over every test window
+	FoldsSelected int                   `json:"foldsSelected"` // folds whose training picked it
+}
+
+// OptimizationReport is the result of a walk-forward run. WalkForward is the
+// honest estimate: each fold's pick measured only on data it wasn't picked
+// on. Best ranks every configuration on the pooled test windows, which is
+// itself a selection; treat a saved version as a candidate to shadow.
+type OptimizationReport struct {
+	From        time.Time              `json:"from"`
+	To          time.Time              `json:"to"`
+	Horizon     string                 `json:"horizon"`
+	Snapshots   int                    `json:"snapshots"`
+	Configs     int                    `json:"configs"`
+	Live        ScoringConfig          `json:"live"`
+	Folds       []WalkForwardFold      `json:"folds"`
+	WalkForward domain.ScoringMetrics  `json:"walkForward"`
+	Baseline    domain.ScoringMetrics  `json:"baseline"` // live configuration on the same test windows
+	Best        []RankedScoringConfig  `json:"best"`
+	Saved       *domain.ScoringVersion `json:"saved,omitempty"`
+}
+
+// ScoringEvaluation compares a scoring version with the live configuration
+// on the snapshots archived since it was saved
+type ScoringEvaluation struct {
+	Version    *domain.ScoringVersion `json:"version"`
+	From       time.Time              `json:"from"`
+	To         time.Time              `json:"to"`
+	Snapshots  int                    `json:"snapshots"`
+	Shadow     domain.ScoringMetrics  `json:"shadow"`
+	Live       domain.ScoringMetrics  `json:"live"`
+	LiveConfig ScoringConfig          `json:"liveConfig"`
+}
+
+// ScoringOptimizer tunes the reversal score weights and TRIGGER threshold on
+// archived snapshots labeled with their forward returns.
+//
+// Archived snapshots only carry the primary timeframe's features, so a
+// configuration is replayed by scaling the coin's archived final score with
+// the ratio of the primary TF's reweighted and live-weighted components. Only
+// coins that had some status were archived; coins a heavier weighting would
+// have lifted from below WATCH are not seen.
+type ScoringOptimizer struct {
+	dataset  *DatasetService
+	versions domain.ScoringVersionRepository
+	store    *config.Store
+}
+
+// NewScoringOptimizer creates a new scoring optimizer
+func NewScoringOptimizer(dataset *DatasetService, versions domain.ScoringVersionRepository, store *config.Store) *ScoringOptimizer {
+	return &ScoringOptimizer{dataset: dataset, versions: versions, store: store}
+}
+
+// scoringSample is one archived snapshot reduced to what a replay needs
+type scoringSample struct {
+	at         time.Time
+	score      float64 // archived final score
+	components ScoreComponents
+	base       float64 // components weighted by the live weights
+	eligible   bool    // 2-TF confluence outside a dead market
+	ret        float64 // short-side return at the horizon; NaN when not labeled yet
+	prev       int     // the symbol's previous snapshot when in the previous cycle, else -1
+	window     int
+}
+
+// scoreWith replays the final score under other weights
+func (s *scoringSample) scoreWith(w domain.ScoreWeights) float64 {
+	if s.base <= 0 {
+		return s.score
+	}
+	return math.Min(s.score*s.components.Weighted(w)/s.base, 100)
+}
+
+// signalSums accumulates TRIGGER outcomes
+type signalSums struct {
+	signals int
+	hits    int
+	sum     float64
+}
+
+func (m signalSums) add(o signalSums) signalSums {
+	return signalSums{signals: m.signals + o.signals, hits: m.hits + o.hits, sum: m.sum + o.sum}
+}
+
+func (m signalSums) metrics() domain.ScoringMetrics {
+	if m.signals == 0 {
+		return domain.ScoringMetrics{}
+	}
+	return domain.ScoringMetrics{
+		Signals:      m.signals,
+		HitRate:      float64(m.hits) / float64(m.signals),
+		AvgReturnPct: m.sum / float64(m.signals),
+	}
+}
+
+// better orders configurations by average return, then hit rate
+func (m signalSums) better(o signalSums) bool {
+	a, b := m.metrics(), o.metrics()
+	if a.AvgReturnPct != b.AvgReturnPct {
+		return a.AvgReturnPct > b.AvgReturnPct
+	}
+	return a.HitRate > b.HitRate
+}
+
+// Optimize sweeps the weight/threshold grid with walk-forward validation:
+// the range is cut into Folds+1 time windows, and for each fold the best
+// configuration on all earlier windows is measured on the next one.
+func (o *ScoringOptimizer) Optimize(ctx context.Context, req OptimizeRequest) (*OptimizationReport, error) {
+	if req.Horizon == "" {
+		req.Horizon = defaultOptimizeHorizon
+	}
+	if req.Folds == 0 {
+		req.Folds = defaultOptimizeFolds
+	}
+	if req.MinSignals == 0 {
+		req.MinSignals = defaultOptimizeMinSignals
+	}
+	if req.Top == 0 {
+		req.Top = defaultOptimizeTop
+	}
+	if req.Folds < 1 || req.Folds > maxOptimizeFolds || req.MinSignals < 0 || req.Top < 0 {
+		return nil, ErrInvalidOptimizeArgs
+	}
+	horizon, ok := horizonIndex(req.Horizon)
+	if !ok {
+		return nil, ErrUnknownHorizon
+	}
+
+	cfg := o.store.Current()
+	opts := liveScoreOptions(cfg)
+	live := ScoringConfig{Weights: opts.Weights, TriggerScore: opts.TriggerScore}
+	samples, err := o.samples(ctx, req.From, req.To, horizon, opts, cfg.Screener.ScanInterval)
+	if err != nil {
+		return nil, err
+	}
+	windows := req.Folds + 1
+	if len(samples) < windows*minSnapshotsPerWindow {
+		return nil, ErrNotEnoughSnapshots
+	}
+	bounds := assignWindows(samples, windows)
+
+	triggered := make([]bool, len(samples))
+	baseline := replayScoring(samples, windows, live, triggered)
+
+	type candidate struct {
+		cfg  ScoringConfig
+		sums []signalSums
+	}
+	configs := scoringGrid()
+	candidates := make([]candidate, 0, len(configs))
+	for _, c := range configs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate{cfg: c, sums: replayScoring(samples, windows, c, triggered)})
+	}
+
+	report := &OptimizationReport{
+		From:      req.From,
+		To:        req.To,
+		Horizon:   req.Horizon,
+		Snapshots: len(samples),
+		Configs:   len(configs),
+		Live:      live,
+		Folds:     make([]WalkForwardFold, 0, req.Folds),
+		Best:      []RankedScoringConfig{},
+	}
+	selected := make(map[ScoringConfig]int)
+	var walkForward, baselineTest signalSums
+	for k := 1; k < windows; k++ {
+		fold := WalkForwardFold{TrainFrom: bounds[0], TestFrom: bounds[k], TestTo: bounds[k+1], Baseline: baseline[k].metrics()}
+		baselineTest = baselineTest.add(baseline[k])
+
+		best := -1
+		var bestTrain signalSums
+		for i, c := range candidates {
+			var train signalSums
+			for _, s := range c.sums[:k] {
+				train = train.add(s)
+			}
+			if train.signals < req.MinSignals || train.signals == 0 {
+				continue
+			}
+			if best < 0 || train.better(bestTrain) {
+				best, bestTrain = i, train
+			}
+		}
+		if best >= 0 {
+			pick := candidates[best]
+			fold.Selected = &pick.cfg
+			fold.Train = bestTrain.metrics()
+			fold.Test = pick.sums[k].metrics()
+			walkForward = walkForward.add(pick.sums[k])
+			selected[pick.cfg]++
+		}
+		report.Folds = append(report.Folds, fold)
+	}
+	report.WalkForward = walkForward.metrics()
+	report.Baseline = baselineTest.metrics()
+
+	type ranked struct {
+		RankedScoringConfig
+		sums signalSums
+	}
+	var ranking []ranked
+	for _, c := range candidates {
+		var test signalSums
+		for _, s := range c.sums[1:] {
+			test = test.add(s)
+		}
+		if test.signals < req.MinSignals || test.signals == 0 {
+			continue
+		}
+		ranking = append(ranking, ranked{RankedScoringConfig{ScoringConfig: c.cfg, Test: test.metrics(), FoldsSelected: selected[c.cfg]}, test})
+	}
+	sort.SliceStable(ranking, func(i, j int) bool { return ranking[i].sums.better(ranking[j].sums) })
+	for i := 0; i < len(ranking) && i < req.Top; i++ {
+		report.Best = append(report.Best, ranking[i].RankedScoringConfig)
+	}
+
+	if req.Save {
+		if len(ranking) == 0 {
+			return nil, ErrNoScoringCandidate
+		}
+		top := ranking[0]
+		version := &domain.ScoringVersion{
+			CreatedAt:    time.Now().UTC(),
+			Source:       "optimizer",
+			Status:       domain.ScoringShadow,
+			Weights:      top.Weights,
+			TriggerScore: top.TriggerScore,
+			Horizon:      req.Horizon,
+			TestMetrics:  top.Test,
+			Note:         req.Note,
+		}
+		if err := o.versions.SaveScoringVersion(ctx, version); err != nil {
+			return nil, err
+		}
+		report.Saved = version
+		logging.Infof("Scoring optimizer: saved version #%d (%s, trigger %g) by %s", version.ID, version.Weights, version.TriggerScore, req.Author)
+	}
+	return report, nil
+}
+
+// Versions returns the newest scoring versions first
+func (o *ScoringOptimizer) Versions(ctx context.Context, limit int) ([]*domain.ScoringVersion, error) {
+	return o.versions.ListScoringVersions(ctx, limit)
+}
+
+// EvaluateVersion replays a scoring version next to the live configuration.
+// from defaults to the version's creation, to to now; at most the last 31
+// days of the range are used.
+func (o *ScoringOptimizer) EvaluateVersion(ctx context.Context, id int64, from, to time.Time) (*ScoringEvaluation, error) {
+	version, err := o.versions.GetScoringVersion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = version.CreatedAt
+	}
+	if to.Sub(from) > maxDatasetSpan {
+		from = to.Add(-maxDatasetSpan)
+	}
+	horizon, ok := horizonIndex(version.Horizon)
+	if !ok {
+		return nil, ErrUnknownHorizon
+	}
+
+	cfg := o.store.Current()
+	opts := liveScoreOptions(cfg)
+	live := ScoringConfig{Weights: opts.Weights, TriggerScore: opts.TriggerScore}
+	samples, err := o.samples(ctx, from, to, horizon, opts, cfg.Screener.ScanInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	triggered := make([]bool, len(samples))
+	shadow := replayScoring(samples, 1, ScoringConfig{Weights: version.Weights, TriggerScore: version.TriggerScore}, triggered)
+	current := replayScoring(samples, 1, live, triggered)
+	return &ScoringEvaluation{
+		Version:    version,
+		From:       from,
+		To:         to,
+		Snapshots:  len(samples),
+		Shadow:     shadow[0].metrics(),
+		Live:       current[0].metrics(),
+		LiveConfig: live,
+	}, nil
+}
+
+// samples loads the labeled snapshots of every symbol in [from, to]
+func (o *ScoringOptimizer) samples(ctx context.Context, from, to time.Time, horizon int, opts ScoreOptions, scanInterval time.Duration) ([]scoringSample, error) {
+	snapshots, labels, err := o.dataset.labeledSnapshots(ctx, "", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// A symbol seen again within two scan intervals is the same run of
+	// statuses, as the screener's own signal recording treats it
+	gap := 2 * scanInterval
+	last := make(map[string]int)
+	samples := make([]scoringSample, 0, len(snapshots))
+	for i := range snapshots {
+		snap := &snapshots[i]
+		coin := &snap.Coin
+		if coin.Features == nil {
+			continue
+		}
+		components := ScoreBreakdown(coin.Features, opts)
+		sample := scoringSample{
+			at:         snap.TakenAt,
+			score:      coin.Score,
+			components: components,
+			base:       components.Weighted(opts.Weights),
+			eligible:   coin.ConfluenceCount >= 2 && coin.Features.VolatilityRegime != indicators.VolRegimeLow,
+			ret:        -labels[i][horizon],
+			prev:       -1,
+		}
+		if j, ok := last[coin.Symbol]; ok && sample.at.Sub(samples[j].at) <= gap {
+			sample.prev = j
+		}
+		last[coin.Symbol] = len(samples)
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// replayScoring counts the TRIGGER signals cfg raises per window. Like the
+// screener, a symbol already triggering in the previous cycle is no new signal.
+// triggered is scratch space of len(samples).
+func replayScoring(samples []scoringSample, windows int, cfg ScoringConfig, triggered []bool) []signalSums {
+	sums := make([]signalSums, windows)
+	for i := range samples {
+		s := &samples[i]
+		triggered[i] = s.eligible && s.scoreWith(cfg.Weights) >= cfg.TriggerScore
+		if !triggered[i] || (s.prev >= 0 && triggered[s.prev]) || math.IsNaN(s.ret) {
+			continue
+		}
+		w := &sums[s.window]
+		w.signals++
+		w.sum += s.ret
+		if s.ret > 0 {
+			w.hits++
+		}
+	}
+	return sums
+}
+
+// assignWindows cuts the samples' time span into equal windows and returns
+// the window boundaries (windows+1 of them)
+func assignWindows(samples []scoringSample, windows int) []time.Time {
+	first, last := samples[0].at, samples[len(samples)-1].at
+	span := last.Sub(first) + time.Second
+	for i := range samples {
+		samples[i].window = int(int64(samples[i].at.Sub(first)) * int64(windows) / int64(span))
+	}
+	bounds := make([]time.Time, windows+1)
+	for k := range bounds {
+		bounds[k] = first.Add(time.Duration(int64(span) * int64(k) / int64(windows)))
+	}
+	return bounds
+}
+
+// scoringGrid lists every configuration the optimizer tries
+func scoringGrid() []ScoringConfig {
+	var grid []ScoringConfig
+	for _, over := range optimizerWeightGrid {
+		for _, crowd := range optimizerWeightGrid {
+			for _, exhaust := range optimizerWeightGrid {
+				for _, structure := range optimizerWeightGrid {
+					for _, momentum := range optimizerWeightGrid {
+						for _, trigger := range optimizerTriggerGrid {
+							grid = append(grid, ScoringConfig{
+								Weights: domain.ScoreWeights{
+									Overextension: over,
+									Crowding:      crowd,
+									Exhaustion:    exhaust,
+									Structure:     structure,
+									Momentum:      momentum,
+								},
+								TriggerScore: trigger,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+	return grid
+}
+
+func liveScoreOptions(cfg *config.Config) ScoreOptions {
+	return ParseScoreOptions(cfg.Screener.CCIExtreme, cfg.Screener.ScoreWeights, cfg.Screener.TriggerScore)
+}
+
+func horizonIndex(name string) (int, bool) {
+	for i, h := range domain.SignalHorizons {
+		if h.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"screener-backend/internal/config"
+	"screener-backend/internal/domain"
+)
+
+var ErrScoringProfileWeights = domain.Validation("SCORING_PROFILE_INVALID_WEIGHTS",
+	"each weight must be between 0 and 5, with at least one above 0")
+
+// ScoringProfileService holds the users' own weightings of the reversal score
+type ScoringProfileService struct {
+	repo    domain.ScoringProfileRepository
+	store   *config.Store
+	configs *ScreenerConfigService
+	regime  *RegimeFilter
+}
+
+// NewScoringProfileService creates a new scoring profile service
+func NewScoringProfileService(repo domain.ScoringProfileRepository, store *config.Store, configs *ScreenerConfigService, regime *RegimeFilter) *ScoringProfileService {
+	return &ScoringProfileService{repo: repo, store: store, configs: configs, regime: regime}
+}
+
+// Get returns the user's profile, or one with the configured weights
+// (screener.scoreWeights) before the user saves one
+func (s *ScoringProfileService) Get(ctx context.Context, userID string) (*domain.ScoringProfile, error) {
+	p, err := s.repo.GetScoringProfile(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		cfg := s.store.Current().Screener
+		return &domain.ScoringProfile{UserID: userID, Weights: ParseScoreOptions(cfg.CCIExtreme, cfg.ScoreWeights, cfg.TriggerScore).Weights}, nil
+	}
+	return p, err
+}
+
+// Save validates and stores the user's profile
+func (s *ScoringProfileService) Save(ctx context.Context, p *domain.ScoringProfile) error {
+	if !p.Weights.Valid() {
+		return ErrScoringProfileWeights
+	}
+	p.UpdatedAt = time.Now().UTC()
+	return s.repo.SaveScoringProfile(ctx, p)
+}
+
+// Rescore scores the coins' reversal signals with the weights of the user's
+// profile, the way the cycle scored them with the configured ones (see
+// ReversalScore), then sets their reversal status anew from the score: by
+// the screener's rules, gated by the BTC regime filter. The coins are
+// returned by score, highest first; a user without a profile gets them as
+// the cycle scored them. coins is not modified.
+func (s *ScoringProfileService) Rescore(ctx context.Context, userID string, coins []domain.CoinData) ([]domain.CoinData, error) {
+	profile, err := s.repo.GetScoringProfile(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return coins, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg := s.store.Current().Screener
+	triggerScore := ParseScoreOptions(cfg.CCIExtreme, cfg.ScoreWeights, cfg.TriggerScore).TriggerScore
+	// A disabled scalp strategy sets no reversal status
+	scalp := s.configs.Current(ctx).Scalp.Enabled
+
+	rescored := make([]domain.CoinData, len(coins))
+	for i, coin := range coins {
+		if scoreWith(&coin, profile.Weights) && scalp {
+			s.regime.Reapply(&coin, triggerScore)
+		}
+		rescored[i] = coin
+	}
+	sort.SliceStable(rescored, func(i, j int) bool { return rescored[i].Score > rescored[j].Score })
+	return rescored, nil
+}
+
+// scoreWith scores coin anew with weights from its timeframes' components;
+// false, leaving it as it is, when the cycle kept too little to do so
+func scoreWith(coin *domain.CoinData, weights domain.ScoreWeights) bool {
+	if coin.ScoreMultiplier == 0 || len(coin.TFScores) == 0 {
+		return false
+	}
+	for _, tf := range coin.TFScores {
+		if tf.Components == nil {
+			return false
+		}
+	}
+	coin.TFScores = slices.Clone(coin.TFScores)
+	coin.Score = ReversalScore(coin.TFScores, weights, coin.ScoreMultiplier)
+	return true
+}
@@ -1,60 +1,201 @@
 package usecase
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime/debug"
 	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"screener-backend/internal/config"
 	"screener-backend/internal/domain"
 	"screener-backend/internal/infrastructure/binance"
 	"screener-backend/internal/infrastructure/fcm"
 	"screener-backend/internal/infrastructure/indicators"
+	"screener-backend/internal/infrastructure/logging"
+	"screener-backend/internal/infrastructure/reporting"
+	"screener-backend/internal/infrastructure/tracing"
 	"screener-backend/internal/repository"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = tracing.Tracer("screener-backend/screener")
+
+// screenerSettings are the hot-reloadable knobs, swapped as one unit so a
+// cycle never sees half of an update.
+type screenerSettings struct {
+	scanInterval          time.Duration         // screener.scanInterval: time between screening cycles
+	notifyCooldown        time.Duration         // notifications.cooldown: minimum gap between alerts per key
+	vwapAnchor            indicators.VWAPAnchor // screener.vwapAnchor: session (default), week, swing_low, swing_high
+	scoreOptions          ScoreOptions          // screener.cciExtreme, scoreWeights and triggerScore: reversal score tuning
+	pullbackMA            indicators.MAType     // screener.pullbackTrendMa: ema (default) or hma for the 20/50 trend baseline
+	linregWindow          int                   // screener.linregLookback: candles in the intraday regression channel (default 50)
+	replaySymbols         string                // replay.symbols: symbols whose raw data every cycle records
+	replayKeep            int                   // replay.keep: recorded cycles kept
+	historyTopN           int                   // screener.historyTopN: coins per cycle kept in the score history
+	liquidationWindow     time.Duration         // screener.liquidationWindow: span the liquidation features total
+	liquidationCascadeUSD float64               // screener.liquidationCascadeUsd: 5m long liquidations of a cascade
+	recorded              replaySettings        // screener.* and regime.* as configured, stored with recorded cycles
+}
+
+func newScreenerSettings(cfg *config.Config) *screenerSettings {
+	return &screenerSettings{
+		scanInterval:          cfg.Screener.ScanInterval,
+		notifyCooldown:        cfg.Notifications.Cooldown,
+		vwapAnchor:            indicators.ParseVWAPAnchor(cfg.Screener.VWAPAnchor),
+		scoreOptions:          ParseScoreOptions(cfg.Screener.CCIExtreme, cfg.Screener.ScoreWeights, cfg.Screener.TriggerScore),
+		pullbackMA:            indicators.ParseMAType(cfg.Screener.PullbackTrendMA),
+		linregWindow:          cfg.Screener.LinRegLookback,
+		replaySymbols:         cfg.Replay.Symbols,
+		replayKeep:            cfg.Replay.Keep,
+		historyTopN:           cfg.Screener.HistoryTopN,
+		liquidationWindow:     cfg.Screener.LiquidationWindow,
+		liquidationCascadeUSD: cfg.Screener.LiquidationCascadeUSD,
+		recorded:              replaySettings{Screener: cfg.Screener, Regime: cfg.Regime},
+	}
+}
+
 type ScreenerUsecase struct {
 	repo          domain.ScreenerRepository
 	binanceClient *binance.Client
 	fcmClient     *fcm.Client
 	tokenRepo     *repository.TokenRepository
 	notifiedCoins map[string]time.Time // Track notified coins with timestamp
+	cooldowns     domain.CooldownStore  // persists notifiedCoins across restarts
+	settings      atomic.Pointer[screenerSettings]
+	archive       domain.MarketArchiveRepository
+	archivedUntil map[string]time.Time // symbol|interval -> open time of the last archived candle
+	events        domain.EventPublisher
+	signals       domain.SignalRepository
+	activeSignals map[string]bool // strategy|symbol signaling in the previous cycle
+	regime        *RegimeFilter
+	marketContext *MarketContextService
+	blackouts     *BlackoutCalendar
+	correlations  *CorrelationTracker
+	recordings    domain.CycleRecordingRepository
+	watchlists    *WatchlistService
+	strategies    *ScreenerConfigService
+	profiles      *ScoringProfileService // weight the watchlist alerts' reversal scores
+	openInterest  *OpenInterestTracker
+	positioning   *PositioningTracker
+	liquidations  *binance.LiquidationFeed // nil with binance.liquidations off
 	mu            sync.RWMutex
 }
 
-func NewScreenerUsecase(repo domain.ScreenerRepository, tokenRepo *repository.TokenRepository, fcmClient *fcm.Client, binanceBaseURL string) *ScreenerUsecase {
-	return &ScreenerUsecase{
+// cycleEnv is what the strategies of one cycle read: the settings, the
+// market data and the market-wide modifiers, fixed for the whole cycle
+type cycleEnv struct {
+	settings        *screenerSettings
+	strategies      *domain.ScreenerConfig // strategy toggles and timeframes
+	tickers         map[string]binance.Ticker24h
+	klines          klineFetcher
+	funding         func(ctx context.Context, symbol string) (float64, error)
+	openInterest    func(ctx context.Context, symbol string) (float64, error) // OI delta, percent
+	positioning     func(ctx context.Context, symbol string) (positioning, error)
+	liquidations    func(symbol string) liquidationFlow
+	regime          *RegimeFilter
+	shortMultiplier float64 // market context modifier of reversal scores
+	longMultiplier  float64 // market context modifier of pullback scores
+}
+
+func NewScreenerUsecase(repo domain.ScreenerRepository, tokenRepo *repository.TokenRepository, fcmClient *fcm.Client, cfg *config.Config, archive domain.MarketArchiveRepository, cooldowns domain.CooldownStore, events domain.EventPublisher, signals domain.SignalRepository, regime *RegimeFilter, marketContext *MarketContextService, blackouts *BlackoutCalendar, correlations *CorrelationTracker, recordings domain.CycleRecordingRepository, watchlists *WatchlistService, strategies *ScreenerConfigService, profiles *ScoringProfileService) *ScreenerUsecase {
+	client := binance.NewClient(cfg.Binance.BaseURL).WithKlineCache(
+		binance.NewKlineCache(cfg.Binance.KlineCacheSize, cfg.Binance.KlineCacheLiveTTL))
+	if cfg.Binance.Stream {
+		client.WithStream(binance.NewMarketStream(cfg.Binance.StreamURL))
+	}
+	uc := &ScreenerUsecase{
 		repo:          repo,
-		binanceClient: binance.NewClient(binanceBaseURL),
+		archive:       archive,
+		archivedUntil: make(map[string]time.Time),
+		binanceClient: client,
 		fcmClient:     fcmClient,
 		tokenRepo:     tokenRepo,
 		notifiedCoins: make(map[string]time.Time),
+		cooldowns:     cooldowns,
+		events:        events,
+		signals:       signals,
+		activeSignals: make(map[string]bool),
+		regime:        regime,
+		marketContext: marketContext,
+		blackouts:     blackouts,
+		correlations:  correlations,
+		recordings:    recordings,
+		watchlists:    watchlists,
+		strategies:    strategies,
+		profiles:      profiles,
+		openInterest:  NewOpenInterestTracker(client, cfg),
+		positioning:   NewPositioningTracker(client, cfg),
 	}
-}
+	if cfg.Binance.Liquidations {
+		uc.liquidations = binance.NewLiquidationFeed(cfg.Binance.StreamURL)
+	}
+	uc.settings.Store(newScreenerSettings(cfg))
 
-// Run starts the screening loop.
-func (uc *ScreenerUsecase) Run() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	uc.RestoreCooldowns(ctx)
+	return uc
+}
 
-	// Initial run
-	go uc.process()
+// publishCoins sends the cycle's coins to the WebSocket clients of every instance
+func (uc *ScreenerUsecase) publishCoins(ctx context.Context, coins []domain.CoinData) {
+	payload, err := json.Marshal(coins)
+	if err != nil {
+		logging.Warnf("Encoding coins event failed: %v", err)
+		return
+	}
+	if err := uc.events.Publish(ctx, domain.TopicCoins, payload); err != nil {
+		logging.Warnf("Publishing coins event failed: %v", err)
+	}
+}
 
-	for range ticker.C {
-		go uc.process()
+// ApplyConfig swaps in the reloadable screener settings. Cycles already
+// running finish with the settings they started with.
+func (uc *ScreenerUsecase) ApplyConfig(cfg *config.Config) {
+	next := newScreenerSettings(cfg)
+	prev := uc.settings.Swap(next)
+	uc.openInterest.ApplyConfig(cfg)
+	uc.positioning.ApplyConfig(cfg)
+	if prev.scanInterval != next.scanInterval {
+		logging.Infof("Screening interval changed to %v", next.scanInterval)
 	}
 }
 
-func (uc *ScreenerUsecase) process() {
+// ScanInterval is the current pause between screening cycles
+func (uc *ScreenerUsecase) ScanInterval() time.Duration {
+	return uc.settings.Load().scanInterval
+}
+
+// RunCycle screens every symbol once, then publishes and notifies.
+// The scheduler runs it every ScanInterval.
+func (uc *ScreenerUsecase) RunCycle(ctx context.Context) (err error) {
 	start := time.Now()
-	log.Println("Starting screening cycle...")
+	settings := uc.settings.Load()
+	logging.Infof("Starting screening cycle...")
+
+	ctx, cycleSpan := tracer.Start(ctx, "screener.cycle")
+	defer func() { tracing.EndSpan(cycleSpan, err) }()
+
+	// Klines are fetched once per symbol and interval for the whole cycle
+	cycleKlines := newCycleKlines(uc.getKlines)
+
+	// BTC regime for the signal filters; on failure the last one is used
+	if err := uc.regime.Detect(ctx, cycleKlines.klines); err != nil {
+		logging.Warnf("BTC regime detection failed: %v", err)
+	}
 
 	// 1. Get Active Symbols
-	symbols, err := uc.binanceClient.GetActiveTradingSymbols()
+	symbols, err := uc.binanceClient.GetActiveTradingSymbols(ctx)
 	if err != nil {
-		log.Printf("Error getting symbols: %v", err)
-		return
+		return fmt.Errorf("get symbols: %w", err)
 	}
 
 	// Limit to top volume or something if too many?
@@ -65,10 +206,9 @@ func (uc *ScreenerUsecase) process() {
 	// Dart app processes ALL.
 	// Let's try to process a subset for safety in this initial version, or use `GetFutures24hrTicker` to filter top volume first.
 
-	tickers, err := uc.binanceClient.GetFutures24hrTicker()
+	tickers, err := uc.binanceClient.GetFutures24hrTicker(ctx)
 	if err != nil {
-		log.Printf("Error getting tickers: %v", err)
-		return
+		return fmt.Errorf("get tickers: %w", err)
 	}
 
 	// Create map for easy access
@@ -77,475 +217,421 @@ func (uc *ScreenerUsecase) process() {
 		tickerMap[t.Symbol] = t
 	}
 
+	// Liquidations are followed from the first cycle on, so instances that
+	// never screen never connect
+	if uc.liquidations != nil {
+		uc.liquidations.Start()
+	}
+
+	env := &cycleEnv{
+		settings:        settings,
+		strategies:      uc.strategies.Current(ctx),
+		tickers:         tickerMap,
+		klines:          cycleKlines.klines,
+		funding:         uc.binanceClient.GetFundingRate,
+		openInterest:    uc.openInterest.Delta,
+		positioning:     uc.positioning.Get,
+		liquidations:    liquidationSource(uc.liquidations, settings.liquidationWindow),
+		regime:          uc.regime,
+		shortMultiplier: uc.marketContext.ShortMultiplier(),
+		longMultiplier:  uc.marketContext.LongMultiplier(),
+	}
+	// Raw data of the replay.symbols, so the cycle can be replayed
+	recorder := newCycleRecorder(settings.replaySymbols)
+	if recorder != nil {
+		recorder.attach(env)
+	}
+
 	var computedCoins []domain.CoinData
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	
-	sem := make(chan struct{}, 10) // Semaphore to limit concurrency
 
-	// Filter symbols to those present in tickerMap (Futures)
+	// Watchlisted symbols are screened first and even without a 24h ticker,
+	// so a cycle cut short by its timeout still covers them
+	watched := uc.watchlists.Symbols(ctx)
 	var targetSymbols []string
 	for _, s := range symbols {
-		if _, ok := tickerMap[s]; ok {
+		if watched[s] {
 			targetSymbols = append(targetSymbols, s)
 		}
 	}
 
-	// Let's pick top 20 by volume for the demo/safety first? 
-	// Or just do all but slowly?
-	// 10 concurrent requests * 100+ symbols might trigger limit. 2400 req/min is weight.
-	// Klines weight is 2 usually.
-	
-	log.Printf("Found %d active symbols", len(targetSymbols))
-
-	// Core timeframes for scalping: 1m + 5m
-	// Intraday timeframes: 15m + 1h
-	// Pullback setup: 5m + 15m (trend), 1m + 3m (execution)
-	// Breakout: 15m + 1h (for solid breakouts)
-	coreTimeframes := []string{"1m", "5m"}
-	intradayTimeframes := []string{"15m", "1h"}
-	pullbackSetupTFs := []string{"5m", "15m"}
-	pullbackExecTFs := []string{"1m", "3m"}
-	breakoutTimeframes := []string{"15m", "1h"}
+	// Filter symbols to those present in tickerMap (Futures)
+	for _, s := range symbols {
+		if _, ok := tickerMap[s]; ok && !watched[s] {
+			targetSymbols = append(targetSymbols, s)
+		}
+	}
+
+	// Every symbol is screened at once; the Binance client queues the
+	// requests past its weight budget, in the order they are made
+	logging.Infof("Found %d active symbols", len(targetSymbols))
+	cycleSpan.SetAttributes(attribute.Int("screener.symbols", len(targetSymbols)))
 
 	for _, sym := range targetSymbols {
 		wg.Add(1)
 		go func(symbol string) {
 			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			// Funding Rate (same for all TFs)
-			funding, _ := uc.binanceClient.GetFundingRate(symbol)
-
-			var tfScores []domain.TimeframeScore
-			var tfFeatures []domain.TimeframeFeatures
-			var featuresMap = make(map[string]*domain.MarketFeatures)
-			var pricesMap = make(map[string]float64)
-
-			// === SCALPING ANALYSIS (1m + 5m) ===
-			for _, tf := range coreTimeframes {
-				rawKlines, err := uc.binanceClient.GetKlines(symbol, tf, 100)
-				if err != nil {
-					continue
-				}
-				if len(rawKlines) < 50 {
-					continue
-				}
-
-				// Parse Klines to float slices
-				prices := make([]float64, len(rawKlines))
-				highs := make([]float64, len(rawKlines))
-				lows := make([]float64, len(rawKlines))
-				volumes := make([]float64, len(rawKlines))
-
-				for i, k := range rawKlines {
-					h, _ := parseValue(k[2])
-					l, _ := parseValue(k[3])
-					c, _ := parseValue(k[4])
-					v, _ := parseValue(k[5]) // Volume is at index 5
-					prices[i] = c
-					highs[i] = h
-					lows[i] = l
-					volumes[i] = v
+			// A panic on one symbol's data skips that symbol instead of
+			// taking the process down
+			defer func() {
+				if r := recover(); r != nil {
+					logging.Warnf("Screening %s panicked: %v", symbol, r)
+					reporting.Capture(ctx, reporting.Event{
+						Message: "panic while screening symbol",
+						Err:     fmt.Errorf("panic: %v", r),
+						Symbol:  symbol,
+						Stack:   debug.Stack(),
+					})
 				}
+			}()
 
-				// Calculate Indicators
-				ema50 := indicators.CalculateEMA(prices, 50)
-				vwap := make([]float64, len(prices)) // placeholder
-				rsi := indicators.CalculateRSI(prices, 14)
-				atr := indicators.CalculateATR(highs, lows, prices, 14)
-				bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
-				pivots := indicators.FindPivotLows(lows, 5, 2)
-
-				features := ExtractFeatures(
-					prices, highs, lows, volumes,
-					tickerMap[symbol],
-					ema50, vwap, rsi,
-					bb, atr, pivots,
-					funding, 0,
-				)
-
-				if features == nil {
-					continue
-				}
-
-				scoreResult := CalculateScore(features)
-				tfScores = append(tfScores, domain.TimeframeScore{
-					TF:    tf,
-					Score: scoreResult,
-					RSI:   features.RSI,
-				})
-				tfFeatures = append(tfFeatures, domain.TimeframeFeatures{
-					TF:             tf,
-					RSI:            features.RSI,
-					OverExtEma:     features.OverExtEma,
-					IsAboveUpperBB: features.IsAboveUpperBand,
-					IsBreakdown:    features.IsBreakdown,
-				})
-				featuresMap[tf] = features
-				pricesMap[tf] = prices[len(prices)-1]
+			if coin, ok := uc.screenSymbol(ctx, env, symbol); ok {
+				mu.Lock()
+				computedCoins = append(computedCoins, coin)
+				mu.Unlock()
 			}
+		}(sym)
+	}
 
-			// === INTRADAY ANALYSIS (15m + 1h) ===
-			var intradayTFScores []domain.TimeframeScore
-			var intradayFeaturesMap = make(map[string]*domain.MarketFeatures)
-
-			for _, tf := range intradayTimeframes {
-				rawKlines, err := uc.binanceClient.GetKlines(symbol, tf, 100)
-				if err != nil {
-					continue
-				}
-				if len(rawKlines) < 50 {
-					continue
-				}
-
-				prices := make([]float64, len(rawKlines))
-				highs := make([]float64, len(rawKlines))
-				lows := make([]float64, len(rawKlines))
-				volumes := make([]float64, len(rawKlines))
-
-				for i, k := range rawKlines {
-					h, _ := parseValue(k[2])
-					l, _ := parseValue(k[3])
-					c, _ := parseValue(k[4])
-					v, _ := parseValue(k[5])
-					prices[i] = c
-					highs[i] = h
-					lows[i] = l
-					volumes[i] = v
-				}
-
-				ema50 := indicators.CalculateEMA(prices, 50)
-				vwap := make([]float64, len(prices))
-				rsi := indicators.CalculateRSI(prices, 14)
-				atr := indicators.CalculateATR(highs, lows, prices, 14)
-				bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
-				pivots := indicators.FindPivotLows(lows, 5, 2)
-
-				features := ExtractFeatures(
-					prices, highs, lows, volumes,
-					tickerMap[symbol],
-					ema50, vwap, rsi,
-					bb, atr, pivots,
-					funding, 0,
-				)
-
-				if features == nil {
-					continue
-				}
+	wg.Wait()
+	
+	// Sort coins by score (highest first)
+	sort.Slice(computedCoins, func(i, j int) bool {
+		return computedCoins[i].Score > computedCoins[j].Score
+	})
+	
+	uc.repo.SaveCoins(computedCoins)
+	uc.publishCoins(ctx, computedCoins)
+	uc.archiveSnapshots(ctx, start, computedCoins)
+	uc.recordScoreHistory(ctx, start, computedCoins, settings.historyTopN)
+	uc.recordSignals(ctx, start, computedCoins)
+	if err := uc.correlations.Update(ctx, computedCoins, cycleKlines.klines); err != nil {
+		logging.Warnf("Correlation matrix update failed: %v", err)
+	}
+	if recorder != nil {
+		if err := recorder.save(ctx, uc.recordings, start, computedCoins, settings.replayKeep); err != nil {
+			logging.Warnf("Saving the cycle recording failed: %v", err)
+		}
+	}
+	
+	// Send FCM notifications for TRIGGER coins
+	uc.sendNotificationsForTriggers(ctx, computedCoins)
+	
+	// Send FCM notifications for BREAKOUT coins
+	uc.sendNotificationsForBreakouts(ctx, computedCoins)
+	
+	// Send FCM notifications for watchlisted coins below the global thresholds
+	uc.sendNotificationsForWatchlists(ctx, computedCoins)
+	
+	fetched, reused := cycleKlines.stats()
+	logging.Infof("Cycle completed in %v. Processed %d coins, fetched %d kline series (%d reads reused).", time.Since(start), len(computedCoins), fetched, reused)
+	return nil
+}
 
-				scoreResult := CalculateScore(features)
-				intradayTFScores = append(intradayTFScores, domain.TimeframeScore{
-					TF:    tf,
-					Score: scoreResult,
-					RSI:   features.RSI,
-				})
-				intradayFeaturesMap[tf] = features
-			}
+// screenSymbol runs every strategy on one symbol with the cycle's data;
+// false when there is too little data to score it
+func (uc *ScreenerUsecase) screenSymbol(ctx context.Context, env *cycleEnv, symbol string) (domain.CoinData, bool) {
+	settings := env.settings
+	strategies := env.strategies
+	tickerMap := env.tickers
+
+	// Timeframes per strategy, fastest first (defaults in parentheses);
+	// a disabled strategy gets none and is skipped
+	// Core timeframes for scalping (1m + 5m), screened even when disabled
+	// Intraday timeframes (15m + 1h)
+	// Pullback setup (5m + 15m, trend) and execution (1m + 3m)
+	// Breakout (15m + 1h, for solid breakouts)
+	// Follow trend (15m + 1h)
+	coreTimeframes := strategies.Scalp.Timeframes
+	var intradayTimeframes, pullbackSetupTFs, pullbackExecTFs, breakoutTimeframes, trendTimeframes []string
+	if strategies.Intraday.Enabled {
+		intradayTimeframes = strategies.Intraday.Timeframes
+	}
+	if strategies.Pullback.Enabled {
+		pullbackSetupTFs = strategies.Pullback.SetupTimeframes
+		pullbackExecTFs = strategies.Pullback.ExecutionTimeframes
+	}
+	if strategies.Breakout.Enabled {
+		breakoutTimeframes = strategies.Breakout.Timeframes
+	}
+	if strategies.FollowTrend.Enabled {
+		trendTimeframes = strategies.FollowTrend.Timeframes
+	}
 
-			// Need at least 2 TFs to evaluate scalping
-			if len(tfScores) < 2 {
-				return
-			}
+	symCtx, symSpan := tracer.Start(ctx, "screener.symbol", trace.WithAttributes(attribute.String("symbol", symbol)))
+	defer symSpan.End()
+	stages := tracing.NewStages(symCtx, tracer)
+	defer stages.End()
 
-			// === MULTI-TF CONFLUENCE SCORING ===
-			// Count how many TFs are showing overbought signals
-			confluenceCount := 0
-			var totalScore float64
-			var primaryTF string
-			var primaryFeatures *domain.MarketFeatures
-			var currentPrice float64
-
-			for _, tf := range coreTimeframes {
-				feat, ok := featuresMap[tf]
-				if !ok {
-					continue
-				}
+	// Funding Rate and OI delta (same for all TFs)
+	funding, _ := env.funding(symCtx, symbol)
+	oiDelta, err := env.openInterest(symCtx, symbol)
+	if err != nil {
+		logging.Symbolf(symbol, "OI delta failed: %v", err)
+	}
+	ratios, err := env.positioning(symCtx, symbol)
+	if err != nil {
+		logging.Symbolf(symbol, "Long/short ratios failed: %v", err)
+	}
+	liquidations := env.liquidations(symbol)
+
+	var tfScores []domain.TimeframeScore
+	var tfFeatures []domain.TimeframeFeatures
+	var featuresMap = make(map[string]*domain.MarketFeatures)
+	var pricesMap = make(map[string]float64)
+
+	stages.Start("strategy.scalping")
+	// === SCALPING ANALYSIS (1m + 5m) ===
+	for _, tf := range coreTimeframes {
+		rawKlines, err := env.klines(stages.Context(), symbol, tf, 100)
+		if err != nil {
+			continue
+		}
+		if len(rawKlines) < 50 {
+			continue
+		}
 
-				// A TF is "aligned" if it shows overbought signals OR losing momentum
-				// Added momentum loss signals for better reversal detection
-				isOverbought := feat.RSI > 60 || feat.OverExtEma > 0.02 || feat.IsAboveUpperBand
-				hasLosingMomentum := feat.IsLosingMomentum || feat.HasRsiDivergence || feat.HasVolumeDivergence
-				isAligned := isOverbought || hasLosingMomentum
-				if isAligned {
-					confluenceCount++
-				}
+		// Parse Klines to float slices
+		prices := make([]float64, len(rawKlines))
+		highs := make([]float64, len(rawKlines))
+		lows := make([]float64, len(rawKlines))
+		volumes := make([]float64, len(rawKlines))
+
+		for i, k := range rawKlines {
+			h, _ := parseValue(k[2])
+			l, _ := parseValue(k[3])
+			c, _ := parseValue(k[4])
+			v, _ := parseValue(k[5]) // Volume is at index 5
+			prices[i] = c
+			highs[i] = h
+			lows[i] = l
+			volumes[i] = v
+		}
 
-				// Find highest scoring TF as primary
-				for _, ts := range tfScores {
-					if ts.TF == tf {
-						totalScore += ts.Score
-						if primaryFeatures == nil || ts.Score > CalculateScore(primaryFeatures) {
-							primaryTF = tf
-							primaryFeatures = feat
-							currentPrice = pricesMap[tf]
-						}
-					}
-				}
-			}
+		// Calculate Indicators
+		ema50 := indicators.CalculateEMA(prices, 50)
+		vwap := indicators.CalculateAnchoredVWAP(indicators.ParseKlineOpenTimes(rawKlines), highs, lows, prices, volumes, settings.vwapAnchor)
+		rsi := indicators.CalculateRSI(prices, 14)
+		atr := indicators.CalculateATR(highs, lows, prices, 14)
+		bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
+		pivots := indicators.FindPivotLows(lows, 5, 2)
+
+		features := ExtractFeatures(
+			prices, highs, lows, volumes,
+			tickerMap[symbol],
+			ema50, vwap, rsi,
+			bb, atr, pivots,
+			funding, oiDelta,
+		)
+
+		if features == nil {
+			continue
+		}
 
-			if primaryFeatures == nil {
-				return
-			}
+		applyTakerFlow(features, rawKlines, highs)
+		applyPositioning(features, ratios)
+
+		components := ScoreBreakdown(features, settings.scoreOptions)
+		tfScores = append(tfScores, domain.TimeframeScore{
+			TF:         tf,
+			Score:      components.Weighted(settings.scoreOptions.Weights),
+			RSI:        features.RSI,
+			Components: &components,
+		})
+		tfFeatures = append(tfFeatures, domain.TimeframeFeatures{
+			TF:             tf,
+			RSI:            features.RSI,
+			OverExtEma:     features.OverExtEma,
+			IsAboveUpperBB: features.IsAboveUpperBand,
+			IsBreakdown:    features.IsBreakdown,
+		})
+		featuresMap[tf] = features
+		pricesMap[tf] = prices[len(prices)-1]
+	}
 
-			// === CONFLUENCE BONUS ===
-			// Base score is average of all TFs
-			avgScore := totalScore / float64(len(tfScores))
-
-			// Confluence multiplier for 1m + 5m:
-			// 2 TFs aligned: x1.3 (TRIGGERED - ready for entry!)
-			// 1 TF aligned: x1.1 (WATCH)
-			// 0 TFs aligned: x1.0 (AVOID)
-			var confluenceMultiplier float64
-			switch confluenceCount {
-			case 2:
-				confluenceMultiplier = 1.3
-			case 1:
-				confluenceMultiplier = 1.1
-			default:
-				confluenceMultiplier = 1.0
-			}
+	stages.Start("strategy.intraday")
+	// === INTRADAY ANALYSIS (15m + 1h) ===
+	var intradayTFScores []domain.TimeframeScore
+	var intradayFeaturesMap = make(map[string]*domain.MarketFeatures)
 
-			finalScore := avgScore * confluenceMultiplier
-			if finalScore > 100 {
-				finalScore = 100
-			}
+	for _, tf := range intradayTimeframes {
+		rawKlines, err := env.klines(stages.Context(), symbol, tf, 100)
+		if err != nil {
+			continue
+		}
+		if len(rawKlines) < 50 {
+			continue
+		}
 
-			coin := domain.CoinData{
-				Symbol:             symbol,
-				Price:              currentPrice,
-				Score:              finalScore,
-				Status:             "",
-				TriggerTF:          primaryTF,
-				ConfluenceCount:    confluenceCount,
-				TFScores:           tfScores,
-				TFFeatures:         tfFeatures,
-				PriceChangePercent: primaryFeatures.PctChange24h,
-				FundingRate:        funding,
-				Features:           primaryFeatures,
-				IntradayTFScores:   intradayTFScores,
-			}
+		prices := make([]float64, len(rawKlines))
+		highs := make([]float64, len(rawKlines))
+		lows := make([]float64, len(rawKlines))
+		volumes := make([]float64, len(rawKlines))
+
+		for i, k := range rawKlines {
+			h, _ := parseValue(k[2])
+			l, _ := parseValue(k[3])
+			c, _ := parseValue(k[4])
+			v, _ := parseValue(k[5])
+			prices[i] = c
+			highs[i] = h
+			lows[i] = l
+			volumes[i] = v
+		}
 
-			// === INTRADAY STATUS (15m + 1h) - SHORT ONLY ===
-			// Fokus mencari setup SHORT/SELL berdasarkan exhaustion signals
-			// Score 0-100: <50 = strong buy (jangan short), 50-70 = waspada, >70 = ready to short
-			if len(intradayTFScores) >= 2 {
-				var intradayPrimaryFeatures *domain.MarketFeatures
-				var primary15mFeatures *domain.MarketFeatures
-				var primary1hFeatures *domain.MarketFeatures
+		ema50 := indicators.CalculateEMA(prices, 50)
+		vwap := indicators.CalculateAnchoredVWAP(indicators.ParseKlineOpenTimes(rawKlines), highs, lows, prices, volumes, settings.vwapAnchor)
+		rsi := indicators.CalculateRSI(prices, 14)
+		atr := indicators.CalculateATR(highs, lows, prices, 14)
+		bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
+		pivots := indicators.FindPivotLows(lows, 5, 2)
+
+		features := ExtractFeatures(
+			prices, highs, lows, volumes,
+			tickerMap[symbol],
+			ema50, vwap, rsi,
+			bb, atr, pivots,
+			funding, oiDelta,
+		)
+
+		if features == nil {
+			continue
+		}
 
-				// Get features for both timeframes
-				feat15m, ok15m := intradayFeaturesMap["15m"]
-				feat1h, ok1h := intradayFeaturesMap["1h"]
+		applyTakerFlow(features, rawKlines, highs)
+		applyLiquidations(features, liquidations, settings.liquidationWindow, settings.liquidationCascadeUSD)
+		applyPositioning(features, ratios)
+
+		scoreResult := CalculateScoreWithOptions(features, settings.scoreOptions)
+		intradayTFScores = append(intradayTFScores, domain.TimeframeScore{
+			TF:    tf,
+			Score: scoreResult,
+			RSI:   features.RSI,
+		})
+		intradayFeaturesMap[tf] = features
+	}
 
-				if ok15m {
-					primary15mFeatures = feat15m
-				}
-				if ok1h {
-					primary1hFeatures = feat1h
-				}
+	// Need at least 2 TFs to evaluate scalping
+	if len(tfScores) < 2 {
+		return domain.CoinData{}, false
+	}
 
-				// Use 15m as primary (faster reaction)
-				if primary15mFeatures != nil {
-					intradayPrimaryFeatures = primary15mFeatures
-				} else if primary1hFeatures != nil {
-					intradayPrimaryFeatures = primary1hFeatures
-				}
+	stages.Start("strategy.confluence")
+	// === MULTI-TF CONFLUENCE SCORING ===
+	// Count how many TFs are showing overbought signals
+	confluenceCount := 0
+	var primaryTF string
+	var primaryFeatures *domain.MarketFeatures
+	var currentPrice float64
+
+	for _, tf := range coreTimeframes {
+		feat, ok := featuresMap[tf]
+		if !ok {
+			continue
+		}
 
-				if intradayPrimaryFeatures != nil {
-					// Get klines for detailed analysis
-					rawKlines15m, err15m := uc.binanceClient.GetKlines(symbol, "15m", 100)
-					
-					if err15m == nil && len(rawKlines15m) >= 30 {
-						// Parse klines
-						prices := make([]float64, len(rawKlines15m))
-						highs := make([]float64, len(rawKlines15m))
-						lows := make([]float64, len(rawKlines15m))
-						volumes := make([]float64, len(rawKlines15m))
-
-						for i, k := range rawKlines15m {
-							h, _ := parseValue(k[2])
-							l, _ := parseValue(k[3])
-							c, _ := parseValue(k[4])
-							v, _ := parseValue(k[5])
-							prices[i] = c
-							highs[i] = h
-							lows[i] = l
-							volumes[i] = v
-						}
+		// A TF is "aligned" if it shows overbought signals OR losing momentum
+		// Added momentum loss signals for better reversal detection
+		isOverbought := feat.RSI > 60 || feat.OverExtEma > 0.02 || feat.IsAboveUpperBand
+		hasLosingMomentum := feat.IsLosingMomentum || feat.HasRsiDivergence || feat.HasVolumeDivergence
+		isAligned := isOverbought || hasLosingMomentum
+		if isAligned {
+			confluenceCount++
+		}
 
-						// Calculate EMAs and RSI for short readiness
-						ema20 := indicators.CalculateEMA(prices, 20)
-						ema50 := indicators.CalculateEMA(prices, 50)
-						rsi := indicators.CalculateRSI(prices, 14)
-
-						// Calculate SHORT READINESS SCORE (0-100)
-						shortReadinessScore := CalculateShortReadinessScore(
-							prices, highs, lows, volumes,
-							ema20, ema50, rsi,
-							intradayPrimaryFeatures,
-							tickerMap[symbol],
-						)
-
-						coin.IntradayScore = shortReadinessScore
-
-						// Determine status based on score and conditions
-						// <50 = STRONG_BUY (jangan short!)
-						// 50-70 = WATCH (waspada, cari trigger)
-						// >70 + BOS = READY (candidate short dengan trigger)
-						// >70 + BOS + volume spike = HOT (execute short!)
-
-						if shortReadinessScore < 50 {
-							// Strong buy territory - DON'T SHORT
-							// Check if truly strong or just no exhaustion yet
-							hasStrongBuySignal := false
-							
-							// Struktur masih sehat: higher highs, EMA alignment
-							if len(prices) >= 20 && len(ema20) >= 20 && len(ema50) >= 20 {
-								lastIdx := len(prices) - 1
-								// Check higher high pattern
-								recentHigh := highs[lastIdx]
-								prevHigh := highs[lastIdx-10]
-								if recentHigh > prevHigh && prices[lastIdx] > ema20[lastIdx] && ema20[lastIdx] > ema50[lastIdx] {
-									hasStrongBuySignal = true
-								}
-							}
-
-							if hasStrongBuySignal {
-								coin.IntradayStatus = "STRONG_BUY" // Roket masih punya bahan bakar
-							} else {
-								coin.IntradayStatus = "" // Neutral, belum ada sinyal
-							}
-
-						} else if shortReadinessScore >= 70 {
-							// Exhaustion zone - look for trigger
-							
-							// Check for BOS (Break of Structure)
-							hasBOS := false
-							if len(prices) >= 20 && len(lows) >= 20 {
-								lastIdx := len(prices) - 1
-								currentPrice := prices[lastIdx]
-								
-								// Find recent swing low (last 10-15 candles)
-								recentLow := lows[lastIdx-1]
-								for i := lastIdx - 15; i < lastIdx; i++ {
-									if i >= 0 && lows[i] < recentLow {
-										recentLow = lows[i]
-									}
-								}
-								
-								// BOS if price broke below recent higher low
-								if currentPrice < recentLow {
-									hasBOS = true
-								}
-							}
-
-							// Check for volume spike (climax)
-							hasVolumeSpike := false
-							if len(volumes) >= 21 {
-								lastIdx := len(volumes) - 1
-								currentVolume := volumes[lastIdx]
-								
-								sumVol := 0.0
-								for i := lastIdx - 20; i < lastIdx; i++ {
-									sumVol += volumes[i]
-								}
-								avgVol := sumVol / 20.0
-								
-								if currentVolume / avgVol > 2.0 {
-									hasVolumeSpike = true
-								}
-							}
-
-							// Assign status
-							if hasBOS && hasVolumeSpike {
-								coin.IntradayStatus = "HOT" // Execute short now!
-							} else if hasBOS {
-								coin.IntradayStatus = "READY" // BOS confirmed, watch for entry
-							} else {
-								coin.IntradayStatus = "WATCH" // Exhausted but no trigger yet
-							}
-
-						} else {
-							// 50-70 range: Waspada zone
-							coin.IntradayStatus = "WATCH" // Monitor closely
-						}
-					}
+		// Find highest scoring TF as primary
+		for _, ts := range tfScores {
+			if ts.TF == tf {
+				if primaryFeatures == nil || ts.Score > CalculateScoreWithOptions(primaryFeatures, settings.scoreOptions) {
+					primaryTF = tf
+					primaryFeatures = feat
+					currentPrice = pricesMap[tf]
 				}
 			}
+		}
+	}
 
-			// === PULLBACK ENTRY (Buy the Dip) ===
-			// Setup di 5m/15m (trend confirmation), eksekusi di 1m/3m (entry timing)
-			// Criteria: Uptrend + Pullback to support/EMA + Bounce signal
-			var pullbackTFScores []domain.TimeframeScore
-			var pullbackFeaturesMap = make(map[string]*domain.MarketFeatures)
-
-			// Analyze setup timeframes (5m, 15m) for trend
-			for _, tf := range pullbackSetupTFs {
-				rawKlines, err := uc.binanceClient.GetKlines(symbol, tf, 100)
-				if err != nil || len(rawKlines) < 50 {
-					continue
-				}
-
-				prices := make([]float64, len(rawKlines))
-				highs := make([]float64, len(rawKlines))
-				lows := make([]float64, len(rawKlines))
-				volumes := make([]float64, len(rawKlines))
-
-				for i, k := range rawKlines {
-					h, _ := parseValue(k[2])
-					l, _ := parseValue(k[3])
-					c, _ := parseValue(k[4])
-					v, _ := parseValue(k[5])
-					prices[i] = c
-					highs[i] = h
-					lows[i] = l
-					volumes[i] = v
-				}
-
-				ema20 := indicators.CalculateEMA(prices, 20)
-				ema50 := indicators.CalculateEMA(prices, 50)
-				rsi := indicators.CalculateRSI(prices, 14)
-				atr := indicators.CalculateATR(highs, lows, prices, 14)
-				bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
-				pivots := indicators.FindPivotLows(lows, 5, 2)
-
-				features := ExtractFeatures(
-					prices, highs, lows, volumes,
-					tickerMap[symbol],
-					ema50, make([]float64, len(prices)), rsi,
-					bb, atr, pivots,
-					funding, 0,
-				)
+	if primaryFeatures == nil {
+		return domain.CoinData{}, false
+	}
 
-				if features == nil {
-					continue
-				}
+	// === CONFLUENCE BONUS ===
+	// Base score is average of all TFs
+
+	// Confluence multiplier for 1m + 5m:
+	// 2 TFs aligned: x1.3 (TRIGGERED - ready for entry!)
+	// 1 TF aligned: x1.1 (WATCH)
+	// 0 TFs aligned: x1.0 (AVOID)
+	var confluenceMultiplier float64
+	switch confluenceCount {
+	case 2:
+		confluenceMultiplier = 1.3
+	case 1:
+		confluenceMultiplier = 1.1
+	default:
+		confluenceMultiplier = 1.0
+	}
 
-				// Calculate pullback score (different criteria)
-				pullbackScore := CalculatePullbackScore(prices, ema20, ema50, rsi, features)
-				pullbackTFScores = append(pullbackTFScores, domain.TimeframeScore{
-					TF:    tf,
-					Score: pullbackScore,
-					RSI:   features.RSI,
-				})
-				pullbackFeaturesMap[tf] = features
-			}
+	// Optional global context modifier (Fear & Greed), 1 when off
+	scoreMultiplier := confluenceMultiplier * env.shortMultiplier
+	finalScore := ReversalScore(tfScores, settings.scoreOptions.Weights, scoreMultiplier)
+
+	coin := domain.CoinData{
+		Symbol:             symbol,
+		Price:              currentPrice,
+		Score:              finalScore,
+		Status:             "",
+		TriggerTF:          primaryTF,
+		ConfluenceCount:    confluenceCount,
+		TFScores:           tfScores,
+		ScoreMultiplier:    scoreMultiplier,
+		TFFeatures:         tfFeatures,
+		PriceChangePercent: primaryFeatures.PctChange24h,
+		FundingRate:        funding,
+		Features:           primaryFeatures,
+		IntradayTFScores:   intradayTFScores,
+	}
 
-			// Analyze execution timeframes (1m, 3m) for entry timing
-			for _, tf := range pullbackExecTFs {
-				rawKlines, err := uc.binanceClient.GetKlines(symbol, tf, 100)
-				if err != nil || len(rawKlines) < 50 {
-					continue
-				}
+	stages.Start("strategy.intraday_status")
+	// === INTRADAY STATUS (15m + 1h) - SHORT ONLY ===
+	// Fokus mencari setup SHORT/SELL berdasarkan exhaustion signals
+	// Score 0-100: <50 = strong buy (jangan short), 50-70 = waspada, >70 = ready to short
+	if len(intradayTFScores) >= 2 {
+		var intradayPrimaryFeatures *domain.MarketFeatures
+		var primary15mFeatures *domain.MarketFeatures
+		var primary1hFeatures *domain.MarketFeatures
+
+		// Get features for both timeframes (15m and 1h by default)
+		feat15m, ok15m := intradayFeaturesMap[intradayTimeframes[0]]
+		feat1h, ok1h := intradayFeaturesMap[intradayTimeframes[1]]
+
+		if ok15m {
+			primary15mFeatures = feat15m
+		}
+		if ok1h {
+			primary1hFeatures = feat1h
+		}
 
-				prices := make([]float64, len(rawKlines))
-				highs := make([]float64, len(rawKlines))
-				lows := make([]float64, len(rawKlines))
-				volumes := make([]float64, len(rawKlines))
+		// Use the faster timeframe as primary (faster reaction)
+		if primary15mFeatures != nil {
+			intradayPrimaryFeatures = primary15mFeatures
+		} else if primary1hFeatures != nil {
+			intradayPrimaryFeatures = primary1hFeatures
+		}
 
-				for i, k := range rawKlines {
+		if intradayPrimaryFeatures != nil {
+			// Get klines for detailed analysis
+			rawKlines15m, err15m := env.klines(stages.Context(), symbol, intradayTimeframes[0], 100)
+			
+			if err15m == nil && len(rawKlines15m) >= 30 {
+				// Parse klines
+				prices := make([]float64, len(rawKlines15m))
+				highs := make([]float64, len(rawKlines15m))
+				lows := make([]float64, len(rawKlines15m))
+				volumes := make([]float64, len(rawKlines15m))
+
+				for i, k := range rawKlines15m {
 					h, _ := parseValue(k[2])
 					l, _ := parseValue(k[3])
 					c, _ := parseValue(k[4])
@@ -556,483 +642,698 @@ func (uc *ScreenerUsecase) process() {
 					volumes[i] = v
 				}
 
+				// Calculate EMAs and RSI for short readiness
 				ema20 := indicators.CalculateEMA(prices, 20)
 				ema50 := indicators.CalculateEMA(prices, 50)
 				rsi := indicators.CalculateRSI(prices, 14)
-				atr := indicators.CalculateATR(highs, lows, prices, 14)
-				bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
-				pivots := indicators.FindPivotLows(lows, 5, 2)
 
-				features := ExtractFeatures(
+				// Calculate SHORT READINESS SCORE (0-100)
+				shortReadinessScore := CalculateShortReadinessScore(
 					prices, highs, lows, volumes,
+					ema20, ema50, rsi,
+					intradayPrimaryFeatures,
 					tickerMap[symbol],
-					ema50, make([]float64, len(prices)), rsi,
-					bb, atr, pivots,
-					funding, 0,
 				)
 
-				if features == nil {
-					continue
-				}
+				coin.IntradayScore = shortReadinessScore
 
-				pullbackScore := CalculatePullbackScore(prices, ema20, ema50, rsi, features)
-				pullbackTFScores = append(pullbackTFScores, domain.TimeframeScore{
-					TF:    tf,
-					Score: pullbackScore,
-					RSI:   features.RSI,
-				})
-				pullbackFeaturesMap[tf] = features
-			}
+				// Determine status based on score and conditions
+				// <50 = STRONG_BUY (jangan short!)
+				// 50-70 = WATCH (waspada, cari trigger)
+				// >70 + BOS = READY (candidate short dengan trigger)
+				// >70 + BOS + volume spike = HOT (execute short!)
 
-			// Evaluate Pullback Setup
-			if len(pullbackTFScores) >= 2 {
-				var pullbackTotalScore float64
-				pullbackConfluence := 0
-				var pullbackPrimaryFeatures *domain.MarketFeatures
-
-				// Check setup TFs (5m, 15m) for uptrend confirmation
-				setupInUptrend := 0
-				for _, tf := range pullbackSetupTFs {
-					feat, ok := pullbackFeaturesMap[tf]
-					if !ok {
-						continue
-					}
-					// Uptrend: price above EMA, positive 24h change, RSI not extremely low
-					isUptrend := feat.OverExtEma > -0.02 && feat.PctChange24h > -2
-					isPullback := feat.RSI < 45 && feat.RSI > 20 // RSI pulled back but not crashed
+				if shortReadinessScore < 50 {
+					// Strong buy territory - DON'T SHORT
+					// Check if truly strong or just no exhaustion yet
+					hasStrongBuySignal := false
 					
-					if isUptrend && isPullback {
-						setupInUptrend++
+					// Struktur masih sehat: higher highs, rising regression channel,
+					// price above the midline but not stretched past the upper band
+					if channel := indicators.CalculateLinRegChannel(prices, settings.linregWindow, 2); channel.Midline > 0 {
+						lastIdx := len(prices) - 1
+						structure := indicators.AnalyzeMarketStructure(highs, lows, prices, 5, 2)
+						isHigherHighs := structure.Trend == "BULLISH" && structure.LastSwingLabel(true) == indicators.SwingHH
+						inRisingChannel := channel.SlopePct > 0 && prices[lastIdx] > channel.Midline && prices[lastIdx] <= channel.Upper
+						if isHigherHighs && inRisingChannel {
+							hasStrongBuySignal = true
+						}
 					}
-				}
 
-				// Check execution TFs (1m, 3m) for bounce/reversal signal
-				hasEntrySignal := false
-				for _, tf := range pullbackExecTFs {
-					feat, ok := pullbackFeaturesMap[tf]
-					if !ok {
-						continue
-					}
-					// Entry signal: RSI bouncing from oversold, near support
-					isBouncing := feat.RSI > 30 && feat.RSI < 50 // Coming out of oversold
-					nearSupport := feat.DistToSupportATR != nil && *feat.DistToSupportATR < 1.5
-					hasReversal := !feat.IsBreakdown && feat.RejectionWickRatio < 0.3 // No strong rejection
-
-					if isBouncing || nearSupport || hasReversal {
-						hasEntrySignal = true
-						pullbackConfluence++
+					if hasStrongBuySignal {
+						coin.IntradayStatus = "STRONG_BUY" // Roket masih punya bahan bakar
+					} else {
+						coin.IntradayStatus = "" // Neutral, belum ada sinyal
 					}
+
+				} else if shortReadinessScore >= 70 {
+					// Exhaustion zone - look for trigger
 					
-					if pullbackPrimaryFeatures == nil {
-						pullbackPrimaryFeatures = feat
+					// Check for BOS (Break of Structure)
+					hasBOS := hasRecentBearishBreak(highs, lows, prices)
+
+					// Check for volume spike (climax)
+					hasVolumeSpike := false
+					if len(volumes) >= 21 {
+						lastIdx := len(volumes) - 1
+						currentVolume := volumes[lastIdx]
+						
+						sumVol := 0.0
+						for i := lastIdx - 20; i < lastIdx; i++ {
+							sumVol += volumes[i]
+						}
+						avgVol := sumVol / 20.0
+						
+						if currentVolume / avgVol > 2.0 {
+							hasVolumeSpike = true
+						}
 					}
-				}
 
-				for _, ts := range pullbackTFScores {
-					pullbackTotalScore += ts.Score
-				}
+					// 1h Ichimoku trend filter: no HOT short against a bullish 1h cloud
+					htfBullish := primary1hFeatures != nil && primary1hFeatures.IchimokuTrend == "BULLISH"
 
-				pullbackAvgScore := pullbackTotalScore / float64(len(pullbackTFScores))
+					// Assign status
+					if hasBOS && hasVolumeSpike && !htfBullish {
+						coin.IntradayStatus = "HOT" // Execute short now!
+					} else if hasBOS {
+						coin.IntradayStatus = "READY" // BOS confirmed, watch for entry
+					} else {
+						coin.IntradayStatus = "WATCH" // Exhausted but no trigger yet
+					}
 
-				// Multiplier based on setup quality
-				var pullbackMultiplier float64
-				if setupInUptrend >= 2 && hasEntrySignal {
-					pullbackMultiplier = 1.4 // Strong setup
-					pullbackConfluence = 2
-				} else if setupInUptrend >= 1 && hasEntrySignal {
-					pullbackMultiplier = 1.2 // Decent setup
-					pullbackConfluence = 1
 				} else {
-					pullbackMultiplier = 1.0
-				}
-
-				coin.PullbackScore = pullbackAvgScore * pullbackMultiplier
-				if coin.PullbackScore > 100 {
-					coin.PullbackScore = 100
-				}
-				coin.PullbackTFScores = pullbackTFScores
-				coin.PullbackFeatures = pullbackPrimaryFeatures
-
-				// Pullback Status: DIP (ready to buy), BOUNCE (confirming), WAIT (watching)
-				if pullbackPrimaryFeatures != nil && setupInUptrend >= 1 {
-					if pullbackConfluence >= 2 && coin.PullbackScore >= 45 {
-						coin.PullbackStatus = "DIP" // Ready to buy the dip!
-					} else if pullbackConfluence >= 1 && coin.PullbackScore >= 35 {
-						coin.PullbackStatus = "BOUNCE" // Bounce starting
-					} else if coin.PullbackScore >= 30 {
-						coin.PullbackStatus = "WAIT" // Waiting for confirmation
-					}
+					// 50-70 range: Waspada zone
+					coin.IntradayStatus = "WATCH" // Monitor closely
 				}
 			}
+		}
+	}
 
-			// === BREAKOUT HUNTER (15m + 1h) with Volume Spike ===
-			// Detect both LONG (resistance breakout) and SHORT (support breakdown)
-			var breakoutTFScores []domain.TimeframeScore
-			var breakoutFeaturesMap = make(map[string]*domain.MarketFeatures)
-			var breakoutLowsMap = make(map[string][]float64) // For support levels
+	stages.Start("strategy.pullback")
+	// === PULLBACK ENTRY (Buy the Dip) ===
+	// Setup di 5m/15m (trend confirmation), eksekusi di 1m/3m (entry timing)
+	// Criteria: Uptrend + Pullback to support/EMA + Bounce signal
+	var pullbackTFScores []domain.TimeframeScore
+	var pullbackFeaturesMap = make(map[string]*domain.MarketFeatures)
+
+	// Analyze setup timeframes (5m, 15m) for trend
+	for _, tf := range pullbackSetupTFs {
+		rawKlines, err := env.klines(stages.Context(), symbol, tf, 100)
+		if err != nil || len(rawKlines) < 50 {
+			continue
+		}
 
-			for _, tf := range breakoutTimeframes {
-				rawKlines, err := uc.binanceClient.GetKlines(symbol, tf, 100)
-				if err != nil || len(rawKlines) < 50 {
-					continue
-				}
+		prices := make([]float64, len(rawKlines))
+		highs := make([]float64, len(rawKlines))
+		lows := make([]float64, len(rawKlines))
+		volumes := make([]float64, len(rawKlines))
+
+		for i, k := range rawKlines {
+			h, _ := parseValue(k[2])
+			l, _ := parseValue(k[3])
+			c, _ := parseValue(k[4])
+			v, _ := parseValue(k[5])
+			prices[i] = c
+			highs[i] = h
+			lows[i] = l
+			volumes[i] = v
+		}
 
-				prices := make([]float64, len(rawKlines))
-				highs := make([]float64, len(rawKlines))
-				lows := make([]float64, len(rawKlines))
-				volumes := make([]float64, len(rawKlines))
+		ema20 := indicators.CalculateEMA(prices, 20)
+		ema50 := indicators.CalculateEMA(prices, 50)
+		vwap := indicators.CalculateAnchoredVWAP(indicators.ParseKlineOpenTimes(rawKlines), highs, lows, prices, volumes, settings.vwapAnchor)
+		rsi := indicators.CalculateRSI(prices, 14)
+		atr := indicators.CalculateATR(highs, lows, prices, 14)
+		bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
+		pivots := indicators.FindPivotLows(lows, 5, 2)
+
+		features := ExtractFeatures(
+			prices, highs, lows, volumes,
+			tickerMap[symbol],
+			ema50, vwap, rsi,
+			bb, atr, pivots,
+			funding, oiDelta,
+		)
+
+		if features == nil {
+			continue
+		}
 
-				for i, k := range rawKlines {
-					h, _ := parseValue(k[2])
-					l, _ := parseValue(k[3])
-					c, _ := parseValue(k[4])
-					v, _ := parseValue(k[5])
-					prices[i] = c
-					highs[i] = h
-					lows[i] = l
-					volumes[i] = v
-				}
+		// Calculate pullback score (different criteria)
+		pullbackScore := CalculatePullbackScore(prices, uc.pullbackTrendLine(settings, prices, ema20, 20), uc.pullbackTrendLine(settings, prices, ema50, 50), rsi, features)
+		pullbackTFScores = append(pullbackTFScores, domain.TimeframeScore{
+			TF:    tf,
+			Score: pullbackScore,
+			RSI:   features.RSI,
+		})
+		pullbackFeaturesMap[tf] = features
+	}
 
-				ema20 := indicators.CalculateEMA(prices, 20)
-				ema50 := indicators.CalculateEMA(prices, 50)
-				rsi := indicators.CalculateRSI(prices, 14)
-				atr := indicators.CalculateATR(highs, lows, prices, 14)
-				bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
-				pivotsLow := indicators.FindPivotLows(lows, 5, 2) // For support
+	// Analyze execution timeframes (1m, 3m) for entry timing
+	for _, tf := range pullbackExecTFs {
+		rawKlines, err := env.klines(stages.Context(), symbol, tf, 100)
+		if err != nil || len(rawKlines) < 50 {
+			continue
+		}
 
-				features := ExtractFeatures(
-					prices, highs, lows, volumes,
-					tickerMap[symbol],
-					ema50, make([]float64, len(prices)), rsi,
-					bb, atr, pivotsLow,
-					funding, 0,
-				)
+		prices := make([]float64, len(rawKlines))
+		highs := make([]float64, len(rawKlines))
+		lows := make([]float64, len(rawKlines))
+		volumes := make([]float64, len(rawKlines))
+
+		for i, k := range rawKlines {
+			h, _ := parseValue(k[2])
+			l, _ := parseValue(k[3])
+			c, _ := parseValue(k[4])
+			v, _ := parseValue(k[5])
+			prices[i] = c
+			highs[i] = h
+			lows[i] = l
+			volumes[i] = v
+		}
 
-				if features == nil {
-					continue
-				}
+		ema20 := indicators.CalculateEMA(prices, 20)
+		ema50 := indicators.CalculateEMA(prices, 50)
+		vwap := indicators.CalculateAnchoredVWAP(indicators.ParseKlineOpenTimes(rawKlines), highs, lows, prices, volumes, settings.vwapAnchor)
+		rsi := indicators.CalculateRSI(prices, 14)
+		atr := indicators.CalculateATR(highs, lows, prices, 14)
+		bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
+		pivots := indicators.FindPivotLows(lows, 5, 2)
+
+		features := ExtractFeatures(
+			prices, highs, lows, volumes,
+			tickerMap[symbol],
+			ema50, vwap, rsi,
+			bb, atr, pivots,
+			funding, oiDelta,
+		)
+
+		if features == nil {
+			continue
+		}
 
-				// Calculate breakout/breakdown score
-				breakoutScoreLong := CalculateBreakoutScore(prices, highs, volumes, ema20, ema50, rsi, features, "LONG")
-				breakoutScoreShort := CalculateBreakoutScore(prices, lows, volumes, ema20, ema50, rsi, features, "SHORT")
-				
-				// Use the higher score
-				breakoutScore := breakoutScoreLong
-				if breakoutScoreShort > breakoutScoreLong {
-					breakoutScore = breakoutScoreShort
-				}
+		pullbackScore := CalculatePullbackScore(prices, uc.pullbackTrendLine(settings, prices, ema20, 20), uc.pullbackTrendLine(settings, prices, ema50, 50), rsi, features)
+		pullbackTFScores = append(pullbackTFScores, domain.TimeframeScore{
+			TF:    tf,
+			Score: pullbackScore,
+			RSI:   features.RSI,
+		})
+		pullbackFeaturesMap[tf] = features
+	}
 
-				breakoutTFScores = append(breakoutTFScores, domain.TimeframeScore{
-					TF:    tf,
-					Score: breakoutScore,
-					RSI:   features.RSI,
-				})
-				breakoutFeaturesMap[tf] = features
-				breakoutLowsMap[tf] = lows
+	// Evaluate Pullback Setup
+	if len(pullbackTFScores) >= 2 {
+		var pullbackTotalScore float64
+		pullbackConfluence := 0
+		var pullbackPrimaryFeatures *domain.MarketFeatures
+
+		// Check setup TFs (5m, 15m) for uptrend confirmation
+		setupInUptrend := 0
+		adxTrend := false // ADX on a setup TF confirms there is a trend to dip in
+		for _, tf := range pullbackSetupTFs {
+			feat, ok := pullbackFeaturesMap[tf]
+			if !ok {
+				continue
+			}
+			// Uptrend: price above EMA, positive 24h change, RSI not extremely low
+			isUptrend := feat.OverExtEma > -0.02 && feat.PctChange24h > -2
+			isPullback := feat.RSI < 45 && feat.RSI > 20 // RSI pulled back but not crashed
+			
+			if isUptrend && isPullback {
+				setupInUptrend++
 			}
+			if feat.ADX >= indicators.ADXWeak {
+				adxTrend = true
+			}
+		}
 
-			// Evaluate Breakout Setup (LONG and SHORT)
-			if len(breakoutTFScores) >= 2 {
-				var breakoutTotalScore float64
-				breakoutConfluence := 0
-				var breakoutPrimaryFeatures *domain.MarketFeatures
+		// Check execution TFs (1m, 3m) for bounce/reversal signal
+		hasEntrySignal := false
+		for _, tf := range pullbackExecTFs {
+			feat, ok := pullbackFeaturesMap[tf]
+			if !ok {
+				continue
+			}
+			// Entry signal: RSI bouncing from oversold, near support
+			isBouncing := feat.RSI > 30 && feat.RSI < 50 // Coming out of oversold
+			nearSupport := (feat.DistToSupportATR != nil && *feat.DistToSupportATR < 1.5) ||
+				(feat.DistToPocATR != nil && *feat.DistToPocATR >= 0 && *feat.DistToPocATR < 0.5)
+			hasReversal := !feat.IsBreakdown && feat.RejectionWickRatio < 0.3 // No strong rejection
+
+			if isBouncing || nearSupport || hasReversal {
+				hasEntrySignal = true
+				pullbackConfluence++
+			}
+			
+			if pullbackPrimaryFeatures == nil {
+				pullbackPrimaryFeatures = feat
+			}
+		}
 
-				// Check both TFs for LONG breakout signals
-				confirmedBreakoutsLong := 0
-				testingBreakoutsLong := 0
+		for _, ts := range pullbackTFScores {
+			pullbackTotalScore += ts.Score
+		}
 
-				// Check both TFs for SHORT breakdown signals  
-				confirmedBreakoutsShort := 0
-				testingBreakoutsShort := 0
+		// Bullish divergence on any pullback TF = sellers exhausted at the lows
+		hasBullishDiv := false
+		for _, feat := range pullbackFeaturesMap {
+			if feat.HasRsiBullishDiv {
+				hasBullishDiv = true
+				break
+			}
+		}
 
-				for _, tf := range breakoutTimeframes {
-					feat, ok := breakoutFeaturesMap[tf]
-					if !ok {
-						continue
-					}
+		pullbackAvgScore := pullbackTotalScore / float64(len(pullbackTFScores))
+
+		// Multiplier based on setup quality
+		var pullbackMultiplier float64
+		if setupInUptrend >= 2 && hasEntrySignal {
+			pullbackMultiplier = 1.4 // Strong setup
+			pullbackConfluence = 2
+		} else if setupInUptrend >= 1 && hasEntrySignal {
+			pullbackMultiplier = 1.2 // Decent setup
+			pullbackConfluence = 1
+		} else {
+			pullbackMultiplier = 1.0
+		}
 
-					// === LONG Breakout Criteria ===
-					// 1. Price breaking recent highs
-					// 2. Volume spike (>1.5x average)
-					// 3. RSI > 50 (bullish momentum)
-					// 4. Price above EMA20
-					
-					isBreakingOutLong := feat.OverExtEma > 0.01 && !feat.IsAboveUpperBand // Above EMA but not overextended
-					hasVolumeLong := feat.VolumeDeclineRatio < -0.3                        // Volume increasing
-					hasMomentumLong := feat.RSI > 50 && feat.RSI < 75                      // Strong but not overbought
-					
-					if isBreakingOutLong && hasVolumeLong && hasMomentumLong {
-						confirmedBreakoutsLong++
-					} else if (isBreakingOutLong && hasVolumeLong) || (isBreakingOutLong && hasMomentumLong) {
-						testingBreakoutsLong++
-					}
+		coin.PullbackScore = pullbackAvgScore * pullbackMultiplier * env.longMultiplier
+		if coin.PullbackScore > 100 {
+			coin.PullbackScore = 100
+		}
+		coin.PullbackTFScores = pullbackTFScores
+		coin.PullbackFeatures = pullbackPrimaryFeatures
+
+		// Pullback Status: DIP (ready to buy), BOUNCE (confirming), WAIT (watching)
+		// A DIP needs ADX on a setup TF to confirm there is a trend to buy into
+		if pullbackPrimaryFeatures != nil && setupInUptrend >= 1 {
+			if adxTrend && ((pullbackConfluence >= 2 && coin.PullbackScore >= 45) ||
+				(hasBullishDiv && pullbackConfluence >= 1 && coin.PullbackScore >= 40)) {
+				coin.PullbackStatus = "DIP" // Ready to buy the dip!
+			} else if pullbackConfluence >= 1 && coin.PullbackScore >= 35 {
+				coin.PullbackStatus = "BOUNCE" // Bounce starting
+			} else if coin.PullbackScore >= 30 {
+				coin.PullbackStatus = "WAIT" // Waiting for confirmation
+			}
+		}
+	}
 
-					// === SHORT Breakdown Criteria ===
-					// 1. Price breaking recent lows (support)
-					// 2. Volume spike (>1.5x average)
-					// 3. RSI < 50 (bearish momentum)
-					// 4. Price below EMA20
-					
-					isBreakingDownShort := feat.OverExtEma < -0.01 // Below EMA
-					hasVolumeShort := feat.VolumeDeclineRatio < -0.3 // Volume increasing
-					hasMomentumShort := feat.RSI < 50 && feat.RSI > 25 // Bearish but not oversold yet
-					
-					if isBreakingDownShort && hasVolumeShort && hasMomentumShort {
-						confirmedBreakoutsShort++
-					} else if (isBreakingDownShort && hasVolumeShort) || (isBreakingDownShort && hasMomentumShort) {
-						testingBreakoutsShort++
-					}
+	stages.Start("strategy.breakout")
+	// === BREAKOUT HUNTER (15m + 1h) with Volume Spike ===
+	// Detect both LONG (resistance breakout) and SHORT (support breakdown)
+	var breakoutTFScores []domain.TimeframeScore
+	var breakoutFeaturesMap = make(map[string]*domain.MarketFeatures)
+	var breakoutLowsMap = make(map[string][]float64) // For support levels
+
+	for _, tf := range breakoutTimeframes {
+		rawKlines, err := env.klines(stages.Context(), symbol, tf, 100)
+		if err != nil || len(rawKlines) < 50 {
+			continue
+		}
 
-					if breakoutPrimaryFeatures == nil {
-						breakoutPrimaryFeatures = feat
-					}
-				}
+		prices := make([]float64, len(rawKlines))
+		highs := make([]float64, len(rawKlines))
+		lows := make([]float64, len(rawKlines))
+		volumes := make([]float64, len(rawKlines))
+
+		for i, k := range rawKlines {
+			h, _ := parseValue(k[2])
+			l, _ := parseValue(k[3])
+			c, _ := parseValue(k[4])
+			v, _ := parseValue(k[5])
+			prices[i] = c
+			highs[i] = h
+			lows[i] = l
+			volumes[i] = v
+		}
 
-				// Determine direction and status
-				var breakoutDirection string
-				var confirmedBreakouts int
-				var testingBreakouts int
-
-				// Prioritize the stronger signal
-				if confirmedBreakoutsLong >= confirmedBreakoutsShort && (confirmedBreakoutsLong > 0 || testingBreakoutsLong > testingBreakoutsShort) {
-					breakoutDirection = "LONG"
-					confirmedBreakouts = confirmedBreakoutsLong
-					testingBreakouts = testingBreakoutsLong
-					breakoutConfluence = confirmedBreakoutsLong
-					if testingBreakoutsLong > 0 && breakoutConfluence == 0 {
-						breakoutConfluence = 1
-					}
-				} else if confirmedBreakoutsShort > 0 || testingBreakoutsShort > 0 {
-					breakoutDirection = "SHORT"
-					confirmedBreakouts = confirmedBreakoutsShort
-					testingBreakouts = testingBreakoutsShort
-					breakoutConfluence = confirmedBreakoutsShort
-					if testingBreakoutsShort > 0 && breakoutConfluence == 0 {
-						breakoutConfluence = 1
-					}
-				}
+		ema20 := indicators.CalculateEMA(prices, 20)
+		ema50 := indicators.CalculateEMA(prices, 50)
+		vwap := indicators.CalculateAnchoredVWAP(indicators.ParseKlineOpenTimes(rawKlines), highs, lows, prices, volumes, settings.vwapAnchor)
+		rsi := indicators.CalculateRSI(prices, 14)
+		atr := indicators.CalculateATR(highs, lows, prices, 14)
+		bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
+		pivotsLow := indicators.FindPivotLows(lows, 5, 2) // For support
+
+		features := ExtractFeatures(
+			prices, highs, lows, volumes,
+			tickerMap[symbol],
+			ema50, vwap, rsi,
+			bb, atr, pivotsLow,
+			funding, oiDelta,
+		)
+
+		if features == nil {
+			continue
+		}
 
-				for _, ts := range breakoutTFScores {
-					breakoutTotalScore += ts.Score
-				}
+		applyTakerFlow(features, rawKlines, highs)
 
-				breakoutAvgScore := breakoutTotalScore / float64(len(breakoutTFScores))
+		// Calculate breakout/breakdown score
+		breakoutScoreLong := CalculateBreakoutScore(prices, highs, volumes, ema20, ema50, rsi, features, "LONG")
+		breakoutScoreShort := CalculateBreakoutScore(prices, lows, volumes, ema20, ema50, rsi, features, "SHORT")
+		
+		// Use the higher score
+		breakoutScore := breakoutScoreLong
+		if breakoutScoreShort > breakoutScoreLong {
+			breakoutScore = breakoutScoreShort
+		}
 
-				// Multiplier based on confirmation
-				var breakoutMultiplier float64
-				if confirmedBreakouts >= 2 {
-					breakoutMultiplier = 1.5 // Strong breakout confirmed on both TFs
-				} else if confirmedBreakouts >= 1 || testingBreakouts >= 2 {
-					breakoutMultiplier = 1.2 // Decent breakout
-				} else {
-					breakoutMultiplier = 1.0
-				}
+		breakoutTFScores = append(breakoutTFScores, domain.TimeframeScore{
+			TF:    tf,
+			Score: breakoutScore,
+			RSI:   features.RSI,
+		})
+		breakoutFeaturesMap[tf] = features
+		breakoutLowsMap[tf] = lows
+	}
 
-				coin.BreakoutScore = breakoutAvgScore * breakoutMultiplier
-				if coin.BreakoutScore > 100 {
-					coin.BreakoutScore = 100
-				}
-				coin.BreakoutTFScores = breakoutTFScores
-				coin.BreakoutFeatures = breakoutPrimaryFeatures
-				coin.BreakoutDirection = breakoutDirection
-
-				// Breakout Status with direction
-				if breakoutPrimaryFeatures != nil && breakoutDirection != "" {
-					if confirmedBreakouts >= 2 && coin.BreakoutScore >= 50 {
-						coin.BreakoutStatus = "BREAKOUT_" + breakoutDirection // "BREAKOUT_LONG" or "BREAKOUT_SHORT"
-					} else if confirmedBreakouts >= 1 && coin.BreakoutScore >= 40 {
-						coin.BreakoutStatus = "TESTING_" + breakoutDirection // "TESTING_LONG" or "TESTING_SHORT"
-					} else if testingBreakouts >= 1 && coin.BreakoutScore >= 30 {
-						coin.BreakoutStatus = "WAIT_" + breakoutDirection // "WAIT_LONG" or "WAIT_SHORT"
-					}
-				}
+	// Evaluate Breakout Setup (LONG and SHORT)
+	if len(breakoutTFScores) >= 2 {
+		var breakoutTotalScore float64
+		breakoutConfluence := 0
+		var breakoutPrimaryFeatures *domain.MarketFeatures
+
+		// Check both TFs for LONG breakout signals
+		confirmedBreakoutsLong := 0
+		testingBreakoutsLong := 0
+
+		// Check both TFs for SHORT breakdown signals  
+		confirmedBreakoutsShort := 0
+		testingBreakoutsShort := 0
+
+		for _, tf := range breakoutTimeframes {
+			feat, ok := breakoutFeaturesMap[tf]
+			if !ok {
+				continue
 			}
 
-			// === FOLLOW TREND ANALYSIS (15m + 1h) ===
-			// Detect strong trending coins (both LONG and SHORT)
-			// LONG: EMA alignment (20>50), strong momentum, sustained volume
-			// SHORT: EMA alignment (20<50), strong bearish momentum, sustained volume
-			var followTrendTFScores []domain.TimeframeScore
-			var followTrendFeaturesMap = make(map[string]*domain.MarketFeatures)
+			// === LONG Breakout Criteria ===
+			// 1. Price breaking recent highs
+			// 2. Volume spike (>1.5x average)
+			// 3. RSI > 50 (bullish momentum)
+			// 4. Price above EMA20
 			
-			trendTimeframes := []string{"15m", "1h"}
+			isBreakingOutLong := feat.OverExtEma > 0.01 && !feat.IsAboveUpperBand // Above EMA but not overextended
+			hasVolumeLong := feat.VolumeDeclineRatio < -0.3                        // Volume increasing
+			hasMomentumLong := feat.RSI > 50 && feat.RSI < 75                      // Strong but not overbought
 			
-			for _, tf := range trendTimeframes {
-				rawKlines, err := uc.binanceClient.GetKlines(symbol, tf, 100)
-				if err != nil || len(rawKlines) < 50 {
-					continue
-				}
+			if isBreakingOutLong && hasVolumeLong && hasMomentumLong {
+				confirmedBreakoutsLong++
+			} else if (isBreakingOutLong && hasVolumeLong) || (isBreakingOutLong && hasMomentumLong) {
+				testingBreakoutsLong++
+			}
 
-				prices := make([]float64, len(rawKlines))
-				highs := make([]float64, len(rawKlines))
-				lows := make([]float64, len(rawKlines))
-				volumes := make([]float64, len(rawKlines))
+			// === SHORT Breakdown Criteria ===
+			// 1. Price breaking recent lows (support)
+			// 2. Volume spike (>1.5x average)
+			// 3. RSI < 50 (bearish momentum)
+			// 4. Price below EMA20
+			
+			isBreakingDownShort := feat.OverExtEma < -0.01 // Below EMA
+			hasVolumeShort := feat.VolumeDeclineRatio < -0.3 // Volume increasing
+			hasMomentumShort := feat.RSI < 50 && feat.RSI > 25 // Bearish but not oversold yet
+			
+			if isBreakingDownShort && hasVolumeShort && hasMomentumShort {
+				confirmedBreakoutsShort++
+			} else if (isBreakingDownShort && hasVolumeShort) || (isBreakingDownShort && hasMomentumShort) {
+				testingBreakoutsShort++
+			}
 
-				for i, k := range rawKlines {
-					h, _ := parseValue(k[2])
-					l, _ := parseValue(k[3])
-					c, _ := parseValue(k[4])
-					v, _ := parseValue(k[5])
-					prices[i] = c
-					highs[i] = h
-					lows[i] = l
-					volumes[i] = v
-				}
+			if breakoutPrimaryFeatures == nil {
+				breakoutPrimaryFeatures = feat
+			}
+		}
 
-				ema20 := indicators.CalculateEMA(prices, 20)
-				ema50 := indicators.CalculateEMA(prices, 50)
-				rsi := indicators.CalculateRSI(prices, 14)
-				atr := indicators.CalculateATR(highs, lows, prices, 14)
-				bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
-				pivotsLow := indicators.FindPivotLows(lows, 5, 2)
+		// Determine direction and status
+		var breakoutDirection string
+		var confirmedBreakouts int
+		var testingBreakouts int
+
+		// Prioritize the stronger signal
+		if confirmedBreakoutsLong >= confirmedBreakoutsShort && (confirmedBreakoutsLong > 0 || testingBreakoutsLong > testingBreakoutsShort) {
+			breakoutDirection = "LONG"
+			confirmedBreakouts = confirmedBreakoutsLong
+			testingBreakouts = testingBreakoutsLong
+			breakoutConfluence = confirmedBreakoutsLong
+			if testingBreakoutsLong > 0 && breakoutConfluence == 0 {
+				breakoutConfluence = 1
+			}
+		} else if confirmedBreakoutsShort > 0 || testingBreakoutsShort > 0 {
+			breakoutDirection = "SHORT"
+			confirmedBreakouts = confirmedBreakoutsShort
+			testingBreakouts = testingBreakoutsShort
+			breakoutConfluence = confirmedBreakoutsShort
+			if testingBreakoutsShort > 0 && breakoutConfluence == 0 {
+				breakoutConfluence = 1
+			}
+		}
 
-				features := ExtractFeatures(
-					prices, highs, lows, volumes,
-					tickerMap[symbol],
-					ema50, make([]float64, len(prices)), rsi,
-					bb, atr, pivotsLow,
-					funding, 0,
-				)
+		for _, ts := range breakoutTFScores {
+			breakoutTotalScore += ts.Score
+		}
 
-				if features == nil {
-					continue
-				}
+		breakoutAvgScore := breakoutTotalScore / float64(len(breakoutTFScores))
 
-				// Calculate trend strength score
-				trendScore := CalculateFollowTrendScore(prices, volumes, ema20, ema50, rsi, features)
+		// Multiplier based on confirmation
+		var breakoutMultiplier float64
+		if confirmedBreakouts >= 2 {
+			breakoutMultiplier = 1.5 // Strong breakout confirmed on both TFs
+		} else if confirmedBreakouts >= 1 || testingBreakouts >= 2 {
+			breakoutMultiplier = 1.2 // Decent breakout
+		} else {
+			breakoutMultiplier = 1.0
+		}
 
-				followTrendTFScores = append(followTrendTFScores, domain.TimeframeScore{
-					TF:    tf,
-					Score: trendScore,
-					RSI:   features.RSI,
-				})
-				followTrendFeaturesMap[tf] = features
-			}
+		// 1h Ichimoku trend filter: breakouts against the cloud of the slower
+		// timeframe are discounted
+		htfAgainst := false
+		if feat1h, ok := breakoutFeaturesMap[breakoutTimeframes[1]]; ok {
+			htfAgainst = (breakoutDirection == "LONG" && feat1h.CloudPosition == "BELOW") ||
+				(breakoutDirection == "SHORT" && feat1h.CloudPosition == "ABOVE")
+		}
+		if htfAgainst {
+			breakoutMultiplier *= 0.8
+		}
 
-			// Evaluate Follow Trend Setup
-			if len(followTrendTFScores) >= 2 {
-				var trendTotalScore float64
-				var trendPrimaryFeatures *domain.MarketFeatures
-				
-				// Check EMA alignment consistency across timeframes
-				emaAlignedLong := 0
-				emaAlignedShort := 0
-				hasStrongVolume := 0
-				
-				for _, tf := range trendTimeframes {
-					feat, ok := followTrendFeaturesMap[tf]
-					if !ok {
-						continue
-					}
-					
-					// LONG trend: Price > EMA20 > EMA50, RSI > 50
-					if feat.OverExtEma > 0 && feat.RSI > 50 && feat.RSI < 80 {
-						emaAlignedLong++
-					}
-					
-					// SHORT trend: Price < EMA20 < EMA50, RSI < 50
-					if feat.OverExtEma < 0 && feat.RSI < 50 && feat.RSI > 20 {
-						emaAlignedShort++
-					}
-					
-					// Volume confirmation
-					if feat.VolumeDeclineRatio < -0.2 {
-						hasStrongVolume++
-					}
-					
-					if trendPrimaryFeatures == nil {
-						trendPrimaryFeatures = feat
-					}
-				}
-				
-				// Calculate average score
-				for _, ts := range followTrendTFScores {
-					trendTotalScore += ts.Score
-				}
-				trendAvgScore := trendTotalScore / float64(len(followTrendTFScores))
-				
-				// Determine direction and apply multipliers
-				var trendDirection string
-				var trendMultiplier float64 = 1.0
-				
-				if emaAlignedLong >= 2 {
-					trendDirection = "LONG"
-					trendMultiplier = 1.3
-					if hasStrongVolume >= 2 {
-						trendMultiplier = 1.5
-					}
-				} else if emaAlignedShort >= 2 {
-					trendDirection = "SHORT"
-					trendMultiplier = 1.3
-					if hasStrongVolume >= 2 {
-						trendMultiplier = 1.5
-					}
-				} else if emaAlignedLong >= 1 || emaAlignedShort >= 1 {
-					// Partial alignment
-					if emaAlignedLong > emaAlignedShort {
-						trendDirection = "LONG"
-					} else {
-						trendDirection = "SHORT"
-					}
-					trendMultiplier = 1.1
-				}
-				
-				coin.FollowTrendScore = trendAvgScore * trendMultiplier
-				if coin.FollowTrendScore > 100 {
-					coin.FollowTrendScore = 100
-				}
-				coin.FollowTrendTFScores = followTrendTFScores
-				coin.FollowTrendFeatures = trendPrimaryFeatures
-				coin.FollowTrendDirection = trendDirection
-				
-				// Set status based on trend strength
-				if trendDirection != "" && coin.FollowTrendScore >= 60 {
-					if hasStrongVolume >= 2 && (emaAlignedLong >= 2 || emaAlignedShort >= 2) {
-						coin.FollowTrendStatus = "HOT" // Strong trend with volume
-					} else if emaAlignedLong >= 2 || emaAlignedShort >= 2 {
-						coin.FollowTrendStatus = "STRONG" // Strong trend
-					} else {
-						coin.FollowTrendStatus = "MODERATE" // Moderate trend
-					}
-				}
+		coin.BreakoutScore = breakoutAvgScore * breakoutMultiplier
+		if coin.BreakoutScore > 100 {
+			coin.BreakoutScore = 100
+		}
+		coin.BreakoutTFScores = breakoutTFScores
+		coin.BreakoutFeatures = breakoutPrimaryFeatures
+		coin.BreakoutDirection = breakoutDirection
+
+		// Volatility squeeze on either TF: coiling before expansion
+		for _, tf := range breakoutTimeframes {
+			feat, ok := breakoutFeaturesMap[tf]
+			if !ok {
+				continue
+			}
+			if feat.SqueezeFired {
+				coin.BreakoutSqueeze = "FIRED"
+			} else if feat.IsSqueeze && feat.SqueezeBars >= 6 && coin.BreakoutSqueeze == "" {
+				coin.BreakoutSqueeze = "COILING"
 			}
+		}
 
-			// Determine Status based on 1m + 5m confluence
-			// TRIGGER: both 1m and 5m aligned (confluence = 2) - ready for entry!
-			// SETUP: 1 TF aligned with decent score - preparing
-			// WATCH: decent score but weak alignment
-			// (no status = not displayed)
-			if confluenceCount >= 2 && finalScore >= 40 {
-				coin.Status = "TRIGGER"
-			} else if confluenceCount >= 1 && finalScore >= 35 {
-				coin.Status = "SETUP"
-			} else if finalScore >= 30 {
-				coin.Status = "WATCH"
+		// Breakout Status with direction
+		if breakoutPrimaryFeatures != nil && breakoutDirection != "" {
+			if confirmedBreakouts >= 2 && coin.BreakoutScore >= 50 && !htfAgainst && !adxChop(breakoutPrimaryFeatures) {
+				coin.BreakoutStatus = "BREAKOUT_" + breakoutDirection // "BREAKOUT_LONG" or "BREAKOUT_SHORT"
+			} else if confirmedBreakouts >= 1 && coin.BreakoutScore >= 40 {
+				coin.BreakoutStatus = "TESTING_" + breakoutDirection // "TESTING_LONG" or "TESTING_SHORT"
+			} else if testingBreakouts >= 1 && coin.BreakoutScore >= 30 {
+				coin.BreakoutStatus = "WAIT_" + breakoutDirection // "WAIT_LONG" or "WAIT_SHORT"
 			}
+		}
+		if coin.BreakoutStatus == "" && coin.BreakoutSqueeze == "COILING" {
+			coin.BreakoutStatus = "SQUEEZE" // No direction yet, volatility compressed
+		}
+	}
 
-			mu.Lock()
-			computedCoins = append(computedCoins, coin)
-			mu.Unlock()
+	stages.Start("strategy.follow_trend")
+	// === FOLLOW TREND ANALYSIS (15m + 1h) ===
+	// Detect strong trending coins (both LONG and SHORT)
+	// LONG: EMA alignment (20>50), strong momentum, sustained volume
+	// SHORT: EMA alignment (20<50), strong bearish momentum, sustained volume
+	var followTrendTFScores []domain.TimeframeScore
+	var followTrendFeaturesMap = make(map[string]*domain.MarketFeatures)
+	
+	for _, tf := range trendTimeframes {
+		rawKlines, err := env.klines(stages.Context(), symbol, tf, 100)
+		if err != nil || len(rawKlines) < 50 {
+			continue
+		}
 
-		}(sym)
+		prices := make([]float64, len(rawKlines))
+		highs := make([]float64, len(rawKlines))
+		lows := make([]float64, len(rawKlines))
+		volumes := make([]float64, len(rawKlines))
+
+		for i, k := range rawKlines {
+			h, _ := parseValue(k[2])
+			l, _ := parseValue(k[3])
+			c, _ := parseValue(k[4])
+			v, _ := parseValue(k[5])
+			prices[i] = c
+			highs[i] = h
+			lows[i] = l
+			volumes[i] = v
+		}
+
+		ema20 := indicators.CalculateEMA(prices, 20)
+		ema50 := indicators.CalculateEMA(prices, 50)
+		vwap := indicators.CalculateAnchoredVWAP(indicators.ParseKlineOpenTimes(rawKlines), highs, lows, prices, volumes, settings.vwapAnchor)
+		rsi := indicators.CalculateRSI(prices, 14)
+		atr := indicators.CalculateATR(highs, lows, prices, 14)
+		bb := indicators.CalculateBollingerBands(prices, 20, 2.0)
+		pivotsLow := indicators.FindPivotLows(lows, 5, 2)
+
+		features := ExtractFeatures(
+			prices, highs, lows, volumes,
+			tickerMap[symbol],
+			ema50, vwap, rsi,
+			bb, atr, pivotsLow,
+			funding, oiDelta,
+		)
+
+		if features == nil {
+			continue
+		}
+
+		// Calculate trend strength score
+		trendScore := CalculateFollowTrendScore(prices, volumes, ema20, ema50, rsi, features)
+
+		followTrendTFScores = append(followTrendTFScores, domain.TimeframeScore{
+			TF:    tf,
+			Score: trendScore,
+			RSI:   features.RSI,
+		})
+		followTrendFeaturesMap[tf] = features
 	}
 
-	wg.Wait()
-	
-	// Sort coins by score (highest first)
-	sort.Slice(computedCoins, func(i, j int) bool {
-		return computedCoins[i].Score > computedCoins[j].Score
-	})
-	
-	uc.repo.SaveCoins(computedCoins)
-	
-	// Send FCM notifications for TRIGGER coins
-	uc.sendNotificationsForTriggers(computedCoins)
-	
-	// Send FCM notifications for BREAKOUT coins
-	uc.sendNotificationsForBreakouts(computedCoins)
-	
-	log.Printf("Cycle completed in %v. Processed %d coins.", time.Since(start), len(computedCoins))
+	// Evaluate Follow Trend Setup
+	if len(followTrendTFScores) >= 2 {
+		var trendTotalScore float64
+		var trendPrimaryFeatures *domain.MarketFeatures
+		
+		// Check EMA alignment consistency across timeframes
+		emaAlignedLong := 0
+		emaAlignedShort := 0
+		hasStrongVolume := 0
+		
+		for _, tf := range trendTimeframes {
+			feat, ok := followTrendFeaturesMap[tf]
+			if !ok {
+				continue
+			}
+			
+			// LONG trend: Price > EMA20 > EMA50, RSI > 50
+			if feat.OverExtEma > 0 && feat.RSI > 50 && feat.RSI < 80 {
+				emaAlignedLong++
+			}
+			
+			// SHORT trend: Price < EMA20 < EMA50, RSI < 50
+			if feat.OverExtEma < 0 && feat.RSI < 50 && feat.RSI > 20 {
+				emaAlignedShort++
+			}
+			
+			// Volume confirmation
+			if feat.VolumeDeclineRatio < -0.2 {
+				hasStrongVolume++
+			}
+			
+			if trendPrimaryFeatures == nil {
+				trendPrimaryFeatures = feat
+			}
+		}
+		
+		// Calculate average score
+		for _, ts := range followTrendTFScores {
+			trendTotalScore += ts.Score
+		}
+		trendAvgScore := trendTotalScore / float64(len(followTrendTFScores))
+		
+		// Determine direction and apply multipliers
+		var trendDirection string
+		var trendMultiplier float64 = 1.0
+		
+		if emaAlignedLong >= 2 {
+			trendDirection = "LONG"
+			trendMultiplier = 1.3
+			if hasStrongVolume >= 2 {
+				trendMultiplier = 1.5
+			}
+		} else if emaAlignedShort >= 2 {
+			trendDirection = "SHORT"
+			trendMultiplier = 1.3
+			if hasStrongVolume >= 2 {
+				trendMultiplier = 1.5
+			}
+		} else if emaAlignedLong >= 1 || emaAlignedShort >= 1 {
+			// Partial alignment
+			if emaAlignedLong > emaAlignedShort {
+				trendDirection = "LONG"
+			} else {
+				trendDirection = "SHORT"
+			}
+			trendMultiplier = 1.1
+		}
+		
+		coin.FollowTrendScore = trendAvgScore * trendMultiplier
+		if coin.FollowTrendScore > 100 {
+			coin.FollowTrendScore = 100
+		}
+		coin.FollowTrendTFScores = followTrendTFScores
+		coin.FollowTrendFeatures = trendPrimaryFeatures
+		coin.FollowTrendDirection = trendDirection
+		
+		// Set status based on trend strength
+		if trendDirection != "" && coin.FollowTrendScore >= 60 {
+			if hasStrongVolume >= 2 && (emaAlignedLong >= 2 || emaAlignedShort >= 2) {
+				coin.FollowTrendStatus = "HOT" // Strong trend with volume
+			} else if emaAlignedLong >= 2 || emaAlignedShort >= 2 {
+				coin.FollowTrendStatus = "STRONG" // Strong trend
+			} else {
+				coin.FollowTrendStatus = "MODERATE" // Moderate trend
+			}
+		}
+	}
+
+	// Determine Status based on 1m + 5m confluence
+	// TRIGGER: both 1m and 5m aligned (confluence = 2) and score >= screener.triggerScore - ready for entry!
+	// SETUP: 1 TF aligned with decent score - preparing
+	// WATCH: decent score but weak alignment
+	// (no status = not displayed, nor when scalping is disabled)
+	// Dead market (ATR in its bottom quartile): a scalp has no room to pay, WATCH at most
+	deadMarket := primaryFeatures.VolatilityRegime == indicators.VolRegimeLow
+	if !strategies.Scalp.Enabled {
+		coin.Status = ""
+	} else {
+		coin.Status = reversalStatus(confluenceCount, finalScore, settings.scoreOptions.TriggerScore, deadMarket)
+	}
+	env.regime.Apply(&coin, settings.scoreOptions.TriggerScore)
+
+	stages.End()
+	logging.Symbolf(symbol, "score %.1f status=%q confluence=%d | intraday=%q pullback=%q breakout=%q trend=%q",
+		coin.Score, coin.Status, coin.ConfluenceCount, coin.IntradayStatus, coin.PullbackStatus, coin.BreakoutStatus, coin.FollowTrendStatus)
+
+	return coin, true
+}
+
+// reversalStatus is the reversal status a coin earns with score on
+// confluence aligned timeframes, "" for none
+func reversalStatus(confluence int, score, triggerScore float64, deadMarket bool) string {
+	switch {
+	case confluence >= 2 && score >= triggerScore && !deadMarket:
+		return "TRIGGER"
+	case confluence >= 1 && score >= 35 && !deadMarket:
+		return "SETUP"
+	case score >= 30:
+		return "WATCH"
+	}
+	return ""
 }
 
 func parseValue(v interface{}) (float64, error) {
@@ -1044,3 +1345,122 @@ func parseValue(v interface{}) (float64, error) {
 	}
 	return 0, nil
 }
+
+// getKlines fetches klines and archives the candles that closed since the
+// last fetch of the same symbol/interval.
+func (uc *ScreenerUsecase) getKlines(ctx context.Context, symbol, interval string, limit int) ([][]interface{}, error) {
+	klines, err := uc.binanceClient.GetKlines(ctx, symbol, interval, limit)
+	if err != nil {
+		logging.Symbolf(symbol, "%s klines failed: %v", interval, err)
+	}
+	if err != nil || uc.archive == nil {
+		return klines, err
+	}
+
+	key := symbol + "|" + interval
+	uc.mu.RLock()
+	last := uc.archivedUntil[key]
+	uc.mu.RUnlock()
+
+	var fresh []domain.Candle
+	for _, c := range binance.ClosedCandles(klines, time.Now()) {
+		if c.OpenTime.After(last) {
+			fresh = append(fresh, c)
+		}
+	}
+	if len(fresh) == 0 {
+		return klines, nil
+	}
+	if err := uc.archive.SaveCandles(ctx, symbol, interval, fresh); err != nil {
+		logging.Warnf("Archive: saving %s %s candles failed: %v", symbol, interval, err)
+		return klines, nil
+	}
+
+	uc.mu.Lock()
+	uc.archivedUntil[key] = fresh[len(fresh)-1].OpenTime
+	uc.mu.Unlock()
+	return klines, nil
+}
+
+// recordSignals stores the signals that started this cycle, for outcome
+// labeling. A coin staying in a signal state is recorded once; after a
+// restart or a change of leader the ongoing signals are recorded again.
+func (uc *ScreenerUsecase) recordSignals(ctx context.Context, at time.Time, coins []domain.CoinData) {
+	active := make(map[string]bool, len(uc.activeSignals))
+	recorded := 0
+	for i := range coins {
+		for _, sig := range coinSignals(&coins[i], at) {
+			key := sig.Strategy + "|" + sig.Symbol
+			active[key] = true
+			if uc.activeSignals[key] {
+				continue
+			}
+			if err := uc.signals.SaveSignal(ctx, sig); err != nil {
+				logging.Warnf("Recording %s signal of %s failed: %v", sig.Strategy, sig.Symbol, err)
+				continue
+			}
+			recorded++
+		}
+	}
+	uc.activeSignals = active
+	if recorded > 0 {
+		logging.Debugf("Recorded %d new signals", recorded)
+	}
+}
+
+// archiveSnapshots stores this cycle's coins that have any signal status
+func (uc *ScreenerUsecase) archiveSnapshots(ctx context.Context, takenAt time.Time, coins []domain.CoinData) {
+	if uc.archive == nil {
+		return
+	}
+	var signalled []domain.CoinData
+	for _, c := range coins {
+		if c.Status != "" || c.IntradayStatus != "" || c.PullbackStatus != "" || c.BreakoutStatus != "" || c.FollowTrendStatus != "" {
+			signalled = append(signalled, c)
+		}
+	}
+	if err := uc.archive.SaveSnapshots(ctx, takenAt.UTC().Truncate(time.Second), signalled); err != nil {
+		logging.Warnf("Archive: saving snapshots failed: %v", err)
+	}
+}
+
+// recordScoreHistory stores the scores of this cycle's top coins, ranked by
+// their best score over the strategies
+func (uc *ScreenerUsecase) recordScoreHistory(ctx context.Context, takenAt time.Time, coins []domain.CoinData, topN int) {
+	if uc.archive == nil || topN <= 0 || len(coins) == 0 {
+		return
+	}
+	best := func(c *domain.CoinData) float64 {
+		return math.Max(math.Max(c.Score, c.IntradayScore), math.Max(math.Max(c.PullbackScore, c.BreakoutScore), c.FollowTrendScore))
+	}
+	ranked := make([]*domain.CoinData, len(coins))
+	for i := range coins {
+		ranked[i] = &coins[i]
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return best(ranked[i]) > best(ranked[j]) })
+
+	takenAt = takenAt.UTC().Truncate(time.Second)
+	snapshots := make([]domain.ScreenerSnapshot, 0, min(topN, len(ranked)))
+	for i, c := range ranked[:min(topN, len(ranked))] {
+		snapshots = append(snapshots, domain.ScreenerSnapshot{
+			TakenAt: takenAt, Symbol: c.Symbol, Rank: i + 1, Price: c.Price,
+			Score: c.Score, Status: c.Status,
+			IntradayScore: c.IntradayScore, IntradayStatus: c.IntradayStatus,
+			PullbackScore: c.PullbackScore, PullbackStatus: c.PullbackStatus,
+			BreakoutScore: c.BreakoutScore, BreakoutStatus: c.BreakoutStatus,
+			FollowTrendScore: c.FollowTrendScore, FollowTrendStatus: c.FollowTrendStatus,
+		})
+	}
+	if err := uc.archive.SaveScreenerSnapshots(ctx, snapshots); err != nil {
+		logging.Warnf("Archive: saving the score history failed: %v", err)
+	}
+}
+
+// pullbackTrendLine returns the configured trend baseline for pullback scoring,
+// reusing the already computed EMA when EMA is selected.
+func (uc *ScreenerUsecase) pullbackTrendLine(settings *screenerSettings, prices, ema []float64, period int) []float64 {
+	if settings.pullbackMA == indicators.MATypeEMA {
+		return ema
+	}
+	return indicators.CalculateMA(prices, period, settings.pullbackMA)
+}
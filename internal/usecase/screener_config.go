@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+// screenerConfigCacheTTL is how long the screener config is served from
+// memory, so a config saved on another instance is used within a minute
+const screenerConfigCacheTTL = time.Minute
+
+var ErrScreenerTimeframes = domain.Validation("SCREENER_INVALID_TIMEFRAMES",
+	"each strategy takes two different timeframes out of "+strings.Join(domain.ScreeningIntervals, ", "))
+
+// ScreenerConfigService holds the strategy toggles and timeframes the
+// screening cycles run with
+type ScreenerConfigService struct {
+	repo domain.ScreenerConfigRepository
+
+	mu       sync.Mutex
+	current  *domain.ScreenerConfig
+	loadedAt time.Time
+}
+
+// NewScreenerConfigService creates a new screener config service
+func NewScreenerConfigService(repo domain.ScreenerConfigRepository) *ScreenerConfigService {
+	return &ScreenerConfigService{repo: repo, current: domain.DefaultScreenerConfig()}
+}
+
+// Current returns the config in effect: the saved one, or the default before
+// the first save. A failed load keeps the cached config.
+func (s *ScreenerConfigService) Current(ctx context.Context) *domain.ScreenerConfig {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.loadedAt) > screenerConfigCacheTTL {
+		cfg, err := s.repo.GetScreenerConfig(ctx)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			s.current = domain.DefaultScreenerConfig()
+			s.loadedAt = now
+		case err != nil:
+			logging.Warnf("Loading the screener config failed: %v", err)
+		default:
+			s.current = cfg
+			s.loadedAt = now
+		}
+	}
+	return cloneScreenerConfig(s.current)
+}
+
+// Save validates and stores cfg; the next cycle runs with it
+func (s *ScreenerConfigService) Save(ctx context.Context, cfg *domain.ScreenerConfig) error {
+	defaults := domain.DefaultScreenerConfig()
+	for _, tfs := range []struct {
+		timeframes *[]string
+		defaults   []string
+	}{
+		{&cfg.Scalp.Timeframes, defaults.Scalp.Timeframes},
+		{&cfg.Intraday.Timeframes, defaults.Intraday.Timeframes},
+		{&cfg.Pullback.SetupTimeframes, defaults.Pullback.SetupTimeframes},
+		{&cfg.Pullback.ExecutionTimeframes, defaults.Pullback.ExecutionTimeframes},
+		{&cfg.Breakout.Timeframes, defaults.Breakout.Timeframes},
+		{&cfg.FollowTrend.Timeframes, defaults.FollowTrend.Timeframes},
+	} {
+		if len(*tfs.timeframes) == 0 {
+			*tfs.timeframes = tfs.defaults
+		}
+		normalized, err := normalizeTimeframes(*tfs.timeframes)
+		if err != nil {
+			return err
+		}
+		*tfs.timeframes = normalized
+	}
+	cfg.UpdatedAt = time.Now().UTC()
+	if err := s.repo.SaveScreenerConfig(ctx, cfg); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.current = cloneScreenerConfig(cfg)
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	logging.Infof("Screener config updated: %s", describeScreenerConfig(cfg))
+	return nil
+}
+
+// normalizeTimeframes checks a strategy's two timeframes and orders them
+// fastest first
+func normalizeTimeframes(timeframes []string) ([]string, error) {
+	if len(timeframes) != 2 {
+		return nil, ErrScreenerTimeframes
+	}
+	normalized := make([]string, len(timeframes))
+	for i, tf := range timeframes {
+		tf = strings.TrimSpace(tf)
+		if !slices.Contains(domain.ScreeningIntervals, tf) {
+			return nil, ErrScreenerTimeframes
+		}
+		normalized[i] = tf
+	}
+	if normalized[0] == normalized[1] {
+		return nil, ErrScreenerTimeframes
+	}
+	if binance.IntervalDuration(normalized[0]) > binance.IntervalDuration(normalized[1]) {
+		normalized[0], normalized[1] = normalized[1], normalized[0]
+	}
+	return normalized, nil
+}
+
+// slowestScreeningInterval is the longest timeframe cfg screens
+func slowestScreeningInterval(cfg *domain.ScreenerConfig) time.Duration {
+	var slowest time.Duration
+	for _, tfs := range [][]string{cfg.Scalp.Timeframes, cfg.Intraday.Timeframes, cfg.Pullback.SetupTimeframes,
+		cfg.Pullback.ExecutionTimeframes, cfg.Breakout.Timeframes, cfg.FollowTrend.Timeframes} {
+		for _, tf := range tfs {
+			if d := binance.IntervalDuration(tf); d > slowest {
+				slowest = d
+			}
+		}
+	}
+	return slowest
+}
+
+func cloneScreenerConfig(cfg *domain.ScreenerConfig) *domain.ScreenerConfig {
+	cloned := *cfg
+	cloned.Scalp.Timeframes = slices.Clone(cfg.Scalp.Timeframes)
+	cloned.Intraday.Timeframes = slices.Clone(cfg.Intraday.Timeframes)
+	cloned.Pullback.SetupTimeframes = slices.Clone(cfg.Pullback.SetupTimeframes)
+	cloned.Pullback.ExecutionTimeframes = slices.Clone(cfg.Pullback.ExecutionTimeframes)
+	cloned.Breakout.Timeframes = slices.Clone(cfg.Breakout.Timeframes)
+	cloned.FollowTrend.Timeframes = slices.Clone(cfg.FollowTrend.Timeframes)
+	return &cloned
+}
+
+// describeScreenerConfig is a one-line summary for the logs
+func describeScreenerConfig(cfg *domain.ScreenerConfig) string {
+	describe := func(name string, enabled bool, timeframes ...[]string) string {
+		state := "off"
+		if enabled {
+			state = "on"
+		}
+		var tfs []string
+		for _, t := range timeframes {
+			tfs = append(tfs, strings.Join(t, "+"))
+		}
+		return fmt.Sprintf("%s=%s(%s)", name, state, strings.Join(tfs, ", "))
+	}
+	return strings.Join([]string{
+		describe("scalp", cfg.Scalp.Enabled, cfg.Scalp.Timeframes),
+		describe("intraday", cfg.Intraday.Enabled, cfg.Intraday.Timeframes),
+		describe("pullback", cfg.Pullback.Enabled, cfg.Pullback.SetupTimeframes, cfg.Pullback.ExecutionTimeframes),
+		describe("breakout", cfg.Breakout.Enabled, cfg.Breakout.Timeframes),
+		describe("followTrend", cfg.FollowTrend.Enabled, cfg.FollowTrend.Timeframes),
+	}, " ")
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+	"screener-backend/internal/infrastructure/fcm"
+)
+
+// selfTestCheckTimeout bounds each connectivity check
+const selfTestCheckTimeout = 15 * time.Second
+
+// Self-test check outcomes
+const (
+	selfTestOK      = "ok"
+	selfTestFailed  = "failed"
+	selfTestSkipped = "skipped"
+)
+
+// SelfTestReport is the outcome of the startup connectivity checks
+type SelfTestReport struct {
+	Status     string          `json:"status"` // running, passed or failed
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt *time.Time      `json:"finishedAt,omitempty"`
+	Checks     []SelfTestCheck `json:"checks"`
+}
+
+// SelfTestCheck is one connectivity check
+type SelfTestCheck struct {
+	Name    string `json:"name"` // binance-public, database, fcm, credentials, binance-signed:<userId>
+	Status  string `json:"status"`
+	Latency string `json:"latency,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SelfTestService checks at startup that every outside dependency is
+// reachable and accepts our credentials, so a bad key or a geo-blocked host
+// shows up right away instead of on the first trade
+type SelfTestService struct {
+	market *binance.Client
+	creds  domain.BinanceAPIStore
+	dbPing func(ctx context.Context) error // nil without Postgres
+	fcm    *fcm.Client
+
+	mu   sync.RWMutex
+	last *SelfTestReport
+}
+
+// NewSelfTestService creates a self-test service; dbPing is nil when the app
+// runs on in-memory storage
+func NewSelfTestService(market *binance.Client, creds domain.BinanceAPIStore, dbPing func(ctx context.Context) error, fcmClient *fcm.Client) *SelfTestService {
+	return &SelfTestService{market: market, creds: creds, dbPing: dbPing, fcm: fcmClient}
+}
+
+// Report returns the latest report, nil before the first run
+func (s *SelfTestService) Report() *SelfTestReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Run performs every check and records the report. Failed checks are logged;
+// they never stop the server.
+func (s *SelfTestService) Run(ctx context.Context) *SelfTestReport {
+	report := &SelfTestReport{Status: "running", StartedAt: time.Now().UTC(), Checks: []SelfTestCheck{}}
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+
+	checks := []SelfTestCheck{
+		s.check(ctx, "binance-public", func(ctx context.Context) (string, error) {
+			symbols, err := s.market.GetActiveTradingSymbols(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d symbols trading", len(symbols)), nil
+		}),
+		s.checkDatabase(ctx),
+		s.checkFCM(ctx),
+	}
+	checks = append(checks, s.checkCredentials(ctx)...)
+
+	done := &SelfTestReport{Status: "passed", StartedAt: report.StartedAt, Checks: checks}
+	finished := time.Now().UTC()
+	done.FinishedAt = &finished
+	var failed []string
+	for _, c := range checks {
+		if c.Status == selfTestFailed {
+			failed = append(failed, c.Name)
+		}
+	}
+	if len(failed) > 0 {
+		done.Status = "failed"
+		log.Printf("⚠ Self-test: %d of %d checks failed (%s); see GET /api/admin/selftest", len(failed), len(checks), strings.Join(failed, ", "))
+	} else {
+		log.Printf("✓ Self-test passed (%d checks)", len(checks))
+	}
+
+	s.mu.Lock()
+	s.last = done
+	s.mu.Unlock()
+	return done
+}
+
+func (s *SelfTestService) checkDatabase(ctx context.Context) SelfTestCheck {
+	if s.dbPing == nil {
+		return SelfTestCheck{Name: "database", Status: selfTestSkipped, Detail: "Postgres not configured; using in-memory storage"}
+	}
+	return s.check(ctx, "database", func(ctx context.Context) (string, error) {
+		return "", s.dbPing(ctx)
+	})
+}
+
+func (s *SelfTestService) checkFCM(ctx context.Context) SelfTestCheck {
+	if s.fcm == nil || !s.fcm.IsEnabled() {
+		return SelfTestCheck{Name: "fcm", Status: selfTestSkipped, Detail: "no Firebase credentials; push notifications disabled"}
+	}
+	return s.check(ctx, "fcm", func(ctx context.Context) (string, error) {
+		return "", s.fcm.Validate(ctx)
+	})
+}
+
+// checkCredentials sends a signed request for every user with enabled
+// credentials. A failure to read the credentials at all (e.g. a changed
+// API_ENCRYPTION_KEY) is reported as its own check.
+func (s *SelfTestService) checkCredentials(ctx context.Context) []SelfTestCheck {
+	var creds []*domain.BinanceAPICredentials
+	listed := s.check(ctx, "credentials", func(ctx context.Context) (string, error) {
+		var err error
+		creds, err = s.creds.ListCredentials(ctx)
+		if err != nil {
+			return "", err
+		}
+		enabled := 0
+		for _, cred := range creds {
+			if cred.IsEnabled {
+				enabled++
+			}
+		}
+		return fmt.Sprintf("%d stored, %d enabled", len(creds), enabled), nil
+	})
+
+	checks := []SelfTestCheck{listed}
+	for _, cred := range creds {
+		if !cred.IsEnabled {
+			continue
+		}
+		client := binance.NewTradingClientForCredentials(cred)
+		checks = append(checks, s.check(ctx, "binance-signed:"+cred.UserID, func(ctx context.Context) (string, error) {
+			if cred.IsTestnet {
+				return "testnet", client.TestConnection(ctx)
+			}
+			return "", client.TestConnection(ctx)
+		}))
+	}
+	return checks
+}
+
+// check runs fn under the per-check timeout and times it
+func (s *SelfTestService) check(ctx context.Context, name string, fn func(ctx context.Context) (string, error)) SelfTestCheck {
+	ctx, cancel := context.WithTimeout(ctx, selfTestCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := fn(ctx)
+	c := SelfTestCheck{
+		Name:    name,
+		Status:  selfTestOK,
+		Latency: time.Since(start).Round(time.Millisecond).String(),
+		Detail:  detail,
+	}
+	if err != nil {
+		c.Status = selfTestFailed
+		c.Error = err.Error()
+	}
+	return c
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+const (
+	// maxPriceFetchesPerRun keeps one labeling run well inside Binance's
+	// request weight budget; the rest waits for the next run
+	maxPriceFetchesPerRun = 300
+	// unlabeledSignalBatch is how many pending signals one run looks at
+	unlabeledSignalBatch = 5000
+	// signalLabelGiveUp is how long past its last horizon a signal may stay
+	// unmeasurable (e.g. a delisted symbol) before it is closed as is
+	signalLabelGiveUp = 24 * time.Hour
+	// defaultScoreBucket is the width of the score buckets in the statistics
+	defaultScoreBucket = 10
+)
+
+// SignalStats are hit rates of the recorded signals per strategy and score bucket
+type SignalStats struct {
+	From       time.Time             `json:"from"`
+	To         time.Time             `json:"to"`
+	BucketSize float64               `json:"bucketSize"`
+	Strategies []StrategySignalStats `json:"strategies"`
+}
+
+// StrategySignalStats covers one strategy
+type StrategySignalStats struct {
+	Strategy string                  `json:"strategy"`
+	Signals  int                     `json:"signals"`
+	Horizons map[string]HorizonStats `json:"horizons"`
+	Buckets  []ScoreBucketStats      `json:"buckets"`
+}
+
+// ScoreBucketStats covers the signals scored in [MinScore, MinScore+bucketSize)
+type ScoreBucketStats struct {
+	Bucket   string                  `json:"bucket"` // e.g. 70-80
+	MinScore float64                 `json:"minScore"`
+	Signals  int                     `json:"signals"`
+	Horizons map[string]HorizonStats `json:"horizons"`
+}
+
+// HorizonStats summarizes the outcomes measured at one horizon. A hit is a
+// move in the signal's direction.
+type HorizonStats struct {
+	Labeled      int     `json:"labeled"`
+	HitRate      float64 `json:"hitRate"`
+	AvgReturnPct float64 `json:"avgReturnPct"`
+}
+
+// SignalOutcomeService labels recorded signals with the price change that
+// followed them and reports how often each strategy called it right
+type SignalOutcomeService struct {
+	repo   domain.SignalRepository
+	market *binance.Client
+}
+
+// NewSignalOutcomeService creates a new signal outcome service
+func NewSignalOutcomeService(repo domain.SignalRepository, market *binance.Client) *SignalOutcomeService {
+	return &SignalOutcomeService{repo: repo, market: market}
+}
+
+// Label measures every horizon that has passed since its signal. Run it
+// every minute or so; a horizon is measured once its 1m candle has closed.
+func (s *SignalOutcomeService) Label(ctx context.Context) error {
+	pending, err := s.repo.ListUnlabeledSignals(ctx, unlabeledSignalBatch)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	fetches, failed := 0, 0
+	var firstErr error
+	var errs []error
+	for _, sig := range pending {
+		if sig.Outcomes == nil {
+			sig.Outcomes = make(map[string]domain.SignalOutcome)
+		}
+		changed := false
+		for _, h := range domain.SignalHorizons {
+			if _, done := sig.Outcomes[h.Name]; done {
+				continue
+			}
+			at := sig.SignaledAt.Add(h.After)
+			if now.Before(at.Add(time.Minute)) || fetches >= maxPriceFetchesPerRun {
+				continue
+			}
+			fetches++
+			price, err := s.market.GetPriceAt(ctx, sig.Symbol, at)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if failed++; firstErr == nil {
+					firstErr = fmt.Errorf("%s +%s: %w", sig.Symbol, h.Name, err)
+				}
+				continue
+			}
+			sig.Outcomes[h.Name] = domain.SignalOutcome{Price: price, ReturnPct: signalReturn(sig, price)}
+			changed = true
+		}
+
+		last := domain.SignalHorizons[len(domain.SignalHorizons)-1]
+		labeled := len(sig.Outcomes) == len(domain.SignalHorizons) ||
+			now.After(sig.SignaledAt.Add(last.After+signalLabelGiveUp))
+		if !changed && !labeled {
+			continue
+		}
+		if err := s.repo.SaveOutcomes(ctx, sig.ID, sig.Outcomes, labeled); err != nil {
+			errs = append(errs, fmt.Errorf("save outcomes of signal %d: %w", sig.ID, err))
+		}
+	}
+	if failed > 0 {
+		// Usually a delisted symbol; retried next run until signalLabelGiveUp
+		logging.Warnf("Signal labeling: %d outcomes could not be measured (first: %v)", failed, firstErr)
+	}
+	return errors.Join(errs...)
+}
+
+// List returns recorded signals, newest first
+func (s *SignalOutcomeService) List(ctx context.Context, filter domain.SignalFilter) ([]*domain.Signal, error) {
+	return s.repo.ListSignals(ctx, filter)
+}
+
+// Stats summarizes the outcomes of the signals raised in [from, to];
+// strategy empty covers every strategy
+func (s *SignalOutcomeService) Stats(ctx context.Context, from, to time.Time, strategy string, bucketSize float64) (*SignalStats, error) {
+	if bucketSize <= 0 {
+		bucketSize = defaultScoreBucket
+	}
+	signals, err := s.repo.ListSignals(ctx, domain.SignalFilter{Strategy: strategy, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	byStrategy := make(map[string][]*domain.Signal)
+	for _, sig := range signals {
+		byStrategy[sig.Strategy] = append(byStrategy[sig.Strategy], sig)
+	}
+
+	stats := &SignalStats{From: from, To: to, BucketSize: bucketSize, Strategies: []StrategySignalStats{}}
+	for name, group := range byStrategy {
+		st := StrategySignalStats{Strategy: name, Signals: len(group), Horizons: horizonStats(group), Buckets: []ScoreBucketStats{}}
+
+		byBucket := make(map[float64][]*domain.Signal)
+		for _, sig := range group {
+			low := math.Floor(sig.Score/bucketSize) * bucketSize
+			byBucket[low] = append(byBucket[low], sig)
+		}
+		for low, bucket := range byBucket {
+			st.Buckets = append(st.Buckets, ScoreBucketStats{
+				Bucket:   fmt.Sprintf("%g-%g", low, low+bucketSize),
+				MinScore: low,
+				Signals:  len(bucket),
+				Horizons: horizonStats(bucket),
+			})
+		}
+		sort.Slice(st.Buckets, func(i, j int) bool { return st.Buckets[i].MinScore < st.Buckets[j].MinScore })
+		stats.Strategies = append(stats.Strategies, st)
+	}
+	sort.Slice(stats.Strategies, func(i, j int) bool { return stats.Strategies[i].Strategy < stats.Strategies[j].Strategy })
+	return stats, nil
+}
+
+func horizonStats(signals []*domain.Signal) map[string]HorizonStats {
+	result := make(map[string]HorizonStats, len(domain.SignalHorizons))
+	for _, h := range domain.SignalHorizons {
+		var hs HorizonStats
+		hits := 0
+		sum := 0.0
+		for _, sig := range signals {
+			o, ok := sig.Outcomes[h.Name]
+			if !ok {
+				continue
+			}
+			hs.Labeled++
+			sum += o.ReturnPct
+			if o.ReturnPct > 0 {
+				hits++
+			}
+		}
+		if hs.Labeled > 0 {
+			hs.HitRate = float64(hits) / float64(hs.Labeled)
+			hs.AvgReturnPct = sum / float64(hs.Labeled)
+		}
+		result[h.Name] = hs
+	}
+	return result
+}
+
+// signalReturn is the percent move from the signal price, positive when the
+// price went the signal's way
+func signalReturn(sig *domain.Signal, price float64) float64 {
+	if sig.Price <= 0 {
+		return 0
+	}
+	ret := (price - sig.Price) / sig.Price * 100
+	if sig.Direction == "SHORT" {
+		ret = -ret
+	}
+	return ret
+}
+
+// coinSignals lists the tracked strategy signals a coin shows this cycle
+func coinSignals(coin *domain.CoinData, at time.Time) []*domain.Signal {
+	var signals []*domain.Signal
+	add := func(strategy, direction, tf string, score float64) {
+		signals = append(signals, &domain.Signal{
+			Symbol:     coin.Symbol,
+			Strategy:   strategy,
+			Direction:  direction,
+			Timeframe:  tf,
+			Score:      score,
+			Price:      coin.Price,
+			SignaledAt: at,
+		})
+	}
+	if coin.Status == "TRIGGER" {
+		add(domain.StrategyTrigger, "SHORT", coin.TriggerTF, coin.Score)
+	}
+	switch coin.BreakoutStatus {
+	case "BREAKOUT_LONG":
+		add(domain.StrategyBreakout, "LONG", strongestTF(coin.BreakoutTFScores), coin.BreakoutScore)
+	case "BREAKOUT_SHORT":
+		add(domain.StrategyBreakout, "SHORT", strongestTF(coin.BreakoutTFScores), coin.BreakoutScore)
+	}
+	if coin.PullbackStatus == "DIP" {
+		add(domain.StrategyDip, "LONG", strongestTF(coin.PullbackTFScores), coin.PullbackScore)
+	}
+	return signals
+}
+
+// strongestTF is the timeframe with the highest score, empty when none
+func strongestTF(scores []domain.TimeframeScore) string {
+	best := -1
+	for i, s := range scores {
+		if best < 0 || s.Score > scores[best].Score {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	return scores[best].TF
+}
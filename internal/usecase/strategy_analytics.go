@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"screener-backend/internal/domain"
+)
+
+// Sources of the results in a strategy dashboard
+const (
+	PerformanceSourceSignals   = "signals"   // screener signals, measured by forward return
+	PerformanceSourceAutoScalp = "autoscalp" // closed auto scalp positions
+	PerformanceSourceJournal   = "journal"   // closed trade journal entries
+)
+
+// StrategyDashboard compares how each strategy's setups worked out over a period
+type StrategyDashboard struct {
+	From       time.Time             `json:"from"`
+	To         time.Time             `json:"to"`
+	Horizon    string                `json:"horizon"` // forward-return horizon of the signal rows
+	Strategies []StrategyPerformance `json:"strategies"`
+}
+
+// StrategyPerformance covers one strategy of one source, overall and per timeframe
+type StrategyPerformance struct {
+	Source   string `json:"source"`
+	Strategy string `json:"strategy"`
+	PerformanceStats
+	Timeframes []TimeframePerformance `json:"timeframes"`
+}
+
+// TimeframePerformance covers the results taken on one timeframe; an empty
+// timeframe collects results that don't record one
+type TimeframePerformance struct {
+	Timeframe string `json:"timeframe"`
+	PerformanceStats
+}
+
+// PerformanceStats summarize a group of results. Returns are percent moves in
+// the trade's direction (P/L over notional for trades), so AvgReturnPct is
+// the expectancy per setup. Expectancy is the average P/L in USDT, trades only.
+type PerformanceStats struct {
+	Samples      int      `json:"samples"`
+	HitRate      float64  `json:"hitRate"`
+	AvgReturnPct float64  `json:"avgReturnPct"`
+	AvgWinPct    float64  `json:"avgWinPct"`
+	AvgLossPct   float64  `json:"avgLossPct"` // negative
+	Expectancy   *float64 `json:"expectancy,omitempty"`
+}
+
+// StrategyAnalyticsService aggregates signal outcomes, auto scalp results and
+// journal trades per strategy and timeframe
+type StrategyAnalyticsService struct {
+	signals   domain.SignalRepository
+	autoScalp domain.AutoScalpRepository
+	trades    domain.TradeEntryRepository
+}
+
+// NewStrategyAnalyticsService creates a new strategy analytics service
+func NewStrategyAnalyticsService(signals domain.SignalRepository, autoScalp domain.AutoScalpRepository, trades domain.TradeEntryRepository) *StrategyAnalyticsService {
+	return &StrategyAnalyticsService{signals: signals, autoScalp: autoScalp, trades: trades}
+}
+
+// performanceResult is one measured setup
+type performanceResult struct {
+	source    string
+	strategy  string
+	timeframe string
+	returnPct float64
+	profit    *float64 // USDT, trades only
+}
+
+// Dashboard reports the signals raised and trades closed in [from, to].
+// Signals are judged at horizon; journal entries are included, and auto scalp
+// positions narrowed to the user's, when userID is set. An auto scalp position
+// taken over by a journal entry is counted once, under the journal.
+func (s *StrategyAnalyticsService) Dashboard(ctx context.Context, userID string, from, to time.Time, horizon string) (*StrategyDashboard, error) {
+	if _, ok := horizonIndex(horizon); !ok {
+		return nil, ErrUnknownHorizon
+	}
+
+	signals, err := s.signals.ListSignals(ctx, domain.SignalFilter{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	var results []performanceResult
+	for _, sig := range signals {
+		if o, ok := sig.Outcomes[horizon]; ok {
+			results = append(results, performanceResult{
+				source:    PerformanceSourceSignals,
+				strategy:  sig.Strategy,
+				timeframe: sig.Timeframe,
+				returnPct: o.ReturnPct,
+			})
+		}
+	}
+
+	autoScalp := s.autoScalp.GetAllHistory(ctx, from)
+	if userID != "" {
+		autoScalp = s.autoScalp.GetHistory(ctx, userID, from)
+	}
+	for _, e := range autoScalp {
+		if e.ExitTime == nil || e.ExitTime.After(to) || e.ProfitLossPct == nil || e.TradeEntryID != "" {
+			continue
+		}
+		results = append(results, performanceResult{
+			source:    PerformanceSourceAutoScalp,
+			strategy:  domain.StrategyTrigger,
+			returnPct: *e.ProfitLossPct,
+			profit:    e.ProfitLoss,
+		})
+	}
+
+	if userID != "" {
+		for _, e := range s.trades.GetEntryHistory(ctx, userID, domain.TradeHistoryFilter{From: &from, To: &to}) {
+			if !e.IsClosed() || e.ProfitLoss == nil || e.ExitPrice == nil {
+				continue
+			}
+			results = append(results, performanceResult{
+				source:    PerformanceSourceJournal,
+				strategy:  e.Strategy,
+				timeframe: journalTimeframe(e),
+				returnPct: journalReturnPct(e),
+				profit:    e.ProfitLoss,
+			})
+		}
+	}
+
+	return &StrategyDashboard{From: from, To: to, Horizon: horizon, Strategies: groupPerformance(results)}, nil
+}
+
+// groupPerformance builds one row per source and strategy, ordered by source
+// then strategy, with timeframes shortest first
+func groupPerformance(results []performanceResult) []StrategyPerformance {
+	type key struct{ source, strategy string }
+	groups := make(map[key][]performanceResult)
+	for _, r := range results {
+		k := key{r.source, r.strategy}
+		groups[k] = append(groups[k], r)
+	}
+
+	rows := make([]StrategyPerformance, 0, len(groups))
+	for k, group := range groups {
+		byTF := make(map[string][]performanceResult)
+		for _, r := range group {
+			byTF[r.timeframe] = append(byTF[r.timeframe], r)
+		}
+		row := StrategyPerformance{Source: k.source, Strategy: k.strategy, PerformanceStats: performanceStats(group), Timeframes: []TimeframePerformance{}}
+		for tf, tfGroup := range byTF {
+			row.Timeframes = append(row.Timeframes, TimeframePerformance{Timeframe: tf, PerformanceStats: performanceStats(tfGroup)})
+		}
+		sort.Slice(row.Timeframes, func(i, j int) bool {
+			return timeframeLess(row.Timeframes[i].Timeframe, row.Timeframes[j].Timeframe)
+		})
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Source != rows[j].Source {
+			return rows[i].Source < rows[j].Source
+		}
+		return rows[i].Strategy < rows[j].Strategy
+	})
+	return rows
+}
+
+func performanceStats(results []performanceResult) PerformanceStats {
+	stats := PerformanceStats{Samples: len(results)}
+	if len(results) == 0 {
+		return stats
+	}
+	var sum, winSum, lossSum, profitSum float64
+	wins, losses, trades := 0, 0, 0
+	for _, r := range results {
+		sum += r.returnPct
+		if r.returnPct > 0 {
+			wins++
+			winSum += r.returnPct
+		} else {
+			losses++
+			lossSum += r.returnPct
+		}
+		if r.profit != nil {
+			trades++
+			profitSum += *r.profit
+		}
+	}
+	stats.HitRate = float64(wins) / float64(len(results))
+	stats.AvgReturnPct = sum / float64(len(results))
+	if wins > 0 {
+		stats.AvgWinPct = winSum / float64(wins)
+	}
+	if losses > 0 {
+		stats.AvgLossPct = lossSum / float64(losses)
+	}
+	if trades > 0 {
+		expectancy := round2(profitSum / float64(trades))
+		stats.Expectancy = &expectancy
+	}
+	return stats
+}
+
+// journalReturnPct is the trade's net P/L over its notional, or the price
+// move in its direction for entries recorded without a size
+func journalReturnPct(e *domain.TradeEntry) float64 {
+	if e.Notional > 0 && !e.IsCoinMargined() {
+		return *e.ProfitLoss / e.Notional * 100
+	}
+	if e.EntryPrice <= 0 {
+		return 0
+	}
+	move := (*e.ExitPrice - e.EntryPrice) / e.EntryPrice * 100
+	if !e.IsLong {
+		move = -move
+	}
+	return move
+}
+
+// journalTimeframe reads the timeframe from the signal the trade was taken
+// from: the strategy's strongest TF, or the screener's trigger TF
+func journalTimeframe(e *domain.TradeEntry) string {
+	if e.Signal == nil {
+		return ""
+	}
+	coin := &e.Signal.Coin
+	switch e.Strategy {
+	case "breakout":
+		if tf := strongestTF(coin.BreakoutTFScores); tf != "" {
+			return tf
+		}
+	case "pullback":
+		if tf := strongestTF(coin.PullbackTFScores); tf != "" {
+			return tf
+		}
+	}
+	return coin.TriggerTF
+}
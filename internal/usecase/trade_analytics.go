@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"math"
+	"screener-backend/internal/domain"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const topSymbolCount = 5
+
+// ComputeTradeAnalytics aggregates closed journal entries that exited within [from, to].
+// Entries without a realized P/L are ignored.
+func ComputeTradeAnalytics(entries []*domain.TradeEntry, from, to time.Time) *domain.TradeAnalytics {
+	result := &domain.TradeAnalytics{
+		From:         from,
+		To:           to,
+		BestSymbols:  []domain.TradeGroupStats{},
+		WorstSymbols: []domain.TradeGroupStats{},
+		ByWeekday:    []domain.TradeGroupStats{},
+		ByHour:       []domain.TradeGroupStats{},
+		ByStrategy:   []domain.TradeGroupStats{},
+		ByTag:        []domain.TradeGroupStats{},
+	}
+
+	bySymbol := newGroupAccumulator()
+	byWeekday := newGroupAccumulator()
+	byHour := newGroupAccumulator()
+	byStrategy := newGroupAccumulator()
+	byTag := newGroupAccumulator()
+
+	var sumR float64
+	var rCount int
+
+	for _, e := range entries {
+		if e.ProfitLoss == nil {
+			continue
+		}
+		exit := e.EntryTime
+		if e.ExitTime != nil {
+			exit = *e.ExitTime
+		}
+		if exit.Before(from) || exit.After(to) {
+			continue
+		}
+
+		pl := *e.ProfitLoss
+		result.TotalTrades++
+		result.NetProfit += pl
+		if pl > 0 {
+			result.Wins++
+			result.GrossProfit += pl
+		} else if pl < 0 {
+			result.Losses++
+			result.GrossLoss += -pl
+		}
+		if r, ok := e.RMultiple(); ok {
+			sumR += r
+			rCount++
+		}
+
+		entryUTC := e.EntryTime.UTC()
+		bySymbol.add(e.Symbol, e)
+		byWeekday.add(entryUTC.Weekday().String(), e)
+		byHour.add(strconv.Itoa(entryUTC.Hour()), e)
+		strategy := e.Strategy
+		if strategy == "" {
+			strategy = "manual"
+		}
+		byStrategy.add(strategy, e)
+		for _, tag := range e.Tags {
+			byTag.add(tag, e)
+		}
+	}
+
+	if result.TotalTrades == 0 {
+		return result
+	}
+
+	result.WinRate = round2(float64(result.Wins) / float64(result.TotalTrades) * 100)
+	result.Expectancy = result.NetProfit / float64(result.TotalTrades)
+	if rCount > 0 {
+		result.AverageR = round2(sumR / float64(rCount))
+	}
+	if result.GrossLoss > 0 {
+		pf := round2(result.GrossProfit / result.GrossLoss)
+		result.ProfitFactor = &pf
+	}
+
+	symbols := bySymbol.stats()
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].NetProfit > symbols[j].NetProfit })
+	for i := 0; i < len(symbols) && i < topSymbolCount && symbols[i].NetProfit > 0; i++ {
+		result.BestSymbols = append(result.BestSymbols, symbols[i])
+	}
+	for i := len(symbols) - 1; i >= 0 && len(result.WorstSymbols) < topSymbolCount && symbols[i].NetProfit < 0; i-- {
+		result.WorstSymbols = append(result.WorstSymbols, symbols[i])
+	}
+
+	result.ByWeekday = byWeekday.statsOrdered(func(key string) int {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if d.String() == key {
+				return int(d)
+			}
+		}
+		return 7
+	})
+	result.ByHour = byHour.statsOrdered(func(key string) int {
+		h, _ := strconv.Atoi(key)
+		return h
+	})
+	result.ByStrategy = byStrategy.statsByProfit()
+	result.ByTag = byTag.statsByProfit()
+
+	return result
+}
+
+type groupAccumulator struct {
+	order  []string
+	groups map[string]*groupTotals
+}
+
+type groupTotals struct {
+	trades int
+	wins   int
+	net    float64
+	sumR   float64
+	rCount int
+}
+
+func newGroupAccumulator() *groupAccumulator {
+	return &groupAccumulator{groups: make(map[string]*groupTotals)}
+}
+
+func (g *groupAccumulator) add(key string, e *domain.TradeEntry) {
+	t, ok := g.groups[key]
+	if !ok {
+		t = &groupTotals{}
+		g.groups[key] = t
+		g.order = append(g.order, key)
+	}
+	t.trades++
+	t.net += *e.ProfitLoss
+	if *e.ProfitLoss > 0 {
+		t.wins++
+	}
+	if r, ok := e.RMultiple(); ok {
+		t.sumR += r
+		t.rCount++
+	}
+}
+
+func (g *groupAccumulator) stats() []domain.TradeGroupStats {
+	out := make([]domain.TradeGroupStats, 0, len(g.order))
+	for _, key := range g.order {
+		t := g.groups[key]
+		s := domain.TradeGroupStats{
+			Key:       key,
+			Trades:    t.trades,
+			WinRate:   round2(float64(t.wins) / float64(t.trades) * 100),
+			NetProfit: t.net,
+		}
+		if t.rCount > 0 {
+			s.AverageR = round2(t.sumR / float64(t.rCount))
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func (g *groupAccumulator) statsOrdered(rank func(key string) int) []domain.TradeGroupStats {
+	out := g.stats()
+	sort.Slice(out, func(i, j int) bool { return rank(out[i].Key) < rank(out[j].Key) })
+	return out
+}
+
+func (g *groupAccumulator) statsByProfit() []domain.TradeGroupStats {
+	out := g.stats()
+	sort.Slice(out, func(i, j int) bool { return out[i].NetProfit > out[j].NetProfit })
+	return out
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// ComputeTradeCalendar buckets closed entries by UTC exit day for the month
+// starting at monthStart (UTC, first day of the month).
+func ComputeTradeCalendar(entries []*domain.TradeEntry, monthStart time.Time) *domain.TradeCalendar {
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	cal := &domain.TradeCalendar{Month: monthStart.Format("2006-01")}
+
+	days := make([]domain.TradeCalendarDay, 0, 31)
+	for d := monthStart; d.Before(monthEnd); d = d.AddDate(0, 0, 1) {
+		days = append(days, domain.TradeCalendarDay{Date: d.Format("2006-01-02")})
+	}
+
+	for _, e := range entries {
+		if e.ProfitLoss == nil {
+			continue
+		}
+		exit := e.EntryTime
+		if e.ExitTime != nil {
+			exit = *e.ExitTime
+		}
+		exit = exit.UTC()
+		if exit.Before(monthStart) || !exit.Before(monthEnd) {
+			continue
+		}
+
+		day := &days[exit.Day()-1]
+		pl := *e.ProfitLoss
+		day.Trades++
+		day.NetProfit += pl
+		if pl > 0 {
+			day.Wins++
+		} else if pl < 0 {
+			day.Losses++
+		}
+		cal.TotalTrades++
+		cal.NetProfit += pl
+	}
+
+	for i := range days {
+		days[i].NetProfit = round2(days[i].NetProfit)
+		if days[i].Trades == 0 {
+			continue
+		}
+		cal.TradingDays++
+		if cal.BestDay == nil || days[i].NetProfit > cal.BestDay.NetProfit {
+			best := days[i]
+			cal.BestDay = &best
+		}
+		if cal.WorstDay == nil || days[i].NetProfit < cal.WorstDay.NetProfit {
+			worst := days[i]
+			cal.WorstDay = &worst
+		}
+	}
+	cal.NetProfit = round2(cal.NetProfit)
+	cal.Days = days
+	return cal
+}
@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+	"sort"
+	"time"
+)
+
+// Binance limits userTrades/income queries to 7-day windows
+const binanceHistoryWindow = 7 * 24 * time.Hour
+
+// MaxTradeImportDays bounds how far back an import may reach
+const MaxTradeImportDays = 90
+
+// TradeImportResult summarizes a Binance import run
+type TradeImportResult struct {
+	Imported int                  `json:"imported"`
+	Skipped  int                  `json:"skipped"` // already in the journal
+	Entries  []*domain.TradeEntry `json:"entries"`
+}
+
+// TradeImportService rebuilds journal entries from the user's Binance futures fills
+type TradeImportService struct {
+	apiRepo   domain.BinanceAPIStore
+	tradeRepo domain.TradeEntryRepository
+	converter *USDConverter
+}
+
+// NewTradeImportService creates a new import service
+func NewTradeImportService(apiRepo domain.BinanceAPIStore, tradeRepo domain.TradeEntryRepository, converter *USDConverter) *TradeImportService {
+	return &TradeImportService{apiRepo: apiRepo, tradeRepo: tradeRepo, converter: converter}
+}
+
+// Import pulls fills between from and to, groups them into round-trip positions
+// (flat -> open -> flat) and stores each as a closed journal entry.
+// Positions still open at `to`, and closes of positions opened before `from`,
+// are left out. Fees and funding charged in other assets (BNB, USDC...) are
+// converted to USD at the time they were charged.
+func (s *TradeImportService) Import(ctx context.Context, userID string, from, to time.Time) (*TradeImportResult, error) {
+	cred, err := s.apiRepo.GetCredentials(ctx, userID)
+	if err != nil {
+		return nil, ErrMissingCredentials
+	}
+	client := binance.NewTradingClientForCredentials(cred)
+
+	// Discover traded symbols from realized P/L income, and collect funding in the same pass
+	symbols := make(map[string]bool)
+	var funding []domain.BinanceIncome
+	for start := from; start.Before(to); start = start.Add(binanceHistoryWindow) {
+		end := minTime(start.Add(binanceHistoryWindow), to)
+		incomes, err := fetchAllIncome(ctx, client, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("fetch income history: %w", err)
+		}
+		for _, in := range incomes {
+			switch in.IncomeType {
+			case "REALIZED_PNL":
+				symbols[in.Symbol] = true
+			case "FUNDING_FEE":
+				in.Income = s.toUSD(ctx, in.Asset, in.Income, in.Time)
+				in.Asset = "USDT"
+				funding = append(funding, in)
+			}
+		}
+	}
+
+	result := &TradeImportResult{Entries: []*domain.TradeEntry{}}
+	for symbol := range symbols {
+		fills, err := fetchAllUserTrades(ctx, client, symbol, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("fetch trades for %s: %w", symbol, err)
+		}
+
+		for i := range fills {
+			fills[i].Commission = s.toUSD(ctx, fills[i].CommissionAsset, fills[i].Commission, fills[i].Time)
+			fills[i].CommissionAsset = "USDT"
+		}
+
+		for _, rt := range groupRoundTrips(fills) {
+			entry := rt.toEntry(userID, funding)
+			if _, err := s.tradeRepo.GetEntryByID(ctx, userID, entry.ID); err == nil {
+				result.Skipped++
+				continue
+			}
+			if err := s.tradeRepo.CreateEntry(ctx, entry); err != nil {
+				log.Printf("Trade import: failed to store %s: %v", entry.ID, err)
+				continue
+			}
+			result.Imported++
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	sort.Slice(result.Entries, func(i, j int) bool {
+		return result.Entries[i].EntryTime.Before(result.Entries[j].EntryTime)
+	})
+	return result, nil
+}
+
+// toUSD converts an amount charged in asset; unconvertible amounts are dropped
+// (logged) rather than mixed into USD totals.
+func (s *TradeImportService) toUSD(ctx context.Context, asset string, amount float64, at time.Time) float64 {
+	usd, err := s.converter.ToUSD(ctx, asset, amount, at)
+	if err != nil {
+		log.Printf("Trade import: skipping %g %s: %v", amount, asset, err)
+		return 0
+	}
+	return usd
+}
+
+func fetchAllUserTrades(ctx context.Context, client *binance.TradingClient, symbol string, from, to time.Time) ([]domain.BinanceUserTrade, error) {
+	var all []domain.BinanceUserTrade
+	for start := from; start.Before(to); start = start.Add(binanceHistoryWindow) {
+		end := minTime(start.Add(binanceHistoryWindow), to)
+		pageStart := start
+		for {
+			fills, err := client.GetUserTrades(ctx, symbol, pageStart, end)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, fills...)
+			if len(fills) < 1000 {
+				break
+			}
+			pageStart = fills[len(fills)-1].Time.Add(time.Millisecond)
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Time.Equal(all[j].Time) {
+			return all[i].ID < all[j].ID
+		}
+		return all[i].Time.Before(all[j].Time)
+	})
+	return all, nil
+}
+
+// fetchAllIncome pages through one income window (1000 records per call)
+func fetchAllIncome(ctx context.Context, client *binance.TradingClient, start, end time.Time) ([]domain.BinanceIncome, error) {
+	var all []domain.BinanceIncome
+	for {
+		incomes, err := client.GetIncomeHistory(ctx, "", start, end)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, incomes...)
+		if len(incomes) < 1000 {
+			return all, nil
+		}
+		start = incomes[len(incomes)-1].Time.Add(time.Millisecond)
+	}
+}
+
+// roundTrip accumulates the fills of one position from open to flat
+type roundTrip struct {
+	symbol     string
+	isLong     bool
+	firstID    int64
+	openTime   time.Time
+	closeTime  time.Time
+	openQty    float64
+	openCost   float64
+	closeQty   float64
+	closeCost  float64
+	realized   float64
+	commission float64
+}
+
+const qtyEpsilon = 1e-9
+
+// groupRoundTrips walks time-ordered fills per position side and splits them
+// into completed round trips. A fill that flips the position closes the
+// current trip and opens the next one with the remainder.
+func groupRoundTrips(fills []domain.BinanceUserTrade) []*roundTrip {
+	type sideState struct {
+		pos     float64 // signed position size
+		current *roundTrip
+	}
+	states := make(map[string]*sideState)
+	var done []*roundTrip
+
+	for _, f := range fills {
+		st, ok := states[f.PositionSide]
+		if !ok {
+			st = &sideState{}
+			states[f.PositionSide] = st
+		}
+
+		delta := f.Qty
+		if f.Side == "SELL" {
+			delta = -delta
+		}
+		fee := 0.0
+		if f.CommissionAsset == "USDT" {
+			fee = f.Commission
+		}
+
+		if st.current == nil {
+			if f.RealizedPnl != 0 {
+				continue // closes a position opened before the import window
+			}
+			st.current = &roundTrip{symbol: f.Symbol, isLong: delta > 0, firstID: f.ID, openTime: f.Time}
+		}
+
+		rt := st.current
+		rt.commission += fee
+		rt.realized += f.RealizedPnl
+
+		if st.pos == 0 || (st.pos > 0) == (delta > 0) {
+			rt.openQty += math.Abs(delta)
+			rt.openCost += math.Abs(delta) * f.Price
+			st.pos += delta
+			continue
+		}
+
+		closing := math.Min(math.Abs(delta), math.Abs(st.pos))
+		rt.closeQty += closing
+		rt.closeCost += closing * f.Price
+		rt.closeTime = f.Time
+		remainder := math.Abs(delta) - closing
+		st.pos += delta
+
+		if math.Abs(st.pos) > qtyEpsilon && remainder <= qtyEpsilon {
+			continue // partial close
+		}
+
+		done = append(done, rt)
+		st.current = nil
+		st.pos = 0
+
+		if remainder > qtyEpsilon {
+			st.current = &roundTrip{
+				symbol:   f.Symbol,
+				isLong:   delta > 0,
+				firstID:  f.ID,
+				openTime: f.Time,
+				openQty:  remainder,
+				openCost: remainder * f.Price,
+			}
+			if delta > 0 {
+				st.pos = remainder
+			} else {
+				st.pos = -remainder
+			}
+		}
+	}
+
+	return done
+}
+
+func (rt *roundTrip) toEntry(userID string, funding []domain.BinanceIncome) *domain.TradeEntry {
+	fundingTotal := 0.0
+	for _, in := range funding {
+		if in.Symbol == rt.symbol && in.Asset == "USDT" &&
+			!in.Time.Before(rt.openTime) && !in.Time.After(rt.closeTime) {
+			fundingTotal += in.Income
+		}
+	}
+
+	exitPrice := rt.closeCost / rt.closeQty
+	exitTime := rt.closeTime
+	pl := rt.realized - rt.commission + fundingTotal
+
+	return &domain.TradeEntry{
+		ID:          fmt.Sprintf("binance-%s-%s-%d", userID, rt.symbol, rt.firstID),
+		UserID:      userID,
+		Symbol:      rt.symbol,
+		IsLong:      rt.isLong,
+		EntryPrice:  rt.openCost / rt.openQty,
+		Quantity:    rt.openQty,
+		Notional:    rt.openCost,
+		EntryTime:   rt.openTime,
+		Status:      "closed",
+		ExitPrice:   &exitPrice,
+		ExitTime:    &exitTime,
+		ProfitLoss:  &pl,
+		EntryReason: "Imported from Binance",
+		Tags:        []string{"binance-import"},
+		Strategy:    "manual",
+		Fees:        rt.commission,
+		Funding:     fundingTotal,
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
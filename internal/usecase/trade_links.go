@@ -0,0 +1,58 @@
+package usecase
+
+import "screener-backend/internal/domain"
+
+// MergeAutoScalpEntries appends closed auto scalp results to journal entries
+// for combined stats. An auto scalp entry linked to one of the given journal
+// entries (in either direction) is left out, since the journal entry already
+// carries the final result of that position. filter applies to the auto scalp
+// side only (strategy "autoscalp"); entries are assumed to be filtered already.
+// Both are expected to belong to the same user.
+func MergeAutoScalpEntries(entries []*domain.TradeEntry, autoEntries []*domain.AutoScalpEntry, filter domain.TradeHistoryFilter) []*domain.TradeEntry {
+	journalIDs := make(map[string]bool, len(entries))
+	linkedAuto := make(map[string]bool)
+	for _, e := range entries {
+		journalIDs[e.ID] = true
+		if e.AutoScalpEntryID != "" {
+			linkedAuto[e.AutoScalpEntryID] = true
+		}
+	}
+
+	merged := make([]*domain.TradeEntry, 0, len(entries)+len(autoEntries))
+	merged = append(merged, entries...)
+	for _, a := range autoEntries {
+		if linkedAuto[a.ID] || (a.TradeEntryID != "" && journalIDs[a.TradeEntryID]) {
+			continue
+		}
+		if e := autoScalpAsTradeEntry(a); filter.Matches(e) {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// autoScalpAsTradeEntry maps an auto scalp position (short only) onto the journal shape
+func autoScalpAsTradeEntry(a *domain.AutoScalpEntry) *domain.TradeEntry {
+	status := "active"
+	if a.Status == "CLOSED" {
+		status = "closed"
+	}
+	return &domain.TradeEntry{
+		ID:               a.ID,
+		Symbol:           a.Symbol,
+		IsLong:           false,
+		EntryPrice:       a.EntryPrice,
+		StopLoss:         a.StopLoss,
+		EntryTime:        a.EntryTime,
+		Status:           status,
+		ExitPrice:        a.ExitPrice,
+		ExitTime:         a.ExitTime,
+		ProfitLoss:       a.ProfitLoss,
+		EntryReason:      "Auto scalp",
+		Strategy:         "autoscalp",
+		Quantity:         a.Quantity,
+		Leverage:         a.Leverage,
+		Notional:         a.EntryPrice * a.Quantity,
+		AutoScalpEntryID: a.ID,
+	}
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"screener-backend/internal/domain"
+	"time"
+)
+
+// TradeMonitorService watches manual trade journal entries against the live
+// screener prices and moves them through tp1_hit/tp2_hit/tp3_hit/stopped
+// automatically, so users don't have to update the status by hand. With fill
+// simulation on, the levels are checked against the 1m candles traded since
+// the last run instead.
+type TradeMonitorService struct {
+	repo          domain.TradeEntryRepository
+	screeningRepo domain.ScreenerRepository
+	fills         *FillSimulator
+	walkedUntil   map[string]time.Time // entry ID -> end of the last closed candle walked
+}
+
+// NewTradeMonitorService creates a new trade monitor
+func NewTradeMonitorService(
+	repo domain.TradeEntryRepository,
+	screeningRepo domain.ScreenerRepository,
+	fills *FillSimulator,
+) *TradeMonitorService {
+	return &TradeMonitorService{
+		repo:          repo,
+		screeningRepo: screeningRepo,
+		fills:         fills,
+		walkedUntil:   make(map[string]time.Time),
+	}
+}
+
+// statusRank orders the open statuses so an entry only ever moves forward.
+var statusRank = map[string]int{
+	"active":  0,
+	"tp1_hit": 1,
+	"tp2_hit": 2,
+}
+
+// CheckEntries evaluates every active entry against the latest price (called
+// periodically). It returns ctx's error when the run was cut short.
+func (s *TradeMonitorService) CheckEntries(ctx context.Context) error {
+	entries := s.repo.GetAllActiveEntries(ctx)
+	if len(entries) == 0 {
+		return ctx.Err()
+	}
+
+	prices := make(map[string]float64)
+	for _, coin := range s.screeningRepo.GetCoins() {
+		prices[coin.Symbol] = coin.Price
+	}
+
+	s.pruneWalked(entries)
+
+	for _, entry := range entries {
+		status, price, ok := s.candleStatus(ctx, entry)
+		if !ok {
+			price, ok = prices[entry.Symbol]
+			if !ok || price <= 0 {
+				continue
+			}
+			status = nextTradeStatus(entry, price)
+		}
+		if status == entry.Status {
+			continue
+		}
+
+		// Work on a copy: the in-memory repo hands out its own pointers
+		updated := *entry
+		updated.Status = status
+		if updated.IsClosed() {
+			now := time.Now()
+			exitPrice := price
+			updated.ExitPrice = &exitPrice
+			updated.ExitTime = &now
+			updated.SettleProfitLoss(exitPrice, nil)
+		}
+
+		if err := s.repo.UpdateEntry(ctx, &updated); err != nil {
+			log.Printf("Trade monitor: failed to update %s (%s): %v", entry.ID, entry.Symbol, err)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		log.Printf("📒 Trade %s %s: %s -> %s @ %.8f", entry.ID, entry.Symbol, entry.Status, status, price)
+	}
+	return nil
+}
+
+// candleStatus walks the 1m candles since the entry was last checked. A stop
+// and a take profit reached in the same candle count as stopped. The price is
+// the fill of a closing level, else the last close. ok is false when fill
+// simulation is off or no candle after the entry's own minute could be loaded.
+func (s *TradeMonitorService) candleStatus(ctx context.Context, entry *domain.TradeEntry) (string, float64, bool) {
+	if !s.fills.Enabled() {
+		return "", 0, false
+	}
+	since, ok := s.walkedUntil[entry.ID]
+	if !ok {
+		since = entry.EntryTime.Truncate(time.Minute).Add(time.Minute)
+	}
+	candles, err := s.fills.Candles(ctx, entry.Symbol, since)
+	if err != nil || len(candles) == 0 {
+		return "", 0, false
+	}
+
+	// Closing a LONG sells, closing a SHORT buys
+	buy := !entry.IsLong
+	status := entry.Status
+	for _, c := range candles {
+		if price, hit := stopFill(c, entry.StopLoss, buy); hit {
+			return "stopped", s.fills.Slip(ctx, entry.Symbol, price, entry.Notional, buy), true
+		}
+		if price, hit := limitFill(c, entry.TakeProfit3, buy); hit {
+			return "tp3_hit", price, true
+		}
+		if _, hit := limitFill(c, entry.TakeProfit2, buy); hit && statusRank[status] < statusRank["tp2_hit"] {
+			status = "tp2_hit"
+		}
+		if _, hit := limitFill(c, entry.TakeProfit1, buy); hit && statusRank[status] < statusRank["tp1_hit"] {
+			status = "tp1_hit"
+		}
+		if !s.fills.candleClosed(c) {
+			break
+		}
+		s.walkedUntil[entry.ID] = c.OpenTime.Add(time.Minute)
+	}
+	return status, candles[len(candles)-1].Close, true
+}
+
+// pruneWalked forgets the candle progress of entries no longer active
+func (s *TradeMonitorService) pruneWalked(active []*domain.TradeEntry) {
+	ids := make(map[string]bool, len(active))
+	for _, e := range active {
+		ids[e.ID] = true
+	}
+	for id := range s.walkedUntil {
+		if !ids[id] {
+			delete(s.walkedUntil, id)
+		}
+	}
+}
+
+// nextTradeStatus returns the status the entry should have at the given price.
+// Stop loss takes precedence; take profits are checked from the furthest target down.
+func nextTradeStatus(entry *domain.TradeEntry, price float64) string {
+	reached := func(level float64) bool {
+		if level <= 0 {
+			return false
+		}
+		if entry.IsLong {
+			return price >= level
+		}
+		return price <= level
+	}
+
+	if entry.StopLoss > 0 {
+		if (entry.IsLong && price <= entry.StopLoss) || (!entry.IsLong && price >= entry.StopLoss) {
+			return "stopped"
+		}
+	}
+
+	if reached(entry.TakeProfit3) {
+		return "tp3_hit"
+	}
+
+	current := statusRank[entry.Status]
+	if reached(entry.TakeProfit2) && current < statusRank["tp2_hit"] {
+		return "tp2_hit"
+	}
+	if reached(entry.TakeProfit1) && current < statusRank["tp1_hit"] {
+		return "tp1_hit"
+	}
+
+	return entry.Status
+}
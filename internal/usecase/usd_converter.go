@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/binance"
+)
+
+const maxCachedPrices = 10000
+
+// USDConverter values amounts in other assets (BNB fees, coin funding...) in
+// USD at a point in time, using the <ASSET>USDT futures price of that minute.
+// Prices are cached per asset and minute since imports ask for the same
+// minutes over and over.
+type USDConverter struct {
+	client *binance.Client
+	mu     sync.Mutex
+	cache  map[string]float64
+}
+
+// NewUSDConverter creates a converter backed by Binance futures klines
+func NewUSDConverter(binanceBaseURL string) *USDConverter {
+	return &USDConverter{
+		client: binance.NewClient(binanceBaseURL),
+		cache:  make(map[string]float64),
+	}
+}
+
+// ToUSD converts amount of asset to USD at time at. Stablecoins convert 1:1.
+func (c *USDConverter) ToUSD(ctx context.Context, asset string, amount float64, at time.Time) (float64, error) {
+	if amount == 0 || domain.IsUSDStable(asset) {
+		return amount, nil
+	}
+	price, err := c.priceAt(ctx, strings.ToUpper(asset), at)
+	if err != nil {
+		return 0, err
+	}
+	return amount * price, nil
+}
+
+func (c *USDConverter) priceAt(ctx context.Context, asset string, at time.Time) (float64, error) {
+	minute := at.UTC().Truncate(time.Minute)
+	key := fmt.Sprintf("%s|%d", asset, minute.Unix())
+
+	c.mu.Lock()
+	price, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok {
+		return price, nil
+	}
+
+	price, err := c.client.GetPriceAt(ctx, asset+"USDT", minute)
+	if err != nil {
+		return 0, fmt.Errorf("price of %s at %s: %w", asset, minute.Format(time.RFC3339), err)
+	}
+
+	c.mu.Lock()
+	if len(c.cache) >= maxCachedPrices {
+		c.cache = make(map[string]float64)
+	}
+	c.cache[key] = price
+	c.mu.Unlock()
+	return price, nil
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"screener-backend/internal/domain"
+	"screener-backend/internal/infrastructure/logging"
+)
+
+const (
+	// watchlistCacheTTL is how long every user's watchlist is served from
+	// memory, so changes made on another instance apply within a minute
+	watchlistCacheTTL = time.Minute
+	maxWatchlistItems = 50
+)
+
+var (
+	ErrWatchlistSymbol = domain.Validation("WATCHLIST_INVALID_SYMBOL", "symbol must be a USDT pair, e.g. BTCUSDT")
+	ErrWatchlistScore  = domain.Validation("WATCHLIST_INVALID_SCORE", "alertScore and breakoutAlertScore must be between 1 and 100")
+	ErrWatchlistFull   = domain.Validation("WATCHLIST_FULL", fmt.Sprintf("a watchlist holds at most %d symbols", maxWatchlistItems))
+)
+
+// WatchlistService manages the users' watchlists and serves them to the
+// screener (priority scanning and alerts) and the WebSocket handler
+// (watched coins first) from a shared cache
+type WatchlistService struct {
+	repo domain.WatchlistRepository
+
+	mu       sync.Mutex
+	items    []*domain.WatchlistItem // every user's, as of loadedAt
+	loadedAt time.Time
+}
+
+// NewWatchlistService creates a new watchlist service
+func NewWatchlistService(repo domain.WatchlistRepository) *WatchlistService {
+	return &WatchlistService{repo: repo}
+}
+
+// List returns the user's watchlist by symbol
+func (s *WatchlistService) List(ctx context.Context, userID string) ([]*domain.WatchlistItem, error) {
+	return s.repo.ListWatchlist(ctx, userID)
+}
+
+// Save validates and stores an item, replacing the user's item for the same
+// symbol
+func (s *WatchlistService) Save(ctx context.Context, item *domain.WatchlistItem) error {
+	item.Symbol = strings.ToUpper(strings.TrimSpace(item.Symbol))
+	item.Note = strings.TrimSpace(item.Note)
+	if len(item.Symbol) <= len("USDT") || !strings.HasSuffix(item.Symbol, "USDT") {
+		return ErrWatchlistSymbol
+	}
+	for _, score := range []*float64{item.AlertScore, item.BreakoutAlertScore} {
+		if score != nil && (*score < 1 || *score > 100) {
+			return ErrWatchlistScore
+		}
+	}
+
+	existing, err := s.repo.ListWatchlist(ctx, item.UserID)
+	if err != nil {
+		return err
+	}
+	replacing := false
+	for _, e := range existing {
+		if e.Symbol == item.Symbol {
+			replacing = true
+		}
+	}
+	if !replacing && len(existing) >= maxWatchlistItems {
+		return ErrWatchlistFull
+	}
+
+	now := time.Now().UTC()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	if err := s.repo.SaveWatchlistItem(ctx, item); err != nil {
+		return err
+	}
+	s.invalidate()
+	logging.Debugf("Watchlist of %s: saved %s", item.UserID, item.Symbol)
+	return nil
+}
+
+// Remove takes a symbol off the user's watchlist
+func (s *WatchlistService) Remove(ctx context.Context, userID, symbol string) error {
+	if err := s.repo.DeleteWatchlistItem(ctx, userID, strings.ToUpper(symbol)); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// All returns every user's items. A failed load keeps the cached lists.
+func (s *WatchlistService) All(ctx context.Context) []*domain.WatchlistItem {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.loadedAt) > watchlistCacheTTL {
+		items, err := s.repo.ListAllWatchlists(ctx)
+		if err != nil {
+			logging.Warnf("Loading watchlists failed: %v", err)
+		} else {
+			s.items = items
+			s.loadedAt = now
+		}
+	}
+	return s.items
+}
+
+// Symbols is the set of symbols on any watchlist
+func (s *WatchlistService) Symbols(ctx context.Context) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, item := range s.All(ctx) {
+		symbols[item.Symbol] = true
+	}
+	return symbols
+}
+
+// WatchedSymbols is the set of symbols on the user's watchlist
+func (s *WatchlistService) WatchedSymbols(ctx context.Context, userID string) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, item := range s.All(ctx) {
+		if item.UserID == userID {
+			symbols[item.Symbol] = true
+		}
+	}
+	return symbols
+}
+
+func (s *WatchlistService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}